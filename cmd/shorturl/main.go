@@ -1,34 +1,60 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log/slog"
+	"net"
 	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+	googlegrpc "google.golang.org/grpc"
+
 	"github.com/AvalosM/short-url-service/internal/cache"
 	"github.com/AvalosM/short-url-service/internal/config"
+	"github.com/AvalosM/short-url-service/internal/grpc"
 	"github.com/AvalosM/short-url-service/internal/handlers"
+	"github.com/AvalosM/short-url-service/internal/middleware"
 	"github.com/AvalosM/short-url-service/internal/router"
 	"github.com/AvalosM/short-url-service/internal/storage"
+	"github.com/AvalosM/short-url-service/pkg/geo"
 	"github.com/AvalosM/short-url-service/pkg/logging"
 	"github.com/AvalosM/short-url-service/pkg/metrics"
+	"github.com/AvalosM/short-url-service/pkg/metrics/influxdb"
 	"github.com/AvalosM/short-url-service/pkg/shorturl"
+	"github.com/AvalosM/short-url-service/pkg/token"
+	"github.com/AvalosM/short-url-service/pkg/webhook"
 )
 
 func main() {
-	// TODO: Read configuration from environment variables or a config file
-	cfg := config.DefaultConfig()
-	err := cfg.Validate()
+	configFile := os.Getenv(config.FileEnv)
+	loadConfig := func() (*config.Config, error) {
+		return config.LoadFile(configFile)
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		os.Exit(-1)
+	}
+	err = cfg.Validate()
 	if err != nil {
 		os.Exit(-1)
 	}
 
-	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
-		Level: slog.Level(cfg.Logger.Level),
-	}))
+	// The levels are variables so reloading the config changes them without restarting, records are filtered by the
+	// level of the package logging them so the JSON handler lets every level through
+	logLevel := &slog.LevelVar{}
+	logLevel.Set(slog.Level(cfg.Logger.Level))
+	packageLevels := logging.NewPackageLevels(cfg.Logger.SlogPackageLevels())
+	logger := slog.New(logging.NewPackageLevelHandler(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+	}), logLevel, packageLevels))
 
 	shutdownOnError := func(err error) {
 		if err != nil {
@@ -37,24 +63,106 @@ func main() {
 		}
 	}
 
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	storage, err := storage.NewStorage(cfg.Storage)
 	shutdownOnError(err)
+	// Deferred first so the connections are closed last, once everything using them has stopped
+	defer func() {
+		if err := storage.Close(); err != nil {
+			logger.Error("error closing storage", logging.ErrorKey, err)
+		}
+	}()
+
+	err = storage.Migrate()
+	shutdownOnError(err)
 
 	cache := cache.NewCache(cfg.Cache)
+	defer func() {
+		if err := cache.Close(); err != nil {
+			logger.Error("error closing cache", logging.ErrorKey, err)
+		}
+	}()
+
+	botDetector, err := metrics.NewBotDetector(cfg.MetricsManager.BotUAFile)
+	shutdownOnError(err)
+
+	var metricsStorage metrics.Storage = storage
+	if cfg.MetricsManager.Driver == metrics.DriverInfluxDB {
+		influxDBStorage, closeInfluxDB, err := influxdb.NewClientStorage(cfg.MetricsManager.InfluxDB)
+		shutdownOnError(err)
+		defer closeInfluxDB()
 
-	metricsManager, err := metrics.NewManager(cfg.MetricsManager, storage, logger)
+		metricsStorage = influxDBStorage
+	}
+
+	// The lookup is kept as an interface, a nil *geo.Lookup would not be a nil metrics.GeoLookup
+	var geoLookup metrics.GeoLookup
+	if cfg.MetricsManager.GeoDBPath != "" {
+		lookup, err := geo.Open(cfg.MetricsManager.GeoDBPath)
+		shutdownOnError(err)
+		defer lookup.Close()
+
+		geoLookup = lookup
+	}
+
+	metricsManager, err := metrics.NewManager(cfg.MetricsManager, metricsStorage, botDetector, geoLookup,
+		logging.NewPackageLogger(logger, "metrics"))
 	shutdownOnError(err)
 
 	stopMetricsManager := metricsManager.Start()
 	defer stopMetricsManager()
 
-	shortURLManager, err := shorturl.NewManager(cfg.ShortURLManager, storage, cache, logger)
+	go func() {
+		for {
+			select {
+			case err := <-metricsManager.Errors():
+				logger.Warn("dropped short URL request metrics", logging.ErrorKey, err)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	stopMetricsRollup := metricsManager.StartRollup(ctx, time.Duration(cfg.MetricsManager.RollupIntervalInHours)*time.Hour)
+	defer stopMetricsRollup()
+
+	shortURLManager, err := shorturl.NewManager(cfg.ShortURLManager, storage, cache.WithNamespace("urls"),
+		logging.NewPackageLogger(logger, "shorturl"))
+	shutdownOnError(err)
+
+	stopExpiryCleanup := shortURLManager.StartExpiryCleanup(ctx, time.Duration(cfg.ShortURLManager.ExpiryCleanupIntervalInSeconds)*time.Second)
+	defer stopExpiryCleanup()
+
+	stopClickLimiterEviction := shortURLManager.StartClickLimiterEviction(ctx, time.Minute)
+	defer stopClickLimiterEviction()
+
+	webhookManager, err := webhook.NewManager(cfg.Webhook, storage, http.DefaultClient, logging.NewPackageLogger(logger, "webhook"))
+	shutdownOnError(err)
+
+	stopWebhookManager := webhookManager.Start()
+	defer stopWebhookManager()
+
+	tokenSigner, err := token.NewSigner(cfg.Token)
+	shutdownOnError(err)
+
+	shortURLHandler, err := handlers.NewShortURLHandler(cfg.Handler, shortURLManager, metricsManager, webhookManager, tokenSigner,
+		logging.NewPackageLogger(logger, "handlers"))
+	shutdownOnError(err)
+
+	cfg.Router.HSTSEnabled = cfg.HTTPServer.TLSEnabled()
+	if cfg.Router.PProfEnabled && slog.Level(cfg.Logger.Level) > slog.LevelDebug {
+		logger.Warn("pprof is enabled outside of development, profiling endpoints are exposed on the private router")
+	}
+	configHolder, err := config.NewHolder(cfg, loadConfig, logLevel, packageLevels, logging.NewPackageLogger(logger, "config"))
 	shutdownOnError(err)
 
-	shortURLHandler, err := handlers.NewShortURLHandler(shortURLManager, metricsManager, logger)
+	adminHandler, err := handlers.NewAdminHandler(configHolder, shortURLManager, shortURLManager, logging.NewPackageLogger(logger, "handlers"))
 	shutdownOnError(err)
 
-	router := router.NewRouter(cfg.Router, shortURLHandler)
+	router := router.NewRouter(cfg.Router, shortURLHandler, adminHandler, cache.WithNamespace("idempotency"),
+		logging.NewPackageLogger(logger, "router"))
 
 	server := &http.Server{
 		Addr:         fmt.Sprintf(":%v", cfg.HTTPServer.Port),
@@ -64,11 +172,81 @@ func main() {
 		IdleTimeout:  60 * time.Second,
 	}
 
-	logger.Info("Starting server on port", "port", cfg.HTTPServer.Port)
-	err = server.ListenAndServe()
+	// The gRPC server is off by default, its calls are authenticated with the private router JWTs when auth is enabled
+	if cfg.GRPC.Enabled {
+		shortURLService, err := grpc.NewServer(shortURLManager, metricsManager, logging.NewPackageLogger(logger, "grpc"))
+		shutdownOnError(err)
+
+		grpcListener, err := net.Listen("tcp", fmt.Sprintf(":%v", cfg.GRPC.Port))
+		shutdownOnError(err)
+
+		var grpcOptions []googlegrpc.ServerOption
+		if cfg.Router.AuthEnabled {
+			grpcOptions = append(grpcOptions, googlegrpc.UnaryInterceptor(grpc.AuthInterceptor(
+				middleware.NewTokenAuthenticator([]byte(cfg.Router.AuthSecret)))))
+		} else {
+			logger.Warn("auth is disabled, gRPC calls are not authenticated")
+		}
+
+		grpcServer := googlegrpc.NewServer(grpcOptions...)
+		shortURLService.Register(grpcServer)
+		go func() {
+			logger.Info("Starting gRPC server on port", logging.PortKey, cfg.GRPC.Port)
+			if err := grpcServer.Serve(grpcListener); err != nil {
+				shutdownOnError(err)
+			}
+		}()
+		defer grpcServer.GracefulStop()
+	}
+
+	// Shutdown makes ListenAndServe return right away, in-flight requests are drained before exiting
+	shutdownDone := make(chan struct{})
+	go func() {
+		defer close(shutdownDone)
+
+		<-ctx.Done()
+		logger.Info("Shutting down server")
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			logger.Error("error shutting down server", logging.ErrorKey, err)
+		}
+	}()
+
+	err = configureHTTP2(server, cfg.HTTPServer)
+	shutdownOnError(err)
+
+	logger.Info("Starting server on port", logging.PortKey, cfg.HTTPServer.Port, logging.TLSKey, cfg.HTTPServer.TLSEnabled(),
+		logging.HTTP2Key, cfg.HTTPServer.HTTP2Enabled)
+	err = listenAndServe(server, cfg.HTTPServer)
 	if err != nil && !errors.Is(err, http.ErrServerClosed) {
 		shutdownOnError(err)
 	}
+	<-shutdownDone
+}
+
+// configureHTTP2 makes the server serve HTTP/2 when enabled in the server config, negotiated through TLS when it is
+// enabled and as cleartext HTTP/2 (h2c) otherwise
+func configureHTTP2(server *http.Server, config *config.HTTPServerConfig) error {
+	if !config.HTTP2Enabled {
+		return nil
+	}
+
+	if config.TLSEnabled() {
+		return http2.ConfigureServer(server, nil)
+	}
+
+	server.Handler = h2c.NewHandler(server.Handler, &http2.Server{IdleTimeout: server.IdleTimeout})
+
+	return nil
+}
+
+// listenAndServe serves HTTPS when the server config has TLS cert files and plain HTTP otherwise
+func listenAndServe(server *http.Server, config *config.HTTPServerConfig) error {
+	if config.TLSEnabled() {
+		return server.ListenAndServeTLS(config.TLSCertFile, config.TLSKeyFile)
+	}
 
-	os.Exit(0)
+	return server.ListenAndServe()
 }
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/net/http2"
+
+	"github.com/AvalosM/short-url-service/internal/config"
+)
+
+type MainSuite struct {
+	suite.Suite
+}
+
+func TestMainSuite(t *testing.T) {
+	suite.Run(t, new(MainSuite))
+}
+
+// writeSelfSignedCert writes a self-signed certificate for 127.0.0.1 and its key to a temp dir
+func (suite *MainSuite) writeSelfSignedCert() (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	suite.Require().NoError(err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "127.0.0.1"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	cert, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	suite.Require().NoError(err)
+	keyBytes, err := x509.MarshalECPrivateKey(key)
+	suite.Require().NoError(err)
+
+	dir := suite.T().TempDir()
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	suite.Require().NoError(os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert}), 0600))
+	suite.Require().NoError(os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyBytes}), 0600))
+
+	return certFile, keyFile
+}
+
+func (suite *MainSuite) freePort() int {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	suite.Require().NoError(err)
+	defer listener.Close()
+
+	return listener.Addr().(*net.TCPAddr).Port
+}
+
+func (suite *MainSuite) TestListenAndServeTLS() {
+	certFile, keyFile := suite.writeSelfSignedCert()
+	serverConfig := config.DefaultHTTPServerConfig()
+	serverConfig.Port = suite.freePort()
+	serverConfig.TLSCertFile = certFile
+	serverConfig.TLSKeyFile = keyFile
+	suite.Require().NoError(serverConfig.Validate())
+	suite.True(serverConfig.TLSEnabled())
+
+	server := &http.Server{
+		Addr: fmt.Sprintf("127.0.0.1:%d", serverConfig.Port),
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}),
+	}
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- listenAndServe(server, serverConfig)
+	}()
+	defer func() {
+		suite.NoError(server.Close())
+		suite.True(errors.Is(<-serveErr, http.ErrServerClosed))
+	}()
+
+	client := &http.Client{
+		Timeout: time.Second,
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		},
+	}
+	url := fmt.Sprintf("https://%s/", server.Addr)
+	suite.Eventually(func() bool {
+		response, err := client.Get(url)
+		if err != nil {
+			return false
+		}
+		defer response.Body.Close()
+
+		return response.StatusCode == http.StatusOK && response.TLS != nil
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func (suite *MainSuite) TestHTTPServerConfigTLSFilesMustBeSetTogether() {
+	serverConfig := config.DefaultHTTPServerConfig()
+	suite.NoError(serverConfig.Validate())
+	suite.False(serverConfig.TLSEnabled())
+
+	serverConfig.TLSCertFile = "cert.pem"
+	suite.Error(serverConfig.Validate())
+
+	serverConfig.TLSCertFile = ""
+	serverConfig.TLSKeyFile = "key.pem"
+	suite.Error(serverConfig.Validate())
+}
+
+// serveRedirects serves a redirect to the long URL with the status code in the path, /301 or /302, until the test ends
+func (suite *MainSuite) serveRedirects(serverConfig *config.HTTPServerConfig) string {
+	server := &http.Server{
+		Addr: fmt.Sprintf("127.0.0.1:%d", serverConfig.Port),
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			code := http.StatusFound
+			if r.URL.Path == "/301" {
+				code = http.StatusMovedPermanently
+			}
+
+			http.Redirect(w, r, "https://example.com", code)
+		}),
+	}
+	suite.Require().NoError(configureHTTP2(server, serverConfig))
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- listenAndServe(server, serverConfig)
+	}()
+	suite.T().Cleanup(func() {
+		suite.NoError(server.Close())
+		suite.True(errors.Is(<-serveErr, http.ErrServerClosed))
+	})
+
+	return server.Addr
+}
+
+// assertHTTP2Redirects checks the redirects of the server at baseURL are served over HTTP/2 with their status code
+func (suite *MainSuite) assertHTTP2Redirects(transport http.RoundTripper, baseURL string) {
+	client := &http.Client{
+		Timeout:   time.Second,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	for path, expectedStatus := range map[string]int{"/301": http.StatusMovedPermanently, "/302": http.StatusFound} {
+		var response *http.Response
+		suite.Eventually(func() bool {
+			var err error
+			response, err = client.Get(baseURL + path)
+
+			return err == nil
+		}, 5*time.Second, 10*time.Millisecond)
+		response.Body.Close()
+
+		suite.Equal(2, response.ProtoMajor)
+		suite.Equal(expectedStatus, response.StatusCode)
+		suite.Equal("https://example.com", response.Header.Get("Location"))
+	}
+}
+
+func (suite *MainSuite) TestHTTP2Cleartext() {
+	serverConfig := config.DefaultHTTPServerConfig()
+	serverConfig.Port = suite.freePort()
+	serverConfig.HTTP2Enabled = true
+	addr := suite.serveRedirects(serverConfig)
+
+	// Prior knowledge h2c, the client speaks HTTP/2 over the plain TCP connection
+	transport := &http2.Transport{
+		AllowHTTP: true,
+		DialTLSContext: func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, network, addr)
+		},
+	}
+	suite.assertHTTP2Redirects(transport, "http://"+addr)
+}
+
+func (suite *MainSuite) TestHTTP2TLS() {
+	certFile, keyFile := suite.writeSelfSignedCert()
+	serverConfig := config.DefaultHTTPServerConfig()
+	serverConfig.Port = suite.freePort()
+	serverConfig.TLSCertFile = certFile
+	serverConfig.TLSKeyFile = keyFile
+	serverConfig.HTTP2Enabled = true
+	addr := suite.serveRedirects(serverConfig)
+
+	transport := &http2.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}
+	suite.assertHTTP2Redirects(transport, "https://"+addr)
+}
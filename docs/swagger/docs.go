@@ -15,40 +15,49 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/private/v1/short-urls/create": {
+        "/private/v1/admin/cache/warm": {
             "post": {
-                "description": "Create a short URL for the given long URL",
+                "description": "Cache the redirects of the given short URLs so their first clicks are served from the cache. Short\nURLs not found or never cached, like inactive or password protected ones, are skipped.",
                 "consumes": [
                     "application/json"
                 ],
-                "produces": [
-                    "application/json"
-                ],
                 "tags": [
-                    "short-url",
+                    "admin",
                     "private"
                 ],
-                "summary": "Create a short URL",
+                "summary": "Warm the cache",
                 "parameters": [
                     {
-                        "description": "Long URL to be shortened",
-                        "name": "ShortURLRequest",
+                        "description": "Short URLs to cache",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handlers.WarmCacheRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Short URL id",
+                    "204": {
+                        "description": "Cache warmed",
                         "schema": {
                             "type": "string"
                         }
                     },
                     "400": {
-                        "description": "Invalid long URL",
+                        "description": "Invalid request body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin role required",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "413": {
+                        "description": "Request body too large",
                         "schema": {
                             "type": "string"
                         }
@@ -62,38 +71,56 @@ const docTemplate = `{
                 }
             }
         },
-        "/private/v1/short-urls/{shortURLId}": {
-            "delete": {
-                "description": "Delete a short URL by its id",
-                "consumes": [
-                    "application/json"
+        "/private/v1/admin/cache/warm/all": {
+            "post": {
+                "description": "Cache the redirects of the short URLs with the most visits within the last week, as many as\nconfigured",
+                "tags": [
+                    "admin",
+                    "private"
                 ],
+                "summary": "Warm the cache with the most visited short URLs",
+                "responses": {
+                    "204": {
+                        "description": "Cache warmed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin role required",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/private/v1/admin/config/reload": {
+            "post": {
+                "description": "Reload the configuration and apply the sections that support hot reload, the log level. Changes to\nother sections are ignored until restart.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "short-url",
+                    "admin",
                     "private"
                 ],
-                "summary": "Delete a short URL",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Short URL id to be deleted",
-                        "name": "shortURLId",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
+                "summary": "Reload the configuration",
                 "responses": {
                     "200": {
-                        "description": "Short URL deleted successfully",
+                        "description": "Sections reloaded and ignored",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handlers.ConfigReloadResponse"
                         }
                     },
-                    "400": {
-                        "description": "Invalid short URL id",
+                    "403": {
+                        "description": "Admin role required",
                         "schema": {
                             "type": "string"
                         }
@@ -107,38 +134,106 @@ const docTemplate = `{
                 }
             }
         },
-        "/private/v1/short-urls/{shortURLId}/metrics": {
+        "/private/v1/admin/shorturl/stats": {
             "get": {
-                "description": "Get metrics for a short URL within a specified time range",
-                "consumes": [
+                "description": "Get the counters of the short URL manager since it started or its stats were last reset, and the\nrate of id collisions per short URL created",
+                "produces": [
                     "application/json"
                 ],
+                "tags": [
+                    "admin",
+                    "private"
+                ],
+                "summary": "Get the short URL manager stats",
+                "responses": {
+                    "200": {
+                        "description": "Short URL manager stats",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ManagerStatsResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin role required",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/private/v1/admin/stats": {
+            "get": {
+                "description": "Get the counters of the short URL manager since it started or its stats were last reset, and the\nrate of id collisions per short URL created",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "short-url",
+                    "admin",
                     "private"
                 ],
-                "summary": "Get short URL metrics",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Short URL id to get metrics for",
-                        "name": "shortURLId",
-                        "in": "path",
-                        "required": true
+                "summary": "Get the short URL manager stats",
+                "responses": {
+                    "200": {
+                        "description": "Short URL manager stats",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ManagerStatsResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin role required",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/private/v1/admin/stats/reset": {
+            "post": {
+                "description": "Set every counter of the short URL manager back to zero",
+                "tags": [
+                    "admin",
+                    "private"
+                ],
+                "summary": "Reset the short URL manager stats",
+                "responses": {
+                    "204": {
+                        "description": "Stats reset",
+                        "schema": {
+                            "type": "string"
+                        }
                     },
+                    "403": {
+                        "description": "Admin role required",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/private/v1/analytics/dashboard": {
+            "get": {
+                "description": "Get the short URLs created, the redirects and unique visitors, and the top 5 short URLs by visits and\nby unique visitors within a specified time range. Stats are cached for up to a minute.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "analytics",
+                    "private"
+                ],
+                "summary": "Get the analytics dashboard",
+                "parameters": [
                     {
                         "type": "string",
-                        "description": "Start time for metrics (RFC3339 format)",
+                        "description": "Start time of the stats (RFC3339 format)",
                         "name": "from",
                         "in": "query",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "End time for metrics (RFC3339 format)",
+                        "description": "End time of the stats (RFC3339 format)",
                         "name": "to",
                         "in": "query",
                         "required": true
@@ -146,9 +241,9 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Short URL metrics",
+                        "description": "Dashboard stats",
                         "schema": {
-                            "$ref": "#/definitions/metrics.Metrics"
+                            "$ref": "#/definitions/handlers.DashboardResponse"
                         }
                     },
                     "400": {
@@ -157,12 +252,6 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
-                    "404": {
-                        "description": "Metrics not found",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -172,9 +261,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/public/v1/short-urls/{shortURLId}": {
-            "get": {
-                "description": "Redirect to the long URL for the given short URL id",
+        "/private/v1/metrics/bulk": {
+            "post": {
+                "description": "Get the metrics of many short URLs within a specified time range at once. Short URLs without visits,\nor that do not exist, get zero metrics.",
                 "consumes": [
                     "application/json"
                 ],
@@ -182,34 +271,36 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "short-url",
-                    "public"
+                    "metrics",
+                    "private"
                 ],
-                "summary": "Redirect to long URL",
+                "summary": "Get the metrics of many short URLs",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Long URL to be shortened",
-                        "name": "longURL",
-                        "in": "query",
-                        "required": true
+                        "description": "Short URL ids, at most 100, and time range to get metrics for",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.MetricsBulkRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "302": {
-                        "description": "Short URL ID",
+                    "200": {
+                        "description": "Metrics by short URL id",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handlers.MetricsBulkResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid long URL",
+                        "description": "Invalid request parameters",
                         "schema": {
                             "type": "string"
                         }
                     },
-                    "404": {
-                        "description": "Short URL not found",
+                    "413": {
+                        "description": "Request body too large",
                         "schema": {
                             "type": "string"
                         }
@@ -222,30 +313,2358 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "metrics.Metrics": {
-            "type": "object",
-            "properties": {
-                "from": {
-                    "type": "string"
-                },
-                "shortURLId": {
-                    "type": "string"
-                },
-                "to": {
-                    "type": "string"
-                },
-                "uniqueVisits": {
-                    "type": "integer",
-                    "format": "int64"
-                },
-                "visits": {
-                    "type": "integer",
-                    "format": "int64"
+        },
+        "/private/v1/metrics/drain": {
+            "post": {
+                "description": "Flush the metrics collected in memory to storage without waiting for the next flush interval",
+                "tags": [
+                    "metrics",
+                    "private"
+                ],
+                "summary": "Flush the unflushed metrics",
+                "responses": {
+                    "200": {
+                        "description": "Metrics flushed"
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "Metrics manager is stopped",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/private/v1/metrics/snapshot": {
+            "get": {
+                "description": "Get the metrics collected in memory since the last flush to storage, keyed by short URL id",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "metrics",
+                    "private"
+                ],
+                "summary": "Get the unflushed metrics",
+                "responses": {
+                    "200": {
+                        "description": "Metrics collected since the last flush",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.MetricsSnapshotResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "Metrics manager is stopped",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
                 }
             }
+        },
+        "/private/v1/short-urls": {
+            "get": {
+                "description": "List short URLs, newest first, optionally filtered by tag",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "short-url",
+                    "private"
+                ],
+                "summary": "List short URLs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only list short URLs with this tag",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of short URLs to return (default 100, max 1000)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of short URLs to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Short URLs",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ShortURLListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request parameters",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Get the short URL of the given long URL, creating it if the long URL was not shortened yet. Repeating\nthe request returns the same short URL.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "short-url",
+                    "private"
+                ],
+                "summary": "Get or create a short URL",
+                "parameters": [
+                    {
+                        "description": "Long URL to be shortened",
+                        "name": "GetOrCreateShortURLRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.GetOrCreateShortURLRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Actor recorded in the audit log",
+                        "name": "X-Actor",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Base URL of the returned short URL, one of the allowed base URLs",
+                        "name": "X-Base-URL",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Existing short URL",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.GetOrCreateShortURLResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Created short URL",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.GetOrCreateShortURLResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid long URL",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Long URL of a denied host",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "413": {
+                        "description": "Request body too large",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/private/v1/short-urls/create": {
+            "post": {
+                "description": "Create a short URL for the given long URL",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "short-url",
+                    "private"
+                ],
+                "summary": "Create a short URL",
+                "parameters": [
+                    {
+                        "description": "Long URL to be shortened, its tags, description, click limit, password, redirect code and an optional webhook",
+                        "name": "ShortURLRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ShortURLRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Key used to replay the response of a retried request",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Actor recorded in the audit log",
+                        "name": "X-Actor",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Base URL of the returned short URL, one of the allowed base URLs",
+                        "name": "X-Base-URL",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created short URL, without its webhook if registering it failed",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ShortURLResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid long URL, tags, description, click limit, password, redirect code or webhook, an invalid long URL is an ErrorResponse with code INVALID_URL, or URL_TOO_LONG if it is too long",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Long URL of a denied host, with code URL_DENIED",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "A request with the same Idempotency-Key is in progress",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "413": {
+                        "description": "Request body too large",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "Idempotency-Key already used with another request body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/private/v1/short-urls/export": {
+            "get": {
+                "description": "Export all short URLs, newest first, optionally filtered by tags. Rows are streamed as they are read\nfrom storage, as newline delimited JSON or as a CSV.",
+                "produces": [
+                    "application/x-ndjson",
+                    "text/csv"
+                ],
+                "tags": [
+                    "short-url",
+                    "private"
+                ],
+                "summary": "Export short URLs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Export format, json or csv (default json)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated tags, only short URLs with all of them are exported",
+                        "name": "tags",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "One short URL per line, or a CSV with id, short_url, long_url, tags, description, created_at and updated_at columns",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ShortURLListItem"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request parameters",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/private/v1/short-urls/import": {
+            "post": {
+                "description": "Create a short URL for each row of a CSV file. Its header must have a long_url column, and can have\ncustom_slug, tags and expires_at columns. custom_slug is created as an alias of the short URL, tags are\ncomma separated and expires_at is in RFC3339 format. Rows failing do not stop the others from being\nimported, the report tells the outcome of each row.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "short-url",
+                    "private"
+                ],
+                "summary": "Import short URLs from a CSV",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "CSV file with the long URLs to be shortened",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Actor recorded in the audit log",
+                        "name": "X-Actor",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Base URL of the returned short URLs, one of the allowed base URLs",
+                        "name": "X-Base-URL",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Import report",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ImportShortURLsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Missing file or invalid CSV",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "413": {
+                        "description": "File too large",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/private/v1/short-urls/resolve": {
+            "get": {
+                "description": "Get the long URLs of many short URLs at once, without counting clicks. Inactive, expired and password\nprotected short URLs are not found, aliases are not resolved.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "short-url",
+                    "private"
+                ],
+                "summary": "Resolve many short URLs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Comma separated short URL ids, at most 100",
+                        "name": "ids",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Long URLs by short URL id and the ids not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResolveShortURLsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request parameters",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/private/v1/short-urls/{shortURLId}": {
+            "get": {
+                "description": "Get every attribute of the short URL with the given id, its password hash excepted, without being\nredirected. Paused short URLs are returned, expired, archived and exhausted ones are gone.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "short-url",
+                    "private"
+                ],
+                "summary": "Get a short URL",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Short URL id to get",
+                        "name": "shortURLId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Short URL",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ShortURLDetailsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid short URL id",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Short URL not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "410": {
+                        "description": "Short URL expired, archived or click limit exceeded",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a short URL by its id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "short-url",
+                    "private"
+                ],
+                "summary": "Delete a short URL",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Short URL id to be deleted",
+                        "name": "shortURLId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Actor recorded in the audit log",
+                        "name": "X-Actor",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Short URL deleted successfully",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid short URL id",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/private/v1/short-urls/{shortURLId}/aliases": {
+            "post": {
+                "description": "Create an alias id that redirects like the short URL, so links to a retired short URL keep working",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "short-url",
+                    "private"
+                ],
+                "summary": "Create an alias",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Short URL id the alias points to",
+                        "name": "shortURLId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Alias id",
+                        "name": "AliasRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.AliasRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created alias",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.AliasResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid alias id",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Short URL not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Alias id already in use",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "413": {
+                        "description": "Request body too large",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/private/v1/short-urls/{shortURLId}/aliases/{aliasId}": {
+            "delete": {
+                "description": "Delete an alias of a short URL, it stops redirecting",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "short-url",
+                    "private"
+                ],
+                "summary": "Delete an alias",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Short URL id the alias points to",
+                        "name": "shortURLId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Alias id to be deleted",
+                        "name": "aliasId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Alias deleted successfully",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid short URL or alias id",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Alias not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/private/v1/short-urls/{shortURLId}/archive": {
+            "post": {
+                "description": "Permanently disable the redirect of an active or paused short URL, archived short URLs cannot be resumed",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "short-url",
+                    "private"
+                ],
+                "summary": "Archive a short URL",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Short URL id to be archived",
+                        "name": "shortURLId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Actor recorded in the audit log",
+                        "name": "X-Actor",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Short URL archived successfully",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid short URL id",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Short URL not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Short URL is already archived",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/private/v1/short-urls/{shortURLId}/audit": {
+            "get": {
+                "description": "Get the operations made on a short URL, oldest first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "short-url",
+                    "private"
+                ],
+                "summary": "Get the audit log of a short URL",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Short URL id to get the audit log for",
+                        "name": "shortURLId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of entries to return (default 100, max 1000)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of entries to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Audit log entries",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.AuditLogResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request parameters",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/private/v1/short-urls/{shortURLId}/latency": {
+            "get": {
+                "description": "Get the p50, p95 and p99 latency of the redirects of a short URL within a specified time range, one\ninterval per metrics flush oldest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "short-url",
+                    "private"
+                ],
+                "summary": "Get short URL redirect latency",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Short URL id to get the latency for",
+                        "name": "shortURLId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start time for latency (RFC3339 format)",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "End time for latency (RFC3339 format)",
+                        "name": "to",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Short URL redirect latency",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ShortURLLatencyResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request parameters",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Short URL not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/private/v1/short-urls/{shortURLId}/metrics": {
+            "get": {
+                "description": "Get metrics for a short URL within a specified time range",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "short-url",
+                    "private"
+                ],
+                "summary": "Get short URL metrics",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Short URL id to get metrics for",
+                        "name": "shortURLId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start time for metrics (RFC3339 format)",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "End time for metrics (RFC3339 format)",
+                        "name": "to",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Short URL metrics",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ShortURLMetricsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request parameters",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Metrics not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "413": {
+                        "description": "Request body too large",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/private/v1/short-urls/{shortURLId}/metrics/export": {
+            "get": {
+                "description": "Export the metrics of a short URL within a specified time range as a CSV, one row per interval\noldest first. Rolled up periods are exported as one row at the start of the period.",
+                "produces": [
+                    "text/csv"
+                ],
+                "tags": [
+                    "short-url",
+                    "private"
+                ],
+                "summary": "Export short URL metrics",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Short URL id to export metrics for",
+                        "name": "shortURLId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start time for metrics (RFC3339 format)",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "End time for metrics (RFC3339 format)",
+                        "name": "to",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Export format, only csv is supported (default csv)",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "CSV with timestamp, visits and unique_visits columns",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request parameters",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Short URL not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/private/v1/short-urls/{shortURLId}/pause": {
+            "post": {
+                "description": "Temporarily disable the redirect of an active short URL until it is resumed",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "short-url",
+                    "private"
+                ],
+                "summary": "Pause a short URL",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Short URL id to be paused",
+                        "name": "shortURLId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Actor recorded in the audit log",
+                        "name": "X-Actor",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Short URL paused successfully",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid short URL id",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Short URL not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Short URL is not active",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/private/v1/short-urls/{shortURLId}/resume": {
+            "post": {
+                "description": "Enable the redirect of a paused short URL again",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "short-url",
+                    "private"
+                ],
+                "summary": "Resume a short URL",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Short URL id to be resumed",
+                        "name": "shortURLId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Actor recorded in the audit log",
+                        "name": "X-Actor",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Short URL resumed successfully",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid short URL id",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Short URL not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Short URL is not paused",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/private/v1/short-urls/{shortURLId}/stream": {
+            "get": {
+                "description": "Stream the clicks of a short URL as Server-Sent Events while they are collected. Each click is a\nclick event with the short URL id, the visitor IP and the click time as data. A heartbeat event is\nsent every heartbeat interval, 30 seconds by default. Repeated visits within the visit window are not\nstreamed.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "short-url",
+                    "private"
+                ],
+                "summary": "Stream short URL clicks",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Short URL id to stream clicks of",
+                        "name": "shortURLId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ClickEvent"
+                        }
+                    },
+                    "400": {
+                        "description": "Short URL id is required",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Short URL not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/private/v1/short-urls/{shortURLId}/webhooks": {
+            "post": {
+                "description": "Register a webhook notified with a signed POST every time the short URL is followed",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "short-url",
+                    "private"
+                ],
+                "summary": "Register a webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Short URL id to register the webhook for",
+                        "name": "shortURLId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Webhook URL and the secret used to sign its payloads",
+                        "name": "WebhookConfig",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.WebhookConfig"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Registered webhook",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.WebhookResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid webhook",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Short URL not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "413": {
+                        "description": "Request body too large",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/private/v1/short-urls/{shortURLId}/webhooks/{webhookId}": {
+            "delete": {
+                "description": "Delete a webhook registered for a short URL",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "short-url",
+                    "private"
+                ],
+                "summary": "Delete a webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Short URL id the webhook is registered for",
+                        "name": "shortURLId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Webhook id to be deleted",
+                        "name": "webhookId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Webhook deleted successfully",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid short URL or webhook id",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Webhook not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/private/v1/users/{userId}/short-urls": {
+            "get": {
+                "description": "List the short URLs created by a user, newest first. Only the user and admins can list them.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "short-url",
+                    "private"
+                ],
+                "summary": "List the short URLs of a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Id of the user who created the short URLs",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of short URLs to return (default 100, max 1000)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of short URLs to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Short URLs",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ShortURLListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request parameters",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Not allowed to list the short URLs of the user",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete every short URL created by a user, for deletions of user accounts. Only the user and admins\ncan delete them.",
+                "tags": [
+                    "short-url",
+                    "private"
+                ],
+                "summary": "Delete the short URLs of a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Id of the user who created the short URLs",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Actor recorded in the audit log",
+                        "name": "X-Actor",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Short URLs deleted",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user id",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Not allowed to delete the short URLs of the user",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/public/v1/short-urls/{shortURLId}": {
+            "get": {
+                "description": "Redirect to the long URL for the given short URL id. Password protected short URLs answer with a\nchallenge instead, unless a token obtained from the unlock endpoint is given. Query parameters are\nappended to the long URL when the short URL forwards them. Expiring short URLs tell the time they\nhave left in the X-Short-URL-TTL and X-Short-URL-Expires-At headers. Aliases redirect like the short\nURL they point to. Short URLs showing an interstitial answer with an HTML page displaying the long URL\nthat redirects after a countdown.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/html"
+                ],
+                "tags": [
+                    "short-url",
+                    "public"
+                ],
+                "summary": "Redirect to long URL",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Short URL id to be followed",
+                        "name": "shortURLId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Token unlocking a password protected short URL",
+                        "name": "token",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Short URL is password protected",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ProtectedShortURLResponse"
+                        }
+                    },
+                    "301": {
+                        "description": "Redirect to the long URL, when configured for the short URL",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "302": {
+                        "description": "Redirect to the long URL",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "307": {
+                        "description": "Redirect to the long URL, when configured for the short URL",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid long URL",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Invalid or expired token",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Short URL not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "410": {
+                        "description": "Short URL expired, click limit exceeded or short URL archived",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "429": {
+                        "description": "Short URL click rate limit exceeded",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "Short URL paused",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "head": {
+                "description": "Check the short URL or alias with the given id would redirect, without following it. No visit or\nclick is counted.",
+                "tags": [
+                    "short-url",
+                    "public"
+                ],
+                "summary": "Check a short URL exists",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Short URL id to be checked",
+                        "name": "shortURLId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Short URL exists and is active"
+                    },
+                    "400": {
+                        "description": "Short URL id is required"
+                    },
+                    "404": {
+                        "description": "Short URL not found"
+                    },
+                    "410": {
+                        "description": "Short URL expired, archived or its click limit exceeded"
+                    },
+                    "500": {
+                        "description": "Internal server error"
+                    },
+                    "503": {
+                        "description": "Short URL paused"
+                    }
+                }
+            }
+        },
+        "/public/v1/short-urls/{shortURLId}/preview": {
+            "get": {
+                "description": "Get the long URL for the given short URL id without being redirected. Expiring short URLs tell the\ntime they have left in the X-Short-URL-TTL and X-Short-URL-Expires-At headers.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "short-url",
+                    "public"
+                ],
+                "summary": "Preview a short URL",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Short URL id to preview",
+                        "name": "shortURLId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of a previously retrieved preview",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Short URL preview",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ShortURLPreviewResponse"
+                        }
+                    },
+                    "304": {
+                        "description": "Preview not modified",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid short URL id",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Short URL not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/public/v1/short-urls/{shortURLId}/unlock": {
+            "post": {
+                "description": "Exchange the password of a short URL for a time-limited token to follow its redirect",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "short-url",
+                    "public"
+                ],
+                "summary": "Unlock a password protected short URL",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Short URL id to unlock",
+                        "name": "shortURLId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Password of the short URL",
+                        "name": "UnlockShortURLRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.UnlockShortURLRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Token unlocking the short URL",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.UnlockShortURLResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request or short URL not password protected",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid password",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Short URL not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "413": {
+                        "description": "Request body too large",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "handlers.AliasRequest": {
+            "type": "object",
+            "properties": {
+                "alias_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.AliasResponse": {
+            "type": "object",
+            "properties": {
+                "alias_id": {
+                    "type": "string"
+                },
+                "short_url": {
+                    "type": "string"
+                },
+                "short_url_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.AuditLogEntry": {
+            "type": "object",
+            "properties": {
+                "actor": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "operation": {
+                    "type": "string"
+                },
+                "payload": {
+                    "type": "object"
+                },
+                "short_url_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.AuditLogResponse": {
+            "type": "object",
+            "properties": {
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.AuditLogEntry"
+                    }
+                }
+            }
+        },
+        "handlers.ClickEvent": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "ip": {
+                    "type": "string"
+                },
+                "timestamp": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.CollectorSnapshotResponse": {
+            "type": "object",
+            "properties": {
+                "bot_visits": {
+                    "type": "integer"
+                },
+                "short_url_id": {
+                    "type": "string"
+                },
+                "unique_visits": {
+                    "type": "integer"
+                },
+                "visits": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.ConfigReloadResponse": {
+            "type": "object",
+            "properties": {
+                "ignored": {
+                    "description": "Ignored are the changed configuration sections that only take effect on restart",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "reloaded": {
+                    "description": "Reloaded are the configuration sections applied by the reload",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "handlers.CountryStatResponse": {
+            "type": "object",
+            "properties": {
+                "country": {
+                    "type": "string"
+                },
+                "visits": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.DashboardResponse": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "type": "string"
+                },
+                "short_urls_created": {
+                    "type": "integer"
+                },
+                "to": {
+                    "type": "string"
+                },
+                "top_by_unique_visits": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.DashboardShortURLStats"
+                    }
+                },
+                "top_by_visits": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.DashboardShortURLStats"
+                    }
+                },
+                "unique_visits": {
+                    "description": "UniqueVisits sums the unique visitors of every metrics interval",
+                    "type": "integer"
+                },
+                "visits": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.DashboardShortURLStats": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "long_url": {
+                    "type": "string"
+                },
+                "short_url": {
+                    "type": "string"
+                },
+                "unique_visits": {
+                    "type": "integer"
+                },
+                "visits": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.GetOrCreateShortURLRequest": {
+            "type": "object",
+            "properties": {
+                "long_url": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.GetOrCreateShortURLResponse": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "description": "Created is false when the long URL was already shortened",
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "short_url": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.ImportRowResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "description": "Error tells why the row was not imported, the short URL may still have been created when only its custom slug failed",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "long_url": {
+                    "type": "string"
+                },
+                "row": {
+                    "description": "Row is the line of the CSV row, the header being line 1",
+                    "type": "integer"
+                },
+                "short_url": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.ImportShortURLsResponse": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "type": "integer"
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "rows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.ImportRowResponse"
+                    }
+                }
+            }
+        },
+        "handlers.LatencyIntervalResponse": {
+            "type": "object",
+            "properties": {
+                "p50_ns": {
+                    "type": "integer"
+                },
+                "p95_ns": {
+                    "type": "integer"
+                },
+                "p99_ns": {
+                    "type": "integer"
+                },
+                "requests": {
+                    "type": "integer"
+                },
+                "timestamp": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.ManagerStatsResponse": {
+            "type": "object",
+            "properties": {
+                "collision_count": {
+                    "type": "integer"
+                },
+                "collision_rate": {
+                    "description": "CollisionRate is collision_count divided by create_count, 0 until a short URL is created",
+                    "type": "number"
+                },
+                "create_count": {
+                    "type": "integer"
+                },
+                "hit_count": {
+                    "type": "integer"
+                },
+                "miss_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.MetricsBulkRequest": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "type": "string"
+                },
+                "ids": {
+                    "description": "Ids are the short URLs to get metrics for, at most 100",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.MetricsBulkResponse": {
+            "type": "object",
+            "properties": {
+                "metrics": {
+                    "description": "Metrics are the metrics of each short URL asked for by id, short URLs without visits get zero metrics",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/handlers.ShortURLMetricsResponse"
+                    }
+                }
+            }
+        },
+        "handlers.MetricsSnapshotResponse": {
+            "type": "object",
+            "properties": {
+                "short_urls": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/handlers.CollectorSnapshotResponse"
+                    }
+                }
+            }
+        },
+        "handlers.ProtectedShortURLResponse": {
+            "type": "object",
+            "properties": {
+                "protected": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "handlers.ResolveShortURLsResponse": {
+            "type": "object",
+            "properties": {
+                "long_urls": {
+                    "description": "LongURLs are the long URLs of the short URLs found by id",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "not_found": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "handlers.ShortURLDetailsResponse": {
+            "type": "object",
+            "properties": {
+                "cache_ttl_seconds": {
+                    "type": "integer"
+                },
+                "click_count": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "forward_query_params": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "long_url": {
+                    "type": "string"
+                },
+                "max_clicks": {
+                    "type": "integer"
+                },
+                "protected": {
+                    "type": "boolean"
+                },
+                "redirect_code": {
+                    "type": "integer"
+                },
+                "short_url": {
+                    "type": "string"
+                },
+                "show_interstitial": {
+                    "type": "boolean"
+                },
+                "status": {
+                    "$ref": "#/definitions/shorturl.Status"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.ShortURLLatencyResponse": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "type": "string"
+                },
+                "intervals": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.LatencyIntervalResponse"
+                    }
+                },
+                "short_url_id": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.ShortURLListItem": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "long_url": {
+                    "type": "string"
+                },
+                "short_url": {
+                    "type": "string"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.ShortURLListResponse": {
+            "type": "object",
+            "properties": {
+                "short_urls": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.ShortURLListItem"
+                    }
+                }
+            }
+        },
+        "handlers.ShortURLMetricsResponse": {
+            "type": "object",
+            "properties": {
+                "bot_visits": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "description": "CreatedAt is the creation time of the short URL, it is left out of bulk responses",
+                    "type": "string"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "short_url_id": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string"
+                },
+                "top_countries": {
+                    "description": "TopCountries are the countries with the most visits, most visited first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.CountryStatResponse"
+                    }
+                },
+                "unique_visits": {
+                    "type": "integer"
+                },
+                "visits": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.ShortURLPreviewResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "long_url": {
+                    "type": "string"
+                },
+                "protected": {
+                    "type": "boolean"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.ShortURLRequest": {
+            "type": "object",
+            "properties": {
+                "cache_ttl_seconds": {
+                    "description": "CacheTTLSeconds is how long the redirect is cached, 1 second to 1 year, the service default is used when it is\nnot set or 0",
+                    "type": "integer"
+                },
+                "description": {
+                    "description": "Description is a human readable note of at most 512 characters about the short URL",
+                    "type": "string"
+                },
+                "expires_at": {
+                    "description": "ExpiresAt must be in the future, the short URL never expires when it is not set",
+                    "type": "string"
+                },
+                "forward_query_params": {
+                    "description": "ForwardQueryParams appends the query parameters of the short URL request to the long URL on redirect",
+                    "type": "boolean"
+                },
+                "long_url": {
+                    "type": "string"
+                },
+                "max_clicks": {
+                    "type": "integer"
+                },
+                "password": {
+                    "type": "string"
+                },
+                "redirect_code": {
+                    "description": "RedirectCode is one of 301, 302 or 307, the service default is used when it is not set",
+                    "type": "integer"
+                },
+                "show_interstitial": {
+                    "description": "ShowInterstitial shows a page with the long URL that redirects after a 5 seconds countdown",
+                    "type": "boolean"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "webhook": {
+                    "$ref": "#/definitions/handlers.WebhookConfig"
+                }
+            }
+        },
+        "handlers.ShortURLResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "short_url": {
+                    "type": "string"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "webhook": {
+                    "$ref": "#/definitions/handlers.WebhookResponse"
+                }
+            }
+        },
+        "handlers.UnlockShortURLRequest": {
+            "type": "object",
+            "properties": {
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.UnlockShortURLResponse": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.WarmCacheRequest": {
+            "type": "object",
+            "properties": {
+                "ids": {
+                    "description": "Ids are the short URLs to cache, at most 10000",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "handlers.WebhookConfig": {
+            "type": "object",
+            "properties": {
+                "secret": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.WebhookResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "short_url_id": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "shorturl.Status": {
+            "type": "string",
+            "enum": [
+                "active",
+                "paused",
+                "archived"
+            ],
+            "x-enum-varnames": [
+                "StatusActive",
+                "StatusPaused",
+                "StatusArchived"
+            ]
         }
     }
 }`
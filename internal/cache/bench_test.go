@@ -0,0 +1,162 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+// benchmarkProtocols runs the benchmark against a cache of each RESP protocol version backed by miniredis
+func benchmarkProtocols(b *testing.B, benchmark func(b *testing.B, cache *Cache)) {
+	for _, protocol := range []int{2, 3} {
+		b.Run(fmt.Sprintf("RESP%d", protocol), func(b *testing.B) {
+			server := miniredis.RunT(b)
+
+			config := DefaultConfig()
+			config.Addr = server.Addr()
+			config.Protocol = protocol
+			config.EnablePatternDelete = true
+			if err := config.Validate(); err != nil {
+				b.Fatal(err)
+			}
+
+			cache := NewCache(config)
+			defer cache.Close()
+
+			b.ReportAllocs()
+			benchmark(b, cache)
+		})
+	}
+}
+
+func BenchmarkGetHit(b *testing.B) {
+	benchmarkProtocols(b, func(b *testing.B, cache *Cache) {
+		ctx := context.Background()
+		if err := cache.Set(ctx, "AABBCC", "https://example.com", time.Hour); err != nil {
+			b.Fatal(err)
+		}
+
+		for b.Loop() {
+			if _, found, err := cache.Get(ctx, "AABBCC"); err != nil || !found {
+				b.Fatal("cached value not found", err)
+			}
+		}
+	})
+}
+
+func BenchmarkGetMiss(b *testing.B) {
+	benchmarkProtocols(b, func(b *testing.B, cache *Cache) {
+		ctx := context.Background()
+
+		for b.Loop() {
+			if _, found, err := cache.Get(ctx, "AABBCC"); err != nil || found {
+				b.Fatal("uncached value found", err)
+			}
+		}
+	})
+}
+
+func BenchmarkSet(b *testing.B) {
+	benchmarkProtocols(b, func(b *testing.B, cache *Cache) {
+		ctx := context.Background()
+
+		for b.Loop() {
+			if err := cache.Set(ctx, "AABBCC", "https://example.com", time.Hour); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkDelete(b *testing.B) {
+	benchmarkProtocols(b, func(b *testing.B, cache *Cache) {
+		ctx := context.Background()
+
+		for b.Loop() {
+			if err := cache.Delete(ctx, "AABBCC"); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+// BenchmarkDeleteByPattern deletes 100 matching keys out of 1000 per operation, setting them again is not timed
+func BenchmarkDeleteByPattern(b *testing.B) {
+	benchmarkProtocols(b, func(b *testing.B, cache *Cache) {
+		ctx := context.Background()
+		for i := range 900 {
+			if err := cache.Set(ctx, fmt.Sprintf("other:%d", i), "value", time.Hour); err != nil {
+				b.Fatal(err)
+			}
+		}
+
+		for b.Loop() {
+			b.StopTimer()
+			for i := range 100 {
+				if err := cache.Set(ctx, fmt.Sprintf("tenant:%d", i), "value", time.Hour); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.StartTimer()
+
+			if err := cache.DeleteByPattern(ctx, "tenant:*"); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+// cachedKeys sets n keys in cache and returns them
+func cachedKeys(b *testing.B, cache *Cache, n int) []string {
+	keys := make([]string, n)
+	entries := make(map[string]string, n)
+	for i := range n {
+		keys[i] = fmt.Sprintf("%06d", i)
+		entries[keys[i]] = "https://example.com/" + keys[i]
+	}
+
+	if err := cache.SetMulti(context.Background(), entries, time.Hour); err != nil {
+		b.Fatal(err)
+	}
+
+	return keys
+}
+
+func BenchmarkGetN(b *testing.B) {
+	for _, n := range []int{10, 100, 1000} {
+		b.Run(fmt.Sprintf("N=%d", n), func(b *testing.B) {
+			benchmarkProtocols(b, func(b *testing.B, cache *Cache) {
+				ctx := context.Background()
+				keys := cachedKeys(b, cache, n)
+
+				for b.Loop() {
+					for _, key := range keys {
+						if _, found, err := cache.Get(ctx, key); err != nil || !found {
+							b.Fatal("cached value not found", err)
+						}
+					}
+				}
+			})
+		})
+	}
+}
+
+func BenchmarkGetMulti(b *testing.B) {
+	for _, n := range []int{10, 100, 1000} {
+		b.Run(fmt.Sprintf("N=%d", n), func(b *testing.B) {
+			benchmarkProtocols(b, func(b *testing.B, cache *Cache) {
+				ctx := context.Background()
+				keys := cachedKeys(b, cache, n)
+
+				for b.Loop() {
+					if values, err := cache.GetMulti(ctx, keys); err != nil || len(values) != n {
+						b.Fatal("cached values not found", err)
+					}
+				}
+			})
+		})
+	}
+}
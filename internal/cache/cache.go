@@ -3,52 +3,120 @@ package cache
 import (
 	"context"
 	"errors"
-	"fmt"
+	"slices"
 	"time"
 
 	"github.com/redis/go-redis/v9"
 )
 
-const (
-	namespace = "short_url:%s"
-)
+// patternDeleteBatchSize is the number of keys scanned and deleted at a time by DeleteByPattern
+const patternDeleteBatchSize = 100
+
+// ErrPatternDeleteDisabled is returned by DeleteByPattern unless EnablePatternDelete is configured
+var ErrPatternDeleteDisabled = errors.New("cache pattern delete is disabled")
 
 // Cache contains resource to interact with cache
 type Cache struct {
-	client *redis.Client
+	// client is a *redis.Client for single node and Sentinel configs, or a *redis.ClusterClient for cluster configs
+	client redis.UniversalClient
+	// namespace prefixes every key of the cache
+	namespace     string
+	patternDelete bool
 }
 
-// NewCache creates a new Cache instance with the provided configuration
+// NewCache creates a new Cache instance with the provided configuration, it connects to a Redis Cluster when cluster
+// addrs are configured and through Redis Sentinel when sentinel addrs are
 func NewCache(config *Config) *Cache {
-	client := redis.NewClient(&redis.Options{
-		Addr:     config.Addr,
-		Password: config.Password,
-		DB:       config.DB,
-		Protocol: config.Protocol,
-	})
+	var client redis.UniversalClient
+	if len(config.ClusterAddrs) > 0 {
+		client = redis.NewClusterClient(&redis.ClusterOptions{
+			Addrs:        config.ClusterAddrs,
+			MaxRedirects: config.ClusterMaxRedirects,
+			Password:     config.Password,
+			Protocol:     config.Protocol,
+			DialTimeout:  time.Duration(config.DialTimeoutInMS) * time.Millisecond,
+			ReadTimeout:  time.Duration(config.ReadTimeoutInMS) * time.Millisecond,
+			WriteTimeout: time.Duration(config.WriteTimeoutInMS) * time.Millisecond,
+			PoolSize:     config.PoolSize,
+			MinIdleConns: config.MinIdleConns,
+			PoolTimeout:  time.Duration(config.PoolTimeoutInMS) * time.Millisecond,
+		})
+	} else if len(config.SentinelAddrs) > 0 {
+		client = redis.NewFailoverClient(&redis.FailoverOptions{
+			MasterName:       config.MasterName,
+			SentinelAddrs:    config.SentinelAddrs,
+			SentinelPassword: config.SentinelPassword,
+			Password:         config.Password,
+			DB:               config.DB,
+			Protocol:         config.Protocol,
+			DialTimeout:      time.Duration(config.DialTimeoutInMS) * time.Millisecond,
+			ReadTimeout:      time.Duration(config.ReadTimeoutInMS) * time.Millisecond,
+			WriteTimeout:     time.Duration(config.WriteTimeoutInMS) * time.Millisecond,
+			PoolSize:         config.PoolSize,
+			MinIdleConns:     config.MinIdleConns,
+			PoolTimeout:      time.Duration(config.PoolTimeoutInMS) * time.Millisecond,
+		})
+	} else {
+		client = redis.NewClient(&redis.Options{
+			Addr:         config.Addr,
+			Password:     config.Password,
+			DB:           config.DB,
+			Protocol:     config.Protocol,
+			DialTimeout:  time.Duration(config.DialTimeoutInMS) * time.Millisecond,
+			ReadTimeout:  time.Duration(config.ReadTimeoutInMS) * time.Millisecond,
+			WriteTimeout: time.Duration(config.WriteTimeoutInMS) * time.Millisecond,
+			PoolSize:     config.PoolSize,
+			MinIdleConns: config.MinIdleConns,
+			PoolTimeout:  time.Duration(config.PoolTimeoutInMS) * time.Millisecond,
+		})
+	}
 
 	client.Ping(context.Background())
 
 	return &Cache{
-		client: client,
+		client:        client,
+		namespace:     config.Namespace,
+		patternDelete: config.EnablePatternDelete,
+	}
+}
+
+// WithNamespace returns a cache whose keys are scoped to ns within the namespace of c, so caches of different
+// resources cannot collide. It shares the connection of c, closing either of them closes both.
+func (c *Cache) WithNamespace(ns string) *Cache {
+	return &Cache{
+		client:        c.client,
+		namespace:     c.key(ns),
+		patternDelete: c.patternDelete,
 	}
 }
 
-// Healthy checks cache connection health
+// key returns the namespaced key of a cache key
+func (c *Cache) key(key string) string {
+	return c.namespace + ":" + key
+}
+
+// Healthy checks cache connection health, a cluster is pinged through one of its nodes
 func (c *Cache) Healthy() bool {
 	_, err := c.client.Ping(context.Background()).Result()
 
 	return err == nil
 }
 
-// Close closes the cache connection
+// Close closes the cache connection, closing it again does nothing. Caches created with WithNamespace share the
+// connection and are closed with it.
 func (c *Cache) Close() error {
-	return c.client.Close()
+	if err := c.client.Close(); err != nil && !errors.Is(err, redis.ErrClosed) {
+		return err
+	}
+
+	return nil
 }
 
 // Get retrieves a value from the cache by its key
 func (c *Cache) Get(ctx context.Context, key string) (string, bool, error) {
-	val, err := c.client.Get(ctx, fmt.Sprintf(namespace, key)).Result()
+	defer observeDuration("get")()
+
+	val, err := c.client.Get(ctx, c.key(key)).Result()
 	if err != nil {
 		if errors.Is(err, redis.Nil) {
 			return "", false, nil
@@ -61,7 +129,99 @@ func (c *Cache) Get(ctx context.Context, key string) (string, bool, error) {
 
 // Set adds a key-value pair to the cache with a ttl expiration time
 func (c *Cache) Set(ctx context.Context, key string, value string, duration time.Duration) error {
-	_, err := c.client.Set(ctx, fmt.Sprintf(namespace, key), value, duration).Result()
+	defer observeDuration("set")()
+
+	_, err := c.client.Set(ctx, c.key(key), value, duration).Result()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SetNX adds a key-value pair to the cache with a ttl expiration time unless the key is already set, it reports
+// whether the key was set
+func (c *Cache) SetNX(ctx context.Context, key string, value string, duration time.Duration) (bool, error) {
+	defer observeDuration("set_nx")()
+
+	return c.client.SetNX(ctx, c.key(key), value, duration).Result()
+}
+
+// GetMulti retrieves the values of several keys from the cache with a single MGET, keys not found are left out of
+// the returned map. A cluster is sent a pipeline of GETs instead, the keys may belong to different hash slots.
+func (c *Cache) GetMulti(ctx context.Context, keys []string) (map[string]string, error) {
+	defer observeDuration("get_multi")()
+
+	if len(keys) == 0 {
+		return map[string]string{}, nil
+	}
+
+	namespacedKeys := make([]string, len(keys))
+	for i, key := range keys {
+		namespacedKeys[i] = c.key(key)
+	}
+
+	var values []interface{}
+	var err error
+	if _, ok := c.client.(*redis.ClusterClient); ok {
+		values, err = c.pipelinedGet(ctx, namespacedKeys)
+	} else {
+		values, err = c.client.MGet(ctx, namespacedKeys...).Result()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	found := make(map[string]string, len(keys))
+	for i, value := range values {
+		if value, ok := value.(string); ok {
+			found[keys[i]] = value
+		}
+	}
+
+	return found, nil
+}
+
+// pipelinedGet gets the values of several keys with a pipeline of GETs, missing keys have a nil value like with MGET
+func (c *Cache) pipelinedGet(ctx context.Context, namespacedKeys []string) ([]interface{}, error) {
+	cmds := make([]*redis.StringCmd, len(namespacedKeys))
+	_, err := c.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, key := range namespacedKeys {
+			cmds[i] = pipe.Get(ctx, key)
+		}
+
+		return nil
+	})
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+
+	values := make([]interface{}, len(cmds))
+	for i, cmd := range cmds {
+		if value, err := cmd.Result(); err == nil {
+			values[i] = value
+		}
+	}
+
+	return values, nil
+}
+
+// SetMulti adds several key-value pairs to the cache with the same ttl expiration time, they are sent in a single
+// pipeline
+func (c *Cache) SetMulti(ctx context.Context, entries map[string]string, duration time.Duration) error {
+	defer observeDuration("set_multi")()
+
+	if len(entries) == 0 {
+		return nil
+	}
+
+	_, err := c.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for key, value := range entries {
+			pipe.Set(ctx, c.key(key), value, duration)
+		}
+
+		return nil
+	})
 	if err != nil {
 		return err
 	}
@@ -69,9 +229,105 @@ func (c *Cache) Set(ctx context.Context, key string, value string, duration time
 	return nil
 }
 
-// Delete removes a key from the cache
+// Delete removes a key from the cache, deleting a key that does not exist, like an expired one, is not an error.
+// Only errors reaching the cache are returned.
 func (c *Cache) Delete(ctx context.Context, key string) error {
-	_, err := c.client.Del(ctx, fmt.Sprintf(namespace, key)).Result()
+	defer observeDuration("delete")()
+
+	// DEL replies with the number of deleted keys, 0 for missing ones, redis.Nil is not expected but means the same
+	_, err := c.client.Del(ctx, c.key(key)).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteByPattern removes the keys matching a glob-style pattern within the namespace of the cache. Keys are scanned
+// and deleted in batches so Redis is not blocked like with KEYS, keys set while it runs may be left behind. Every
+// master of a cluster is scanned. ErrPatternDeleteDisabled is returned unless EnablePatternDelete is configured.
+func (c *Cache) DeleteByPattern(ctx context.Context, pattern string) error {
+	defer observeDuration("delete_by_pattern")()
+
+	if !c.patternDelete {
+		return ErrPatternDeleteDisabled
+	}
+
+	cluster, ok := c.client.(*redis.ClusterClient)
+	if !ok {
+		return c.scanAndDelete(ctx, c.client, pattern, func(keys []string) error {
+			return c.client.Del(ctx, keys...).Err()
+		})
+	}
+
+	// SCAN only walks the keys of one node and a DEL of keys in different hash slots fails with CROSSSLOT, so every
+	// master is scanned and its keys are deleted one at a time, each DEL is routed to the node of its slot
+	return cluster.ForEachMaster(ctx, func(ctx context.Context, master *redis.Client) error {
+		return c.scanAndDelete(ctx, master, pattern, func(keys []string) error {
+			_, err := cluster.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+				for _, key := range keys {
+					pipe.Del(ctx, key)
+				}
+
+				return nil
+			})
+
+			return err
+		})
+	})
+}
+
+// scanAndDelete scans the keys of a node matching a pattern within the namespace of the cache and calls del with
+// batches of at most patternDeleteBatchSize of them
+func (c *Cache) scanAndDelete(ctx context.Context, node redis.Cmdable, pattern string, del func(keys []string) error) error {
+	var cursor uint64
+	for {
+		keys, next, err := node.Scan(ctx, cursor, c.key(pattern), patternDeleteBatchSize).Result()
+		if err != nil {
+			return err
+		}
+
+		// COUNT is only a hint, a scan can return more keys than a batch
+		for batch := range slices.Chunk(keys, patternDeleteBatchSize) {
+			if err := del(batch); err != nil && !errors.Is(err, redis.Nil) {
+				return err
+			}
+		}
+
+		if next == 0 {
+			return nil
+		}
+		cursor = next
+	}
+}
+
+// GetHash retrieves all the fields of a hash from the cache by its key
+func (c *Cache) GetHash(ctx context.Context, key string) (map[string]string, bool, error) {
+	defer observeDuration("get_hash")()
+
+	fields, err := c.client.HGetAll(ctx, c.key(key)).Result()
+	if err != nil {
+		return nil, false, err
+	}
+	if len(fields) == 0 {
+		return nil, false, nil
+	}
+
+	return fields, true, nil
+}
+
+// SetHash stores the fields of a hash in the cache with a ttl expiration time
+func (c *Cache) SetHash(ctx context.Context, key string, fields map[string]string, duration time.Duration) error {
+	defer observeDuration("set_hash")()
+
+	namespacedKey := c.key(key)
+
+	_, err := c.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, namespacedKey, fields)
+		pipe.Expire(ctx, namespacedKey, duration)
+
+		return nil
+	})
 	if err != nil {
 		return err
 	}
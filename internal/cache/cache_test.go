@@ -0,0 +1,414 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/suite"
+)
+
+type CacheSuite struct {
+	suite.Suite
+}
+
+func TestCacheSuite(t *testing.T) {
+	suite.Run(t, new(CacheSuite))
+}
+
+func (suite *CacheSuite) TestNewCacheCustomPoolSettings() {
+	config := DefaultConfig()
+	config.DialTimeoutInMS = 100
+	config.ReadTimeoutInMS = 200
+	config.WriteTimeoutInMS = 300
+	config.PoolSize = 20
+	config.MinIdleConns = 5
+	config.PoolTimeoutInMS = 400
+	suite.Require().NoError(config.Validate())
+
+	cache := NewCache(config)
+	defer cache.Close()
+
+	options := cache.client.(*redis.Client).Options()
+	suite.Equal(100*time.Millisecond, options.DialTimeout)
+	suite.Equal(200*time.Millisecond, options.ReadTimeout)
+	suite.Equal(300*time.Millisecond, options.WriteTimeout)
+	suite.Equal(20, options.PoolSize)
+	suite.Equal(5, options.MinIdleConns)
+	suite.Equal(400*time.Millisecond, options.PoolTimeout)
+}
+
+func (suite *CacheSuite) TestCloseTwice() {
+	cache := NewCache(DefaultConfig())
+
+	suite.NoError(cache.Close())
+	suite.NoError(cache.Close())
+}
+
+func (suite *CacheSuite) TestNewCacheClientType() {
+	config := DefaultConfig()
+	config.DialTimeoutInMS = 100
+	suite.Require().NoError(config.Validate())
+
+	cache := NewCache(config)
+	defer cache.Close()
+
+	suite.Equal(config.Addr, cache.client.(*redis.Client).Options().Addr)
+
+	config.SentinelAddrs = []string{"localhost:26379"}
+	config.MasterName = "mymaster"
+	config.SentinelPassword = "sentinel-secret"
+	suite.Require().NoError(config.Validate())
+
+	failoverCache := NewCache(config)
+	defer failoverCache.Close()
+
+	// Failover clients resolve the master address through the Sentinels instead of a configured one
+	suite.Equal("FailoverClient", failoverCache.client.(*redis.Client).Options().Addr)
+	suite.Equal(100*time.Millisecond, failoverCache.client.(*redis.Client).Options().DialTimeout)
+}
+
+func (suite *CacheSuite) TestNewCacheClusterClient() {
+	config := DefaultConfig()
+	config.Addr = ""
+	config.ClusterAddrs = []string{"localhost:7000", "localhost:7001"}
+	config.ClusterMaxRedirects = 5
+	config.DialTimeoutInMS = 100
+	suite.Require().NoError(config.Validate())
+
+	cache := NewCache(config)
+	defer cache.Close()
+
+	cluster, ok := cache.client.(*redis.ClusterClient)
+	suite.Require().True(ok)
+	suite.Equal(config.ClusterAddrs, cluster.Options().Addrs)
+	suite.Equal(5, cluster.Options().MaxRedirects)
+	suite.Equal(100*time.Millisecond, cluster.Options().DialTimeout)
+}
+
+func (suite *CacheSuite) TestConfigValidateCluster() {
+	config := DefaultConfig()
+	config.ClusterAddrs = []string{"localhost:7000"}
+	suite.Require().Error(config.Validate(), "addr and cluster addrs are mutually exclusive")
+
+	config.Addr = ""
+	suite.Require().NoError(config.Validate())
+
+	config.SentinelAddrs = []string{"localhost:26379"}
+	config.MasterName = "mymaster"
+	suite.Require().Error(config.Validate(), "sentinel addrs and cluster addrs are mutually exclusive")
+
+	config.SentinelAddrs = nil
+	config.ClusterMaxRedirects = 0
+	suite.Require().Error(config.Validate())
+
+	config.ClusterMaxRedirects = 3
+	config.DB = 1
+	suite.Require().Error(config.Validate())
+}
+
+func (suite *CacheSuite) TestConfigValidateSentinel() {
+	config := DefaultConfig()
+	config.Addr = ""
+	config.SentinelAddrs = []string{"localhost:26379"}
+	suite.Require().Error(config.Validate())
+
+	config.MasterName = "mymaster"
+	suite.Require().NoError(config.Validate())
+}
+
+func (suite *CacheSuite) TestConfigValidateFailInvalidPoolSettings() {
+	testCases := []struct {
+		name   string
+		modify func(c *Config)
+	}{
+		{name: "dial timeout", modify: func(c *Config) { c.DialTimeoutInMS = 0 }},
+		{name: "read timeout", modify: func(c *Config) { c.ReadTimeoutInMS = -1 }},
+		{name: "write timeout", modify: func(c *Config) { c.WriteTimeoutInMS = 0 }},
+		{name: "pool size", modify: func(c *Config) { c.PoolSize = 0 }},
+		{name: "min idle conns", modify: func(c *Config) { c.MinIdleConns = 0 }},
+		{name: "min idle conns above pool size", modify: func(c *Config) { c.MinIdleConns = c.PoolSize + 1 }},
+		{name: "pool timeout", modify: func(c *Config) { c.PoolTimeoutInMS = 0 }},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			config := DefaultConfig()
+			tc.modify(config)
+			suite.Require().Error(config.Validate())
+		})
+	}
+}
+
+func (suite *CacheSuite) TestWithNamespace() {
+	config := DefaultConfig()
+	config.DialTimeoutInMS = 100
+	suite.Require().NoError(config.Validate())
+
+	cache := NewCache(config)
+	defer cache.Close()
+
+	urls := cache.WithNamespace("urls")
+	idempotency := cache.WithNamespace("idempotency")
+
+	suite.Equal("short_url:AABBCC", cache.key("AABBCC"))
+	suite.Equal("short_url:urls:AABBCC", urls.key("AABBCC"))
+	suite.Equal("short_url:idempotency:AABBCC", idempotency.key("AABBCC"))
+	suite.Equal("short_url:urls:qr:AABBCC", urls.WithNamespace("qr").key("AABBCC"))
+	suite.Same(cache.client, urls.client)
+}
+
+func (suite *CacheSuite) TestConfigValidateNamespace() {
+	config := DefaultConfig()
+	suite.NoError(config.Validate())
+
+	config.Namespace = ""
+	suite.Error(config.Validate())
+}
+
+func (suite *CacheSuite) TestDeleteFailUnreachable() {
+	config := DefaultConfig()
+	config.Addr = "localhost:1"
+	config.DialTimeoutInMS = 100
+	suite.Require().NoError(config.Validate())
+
+	cache := NewCache(config)
+	defer cache.Close()
+
+	// Only errors reaching the cache are returned, a missing key is not one of them
+	suite.Error(cache.Delete(context.Background(), "AABBCC"))
+}
+
+func (suite *CacheSuite) TestOperationDurationObserved() {
+	config := DefaultConfig()
+	config.Addr = "localhost:1"
+	config.DialTimeoutInMS = 100
+	suite.Require().NoError(config.Validate())
+
+	cache := NewCache(config)
+	defer cache.Close()
+
+	operationDuration.Reset()
+
+	// Failed operations are timed too
+	_, _, err := cache.Get(context.Background(), "AABBCC")
+	suite.Error(err)
+	suite.Equal(1, testutil.CollectAndCount(operationDuration, "cache_operation_duration_seconds"))
+
+	suite.Error(cache.Set(context.Background(), "AABBCC", "https://example.com", time.Minute))
+	suite.Equal(2, testutil.CollectAndCount(operationDuration, "cache_operation_duration_seconds"))
+}
+
+// fakeKeysHook answers SCAN and DEL commands from keys without reaching Redis
+type fakeKeysHook struct {
+	keys []string
+	// deletes are the keys of each DEL command
+	deletes [][]string
+}
+
+func (h *fakeKeysHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+func (h *fakeKeysHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		args := cmd.Args()
+		switch cmd.Name() {
+		case "scan":
+			// The cursor is the index of the next key, each scan returns at most 2 batches of matching keys
+			cursor, pattern := int(args[1].(uint64)), args[3].(string)
+			var matched []string
+			end := min(cursor+2*patternDeleteBatchSize, len(h.keys))
+			for _, key := range h.keys[cursor:end] {
+				if ok, _ := path.Match(pattern, key); ok {
+					matched = append(matched, key)
+				}
+			}
+			if end == len(h.keys) {
+				end = 0
+			}
+			cmd.(*redis.ScanCmd).SetVal(matched, uint64(end))
+		case "del":
+			var deleted []string
+			for _, arg := range args[1:] {
+				deleted = append(deleted, arg.(string))
+			}
+			h.deletes = append(h.deletes, deleted)
+			cmd.(*redis.IntCmd).SetVal(int64(len(deleted)))
+		default:
+			return next(ctx, cmd)
+		}
+
+		return nil
+	}
+}
+
+func (h *fakeKeysHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return next
+}
+
+func (suite *CacheSuite) TestDeleteByPattern() {
+	config := DefaultConfig()
+	config.Addr = "localhost:1"
+	config.DialTimeoutInMS = 100
+	config.EnablePatternDelete = true
+	suite.Require().NoError(config.Validate())
+
+	cache := NewCache(config)
+	defer cache.Close()
+
+	var qrKeys []string
+	for i := range 250 {
+		qrKeys = append(qrKeys, fmt.Sprintf("short_url:urls:qr:AABBCC:%d", i))
+	}
+	hook := &fakeKeysHook{keys: append(slices.Clone(qrKeys), "short_url:urls:AABBCC", "short_url:urls:qr:DDEEFF:0")}
+	cache.client.AddHook(hook)
+
+	suite.Require().NoError(cache.WithNamespace("urls").DeleteByPattern(context.Background(), "qr:AABBCC:*"))
+
+	var deleted []string
+	for _, keys := range hook.deletes {
+		suite.LessOrEqual(len(keys), patternDeleteBatchSize)
+		deleted = append(deleted, keys...)
+	}
+	suite.Len(hook.deletes, 3)
+	suite.Equal(qrKeys, deleted)
+}
+
+func (suite *CacheSuite) TestDeleteByPatternFailDisabled() {
+	config := DefaultConfig()
+	config.Addr = "localhost:1"
+	config.DialTimeoutInMS = 100
+	suite.Require().NoError(config.Validate())
+
+	cache := NewCache(config)
+	defer cache.Close()
+
+	suite.ErrorIs(cache.DeleteByPattern(context.Background(), "qr:AABBCC:*"), ErrPatternDeleteDisabled)
+}
+
+func (suite *CacheSuite) TestConfigValidatePatternDelete() {
+	config := DefaultConfig()
+	config.EnablePatternDelete = true
+	suite.NoError(config.Validate())
+
+	config.Addr = ""
+	config.ClusterAddrs = []string{"localhost:7000"}
+	suite.NoError(config.Validate())
+}
+
+func (suite *CacheSuite) TestDeleteByPatternCluster() {
+	// Two masters owning half of the hash slots each
+	first, second := miniredis.RunT(suite.T()), miniredis.RunT(suite.T())
+	client := redis.NewClusterClient(&redis.ClusterOptions{
+		ClusterSlots: func(context.Context) ([]redis.ClusterSlot, error) {
+			return []redis.ClusterSlot{
+				{Start: 0, End: 8191, Nodes: []redis.ClusterNode{{Addr: first.Addr()}}},
+				{Start: 8192, End: 16383, Nodes: []redis.ClusterNode{{Addr: second.Addr()}}},
+			}, nil
+		},
+	})
+	cache := (&Cache{client: client, namespace: "short_url", patternDelete: true}).WithNamespace("urls")
+	defer cache.Close()
+
+	ctx := context.Background()
+	for i := range 50 {
+		suite.Require().NoError(cache.Set(ctx, fmt.Sprintf("qr:AABBCC:%d", i), "png", time.Minute))
+	}
+	suite.Require().NoError(cache.Set(ctx, "AABBCC", "https://example.com", time.Minute))
+	suite.Require().NoError(cache.Set(ctx, "qr:DDEEFF:0", "png", time.Minute))
+
+	// The keys are spread over both masters
+	suite.NotEmpty(first.Keys())
+	suite.NotEmpty(second.Keys())
+
+	suite.Require().NoError(cache.DeleteByPattern(ctx, "qr:AABBCC:*"))
+
+	remaining := append(first.Keys(), second.Keys()...)
+	suite.ElementsMatch([]string{"short_url:urls:AABBCC", "short_url:urls:qr:DDEEFF:0"}, remaining)
+}
+
+// newMiniredisCache returns a cache backed by miniredis, within the urls namespace
+func (suite *CacheSuite) newMiniredisCache() (*Cache, *miniredis.Miniredis) {
+	server := miniredis.RunT(suite.T())
+
+	config := DefaultConfig()
+	config.Addr = server.Addr()
+	suite.Require().NoError(config.Validate())
+
+	cache := NewCache(config)
+	suite.T().Cleanup(func() { _ = cache.Close() })
+
+	return cache.WithNamespace("urls"), server
+}
+
+func (suite *CacheSuite) TestGetMultiAndSetMulti() {
+	cache, server := suite.newMiniredisCache()
+	ctx := context.Background()
+
+	suite.Require().NoError(cache.SetMulti(ctx, map[string]string{
+		"AABBCC": "https://example.com/a",
+		"DDEEFF": "https://example.com/d",
+	}, time.Minute))
+
+	value, err := server.Get("short_url:urls:AABBCC")
+	suite.Require().NoError(err)
+	suite.Equal("https://example.com/a", value)
+	suite.Equal(time.Minute, server.TTL("short_url:urls:DDEEFF"))
+
+	values, err := cache.GetMulti(ctx, []string{"AABBCC", "GGHHII", "DDEEFF"})
+	suite.Require().NoError(err)
+	suite.Equal(map[string]string{
+		"AABBCC": "https://example.com/a",
+		"DDEEFF": "https://example.com/d",
+	}, values)
+}
+
+func (suite *CacheSuite) TestGetMultiAndSetMultiEmpty() {
+	cache, _ := suite.newMiniredisCache()
+	ctx := context.Background()
+
+	suite.NoError(cache.SetMulti(ctx, nil, time.Minute))
+
+	values, err := cache.GetMulti(ctx, nil)
+	suite.NoError(err)
+	suite.Empty(values)
+}
+
+func (suite *CacheSuite) TestGetMultiFailUnreachable() {
+	config := DefaultConfig()
+	config.Addr = "localhost:1"
+	config.DialTimeoutInMS = 100
+	suite.Require().NoError(config.Validate())
+
+	cache := NewCache(config)
+	defer cache.Close()
+
+	_, err := cache.GetMulti(context.Background(), []string{"AABBCC"})
+	suite.Error(err)
+	suite.Error(cache.SetMulti(context.Background(), map[string]string{"AABBCC": "https://example.com"}, time.Minute))
+}
+
+func (suite *CacheSuite) TestSetNX() {
+	cache, server := suite.newMiniredisCache()
+	ctx := context.Background()
+
+	set, err := cache.SetNX(ctx, "AABBCC", "first", time.Minute)
+	suite.Require().NoError(err)
+	suite.True(set)
+
+	set, err = cache.SetNX(ctx, "AABBCC", "second", time.Minute)
+	suite.Require().NoError(err)
+	suite.False(set)
+
+	value, _, err := cache.Get(ctx, "AABBCC")
+	suite.Require().NoError(err)
+	suite.Equal("first", value)
+	suite.Equal(time.Minute, server.TTL("short_url:urls:AABBCC"))
+}
@@ -4,33 +4,101 @@ import "errors"
 
 // Config holds the configuration for the cache connection.
 type Config struct {
-	Addr     string `json:"addr"`
-	Password string `json:"password"`
-	DB       int    `json:"db"`
-	Protocol int    `json:"protocol"`
+	// Namespace prefixes every key, caches of resources are scoped further with Cache.WithNamespace
+	Namespace        string `json:"namespace"`
+	Addr             string `json:"addr"`
+	Password         string `json:"password"`
+	DB               int    `json:"db"`
+	Protocol         int    `json:"protocol"`
+	DialTimeoutInMS  int    `json:"dial_timeout_in_ms"`
+	ReadTimeoutInMS  int    `json:"read_timeout_in_ms"`
+	WriteTimeoutInMS int    `json:"write_timeout_in_ms"`
+	PoolSize         int    `json:"pool_size"`
+	MinIdleConns     int    `json:"min_idle_conns"`
+	PoolTimeoutInMS  int    `json:"pool_timeout_in_ms"`
+	// SentinelAddrs are the Redis Sentinel addresses, when set the cache connects to the master named MasterName
+	// through them and Addr is ignored.
+	SentinelAddrs []string `json:"sentinel_addrs"`
+	MasterName    string   `json:"master_name"`
+	// SentinelPassword authenticates with the Sentinels, Password is still used for the Redis nodes.
+	SentinelPassword string `json:"sentinel_password"`
+	// ClusterAddrs are the seed addresses of a Redis Cluster, they cannot be set along with Addr.
+	ClusterAddrs        []string `json:"cluster_addrs"`
+	ClusterMaxRedirects int      `json:"cluster_max_redirects"`
+	// EnablePatternDelete allows Cache.DeleteByPattern, which scans the keys of every Redis node, every master of a
+	// cluster
+	EnablePatternDelete bool `json:"enable_pattern_delete"`
 }
 
 // DefaultConfig returns the default configuration for the cache connection.
 func DefaultConfig() *Config {
 	return &Config{
-		Addr:     "localhost:6379",
-		Password: "",
-		DB:       0,
-		Protocol: 2,
+		Namespace:           "short_url",
+		Addr:                "localhost:6379",
+		Password:            "",
+		DB:                  0,
+		Protocol:            2,
+		DialTimeoutInMS:     5000,
+		ReadTimeoutInMS:     3000,
+		WriteTimeoutInMS:    3000,
+		PoolSize:            10,
+		MinIdleConns:        2,
+		PoolTimeoutInMS:     4000,
+		ClusterMaxRedirects: 3,
 	}
 }
 
 // Validate checks if the configuration is valid.
 func (c *Config) Validate() error {
-	if c.Addr == "" {
+	if c.Namespace == "" {
+		return errors.New("namespace cannot be empty")
+	}
+	if len(c.ClusterAddrs) > 0 {
+		if c.Addr != "" {
+			return errors.New("addr and cluster addrs cannot both be set")
+		}
+		if len(c.SentinelAddrs) > 0 {
+			return errors.New("sentinel addrs and cluster addrs cannot both be set")
+		}
+		if c.DB != 0 {
+			return errors.New("db must be 0 with cluster addrs")
+		}
+		if c.ClusterMaxRedirects <= 0 {
+			return errors.New("cluster max redirects must be greater than 0")
+		}
+	} else if len(c.SentinelAddrs) == 0 && c.Addr == "" {
 		return errors.New("addr cannot be empty")
 	}
+	if len(c.SentinelAddrs) > 0 && c.MasterName == "" {
+		return errors.New("master name cannot be empty when sentinel addrs are set")
+	}
 	if c.DB < 0 {
 		return errors.New("db must be a non-negative integer")
 	}
 	if c.Protocol != 2 && c.Protocol != 3 {
 		return errors.New("protocol must be either 2 or 3")
 	}
+	if c.DialTimeoutInMS <= 0 {
+		return errors.New("dial timeout must be greater than 0")
+	}
+	if c.ReadTimeoutInMS <= 0 {
+		return errors.New("read timeout must be greater than 0")
+	}
+	if c.WriteTimeoutInMS <= 0 {
+		return errors.New("write timeout must be greater than 0")
+	}
+	if c.PoolSize <= 0 {
+		return errors.New("pool size must be greater than 0")
+	}
+	if c.MinIdleConns <= 0 {
+		return errors.New("min idle conns must be greater than 0")
+	}
+	if c.MinIdleConns > c.PoolSize {
+		return errors.New("min idle conns cannot be greater than pool size")
+	}
+	if c.PoolTimeoutInMS <= 0 {
+		return errors.New("pool timeout must be greater than 0")
+	}
 
 	return nil
 }
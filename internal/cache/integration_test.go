@@ -0,0 +1,136 @@
+package cache
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/testcontainers/testcontainers-go"
+	tcredis "github.com/testcontainers/testcontainers-go/modules/redis"
+)
+
+// integrationTestsEnv enables the tests that run against a Redis container, they need Docker
+const integrationTestsEnv = "INTEGRATION_TESTS_ENABLED"
+
+// IntegrationSuite runs the cache against a real Redis, the other cache tests use miniredis
+type IntegrationSuite struct {
+	suite.Suite
+	container *tcredis.RedisContainer
+	cache     *Cache
+}
+
+func TestIntegrationSuite(t *testing.T) {
+	if os.Getenv(integrationTestsEnv) != "true" {
+		t.Skipf("integration tests are disabled, set %s=true to run them", integrationTestsEnv)
+	}
+
+	suite.Run(t, new(IntegrationSuite))
+}
+
+func (suite *IntegrationSuite) SetupSuite() {
+	ctx := context.Background()
+
+	container, err := tcredis.Run(ctx, "redis:7")
+	suite.container = container
+	suite.Require().NoError(err)
+
+	endpoint, err := container.Endpoint(ctx, "")
+	suite.Require().NoError(err)
+
+	config := DefaultConfig()
+	config.Addr = endpoint
+	config.EnablePatternDelete = true
+	suite.Require().NoError(config.Validate())
+
+	suite.cache = NewCache(config).WithNamespace("urls")
+}
+
+func (suite *IntegrationSuite) TearDownSuite() {
+	if suite.cache != nil {
+		suite.NoError(suite.cache.Close())
+	}
+	suite.NoError(testcontainers.TerminateContainer(suite.container))
+}
+
+func (suite *IntegrationSuite) TearDownTest() {
+	suite.Require().NoError(suite.cache.DeleteByPattern(context.Background(), "*"))
+}
+
+func (suite *IntegrationSuite) TestHealthy() {
+	suite.True(suite.cache.Healthy())
+}
+
+func (suite *IntegrationSuite) TestSetGetAndDelete() {
+	ctx := context.Background()
+
+	suite.Require().NoError(suite.cache.Set(ctx, "AABBCC", "https://example.com", time.Minute))
+
+	value, found, err := suite.cache.Get(ctx, "AABBCC")
+	suite.Require().NoError(err)
+	suite.True(found)
+	suite.Equal("https://example.com", value)
+
+	suite.Require().NoError(suite.cache.Delete(ctx, "AABBCC"))
+	suite.Require().NoError(suite.cache.Delete(ctx, "AABBCC"))
+
+	_, found, err = suite.cache.Get(ctx, "AABBCC")
+	suite.Require().NoError(err)
+	suite.False(found)
+}
+
+func (suite *IntegrationSuite) TestSetExpires() {
+	ctx := context.Background()
+
+	suite.Require().NoError(suite.cache.Set(ctx, "AABBCC", "https://example.com", 100*time.Millisecond))
+
+	suite.Eventually(func() bool {
+		_, found, err := suite.cache.Get(ctx, "AABBCC")
+
+		return err == nil && !found
+	}, time.Second, 50*time.Millisecond)
+}
+
+func (suite *IntegrationSuite) TestGetMultiAndSetMulti() {
+	ctx := context.Background()
+
+	suite.Require().NoError(suite.cache.SetMulti(ctx, map[string]string{
+		"AABBCC": "https://example.com/a",
+		"DDEEFF": "https://example.com/d",
+	}, time.Minute))
+
+	values, err := suite.cache.GetMulti(ctx, []string{"AABBCC", "GGHHII", "DDEEFF"})
+	suite.Require().NoError(err)
+	suite.Equal(map[string]string{
+		"AABBCC": "https://example.com/a",
+		"DDEEFF": "https://example.com/d",
+	}, values)
+}
+
+func (suite *IntegrationSuite) TestGetHashAndSetHash() {
+	ctx := context.Background()
+
+	suite.Require().NoError(suite.cache.SetHash(ctx, "stats", map[string]string{"visits": "42"}, time.Minute))
+
+	fields, found, err := suite.cache.GetHash(ctx, "stats")
+	suite.Require().NoError(err)
+	suite.True(found)
+	suite.Equal(map[string]string{"visits": "42"}, fields)
+}
+
+func (suite *IntegrationSuite) TestDeleteByPattern() {
+	ctx := context.Background()
+
+	suite.Require().NoError(suite.cache.SetMulti(ctx, map[string]string{
+		"redirect:AABBCC": "https://example.com/a",
+		"redirect:DDEEFF": "https://example.com/d",
+		"stats:AABBCC":    "42",
+	}, time.Minute))
+
+	suite.Require().NoError(suite.cache.DeleteByPattern(ctx, "redirect:*"))
+
+	values, err := suite.cache.GetMulti(ctx, []string{"redirect:AABBCC", "redirect:DDEEFF", "stats:AABBCC"})
+	suite.Require().NoError(err)
+	suite.Equal(map[string]string{"stats:AABBCC": "42"}, values)
+}
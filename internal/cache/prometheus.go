@@ -0,0 +1,23 @@
+package cache
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var operationDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Name: "cache_operation_duration_seconds",
+	Help: "Duration of the cache operations in seconds",
+}, []string{"operation"})
+
+// observeDuration starts timing a cache operation, the returned function records its duration and is meant to be
+// deferred
+func observeDuration(operation string) func() {
+	start := time.Now()
+
+	return func() {
+		operationDuration.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+	}
+}
@@ -1,17 +1,25 @@
 package config
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
+	"os"
 
 	"github.com/AvalosM/short-url-service/internal/cache"
+	"github.com/AvalosM/short-url-service/internal/handlers"
 	"github.com/AvalosM/short-url-service/internal/router"
 	"github.com/AvalosM/short-url-service/internal/storage"
 	"github.com/AvalosM/short-url-service/pkg/metrics"
 	"github.com/AvalosM/short-url-service/pkg/shorturl"
+	"github.com/AvalosM/short-url-service/pkg/token"
+	"github.com/AvalosM/short-url-service/pkg/webhook"
 )
 
+// FileEnv is the environment variable with the path of the JSON configuration file
+const FileEnv = "SHORT_URL_SERVICE_CONFIG_FILE"
+
 // Config holds the configuration for the application
 type Config struct {
 	Logger          *LoggerConfig     `json:"logger"`
@@ -19,12 +27,21 @@ type Config struct {
 	Cache           *cache.Config     `json:"cache"`
 	ShortURLManager *shorturl.Config  `json:"short_url_manager"`
 	MetricsManager  *metrics.Config   `json:"metrics_manager"`
+	Webhook         *webhook.Config   `json:"webhook"`
+	Token           *token.Config     `json:"token"`
+	Handler         *handlers.Config  `json:"handler"`
 	Router          *router.Config    `json:"router"`
 	HTTPServer      *HTTPServerConfig `json:"http_server"`
+	GRPC            *GRPCConfig       `json:"grpc"`
+
+	// defaultTokenSecret is set when the token secret is the random default, which differs on every load
+	defaultTokenSecret bool
 }
 
 type LoggerConfig struct {
 	Level int `json:"level"`
+	// PackageLevels are the levels of the packages logging at a level other than Level, by package name
+	PackageLevels map[string]int `json:"package_levels,omitempty"`
 }
 
 // DefaultLoggerConfig returns a default logger configuration
@@ -36,11 +53,34 @@ func DefaultLoggerConfig() *LoggerConfig {
 
 // Validate checks if the logger configuration is valid
 func (c *LoggerConfig) Validate() error {
-	switch slog.Level(c.Level) {
+	if !validLogLevel(c.Level) {
+		return errors.New("invalid log level")
+	}
+	for pkg, level := range c.PackageLevels {
+		if !validLogLevel(level) {
+			return fmt.Errorf("invalid log level of package %s", pkg)
+		}
+	}
+
+	return nil
+}
+
+// SlogPackageLevels returns the package levels as slog levels
+func (c *LoggerConfig) SlogPackageLevels() map[string]slog.Level {
+	levels := make(map[string]slog.Level, len(c.PackageLevels))
+	for pkg, level := range c.PackageLevels {
+		levels[pkg] = slog.Level(level)
+	}
+
+	return levels
+}
+
+func validLogLevel(level int) bool {
+	switch slog.Level(level) {
 	case slog.LevelInfo, slog.LevelDebug, slog.LevelError, slog.LevelWarn:
-		return nil
+		return true
 	default:
-		return errors.New("invalid log level")
+		return false
 	}
 }
 
@@ -50,6 +90,16 @@ type HTTPServerConfig struct {
 	ReadTimeoutInMS  int `json:"read_timeout_in_ms"`
 	WriteTimeoutInMS int `json:"write_timeout_in_ms"`
 	IdleTimeoutInMS  int `json:"idle_timeout_in_ms"`
+	// TLSCertFile and TLSKeyFile are the PEM certificate and key files the server uses for TLS, both or none must be set
+	TLSCertFile string `json:"tls_cert_file"`
+	TLSKeyFile  string `json:"tls_key_file"`
+	// HTTP2Enabled serves HTTP/2, over TLS when it is enabled and over cleartext (h2c) otherwise
+	HTTP2Enabled bool `json:"http2_enabled"`
+}
+
+// TLSEnabled reports whether the server is configured to serve TLS
+func (c *HTTPServerConfig) TLSEnabled() bool {
+	return c.TLSCertFile != "" && c.TLSKeyFile != ""
 }
 
 // Validate checks if the HTTP server configuration is valid
@@ -66,6 +116,9 @@ func (c *HTTPServerConfig) Validate() error {
 	if c.IdleTimeoutInMS <= 0 {
 		return fmt.Errorf("invalid idle timeout: %d ms", c.IdleTimeoutInMS)
 	}
+	if (c.TLSCertFile == "") != (c.TLSKeyFile == "") {
+		return errors.New("TLS cert file and key file must be set together")
+	}
 
 	return nil
 }
@@ -80,6 +133,32 @@ func DefaultHTTPServerConfig() *HTTPServerConfig {
 	}
 }
 
+// GRPCConfig holds the configuration for the gRPC server
+type GRPCConfig struct {
+	// Enabled starts the gRPC server, its calls are authenticated like the private router ones when auth is enabled
+	Enabled bool `json:"enabled"`
+	Port    int  `json:"port"`
+}
+
+// Validate checks if the gRPC server configuration is valid
+func (c *GRPCConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.Port <= 0 || c.Port > 65535 {
+		return fmt.Errorf("invalid gRPC port: %d", c.Port)
+	}
+
+	return nil
+}
+
+// DefaultGRPCConfig returns a default gRPC server configuration
+func DefaultGRPCConfig() *GRPCConfig {
+	return &GRPCConfig{
+		Port: 9090,
+	}
+}
+
 // DefaultConfig returns a default configuration for the application
 func DefaultConfig() *Config {
 	return &Config{
@@ -88,9 +167,37 @@ func DefaultConfig() *Config {
 		Cache:           cache.DefaultConfig(),
 		ShortURLManager: shorturl.DefaultConfig(),
 		MetricsManager:  metrics.DefaultConfig(),
+		Webhook:         webhook.DefaultConfig(),
+		Token:           token.DefaultConfig(),
+		Handler:         handlers.DefaultConfig(),
 		Router:          router.DefaultConfig(),
 		HTTPServer:      DefaultHTTPServerConfig(),
+		GRPC:            DefaultGRPCConfig(),
+	}
+}
+
+// LoadFile returns the default configuration overridden by the JSON configuration file at path, sections and fields
+// missing from the file keep their defaults. The default configuration is returned when path is empty.
+func LoadFile(path string) (*Config, error) {
+	config := DefaultConfig()
+	config.defaultTokenSecret = true
+	if path == "" {
+		return config, nil
+	}
+	defaultSecret := config.Token.Secret
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening config file: %w", err)
 	}
+	defer file.Close()
+
+	if err := json.NewDecoder(file).Decode(config); err != nil {
+		return nil, fmt.Errorf("decoding config file: %w", err)
+	}
+	config.defaultTokenSecret = config.Token.Secret == defaultSecret
+
+	return config, nil
 }
 
 // Validate checks if the configuration is valid
@@ -110,12 +217,27 @@ func (c *Config) Validate() error {
 	if err := c.MetricsManager.Validate(); err != nil {
 		return err
 	}
+	if err := c.Webhook.Validate(); err != nil {
+		return err
+	}
+	if err := c.Token.Validate(); err != nil {
+		return err
+	}
+	if err := c.Handler.Validate(); err != nil {
+		return err
+	}
 	if err := c.Router.Validate(); err != nil {
 		return err
 	}
 	if err := c.HTTPServer.Validate(); err != nil {
 		return err
 	}
+	if err := c.GRPC.Validate(); err != nil {
+		return err
+	}
+	if c.GRPC.Port == c.HTTPServer.Port {
+		return errors.New("gRPC and HTTP servers cannot share a port")
+	}
 
 	return nil
 }
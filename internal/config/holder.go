@@ -0,0 +1,150 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"sync/atomic"
+
+	"github.com/AvalosM/short-url-service/pkg/logging"
+)
+
+// loggerSection is the only section of the configuration that supports hot reload
+const loggerSection = "logger"
+
+// Logger ...
+type Logger interface {
+	Error(msg string, args ...interface{})
+	Info(msg string, args ...interface{})
+	Debug(msg string, args ...interface{})
+	Warn(msg string, args ...interface{})
+}
+
+// Holder holds the running configuration. Reload applies the sections of a freshly loaded configuration that support
+// hot reload, the log levels, changes to the others only take effect on restart.
+type Holder struct {
+	config atomic.Pointer[Config]
+	// reloadMu keeps concurrent reloads from overwriting each other
+	reloadMu      sync.Mutex
+	load          func() (*Config, error)
+	level         *slog.LevelVar
+	packageLevels *logging.PackageLevels
+	logger        Logger
+}
+
+// NewHolder creates a Holder of the given running configuration, load returns the configuration to reload and level
+// and packageLevels are the levels of the running logger
+func NewHolder(config *Config, load func() (*Config, error), level *slog.LevelVar, packageLevels *logging.PackageLevels,
+	logger Logger) (*Holder, error) {
+	if config == nil {
+		return nil, errors.New("config cannot be nil")
+	}
+	if load == nil {
+		return nil, errors.New("load cannot be nil")
+	}
+	if level == nil {
+		return nil, errors.New("level cannot be nil")
+	}
+	if packageLevels == nil {
+		return nil, errors.New("packageLevels cannot be nil")
+	}
+	if logger == nil {
+		return nil, errors.New("logger cannot be nil")
+	}
+
+	holder := &Holder{
+		load:          load,
+		level:         level,
+		packageLevels: packageLevels,
+		logger:        logger,
+	}
+	holder.config.Store(config)
+
+	return holder, nil
+}
+
+// Config returns the running configuration, it must not be modified
+func (h *Holder) Config() *Config {
+	return h.config.Load()
+}
+
+// Reload loads the configuration again and applies the sections that support hot reload. It returns the names of the
+// sections reloaded and of the changed sections ignored because they need a restart. The running configuration is
+// kept as it is if the loaded one is invalid.
+func (h *Holder) Reload() ([]string, []string, error) {
+	h.reloadMu.Lock()
+	defer h.reloadMu.Unlock()
+
+	next, err := h.load()
+	if err != nil {
+		return nil, nil, fmt.Errorf("loading config: %w", err)
+	}
+	if err := next.Validate(); err != nil {
+		return nil, nil, fmt.Errorf("validating config: %w", err)
+	}
+
+	current := h.config.Load()
+	// A token secret left to its random default is not a change, the running one stays in use
+	if next.defaultTokenSecret && current.defaultTokenSecret {
+		nextToken := *next.Token
+		nextToken.Secret = current.Token.Secret
+		next.Token = &nextToken
+	}
+
+	currentSections, err := sections(current)
+	if err != nil {
+		return nil, nil, err
+	}
+	nextSections, err := sections(next)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var reloaded, ignored []string
+	for name, section := range nextSections {
+		if string(section) == string(currentSections[name]) {
+			continue
+		}
+
+		if name == loggerSection {
+			reloaded = append(reloaded, name)
+
+			continue
+		}
+
+		h.logger.Warn("config section does not support hot reload, restart to apply it", logging.SectionKey, name)
+		ignored = append(ignored, name)
+	}
+	slices.Sort(ignored)
+
+	if len(reloaded) > 0 {
+		updated := *current
+		updated.Logger = next.Logger
+		h.config.Store(&updated)
+		h.level.Set(slog.Level(next.Logger.Level))
+		h.packageLevels.Set(next.Logger.SlogPackageLevels())
+
+		h.logger.Info("config reloaded", logging.SectionKey, loggerSection)
+	}
+
+	return reloaded, ignored, nil
+}
+
+// sections returns the JSON encoding of every section of the configuration by name, fields derived at startup are
+// not encoded so they never count as changes
+func sections(config *Config) (map[string]json.RawMessage, error) {
+	encoded, err := json.Marshal(config)
+	if err != nil {
+		return nil, fmt.Errorf("encoding config: %w", err)
+	}
+
+	var sections map[string]json.RawMessage
+	if err := json.Unmarshal(encoded, &sections); err != nil {
+		return nil, fmt.Errorf("decoding config sections: %w", err)
+	}
+
+	return sections, nil
+}
@@ -0,0 +1,122 @@
+package config_test
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/AvalosM/short-url-service/internal/config"
+	"github.com/AvalosM/short-url-service/pkg/logging"
+)
+
+type HolderSuite struct {
+	suite.Suite
+	configFile    string
+	level         *slog.LevelVar
+	packageLevels *logging.PackageLevels
+	logs          *bytes.Buffer
+	logger        *slog.Logger
+	holder        *config.Holder
+}
+
+func (suite *HolderSuite) SetupTest() {
+	suite.configFile = filepath.Join(suite.T().TempDir(), "config.json")
+	suite.writeConfigFile(`{"logger": {"level": 0}}`)
+
+	cfg, err := config.LoadFile(suite.configFile)
+	suite.Require().NoError(err)
+
+	suite.level = &slog.LevelVar{}
+	suite.level.Set(slog.Level(cfg.Logger.Level))
+	suite.packageLevels = logging.NewPackageLevels(cfg.Logger.SlogPackageLevels())
+	suite.logs = &bytes.Buffer{}
+	suite.logger = slog.New(logging.NewPackageLevelHandler(slog.NewTextHandler(suite.logs, &slog.HandlerOptions{Level: slog.LevelDebug}),
+		suite.level, suite.packageLevels))
+
+	suite.holder, err = config.NewHolder(cfg, func() (*config.Config, error) {
+		return config.LoadFile(suite.configFile)
+	}, suite.level, suite.packageLevels, suite.logger)
+	suite.Require().NoError(err)
+}
+
+func TestHolderSuite(t *testing.T) {
+	suite.Run(t, new(HolderSuite))
+}
+
+func (suite *HolderSuite) writeConfigFile(content string) {
+	err := os.WriteFile(suite.configFile, []byte(content), 0o600)
+	suite.Require().NoError(err)
+}
+
+func (suite *HolderSuite) TestReloadLogLevel() {
+	suite.logger.Debug("before reload")
+	suite.NotContains(suite.logs.String(), "before reload")
+
+	suite.writeConfigFile(`{"logger": {"level": -4}}`)
+	reloaded, ignored, err := suite.holder.Reload()
+	suite.Require().NoError(err)
+	suite.Equal([]string{"logger"}, reloaded)
+	suite.Empty(ignored)
+	suite.Equal(-4, suite.holder.Config().Logger.Level)
+
+	suite.logger.Debug("after reload")
+	suite.Contains(suite.logs.String(), "after reload")
+}
+
+func (suite *HolderSuite) TestReloadPackageLevels() {
+	metricsLogger := logging.NewPackageLogger(suite.logger, "metrics")
+
+	suite.writeConfigFile(`{"logger": {"level": 0, "package_levels": {"metrics": 8}}}`)
+	reloaded, _, err := suite.holder.Reload()
+	suite.Require().NoError(err)
+	suite.Equal([]string{"logger"}, reloaded)
+	suite.Equal(map[string]int{"metrics": 8}, suite.holder.Config().Logger.PackageLevels)
+
+	metricsLogger.Warn("metrics warn")
+	suite.logger.Warn("default warn")
+	suite.NotContains(suite.logs.String(), "metrics warn")
+	suite.Contains(suite.logs.String(), "default warn")
+}
+
+func (suite *HolderSuite) TestReloadIgnoresSectionsNeedingRestart() {
+	suite.writeConfigFile(`{"logger": {"level": 0}, "http_server": {"port": 8081}, "grpc": {"port": 9091}}`)
+
+	reloaded, ignored, err := suite.holder.Reload()
+	suite.Require().NoError(err)
+	suite.Empty(reloaded)
+	suite.Equal([]string{"grpc", "http_server"}, ignored)
+	suite.Equal(8080, suite.holder.Config().HTTPServer.Port)
+	suite.Contains(suite.logs.String(), "section=http_server")
+}
+
+func (suite *HolderSuite) TestReloadFailKeepsRunningConfig() {
+	testCases := []struct {
+		name    string
+		content string
+	}{
+		{name: "invalid config", content: `{"logger": {"level": 3}}`},
+		{name: "invalid package level", content: `{"logger": {"level": 0, "package_levels": {"metrics": 3}}}`},
+		{name: "malformed file", content: `{"logger":`},
+	}
+
+	for _, testCase := range testCases {
+		suite.Run(testCase.name, func() {
+			suite.writeConfigFile(testCase.content)
+
+			_, _, err := suite.holder.Reload()
+			suite.Error(err)
+			suite.Equal(0, suite.holder.Config().Logger.Level)
+			suite.Equal(slog.LevelInfo, suite.level.Level())
+		})
+	}
+}
+
+func (suite *HolderSuite) TestLoadFileMissing() {
+	_, err := config.LoadFile(filepath.Join(suite.T().TempDir(), "missing.json"))
+	suite.True(errors.Is(err, os.ErrNotExist))
+}
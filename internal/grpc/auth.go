@@ -0,0 +1,34 @@
+package grpc
+
+import (
+	"context"
+
+	googlegrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/AvalosM/short-url-service/internal/middleware"
+)
+
+// authorizationMetadataKey is the metadata key of the bearer token, the gRPC counterpart of the Authorization header
+const authorizationMetadataKey = "authorization"
+
+// AuthInterceptor authenticates unary calls with the bearer token of their authorization metadata like the HTTP Auth
+// middleware does, and scopes them to the tenant and user of the token. Calls without a valid token are rejected
+// with Unauthenticated.
+func AuthInterceptor(authenticator *middleware.TokenAuthenticator) googlegrpc.UnaryServerInterceptor {
+	return func(ctx context.Context, request any, _ *googlegrpc.UnaryServerInfo, handler googlegrpc.UnaryHandler) (any, error) {
+		var authorization string
+		if values := metadata.ValueFromIncomingContext(ctx, authorizationMetadataKey); len(values) > 0 {
+			authorization = values[0]
+		}
+
+		ctx, err := authenticator.Authenticate(ctx, authorization)
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		}
+
+		return handler(ctx, request)
+	}
+}
@@ -0,0 +1,124 @@
+package grpc_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	googlegrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/AvalosM/short-url-service/internal/grpc"
+	"github.com/AvalosM/short-url-service/internal/handlers/mocks"
+	"github.com/AvalosM/short-url-service/internal/middleware"
+	"github.com/AvalosM/short-url-service/pkg/tenant"
+	shorturlv1 "github.com/AvalosM/short-url-service/proto/shorturl/v1"
+)
+
+type AuthInterceptorSuite struct {
+	suite.Suite
+	secret              []byte
+	mockCtrl            *gomock.Controller
+	mockShortURLManager *mocks.MockShortURLManager
+	grpcServer          *googlegrpc.Server
+	conn                *googlegrpc.ClientConn
+	client              shorturlv1.ShortURLServiceClient
+}
+
+func (suite *AuthInterceptorSuite) SetupTest() {
+	suite.secret = []byte("0123456789abcdef0123456789abcdef")
+	suite.mockCtrl = gomock.NewController(suite.T())
+	suite.mockShortURLManager = mocks.NewMockShortURLManager(suite.mockCtrl)
+
+	server, err := grpc.NewServer(suite.mockShortURLManager, mocks.NewMockMetricsManager(suite.mockCtrl), mocks.NewMockLogger(suite.mockCtrl))
+	suite.Require().NoError(err)
+
+	listener := bufconn.Listen(bufSize)
+	suite.grpcServer = googlegrpc.NewServer(googlegrpc.UnaryInterceptor(grpc.AuthInterceptor(middleware.NewTokenAuthenticator(suite.secret))))
+	server.Register(suite.grpcServer)
+	go func() {
+		_ = suite.grpcServer.Serve(listener)
+	}()
+
+	suite.conn, err = googlegrpc.NewClient("passthrough:///bufnet",
+		googlegrpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		googlegrpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	suite.Require().NoError(err)
+
+	suite.client = shorturlv1.NewShortURLServiceClient(suite.conn)
+}
+
+func (suite *AuthInterceptorSuite) TearDownTest() {
+	_ = suite.conn.Close()
+	suite.grpcServer.Stop()
+	suite.mockCtrl.Finish()
+}
+
+func TestAuthInterceptorSuite(t *testing.T) {
+	suite.Run(t, new(AuthInterceptorSuite))
+}
+
+func (suite *AuthInterceptorSuite) token(tenantID string, secret []byte) string {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, &middleware.TenantClaims{
+		TenantId:         tenantID,
+		RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour))},
+	}).SignedString(secret)
+	suite.Require().NoError(err)
+
+	return token
+}
+
+func (suite *AuthInterceptorSuite) TestAuthenticated() {
+	suite.mockShortURLManager.EXPECT().DeleteShortURL(gomock.Any(), "AABBCC").
+		DoAndReturn(func(ctx context.Context, _ string) error {
+			suite.Equal("acme", tenant.IDFromContext(ctx))
+
+			return nil
+		})
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+suite.token("acme", suite.secret))
+	_, err := suite.client.DeleteShortURL(ctx, &shorturlv1.DeleteShortURLRequest{Id: "AABBCC"})
+	suite.NoError(err)
+}
+
+func (suite *AuthInterceptorSuite) TestUnauthenticated() {
+	testCases := []struct {
+		name          string
+		authorization string
+	}{
+		{
+			name: "missing token",
+		},
+		{
+			name:          "invalid signature",
+			authorization: "Bearer " + suite.token("acme", []byte("another secret of thirty-two bytes")),
+		},
+		{
+			name:          "missing tenant",
+			authorization: "Bearer " + suite.token("", suite.secret),
+		},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			ctx := context.Background()
+			if tc.authorization != "" {
+				ctx = metadata.AppendToOutgoingContext(ctx, "authorization", tc.authorization)
+			}
+
+			_, err := suite.client.DeleteShortURL(ctx, &shorturlv1.DeleteShortURLRequest{Id: "AABBCC"})
+			suite.Equal(codes.Unauthenticated, status.Code(err))
+		})
+	}
+}
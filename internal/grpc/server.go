@@ -0,0 +1,199 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	googlegrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/AvalosM/short-url-service/internal/handlers"
+	"github.com/AvalosM/short-url-service/pkg/logging"
+	"github.com/AvalosM/short-url-service/pkg/shorturl"
+	shorturlv1 "github.com/AvalosM/short-url-service/proto/shorturl/v1"
+)
+
+// actorMetadataKey is the metadata key identifying who makes a request, the gRPC counterpart of handlers.ActorHeader
+const actorMetadataKey = "x-actor"
+
+// validationErrors are the errors of the short URL manager rejecting the arguments of a call
+var validationErrors = []error{
+	shorturl.ErrInvalidLongURL,
+	shorturl.ErrURLTooLong,
+	shorturl.ErrInvalidTags,
+	shorturl.ErrInvalidDescription,
+	shorturl.ErrInvalidMaxClicks,
+	shorturl.ErrInvalidPassword,
+	shorturl.ErrInvalidRedirectCode,
+	shorturl.ErrInvalidCacheTTL,
+	shorturl.ErrInvalidExpiresAt,
+	shorturl.ErrInvalidAliasId,
+	shorturl.ErrSlugTooLong,
+	shorturl.ErrSlugInvalidChars,
+}
+
+// Logger ...
+type Logger interface {
+	Error(msg string, args ...interface{})
+	Info(msg string, args ...interface{})
+	Debug(msg string, args ...interface{})
+	Warn(msg string, args ...interface{})
+}
+
+// Server serves the ShortURLService gRPC API with the same managers as the HTTP handlers
+type Server struct {
+	shorturlv1.UnimplementedShortURLServiceServer
+	shortURLManager handlers.ShortURLManager
+	metricsManager  handlers.MetricsManager
+	logger          Logger
+}
+
+// NewServer creates a new Server
+func NewServer(shortURLManager handlers.ShortURLManager, metricsManager handlers.MetricsManager, logger Logger) (*Server, error) {
+	if shortURLManager == nil {
+		return nil, errors.New("short URL manager cannot be nil")
+	}
+	if metricsManager == nil {
+		return nil, errors.New("metrics manager cannot be nil")
+	}
+	if logger == nil {
+		return nil, errors.New("logger cannot be nil")
+	}
+
+	return &Server{
+		shortURLManager: shortURLManager,
+		metricsManager:  metricsManager,
+		logger:          logger,
+	}, nil
+}
+
+// Register registers the ShortURLService on the given gRPC server
+func (s *Server) Register(registrar googlegrpc.ServiceRegistrar) {
+	shorturlv1.RegisterShortURLServiceServer(registrar, s)
+}
+
+// CreateShortURL creates a short URL for the given long URL
+func (s *Server) CreateShortURL(ctx context.Context, request *shorturlv1.CreateShortURLRequest) (*shorturlv1.CreateShortURLResponse, error) {
+	shortURL, err := s.shortURLManager.CreateShortURL(actorContext(ctx), request.GetLongUrl(), &shorturl.CreateOptions{
+		Tags:      request.GetTags(),
+		MaxClicks: int(request.GetMaxClicks()),
+		Password:  request.GetPassword(),
+	})
+	if err != nil {
+		switch {
+		case invalidArgument(err):
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		case errors.Is(err, shorturl.ErrURLDenied):
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		default:
+			return nil, status.Error(codes.Internal, "failed to create short URL")
+		}
+	}
+
+	return &shorturlv1.CreateShortURLResponse{ShortUrl: newShortURL(shortURL)}, nil
+}
+
+// GetLongURL returns the long URL of a short URL, unlike following the short URL it is not counted as a click. The
+// long URL of a password protected short URL is not returned, PermissionDenied is.
+func (s *Server) GetLongURL(ctx context.Context, request *shorturlv1.GetLongURLRequest) (*shorturlv1.GetLongURLResponse, error) {
+	if request.GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "short URL id is required")
+	}
+
+	shortURL, err := s.shortURLManager.GetShortURL(ctx, request.GetId())
+	if err != nil {
+		switch {
+		case errors.Is(err, shorturl.ErrShortURLNotFound):
+			return nil, status.Error(codes.NotFound, err.Error())
+		default:
+			return nil, status.Error(codes.Internal, "failed to retrieve long URL")
+		}
+	}
+	if shortURL.Protected() {
+		return nil, status.Error(codes.PermissionDenied, shorturl.ErrPasswordRequired.Error())
+	}
+
+	return &shorturlv1.GetLongURLResponse{LongUrl: shortURL.LongURL}, nil
+}
+
+// DeleteShortURL deletes a short URL
+func (s *Server) DeleteShortURL(ctx context.Context, request *shorturlv1.DeleteShortURLRequest) (*shorturlv1.DeleteShortURLResponse, error) {
+	if request.GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "short URL id is required")
+	}
+
+	if err := s.shortURLManager.DeleteShortURL(actorContext(ctx), request.GetId()); err != nil {
+		return nil, status.Error(codes.Internal, "failed to delete short URL")
+	}
+
+	return &shorturlv1.DeleteShortURLResponse{}, nil
+}
+
+// GetMetrics returns the metrics of a short URL within a time range
+func (s *Server) GetMetrics(ctx context.Context, request *shorturlv1.GetMetricsRequest) (*shorturlv1.GetMetricsResponse, error) {
+	if request.GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "short URL id is required")
+	}
+	if err := request.GetFrom().CheckValid(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid from")
+	}
+	if err := request.GetTo().CheckValid(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid to")
+	}
+
+	if _, err := s.shortURLManager.GetShortURL(ctx, request.GetId()); err != nil {
+		switch {
+		case errors.Is(err, shorturl.ErrShortURLNotFound):
+			return nil, status.Error(codes.NotFound, err.Error())
+		default:
+			return nil, status.Error(codes.Internal, "failed to retrieve short URL")
+		}
+	}
+
+	metricsResult, err := s.metricsManager.GetShortURLMetrics(ctx, request.GetId(), request.GetFrom().AsTime(), request.GetTo().AsTime())
+	if err != nil {
+		s.logger.Error("failed to retrieve metrics", logging.ShortURLIdKey, request.GetId(), logging.ErrorKey, err)
+
+		return nil, status.Error(codes.Internal, "failed to retrieve metrics")
+	}
+
+	return &shorturlv1.GetMetricsResponse{
+		Id:           request.GetId(),
+		Visits:       metricsResult.Visits,
+		UniqueVisits: metricsResult.UniqueVisits,
+		From:         timestamppb.New(metricsResult.From),
+		To:           timestamppb.New(metricsResult.To),
+	}, nil
+}
+
+// invalidArgument reports whether err is one of the validationErrors
+func invalidArgument(err error) bool {
+	return slices.ContainsFunc(validationErrors, func(target error) bool {
+		return errors.Is(err, target)
+	})
+}
+
+// actorContext returns ctx carrying the actor of the request, taken from the actorMetadataKey metadata
+func actorContext(ctx context.Context) context.Context {
+	if values := metadata.ValueFromIncomingContext(ctx, actorMetadataKey); len(values) > 0 {
+		return shorturl.WithActor(ctx, values[0])
+	}
+
+	return ctx
+}
+
+func newShortURL(shortURL *shorturl.ShortURL) *shorturlv1.ShortURL {
+	return &shorturlv1.ShortURL{
+		Id:         shortURL.Id,
+		LongUrl:    shortURL.LongURL,
+		Tags:       shortURL.Tags,
+		MaxClicks:  int64(shortURL.MaxClicks),
+		ClickCount: int64(shortURL.ClickCount),
+		Protected:  shortURL.Protected(),
+		CreatedAt:  timestamppb.New(shortURL.CreatedAt),
+	}
+}
@@ -0,0 +1,208 @@
+package grpc_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	googlegrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/AvalosM/short-url-service/internal/grpc"
+	"github.com/AvalosM/short-url-service/internal/handlers/mocks"
+	"github.com/AvalosM/short-url-service/pkg/metrics"
+	"github.com/AvalosM/short-url-service/pkg/shorturl"
+	shorturlv1 "github.com/AvalosM/short-url-service/proto/shorturl/v1"
+)
+
+const bufSize = 1024 * 1024
+
+type ServerSuite struct {
+	suite.Suite
+	mockCtrl            *gomock.Controller
+	mockShortURLManager *mocks.MockShortURLManager
+	mockMetricsManager  *mocks.MockMetricsManager
+	mockLogger          *mocks.MockLogger
+	grpcServer          *googlegrpc.Server
+	conn                *googlegrpc.ClientConn
+	client              shorturlv1.ShortURLServiceClient
+}
+
+func (suite *ServerSuite) SetupTest() {
+	suite.mockCtrl = gomock.NewController(suite.T())
+	suite.mockShortURLManager = mocks.NewMockShortURLManager(suite.mockCtrl)
+	suite.mockMetricsManager = mocks.NewMockMetricsManager(suite.mockCtrl)
+	suite.mockLogger = mocks.NewMockLogger(suite.mockCtrl)
+
+	suite.mockLogger.EXPECT().Error(gomock.Any(), gomock.Any()).AnyTimes()
+
+	server, err := grpc.NewServer(suite.mockShortURLManager, suite.mockMetricsManager, suite.mockLogger)
+	suite.Require().NoError(err)
+
+	listener := bufconn.Listen(bufSize)
+	suite.grpcServer = googlegrpc.NewServer()
+	server.Register(suite.grpcServer)
+	go func() {
+		_ = suite.grpcServer.Serve(listener)
+	}()
+
+	suite.conn, err = googlegrpc.NewClient("passthrough:///bufnet",
+		googlegrpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		googlegrpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	suite.Require().NoError(err)
+
+	suite.client = shorturlv1.NewShortURLServiceClient(suite.conn)
+}
+
+func (suite *ServerSuite) TearDownTest() {
+	_ = suite.conn.Close()
+	suite.grpcServer.Stop()
+	suite.mockCtrl.Finish()
+}
+
+func TestServerSuite(t *testing.T) {
+	suite.Run(t, new(ServerSuite))
+}
+
+func (suite *ServerSuite) TestCreateShortURLSuccess() {
+	createdAt := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	tags := []string{"campaign:summer2025"}
+
+	suite.mockShortURLManager.EXPECT().CreateShortURL(gomock.Any(), "https://example.com", &shorturl.CreateOptions{Tags: tags, MaxClicks: 10}).
+		DoAndReturn(func(ctx context.Context, longURL string, _ *shorturl.CreateOptions) (*shorturl.ShortURL, error) {
+			suite.Equal("admin", shorturl.ActorFromContext(ctx))
+
+			return &shorturl.ShortURL{Id: "AABBCC", LongURL: longURL, Tags: tags, MaxClicks: 10, CreatedAt: createdAt}, nil
+		})
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-actor", "admin")
+	response, err := suite.client.CreateShortURL(ctx, &shorturlv1.CreateShortURLRequest{
+		LongUrl:   "https://example.com",
+		Tags:      tags,
+		MaxClicks: 10,
+	})
+	suite.Require().NoError(err)
+	suite.Equal("AABBCC", response.GetShortUrl().GetId())
+	suite.Equal("https://example.com", response.GetShortUrl().GetLongUrl())
+	suite.Equal(tags, response.GetShortUrl().GetTags())
+	suite.Equal(int64(10), response.GetShortUrl().GetMaxClicks())
+	suite.False(response.GetShortUrl().GetProtected())
+	suite.True(createdAt.Equal(response.GetShortUrl().GetCreatedAt().AsTime()))
+}
+
+func (suite *ServerSuite) TestCreateShortURLFailInvalidArgument() {
+	testCases := []struct {
+		name string
+		err  error
+	}{
+		{name: "invalid long URL", err: shorturl.ErrInvalidLongURL},
+		{name: "long URL too long", err: shorturl.ErrURLTooLong},
+		{name: "invalid tags", err: fmt.Errorf("%w: too many tags", shorturl.ErrInvalidTags)},
+		{name: "invalid description", err: shorturl.ErrInvalidDescription},
+		{name: "invalid max clicks", err: shorturl.ErrInvalidMaxClicks},
+		{name: "invalid password", err: shorturl.ErrInvalidPassword},
+		{name: "invalid redirect code", err: shorturl.ErrInvalidRedirectCode},
+		{name: "invalid cache TTL", err: shorturl.ErrInvalidCacheTTL},
+		{name: "invalid expiration time", err: shorturl.ErrInvalidExpiresAt},
+		{name: "invalid alias id", err: shorturl.ErrInvalidAliasId},
+		{name: "alias id too long", err: shorturl.ErrSlugTooLong},
+		{name: "alias id with invalid characters", err: shorturl.ErrSlugInvalidChars},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			suite.mockShortURLManager.EXPECT().CreateShortURL(gomock.Any(), "https://example.com", gomock.Any()).Return(nil, tc.err)
+
+			_, err := suite.client.CreateShortURL(context.Background(), &shorturlv1.CreateShortURLRequest{LongUrl: "https://example.com"})
+			suite.Equal(codes.InvalidArgument, status.Code(err))
+		})
+	}
+}
+
+func (suite *ServerSuite) TestCreateShortURLFailInternal() {
+	suite.mockShortURLManager.EXPECT().CreateShortURL(gomock.Any(), "https://example.com", gomock.Any()).Return(nil, errors.New("storage error"))
+
+	_, err := suite.client.CreateShortURL(context.Background(), &shorturlv1.CreateShortURLRequest{LongUrl: "https://example.com"})
+	suite.Equal(codes.Internal, status.Code(err))
+}
+
+func (suite *ServerSuite) TestCreateShortURLFailDenied() {
+	suite.mockShortURLManager.EXPECT().CreateShortURL(gomock.Any(), "https://evil.example", gomock.Any()).Return(nil, shorturl.ErrURLDenied)
+
+	_, err := suite.client.CreateShortURL(context.Background(), &shorturlv1.CreateShortURLRequest{LongUrl: "https://evil.example"})
+	suite.Equal(codes.PermissionDenied, status.Code(err))
+}
+
+func (suite *ServerSuite) TestGetLongURLSuccess() {
+	suite.mockShortURLManager.EXPECT().GetShortURL(gomock.Any(), "AABBCC").
+		Return(&shorturl.ShortURL{Id: "AABBCC", LongURL: "https://example.com"}, nil)
+
+	response, err := suite.client.GetLongURL(context.Background(), &shorturlv1.GetLongURLRequest{Id: "AABBCC"})
+	suite.Require().NoError(err)
+	suite.Equal("https://example.com", response.GetLongUrl())
+}
+
+func (suite *ServerSuite) TestGetLongURLFailNotFound() {
+	suite.mockShortURLManager.EXPECT().GetShortURL(gomock.Any(), "AABBCC").Return(nil, shorturl.ErrShortURLNotFound)
+
+	_, err := suite.client.GetLongURL(context.Background(), &shorturlv1.GetLongURLRequest{Id: "AABBCC"})
+	suite.Equal(codes.NotFound, status.Code(err))
+}
+
+func (suite *ServerSuite) TestGetLongURLFailProtected() {
+	suite.mockShortURLManager.EXPECT().GetShortURL(gomock.Any(), "AABBCC").
+		Return(&shorturl.ShortURL{Id: "AABBCC", LongURL: "https://example.com", PasswordHash: "hash"}, nil)
+
+	response, err := suite.client.GetLongURL(context.Background(), &shorturlv1.GetLongURLRequest{Id: "AABBCC"})
+	suite.Equal(codes.PermissionDenied, status.Code(err))
+	suite.Empty(response.GetLongUrl())
+}
+
+func (suite *ServerSuite) TestGetLongURLFailMissingId() {
+	_, err := suite.client.GetLongURL(context.Background(), &shorturlv1.GetLongURLRequest{})
+	suite.Equal(codes.InvalidArgument, status.Code(err))
+}
+
+func (suite *ServerSuite) TestDeleteShortURLSuccess() {
+	suite.mockShortURLManager.EXPECT().DeleteShortURL(gomock.Any(), "AABBCC").Return(nil)
+
+	_, err := suite.client.DeleteShortURL(context.Background(), &shorturlv1.DeleteShortURLRequest{Id: "AABBCC"})
+	suite.NoError(err)
+}
+
+func (suite *ServerSuite) TestGetMetricsSuccess() {
+	from := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)
+
+	suite.mockShortURLManager.EXPECT().GetShortURL(gomock.Any(), "AABBCC").
+		Return(&shorturl.ShortURL{Id: "AABBCC", LongURL: "https://example.com"}, nil)
+	suite.mockMetricsManager.EXPECT().GetShortURLMetrics(gomock.Any(), "AABBCC", from, to).
+		Return(&metrics.Metrics{ShortURLId: "AABBCC", Visits: 3, UniqueVisits: 2, From: from, To: to}, nil)
+
+	response, err := suite.client.GetMetrics(context.Background(), &shorturlv1.GetMetricsRequest{
+		Id:   "AABBCC",
+		From: timestamppb.New(from),
+		To:   timestamppb.New(to),
+	})
+	suite.Require().NoError(err)
+	suite.Equal(int64(3), response.GetVisits())
+	suite.Equal(int64(2), response.GetUniqueVisits())
+}
+
+func (suite *ServerSuite) TestGetMetricsFailMissingRange() {
+	_, err := suite.client.GetMetrics(context.Background(), &shorturlv1.GetMetricsRequest{Id: "AABBCC"})
+	suite.Equal(codes.InvalidArgument, status.Code(err))
+}
@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/AvalosM/short-url-service/pkg/logging"
+)
+
+// maxWarmCacheIds is the number of short URLs a single warm cache request can ask for
+const maxWarmCacheIds = 10000
+
+// AdminHandler handles the http requests operating the service itself
+type AdminHandler struct {
+	configReloader ConfigReloader
+	shortURLStats  ShortURLStats
+	cacheWarmer    CacheWarmer
+	logger         Logger
+}
+
+// NewAdminHandler creates a new AdminHandler
+func NewAdminHandler(configReloader ConfigReloader, shortURLStats ShortURLStats, cacheWarmer CacheWarmer, logger Logger) (*AdminHandler, error) {
+	if configReloader == nil {
+		return nil, errors.New("config reloader cannot be nil")
+	}
+	if shortURLStats == nil {
+		return nil, errors.New("short URL stats cannot be nil")
+	}
+	if cacheWarmer == nil {
+		return nil, errors.New("cache warmer cannot be nil")
+	}
+	if logger == nil {
+		return nil, errors.New("logger cannot be nil")
+	}
+
+	return &AdminHandler{
+		configReloader: configReloader,
+		shortURLStats:  shortURLStats,
+		cacheWarmer:    cacheWarmer,
+		logger:         logger,
+	}, nil
+}
+
+// ReloadConfig godoc
+//
+//	@Summary      Reload the configuration
+//	@Description  Reload the configuration and apply the sections that support hot reload, the log level. Changes to
+//	@Description  other sections are ignored until restart.
+//	@Tags         admin, private
+//	@Produce      json
+//	@Success      200 {object} ConfigReloadResponse "Sections reloaded and ignored"
+//	@Failure      403 {string} string "Admin role required"
+//	@Failure      500 {string} string "Internal server error"
+//	@Router       /private/v1/admin/config/reload [post]
+func (h *AdminHandler) ReloadConfig(w http.ResponseWriter, r *http.Request) {
+	reloaded, ignored, err := h.configReloader.Reload()
+	if err != nil {
+		h.logger.Error("failed to reload config", logging.ErrorKey, err)
+		http.Error(w, "failed to reload config", http.StatusInternalServerError)
+
+		return
+	}
+
+	writeJSON(w, h.logger, http.StatusOK, NewConfigReloadResponse(reloaded, ignored))
+}
+
+// GetStats godoc
+//
+//	@Summary      Get the short URL manager stats
+//	@Description  Get the counters of the short URL manager since it started or its stats were last reset, and the
+//	@Description  rate of id collisions per short URL created
+//	@Tags         admin, private
+//	@Produce      json
+//	@Success      200 {object} ManagerStatsResponse "Short URL manager stats"
+//	@Failure      403 {string} string "Admin role required"
+//	@Router       /private/v1/admin/stats [get]
+//	@Router       /private/v1/admin/shorturl/stats [get]
+func (h *AdminHandler) GetStats(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, h.logger, http.StatusOK, NewManagerStatsResponse(h.shortURLStats.Stats()))
+}
+
+// ResetStats godoc
+//
+//	@Summary      Reset the short URL manager stats
+//	@Description  Set every counter of the short URL manager back to zero
+//	@Tags         admin, private
+//	@Success      204 {string} string "Stats reset"
+//	@Failure      403 {string} string "Admin role required"
+//	@Router       /private/v1/admin/stats/reset [post]
+func (h *AdminHandler) ResetStats(w http.ResponseWriter, _ *http.Request) {
+	h.shortURLStats.ResetStats()
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// WarmCache godoc
+//
+//	@Summary      Warm the cache
+//	@Description  Cache the redirects of the given short URLs so their first clicks are served from the cache. Short
+//	@Description  URLs not found or never cached, like inactive or password protected ones, are skipped.
+//	@Tags         admin, private
+//	@Accept       json
+//	@Param        request body WarmCacheRequest true "Short URLs to cache"
+//	@Success      204 {string} string "Cache warmed"
+//	@Failure      400 {string} string "Invalid request body"
+//	@Failure      403 {string} string "Admin role required"
+//	@Failure      413 {string} string "Request body too large"
+//	@Failure      500 {string} string "Internal server error"
+//	@Router       /private/v1/admin/cache/warm [post]
+func (h *AdminHandler) WarmCache(w http.ResponseWriter, r *http.Request) {
+	var request WarmCacheRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeDecodeError(w, err)
+
+		return
+	}
+	if len(request.Ids) == 0 {
+		http.Error(w, "ids are required", http.StatusBadRequest)
+
+		return
+	}
+	if len(request.Ids) > maxWarmCacheIds {
+		http.Error(w, fmt.Sprintf("at most %d ids can be warmed at once", maxWarmCacheIds), http.StatusBadRequest)
+
+		return
+	}
+
+	if err := h.cacheWarmer.WarmCache(r.Context(), request.Ids); err != nil {
+		http.Error(w, "failed to warm cache", http.StatusInternalServerError)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// WarmCacheMostVisited godoc
+//
+//	@Summary      Warm the cache with the most visited short URLs
+//	@Description  Cache the redirects of the short URLs with the most visits within the last week, as many as
+//	@Description  configured
+//	@Tags         admin, private
+//	@Success      204 {string} string "Cache warmed"
+//	@Failure      403 {string} string "Admin role required"
+//	@Failure      500 {string} string "Internal server error"
+//	@Router       /private/v1/admin/cache/warm/all [post]
+func (h *AdminHandler) WarmCacheMostVisited(w http.ResponseWriter, r *http.Request) {
+	if err := h.cacheWarmer.WarmCacheMostVisited(r.Context()); err != nil {
+		http.Error(w, "failed to warm cache", http.StatusInternalServerError)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,139 @@
+package handlers_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"go.uber.org/mock/gomock"
+
+	"github.com/AvalosM/short-url-service/internal/handlers"
+	"github.com/AvalosM/short-url-service/internal/handlers/mocks"
+	"github.com/AvalosM/short-url-service/pkg/shorturl"
+)
+
+// adminHandlerMocks holds the mocked dependencies of an admin handler
+type adminHandlerMocks struct {
+	configReloader *mocks.MockConfigReloader
+	shortURLStats  *mocks.MockShortURLStats
+	cacheWarmer    *mocks.MockCacheWarmer
+}
+
+func (suite *HandlerSuite) newAdminHandler() (*handlers.AdminHandler, *adminHandlerMocks) {
+	adminMocks := &adminHandlerMocks{
+		configReloader: mocks.NewMockConfigReloader(suite.mockCtrl),
+		shortURLStats:  mocks.NewMockShortURLStats(suite.mockCtrl),
+		cacheWarmer:    mocks.NewMockCacheWarmer(suite.mockCtrl),
+	}
+
+	adminHandler, err := handlers.NewAdminHandler(adminMocks.configReloader, adminMocks.shortURLStats, adminMocks.cacheWarmer, suite.mockLogger)
+	suite.Require().NoError(err)
+
+	return adminHandler, adminMocks
+}
+
+func (suite *HandlerSuite) TestReloadConfigSuccess() {
+	adminHandler, adminMocks := suite.newAdminHandler()
+	adminMocks.configReloader.EXPECT().Reload().Return([]string{"logger"}, nil, nil)
+
+	request := httptest.NewRequest(http.MethodPost, "/private/v1/admin/config/reload", nil)
+	response := httptest.NewRecorder()
+	adminHandler.ReloadConfig(response, request)
+
+	suite.Equal(http.StatusOK, response.Code)
+	suite.JSONEq(`{"reloaded": ["logger"], "ignored": []}`, response.Body.String())
+}
+
+func (suite *HandlerSuite) TestReloadConfigFail() {
+	adminHandler, adminMocks := suite.newAdminHandler()
+	adminMocks.configReloader.EXPECT().Reload().Return(nil, nil, errors.New("invalid log level"))
+
+	request := httptest.NewRequest(http.MethodPost, "/private/v1/admin/config/reload", nil)
+	response := httptest.NewRecorder()
+	adminHandler.ReloadConfig(response, request)
+
+	suite.Equal(http.StatusInternalServerError, response.Code)
+}
+
+func (suite *HandlerSuite) TestGetStatsSuccess() {
+	adminHandler, adminMocks := suite.newAdminHandler()
+	adminMocks.shortURLStats.EXPECT().Stats().Return(shorturl.ManagerStats{CollisionCount: 1, CreateCount: 2, CollisionRate: 0.5, HitCount: 3, MissCount: 4})
+
+	request := httptest.NewRequest(http.MethodGet, "/private/v1/admin/stats", nil)
+	response := httptest.NewRecorder()
+	adminHandler.GetStats(response, request)
+
+	suite.Equal(http.StatusOK, response.Code)
+	suite.JSONEq(`{"collision_count": 1, "create_count": 2, "collision_rate": 0.5, "hit_count": 3, "miss_count": 4}`, response.Body.String())
+}
+
+func (suite *HandlerSuite) TestResetStatsSuccess() {
+	adminHandler, adminMocks := suite.newAdminHandler()
+	adminMocks.shortURLStats.EXPECT().ResetStats()
+
+	request := httptest.NewRequest(http.MethodPost, "/private/v1/admin/stats/reset", nil)
+	response := httptest.NewRecorder()
+	adminHandler.ResetStats(response, request)
+
+	suite.Equal(http.StatusNoContent, response.Code)
+}
+
+func (suite *HandlerSuite) TestWarmCacheSuccess() {
+	adminHandler, adminMocks := suite.newAdminHandler()
+	adminMocks.cacheWarmer.EXPECT().WarmCache(gomock.Any(), []string{"AABBCC", "DDEEFF"}).Return(nil)
+
+	request := httptest.NewRequest(http.MethodPost, "/private/v1/admin/cache/warm", strings.NewReader(`{"ids": ["AABBCC", "DDEEFF"]}`))
+	response := httptest.NewRecorder()
+	adminHandler.WarmCache(response, request)
+
+	suite.Equal(http.StatusNoContent, response.Code)
+}
+
+func (suite *HandlerSuite) TestWarmCacheFailInvalidRequest() {
+	adminHandler, _ := suite.newAdminHandler()
+	tooMany := `{"ids": ["AABBCC"` + strings.Repeat(`, "AABBCC"`, 10000) + `]}`
+
+	for name, body := range map[string]string{
+		"invalid json": `{"ids": `,
+		"no ids":       `{"ids": []}`,
+		"too many ids": tooMany,
+	} {
+		suite.Run(name, func() {
+			request := httptest.NewRequest(http.MethodPost, "/private/v1/admin/cache/warm", strings.NewReader(body))
+			response := httptest.NewRecorder()
+			adminHandler.WarmCache(response, request)
+
+			suite.Equal(http.StatusBadRequest, response.Code)
+		})
+	}
+}
+
+func (suite *HandlerSuite) TestWarmCacheFail() {
+	adminHandler, adminMocks := suite.newAdminHandler()
+	adminMocks.cacheWarmer.EXPECT().WarmCache(gomock.Any(), []string{"AABBCC"}).Return(errors.New("storage error"))
+
+	request := httptest.NewRequest(http.MethodPost, "/private/v1/admin/cache/warm", strings.NewReader(`{"ids": ["AABBCC"]}`))
+	response := httptest.NewRecorder()
+	adminHandler.WarmCache(response, request)
+
+	suite.Equal(http.StatusInternalServerError, response.Code)
+}
+
+func (suite *HandlerSuite) TestWarmCacheMostVisited() {
+	adminHandler, adminMocks := suite.newAdminHandler()
+	adminMocks.cacheWarmer.EXPECT().WarmCacheMostVisited(gomock.Any()).Return(nil)
+
+	request := httptest.NewRequest(http.MethodPost, "/private/v1/admin/cache/warm/all", nil)
+	response := httptest.NewRecorder()
+	adminHandler.WarmCacheMostVisited(response, request)
+
+	suite.Equal(http.StatusNoContent, response.Code)
+
+	adminMocks.cacheWarmer.EXPECT().WarmCacheMostVisited(gomock.Any()).Return(errors.New("storage error"))
+
+	response = httptest.NewRecorder()
+	adminHandler.WarmCacheMostVisited(response, request)
+
+	suite.Equal(http.StatusInternalServerError, response.Code)
+}
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"errors"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/AvalosM/short-url-service/pkg/tenant"
+)
+
+// TenantIdPlaceholder is replaced by the tenant id in TenantBaseURL
+const TenantIdPlaceholder = "{tenantId}"
+
+// Config holds the configuration for the http handlers
+type Config struct {
+	BaseURL string `json:"base_url"`
+	// TenantBaseURL is the base URL of the short URLs of non-default tenants, it must contain TenantIdPlaceholder
+	TenantBaseURL string `json:"tenant_base_url"`
+	// AllowedBaseURLs are the base URLs a short URL creation can ask for with the BaseURLHeader, for deployments
+	// serving short URLs under several domains
+	AllowedBaseURLs []string `json:"allowed_base_urls"`
+	// StreamHeartbeatIntervalInMS is how often a heartbeat event is sent to click stream clients, so idle streams are
+	// not closed by proxies
+	StreamHeartbeatIntervalInMS int `json:"stream_heartbeat_interval_in_ms"`
+	// MaxMetricsRangeInDays is the longest time range metrics, latency and dashboard stats can be requested for
+	MaxMetricsRangeInDays int `json:"max_metrics_range_in_days"`
+}
+
+// DefaultConfig returns the default configuration for the http handlers
+func DefaultConfig() *Config {
+	return &Config{
+		BaseURL:       "http://localhost:8080/public/v1/short-urls/",
+		TenantBaseURL: "http://localhost:8080/public/v1/tenants/" + TenantIdPlaceholder + "/short-urls/",
+
+		StreamHeartbeatIntervalInMS: 30000,
+		MaxMetricsRangeInDays:       366,
+	}
+}
+
+// Validate checks if the configuration is valid
+func (c *Config) Validate() error {
+	if c.BaseURL == "" {
+		return errors.New("base URL cannot be empty")
+	}
+	if _, err := url.Parse(c.BaseURL); err != nil {
+		return errors.New("base URL must be a valid URL")
+	}
+	if !strings.Contains(c.TenantBaseURL, TenantIdPlaceholder) {
+		return errors.New("tenant base URL must contain " + TenantIdPlaceholder)
+	}
+	if _, err := url.Parse(c.TenantBaseURL); err != nil {
+		return errors.New("tenant base URL must be a valid URL")
+	}
+	for _, allowed := range c.AllowedBaseURLs {
+		if !validBaseURL(allowed) {
+			return errors.New("allowed base URLs must be valid http or https URLs")
+		}
+	}
+	if c.StreamHeartbeatIntervalInMS <= 0 {
+		return errors.New("stream heartbeat interval must be greater than 0")
+	}
+	if c.MaxMetricsRangeInDays <= 0 {
+		return errors.New("max metrics range must be greater than 0")
+	}
+
+	return nil
+}
+
+// allowedBaseURL reports whether baseURL is one of the AllowedBaseURLs
+func (c *Config) allowedBaseURL(baseURL string) bool {
+	return slices.Contains(c.AllowedBaseURLs, baseURL)
+}
+
+// validBaseURL reports whether baseURL is an absolute http or https URL
+func validBaseURL(baseURL string) bool {
+	parsed, err := url.Parse(baseURL)
+	if err != nil {
+		return false
+	}
+
+	return (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != ""
+}
+
+// baseURL returns the base URL of the short URLs of the given tenant
+func (c *Config) baseURL(tenantID string) string {
+	if tenantID == tenant.Default {
+		return c.BaseURL
+	}
+
+	return strings.ReplaceAll(c.TenantBaseURL, TenantIdPlaceholder, url.PathEscape(tenantID))
+}
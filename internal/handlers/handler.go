@@ -1,30 +1,95 @@
 package handlers
 
 import (
+	"bytes"
 	"context"
+	"crypto/md5"
+	"encoding/csv"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 
+	"github.com/AvalosM/short-url-service/internal/templates"
 	"github.com/AvalosM/short-url-service/pkg/logging"
 	"github.com/AvalosM/short-url-service/pkg/metrics"
 	"github.com/AvalosM/short-url-service/pkg/shorturl"
+	"github.com/AvalosM/short-url-service/pkg/tenant"
+	"github.com/AvalosM/short-url-service/pkg/webhook"
 )
 
 // ShortURLManager short url manager
 type ShortURLManager interface {
-	GetLongURL(ctx context.Context, shortURLId string) (string, error)
-	CreateShortURL(ctx context.Context, longURL string) (string, error)
+	GetLongURL(ctx context.Context, shortURLId string) (*shorturl.ShortURLResult, error)
+	UnlockLongURL(ctx context.Context, shortURLId string) (*shorturl.ShortURLResult, error)
+	CheckPassword(ctx context.Context, shortURLId string, password string) error
+	GetShortURL(ctx context.Context, shortURLId string) (*shorturl.ShortURL, error)
+	GetLongURLBulk(ctx context.Context, ids []string) (map[string]string, []string, error)
+	CheckShortURL(ctx context.Context, shortURLId string) error
+	CreateShortURL(ctx context.Context, longURL string, options *shorturl.CreateOptions) (*shorturl.ShortURL, error)
+	GetOrCreateShortURL(ctx context.Context, longURL string) (string, bool, error)
+	CreateShortURLBulk(ctx context.Context, entries []*shorturl.BulkEntry) []*shorturl.BulkResult
+	ListShortURLs(ctx context.Context, filter *shorturl.ListFilter) ([]*shorturl.ShortURL, error)
+	ListShortURLsByOwner(ctx context.Context, userID string, limit, offset int) ([]*shorturl.ShortURL, error)
+	DeleteShortURLsByOwner(ctx context.Context, userID string) error
+	StreamShortURLs(ctx context.Context, filter *shorturl.ListFilter) (<-chan *shorturl.ShortURL, <-chan error)
 	DeleteShortURL(ctx context.Context, shortURLId string) error
+	GetAuditLog(ctx context.Context, filter *shorturl.AuditFilter) ([]*shorturl.AuditEntry, error)
+	CreateAlias(ctx context.Context, shortURLId string, aliasId string) error
+	DeleteAlias(ctx context.Context, shortURLId string, aliasId string) error
+	UpdateShortURLStatus(ctx context.Context, shortURLId string, status shorturl.Status) error
+	GetDashboardStats(ctx context.Context, from, to time.Time) (*shorturl.DashboardStats, error)
 }
 
 // MetricsManager metrics manager
 type MetricsManager interface {
-	RecordShortURLRequestAsync(id string, ip string)
+	RecordShortURLRequestAsync(tenantID string, id string, ip string, userAgent string, latency time.Duration)
 	GetShortURLMetrics(ctx context.Context, id string, from, to time.Time) (*metrics.Metrics, error)
+	GetShortURLMetricsBulk(ctx context.Context, ids []string, from, to time.Time) (map[string]*metrics.Metrics, error)
+	GetShortURLLatency(ctx context.Context, id string, from, to time.Time) ([]*metrics.Latency, error)
+	StreamShortURLMetrics(ctx context.Context, id string, from, to time.Time, fn func(*metrics.Interval) error) error
+	Snapshot(ctx context.Context) (map[string]metrics.CollectorSnapshot, error)
+	Drain(ctx context.Context) error
+	Subscribe(ctx context.Context, id string) (<-chan metrics.Event, func())
+}
+
+// WebhookManager webhook manager
+type WebhookManager interface {
+	RegisterWebhook(ctx context.Context, shortURLId string, url string, secret string) (*webhook.Webhook, error)
+	DeleteWebhook(ctx context.Context, shortURLId string, webhookId int64) error
+	NotifyClickAsync(tenantID string, shortURLId string)
+}
+
+// TokenSigner issues and validates the tokens that unlock password protected short URLs
+type TokenSigner interface {
+	Generate(subject string) (string, time.Time, error)
+	Validate(token string, subject string) error
+}
+
+// ConfigReloader reloads the configuration sections that support hot reload, it returns the names of the sections
+// reloaded and of the changed sections ignored until restart
+type ConfigReloader interface {
+	Reload() ([]string, []string, error)
+}
+
+// ShortURLStats reports the runtime counters of the short URL manager
+type ShortURLStats interface {
+	Stats() shorturl.ManagerStats
+	ResetStats()
+}
+
+// CacheWarmer caches the redirects of short URLs ahead of their clicks
+type CacheWarmer interface {
+	WarmCache(ctx context.Context, ids []string) error
+	WarmCacheMostVisited(ctx context.Context) error
 }
 
 // Logger ...
@@ -35,32 +100,75 @@ type Logger interface {
 	Warn(msg string, args ...interface{})
 }
 
+// ActorHeader identifies who makes a request, it is recorded in the short URL audit log
+const ActorHeader = "X-Actor"
+
+// BaseURLHeader asks for the base URL of a created short URL, it must be one of the configured AllowedBaseURLs
+const BaseURLHeader = "X-Base-URL"
+
+const (
+	// TTLHeader is the number of seconds left before an expiring short URL expires
+	TTLHeader = "X-Short-URL-TTL"
+	// ExpiresAtHeader is the RFC3339 time an expiring short URL expires at
+	ExpiresAtHeader = "X-Short-URL-Expires-At"
+)
+
+const (
+	defaultListLimit = 100
+	maxListLimit     = 1000
+	// maxResolveIds is the number of short URLs a single resolve request can ask for
+	maxResolveIds = 100
+	// maxBulkMetricsIds is the number of short URLs a single bulk metrics request can ask for
+	maxBulkMetricsIds = 100
+)
+
 // ShortURLHandler handles short URL http requests
 type ShortURLHandler struct {
+	config          *Config
 	shortURLManager ShortURLManager
 	metricsManager  MetricsManager
+	webhookManager  WebhookManager
+	tokenSigner     TokenSigner
 	logger          Logger
 }
 
 // NewShortURLHandler creates a new ShortURLHandler
-func NewShortURLHandler(shortURLManager ShortURLManager, metricsManager MetricsManager, logger Logger) (*ShortURLHandler, error) {
+func NewShortURLHandler(config *Config, shortURLManager ShortURLManager, metricsManager MetricsManager, webhookManager WebhookManager,
+	tokenSigner TokenSigner, logger Logger) (*ShortURLHandler, error) {
+	if config == nil {
+		return nil, errors.New("config cannot be nil")
+	}
 	if shortURLManager == nil {
 		return nil, errors.New("short URL manager cannot be nil")
 	}
 	if metricsManager == nil {
 		return nil, errors.New("metrics manager cannot be nil")
 	}
+	if webhookManager == nil {
+		return nil, errors.New("webhook manager cannot be nil")
+	}
+	if tokenSigner == nil {
+		return nil, errors.New("token signer cannot be nil")
+	}
 	if logger == nil {
 		return nil, errors.New("logger cannot be nil")
 	}
 
 	return &ShortURLHandler{
+		config:          config,
 		shortURLManager: shortURLManager,
 		metricsManager:  metricsManager,
+		webhookManager:  webhookManager,
+		tokenSigner:     tokenSigner,
 		logger:          logger,
 	}, nil
 }
 
+// log returns the logger of the handler adding the request-scoped attributes of ctx to its records
+func (h *ShortURLHandler) log(ctx context.Context) Logger {
+	return logging.FromContext(ctx, h.logger)
+}
+
 // CreateShortURL godoc
 //
 //	@Summary      Create a short URL
@@ -68,154 +176,2055 @@ func NewShortURLHandler(shortURLManager ShortURLManager, metricsManager MetricsM
 //	@Tags         short-url, private
 //	@Accept       json
 //	@Produce      json
-//	@Param        ShortURLRequest  body string true "Long URL to be shortened"
-//	@Success      201 {string} string "Short URL id"
-//	@Failure      400 {string} string "Invalid long URL"
+//	@Param        ShortURLRequest  body ShortURLRequest true "Long URL to be shortened, its tags, description, click limit, password, redirect code and an optional webhook"
+//	@Param        Idempotency-Key  header string false "Key used to replay the response of a retried request"
+//	@Param        X-Actor          header string false "Actor recorded in the audit log"
+//	@Param        X-Base-URL       header string false "Base URL of the returned short URL, one of the allowed base URLs"
+//	@Success      201 {object} ShortURLResponse "Created short URL, without its webhook if registering it failed"
+//	@Failure      400 {string} string "Invalid long URL, tags, description, click limit, password, redirect code or webhook, an invalid long URL is an ErrorResponse with code INVALID_URL, or URL_TOO_LONG if it is too long"
+//	@Failure      403 {object} ErrorResponse "Long URL of a denied host, with code URL_DENIED"
+//	@Failure      409 {string} string "A request with the same Idempotency-Key is in progress"
+//	@Failure      413 {string} string "Request body too large"
+//	@Failure      422 {string} string "Idempotency-Key already used with another request body"
 //	@Failure      500 {string} string "Internal server error"
 //	@Router       /private/v1/short-urls/create [post]
 func (h *ShortURLHandler) CreateShortURL(w http.ResponseWriter, r *http.Request) {
 	var request ShortURLRequest
 	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeDecodeError(w, err)
 
 		return
 	}
 
-	ctx := r.Context()
-	shortURLId, err := h.shortURLManager.CreateShortURL(ctx, request.LongURL)
+	if request.Webhook != nil {
+		if err := webhook.Validate(request.Webhook.URL, request.Webhook.Secret); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+
+			return
+		}
+	}
+
+	options := &shorturl.CreateOptions{
+		Tags:               request.Tags,
+		Description:        request.Description,
+		MaxClicks:          request.MaxClicks,
+		Password:           request.Password,
+		RedirectCode:       request.RedirectCode,
+		ForwardQueryParams: request.ForwardQueryParams,
+		ExpiresAt:          request.ExpiresAt,
+		ShowInterstitial:   request.ShowInterstitial,
+	}
+	if request.CacheTTLSeconds != nil {
+		options.CacheTTLSeconds = *request.CacheTTLSeconds
+	}
+
+	ctx := actorContext(r)
+	shortURL, err := h.shortURLManager.CreateShortURL(ctx, request.LongURL, options)
 	if err != nil {
-		http.Error(w, "failed to create short URL", http.StatusInternalServerError)
+		switch {
+		case errors.Is(err, shorturl.ErrURLTooLong):
+			h.writeJSON(w, http.StatusBadRequest, &ErrorResponse{Code: ErrorCodeURLTooLong, Message: err.Error()})
+
+			return
+		case errors.Is(err, shorturl.ErrInvalidLongURL):
+			h.writeJSON(w, http.StatusBadRequest, &ErrorResponse{Code: ErrorCodeInvalidURL, Message: err.Error()})
+
+			return
+		case errors.Is(err, shorturl.ErrURLDenied):
+			h.writeJSON(w, http.StatusForbidden, &ErrorResponse{Code: ErrorCodeURLDenied, Message: err.Error()})
+
+			return
+		case errors.Is(err, shorturl.ErrInvalidTags), errors.Is(err, shorturl.ErrInvalidDescription), errors.Is(err, shorturl.ErrInvalidMaxClicks),
+			errors.Is(err, shorturl.ErrInvalidPassword), errors.Is(err, shorturl.ErrInvalidRedirectCode), errors.Is(err, shorturl.ErrInvalidExpiresAt),
+			errors.Is(err, shorturl.ErrInvalidCacheTTL):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+
+			return
+		default:
+			http.Error(w, "failed to create short URL", http.StatusInternalServerError)
+
+			return
+		}
+	}
+
+	shortURLResponse := NewShortURLResponse(shortURL, h.requestBaseURL(r, tenant.IDFromContext(ctx)))
+	if request.Webhook != nil {
+		// The short URL is already created, failing to register its webhook leaves it out of the response and the
+		// webhook can be registered again on its own
+		createdWebhook, err := h.webhookManager.RegisterWebhook(ctx, shortURL.Id, request.Webhook.URL, request.Webhook.Secret)
+		if err != nil {
+			h.log(ctx).Error("failed to register webhook of created short URL", logging.ShortURLIdKey, shortURL.Id, logging.ErrorKey, err)
+		} else {
+			shortURLResponse.Webhook = NewWebhookResponse(createdWebhook)
+		}
+	}
+
+	response, err := json.Marshal(shortURLResponse)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
 
 		return
 	}
 
+	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
-	if _, err = w.Write([]byte(shortURLId)); err != nil {
-		h.logger.Error("failed to write response", logging.ErrorKey, err)
+	if _, err = w.Write(response); err != nil {
+		h.log(r.Context()).Error("failed to write response", logging.ErrorKey, err)
 
 		return
 	}
 }
 
-// DeleteShortURL godoc
+// GetOrCreateShortURL godoc
 //
-//	@Summary      Delete a short URL
-//	@Description  Delete a short URL by its id
+//	@Summary      Get or create a short URL
+//	@Description  Get the short URL of the given long URL, creating it if the long URL was not shortened yet. Repeating
+//	@Description  the request returns the same short URL.
 //	@Tags         short-url, private
 //	@Accept       json
 //	@Produce      json
-//	@Param        shortURLId  path string true "Short URL id to be deleted"
-//	@Success      200 {string} string "Short URL deleted successfully"
-//	@Failure      400 {string} string "Invalid short URL id"
+//	@Param        GetOrCreateShortURLRequest  body GetOrCreateShortURLRequest true "Long URL to be shortened"
+//	@Param        X-Actor                     header string false "Actor recorded in the audit log"
+//	@Param        X-Base-URL                  header string false "Base URL of the returned short URL, one of the allowed base URLs"
+//	@Success      200 {object} GetOrCreateShortURLResponse "Existing short URL"
+//	@Success      201 {object} GetOrCreateShortURLResponse "Created short URL"
+//	@Failure      400 {string} string "Invalid long URL"
+//	@Failure      403 {string} string "Long URL of a denied host"
+//	@Failure      413 {string} string "Request body too large"
 //	@Failure      500 {string} string "Internal server error"
-//	@Router       /private/v1/short-urls/{shortURLId} [delete]
-func (h *ShortURLHandler) DeleteShortURL(w http.ResponseWriter, r *http.Request) {
-	shortURLId := chi.URLParam(r, "shortURLId")
-	if shortURLId == "" {
-		http.Error(w, "short URL id is required", http.StatusBadRequest)
+//	@Router       /private/v1/short-urls [put]
+func (h *ShortURLHandler) GetOrCreateShortURL(w http.ResponseWriter, r *http.Request) {
+	var request GetOrCreateShortURLRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeDecodeError(w, err)
+
+		return
+	}
+
+	ctx := actorContext(r)
+	id, created, err := h.shortURLManager.GetOrCreateShortURL(ctx, request.LongURL)
+	if err != nil {
+		switch {
+		case errors.Is(err, shorturl.ErrInvalidLongURL):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+
+			return
+		case errors.Is(err, shorturl.ErrURLDenied):
+			http.Error(w, err.Error(), http.StatusForbidden)
+
+			return
+		default:
+			http.Error(w, "failed to create short URL", http.StatusInternalServerError)
+
+			return
+		}
+	}
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+
+	h.writeJSON(w, status, &GetOrCreateShortURLResponse{
+		Id:       id,
+		ShortURL: h.requestBaseURL(r, tenant.IDFromContext(ctx)) + id,
+		Created:  created,
+	})
+}
+
+// ImportShortURLs godoc
+//
+//	@Summary      Import short URLs from a CSV
+//	@Description  Create a short URL for each row of a CSV file. Its header must have a long_url column, and can have
+//	@Description  custom_slug, tags and expires_at columns. custom_slug is created as an alias of the short URL, tags are
+//	@Description  comma separated and expires_at is in RFC3339 format. Rows failing do not stop the others from being
+//	@Description  imported, the report tells the outcome of each row.
+//	@Tags         short-url, private
+//	@Accept       multipart/form-data
+//	@Produce      json
+//	@Param        file        formData file   true  "CSV file with the long URLs to be shortened"
+//	@Param        X-Actor     header   string false "Actor recorded in the audit log"
+//	@Param        X-Base-URL  header   string false "Base URL of the returned short URLs, one of the allowed base URLs"
+//	@Success      200 {object} ImportShortURLsResponse "Import report"
+//	@Failure      400 {string} string "Missing file or invalid CSV"
+//	@Failure      413 {string} string "File too large"
+//	@Router       /private/v1/short-urls/import [post]
+func (h *ShortURLHandler) ImportShortURLs(w http.ResponseWriter, r *http.Request) {
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		switch {
+		case errors.Is(err, http.ErrMissingFile):
+			http.Error(w, "file is required", http.StatusBadRequest)
+
+			return
+		default:
+			writeDecodeError(w, err)
+
+			return
+		}
+	}
+	defer file.Close()
+
+	rows, entries, err := parseImportCSV(file)
+	if err != nil {
+		writeDecodeError(w, err)
+
+		return
+	}
+
+	ctx := actorContext(r)
+	var results []*shorturl.BulkResult
+	if len(entries) > 0 {
+		results = h.shortURLManager.CreateShortURLBulk(ctx, entries)
+	}
+	baseURL := h.requestBaseURL(r, tenant.IDFromContext(ctx))
+
+	response := &ImportShortURLsResponse{Rows: rows}
+	entryIndex := 0
+	for _, row := range rows {
+		if row.Error == "" {
+			result := results[entryIndex]
+			entryIndex++
+
+			if result.ShortURL != nil {
+				row.Id = result.ShortURL.Id
+				row.ShortURL = baseURL + result.ShortURL.Id
+			}
+			if result.Err != nil {
+				row.Error = importError(result.Err)
+			}
+		}
+
+		if row.Error == "" {
+			response.Created++
+		} else {
+			response.Failed++
+		}
+	}
+
+	h.writeJSON(w, http.StatusOK, response)
+}
+
+// parseImportCSV parses the rows of an import CSV, it returns the report row of every CSV row and the entries of the
+// valid ones in the same order. Rows failing validation already carry their error.
+func parseImportCSV(file io.Reader) ([]*ImportRowResponse, []*shorturl.BulkEntry, error) {
+	reader := csv.NewReader(file)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading CSV header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, column := range header {
+		columns[strings.ToLower(strings.TrimSpace(column))] = i
+	}
+	if _, ok := columns["long_url"]; !ok {
+		return nil, nil, errors.New("CSV header must have a long_url column")
+	}
+
+	field := func(record []string, column string) string {
+		if i, ok := columns[column]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+
+		return ""
+	}
+
+	var rows []*ImportRowResponse
+	var entries []*shorturl.BulkEntry
+	// The header is the first row
+	for rowNumber := 2; ; rowNumber++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil && !errors.Is(err, csv.ErrFieldCount) {
+			return nil, nil, fmt.Errorf("reading CSV row %d: %w", rowNumber, err)
+		}
+
+		row := &ImportRowResponse{Row: rowNumber, LongURL: field(record, "long_url")}
+		rows = append(rows, row)
+
+		if err != nil {
+			row.Error = "wrong number of fields"
+
+			continue
+		}
+		if row.LongURL == "" {
+			row.Error = "long_url is required"
+
+			continue
+		}
+
+		options := &shorturl.CreateOptions{}
+		if tags := field(record, "tags"); tags != "" {
+			for _, tag := range strings.Split(tags, ",") {
+				if tag = strings.TrimSpace(tag); tag != "" {
+					options.Tags = append(options.Tags, tag)
+				}
+			}
+		}
+		if expiresAt := field(record, "expires_at"); expiresAt != "" {
+			parsed, err := time.Parse(time.RFC3339, expiresAt)
+			if err != nil {
+				row.Error = "expires_at must be in RFC3339 format"
+
+				continue
+			}
+			options.ExpiresAt = &parsed
+		}
+
+		entries = append(entries, &shorturl.BulkEntry{
+			LongURL: row.LongURL,
+			Options: options,
+			AliasId: field(record, "custom_slug"),
+		})
+	}
+
+	return rows, entries, nil
+}
+
+// importError returns the error reported for a row failing to be imported, only validation errors are detailed
+func importError(err error) string {
+	switch {
+	case errors.Is(err, shorturl.ErrInvalidLongURL), errors.Is(err, shorturl.ErrURLDenied), errors.Is(err, shorturl.ErrInvalidTags),
+		errors.Is(err, shorturl.ErrInvalidExpiresAt), errors.Is(err, shorturl.ErrInvalidAliasId), errors.Is(err, shorturl.ErrAliasExists),
+		errors.Is(err, shorturl.ErrShortURLExists):
+		return err.Error()
+	default:
+		return "failed to create short URL"
+	}
+}
+
+// ListShortURLs godoc
+//
+//	@Summary      List short URLs
+//	@Description  List short URLs, newest first, optionally filtered by tag
+//	@Tags         short-url, private
+//	@Accept       json
+//	@Produce      json
+//	@Param        tag     query string false "Only list short URLs with this tag"
+//	@Param        limit   query int    false "Maximum number of short URLs to return (default 100, max 1000)"
+//	@Param        offset  query int    false "Number of short URLs to skip"
+//	@Success      200 {object} ShortURLListResponse "Short URLs"
+//	@Failure      400 {string} string "Invalid request parameters"
+//	@Failure      500 {string} string "Internal server error"
+//	@Router       /private/v1/short-urls [get]
+func (h *ShortURLHandler) ListShortURLs(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	limit, err := parseIntQueryParam(query.Get("limit"), defaultListLimit)
+	if err != nil || limit <= 0 || limit > maxListLimit {
+		http.Error(w, "invalid limit", http.StatusBadRequest)
+
+		return
+	}
+
+	offset, err := parseIntQueryParam(query.Get("offset"), 0)
+	if err != nil || offset < 0 {
+		http.Error(w, "invalid offset", http.StatusBadRequest)
+
 		return
 	}
 
 	ctx := r.Context()
-	if err := h.shortURLManager.DeleteShortURL(ctx, shortURLId); err != nil {
-		http.Error(w, "failed to delete short URL", http.StatusInternalServerError)
+	shortURLs, err := h.shortURLManager.ListShortURLs(ctx, &shorturl.ListFilter{
+		Tag:    query.Get("tag"),
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		http.Error(w, "failed to list short URLs", http.StatusInternalServerError)
+
+		return
+	}
+
+	response, err := json.Marshal(NewShortURLListResponse(shortURLs, h.config.baseURL(tenant.IDFromContext(ctx))))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+
 		return
 	}
 
+	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(response); err != nil {
+		h.log(r.Context()).Error("failed to write response", logging.ErrorKey, err)
+
+		return
+	}
 }
 
-// RedirectToLongURL godoc
+// ListUserShortURLs godoc
 //
-//	@Summary      Redirect to long URL
-//	@Description  Redirect to the long URL for the given short URL id
-//	@Tags         short-url, public
-//	@Accept       json
+//	@Summary      List the short URLs of a user
+//	@Description  List the short URLs created by a user, newest first. Only the user and admins can list them.
+//	@Tags         short-url, private
 //	@Produce      json
-//	@Param        longURL  query string true "Long URL to be shortened"
-//	@Success      302 {string} string "Short URL ID"
-//	@Failure      400 {string} string "Invalid long URL"
-//	@Failure      404 {string} string "Short URL not found"
+//	@Param        userId  path  string true  "Id of the user who created the short URLs"
+//	@Param        limit   query int    false "Maximum number of short URLs to return (default 100, max 1000)"
+//	@Param        offset  query int    false "Number of short URLs to skip"
+//	@Success      200 {object} ShortURLListResponse "Short URLs"
+//	@Failure      400 {string} string "Invalid request parameters"
+//	@Failure      403 {string} string "Not allowed to list the short URLs of the user"
 //	@Failure      500 {string} string "Internal server error"
-//	@Router       /public/v1/short-urls/{shortURLId} [get]
-func (h *ShortURLHandler) RedirectToLongURL(w http.ResponseWriter, r *http.Request) {
-	shortURLId := chi.URLParam(r, "shortURLId")
-	if shortURLId == "" {
-		http.Error(w, "short URL id is required", http.StatusBadRequest)
+//	@Router       /private/v1/users/{userId}/short-urls [get]
+func (h *ShortURLHandler) ListUserShortURLs(w http.ResponseWriter, r *http.Request) {
+	userId := chi.URLParam(r, "userId")
+	if userId == "" {
+		http.Error(w, "user id is required", http.StatusBadRequest)
+
+		return
+	}
+
+	query := r.URL.Query()
+
+	limit, err := parseIntQueryParam(query.Get("limit"), defaultListLimit)
+	if err != nil || limit <= 0 || limit > maxListLimit {
+		http.Error(w, "invalid limit", http.StatusBadRequest)
+
+		return
+	}
+
+	offset, err := parseIntQueryParam(query.Get("offset"), 0)
+	if err != nil || offset < 0 {
+		http.Error(w, "invalid offset", http.StatusBadRequest)
 
 		return
 	}
 
 	ctx := r.Context()
-	longURL, err := h.shortURLManager.GetLongURL(ctx, shortURLId)
+	shortURLs, err := h.shortURLManager.ListShortURLsByOwner(ctx, userId, limit, offset)
 	if err != nil {
 		switch {
-		case errors.Is(err, shorturl.ErrShortURLNotFound):
-			// TODO: return a custom error page instead of a generic 404
-			http.Error(w, "", http.StatusNotFound)
+		case errors.Is(err, shorturl.ErrNotOwner):
+			http.Error(w, "not allowed to list the short URLs of the user", http.StatusForbidden)
 
 			return
 		default:
-			http.Error(w, "failed to retrieve long URL", http.StatusInternalServerError)
+			http.Error(w, "failed to list short URLs", http.StatusInternalServerError)
 
 			return
 		}
 	}
 
-	h.metricsManager.RecordShortURLRequestAsync(shortURLId, r.RemoteAddr)
+	h.writeJSON(w, http.StatusOK, NewShortURLListResponse(shortURLs, h.config.baseURL(tenant.IDFromContext(ctx))))
+}
+
+// DeleteUserShortURLs godoc
+//
+//	@Summary      Delete the short URLs of a user
+//	@Description  Delete every short URL created by a user, for deletions of user accounts. Only the user and admins
+//	@Description  can delete them.
+//	@Tags         short-url, private
+//	@Param        userId   path   string true  "Id of the user who created the short URLs"
+//	@Param        X-Actor  header string false "Actor recorded in the audit log"
+//	@Success      204 {string} string "Short URLs deleted"
+//	@Failure      400 {string} string "Invalid user id"
+//	@Failure      403 {string} string "Not allowed to delete the short URLs of the user"
+//	@Failure      500 {string} string "Internal server error"
+//	@Router       /private/v1/users/{userId}/short-urls [delete]
+func (h *ShortURLHandler) DeleteUserShortURLs(w http.ResponseWriter, r *http.Request) {
+	userId := chi.URLParam(r, "userId")
+	if userId == "" {
+		http.Error(w, "user id is required", http.StatusBadRequest)
+
+		return
+	}
+
+	if err := h.shortURLManager.DeleteShortURLsByOwner(actorContext(r), userId); err != nil {
+		switch {
+		case errors.Is(err, shorturl.ErrNotOwner):
+			http.Error(w, "not allowed to delete the short URLs of the user", http.StatusForbidden)
+
+			return
+		default:
+			http.Error(w, "failed to delete short URLs", http.StatusInternalServerError)
+
+			return
+		}
+	}
 
-	http.Redirect(w, r, longURL, http.StatusFound)
+	w.WriteHeader(http.StatusNoContent)
 }
 
-// GetShortURLMetrics godoc
+// ResolveShortURLs godoc
 //
-//	@Summary      Get short URL metrics
-//	@Description  Get metrics for a short URL within a specified time range
+//	@Summary      Resolve many short URLs
+//	@Description  Get the long URLs of many short URLs at once, without counting clicks. Inactive, expired and password
+//	@Description  protected short URLs are not found, aliases are not resolved.
 //	@Tags         short-url, private
-//	@Accept       json
 //	@Produce      json
-//	@Param        shortURLId  path string true "Short URL id to get metrics for"
-//	@Param        from        query string true "Start time for metrics (RFC3339 format)"
-//	@Param        to          query string true "End time for metrics (RFC3339 format)"
-//	@Success      200 {object} metrics.Metrics "Short URL metrics"
+//	@Param        ids  query string true "Comma separated short URL ids, at most 100"
+//	@Success      200 {object} ResolveShortURLsResponse "Long URLs by short URL id and the ids not found"
 //	@Failure      400 {string} string "Invalid request parameters"
-//	@Failure      404 {string} string "Metrics not found"
 //	@Failure      500 {string} string "Internal server error"
-//	@Router       /private/v1/short-urls/{shortURLId}/metrics [get]
-func (h *ShortURLHandler) GetShortURLMetrics(w http.ResponseWriter, r *http.Request) {
-	shortURLId := chi.URLParam(r, "shortURLId")
-	if shortURLId == "" {
-		http.Error(w, "short URL id is required", http.StatusBadRequest)
+//	@Router       /private/v1/short-urls/resolve [get]
+func (h *ShortURLHandler) ResolveShortURLs(w http.ResponseWriter, r *http.Request) {
+	var ids []string
+	for _, id := range strings.Split(r.URL.Query().Get("ids"), ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		http.Error(w, "ids are required", http.StatusBadRequest)
 
 		return
 	}
-
-	var request ShortURLMetricsRequest
-	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	if len(ids) > maxResolveIds {
+		http.Error(w, fmt.Sprintf("at most %d ids can be resolved at once", maxResolveIds), http.StatusBadRequest)
 
 		return
 	}
 
-	ctx := r.Context()
-	metricsResult, err := h.metricsManager.GetShortURLMetrics(ctx, shortURLId, request.From, request.To)
+	longURLs, notFound, err := h.shortURLManager.GetLongURLBulk(r.Context(), ids)
 	if err != nil {
-		http.Error(w, "failed to retrieve metrics", http.StatusInternalServerError)
+		http.Error(w, "failed to resolve short URLs", http.StatusInternalServerError)
 
 		return
 	}
 
-	response, err := json.Marshal(metricsResult)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	h.writeJSON(w, http.StatusOK, &ResolveShortURLsResponse{LongURLs: longURLs, NotFound: notFound})
+}
+
+// ExportShortURLs godoc
+//
+//	@Summary      Export short URLs
+//	@Description  Export all short URLs, newest first, optionally filtered by tags. Rows are streamed as they are read
+//	@Description  from storage, as newline delimited JSON or as a CSV.
+//	@Tags         short-url, private
+//	@Produce      application/x-ndjson,text/csv
+//	@Param        format  query string false "Export format, json or csv (default json)"
+//	@Param        tags    query string false "Comma separated tags, only short URLs with all of them are exported"
+//	@Success      200 {object} ShortURLListItem "One short URL per line, or a CSV with id, short_url, long_url, tags, description, created_at and updated_at columns"
+//	@Failure      400 {string} string "Invalid request parameters"
+//	@Failure      500 {string} string "Internal server error"
+//	@Router       /private/v1/short-urls/export [get]
+func (h *ShortURLHandler) ExportShortURLs(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	format := query.Get("format")
+	switch format {
+	case "":
+		format = "json"
+	case "json", "csv":
+	default:
+		http.Error(w, "unsupported export format", http.StatusBadRequest)
 
 		return
 	}
 
-	w.WriteHeader(http.StatusOK)
-	if _, err := w.Write(response); err != nil {
-		h.logger.Error("failed to write response", logging.ErrorKey, err)
+	filter := &shorturl.ListFilter{}
+	for _, tag := range strings.Split(query.Get("tags"), ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			filter.Tags = append(filter.Tags, tag)
+		}
+	}
+
+	// Exports of large tables outlive the server write timeout, an export whose write deadline cannot be cleared would
+	// be cut mid-body after its 200 status was sent, so it is not started
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Time{}); err != nil {
+		h.log(r.Context()).Error("failed to clear short URLs export write deadline", logging.ErrorKey, err)
+		http.Error(w, "failed to export short URLs", http.StatusInternalServerError)
 
 		return
 	}
+
+	// Streaming is cancelled when the export stops before every short URL is written
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	baseURL := h.requestBaseURL(r, tenant.IDFromContext(ctx))
+	encoder := json.NewEncoder(w)
+	writer := csv.NewWriter(w)
+
+	// The response is only started with the first short URL, so failing to query storage can still be reported
+	started := false
+	start := func() error {
+		if started {
+			return nil
+		}
+		started = true
+
+		w.Header().Set("Transfer-Encoding", "chunked")
+		if format == "csv" {
+			w.Header().Set("Content-Type", "text/csv")
+			w.Header().Set("Content-Disposition", `attachment; filename="short-urls.csv"`)
+			w.WriteHeader(http.StatusOK)
+
+			return writer.Write([]string{"id", "short_url", "long_url", "tags", "description", "created_at", "updated_at"})
+		}
+
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.WriteHeader(http.StatusOK)
+
+		return nil
+	}
+	write := func(shortURL *shorturl.ShortURL) error {
+		if err := start(); err != nil {
+			return err
+		}
+
+		item := NewShortURLListItem(shortURL, baseURL)
+		if format == "csv" {
+			return writer.Write([]string{
+				item.Id,
+				item.ShortURL,
+				item.LongURL,
+				strings.Join(item.Tags, ","),
+				item.Description,
+				item.CreatedAt.UTC().Format(time.RFC3339),
+				item.UpdatedAt.UTC().Format(time.RFC3339),
+			})
+		}
+
+		return encoder.Encode(item)
+	}
+
+	shortURLs, errs := h.shortURLManager.StreamShortURLs(ctx, filter)
+
+	var err error
+	for shortURL := range shortURLs {
+		if err = write(shortURL); err != nil {
+			break
+		}
+	}
+	if err == nil {
+		err = <-errs
+	}
+	if err == nil {
+		err = start()
+	}
+	if err != nil {
+		if !started {
+			http.Error(w, "failed to export short URLs", http.StatusInternalServerError)
+
+			return
+		}
+
+		h.log(r.Context()).Error("failed to write short URLs export", logging.ErrorKey, err)
+
+		return
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		h.log(r.Context()).Error("failed to write short URLs export", logging.ErrorKey, err)
+
+		return
+	}
+}
+
+// GetShortURL godoc
+//
+//	@Summary      Get a short URL
+//	@Description  Get every attribute of the short URL with the given id, its password hash excepted, without being
+//	@Description  redirected. Paused short URLs are returned, expired, archived and exhausted ones are gone.
+//	@Tags         short-url, private
+//	@Accept       json
+//	@Produce      json
+//	@Param        shortURLId  path   string true  "Short URL id to get"
+//	@Success      200 {object} ShortURLDetailsResponse "Short URL"
+//	@Failure      400 {string} string "Invalid short URL id"
+//	@Failure      404 {string} string "Short URL not found"
+//	@Failure      410 {string} string "Short URL expired, archived or click limit exceeded"
+//	@Failure      500 {string} string "Internal server error"
+//	@Router       /private/v1/short-urls/{shortURLId} [get]
+func (h *ShortURLHandler) GetShortURL(w http.ResponseWriter, r *http.Request) {
+	shortURLId := chi.URLParam(r, "shortURLId")
+	if shortURLId == "" {
+		http.Error(w, "short URL id is required", http.StatusBadRequest)
+
+		return
+	}
+
+	ctx := r.Context()
+	shortURL, err := h.shortURLManager.GetShortURL(ctx, shortURLId)
+	if err == nil {
+		err = shortURL.CheckAvailable(time.Now())
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, shorturl.ErrShortURLNotFound):
+			http.Error(w, "", http.StatusNotFound)
+
+			return
+		case errors.Is(err, shorturl.ErrShortURLExpired), errors.Is(err, shorturl.ErrClickLimitExceeded),
+			errors.Is(err, shorturl.ErrShortURLArchived):
+			http.Error(w, "short URL is no longer available", http.StatusGone)
+
+			return
+		case errors.Is(err, shorturl.ErrShortURLPaused):
+			// Paused short URLs are only unavailable until they are resumed
+		default:
+			http.Error(w, "failed to retrieve short URL", http.StatusInternalServerError)
+
+			return
+		}
+	}
+
+	h.writeJSON(w, http.StatusOK, NewShortURLDetailsResponse(shortURL, h.requestBaseURL(r, tenant.IDFromContext(ctx))))
+}
+
+// DeleteShortURL godoc
+//
+//	@Summary      Delete a short URL
+//	@Description  Delete a short URL by its id
+//	@Tags         short-url, private
+//	@Accept       json
+//	@Produce      json
+//	@Param        shortURLId  path   string true  "Short URL id to be deleted"
+//	@Param        X-Actor     header string false "Actor recorded in the audit log"
+//	@Success      200 {string} string "Short URL deleted successfully"
+//	@Failure      400 {string} string "Invalid short URL id"
+//	@Failure      500 {string} string "Internal server error"
+//	@Router       /private/v1/short-urls/{shortURLId} [delete]
+func (h *ShortURLHandler) DeleteShortURL(w http.ResponseWriter, r *http.Request) {
+	shortURLId := chi.URLParam(r, "shortURLId")
+	if shortURLId == "" {
+		http.Error(w, "short URL id is required", http.StatusBadRequest)
+		return
+	}
+
+	ctx := actorContext(r)
+	if err := h.shortURLManager.DeleteShortURL(ctx, shortURLId); err != nil {
+		http.Error(w, "failed to delete short URL", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// RedirectToLongURL godoc
+//
+//	@Summary      Redirect to long URL
+//	@Description  Redirect to the long URL for the given short URL id. Password protected short URLs answer with a
+//	@Description  challenge instead, unless a token obtained from the unlock endpoint is given. Query parameters are
+//	@Description  appended to the long URL when the short URL forwards them. Expiring short URLs tell the time they
+//	@Description  have left in the X-Short-URL-TTL and X-Short-URL-Expires-At headers. Aliases redirect like the short
+//	@Description  URL they point to. Short URLs showing an interstitial answer with an HTML page displaying the long URL
+//	@Description  that redirects after a countdown.
+//	@Tags         short-url, public
+//	@Accept       json
+//	@Produce      json,html
+//	@Param        shortURLId  path  string true  "Short URL id to be followed"
+//	@Param        token       query string false "Token unlocking a password protected short URL"
+//	@Success      200 {object} ProtectedShortURLResponse "Short URL is password protected"
+//	@Success      301 {string} string "Redirect to the long URL, when configured for the short URL"
+//	@Success      302 {string} string "Redirect to the long URL"
+//	@Success      307 {string} string "Redirect to the long URL, when configured for the short URL"
+//	@Failure      400 {string} string "Invalid long URL"
+//	@Failure      403 {string} string "Invalid or expired token"
+//	@Failure      404 {string} string "Short URL not found"
+//	@Failure      410 {string} string "Short URL expired, click limit exceeded or short URL archived"
+//	@Failure      429 {string} string "Short URL click rate limit exceeded"
+//	@Failure      500 {string} string "Internal server error"
+//	@Failure      503 {string} string "Short URL paused"
+//	@Router       /public/v1/short-urls/{shortURLId} [get]
+func (h *ShortURLHandler) RedirectToLongURL(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+
+	shortURLId := chi.URLParam(r, "shortURLId")
+	if shortURLId == "" {
+		http.Error(w, "short URL id is required", http.StatusBadRequest)
+
+		return
+	}
+
+	ctx := r.Context()
+	query := r.URL.Query()
+	var result *shorturl.ShortURLResult
+	var err error
+	if token := query.Get("token"); token != "" {
+		if err := h.tokenSigner.Validate(token, tokenSubject(ctx, shortURLId)); err != nil {
+			http.Error(w, "invalid or expired token", http.StatusForbidden)
+
+			return
+		}
+
+		result, err = h.shortURLManager.UnlockLongURL(ctx, shortURLId)
+	} else {
+		result, err = h.shortURLManager.GetLongURL(ctx, shortURLId)
+	}
+	// The short URL may expire between being read and the redirect being answered
+	if err == nil && result.ExpiresAt != nil && !result.ExpiresAt.After(time.Now()) {
+		err = shorturl.ErrShortURLExpired
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, shorturl.ErrShortURLNotFound):
+			// TODO: return a custom error page instead of a generic 404
+			http.Error(w, "", http.StatusNotFound)
+
+			return
+		case errors.Is(err, shorturl.ErrShortURLExpired), errors.Is(err, shorturl.ErrClickLimitExceeded),
+			errors.Is(err, shorturl.ErrShortURLArchived):
+			http.Error(w, "short URL is no longer available", http.StatusGone)
+
+			return
+		case errors.Is(err, shorturl.ErrShortURLPaused):
+			http.Error(w, "short URL is temporarily unavailable", http.StatusServiceUnavailable)
+
+			return
+		case errors.Is(err, shorturl.ErrClickRateLimitExceeded):
+			// Click rate limits are whole clicks per second, a click is always allowed again within a second
+			w.Header().Set("Retry-After", "1")
+			http.Error(w, "too many requests", http.StatusTooManyRequests)
+
+			return
+		case errors.Is(err, shorturl.ErrPasswordRequired):
+			h.writeJSON(w, http.StatusOK, &ProtectedShortURLResponse{Protected: true})
+
+			return
+		default:
+			http.Error(w, "failed to retrieve long URL", http.StatusInternalServerError)
+
+			return
+		}
+	}
+
+	// Clicks through an alias count for the short URL it points to
+	tenantID := tenant.IDFromContext(ctx)
+	h.metricsManager.RecordShortURLRequestAsync(tenantID, result.ShortURLId, r.RemoteAddr, r.UserAgent(), time.Since(start))
+	h.webhookManager.NotifyClickAsync(tenantID, result.ShortURLId)
+
+	longURL := result.LongURL
+	if result.ForwardQueryParams {
+		// The unlock token is meant for this service only, it must not leak to the long URL
+		query.Del("token")
+
+		longURL, err = appendQueryParams(longURL, query)
+		if err != nil {
+			h.log(r.Context()).Error("failed to forward query parameters", logging.ShortURLIdKey, shortURLId, logging.ErrorKey, err)
+			http.Error(w, "failed to retrieve long URL", http.StatusInternalServerError)
+
+			return
+		}
+	}
+
+	setExpiryHeaders(w, result.ExpiresAt)
+	if result.ShowInterstitial {
+		h.writeInterstitial(w, r, result.ShortURLId, longURL)
+
+		return
+	}
+
+	http.Redirect(w, r, longURL, result.RedirectCode)
+}
+
+// writeInterstitial writes the page redirecting to the long URL of a short URL after a countdown, with the number of
+// times the short URL was followed when it is known
+func (h *ShortURLHandler) writeInterstitial(w http.ResponseWriter, r *http.Request, shortURLId string, longURL string) {
+	data := templates.InterstitialData{
+		LongURL:          longURL,
+		CountdownSeconds: templates.InterstitialCountdownSeconds,
+	}
+
+	shortURLMetrics, err := h.metricsManager.GetShortURLMetrics(r.Context(), shortURLId, time.Time{}, time.Now())
+	if err != nil {
+		h.log(r.Context()).Warn("failed to get interstitial click count", logging.ShortURLIdKey, shortURLId, logging.ErrorKey, err)
+	} else {
+		data.Clicks = shortURLMetrics.Visits
+	}
+
+	var page bytes.Buffer
+	if err := templates.Interstitial.Execute(&page, data); err != nil {
+		h.log(r.Context()).Error("failed to render interstitial page", logging.ShortURLIdKey, shortURLId, logging.ErrorKey, err)
+		http.Error(w, "failed to retrieve long URL", http.StatusInternalServerError)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(page.Bytes()); err != nil {
+		h.log(r.Context()).Error("failed to write response", logging.ErrorKey, err)
+	}
+}
+
+// CheckShortURL godoc
+//
+//	@Summary      Check a short URL exists
+//	@Description  Check the short URL or alias with the given id would redirect, without following it. No visit or
+//	@Description  click is counted.
+//	@Tags         short-url, public
+//	@Param        shortURLId  path  string true  "Short URL id to be checked"
+//	@Success      200 "Short URL exists and is active"
+//	@Failure      400 "Short URL id is required"
+//	@Failure      404 "Short URL not found"
+//	@Failure      410 "Short URL expired, archived or its click limit exceeded"
+//	@Failure      500 "Internal server error"
+//	@Failure      503 "Short URL paused"
+//	@Router       /public/v1/short-urls/{shortURLId} [head]
+func (h *ShortURLHandler) CheckShortURL(w http.ResponseWriter, r *http.Request) {
+	shortURLId := chi.URLParam(r, "shortURLId")
+	if shortURLId == "" {
+		w.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	err := h.shortURLManager.CheckShortURL(r.Context(), shortURLId)
+	if err != nil {
+		switch {
+		case errors.Is(err, shorturl.ErrShortURLNotFound):
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		case errors.Is(err, shorturl.ErrShortURLExpired), errors.Is(err, shorturl.ErrClickLimitExceeded),
+			errors.Is(err, shorturl.ErrShortURLArchived):
+			w.WriteHeader(http.StatusGone)
+
+			return
+		case errors.Is(err, shorturl.ErrShortURLPaused):
+			w.WriteHeader(http.StatusServiceUnavailable)
+
+			return
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// UnlockShortURL godoc
+//
+//	@Summary      Unlock a password protected short URL
+//	@Description  Exchange the password of a short URL for a time-limited token to follow its redirect
+//	@Tags         short-url, public
+//	@Accept       json
+//	@Produce      json
+//	@Param        shortURLId             path string                true "Short URL id to unlock"
+//	@Param        UnlockShortURLRequest  body UnlockShortURLRequest true "Password of the short URL"
+//	@Success      200 {object} UnlockShortURLResponse "Token unlocking the short URL"
+//	@Failure      400 {string} string "Invalid request or short URL not password protected"
+//	@Failure      401 {string} string "Invalid password"
+//	@Failure      404 {string} string "Short URL not found"
+//	@Failure      413 {string} string "Request body too large"
+//	@Failure      500 {string} string "Internal server error"
+//	@Router       /public/v1/short-urls/{shortURLId}/unlock [post]
+func (h *ShortURLHandler) UnlockShortURL(w http.ResponseWriter, r *http.Request) {
+	shortURLId := chi.URLParam(r, "shortURLId")
+	if shortURLId == "" {
+		http.Error(w, "short URL id is required", http.StatusBadRequest)
+
+		return
+	}
+
+	var request UnlockShortURLRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeDecodeError(w, err)
+
+		return
+	}
+
+	ctx := r.Context()
+	if err := h.shortURLManager.CheckPassword(ctx, shortURLId, request.Password); err != nil {
+		switch {
+		case errors.Is(err, shorturl.ErrShortURLNotFound):
+			http.Error(w, "", http.StatusNotFound)
+
+			return
+		case errors.Is(err, shorturl.ErrNotProtected):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+
+			return
+		case errors.Is(err, shorturl.ErrInvalidPassword):
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+
+			return
+		default:
+			http.Error(w, "failed to unlock short URL", http.StatusInternalServerError)
+
+			return
+		}
+	}
+
+	token, expiresAt, err := h.tokenSigner.Generate(tokenSubject(ctx, shortURLId))
+	if err != nil {
+		h.log(r.Context()).Error("failed to generate token", logging.ShortURLIdKey, shortURLId, logging.ErrorKey, err)
+		http.Error(w, "failed to unlock short URL", http.StatusInternalServerError)
+
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, &UnlockShortURLResponse{
+		Token:     token,
+		ExpiresAt: expiresAt,
+	})
+}
+
+// PreviewShortURL godoc
+//
+//	@Summary      Preview a short URL
+//	@Description  Get the long URL for the given short URL id without being redirected. Expiring short URLs tell the
+//	@Description  time they have left in the X-Short-URL-TTL and X-Short-URL-Expires-At headers.
+//	@Tags         short-url, public
+//	@Accept       json
+//	@Produce      json
+//	@Param        shortURLId     path   string true  "Short URL id to preview"
+//	@Param        If-None-Match  header string false "ETag of a previously retrieved preview"
+//	@Success      200 {object} ShortURLPreviewResponse "Short URL preview"
+//	@Success      304 {string} string "Preview not modified"
+//	@Failure      400 {string} string "Invalid short URL id"
+//	@Failure      404 {string} string "Short URL not found"
+//	@Failure      500 {string} string "Internal server error"
+//	@Router       /public/v1/short-urls/{shortURLId}/preview [get]
+func (h *ShortURLHandler) PreviewShortURL(w http.ResponseWriter, r *http.Request) {
+	shortURLId := chi.URLParam(r, "shortURLId")
+	if shortURLId == "" {
+		http.Error(w, "short URL id is required", http.StatusBadRequest)
+
+		return
+	}
+
+	ctx := r.Context()
+	shortURL, err := h.shortURLManager.GetShortURL(ctx, shortURLId)
+	if err != nil {
+		switch {
+		case errors.Is(err, shorturl.ErrShortURLNotFound):
+			http.Error(w, "", http.StatusNotFound)
+
+			return
+		default:
+			http.Error(w, "failed to retrieve long URL", http.StatusInternalServerError)
+
+			return
+		}
+	}
+
+	response, err := json.Marshal(NewShortURLPreviewResponse(shortURL))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	setExpiryHeaders(w, shortURL.ExpiresAt)
+
+	// The ETag changes with any attribute of the preview, and only with those, so the long URL of a protected short
+	// URL does not leak through it
+	etag := md5.Sum(response)
+	if err := writeETagResponse(w, r, hex.EncodeToString(etag[:]), response); err != nil {
+		h.log(r.Context()).Error("failed to write response", logging.ErrorKey, err)
+
+		return
+	}
+}
+
+// GetShortURLMetrics godoc
+//
+//	@Summary      Get short URL metrics
+//	@Description  Get metrics for a short URL within a specified time range
+//	@Tags         short-url, private
+//	@Accept       json
+//	@Produce      json
+//	@Param        shortURLId  path string true "Short URL id to get metrics for"
+//	@Param        from        query string true "Start time for metrics (RFC3339 format)"
+//	@Param        to          query string true "End time for metrics (RFC3339 format)"
+//	@Success      200 {object} ShortURLMetricsResponse "Short URL metrics"
+//	@Failure      400 {string} string "Invalid request parameters"
+//	@Failure      404 {string} string "Metrics not found"
+//	@Failure      413 {string} string "Request body too large"
+//	@Failure      500 {string} string "Internal server error"
+//	@Router       /private/v1/short-urls/{shortURLId}/metrics [get]
+func (h *ShortURLHandler) GetShortURLMetrics(w http.ResponseWriter, r *http.Request) {
+	shortURLId := chi.URLParam(r, "shortURLId")
+	if shortURLId == "" {
+		http.Error(w, "short URL id is required", http.StatusBadRequest)
+
+		return
+	}
+
+	var request ShortURLMetricsRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeDecodeError(w, err)
+
+		return
+	}
+
+	if err := h.config.validateTimeRange(request.From, request.To); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	ctx := r.Context()
+	shortURL, err := h.shortURLManager.GetShortURL(ctx, shortURLId)
+	if err != nil {
+		switch {
+		case errors.Is(err, shorturl.ErrShortURLNotFound):
+			http.Error(w, "", http.StatusNotFound)
+
+			return
+		default:
+			http.Error(w, "failed to retrieve short URL", http.StatusInternalServerError)
+
+			return
+		}
+	}
+
+	metricsResult, err := h.metricsManager.GetShortURLMetrics(ctx, shortURLId, request.From, request.To)
+	if err != nil {
+		http.Error(w, "failed to retrieve metrics", http.StatusInternalServerError)
+
+		return
+	}
+
+	response, err := json.Marshal(NewShortURLMetricsResponse(metricsResult, shortURL))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(response); err != nil {
+		h.log(r.Context()).Error("failed to write response", logging.ErrorKey, err)
+
+		return
+	}
+}
+
+// ExportShortURLMetrics godoc
+//
+//	@Summary      Export short URL metrics
+//	@Description  Export the metrics of a short URL within a specified time range as a CSV, one row per interval
+//	@Description  oldest first. Rolled up periods are exported as one row at the start of the period.
+//	@Tags         short-url, private
+//	@Produce      text/csv
+//	@Param        shortURLId  path  string true  "Short URL id to export metrics for"
+//	@Param        from        query string true  "Start time for metrics (RFC3339 format)"
+//	@Param        to          query string true  "End time for metrics (RFC3339 format)"
+//	@Param        format      query string false "Export format, only csv is supported (default csv)"
+//	@Success      200 {string} string "CSV with timestamp, visits and unique_visits columns"
+//	@Failure      400 {string} string "Invalid request parameters"
+//	@Failure      404 {string} string "Short URL not found"
+//	@Failure      500 {string} string "Internal server error"
+//	@Router       /private/v1/short-urls/{shortURLId}/metrics/export [get]
+func (h *ShortURLHandler) ExportShortURLMetrics(w http.ResponseWriter, r *http.Request) {
+	shortURLId := chi.URLParam(r, "shortURLId")
+	if shortURLId == "" {
+		http.Error(w, "short URL id is required", http.StatusBadRequest)
+
+		return
+	}
+
+	query := r.URL.Query()
+	if format := query.Get("format"); format != "" && format != "csv" {
+		http.Error(w, "unsupported export format", http.StatusBadRequest)
+
+		return
+	}
+
+	from, err := time.Parse(time.RFC3339, query.Get("from"))
+	if err != nil {
+		http.Error(w, "invalid from", http.StatusBadRequest)
+
+		return
+	}
+
+	to, err := time.Parse(time.RFC3339, query.Get("to"))
+	if err != nil {
+		http.Error(w, "invalid to", http.StatusBadRequest)
+
+		return
+	}
+
+	if err := h.config.validateTimeRange(from, to); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	ctx := r.Context()
+	if _, err := h.shortURLManager.GetShortURL(ctx, shortURLId); err != nil {
+		switch {
+		case errors.Is(err, shorturl.ErrShortURLNotFound):
+			http.Error(w, "", http.StatusNotFound)
+
+			return
+		default:
+			http.Error(w, "failed to retrieve short URL", http.StatusInternalServerError)
+
+			return
+		}
+	}
+
+	// Exports of long time ranges outlive the server write timeout, an export whose write deadline cannot be cleared
+	// would be cut mid-body after its 200 status was sent, so it is not started
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Time{}); err != nil {
+		h.log(r.Context()).Error("failed to clear metrics export write deadline", logging.ShortURLIdKey, shortURLId, logging.ErrorKey, err)
+		http.Error(w, "failed to export metrics", http.StatusInternalServerError)
+
+		return
+	}
+
+	// The response is only started with the first interval, so failing to query storage can still be reported
+	writer := csv.NewWriter(w)
+	started := false
+	start := func() error {
+		if started {
+			return nil
+		}
+		started = true
+
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="metrics-%s.csv"`, shortURLId))
+		w.WriteHeader(http.StatusOK)
+
+		return writer.Write([]string{"timestamp", "visits", "unique_visits"})
+	}
+
+	err = h.metricsManager.StreamShortURLMetrics(ctx, shortURLId, from, to, func(interval *metrics.Interval) error {
+		if err := start(); err != nil {
+			return err
+		}
+
+		return writer.Write([]string{
+			interval.Timestamp.UTC().Format(time.RFC3339),
+			strconv.FormatInt(interval.Visits, 10),
+			strconv.FormatInt(interval.UniqueVisits, 10),
+		})
+	})
+	if err == nil {
+		err = start()
+	}
+	if err != nil {
+		if !started {
+			http.Error(w, "failed to retrieve metrics", http.StatusInternalServerError)
+
+			return
+		}
+
+		h.log(r.Context()).Error("failed to write metrics export", logging.ShortURLIdKey, shortURLId, logging.ErrorKey, err)
+
+		return
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		h.log(r.Context()).Error("failed to write metrics export", logging.ShortURLIdKey, shortURLId, logging.ErrorKey, err)
+
+		return
+	}
+}
+
+// StreamShortURLClicks godoc
+//
+//	@Summary      Stream short URL clicks
+//	@Description  Stream the clicks of a short URL as Server-Sent Events while they are collected. Each click is a
+//	@Description  click event with the short URL id, the visitor IP and the click time as data. A heartbeat event is
+//	@Description  sent every heartbeat interval, 30 seconds by default. Repeated visits within the visit window are not
+//	@Description  streamed.
+//	@Tags         short-url, private
+//	@Produce      text/event-stream
+//	@Param        shortURLId  path  string true  "Short URL id to stream clicks of"
+//	@Success      200 {object} ClickEvent
+//	@Failure      400 {string} string "Short URL id is required"
+//	@Failure      404 {string} string "Short URL not found"
+//	@Failure      500 {string} string "Internal server error"
+//	@Router       /private/v1/short-urls/{shortURLId}/stream [get]
+func (h *ShortURLHandler) StreamShortURLClicks(w http.ResponseWriter, r *http.Request) {
+	shortURLId := chi.URLParam(r, "shortURLId")
+	if shortURLId == "" {
+		http.Error(w, "short URL id is required", http.StatusBadRequest)
+
+		return
+	}
+
+	ctx := r.Context()
+	if _, err := h.shortURLManager.GetShortURL(ctx, shortURLId); err != nil {
+		switch {
+		case errors.Is(err, shorturl.ErrShortURLNotFound):
+			http.Error(w, "", http.StatusNotFound)
+
+			return
+		default:
+			http.Error(w, "failed to retrieve short URL", http.StatusInternalServerError)
+
+			return
+		}
+	}
+
+	events, unsubscribe := h.metricsManager.Subscribe(ctx, shortURLId)
+	defer unsubscribe()
+
+	// The stream outlives the server write timeout, it ends when the client goes away. A stream whose write deadline
+	// cannot be cleared would be cut by the write timeout, so it is not started.
+	controller := http.NewResponseController(w)
+	if err := controller.SetWriteDeadline(time.Time{}); err != nil {
+		h.log(r.Context()).Error("failed to clear click stream write deadline", logging.ShortURLIdKey, shortURLId, logging.ErrorKey, err)
+		http.Error(w, "failed to start click stream", http.StatusInternalServerError)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	if err := controller.Flush(); err != nil {
+		h.log(r.Context()).Error("failed to start click stream", logging.ShortURLIdKey, shortURLId, logging.ErrorKey, err)
+
+		return
+	}
+
+	heartbeat := time.NewTicker(time.Duration(h.config.StreamHeartbeatIntervalInMS) * time.Millisecond)
+	defer heartbeat.Stop()
+
+	for {
+		var err error
+		select {
+		case event := <-events:
+			err = writeServerSentEvent(w, "click", &ClickEvent{Id: event.ShortURLId, IP: event.IP, Timestamp: event.Timestamp})
+		case <-heartbeat.C:
+			err = writeServerSentEvent(w, "heartbeat", struct{}{})
+		case <-ctx.Done():
+			return
+		}
+		if err == nil {
+			err = controller.Flush()
+		}
+		if err != nil {
+			h.log(r.Context()).Error("failed to write click stream", logging.ShortURLIdKey, shortURLId, logging.ErrorKey, err)
+
+			return
+		}
+	}
+}
+
+// writeServerSentEvent writes an event of the given type with data encoded as JSON
+func writeServerSentEvent(w http.ResponseWriter, event string, data any) error {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, encoded)
+
+	return err
+}
+
+// GetShortURLLatency godoc
+//
+//	@Summary      Get short URL redirect latency
+//	@Description  Get the p50, p95 and p99 latency of the redirects of a short URL within a specified time range, one
+//	@Description  interval per metrics flush oldest first
+//	@Tags         short-url, private
+//	@Produce      json
+//	@Param        shortURLId  path  string true "Short URL id to get the latency for"
+//	@Param        from        query string true "Start time for latency (RFC3339 format)"
+//	@Param        to          query string true "End time for latency (RFC3339 format)"
+//	@Success      200 {object} ShortURLLatencyResponse "Short URL redirect latency"
+//	@Failure      400 {string} string "Invalid request parameters"
+//	@Failure      404 {string} string "Short URL not found"
+//	@Failure      500 {string} string "Internal server error"
+//	@Router       /private/v1/short-urls/{shortURLId}/latency [get]
+func (h *ShortURLHandler) GetShortURLLatency(w http.ResponseWriter, r *http.Request) {
+	shortURLId := chi.URLParam(r, "shortURLId")
+	if shortURLId == "" {
+		http.Error(w, "short URL id is required", http.StatusBadRequest)
+
+		return
+	}
+
+	query := r.URL.Query()
+	from, err := time.Parse(time.RFC3339, query.Get("from"))
+	if err != nil {
+		http.Error(w, "invalid from", http.StatusBadRequest)
+
+		return
+	}
+
+	to, err := time.Parse(time.RFC3339, query.Get("to"))
+	if err != nil {
+		http.Error(w, "invalid to", http.StatusBadRequest)
+
+		return
+	}
+
+	if err := h.config.validateTimeRange(from, to); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	ctx := r.Context()
+	if _, err := h.shortURLManager.GetShortURL(ctx, shortURLId); err != nil {
+		switch {
+		case errors.Is(err, shorturl.ErrShortURLNotFound):
+			http.Error(w, "", http.StatusNotFound)
+
+			return
+		default:
+			http.Error(w, "failed to retrieve short URL", http.StatusInternalServerError)
+
+			return
+		}
+	}
+
+	latencies, err := h.metricsManager.GetShortURLLatency(ctx, shortURLId, from, to)
+	if err != nil {
+		http.Error(w, "failed to retrieve latency", http.StatusInternalServerError)
+
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, NewShortURLLatencyResponse(shortURLId, from, to, latencies))
+}
+
+// GetDashboard godoc
+//
+//	@Summary      Get the analytics dashboard
+//	@Description  Get the short URLs created, the redirects and unique visitors, and the top 5 short URLs by visits and
+//	@Description  by unique visitors within a specified time range. Stats are cached for up to a minute.
+//	@Tags         analytics, private
+//	@Produce      json
+//	@Param        from  query string true "Start time of the stats (RFC3339 format)"
+//	@Param        to    query string true "End time of the stats (RFC3339 format)"
+//	@Success      200 {object} DashboardResponse "Dashboard stats"
+//	@Failure      400 {string} string "Invalid request parameters"
+//	@Failure      500 {string} string "Internal server error"
+//	@Router       /private/v1/analytics/dashboard [get]
+func (h *ShortURLHandler) GetDashboard(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	from, err := time.Parse(time.RFC3339, query.Get("from"))
+	if err != nil {
+		http.Error(w, "invalid from", http.StatusBadRequest)
+
+		return
+	}
+
+	to, err := time.Parse(time.RFC3339, query.Get("to"))
+	if err != nil {
+		http.Error(w, "invalid to", http.StatusBadRequest)
+
+		return
+	}
+
+	if err := h.config.validateTimeRange(from, to); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	ctx := r.Context()
+	stats, err := h.shortURLManager.GetDashboardStats(ctx, from, to)
+	if err != nil {
+		http.Error(w, "failed to retrieve dashboard stats", http.StatusInternalServerError)
+
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, NewDashboardResponse(stats, h.requestBaseURL(r, tenant.IDFromContext(ctx))))
+}
+
+// GetMetricsSnapshot godoc
+//
+//	@Summary      Get the unflushed metrics
+//	@Description  Get the metrics collected in memory since the last flush to storage, keyed by short URL id
+//	@Tags         metrics, private
+//	@Produce      json
+//	@Success      200 {object} MetricsSnapshotResponse "Metrics collected since the last flush"
+//	@Failure      500 {string} string "Internal server error"
+//	@Failure      503 {string} string "Metrics manager is stopped"
+//	@Router       /private/v1/metrics/snapshot [get]
+func (h *ShortURLHandler) GetMetricsSnapshot(w http.ResponseWriter, r *http.Request) {
+	snapshot, err := h.metricsManager.Snapshot(r.Context())
+	if err != nil {
+		switch {
+		case errors.Is(err, metrics.ErrManagerStopped):
+			http.Error(w, "metrics manager is stopped", http.StatusServiceUnavailable)
+
+			return
+		default:
+			http.Error(w, "failed to retrieve metrics snapshot", http.StatusInternalServerError)
+
+			return
+		}
+	}
+
+	h.writeJSON(w, http.StatusOK, NewMetricsSnapshotResponse(snapshot))
+}
+
+// DrainMetrics godoc
+//
+//	@Summary      Flush the unflushed metrics
+//	@Description  Flush the metrics collected in memory to storage without waiting for the next flush interval
+//	@Tags         metrics, private
+//	@Success      200 "Metrics flushed"
+//	@Failure      500 {string} string "Internal server error"
+//	@Failure      503 {string} string "Metrics manager is stopped"
+//	@Router       /private/v1/metrics/drain [post]
+func (h *ShortURLHandler) DrainMetrics(w http.ResponseWriter, r *http.Request) {
+	if err := h.metricsManager.Drain(r.Context()); err != nil {
+		switch {
+		case errors.Is(err, metrics.ErrManagerStopped):
+			http.Error(w, "metrics manager is stopped", http.StatusServiceUnavailable)
+
+			return
+		default:
+			http.Error(w, "failed to drain metrics", http.StatusInternalServerError)
+
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// GetMetricsBulk godoc
+//
+//	@Summary      Get the metrics of many short URLs
+//	@Description  Get the metrics of many short URLs within a specified time range at once. Short URLs without visits,
+//	@Description  or that do not exist, get zero metrics.
+//	@Tags         metrics, private
+//	@Accept       json
+//	@Produce      json
+//	@Param        request  body MetricsBulkRequest true "Short URL ids, at most 100, and time range to get metrics for"
+//	@Success      200 {object} MetricsBulkResponse "Metrics by short URL id"
+//	@Failure      400 {string} string "Invalid request parameters"
+//	@Failure      413 {string} string "Request body too large"
+//	@Failure      500 {string} string "Internal server error"
+//	@Router       /private/v1/metrics/bulk [post]
+func (h *ShortURLHandler) GetMetricsBulk(w http.ResponseWriter, r *http.Request) {
+	var request MetricsBulkRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeDecodeError(w, err)
+
+		return
+	}
+
+	if len(request.Ids) == 0 {
+		http.Error(w, "ids are required", http.StatusBadRequest)
+
+		return
+	}
+	if len(request.Ids) > maxBulkMetricsIds {
+		http.Error(w, fmt.Sprintf("at most %d ids can be asked for at once", maxBulkMetricsIds), http.StatusBadRequest)
+
+		return
+	}
+
+	if err := h.config.validateTimeRange(request.From, request.To); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	bulk, err := h.metricsManager.GetShortURLMetricsBulk(r.Context(), request.Ids, request.From, request.To)
+	if err != nil {
+		http.Error(w, "failed to retrieve metrics", http.StatusInternalServerError)
+
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, NewMetricsBulkResponse(bulk))
+}
+
+// GetShortURLAuditLog godoc
+//
+//	@Summary      Get the audit log of a short URL
+//	@Description  Get the operations made on a short URL, oldest first
+//	@Tags         short-url, private
+//	@Accept       json
+//	@Produce      json
+//	@Param        shortURLId  path  string true  "Short URL id to get the audit log for"
+//	@Param        limit       query int    false "Maximum number of entries to return (default 100, max 1000)"
+//	@Param        offset      query int    false "Number of entries to skip"
+//	@Success      200 {object} AuditLogResponse "Audit log entries"
+//	@Failure      400 {string} string "Invalid request parameters"
+//	@Failure      500 {string} string "Internal server error"
+//	@Router       /private/v1/short-urls/{shortURLId}/audit [get]
+func (h *ShortURLHandler) GetShortURLAuditLog(w http.ResponseWriter, r *http.Request) {
+	shortURLId := chi.URLParam(r, "shortURLId")
+	if shortURLId == "" {
+		http.Error(w, "short URL id is required", http.StatusBadRequest)
+
+		return
+	}
+
+	query := r.URL.Query()
+
+	limit, err := parseIntQueryParam(query.Get("limit"), defaultListLimit)
+	if err != nil || limit <= 0 || limit > maxListLimit {
+		http.Error(w, "invalid limit", http.StatusBadRequest)
+
+		return
+	}
+
+	offset, err := parseIntQueryParam(query.Get("offset"), 0)
+	if err != nil || offset < 0 {
+		http.Error(w, "invalid offset", http.StatusBadRequest)
+
+		return
+	}
+
+	ctx := r.Context()
+	entries, err := h.shortURLManager.GetAuditLog(ctx, &shorturl.AuditFilter{
+		ShortURLId: shortURLId,
+		Limit:      limit,
+		Offset:     offset,
+	})
+	if err != nil {
+		http.Error(w, "failed to retrieve audit log", http.StatusInternalServerError)
+
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, NewAuditLogResponse(entries))
+}
+
+// RegisterWebhook godoc
+//
+//	@Summary      Register a webhook
+//	@Description  Register a webhook notified with a signed POST every time the short URL is followed
+//	@Tags         short-url, private
+//	@Accept       json
+//	@Produce      json
+//	@Param        shortURLId     path string        true "Short URL id to register the webhook for"
+//	@Param        WebhookConfig  body WebhookConfig true "Webhook URL and the secret used to sign its payloads"
+//	@Success      201 {object} WebhookResponse "Registered webhook"
+//	@Failure      400 {string} string "Invalid webhook"
+//	@Failure      404 {string} string "Short URL not found"
+//	@Failure      413 {string} string "Request body too large"
+//	@Failure      500 {string} string "Internal server error"
+//	@Router       /private/v1/short-urls/{shortURLId}/webhooks [post]
+func (h *ShortURLHandler) RegisterWebhook(w http.ResponseWriter, r *http.Request) {
+	shortURLId := chi.URLParam(r, "shortURLId")
+	if shortURLId == "" {
+		http.Error(w, "short URL id is required", http.StatusBadRequest)
+
+		return
+	}
+
+	var request WebhookConfig
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeDecodeError(w, err)
+
+		return
+	}
+
+	ctx := r.Context()
+	if _, err := h.shortURLManager.GetShortURL(ctx, shortURLId); err != nil {
+		switch {
+		case errors.Is(err, shorturl.ErrShortURLNotFound):
+			http.Error(w, "", http.StatusNotFound)
+
+			return
+		default:
+			http.Error(w, "failed to retrieve short URL", http.StatusInternalServerError)
+
+			return
+		}
+	}
+
+	createdWebhook, err := h.webhookManager.RegisterWebhook(ctx, shortURLId, request.URL, request.Secret)
+	if err != nil {
+		switch {
+		case errors.Is(err, webhook.ErrInvalidWebhook):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+
+			return
+		default:
+			http.Error(w, "failed to register webhook", http.StatusInternalServerError)
+
+			return
+		}
+	}
+
+	response, err := json.Marshal(NewWebhookResponse(createdWebhook))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if _, err := w.Write(response); err != nil {
+		h.log(r.Context()).Error("failed to write response", logging.ErrorKey, err)
+
+		return
+	}
+}
+
+// DeleteWebhook godoc
+//
+//	@Summary      Delete a webhook
+//	@Description  Delete a webhook registered for a short URL
+//	@Tags         short-url, private
+//	@Accept       json
+//	@Produce      json
+//	@Param        shortURLId  path string true "Short URL id the webhook is registered for"
+//	@Param        webhookId   path int    true "Webhook id to be deleted"
+//	@Success      200 {string} string "Webhook deleted successfully"
+//	@Failure      400 {string} string "Invalid short URL or webhook id"
+//	@Failure      404 {string} string "Webhook not found"
+//	@Failure      500 {string} string "Internal server error"
+//	@Router       /private/v1/short-urls/{shortURLId}/webhooks/{webhookId} [delete]
+func (h *ShortURLHandler) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	shortURLId := chi.URLParam(r, "shortURLId")
+	if shortURLId == "" {
+		http.Error(w, "short URL id is required", http.StatusBadRequest)
+
+		return
+	}
+
+	webhookId, err := strconv.ParseInt(chi.URLParam(r, "webhookId"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid webhook id", http.StatusBadRequest)
+
+		return
+	}
+
+	ctx := r.Context()
+	if err := h.webhookManager.DeleteWebhook(ctx, shortURLId, webhookId); err != nil {
+		switch {
+		case errors.Is(err, webhook.ErrWebhookNotFound):
+			http.Error(w, "", http.StatusNotFound)
+
+			return
+		default:
+			http.Error(w, "failed to delete webhook", http.StatusInternalServerError)
+
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// PauseShortURL godoc
+//
+//	@Summary      Pause a short URL
+//	@Description  Temporarily disable the redirect of an active short URL until it is resumed
+//	@Tags         short-url, private
+//	@Accept       json
+//	@Produce      json
+//	@Param        shortURLId  path   string true  "Short URL id to be paused"
+//	@Param        X-Actor     header string false "Actor recorded in the audit log"
+//	@Success      200 {string} string "Short URL paused successfully"
+//	@Failure      400 {string} string "Invalid short URL id"
+//	@Failure      404 {string} string "Short URL not found"
+//	@Failure      409 {string} string "Short URL is not active"
+//	@Failure      500 {string} string "Internal server error"
+//	@Router       /private/v1/short-urls/{shortURLId}/pause [post]
+func (h *ShortURLHandler) PauseShortURL(w http.ResponseWriter, r *http.Request) {
+	h.updateShortURLStatus(w, r, shorturl.StatusPaused)
+}
+
+// ResumeShortURL godoc
+//
+//	@Summary      Resume a short URL
+//	@Description  Enable the redirect of a paused short URL again
+//	@Tags         short-url, private
+//	@Accept       json
+//	@Produce      json
+//	@Param        shortURLId  path   string true  "Short URL id to be resumed"
+//	@Param        X-Actor     header string false "Actor recorded in the audit log"
+//	@Success      200 {string} string "Short URL resumed successfully"
+//	@Failure      400 {string} string "Invalid short URL id"
+//	@Failure      404 {string} string "Short URL not found"
+//	@Failure      409 {string} string "Short URL is not paused"
+//	@Failure      500 {string} string "Internal server error"
+//	@Router       /private/v1/short-urls/{shortURLId}/resume [post]
+func (h *ShortURLHandler) ResumeShortURL(w http.ResponseWriter, r *http.Request) {
+	h.updateShortURLStatus(w, r, shorturl.StatusActive)
+}
+
+// ArchiveShortURL godoc
+//
+//	@Summary      Archive a short URL
+//	@Description  Permanently disable the redirect of an active or paused short URL, archived short URLs cannot be resumed
+//	@Tags         short-url, private
+//	@Accept       json
+//	@Produce      json
+//	@Param        shortURLId  path   string true  "Short URL id to be archived"
+//	@Param        X-Actor     header string false "Actor recorded in the audit log"
+//	@Success      200 {string} string "Short URL archived successfully"
+//	@Failure      400 {string} string "Invalid short URL id"
+//	@Failure      404 {string} string "Short URL not found"
+//	@Failure      409 {string} string "Short URL is already archived"
+//	@Failure      500 {string} string "Internal server error"
+//	@Router       /private/v1/short-urls/{shortURLId}/archive [post]
+func (h *ShortURLHandler) ArchiveShortURL(w http.ResponseWriter, r *http.Request) {
+	h.updateShortURLStatus(w, r, shorturl.StatusArchived)
+}
+
+// updateShortURLStatus moves the short URL of the request to the given status
+func (h *ShortURLHandler) updateShortURLStatus(w http.ResponseWriter, r *http.Request, status shorturl.Status) {
+	shortURLId := chi.URLParam(r, "shortURLId")
+	if shortURLId == "" {
+		http.Error(w, "short URL id is required", http.StatusBadRequest)
+
+		return
+	}
+
+	if err := h.shortURLManager.UpdateShortURLStatus(actorContext(r), shortURLId, status); err != nil {
+		switch {
+		case errors.Is(err, shorturl.ErrShortURLNotFound):
+			http.Error(w, "", http.StatusNotFound)
+
+			return
+		case errors.Is(err, shorturl.ErrInvalidStatusTransition):
+			http.Error(w, err.Error(), http.StatusConflict)
+
+			return
+		default:
+			http.Error(w, "failed to update short URL status", http.StatusInternalServerError)
+
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// CreateAlias godoc
+//
+//	@Summary      Create an alias
+//	@Description  Create an alias id that redirects like the short URL, so links to a retired short URL keep working
+//	@Tags         short-url, private
+//	@Accept       json
+//	@Produce      json
+//	@Param        shortURLId    path string       true "Short URL id the alias points to"
+//	@Param        AliasRequest  body AliasRequest true "Alias id"
+//	@Success      201 {object} AliasResponse "Created alias"
+//	@Failure      400 {string} string "Invalid alias id"
+//	@Failure      404 {string} string "Short URL not found"
+//	@Failure      409 {string} string "Alias id already in use"
+//	@Failure      413 {string} string "Request body too large"
+//	@Failure      500 {string} string "Internal server error"
+//	@Router       /private/v1/short-urls/{shortURLId}/aliases [post]
+func (h *ShortURLHandler) CreateAlias(w http.ResponseWriter, r *http.Request) {
+	shortURLId := chi.URLParam(r, "shortURLId")
+	if shortURLId == "" {
+		http.Error(w, "short URL id is required", http.StatusBadRequest)
+
+		return
+	}
+
+	var request AliasRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeDecodeError(w, err)
+
+		return
+	}
+
+	ctx := r.Context()
+	if err := h.shortURLManager.CreateAlias(ctx, shortURLId, request.AliasId); err != nil {
+		switch {
+		case errors.Is(err, shorturl.ErrInvalidAliasId):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+
+			return
+		case errors.Is(err, shorturl.ErrShortURLNotFound):
+			http.Error(w, "", http.StatusNotFound)
+
+			return
+		case errors.Is(err, shorturl.ErrAliasExists), errors.Is(err, shorturl.ErrShortURLExists):
+			http.Error(w, "alias id already in use", http.StatusConflict)
+
+			return
+		default:
+			http.Error(w, "failed to create alias", http.StatusInternalServerError)
+
+			return
+		}
+	}
+
+	h.writeJSON(w, http.StatusCreated, NewAliasResponse(request.AliasId, shortURLId, h.requestBaseURL(r, tenant.IDFromContext(ctx))))
+}
+
+// DeleteAlias godoc
+//
+//	@Summary      Delete an alias
+//	@Description  Delete an alias of a short URL, it stops redirecting
+//	@Tags         short-url, private
+//	@Accept       json
+//	@Produce      json
+//	@Param        shortURLId  path string true "Short URL id the alias points to"
+//	@Param        aliasId     path string true "Alias id to be deleted"
+//	@Success      200 {string} string "Alias deleted successfully"
+//	@Failure      400 {string} string "Invalid short URL or alias id"
+//	@Failure      404 {string} string "Alias not found"
+//	@Failure      500 {string} string "Internal server error"
+//	@Router       /private/v1/short-urls/{shortURLId}/aliases/{aliasId} [delete]
+func (h *ShortURLHandler) DeleteAlias(w http.ResponseWriter, r *http.Request) {
+	shortURLId := chi.URLParam(r, "shortURLId")
+	if shortURLId == "" {
+		http.Error(w, "short URL id is required", http.StatusBadRequest)
+
+		return
+	}
+
+	aliasId := chi.URLParam(r, "aliasId")
+	if aliasId == "" {
+		http.Error(w, "alias id is required", http.StatusBadRequest)
+
+		return
+	}
+
+	if err := h.shortURLManager.DeleteAlias(r.Context(), shortURLId, aliasId); err != nil {
+		switch {
+		case errors.Is(err, shorturl.ErrAliasNotFound):
+			http.Error(w, "", http.StatusNotFound)
+
+			return
+		default:
+			http.Error(w, "failed to delete alias", http.StatusInternalServerError)
+
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// appendQueryParams appends params to the query string of longURL, keeping the parameters it already has
+func appendQueryParams(longURL string, params url.Values) (string, error) {
+	if len(params) == 0 {
+		return longURL, nil
+	}
+
+	parsedURL, err := url.Parse(longURL)
+	if err != nil {
+		return "", fmt.Errorf("parsing long URL: %w", err)
+	}
+
+	if parsedURL.RawQuery == "" {
+		parsedURL.RawQuery = params.Encode()
+	} else {
+		parsedURL.RawQuery += "&" + params.Encode()
+	}
+
+	return parsedURL.String(), nil
+}
+
+// requestBaseURL returns the base URL asked for with the BaseURLHeader, or the configured base URL of the tenant when
+// the header is not set or asks for a base URL that is not allowed
+func (h *ShortURLHandler) requestBaseURL(r *http.Request, tenantID string) string {
+	baseURL := r.Header.Get(BaseURLHeader)
+	if baseURL == "" {
+		return h.config.baseURL(tenantID)
+	}
+	if !validBaseURL(baseURL) || !h.config.allowedBaseURL(baseURL) {
+		h.log(r.Context()).Warn("base URL is not allowed, using the default", logging.BaseURLKey, baseURL)
+
+		return h.config.baseURL(tenantID)
+	}
+
+	return baseURL
+}
+
+// actorContext returns the request context carrying the actor of the request, taken from the ActorHeader
+func actorContext(r *http.Request) context.Context {
+	return shorturl.WithActor(r.Context(), r.Header.Get(ActorHeader))
+}
+
+// tokenSubject returns the subject of the tokens unlocking the given short URL, short URL ids are only unique
+// within a tenant so the token of a tenant must not unlock the short URL with the same id of another one
+func tokenSubject(ctx context.Context, shortURLId string) string {
+	tenantID := tenant.IDFromContext(ctx)
+	if tenantID == tenant.Default {
+		return shortURLId
+	}
+
+	return tenantID + "/" + shortURLId
+}
+
+// setExpiryHeaders sets the TTLHeader and ExpiresAtHeader of a short URL expiring at expiresAt, nothing is set for
+// short URLs that never expire
+func setExpiryHeaders(w http.ResponseWriter, expiresAt *time.Time) {
+	if expiresAt == nil {
+		return
+	}
+
+	ttl := max(0, expiresAt.Unix()-time.Now().Unix())
+	w.Header().Set(TTLHeader, strconv.FormatInt(ttl, 10))
+	w.Header().Set(ExpiresAtHeader, expiresAt.UTC().Format(time.RFC3339))
+}
+
+// writeJSON writes the JSON encoding of body as the response with the given status
+func (h *ShortURLHandler) writeJSON(w http.ResponseWriter, status int, body any) {
+	writeJSON(w, h.logger, status, body)
+}
+
+// writeJSON writes body encoded as JSON with the given status, failing to write it is logged
+func writeJSON(w http.ResponseWriter, logger Logger, status int, body any) {
+	response, err := json.Marshal(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if _, err := w.Write(response); err != nil {
+		logger.Error("failed to write response", logging.ErrorKey, err)
+
+		return
+	}
+}
+
+// writeDecodeError writes the error response for a request body that could not be decoded
+func writeDecodeError(w http.ResponseWriter, err error) {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		http.Error(w, "request body too large", http.StatusRequestEntityTooLarge)
+
+		return
+	}
+
+	http.Error(w, err.Error(), http.StatusBadRequest)
+}
+
+// writeETagResponse writes body as a JSON response tagged with etag, or a 304 with no body when
+// the request If-None-Match header already matches it
+func writeETagResponse(w http.ResponseWriter, r *http.Request, etag string, body []byte) error {
+	quotedETag := `"` + etag + `"`
+	w.Header().Set("ETag", quotedETag)
+
+	if etagMatches(r.Header.Get("If-None-Match"), quotedETag) {
+		w.WriteHeader(http.StatusNotModified)
+
+		return nil
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, err := w.Write(body)
+
+	return err
+}
+
+func etagMatches(ifNoneMatch string, quotedETag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == quotedETag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseIntQueryParam parses an optional integer query parameter, returning defaultValue when it is not set
+func parseIntQueryParam(value string, defaultValue int) (int, error) {
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	return strconv.Atoi(value)
 }
@@ -0,0 +1,2414 @@
+package handlers_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+
+	"github.com/AvalosM/short-url-service/internal/handlers"
+	"github.com/AvalosM/short-url-service/internal/handlers/mocks"
+	"github.com/AvalosM/short-url-service/internal/middleware"
+	"github.com/AvalosM/short-url-service/pkg/metrics"
+	"github.com/AvalosM/short-url-service/pkg/shorturl"
+	"github.com/AvalosM/short-url-service/pkg/tenant"
+	"github.com/AvalosM/short-url-service/pkg/webhook"
+)
+
+//go:generate mockgen -typed -package=mocks  -source=./handler.go -destination=./mocks/mocks.go
+
+type HandlerSuite struct {
+	suite.Suite
+	mockCtrl            *gomock.Controller
+	mockShortURLManager *mocks.MockShortURLManager
+	mockMetricsManager  *mocks.MockMetricsManager
+	mockWebhookManager  *mocks.MockWebhookManager
+	mockTokenSigner     *mocks.MockTokenSigner
+	mockLogger          *mocks.MockLogger
+	handler             *handlers.ShortURLHandler
+}
+
+func (suite *HandlerSuite) SetupTest() {
+	suite.mockCtrl = gomock.NewController(suite.T())
+	suite.mockShortURLManager = mocks.NewMockShortURLManager(suite.mockCtrl)
+	suite.mockMetricsManager = mocks.NewMockMetricsManager(suite.mockCtrl)
+	suite.mockWebhookManager = mocks.NewMockWebhookManager(suite.mockCtrl)
+	suite.mockTokenSigner = mocks.NewMockTokenSigner(suite.mockCtrl)
+	suite.mockLogger = mocks.NewMockLogger(suite.mockCtrl)
+
+	suite.mockLogger.EXPECT().Error(gomock.Any(), gomock.Any()).AnyTimes()
+	suite.mockLogger.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+	suite.mockLogger.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+	suite.mockLogger.EXPECT().Warn(gomock.Any(), gomock.Any()).AnyTimes()
+
+	handler, err := handlers.NewShortURLHandler(handlers.DefaultConfig(), suite.mockShortURLManager, suite.mockMetricsManager, suite.mockWebhookManager, suite.mockTokenSigner, suite.mockLogger)
+	suite.Require().NoError(err)
+
+	suite.handler = handler
+}
+
+func (suite *HandlerSuite) TearDownTest() {
+	suite.mockCtrl.Finish()
+}
+
+func TestHandlerSuite(t *testing.T) {
+	suite.Run(t, new(HandlerSuite))
+}
+
+func withURLParams(r *http.Request, params map[string]string) *http.Request {
+	routeContext := chi.NewRouteContext()
+	for key, value := range params {
+		routeContext.URLParams.Add(key, value)
+	}
+
+	return r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, routeContext))
+}
+
+// deadlineRecorder is a ResponseRecorder whose write deadline can be set, like the response writers of a server
+type deadlineRecorder struct {
+	*httptest.ResponseRecorder
+	deadlineCleared bool
+}
+
+func newDeadlineRecorder() *deadlineRecorder {
+	return &deadlineRecorder{ResponseRecorder: httptest.NewRecorder()}
+}
+
+func (r *deadlineRecorder) SetWriteDeadline(deadline time.Time) error {
+	r.deadlineCleared = deadline.IsZero()
+
+	return nil
+}
+
+func (suite *HandlerSuite) TestCreateShortURLSuccess() {
+	longURL := "https://example.com"
+
+	createdAt := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tags := []string{"campaign:summer2025"}
+
+	suite.mockShortURLManager.EXPECT().CreateShortURL(gomock.Any(), longURL, &shorturl.CreateOptions{Tags: tags}).
+		Return(&shorturl.ShortURL{Id: "AABBCC", LongURL: longURL, Tags: tags, CreatedAt: createdAt, UpdatedAt: createdAt}, nil)
+
+	request := httptest.NewRequest(http.MethodPost, "/private/v1/short-urls/",
+		strings.NewReader(`{"long_url":"https://example.com","tags":["campaign:summer2025"]}`))
+	response := httptest.NewRecorder()
+	suite.handler.CreateShortURL(response, request)
+
+	suite.Equal(http.StatusCreated, response.Code)
+	suite.JSONEq(`{
+		"id": "AABBCC",
+		"short_url": "http://localhost:8080/public/v1/short-urls/AABBCC",
+		"tags": ["campaign:summer2025"],
+		"created_at": "2025-06-01T12:00:00Z",
+		"updated_at": "2025-06-01T12:00:00Z"
+	}`, response.Body.String())
+}
+
+func (suite *HandlerSuite) TestCreateShortURLFailInvalidLongURL() {
+	suite.mockShortURLManager.EXPECT().CreateShortURL(gomock.Any(), "htp://bad", gomock.Any()).Return(nil, shorturl.ErrInvalidLongURL)
+
+	request := httptest.NewRequest(http.MethodPost, "/private/v1/short-urls/", strings.NewReader(`{"long_url":"htp://bad"}`))
+	response := httptest.NewRecorder()
+	suite.handler.CreateShortURL(response, request)
+
+	suite.Equal(http.StatusBadRequest, response.Code)
+	suite.Equal("application/json", response.Header().Get("Content-Type"))
+	suite.JSONEq(`{"code": "INVALID_URL", "message": "invalid long URL"}`, response.Body.String())
+}
+
+func (suite *HandlerSuite) TestCreateShortURLFailLongURLErrors() {
+	testCases := []struct {
+		name           string
+		err            error
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "too long",
+			err:            shorturl.ErrURLTooLong,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"code": "URL_TOO_LONG", "message": "invalid long URL: too long"}`,
+		},
+		{
+			name:           "denied",
+			err:            shorturl.ErrURLDenied,
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   `{"code": "URL_DENIED", "message": "long URL host is denied"}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			suite.mockShortURLManager.EXPECT().CreateShortURL(gomock.Any(), "https://example.com", gomock.Any()).Return(nil, tc.err)
+
+			request := httptest.NewRequest(http.MethodPost, "/private/v1/short-urls/", strings.NewReader(`{"long_url":"https://example.com"}`))
+			response := httptest.NewRecorder()
+			suite.handler.CreateShortURL(response, request)
+
+			suite.Equal(tc.expectedStatus, response.Code)
+			suite.JSONEq(tc.expectedBody, response.Body.String())
+		})
+	}
+}
+
+func (suite *HandlerSuite) TestCreateShortURLBaseURLHeader() {
+	config := handlers.DefaultConfig()
+	config.AllowedBaseURLs = []string{"https://sho.rt/"}
+	handler, err := handlers.NewShortURLHandler(config, suite.mockShortURLManager, suite.mockMetricsManager, suite.mockWebhookManager, suite.mockTokenSigner, suite.mockLogger)
+	suite.Require().NoError(err)
+
+	testCases := []struct {
+		name             string
+		baseURL          string
+		expectedShortURL string
+	}{
+		{
+			name:             "allowed base URL",
+			baseURL:          "https://sho.rt/",
+			expectedShortURL: "https://sho.rt/AABBCC",
+		},
+		{
+			name:             "base URL not allowed",
+			baseURL:          "https://evil.example/",
+			expectedShortURL: "http://localhost:8080/public/v1/short-urls/AABBCC",
+		},
+		{
+			name:             "invalid base URL",
+			baseURL:          "not a url",
+			expectedShortURL: "http://localhost:8080/public/v1/short-urls/AABBCC",
+		},
+		{
+			name:             "no base URL",
+			expectedShortURL: "http://localhost:8080/public/v1/short-urls/AABBCC",
+		},
+	}
+
+	for _, testCase := range testCases {
+		suite.Run(testCase.name, func() {
+			suite.mockShortURLManager.EXPECT().CreateShortURL(gomock.Any(), "https://example.com", gomock.Any()).
+				Return(&shorturl.ShortURL{Id: "AABBCC", LongURL: "https://example.com", CreatedAt: time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC),
+					UpdatedAt: time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)}, nil)
+
+			request := httptest.NewRequest(http.MethodPost, "/private/v1/short-urls/", strings.NewReader(`{"long_url":"https://example.com"}`))
+			if testCase.baseURL != "" {
+				request.Header.Set(handlers.BaseURLHeader, testCase.baseURL)
+			}
+			response := httptest.NewRecorder()
+			handler.CreateShortURL(response, request)
+
+			suite.Equal(http.StatusCreated, response.Code)
+			suite.JSONEq(`{
+				"id": "AABBCC",
+				"short_url": "`+testCase.expectedShortURL+`",
+				"tags": [],
+				"created_at": "2025-06-01T12:00:00Z",
+				"updated_at": "2025-06-01T12:00:00Z"
+			}`, response.Body.String())
+		})
+	}
+}
+
+func (suite *HandlerSuite) TestConfigValidateAllowedBaseURLs() {
+	config := handlers.DefaultConfig()
+	config.AllowedBaseURLs = []string{"https://sho.rt/"}
+	suite.NoError(config.Validate())
+
+	config.AllowedBaseURLs = []string{"sho.rt"}
+	suite.Error(config.Validate())
+}
+
+func (suite *HandlerSuite) TestCreateShortURLSuccessTenant() {
+	createdAt := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	suite.mockShortURLManager.EXPECT().CreateShortURL(gomock.Any(), "https://example.com", gomock.Any()).
+		Return(&shorturl.ShortURL{TenantId: "acme", Id: "AABBCC", LongURL: "https://example.com", CreatedAt: createdAt, UpdatedAt: createdAt}, nil)
+
+	request := httptest.NewRequest(http.MethodPost, "/private/v1/short-urls/", strings.NewReader(`{"long_url":"https://example.com"}`))
+	request = request.WithContext(tenant.WithID(request.Context(), "acme"))
+	response := httptest.NewRecorder()
+	suite.handler.CreateShortURL(response, request)
+
+	suite.Equal(http.StatusCreated, response.Code)
+	suite.JSONEq(`{
+		"id": "AABBCC",
+		"short_url": "http://localhost:8080/public/v1/tenants/acme/short-urls/AABBCC",
+		"tags": [],
+		"created_at": "2025-06-01T12:00:00Z",
+		"updated_at": "2025-06-01T12:00:00Z"
+	}`, response.Body.String())
+}
+
+func (suite *HandlerSuite) TestCreateShortURLFailInvalidTags() {
+	suite.mockShortURLManager.EXPECT().CreateShortURL(gomock.Any(), "https://example.com", gomock.Any()).
+		Return(nil, shorturl.ErrInvalidTags)
+
+	request := httptest.NewRequest(http.MethodPost, "/private/v1/short-urls/",
+		strings.NewReader(`{"long_url":"https://example.com","tags":["not valid"]}`))
+	response := httptest.NewRecorder()
+	suite.handler.CreateShortURL(response, request)
+
+	suite.Equal(http.StatusBadRequest, response.Code)
+}
+
+func (suite *HandlerSuite) TestCreateShortURLSuccessWithDescription() {
+	longURL := "https://example.com"
+	createdAt := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	suite.mockShortURLManager.EXPECT().CreateShortURL(gomock.Any(), longURL, &shorturl.CreateOptions{Description: "Summer campaign landing page"}).
+		Return(&shorturl.ShortURL{Id: "AABBCC", LongURL: longURL, Description: "Summer campaign landing page", CreatedAt: createdAt, UpdatedAt: createdAt}, nil)
+
+	request := httptest.NewRequest(http.MethodPost, "/private/v1/short-urls/",
+		strings.NewReader(`{"long_url":"https://example.com","description":"Summer campaign landing page"}`))
+	response := httptest.NewRecorder()
+	suite.handler.CreateShortURL(response, request)
+
+	suite.Equal(http.StatusCreated, response.Code)
+	suite.JSONEq(`{
+		"id": "AABBCC",
+		"short_url": "http://localhost:8080/public/v1/short-urls/AABBCC",
+		"tags": [],
+		"description": "Summer campaign landing page",
+		"created_at": "2025-06-01T12:00:00Z",
+		"updated_at": "2025-06-01T12:00:00Z"
+	}`, response.Body.String())
+}
+
+func (suite *HandlerSuite) TestCreateShortURLFailInvalidDescription() {
+	suite.mockShortURLManager.EXPECT().CreateShortURL(gomock.Any(), "https://example.com", gomock.Any()).
+		Return(nil, shorturl.ErrInvalidDescription)
+
+	request := httptest.NewRequest(http.MethodPost, "/private/v1/short-urls/",
+		strings.NewReader(`{"long_url":"https://example.com","description":"`+strings.Repeat("a", 513)+`"}`))
+	response := httptest.NewRecorder()
+	suite.handler.CreateShortURL(response, request)
+
+	suite.Equal(http.StatusBadRequest, response.Code)
+}
+
+func (suite *HandlerSuite) TestCreateShortURLFailInvalidRedirectCode() {
+	suite.mockShortURLManager.EXPECT().CreateShortURL(gomock.Any(), "https://example.com", &shorturl.CreateOptions{RedirectCode: http.StatusOK}).
+		Return(nil, shorturl.ErrInvalidRedirectCode)
+
+	request := httptest.NewRequest(http.MethodPost, "/private/v1/short-urls/",
+		strings.NewReader(`{"long_url":"https://example.com","redirect_code":200}`))
+	response := httptest.NewRecorder()
+	suite.handler.CreateShortURL(response, request)
+
+	suite.Equal(http.StatusBadRequest, response.Code)
+}
+
+func (suite *HandlerSuite) TestCreateShortURLSuccessWithMaxClicks() {
+	longURL := "https://example.com"
+
+	suite.mockShortURLManager.EXPECT().CreateShortURL(gomock.Any(), longURL, &shorturl.CreateOptions{MaxClicks: 10}).
+		Return(&shorturl.ShortURL{Id: "AABBCC", LongURL: longURL, MaxClicks: 10}, nil)
+
+	request := httptest.NewRequest(http.MethodPost, "/private/v1/short-urls/",
+		strings.NewReader(`{"long_url":"https://example.com","max_clicks":10}`))
+	response := httptest.NewRecorder()
+	suite.handler.CreateShortURL(response, request)
+
+	suite.Equal(http.StatusCreated, response.Code)
+}
+
+func (suite *HandlerSuite) TestCreateShortURLSuccessWithInterstitial() {
+	longURL := "https://example.com"
+
+	suite.mockShortURLManager.EXPECT().CreateShortURL(gomock.Any(), longURL, &shorturl.CreateOptions{ShowInterstitial: true}).
+		Return(&shorturl.ShortURL{Id: "AABBCC", LongURL: longURL, ShowInterstitial: true}, nil)
+
+	request := httptest.NewRequest(http.MethodPost, "/private/v1/short-urls/",
+		strings.NewReader(`{"long_url":"https://example.com","show_interstitial":true}`))
+	response := httptest.NewRecorder()
+	suite.handler.CreateShortURL(response, request)
+
+	suite.Equal(http.StatusCreated, response.Code)
+}
+
+func (suite *HandlerSuite) TestCreateShortURLCacheTTL() {
+	longURL := "https://example.com"
+
+	suite.mockShortURLManager.EXPECT().CreateShortURL(gomock.Any(), longURL, &shorturl.CreateOptions{CacheTTLSeconds: 300}).
+		Return(&shorturl.ShortURL{Id: "AABBCC", LongURL: longURL, CacheTTLSeconds: 300}, nil)
+
+	request := httptest.NewRequest(http.MethodPost, "/private/v1/short-urls/",
+		strings.NewReader(`{"long_url":"https://example.com","cache_ttl_seconds":300}`))
+	response := httptest.NewRecorder()
+	suite.handler.CreateShortURL(response, request)
+
+	suite.Equal(http.StatusCreated, response.Code)
+
+	suite.mockShortURLManager.EXPECT().CreateShortURL(gomock.Any(), longURL, &shorturl.CreateOptions{CacheTTLSeconds: -1}).
+		Return(nil, shorturl.ErrInvalidCacheTTL)
+
+	request = httptest.NewRequest(http.MethodPost, "/private/v1/short-urls/",
+		strings.NewReader(`{"long_url":"https://example.com","cache_ttl_seconds":-1}`))
+	response = httptest.NewRecorder()
+	suite.handler.CreateShortURL(response, request)
+
+	suite.Equal(http.StatusBadRequest, response.Code)
+}
+
+func (suite *HandlerSuite) TestCreateShortURLSuccessWebhookRegistrationFailed() {
+	longURL := "https://example.com"
+	createdAt := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	suite.mockShortURLManager.EXPECT().CreateShortURL(gomock.Any(), longURL, gomock.Any()).
+		Return(&shorturl.ShortURL{Id: "AABBCC", LongURL: longURL, CreatedAt: createdAt, UpdatedAt: createdAt}, nil)
+	suite.mockWebhookManager.EXPECT().RegisterWebhook(gomock.Any(), "AABBCC", "https://hooks.example.com", "secret").
+		Return(nil, errors.New("storage error"))
+
+	request := httptest.NewRequest(http.MethodPost, "/private/v1/short-urls/",
+		strings.NewReader(`{"long_url":"https://example.com","webhook":{"url":"https://hooks.example.com","secret":"secret"}}`))
+	response := httptest.NewRecorder()
+	suite.handler.CreateShortURL(response, request)
+
+	// The short URL was created, it is returned without the webhook
+	suite.Equal(http.StatusCreated, response.Code)
+	suite.JSONEq(`{
+		"id": "AABBCC",
+		"short_url": "http://localhost:8080/public/v1/short-urls/AABBCC",
+		"tags": [],
+		"created_at": "2025-06-01T12:00:00Z",
+		"updated_at": "2025-06-01T12:00:00Z"
+	}`, response.Body.String())
+}
+
+func (suite *HandlerSuite) TestCreateShortURLSuccessWithWebhook() {
+	longURL := "https://example.com"
+	createdAt := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	suite.mockShortURLManager.EXPECT().CreateShortURL(gomock.Any(), longURL, gomock.Any()).
+		Return(&shorturl.ShortURL{Id: "AABBCC", LongURL: longURL, CreatedAt: createdAt, UpdatedAt: createdAt}, nil)
+	suite.mockWebhookManager.EXPECT().RegisterWebhook(gomock.Any(), "AABBCC", "https://hooks.example.com", "secret").
+		Return(&webhook.Webhook{Id: 1, ShortURLId: "AABBCC", URL: "https://hooks.example.com", Secret: "secret", CreatedAt: createdAt}, nil)
+
+	request := httptest.NewRequest(http.MethodPost, "/private/v1/short-urls/",
+		strings.NewReader(`{"long_url":"https://example.com","webhook":{"url":"https://hooks.example.com","secret":"secret"}}`))
+	response := httptest.NewRecorder()
+	suite.handler.CreateShortURL(response, request)
+
+	suite.Equal(http.StatusCreated, response.Code)
+	suite.JSONEq(`{
+		"id": "AABBCC",
+		"short_url": "http://localhost:8080/public/v1/short-urls/AABBCC",
+		"tags": [],
+		"created_at": "2025-06-01T12:00:00Z",
+		"updated_at": "2025-06-01T12:00:00Z",
+		"webhook": {
+			"id": 1,
+			"short_url_id": "AABBCC",
+			"url": "https://hooks.example.com",
+			"created_at": "2025-06-01T12:00:00Z"
+		}
+	}`, response.Body.String())
+}
+
+func (suite *HandlerSuite) TestCreateShortURLFailInvalidWebhook() {
+	request := httptest.NewRequest(http.MethodPost, "/private/v1/short-urls/",
+		strings.NewReader(`{"long_url":"https://example.com","webhook":{"url":"ftp://hooks.example.com","secret":"secret"}}`))
+	response := httptest.NewRecorder()
+	suite.handler.CreateShortURL(response, request)
+
+	suite.Equal(http.StatusBadRequest, response.Code)
+}
+
+func (suite *HandlerSuite) TestCreateShortURLFailBodyTooLarge() {
+	body := `{"long_url":"https://example.com/` + strings.Repeat("a", 10<<20) + `"}`
+	handler := middleware.MaxBodySize(4096)(http.HandlerFunc(suite.handler.CreateShortURL))
+
+	request := httptest.NewRequest(http.MethodPost, "/private/v1/short-urls/", bytes.NewBufferString(body))
+	response := httptest.NewRecorder()
+	handler.ServeHTTP(response, request)
+
+	suite.Equal(http.StatusRequestEntityTooLarge, response.Code)
+}
+
+func (suite *HandlerSuite) TestPreviewShortURL() {
+	id := "AABBCC"
+	longURL := "https://example.com"
+	shortURL := &shorturl.ShortURL{Id: id, LongURL: longURL, CreatedAt: time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC),
+		UpdatedAt: time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)}
+
+	body := `{"id":"AABBCC","long_url":"https://example.com","tags":[],"created_at":"2025-06-01T12:00:00Z","updated_at":"2025-06-01T12:00:00Z"}`
+	hash := md5.Sum([]byte(body))
+	expectedETag := `"` + hex.EncodeToString(hash[:]) + `"`
+
+	testCases := []struct {
+		name           string
+		ifNoneMatch    string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "no If-None-Match",
+			expectedStatus: http.StatusOK,
+			expectedBody:   body,
+		},
+		{
+			name:           "matching tag",
+			ifNoneMatch:    expectedETag,
+			expectedStatus: http.StatusNotModified,
+		},
+		{
+			name:           "stale tag",
+			ifNoneMatch:    `"stale"`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   body,
+		},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			suite.mockShortURLManager.EXPECT().GetShortURL(gomock.Any(), id).Return(shortURL, nil)
+
+			request := httptest.NewRequest(http.MethodGet, "/public/v1/short-urls/AABBCC/preview", nil)
+			request = withURLParams(request, map[string]string{"shortURLId": id})
+			if tc.ifNoneMatch != "" {
+				request.Header.Set("If-None-Match", tc.ifNoneMatch)
+			}
+
+			response := httptest.NewRecorder()
+			suite.handler.PreviewShortURL(response, request)
+
+			suite.Equal(tc.expectedStatus, response.Code)
+			suite.Equal(expectedETag, response.Header().Get("ETag"))
+			suite.Equal(tc.expectedBody, response.Body.String())
+		})
+	}
+}
+
+func (suite *HandlerSuite) TestPreviewShortURLETagChangesWithPreview() {
+	createdAt := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	previews := map[string]*shorturl.ShortURL{
+		"original":            {Id: "AABBCC", LongURL: "https://example.com", CreatedAt: createdAt, UpdatedAt: createdAt},
+		"description":         {Id: "AABBCC", LongURL: "https://example.com", Description: "Sale", CreatedAt: createdAt, UpdatedAt: createdAt.Add(time.Hour)},
+		"tags":                {Id: "AABBCC", LongURL: "https://example.com", Tags: []string{"news"}, CreatedAt: createdAt, UpdatedAt: createdAt.Add(time.Hour)},
+		"protected":           {Id: "AABBCC", LongURL: "https://example.com", PasswordHash: "$2a$10$hash", CreatedAt: createdAt, UpdatedAt: createdAt},
+		"protected described": {Id: "AABBCC", LongURL: "https://example.com", PasswordHash: "$2a$10$hash", Description: "Sale", CreatedAt: createdAt, UpdatedAt: createdAt.Add(time.Hour)},
+	}
+
+	etags := make(map[string]string)
+	for name, shortURL := range previews {
+		suite.mockShortURLManager.EXPECT().GetShortURL(gomock.Any(), "AABBCC").Return(shortURL, nil)
+
+		request := withURLParams(httptest.NewRequest(http.MethodGet, "/public/v1/short-urls/AABBCC/preview", nil),
+			map[string]string{"shortURLId": "AABBCC"})
+		response := httptest.NewRecorder()
+		suite.handler.PreviewShortURL(response, request)
+
+		suite.Require().Equal(http.StatusOK, response.Code)
+		etag := response.Header().Get("ETag")
+		suite.NotContains(etags, etag, name)
+		etags[etag] = name
+	}
+}
+
+func (suite *HandlerSuite) TestGetShortURLMetricsSuccess() {
+	id := "AABBCC"
+	from := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)
+	createdAt := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	suite.mockShortURLManager.EXPECT().GetShortURL(gomock.Any(), id).
+		Return(&shorturl.ShortURL{Id: id, LongURL: "https://example.com", CreatedAt: createdAt}, nil)
+	suite.mockMetricsManager.EXPECT().GetShortURLMetrics(gomock.Any(), id, from, to).
+		Return(&metrics.Metrics{
+			ShortURLId:   id,
+			Visits:       42,
+			UniqueVisits: 7,
+			BotVisits:    5,
+			TopCountries: []metrics.CountryStat{{Country: "AR", Visits: 30}, {Country: "US", Visits: 12}},
+			From:         from,
+			To:           to,
+		}, nil)
+
+	request := httptest.NewRequest(http.MethodGet, "/private/v1/short-urls/AABBCC/metrics",
+		strings.NewReader(`{"from":"2025-06-01T00:00:00Z","to":"2025-06-02T00:00:00Z"}`))
+	request = withURLParams(request, map[string]string{"shortURLId": id})
+
+	response := httptest.NewRecorder()
+	suite.handler.GetShortURLMetrics(response, request)
+
+	suite.Equal(http.StatusOK, response.Code)
+	suite.JSONEq(`{
+		"short_url_id": "AABBCC",
+		"visits": 42,
+		"unique_visits": 7,
+		"bot_visits": 5,
+		"top_countries": [{"country": "AR", "visits": 30}, {"country": "US", "visits": 12}],
+		"from": "2025-06-01T00:00:00Z",
+		"to": "2025-06-02T00:00:00Z",
+		"created_at": "2025-05-01T12:00:00Z"
+	}`, response.Body.String())
+}
+
+func (suite *HandlerSuite) TestGetShortURLMetricsFailNotFound() {
+	id := "AABBCC"
+
+	suite.mockShortURLManager.EXPECT().GetShortURL(gomock.Any(), id).Return(nil, shorturl.ErrShortURLNotFound)
+
+	request := httptest.NewRequest(http.MethodGet, "/private/v1/short-urls/AABBCC/metrics",
+		strings.NewReader(`{"from":"2025-06-01T00:00:00Z","to":"2025-06-02T00:00:00Z"}`))
+	request = withURLParams(request, map[string]string{"shortURLId": id})
+
+	response := httptest.NewRecorder()
+	suite.handler.GetShortURLMetrics(response, request)
+
+	suite.Equal(http.StatusNotFound, response.Code)
+}
+
+func (suite *HandlerSuite) TestGetShortURLMetricsFailInvalidTimeRange() {
+	testCases := map[string]string{
+		"from after to":  `{"from":"2025-06-02T00:00:00Z","to":"2025-06-01T00:00:00Z"}`,
+		"missing range":  `{}`,
+		"range too long": `{"from":"2024-01-01T00:00:00Z","to":"2025-06-01T00:00:00Z"}`,
+	}
+
+	for name, body := range testCases {
+		suite.Run(name, func() {
+			request := httptest.NewRequest(http.MethodGet, "/private/v1/short-urls/AABBCC/metrics", strings.NewReader(body))
+			request = withURLParams(request, map[string]string{"shortURLId": "AABBCC"})
+
+			response := httptest.NewRecorder()
+			suite.handler.GetShortURLMetrics(response, request)
+
+			suite.Equal(http.StatusBadRequest, response.Code)
+		})
+	}
+}
+
+// streamIntervals returns a StreamShortURLMetrics stub calling fn with the given intervals
+func streamIntervals(intervals ...*metrics.Interval) func(context.Context, string, time.Time, time.Time, func(*metrics.Interval) error) error {
+	return func(_ context.Context, _ string, _, _ time.Time, fn func(*metrics.Interval) error) error {
+		for _, interval := range intervals {
+			if err := fn(interval); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
+
+func (suite *HandlerSuite) TestExportShortURLMetricsSuccess() {
+	id := "AABBCC"
+	from := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)
+
+	suite.mockShortURLManager.EXPECT().GetShortURL(gomock.Any(), id).Return(&shorturl.ShortURL{Id: id}, nil)
+	suite.mockMetricsManager.EXPECT().StreamShortURLMetrics(gomock.Any(), id, from, to, gomock.Any()).DoAndReturn(streamIntervals(
+		&metrics.Interval{Timestamp: from, Visits: 42, UniqueVisits: 7, BotVisits: 3},
+		&metrics.Interval{Timestamp: from.Add(time.Hour), Visits: 5, UniqueVisits: 5},
+		&metrics.Interval{Timestamp: from.Add(2 * time.Hour), Visits: 1, UniqueVisits: 1},
+	))
+
+	request := httptest.NewRequest(http.MethodGet,
+		"/private/v1/short-urls/AABBCC/metrics/export?from=2025-06-01T00:00:00Z&to=2025-06-02T00:00:00Z&format=csv", nil)
+	request = withURLParams(request, map[string]string{"shortURLId": id})
+
+	response := newDeadlineRecorder()
+	suite.handler.ExportShortURLMetrics(response, request)
+
+	suite.True(response.deadlineCleared)
+	suite.Equal(http.StatusOK, response.Code)
+	suite.Equal("text/csv", response.Header().Get("Content-Type"))
+	suite.Equal(`attachment; filename="metrics-AABBCC.csv"`, response.Header().Get("Content-Disposition"))
+
+	records, err := csv.NewReader(response.Body).ReadAll()
+	suite.Require().NoError(err)
+	suite.Equal([][]string{
+		{"timestamp", "visits", "unique_visits"},
+		{"2025-06-01T00:00:00Z", "42", "7"},
+		{"2025-06-01T01:00:00Z", "5", "5"},
+		{"2025-06-01T02:00:00Z", "1", "1"},
+	}, records)
+}
+
+func (suite *HandlerSuite) TestExportShortURLMetricsSuccessNoMetrics() {
+	id := "AABBCC"
+
+	suite.mockShortURLManager.EXPECT().GetShortURL(gomock.Any(), id).Return(&shorturl.ShortURL{Id: id}, nil)
+	suite.mockMetricsManager.EXPECT().StreamShortURLMetrics(gomock.Any(), id, gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(streamIntervals())
+
+	request := httptest.NewRequest(http.MethodGet,
+		"/private/v1/short-urls/AABBCC/metrics/export?from=2025-06-01T00:00:00Z&to=2025-06-02T00:00:00Z", nil)
+	request = withURLParams(request, map[string]string{"shortURLId": id})
+
+	response := newDeadlineRecorder()
+	suite.handler.ExportShortURLMetrics(response, request)
+
+	suite.Equal(http.StatusOK, response.Code)
+	suite.Equal(`attachment; filename="metrics-AABBCC.csv"`, response.Header().Get("Content-Disposition"))
+	suite.Equal("timestamp,visits,unique_visits\n", response.Body.String())
+}
+
+func (suite *HandlerSuite) TestExportShortURLMetricsFailInvalidRequest() {
+	testCases := map[string]string{
+		"unsupported format": "from=2025-06-01T00:00:00Z&to=2025-06-02T00:00:00Z&format=xlsx",
+		"missing from":       "to=2025-06-02T00:00:00Z",
+		"invalid to":         "from=2025-06-01T00:00:00Z&to=tomorrow",
+		"from after to":      "from=2025-06-02T00:00:00Z&to=2025-06-01T00:00:00Z",
+		"range too long":     "from=2024-01-01T00:00:00Z&to=2025-06-01T00:00:00Z",
+	}
+
+	for name, rawQuery := range testCases {
+		suite.Run(name, func() {
+			request := httptest.NewRequest(http.MethodGet, "/private/v1/short-urls/AABBCC/metrics/export?"+rawQuery, nil)
+			request = withURLParams(request, map[string]string{"shortURLId": "AABBCC"})
+
+			response := httptest.NewRecorder()
+			suite.handler.ExportShortURLMetrics(response, request)
+
+			suite.Equal(http.StatusBadRequest, response.Code)
+		})
+	}
+}
+
+func (suite *HandlerSuite) TestExportShortURLMetricsFailNotFound() {
+	suite.mockShortURLManager.EXPECT().GetShortURL(gomock.Any(), "AABBCC").Return(nil, shorturl.ErrShortURLNotFound)
+
+	request := httptest.NewRequest(http.MethodGet,
+		"/private/v1/short-urls/AABBCC/metrics/export?from=2025-06-01T00:00:00Z&to=2025-06-02T00:00:00Z", nil)
+	request = withURLParams(request, map[string]string{"shortURLId": "AABBCC"})
+
+	response := httptest.NewRecorder()
+	suite.handler.ExportShortURLMetrics(response, request)
+
+	suite.Equal(http.StatusNotFound, response.Code)
+}
+
+func (suite *HandlerSuite) TestExportShortURLMetricsFailStorageError() {
+	suite.mockShortURLManager.EXPECT().GetShortURL(gomock.Any(), "AABBCC").Return(&shorturl.ShortURL{Id: "AABBCC"}, nil)
+	suite.mockMetricsManager.EXPECT().StreamShortURLMetrics(gomock.Any(), "AABBCC", gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(errors.New("storage error"))
+
+	request := httptest.NewRequest(http.MethodGet,
+		"/private/v1/short-urls/AABBCC/metrics/export?from=2025-06-01T00:00:00Z&to=2025-06-02T00:00:00Z", nil)
+	request = withURLParams(request, map[string]string{"shortURLId": "AABBCC"})
+
+	response := newDeadlineRecorder()
+	suite.handler.ExportShortURLMetrics(response, request)
+
+	suite.Equal(http.StatusInternalServerError, response.Code)
+	suite.Empty(response.Header().Get("Content-Disposition"))
+}
+
+func (suite *HandlerSuite) TestExportShortURLMetricsFailWriteDeadlineNotSupported() {
+	suite.mockShortURLManager.EXPECT().GetShortURL(gomock.Any(), "AABBCC").Return(&shorturl.ShortURL{Id: "AABBCC"}, nil)
+
+	request := httptest.NewRequest(http.MethodGet,
+		"/private/v1/short-urls/AABBCC/metrics/export?from=2025-06-01T00:00:00Z&to=2025-06-02T00:00:00Z", nil)
+	request = withURLParams(request, map[string]string{"shortURLId": "AABBCC"})
+
+	response := httptest.NewRecorder()
+	suite.handler.ExportShortURLMetrics(response, request)
+
+	suite.Equal(http.StatusInternalServerError, response.Code)
+	suite.Empty(response.Header().Get("Content-Disposition"))
+}
+
+// serverSentEvent is an event read from a Server-Sent Events stream
+type serverSentEvent struct {
+	event string
+	data  string
+}
+
+// readServerSentEvent reads the next event of a Server-Sent Events stream
+func readServerSentEvent(reader *bufio.Reader) (serverSentEvent, error) {
+	var event serverSentEvent
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return event, err
+		}
+
+		line = strings.TrimSuffix(line, "\n")
+		switch {
+		case line == "":
+			return event, nil
+		case strings.HasPrefix(line, "event: "):
+			event.event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			event.data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+}
+
+func (suite *HandlerSuite) TestStreamShortURLClicksSuccess() {
+	config := handlers.DefaultConfig()
+	config.StreamHeartbeatIntervalInMS = 50
+
+	handler, err := handlers.NewShortURLHandler(config, suite.mockShortURLManager, suite.mockMetricsManager, suite.mockWebhookManager, suite.mockTokenSigner, suite.mockLogger)
+	suite.Require().NoError(err)
+
+	clickedAt := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	events := make(chan metrics.Event, 1)
+	events <- metrics.Event{ShortURLId: "AABBCC", IP: "127.0.0.1", Timestamp: clickedAt}
+	unsubscribed := make(chan struct{})
+
+	suite.mockShortURLManager.EXPECT().GetShortURL(gomock.Any(), "AABBCC").Return(&shorturl.ShortURL{Id: "AABBCC"}, nil)
+	suite.mockMetricsManager.EXPECT().Subscribe(gomock.Any(), "AABBCC").Return(events, func() { close(unsubscribed) })
+
+	r := chi.NewRouter()
+	r.Get("/private/v1/short-urls/{shortURLId}/stream", handler.StreamShortURLClicks)
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	response, err := http.Get(server.URL + "/private/v1/short-urls/AABBCC/stream")
+	suite.Require().NoError(err)
+
+	suite.Equal(http.StatusOK, response.StatusCode)
+	suite.Equal("text/event-stream", response.Header.Get("Content-Type"))
+	suite.Equal("no-cache", response.Header.Get("Cache-Control"))
+
+	reader := bufio.NewReader(response.Body)
+
+	click, err := readServerSentEvent(reader)
+	suite.Require().NoError(err)
+	suite.Equal("click", click.event)
+	suite.JSONEq(`{"id": "AABBCC", "ip": "127.0.0.1", "timestamp": "2025-06-01T12:00:00Z"}`, click.data)
+
+	heartbeat, err := readServerSentEvent(reader)
+	suite.Require().NoError(err)
+	suite.Equal(serverSentEvent{event: "heartbeat", data: "{}"}, heartbeat)
+
+	// The subscription ends with the stream
+	suite.Require().NoError(response.Body.Close())
+	select {
+	case <-unsubscribed:
+	case <-time.After(time.Second):
+		suite.Fail("click stream not unsubscribed")
+	}
+}
+
+func (suite *HandlerSuite) TestStreamShortURLClicksOutlivesWriteTimeout() {
+	config := handlers.DefaultConfig()
+	config.StreamHeartbeatIntervalInMS = 50
+
+	handler, err := handlers.NewShortURLHandler(config, suite.mockShortURLManager, suite.mockMetricsManager, suite.mockWebhookManager, suite.mockTokenSigner, suite.mockLogger)
+	suite.Require().NoError(err)
+
+	suite.mockShortURLManager.EXPECT().GetShortURL(gomock.Any(), "AABBCC").Return(&shorturl.ShortURL{Id: "AABBCC"}, nil)
+	suite.mockMetricsManager.EXPECT().Subscribe(gomock.Any(), "AABBCC").Return(make(chan metrics.Event), func() {})
+
+	// The stream goes through the gzip middleware of the private router
+	r := chi.NewRouter()
+	r.Use(middleware.Gzip(0))
+	r.Get("/private/v1/short-urls/{shortURLId}/stream", handler.StreamShortURLClicks)
+	server := httptest.NewUnstartedServer(r)
+	server.Config.WriteTimeout = 200 * time.Millisecond
+	server.Start()
+	defer server.Close()
+
+	request, err := http.NewRequest(http.MethodGet, server.URL+"/private/v1/short-urls/AABBCC/stream", nil)
+	suite.Require().NoError(err)
+	request.Header.Set("Accept-Encoding", "gzip")
+
+	response, err := http.DefaultTransport.RoundTrip(request)
+	suite.Require().NoError(err)
+	defer response.Body.Close()
+
+	suite.Equal(http.StatusOK, response.StatusCode)
+	suite.Empty(response.Header.Get("Content-Encoding"))
+
+	reader := bufio.NewReader(response.Body)
+	deadline := time.Now().Add(3 * server.Config.WriteTimeout)
+	for time.Now().Before(deadline) {
+		heartbeat, err := readServerSentEvent(reader)
+		suite.Require().NoError(err)
+		suite.Equal("heartbeat", heartbeat.event)
+	}
+}
+
+func (suite *HandlerSuite) TestStreamShortURLClicksFailNotFound() {
+	suite.mockShortURLManager.EXPECT().GetShortURL(gomock.Any(), "AABBCC").Return(nil, shorturl.ErrShortURLNotFound)
+
+	request := httptest.NewRequest(http.MethodGet, "/private/v1/short-urls/AABBCC/stream", nil)
+	request = withURLParams(request, map[string]string{"shortURLId": "AABBCC"})
+
+	response := httptest.NewRecorder()
+	suite.handler.StreamShortURLClicks(response, request)
+
+	suite.Equal(http.StatusNotFound, response.Code)
+}
+
+func (suite *HandlerSuite) TestGetShortURLLatencySuccess() {
+	id := "AABBCC"
+	from := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)
+
+	suite.mockShortURLManager.EXPECT().GetShortURL(gomock.Any(), id).Return(&shorturl.ShortURL{Id: id}, nil)
+	suite.mockMetricsManager.EXPECT().GetShortURLLatency(gomock.Any(), id, from, to).Return([]*metrics.Latency{
+		{Timestamp: from, Requests: 3, P50Ns: 1000, P95Ns: 2000, P99Ns: 3000},
+	}, nil)
+
+	request := httptest.NewRequest(http.MethodGet,
+		"/private/v1/short-urls/AABBCC/latency?from=2025-06-01T00:00:00Z&to=2025-06-02T00:00:00Z", nil)
+	request = withURLParams(request, map[string]string{"shortURLId": id})
+
+	response := httptest.NewRecorder()
+	suite.handler.GetShortURLLatency(response, request)
+
+	suite.Equal(http.StatusOK, response.Code)
+	suite.JSONEq(`{
+		"short_url_id": "AABBCC",
+		"from": "2025-06-01T00:00:00Z",
+		"to": "2025-06-02T00:00:00Z",
+		"intervals": [{"timestamp": "2025-06-01T00:00:00Z", "requests": 3, "p50_ns": 1000, "p95_ns": 2000, "p99_ns": 3000}]
+	}`, response.Body.String())
+}
+
+func (suite *HandlerSuite) TestGetShortURLLatencyFailInvalidRequest() {
+	testCases := map[string]string{
+		"missing from":  "to=2025-06-02T00:00:00Z",
+		"invalid to":    "from=2025-06-01T00:00:00Z&to=tomorrow",
+		"from equal to": "from=2025-06-01T00:00:00Z&to=2025-06-01T00:00:00Z",
+	}
+
+	for name, rawQuery := range testCases {
+		suite.Run(name, func() {
+			request := httptest.NewRequest(http.MethodGet, "/private/v1/short-urls/AABBCC/latency?"+rawQuery, nil)
+			request = withURLParams(request, map[string]string{"shortURLId": "AABBCC"})
+
+			response := httptest.NewRecorder()
+			suite.handler.GetShortURLLatency(response, request)
+
+			suite.Equal(http.StatusBadRequest, response.Code)
+		})
+	}
+}
+
+func (suite *HandlerSuite) TestGetShortURLLatencyFailNotFound() {
+	suite.mockShortURLManager.EXPECT().GetShortURL(gomock.Any(), "AABBCC").Return(nil, shorturl.ErrShortURLNotFound)
+
+	request := httptest.NewRequest(http.MethodGet,
+		"/private/v1/short-urls/AABBCC/latency?from=2025-06-01T00:00:00Z&to=2025-06-02T00:00:00Z", nil)
+	request = withURLParams(request, map[string]string{"shortURLId": "AABBCC"})
+
+	response := httptest.NewRecorder()
+	suite.handler.GetShortURLLatency(response, request)
+
+	suite.Equal(http.StatusNotFound, response.Code)
+}
+
+func (suite *HandlerSuite) TestGetDashboardSuccess() {
+	from := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 7)
+	top := []*shorturl.ShortURLStats{{ShortURLId: "AABBCC", LongURL: "https://example.com", Visits: 10, UniqueVisits: 4}}
+
+	suite.mockShortURLManager.EXPECT().GetDashboardStats(gomock.Any(), from, to).Return(&shorturl.DashboardStats{
+		From:              from,
+		To:                to,
+		ShortURLsCreated:  3,
+		Visits:            10,
+		UniqueVisits:      4,
+		TopByVisits:       top,
+		TopByUniqueVisits: top,
+	}, nil)
+
+	request := httptest.NewRequest(http.MethodGet, "/private/v1/analytics/dashboard?from=2025-06-01T00:00:00Z&to=2025-06-08T00:00:00Z", nil)
+	response := httptest.NewRecorder()
+	suite.handler.GetDashboard(response, request)
+
+	suite.Equal(http.StatusOK, response.Code)
+	suite.JSONEq(`{
+		"from": "2025-06-01T00:00:00Z",
+		"to": "2025-06-08T00:00:00Z",
+		"short_urls_created": 3,
+		"visits": 10,
+		"unique_visits": 4,
+		"top_by_visits": [{"id": "AABBCC", "short_url": "http://localhost:8080/public/v1/short-urls/AABBCC", "long_url": "https://example.com", "visits": 10, "unique_visits": 4}],
+		"top_by_unique_visits": [{"id": "AABBCC", "short_url": "http://localhost:8080/public/v1/short-urls/AABBCC", "long_url": "https://example.com", "visits": 10, "unique_visits": 4}]
+	}`, response.Body.String())
+}
+
+func (suite *HandlerSuite) TestGetDashboardFail() {
+	suite.Run("invalid from", func() {
+		request := httptest.NewRequest(http.MethodGet, "/private/v1/analytics/dashboard?from=yesterday&to=2025-06-08T00:00:00Z", nil)
+		response := httptest.NewRecorder()
+		suite.handler.GetDashboard(response, request)
+
+		suite.Equal(http.StatusBadRequest, response.Code)
+	})
+
+	suite.Run("from after to", func() {
+		request := httptest.NewRequest(http.MethodGet, "/private/v1/analytics/dashboard?from=2025-06-08T00:00:00Z&to=2025-06-01T00:00:00Z", nil)
+		response := httptest.NewRecorder()
+		suite.handler.GetDashboard(response, request)
+
+		suite.Equal(http.StatusBadRequest, response.Code)
+		suite.Equal("from must be before to\n", response.Body.String())
+	})
+
+	suite.Run("storage error", func() {
+		suite.mockShortURLManager.EXPECT().GetDashboardStats(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("storage error"))
+
+		request := httptest.NewRequest(http.MethodGet, "/private/v1/analytics/dashboard?from=2025-06-01T00:00:00Z&to=2025-06-08T00:00:00Z", nil)
+		response := httptest.NewRecorder()
+		suite.handler.GetDashboard(response, request)
+
+		suite.Equal(http.StatusInternalServerError, response.Code)
+	})
+}
+
+func (suite *HandlerSuite) TestGetMetricsSnapshotSuccess() {
+	suite.mockMetricsManager.EXPECT().Snapshot(gomock.Any()).Return(map[string]metrics.CollectorSnapshot{
+		"AABBCC": {ShortURLId: "AABBCC", Visits: 3, UniqueVisits: 2, BotVisits: 1},
+	}, nil)
+
+	request := httptest.NewRequest(http.MethodGet, "/private/v1/metrics/snapshot", nil)
+	response := httptest.NewRecorder()
+	suite.handler.GetMetricsSnapshot(response, request)
+
+	suite.Equal(http.StatusOK, response.Code)
+	suite.JSONEq(`{
+		"short_urls": {
+			"AABBCC": {"short_url_id": "AABBCC", "visits": 3, "unique_visits": 2, "bot_visits": 1}
+		}
+	}`, response.Body.String())
+}
+
+func (suite *HandlerSuite) TestGetMetricsSnapshotFailManagerStopped() {
+	suite.mockMetricsManager.EXPECT().Snapshot(gomock.Any()).Return(nil, metrics.ErrManagerStopped)
+
+	request := httptest.NewRequest(http.MethodGet, "/private/v1/metrics/snapshot", nil)
+	response := httptest.NewRecorder()
+	suite.handler.GetMetricsSnapshot(response, request)
+
+	suite.Equal(http.StatusServiceUnavailable, response.Code)
+}
+
+func (suite *HandlerSuite) TestDrainMetricsSuccess() {
+	suite.mockMetricsManager.EXPECT().Drain(gomock.Any()).Return(nil)
+
+	request := httptest.NewRequest(http.MethodPost, "/private/v1/metrics/drain", nil)
+	response := httptest.NewRecorder()
+	suite.handler.DrainMetrics(response, request)
+
+	suite.Equal(http.StatusOK, response.Code)
+}
+
+func (suite *HandlerSuite) TestDrainMetricsFailStorageError() {
+	suite.mockMetricsManager.EXPECT().Drain(gomock.Any()).Return(errors.New("creating metrics in storage: storage error"))
+
+	request := httptest.NewRequest(http.MethodPost, "/private/v1/metrics/drain", nil)
+	response := httptest.NewRecorder()
+	suite.handler.DrainMetrics(response, request)
+
+	suite.Equal(http.StatusInternalServerError, response.Code)
+}
+
+func (suite *HandlerSuite) TestGetMetricsBulkSuccess() {
+	from := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)
+
+	suite.mockMetricsManager.EXPECT().GetShortURLMetricsBulk(gomock.Any(), []string{"AABBCC", "DDEEFF"}, from, to).
+		Return(map[string]*metrics.Metrics{
+			"AABBCC": {ShortURLId: "AABBCC", Visits: 42, UniqueVisits: 7, From: from, To: to},
+			"DDEEFF": {ShortURLId: "DDEEFF", From: from, To: to},
+		}, nil)
+
+	request := httptest.NewRequest(http.MethodPost, "/private/v1/metrics/bulk",
+		strings.NewReader(`{"ids":["AABBCC","DDEEFF"],"from":"2025-06-01T00:00:00Z","to":"2025-06-02T00:00:00Z"}`))
+	response := httptest.NewRecorder()
+	suite.handler.GetMetricsBulk(response, request)
+
+	suite.Equal(http.StatusOK, response.Code)
+	suite.JSONEq(`{"metrics": {
+		"AABBCC": {"short_url_id": "AABBCC", "visits": 42, "unique_visits": 7, "bot_visits": 0, "top_countries": [],
+			"from": "2025-06-01T00:00:00Z", "to": "2025-06-02T00:00:00Z"},
+		"DDEEFF": {"short_url_id": "DDEEFF", "visits": 0, "unique_visits": 0, "bot_visits": 0, "top_countries": [],
+			"from": "2025-06-01T00:00:00Z", "to": "2025-06-02T00:00:00Z"}
+	}}`, response.Body.String())
+}
+
+func (suite *HandlerSuite) TestGetMetricsBulkFail() {
+	timeRange := `"from":"2025-06-01T00:00:00Z","to":"2025-06-02T00:00:00Z"`
+
+	testCases := []struct {
+		name           string
+		body           string
+		expectedStatus int
+	}{
+		{name: "invalid body", body: `{"ids":`, expectedStatus: http.StatusBadRequest},
+		{name: "no ids", body: `{"ids":[],` + timeRange + `}`, expectedStatus: http.StatusBadRequest},
+		{
+			name:           "too many ids",
+			body:           `{"ids":["` + strings.TrimSuffix(strings.Repeat(`AABBCC","`, 101), `","`) + `"],` + timeRange + `}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid time range",
+			body:           `{"ids":["AABBCC"],"from":"2025-06-02T00:00:00Z","to":"2025-06-01T00:00:00Z"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, testCase := range testCases {
+		suite.Run(testCase.name, func() {
+			request := httptest.NewRequest(http.MethodPost, "/private/v1/metrics/bulk", strings.NewReader(testCase.body))
+			response := httptest.NewRecorder()
+			suite.handler.GetMetricsBulk(response, request)
+
+			suite.Equal(testCase.expectedStatus, response.Code)
+		})
+	}
+
+	suite.Run("storage error", func() {
+		suite.mockMetricsManager.EXPECT().GetShortURLMetricsBulk(gomock.Any(), []string{"AABBCC"}, gomock.Any(), gomock.Any()).
+			Return(nil, errors.New("storage error"))
+
+		request := httptest.NewRequest(http.MethodPost, "/private/v1/metrics/bulk",
+			strings.NewReader(`{"ids":["AABBCC"],`+timeRange+`}`))
+		response := httptest.NewRecorder()
+		suite.handler.GetMetricsBulk(response, request)
+
+		suite.Equal(http.StatusInternalServerError, response.Code)
+	})
+}
+
+func (suite *HandlerSuite) TestGetOrCreateShortURLSuccess() {
+	testCases := []struct {
+		name           string
+		created        bool
+		expectedStatus int
+	}{
+		{name: "created", created: true, expectedStatus: http.StatusCreated},
+		{name: "existing", created: false, expectedStatus: http.StatusOK},
+	}
+
+	for _, testCase := range testCases {
+		suite.Run(testCase.name, func() {
+			suite.mockShortURLManager.EXPECT().GetOrCreateShortURL(gomock.Any(), "https://example.com").Return("AABBCC", testCase.created, nil)
+
+			request := httptest.NewRequest(http.MethodPut, "/private/v1/short-urls/", strings.NewReader(`{"long_url":"https://example.com"}`))
+			response := httptest.NewRecorder()
+			suite.handler.GetOrCreateShortURL(response, request)
+
+			suite.Equal(testCase.expectedStatus, response.Code)
+			suite.JSONEq(fmt.Sprintf(`{
+				"id": "AABBCC",
+				"short_url": "http://localhost:8080/public/v1/short-urls/AABBCC",
+				"created": %t
+			}`, testCase.created), response.Body.String())
+		})
+	}
+}
+
+func (suite *HandlerSuite) TestGetOrCreateShortURLFailInvalidLongURL() {
+	suite.mockShortURLManager.EXPECT().GetOrCreateShortURL(gomock.Any(), "not a URL").Return("", false, shorturl.ErrInvalidLongURL)
+
+	request := httptest.NewRequest(http.MethodPut, "/private/v1/short-urls/", strings.NewReader(`{"long_url":"not a URL"}`))
+	response := httptest.NewRecorder()
+	suite.handler.GetOrCreateShortURL(response, request)
+
+	suite.Equal(http.StatusBadRequest, response.Code)
+}
+
+func (suite *HandlerSuite) TestGetOrCreateShortURLFailLongURLErrors() {
+	testCases := []struct {
+		name           string
+		err            error
+		expectedStatus int
+	}{
+		{name: "too long", err: shorturl.ErrURLTooLong, expectedStatus: http.StatusBadRequest},
+		{name: "denied", err: shorturl.ErrURLDenied, expectedStatus: http.StatusForbidden},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			suite.mockShortURLManager.EXPECT().GetOrCreateShortURL(gomock.Any(), "https://example.com").Return("", false, tc.err)
+
+			request := httptest.NewRequest(http.MethodPut, "/private/v1/short-urls/", strings.NewReader(`{"long_url":"https://example.com"}`))
+			response := httptest.NewRecorder()
+			suite.handler.GetOrCreateShortURL(response, request)
+
+			suite.Equal(tc.expectedStatus, response.Code)
+		})
+	}
+}
+
+// importRequest returns a multipart request uploading csvContent as the import file
+func importRequest(csvContent string) *http.Request {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	file, _ := writer.CreateFormFile("file", "urls.csv")
+	_, _ = file.Write([]byte(csvContent))
+	_ = writer.Close()
+
+	request := httptest.NewRequest(http.MethodPost, "/private/v1/short-urls/import", body)
+	request.Header.Set("Content-Type", writer.FormDataContentType())
+
+	return request
+}
+
+func (suite *HandlerSuite) TestImportShortURLsSuccess() {
+	expiresAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	expectedEntries := []*shorturl.BulkEntry{
+		{LongURL: "https://example.com/a", Options: &shorturl.CreateOptions{Tags: []string{"news", "sports"}}, AliasId: "promo"},
+		{LongURL: "https://example.com/b", Options: &shorturl.CreateOptions{ExpiresAt: &expiresAt}},
+	}
+	suite.mockShortURLManager.EXPECT().CreateShortURLBulk(gomock.Any(), expectedEntries).Return([]*shorturl.BulkResult{
+		{ShortURL: &shorturl.ShortURL{Id: "AABBCC"}},
+		{ShortURL: &shorturl.ShortURL{Id: "DDEEFF"}},
+	})
+
+	csvContent := "long_url,custom_slug,tags,expires_at\n" +
+		"https://example.com/a,promo,\"news, sports\",\n" +
+		"https://example.com/b,,,2030-01-01T00:00:00Z\n"
+	response := httptest.NewRecorder()
+	suite.handler.ImportShortURLs(response, importRequest(csvContent))
+
+	suite.Equal(http.StatusOK, response.Code)
+	suite.JSONEq(`{
+		"created": 2,
+		"failed": 0,
+		"rows": [
+			{"row": 2, "long_url": "https://example.com/a", "id": "AABBCC", "short_url": "http://localhost:8080/public/v1/short-urls/AABBCC"},
+			{"row": 3, "long_url": "https://example.com/b", "id": "DDEEFF", "short_url": "http://localhost:8080/public/v1/short-urls/DDEEFF"}
+		]
+	}`, response.Body.String())
+}
+
+func (suite *HandlerSuite) TestImportShortURLsInvalidRows() {
+	expectedEntries := []*shorturl.BulkEntry{
+		{LongURL: "https://example.com/a", Options: &shorturl.CreateOptions{}},
+		{LongURL: "not a URL", Options: &shorturl.CreateOptions{}},
+		{LongURL: "https://evil.example", Options: &shorturl.CreateOptions{}},
+	}
+	suite.mockShortURLManager.EXPECT().CreateShortURLBulk(gomock.Any(), expectedEntries).Return([]*shorturl.BulkResult{
+		{ShortURL: &shorturl.ShortURL{Id: "AABBCC"}},
+		{Err: shorturl.ErrInvalidLongURL},
+		{Err: shorturl.ErrURLDenied},
+	})
+
+	csvContent := "long_url,expires_at\n" +
+		",\n" +
+		"https://example.com/a,\n" +
+		"https://example.com/b,tomorrow\n" +
+		"not a URL,\n" +
+		"https://example.com/c\n" +
+		"https://evil.example,\n"
+	response := httptest.NewRecorder()
+	suite.handler.ImportShortURLs(response, importRequest(csvContent))
+
+	suite.Equal(http.StatusOK, response.Code)
+	suite.JSONEq(`{
+		"created": 1,
+		"failed": 5,
+		"rows": [
+			{"row": 2, "long_url": "", "error": "long_url is required"},
+			{"row": 3, "long_url": "https://example.com/a", "id": "AABBCC", "short_url": "http://localhost:8080/public/v1/short-urls/AABBCC"},
+			{"row": 4, "long_url": "https://example.com/b", "error": "expires_at must be in RFC3339 format"},
+			{"row": 5, "long_url": "not a URL", "error": "invalid long URL"},
+			{"row": 6, "long_url": "https://example.com/c", "error": "wrong number of fields"},
+			{"row": 7, "long_url": "https://evil.example", "error": "long URL host is denied"}
+		]
+	}`, response.Body.String())
+}
+
+func (suite *HandlerSuite) TestImportShortURLsFail() {
+	testCases := []struct {
+		name           string
+		request        *http.Request
+		expectedStatus int
+	}{
+		{
+			name:           "missing file",
+			request:        httptest.NewRequest(http.MethodPost, "/private/v1/short-urls/import", nil),
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "missing long_url column",
+			request:        importRequest("url\nhttps://example.com\n"),
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "file too large",
+			request:        importRequest("long_url\n" + strings.Repeat("https://example.com\n", 1000)),
+			expectedStatus: http.StatusRequestEntityTooLarge,
+		},
+	}
+
+	for _, testCase := range testCases {
+		suite.Run(testCase.name, func() {
+			handler := middleware.MaxBodySize(4096)(http.HandlerFunc(suite.handler.ImportShortURLs))
+
+			response := httptest.NewRecorder()
+			handler.ServeHTTP(response, testCase.request)
+
+			suite.Equal(testCase.expectedStatus, response.Code)
+		})
+	}
+}
+
+func (suite *HandlerSuite) TestListShortURLsSuccessFilterByTag() {
+	createdAt := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	suite.mockShortURLManager.EXPECT().ListShortURLs(gomock.Any(), &shorturl.ListFilter{Tag: "campaign:summer2025", Limit: 10, Offset: 0}).
+		Return([]*shorturl.ShortURL{
+			{Id: "AABBCC", LongURL: "https://example.com", Tags: []string{"campaign:summer2025"}, CreatedAt: createdAt, UpdatedAt: createdAt},
+		}, nil)
+
+	request := httptest.NewRequest(http.MethodGet, "/private/v1/short-urls?tag=campaign:summer2025&limit=10", nil)
+	response := httptest.NewRecorder()
+	suite.handler.ListShortURLs(response, request)
+
+	suite.Equal(http.StatusOK, response.Code)
+	suite.JSONEq(`{"short_urls": [{
+		"id": "AABBCC",
+		"short_url": "http://localhost:8080/public/v1/short-urls/AABBCC",
+		"long_url": "https://example.com",
+		"tags": ["campaign:summer2025"],
+		"created_at": "2025-06-01T12:00:00Z",
+		"updated_at": "2025-06-01T12:00:00Z"
+	}]}`, response.Body.String())
+}
+
+func (suite *HandlerSuite) TestListShortURLsSuccessDescription() {
+	createdAt := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	suite.mockShortURLManager.EXPECT().ListShortURLs(gomock.Any(), gomock.Any()).
+		Return([]*shorturl.ShortURL{
+			{Id: "AABBCC", LongURL: "https://example.com", Description: "Newsletter footer", CreatedAt: createdAt, UpdatedAt: createdAt},
+		}, nil)
+
+	request := httptest.NewRequest(http.MethodGet, "/private/v1/short-urls", nil)
+	response := httptest.NewRecorder()
+	suite.handler.ListShortURLs(response, request)
+
+	suite.Equal(http.StatusOK, response.Code)
+	suite.JSONEq(`{"short_urls": [{
+		"id": "AABBCC",
+		"short_url": "http://localhost:8080/public/v1/short-urls/AABBCC",
+		"long_url": "https://example.com",
+		"tags": [],
+		"description": "Newsletter footer",
+		"created_at": "2025-06-01T12:00:00Z",
+		"updated_at": "2025-06-01T12:00:00Z"
+	}]}`, response.Body.String())
+}
+
+func (suite *HandlerSuite) TestListShortURLsFailInvalidLimit() {
+	request := httptest.NewRequest(http.MethodGet, "/private/v1/short-urls?limit=100000", nil)
+	response := httptest.NewRecorder()
+	suite.handler.ListShortURLs(response, request)
+
+	suite.Equal(http.StatusBadRequest, response.Code)
+}
+
+// streamShortURLs returns a StreamShortURLs mock implementation sending the given short URLs followed by err
+func streamShortURLs(err error, shortURLs ...*shorturl.ShortURL) func(context.Context, *shorturl.ListFilter) (<-chan *shorturl.ShortURL, <-chan error) {
+	return func(context.Context, *shorturl.ListFilter) (<-chan *shorturl.ShortURL, <-chan error) {
+		streamed := make(chan *shorturl.ShortURL, len(shortURLs))
+		errs := make(chan error, 1)
+		for _, shortURL := range shortURLs {
+			streamed <- shortURL
+		}
+		if err != nil {
+			errs <- err
+		}
+		close(streamed)
+		close(errs)
+
+		return streamed, errs
+	}
+}
+
+func (suite *HandlerSuite) TestExportShortURLsSuccess() {
+	createdAt := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	shortURLs := []*shorturl.ShortURL{
+		{Id: "AABBCC", LongURL: "https://example.com/a", Tags: []string{"campaign", "news"}, CreatedAt: createdAt, UpdatedAt: createdAt},
+		{Id: "DDEEFF", LongURL: "https://example.com/b", Tags: []string{"campaign"}, Description: "Summer sale", CreatedAt: createdAt, UpdatedAt: createdAt},
+	}
+
+	suite.Run("json", func() {
+		suite.mockShortURLManager.EXPECT().StreamShortURLs(gomock.Any(), &shorturl.ListFilter{Tags: []string{"campaign"}}).
+			DoAndReturn(streamShortURLs(nil, shortURLs...))
+
+		request := httptest.NewRequest(http.MethodGet, "/private/v1/short-urls/export?tags=campaign", nil)
+		response := newDeadlineRecorder()
+		suite.handler.ExportShortURLs(response, request)
+
+		suite.True(response.deadlineCleared)
+		suite.Equal(http.StatusOK, response.Code)
+		suite.Equal("application/x-ndjson", response.Header().Get("Content-Type"))
+		lines := strings.Split(strings.TrimSpace(response.Body.String()), "\n")
+		suite.Require().Len(lines, 2)
+		suite.JSONEq(`{
+			"id": "AABBCC",
+			"short_url": "http://localhost:8080/public/v1/short-urls/AABBCC",
+			"long_url": "https://example.com/a",
+			"tags": ["campaign", "news"],
+			"created_at": "2025-06-01T12:00:00Z",
+			"updated_at": "2025-06-01T12:00:00Z"
+		}`, lines[0])
+		suite.JSONEq(`{
+			"id": "DDEEFF",
+			"short_url": "http://localhost:8080/public/v1/short-urls/DDEEFF",
+			"long_url": "https://example.com/b",
+			"tags": ["campaign"],
+			"description": "Summer sale",
+			"created_at": "2025-06-01T12:00:00Z",
+			"updated_at": "2025-06-01T12:00:00Z"
+		}`, lines[1])
+	})
+
+	suite.Run("csv", func() {
+		suite.mockShortURLManager.EXPECT().StreamShortURLs(gomock.Any(), &shorturl.ListFilter{Tags: []string{"campaign", "news"}}).
+			DoAndReturn(streamShortURLs(nil, shortURLs[0]))
+
+		request := httptest.NewRequest(http.MethodGet, "/private/v1/short-urls/export?format=csv&tags=campaign,news", nil)
+		response := newDeadlineRecorder()
+		suite.handler.ExportShortURLs(response, request)
+
+		suite.Equal(http.StatusOK, response.Code)
+		suite.Equal("text/csv", response.Header().Get("Content-Type"))
+		records, err := csv.NewReader(response.Body).ReadAll()
+		suite.Require().NoError(err)
+		suite.Equal([][]string{
+			{"id", "short_url", "long_url", "tags", "description", "created_at", "updated_at"},
+			{"AABBCC", "http://localhost:8080/public/v1/short-urls/AABBCC", "https://example.com/a", "campaign,news", "", "2025-06-01T12:00:00Z", "2025-06-01T12:00:00Z"},
+		}, records)
+	})
+}
+
+func (suite *HandlerSuite) TestExportShortURLsOutlivesWriteTimeout() {
+	shortURLs := make(chan *shorturl.ShortURL)
+	errs := make(chan error)
+	suite.mockShortURLManager.EXPECT().StreamShortURLs(gomock.Any(), gomock.Any()).Return(shortURLs, errs)
+
+	// Storage is slower than the server write timeout
+	go func() {
+		defer close(errs)
+		defer close(shortURLs)
+
+		for _, id := range []string{"AABBCC", "DDEEFF", "GGHHII"} {
+			time.Sleep(150 * time.Millisecond)
+			shortURLs <- &shorturl.ShortURL{Id: id, LongURL: "https://example.com/" + id}
+		}
+	}()
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(suite.handler.ExportShortURLs))
+	server.Config.WriteTimeout = 200 * time.Millisecond
+	server.Start()
+	defer server.Close()
+
+	response, err := http.Get(server.URL + "/private/v1/short-urls/export")
+	suite.Require().NoError(err)
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(response.Body)
+	suite.Require().NoError(err)
+	suite.Equal(http.StatusOK, response.StatusCode)
+	suite.Len(strings.Split(strings.TrimSpace(string(body)), "\n"), 3)
+}
+
+func (suite *HandlerSuite) TestExportShortURLsFail() {
+	suite.Run("unsupported format", func() {
+		request := httptest.NewRequest(http.MethodGet, "/private/v1/short-urls/export?format=xml", nil)
+		response := newDeadlineRecorder()
+		suite.handler.ExportShortURLs(response, request)
+
+		suite.Equal(http.StatusBadRequest, response.Code)
+	})
+
+	suite.Run("write deadline not supported", func() {
+		request := httptest.NewRequest(http.MethodGet, "/private/v1/short-urls/export", nil)
+		response := httptest.NewRecorder()
+		suite.handler.ExportShortURLs(response, request)
+
+		suite.Equal(http.StatusInternalServerError, response.Code)
+	})
+
+	suite.Run("storage error", func() {
+		suite.mockShortURLManager.EXPECT().StreamShortURLs(gomock.Any(), gomock.Any()).
+			DoAndReturn(streamShortURLs(errors.New("storage error")))
+
+		request := httptest.NewRequest(http.MethodGet, "/private/v1/short-urls/export", nil)
+		response := newDeadlineRecorder()
+		suite.handler.ExportShortURLs(response, request)
+
+		suite.Equal(http.StatusInternalServerError, response.Code)
+	})
+}
+
+func (suite *HandlerSuite) TestResolveShortURLsSuccess() {
+	suite.mockShortURLManager.EXPECT().GetLongURLBulk(gomock.Any(), []string{"AABBCC", "DDEEFF"}).
+		Return(map[string]string{"AABBCC": "https://example.com"}, []string{"DDEEFF"}, nil)
+
+	request := httptest.NewRequest(http.MethodGet, "/private/v1/short-urls/resolve?ids=AABBCC,%20DDEEFF,", nil)
+	response := httptest.NewRecorder()
+	suite.handler.ResolveShortURLs(response, request)
+
+	suite.Equal(http.StatusOK, response.Code)
+	suite.JSONEq(`{"long_urls": {"AABBCC": "https://example.com"}, "not_found": ["DDEEFF"]}`, response.Body.String())
+}
+
+func (suite *HandlerSuite) TestResolveShortURLsFail() {
+	suite.Run("no ids", func() {
+		request := httptest.NewRequest(http.MethodGet, "/private/v1/short-urls/resolve?ids=,", nil)
+		response := httptest.NewRecorder()
+		suite.handler.ResolveShortURLs(response, request)
+
+		suite.Equal(http.StatusBadRequest, response.Code)
+	})
+
+	suite.Run("too many ids", func() {
+		ids := strings.TrimSuffix(strings.Repeat("AABBCC,", 101), ",")
+		request := httptest.NewRequest(http.MethodGet, "/private/v1/short-urls/resolve?ids="+ids, nil)
+		response := httptest.NewRecorder()
+		suite.handler.ResolveShortURLs(response, request)
+
+		suite.Equal(http.StatusBadRequest, response.Code)
+	})
+
+	suite.Run("storage error", func() {
+		suite.mockShortURLManager.EXPECT().GetLongURLBulk(gomock.Any(), []string{"AABBCC"}).Return(nil, nil, errors.New("storage error"))
+
+		request := httptest.NewRequest(http.MethodGet, "/private/v1/short-urls/resolve?ids=AABBCC", nil)
+		response := httptest.NewRecorder()
+		suite.handler.ResolveShortURLs(response, request)
+
+		suite.Equal(http.StatusInternalServerError, response.Code)
+	})
+}
+
+func (suite *HandlerSuite) TestCheckShortURL() {
+	testCases := []struct {
+		name           string
+		err            error
+		expectedStatus int
+	}{
+		{name: "active", err: nil, expectedStatus: http.StatusOK},
+		{name: "not found", err: shorturl.ErrShortURLNotFound, expectedStatus: http.StatusNotFound},
+		{name: "expired", err: shorturl.ErrShortURLExpired, expectedStatus: http.StatusGone},
+		{name: "archived", err: shorturl.ErrShortURLArchived, expectedStatus: http.StatusGone},
+		{name: "click limit exceeded", err: shorturl.ErrClickLimitExceeded, expectedStatus: http.StatusGone},
+		{name: "paused", err: shorturl.ErrShortURLPaused, expectedStatus: http.StatusServiceUnavailable},
+		{name: "storage error", err: errors.New("storage error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			// No visit is recorded and no webhook notified, the metrics and webhook mocks expect no calls
+			suite.mockShortURLManager.EXPECT().CheckShortURL(gomock.Any(), "AABBCC").Return(tc.err)
+
+			request := withURLParams(httptest.NewRequest(http.MethodHead, "/public/v1/short-urls/AABBCC", nil),
+				map[string]string{"shortURLId": "AABBCC"})
+			response := httptest.NewRecorder()
+			suite.handler.CheckShortURL(response, request)
+
+			suite.Equal(tc.expectedStatus, response.Code)
+			suite.Empty(response.Body.String())
+			suite.Empty(response.Header().Get("Location"))
+		})
+	}
+}
+
+func (suite *HandlerSuite) TestRedirectToLongURLNotifiesWebhooks() {
+	suite.mockShortURLManager.EXPECT().GetLongURL(gomock.Any(), "AABBCC").
+		Return(&shorturl.ShortURLResult{ShortURLId: "AABBCC", LongURL: "https://example.com", RedirectCode: http.StatusFound}, nil)
+	suite.mockMetricsManager.EXPECT().RecordShortURLRequestAsync(tenant.Default, "AABBCC", gomock.Any(), gomock.Any(), gomock.Any())
+	suite.mockWebhookManager.EXPECT().NotifyClickAsync(tenant.Default, "AABBCC")
+
+	request := withURLParams(httptest.NewRequest(http.MethodGet, "/public/v1/short-urls/AABBCC", nil),
+		map[string]string{"shortURLId": "AABBCC"})
+	response := httptest.NewRecorder()
+	suite.handler.RedirectToLongURL(response, request)
+
+	suite.Equal(http.StatusFound, response.Code)
+	suite.Equal("https://example.com", response.Header().Get("Location"))
+}
+
+func (suite *HandlerSuite) TestRedirectToLongURLRedirectCode() {
+	suite.mockShortURLManager.EXPECT().GetLongURL(gomock.Any(), "AABBCC").
+		Return(&shorturl.ShortURLResult{ShortURLId: "AABBCC", LongURL: "https://example.com", RedirectCode: http.StatusMovedPermanently}, nil)
+	suite.mockMetricsManager.EXPECT().RecordShortURLRequestAsync(tenant.Default, "AABBCC", gomock.Any(), gomock.Any(), gomock.Any())
+	suite.mockWebhookManager.EXPECT().NotifyClickAsync(tenant.Default, "AABBCC")
+
+	request := withURLParams(httptest.NewRequest(http.MethodGet, "/public/v1/short-urls/AABBCC", nil),
+		map[string]string{"shortURLId": "AABBCC"})
+	response := httptest.NewRecorder()
+	suite.handler.RedirectToLongURL(response, request)
+
+	suite.Equal(http.StatusMovedPermanently, response.Code)
+	suite.Equal("https://example.com", response.Header().Get("Location"))
+}
+
+func (suite *HandlerSuite) TestRedirectToLongURLInterstitial() {
+	suite.mockShortURLManager.EXPECT().GetLongURL(gomock.Any(), "AABBCC").
+		Return(&shorturl.ShortURLResult{ShortURLId: "AABBCC", LongURL: "https://example.com/landing", RedirectCode: http.StatusFound,
+			ShowInterstitial: true}, nil)
+	suite.mockMetricsManager.EXPECT().RecordShortURLRequestAsync(tenant.Default, "AABBCC", gomock.Any(), gomock.Any(), gomock.Any())
+	suite.mockWebhookManager.EXPECT().NotifyClickAsync(tenant.Default, "AABBCC")
+	suite.mockMetricsManager.EXPECT().GetShortURLMetrics(gomock.Any(), "AABBCC", time.Time{}, gomock.Any()).
+		Return(&metrics.Metrics{ShortURLId: "AABBCC", Visits: 42}, nil)
+
+	request := withURLParams(httptest.NewRequest(http.MethodGet, "/public/v1/short-urls/AABBCC", nil),
+		map[string]string{"shortURLId": "AABBCC"})
+	response := httptest.NewRecorder()
+	suite.handler.RedirectToLongURL(response, request)
+
+	suite.Equal(http.StatusOK, response.Code)
+	suite.Equal("text/html; charset=utf-8", response.Header().Get("Content-Type"))
+	suite.Empty(response.Header().Get("Location"))
+	suite.Contains(response.Body.String(), `<meta http-equiv="refresh" content="5; url=https://example.com/landing">`)
+	suite.Contains(response.Body.String(), `<a href="https://example.com/landing">https://example.com/landing</a>`)
+	suite.Contains(response.Body.String(), "followed 42 times")
+}
+
+func (suite *HandlerSuite) TestRedirectToLongURLInterstitialWithoutClickCount() {
+	suite.mockShortURLManager.EXPECT().GetLongURL(gomock.Any(), "AABBCC").
+		Return(&shorturl.ShortURLResult{ShortURLId: "AABBCC", LongURL: "https://example.com", ShowInterstitial: true}, nil)
+	suite.mockMetricsManager.EXPECT().RecordShortURLRequestAsync(tenant.Default, "AABBCC", gomock.Any(), gomock.Any(), gomock.Any())
+	suite.mockWebhookManager.EXPECT().NotifyClickAsync(tenant.Default, "AABBCC")
+	suite.mockMetricsManager.EXPECT().GetShortURLMetrics(gomock.Any(), "AABBCC", gomock.Any(), gomock.Any()).
+		Return(nil, errors.New("storage error"))
+
+	request := withURLParams(httptest.NewRequest(http.MethodGet, "/public/v1/short-urls/AABBCC", nil),
+		map[string]string{"shortURLId": "AABBCC"})
+	response := httptest.NewRecorder()
+	suite.handler.RedirectToLongURL(response, request)
+
+	suite.Equal(http.StatusOK, response.Code)
+	suite.Contains(response.Body.String(), "https://example.com")
+	suite.NotContains(response.Body.String(), "followed")
+}
+
+func (suite *HandlerSuite) TestRedirectToLongURLInternationalizedURL() {
+	testCases := []struct {
+		name               string
+		forwardQueryParams bool
+		expectedLocation   string
+	}{
+		{
+			name:             "no forwarding",
+			expectedLocation: "https://xn--mnchen-3ya.de/b%C3%BCcher",
+		},
+		{
+			name:               "forwarding",
+			forwardQueryParams: true,
+			expectedLocation:   "https://xn--mnchen-3ya.de/b%C3%BCcher?utm_source=newsletter",
+		},
+	}
+
+	for _, testCase := range testCases {
+		suite.Run(testCase.name, func() {
+			suite.mockShortURLManager.EXPECT().GetLongURL(gomock.Any(), "AABBCC").Return(&shorturl.ShortURLResult{
+				ShortURLId:         "AABBCC",
+				LongURL:            "https://xn--mnchen-3ya.de/b%C3%BCcher",
+				RedirectCode:       http.StatusFound,
+				ForwardQueryParams: testCase.forwardQueryParams,
+			}, nil)
+			suite.mockMetricsManager.EXPECT().RecordShortURLRequestAsync(tenant.Default, "AABBCC", gomock.Any(), gomock.Any(), gomock.Any())
+			suite.mockWebhookManager.EXPECT().NotifyClickAsync(tenant.Default, "AABBCC")
+
+			request := withURLParams(httptest.NewRequest(http.MethodGet, "/public/v1/short-urls/AABBCC?utm_source=newsletter", nil),
+				map[string]string{"shortURLId": "AABBCC"})
+			response := httptest.NewRecorder()
+			suite.handler.RedirectToLongURL(response, request)
+
+			suite.Equal(http.StatusFound, response.Code)
+			suite.Equal(testCase.expectedLocation, response.Header().Get("Location"))
+		})
+	}
+}
+
+func (suite *HandlerSuite) TestRedirectToLongURLForwardQueryParams() {
+	testCases := []struct {
+		name               string
+		longURL            string
+		forwardQueryParams bool
+		path               string
+		expectedLocation   string
+	}{
+		{
+			name:             "no forwarding",
+			longURL:          "https://example.com/landing",
+			path:             "/public/v1/short-urls/AABBCC?utm_source=newsletter",
+			expectedLocation: "https://example.com/landing",
+		},
+		{
+			name:               "forwarding without params",
+			longURL:            "https://example.com/landing",
+			forwardQueryParams: true,
+			path:               "/public/v1/short-urls/AABBCC",
+			expectedLocation:   "https://example.com/landing",
+		},
+		{
+			name:               "forwarding with params",
+			longURL:            "https://example.com/landing",
+			forwardQueryParams: true,
+			path:               "/public/v1/short-urls/AABBCC?utm_source=news%20letter&ref=a%26b",
+			expectedLocation:   "https://example.com/landing?ref=a%26b&utm_source=news+letter",
+		},
+		{
+			name:               "forwarding when the long URL has params",
+			longURL:            "https://example.com/landing?lang=en#top",
+			forwardQueryParams: true,
+			path:               "/public/v1/short-urls/AABBCC?utm_source=newsletter",
+			expectedLocation:   "https://example.com/landing?lang=en&utm_source=newsletter#top",
+		},
+	}
+
+	for _, testCase := range testCases {
+		suite.Run(testCase.name, func() {
+			suite.mockShortURLManager.EXPECT().GetLongURL(gomock.Any(), "AABBCC").Return(&shorturl.ShortURLResult{
+				ShortURLId:         "AABBCC",
+				LongURL:            testCase.longURL,
+				RedirectCode:       http.StatusFound,
+				ForwardQueryParams: testCase.forwardQueryParams,
+			}, nil)
+			suite.mockMetricsManager.EXPECT().RecordShortURLRequestAsync(tenant.Default, "AABBCC", gomock.Any(), gomock.Any(), gomock.Any())
+			suite.mockWebhookManager.EXPECT().NotifyClickAsync(tenant.Default, "AABBCC")
+
+			request := withURLParams(httptest.NewRequest(http.MethodGet, testCase.path, nil), map[string]string{"shortURLId": "AABBCC"})
+			response := httptest.NewRecorder()
+			suite.handler.RedirectToLongURL(response, request)
+
+			suite.Equal(http.StatusFound, response.Code)
+			suite.Equal(testCase.expectedLocation, response.Header().Get("Location"))
+		})
+	}
+}
+
+func (suite *HandlerSuite) TestRedirectToLongURLForwardQueryParamsDropsToken() {
+	suite.mockTokenSigner.EXPECT().Validate("valid-token", "AABBCC").Return(nil)
+	suite.mockShortURLManager.EXPECT().UnlockLongURL(gomock.Any(), "AABBCC").Return(&shorturl.ShortURLResult{
+		ShortURLId:         "AABBCC",
+		LongURL:            "https://example.com",
+		RedirectCode:       http.StatusFound,
+		ForwardQueryParams: true,
+	}, nil)
+	suite.mockMetricsManager.EXPECT().RecordShortURLRequestAsync(tenant.Default, "AABBCC", gomock.Any(), gomock.Any(), gomock.Any())
+	suite.mockWebhookManager.EXPECT().NotifyClickAsync(tenant.Default, "AABBCC")
+
+	request := withURLParams(httptest.NewRequest(http.MethodGet, "/public/v1/short-urls/AABBCC?token=valid-token&ref=mail", nil),
+		map[string]string{"shortURLId": "AABBCC"})
+	response := httptest.NewRecorder()
+	suite.handler.RedirectToLongURL(response, request)
+
+	suite.Equal(http.StatusFound, response.Code)
+	suite.Equal("https://example.com?ref=mail", response.Header().Get("Location"))
+}
+
+func (suite *HandlerSuite) TestRedirectToLongURLFailClickLimitExceeded() {
+	suite.mockShortURLManager.EXPECT().GetLongURL(gomock.Any(), "AABBCC").Return(nil, shorturl.ErrClickLimitExceeded)
+
+	request := withURLParams(httptest.NewRequest(http.MethodGet, "/public/v1/short-urls/AABBCC", nil),
+		map[string]string{"shortURLId": "AABBCC"})
+	response := httptest.NewRecorder()
+	suite.handler.RedirectToLongURL(response, request)
+
+	suite.Equal(http.StatusGone, response.Code)
+}
+
+func (suite *HandlerSuite) TestRedirectToLongURLFailExpired() {
+	suite.mockShortURLManager.EXPECT().GetLongURL(gomock.Any(), "AABBCC").Return(nil, shorturl.ErrShortURLExpired)
+
+	request := withURLParams(httptest.NewRequest(http.MethodGet, "/public/v1/short-urls/AABBCC", nil),
+		map[string]string{"shortURLId": "AABBCC"})
+	response := httptest.NewRecorder()
+	suite.handler.RedirectToLongURL(response, request)
+
+	suite.Equal(http.StatusGone, response.Code)
+}
+
+func (suite *HandlerSuite) TestRedirectToLongURLFailClickRateLimitExceeded() {
+	suite.mockShortURLManager.EXPECT().GetLongURL(gomock.Any(), "AABBCC").Return(nil, shorturl.ErrClickRateLimitExceeded)
+
+	request := withURLParams(httptest.NewRequest(http.MethodGet, "/public/v1/short-urls/AABBCC", nil),
+		map[string]string{"shortURLId": "AABBCC"})
+	response := httptest.NewRecorder()
+	suite.handler.RedirectToLongURL(response, request)
+
+	suite.Equal(http.StatusTooManyRequests, response.Code)
+	suite.Equal("1", response.Header().Get("Retry-After"))
+}
+
+func (suite *HandlerSuite) TestRegisterWebhookSuccess() {
+	createdAt := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	suite.mockShortURLManager.EXPECT().GetShortURL(gomock.Any(), "AABBCC").
+		Return(&shorturl.ShortURL{Id: "AABBCC", LongURL: "https://example.com"}, nil)
+	suite.mockWebhookManager.EXPECT().RegisterWebhook(gomock.Any(), "AABBCC", "https://hooks.example.com", "secret").
+		Return(&webhook.Webhook{Id: 7, ShortURLId: "AABBCC", URL: "https://hooks.example.com", Secret: "secret", CreatedAt: createdAt}, nil)
+
+	request := withURLParams(httptest.NewRequest(http.MethodPost, "/private/v1/short-urls/AABBCC/webhooks",
+		strings.NewReader(`{"url":"https://hooks.example.com","secret":"secret"}`)), map[string]string{"shortURLId": "AABBCC"})
+	response := httptest.NewRecorder()
+	suite.handler.RegisterWebhook(response, request)
+
+	suite.Equal(http.StatusCreated, response.Code)
+	suite.JSONEq(`{
+		"id": 7,
+		"short_url_id": "AABBCC",
+		"url": "https://hooks.example.com",
+		"created_at": "2025-06-01T12:00:00Z"
+	}`, response.Body.String())
+}
+
+func (suite *HandlerSuite) TestRegisterWebhookFailNotFound() {
+	suite.mockShortURLManager.EXPECT().GetShortURL(gomock.Any(), "AABBCC").Return(nil, shorturl.ErrShortURLNotFound)
+
+	request := withURLParams(httptest.NewRequest(http.MethodPost, "/private/v1/short-urls/AABBCC/webhooks",
+		strings.NewReader(`{"url":"https://hooks.example.com","secret":"secret"}`)), map[string]string{"shortURLId": "AABBCC"})
+	response := httptest.NewRecorder()
+	suite.handler.RegisterWebhook(response, request)
+
+	suite.Equal(http.StatusNotFound, response.Code)
+}
+
+func (suite *HandlerSuite) TestDeleteWebhookFailNotFound() {
+	suite.mockWebhookManager.EXPECT().DeleteWebhook(gomock.Any(), "AABBCC", int64(7)).Return(webhook.ErrWebhookNotFound)
+
+	request := withURLParams(httptest.NewRequest(http.MethodDelete, "/private/v1/short-urls/AABBCC/webhooks/7", nil),
+		map[string]string{"shortURLId": "AABBCC", "webhookId": "7"})
+	response := httptest.NewRecorder()
+	suite.handler.DeleteWebhook(response, request)
+
+	suite.Equal(http.StatusNotFound, response.Code)
+}
+
+func (suite *HandlerSuite) TestDeleteWebhookFailInvalidId() {
+	request := withURLParams(httptest.NewRequest(http.MethodDelete, "/private/v1/short-urls/AABBCC/webhooks/abc", nil),
+		map[string]string{"shortURLId": "AABBCC", "webhookId": "abc"})
+	response := httptest.NewRecorder()
+	suite.handler.DeleteWebhook(response, request)
+
+	suite.Equal(http.StatusBadRequest, response.Code)
+}
+
+func (suite *HandlerSuite) TestRedirectToLongURLFailInactive() {
+	testCases := []struct {
+		name           string
+		err            error
+		expectedStatus int
+	}{
+		{name: "paused", err: shorturl.ErrShortURLPaused, expectedStatus: http.StatusServiceUnavailable},
+		{name: "archived", err: shorturl.ErrShortURLArchived, expectedStatus: http.StatusGone},
+	}
+
+	for _, testCase := range testCases {
+		suite.Run(testCase.name, func() {
+			suite.mockShortURLManager.EXPECT().GetLongURL(gomock.Any(), "AABBCC").Return(nil, testCase.err)
+
+			request := withURLParams(httptest.NewRequest(http.MethodGet, "/public/v1/short-urls/AABBCC", nil),
+				map[string]string{"shortURLId": "AABBCC"})
+			response := httptest.NewRecorder()
+			suite.handler.RedirectToLongURL(response, request)
+
+			suite.Equal(testCase.expectedStatus, response.Code)
+			suite.Empty(response.Header().Get("Location"))
+		})
+	}
+}
+
+func (suite *HandlerSuite) TestUpdateShortURLStatus() {
+	testCases := []struct {
+		name           string
+		path           string
+		serve          func(w http.ResponseWriter, r *http.Request)
+		status         shorturl.Status
+		err            error
+		expectedStatus int
+	}{
+		{name: "pause", path: "pause", serve: suite.handler.PauseShortURL, status: shorturl.StatusPaused, expectedStatus: http.StatusOK},
+		{name: "resume", path: "resume", serve: suite.handler.ResumeShortURL, status: shorturl.StatusActive, expectedStatus: http.StatusOK},
+		{name: "archive", path: "archive", serve: suite.handler.ArchiveShortURL, status: shorturl.StatusArchived, expectedStatus: http.StatusOK},
+		{
+			name:           "resume archived",
+			path:           "resume",
+			serve:          suite.handler.ResumeShortURL,
+			status:         shorturl.StatusActive,
+			err:            fmt.Errorf("%w: from archived to active", shorturl.ErrInvalidStatusTransition),
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "not found",
+			path:           "pause",
+			serve:          suite.handler.PauseShortURL,
+			status:         shorturl.StatusPaused,
+			err:            shorturl.ErrShortURLNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "storage error",
+			path:           "archive",
+			serve:          suite.handler.ArchiveShortURL,
+			status:         shorturl.StatusArchived,
+			err:            errors.New("some storage error"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, testCase := range testCases {
+		suite.Run(testCase.name, func() {
+			suite.mockShortURLManager.EXPECT().UpdateShortURLStatus(gomock.Any(), "AABBCC", testCase.status).
+				DoAndReturn(func(ctx context.Context, shortURLId string, status shorturl.Status) error {
+					suite.Equal("operator", shorturl.ActorFromContext(ctx))
+
+					return testCase.err
+				})
+
+			request := withURLParams(httptest.NewRequest(http.MethodPost, "/private/v1/short-urls/AABBCC/"+testCase.path, nil),
+				map[string]string{"shortURLId": "AABBCC"})
+			request.Header.Set(handlers.ActorHeader, "operator")
+			response := httptest.NewRecorder()
+			testCase.serve(response, request)
+
+			suite.Equal(testCase.expectedStatus, response.Code)
+		})
+	}
+}
+
+func (suite *HandlerSuite) TestCreateAliasSuccess() {
+	suite.mockShortURLManager.EXPECT().CreateAlias(gomock.Any(), "AABBCC", "OLDID1").Return(nil)
+
+	request := withURLParams(httptest.NewRequest(http.MethodPost, "/private/v1/short-urls/AABBCC/aliases",
+		strings.NewReader(`{"alias_id":"OLDID1"}`)), map[string]string{"shortURLId": "AABBCC"})
+	response := httptest.NewRecorder()
+	suite.handler.CreateAlias(response, request)
+
+	suite.Equal(http.StatusCreated, response.Code)
+	suite.JSONEq(`{
+		"alias_id": "OLDID1",
+		"short_url_id": "AABBCC",
+		"short_url": "http://localhost:8080/public/v1/short-urls/OLDID1"
+	}`, response.Body.String())
+}
+
+func (suite *HandlerSuite) TestCreateAliasFail() {
+	testCases := []struct {
+		name           string
+		err            error
+		expectedStatus int
+	}{
+		{name: "invalid alias id", err: shorturl.ErrInvalidAliasId, expectedStatus: http.StatusBadRequest},
+		{name: "alias id too long", err: shorturl.ErrSlugTooLong, expectedStatus: http.StatusBadRequest},
+		{name: "alias id with invalid characters", err: shorturl.ErrSlugInvalidChars, expectedStatus: http.StatusBadRequest},
+		{name: "short URL not found", err: shorturl.ErrShortURLNotFound, expectedStatus: http.StatusNotFound},
+		{name: "alias exists", err: shorturl.ErrAliasExists, expectedStatus: http.StatusConflict},
+		{name: "alias id is a short URL", err: shorturl.ErrShortURLExists, expectedStatus: http.StatusConflict},
+		{name: "storage error", err: errors.New("some storage error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, testCase := range testCases {
+		suite.Run(testCase.name, func() {
+			suite.mockShortURLManager.EXPECT().CreateAlias(gomock.Any(), "AABBCC", "OLDID1").Return(testCase.err)
+
+			request := withURLParams(httptest.NewRequest(http.MethodPost, "/private/v1/short-urls/AABBCC/aliases",
+				strings.NewReader(`{"alias_id":"OLDID1"}`)), map[string]string{"shortURLId": "AABBCC"})
+			response := httptest.NewRecorder()
+			suite.handler.CreateAlias(response, request)
+
+			suite.Equal(testCase.expectedStatus, response.Code)
+		})
+	}
+}
+
+func (suite *HandlerSuite) TestDeleteAliasSuccess() {
+	suite.mockShortURLManager.EXPECT().DeleteAlias(gomock.Any(), "AABBCC", "OLDID1").Return(nil)
+
+	request := withURLParams(httptest.NewRequest(http.MethodDelete, "/private/v1/short-urls/AABBCC/aliases/OLDID1", nil),
+		map[string]string{"shortURLId": "AABBCC", "aliasId": "OLDID1"})
+	response := httptest.NewRecorder()
+	suite.handler.DeleteAlias(response, request)
+
+	suite.Equal(http.StatusOK, response.Code)
+}
+
+func (suite *HandlerSuite) TestDeleteAliasFailNotFound() {
+	suite.mockShortURLManager.EXPECT().DeleteAlias(gomock.Any(), "AABBCC", "OLDID1").Return(shorturl.ErrAliasNotFound)
+
+	request := withURLParams(httptest.NewRequest(http.MethodDelete, "/private/v1/short-urls/AABBCC/aliases/OLDID1", nil),
+		map[string]string{"shortURLId": "AABBCC", "aliasId": "OLDID1"})
+	response := httptest.NewRecorder()
+	suite.handler.DeleteAlias(response, request)
+
+	suite.Equal(http.StatusNotFound, response.Code)
+}
+
+func (suite *HandlerSuite) TestRedirectToLongURLAlias() {
+	suite.mockShortURLManager.EXPECT().GetLongURL(gomock.Any(), "OLDID1").
+		Return(&shorturl.ShortURLResult{ShortURLId: "AABBCC", LongURL: "https://example.com", RedirectCode: http.StatusFound}, nil)
+	suite.mockMetricsManager.EXPECT().RecordShortURLRequestAsync(tenant.Default, "AABBCC", gomock.Any(), gomock.Any(), gomock.Any())
+	suite.mockWebhookManager.EXPECT().NotifyClickAsync(tenant.Default, "AABBCC")
+
+	request := withURLParams(httptest.NewRequest(http.MethodGet, "/public/v1/short-urls/OLDID1", nil),
+		map[string]string{"shortURLId": "OLDID1"})
+	response := httptest.NewRecorder()
+	suite.handler.RedirectToLongURL(response, request)
+
+	suite.Equal(http.StatusFound, response.Code)
+	suite.Equal("https://example.com", response.Header().Get("Location"))
+}
+
+func (suite *HandlerSuite) TestRedirectToLongURLProtectedChallenge() {
+	suite.mockShortURLManager.EXPECT().GetLongURL(gomock.Any(), "AABBCC").Return(nil, shorturl.ErrPasswordRequired)
+
+	request := withURLParams(httptest.NewRequest(http.MethodGet, "/public/v1/short-urls/AABBCC", nil),
+		map[string]string{"shortURLId": "AABBCC"})
+	response := httptest.NewRecorder()
+	suite.handler.RedirectToLongURL(response, request)
+
+	suite.Equal(http.StatusOK, response.Code)
+	suite.JSONEq(`{"protected": true}`, response.Body.String())
+}
+
+func (suite *HandlerSuite) TestRedirectToLongURLWithToken() {
+	suite.mockTokenSigner.EXPECT().Validate("valid-token", "AABBCC").Return(nil)
+	suite.mockShortURLManager.EXPECT().UnlockLongURL(gomock.Any(), "AABBCC").
+		Return(&shorturl.ShortURLResult{ShortURLId: "AABBCC", LongURL: "https://example.com", RedirectCode: http.StatusFound}, nil)
+	suite.mockMetricsManager.EXPECT().RecordShortURLRequestAsync(tenant.Default, "AABBCC", gomock.Any(), gomock.Any(), gomock.Any())
+	suite.mockWebhookManager.EXPECT().NotifyClickAsync(tenant.Default, "AABBCC")
+
+	request := withURLParams(httptest.NewRequest(http.MethodGet, "/public/v1/short-urls/AABBCC?token=valid-token", nil),
+		map[string]string{"shortURLId": "AABBCC"})
+	response := httptest.NewRecorder()
+	suite.handler.RedirectToLongURL(response, request)
+
+	suite.Equal(http.StatusFound, response.Code)
+	suite.Equal("https://example.com", response.Header().Get("Location"))
+}
+
+func (suite *HandlerSuite) TestRedirectToLongURLWithTokenTenant() {
+	suite.mockTokenSigner.EXPECT().Validate("valid-token", "acme/AABBCC").Return(nil)
+	suite.mockShortURLManager.EXPECT().UnlockLongURL(gomock.Any(), "AABBCC").
+		Return(&shorturl.ShortURLResult{ShortURLId: "AABBCC", LongURL: "https://example.com", RedirectCode: http.StatusFound}, nil)
+	suite.mockMetricsManager.EXPECT().RecordShortURLRequestAsync("acme", "AABBCC", gomock.Any(), gomock.Any(), gomock.Any())
+	suite.mockWebhookManager.EXPECT().NotifyClickAsync("acme", "AABBCC")
+
+	request := withURLParams(httptest.NewRequest(http.MethodGet, "/public/v1/tenants/acme/short-urls/AABBCC?token=valid-token", nil),
+		map[string]string{"shortURLId": "AABBCC"})
+	request = request.WithContext(tenant.WithID(request.Context(), "acme"))
+	response := httptest.NewRecorder()
+	suite.handler.RedirectToLongURL(response, request)
+
+	suite.Equal(http.StatusFound, response.Code)
+	suite.Equal("https://example.com", response.Header().Get("Location"))
+}
+
+func (suite *HandlerSuite) TestRedirectToLongURLFailInvalidToken() {
+	suite.mockTokenSigner.EXPECT().Validate("expired-token", "AABBCC").Return(errors.New("expired token"))
+
+	request := withURLParams(httptest.NewRequest(http.MethodGet, "/public/v1/short-urls/AABBCC?token=expired-token", nil),
+		map[string]string{"shortURLId": "AABBCC"})
+	response := httptest.NewRecorder()
+	suite.handler.RedirectToLongURL(response, request)
+
+	suite.Equal(http.StatusForbidden, response.Code)
+}
+
+func (suite *HandlerSuite) TestUnlockShortURLSuccess() {
+	expiresAt := time.Date(2025, 6, 1, 12, 5, 0, 0, time.UTC)
+
+	suite.mockShortURLManager.EXPECT().CheckPassword(gomock.Any(), "AABBCC", "hunter2").Return(nil)
+	suite.mockTokenSigner.EXPECT().Generate("AABBCC").Return("signed-token", expiresAt, nil)
+
+	request := withURLParams(httptest.NewRequest(http.MethodPost, "/public/v1/short-urls/AABBCC/unlock",
+		strings.NewReader(`{"password":"hunter2"}`)), map[string]string{"shortURLId": "AABBCC"})
+	response := httptest.NewRecorder()
+	suite.handler.UnlockShortURL(response, request)
+
+	suite.Equal(http.StatusOK, response.Code)
+	suite.JSONEq(`{"token": "signed-token", "expires_at": "2025-06-01T12:05:00Z"}`, response.Body.String())
+}
+
+func (suite *HandlerSuite) TestUnlockShortURLFailInvalidPassword() {
+	suite.mockShortURLManager.EXPECT().CheckPassword(gomock.Any(), "AABBCC", "wrong").Return(shorturl.ErrInvalidPassword)
+
+	request := withURLParams(httptest.NewRequest(http.MethodPost, "/public/v1/short-urls/AABBCC/unlock",
+		strings.NewReader(`{"password":"wrong"}`)), map[string]string{"shortURLId": "AABBCC"})
+	response := httptest.NewRecorder()
+	suite.handler.UnlockShortURL(response, request)
+
+	suite.Equal(http.StatusUnauthorized, response.Code)
+}
+
+func (suite *HandlerSuite) TestUnlockShortURLFailNotProtected() {
+	suite.mockShortURLManager.EXPECT().CheckPassword(gomock.Any(), "AABBCC", "hunter2").Return(shorturl.ErrNotProtected)
+
+	request := withURLParams(httptest.NewRequest(http.MethodPost, "/public/v1/short-urls/AABBCC/unlock",
+		strings.NewReader(`{"password":"hunter2"}`)), map[string]string{"shortURLId": "AABBCC"})
+	response := httptest.NewRecorder()
+	suite.handler.UnlockShortURL(response, request)
+
+	suite.Equal(http.StatusBadRequest, response.Code)
+}
+
+func (suite *HandlerSuite) TestPreviewShortURLProtectedHidesLongURL() {
+	shortURL := &shorturl.ShortURL{
+		Id:           "AABBCC",
+		LongURL:      "https://example.com/secret",
+		PasswordHash: "$2a$10$hash",
+		CreatedAt:    time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC),
+		UpdatedAt:    time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC),
+	}
+	suite.mockShortURLManager.EXPECT().GetShortURL(gomock.Any(), "AABBCC").Return(shortURL, nil)
+
+	request := withURLParams(httptest.NewRequest(http.MethodGet, "/public/v1/short-urls/AABBCC/preview", nil),
+		map[string]string{"shortURLId": "AABBCC"})
+	response := httptest.NewRecorder()
+	suite.handler.PreviewShortURL(response, request)
+
+	suite.Equal(http.StatusOK, response.Code)
+	suite.JSONEq(`{
+		"id": "AABBCC",
+		"protected": true,
+		"tags": [],
+		"created_at": "2025-06-01T12:00:00Z",
+		"updated_at": "2025-06-01T12:00:00Z"
+	}`, response.Body.String())
+	suite.NotContains(response.Body.String(), "secret")
+}
+
+func (suite *HandlerSuite) TestPreviewShortURLDescription() {
+	shortURL := &shorturl.ShortURL{
+		Id:          "AABBCC",
+		LongURL:     "https://example.com",
+		Description: "Spring sale banner",
+		CreatedAt:   time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC),
+		UpdatedAt:   time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC),
+	}
+	suite.mockShortURLManager.EXPECT().GetShortURL(gomock.Any(), "AABBCC").Return(shortURL, nil)
+
+	request := withURLParams(httptest.NewRequest(http.MethodGet, "/public/v1/short-urls/AABBCC/preview", nil),
+		map[string]string{"shortURLId": "AABBCC"})
+	response := httptest.NewRecorder()
+	suite.handler.PreviewShortURL(response, request)
+
+	suite.Equal(http.StatusOK, response.Code)
+	suite.JSONEq(`{
+		"id": "AABBCC",
+		"long_url": "https://example.com",
+		"tags": [],
+		"description": "Spring sale banner",
+		"created_at": "2025-06-01T12:00:00Z",
+		"updated_at": "2025-06-01T12:00:00Z"
+	}`, response.Body.String())
+}
+
+func (suite *HandlerSuite) TestRedirectToLongURLJustExpired() {
+	expiredAt := time.Now().Add(-time.Second)
+
+	// The short URL expired after the manager read it, it is not followed nor counted as a click
+	suite.mockShortURLManager.EXPECT().GetLongURL(gomock.Any(), "AABBCC").Return(&shorturl.ShortURLResult{
+		ShortURLId:   "AABBCC",
+		LongURL:      "https://example.com",
+		RedirectCode: http.StatusFound,
+		ExpiresAt:    &expiredAt,
+	}, nil)
+
+	request := withURLParams(httptest.NewRequest(http.MethodGet, "/public/v1/short-urls/AABBCC", nil), map[string]string{"shortURLId": "AABBCC"})
+	response := httptest.NewRecorder()
+	suite.handler.RedirectToLongURL(response, request)
+
+	suite.Equal(http.StatusGone, response.Code)
+	suite.Empty(response.Header().Get("Location"))
+}
+
+func (suite *HandlerSuite) TestRedirectToLongURLExpiryHeaders() {
+	expiresIn := time.Now().Add(time.Hour).Truncate(time.Second)
+
+	testCases := []struct {
+		name              string
+		expiresAt         *time.Time
+		expectedTTL       int64
+		expectedExpiresAt string
+	}{
+		{
+			name:              "expiring",
+			expiresAt:         &expiresIn,
+			expectedTTL:       3600,
+			expectedExpiresAt: expiresIn.UTC().Format(time.RFC3339),
+		},
+		{
+			name: "not expiring",
+		},
+	}
+
+	for _, testCase := range testCases {
+		suite.Run(testCase.name, func() {
+			suite.mockShortURLManager.EXPECT().GetLongURL(gomock.Any(), "AABBCC").Return(&shorturl.ShortURLResult{
+				ShortURLId:   "AABBCC",
+				LongURL:      "https://example.com",
+				RedirectCode: http.StatusFound,
+				ExpiresAt:    testCase.expiresAt,
+			}, nil)
+			suite.mockMetricsManager.EXPECT().RecordShortURLRequestAsync(tenant.Default, "AABBCC", gomock.Any(), gomock.Any(), gomock.Any())
+			suite.mockWebhookManager.EXPECT().NotifyClickAsync(tenant.Default, "AABBCC")
+
+			request := withURLParams(httptest.NewRequest(http.MethodGet, "/public/v1/short-urls/AABBCC", nil), map[string]string{"shortURLId": "AABBCC"})
+			response := httptest.NewRecorder()
+			suite.handler.RedirectToLongURL(response, request)
+
+			suite.Equal(http.StatusFound, response.Code)
+			if testCase.expiresAt == nil {
+				suite.Empty(response.Header().Values(handlers.TTLHeader))
+				suite.Empty(response.Header().Values(handlers.ExpiresAtHeader))
+
+				return
+			}
+
+			ttl, err := strconv.ParseInt(response.Header().Get(handlers.TTLHeader), 10, 64)
+			suite.Require().NoError(err)
+			// A second may go by between setting up the test case and handling the request
+			suite.InDelta(testCase.expectedTTL, ttl, 1)
+			suite.Equal(testCase.expectedExpiresAt, response.Header().Get(handlers.ExpiresAtHeader))
+		})
+	}
+}
+
+func (suite *HandlerSuite) TestPreviewShortURLExpiryHeaders() {
+	expiresAt := time.Now().Add(time.Hour).Truncate(time.Second)
+	suite.mockShortURLManager.EXPECT().GetShortURL(gomock.Any(), "AABBCC").Return(&shorturl.ShortURL{
+		Id:        "AABBCC",
+		LongURL:   "https://example.com",
+		ExpiresAt: &expiresAt,
+		CreatedAt: time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC),
+	}, nil)
+
+	request := withURLParams(httptest.NewRequest(http.MethodGet, "/public/v1/short-urls/AABBCC/preview", nil),
+		map[string]string{"shortURLId": "AABBCC"})
+	response := httptest.NewRecorder()
+	suite.handler.PreviewShortURL(response, request)
+
+	suite.Equal(http.StatusOK, response.Code)
+	ttl, err := strconv.ParseInt(response.Header().Get(handlers.TTLHeader), 10, 64)
+	suite.Require().NoError(err)
+	suite.InDelta(3600, ttl, 1)
+	suite.Equal(expiresAt.UTC().Format(time.RFC3339), response.Header().Get(handlers.ExpiresAtHeader))
+}
+
+func (suite *HandlerSuite) TestDeleteShortURLForwardsActor() {
+	suite.mockShortURLManager.EXPECT().DeleteShortURL(gomock.Any(), "AABBCC").
+		DoAndReturn(func(ctx context.Context, _ string) error {
+			suite.Equal("jane@example.com", shorturl.ActorFromContext(ctx))
+
+			return nil
+		})
+
+	request := withURLParams(httptest.NewRequest(http.MethodDelete, "/private/v1/short-urls/AABBCC", nil),
+		map[string]string{"shortURLId": "AABBCC"})
+	request.Header.Set(handlers.ActorHeader, "jane@example.com")
+	response := httptest.NewRecorder()
+	suite.handler.DeleteShortURL(response, request)
+
+	suite.Equal(http.StatusOK, response.Code)
+}
+
+func (suite *HandlerSuite) TestGetShortURL() {
+	createdAt := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	past := time.Now().Add(-time.Hour)
+
+	testCases := []struct {
+		name           string
+		shortURL       *shorturl.ShortURL
+		err            error
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "found",
+			shortURL: &shorturl.ShortURL{Id: "AABBCC", LongURL: "https://example.com", Tags: []string{"promo"},
+				MaxClicks: 10, ClickCount: 3, PasswordHash: "hash", CreatedBy: "user-1", Status: shorturl.StatusActive,
+				CreatedAt: createdAt, UpdatedAt: createdAt},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"id":"AABBCC","short_url":"http://localhost:8080/public/v1/short-urls/AABBCC",` +
+				`"long_url":"https://example.com","tags":["promo"],"status":"active","max_clicks":10,"click_count":3,` +
+				`"protected":true,"forward_query_params":false,"show_interstitial":false,"created_by":"user-1",` +
+				`"created_at":"2025-06-01T12:00:00Z","updated_at":"2025-06-01T12:00:00Z"}`,
+		},
+		{
+			name: "paused",
+			shortURL: &shorturl.ShortURL{Id: "AABBCC", LongURL: "https://example.com", Status: shorturl.StatusPaused,
+				CreatedAt: createdAt, UpdatedAt: createdAt},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"id":"AABBCC","short_url":"http://localhost:8080/public/v1/short-urls/AABBCC",` +
+				`"long_url":"https://example.com","tags":[],"status":"paused","max_clicks":0,"click_count":0,` +
+				`"protected":false,"forward_query_params":false,"show_interstitial":false,` +
+				`"created_at":"2025-06-01T12:00:00Z","updated_at":"2025-06-01T12:00:00Z"}`,
+		},
+		{
+			name:           "not found",
+			err:            shorturl.ErrShortURLNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "expired",
+			shortURL:       &shorturl.ShortURL{Id: "AABBCC", Status: shorturl.StatusActive, ExpiresAt: &past},
+			expectedStatus: http.StatusGone,
+		},
+		{
+			name:           "archived",
+			shortURL:       &shorturl.ShortURL{Id: "AABBCC", Status: shorturl.StatusArchived},
+			expectedStatus: http.StatusGone,
+		},
+		{
+			name:           "click limit exceeded",
+			shortURL:       &shorturl.ShortURL{Id: "AABBCC", Status: shorturl.StatusActive, MaxClicks: 1, ClickCount: 1},
+			expectedStatus: http.StatusGone,
+		},
+		{
+			name:           "storage error",
+			err:            errors.New("storage error"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			suite.mockShortURLManager.EXPECT().GetShortURL(gomock.Any(), "AABBCC").Return(tc.shortURL, tc.err)
+
+			request := withURLParams(httptest.NewRequest(http.MethodGet, "/private/v1/short-urls/AABBCC", nil),
+				map[string]string{"shortURLId": "AABBCC"})
+			response := httptest.NewRecorder()
+			suite.handler.GetShortURL(response, request)
+
+			suite.Equal(tc.expectedStatus, response.Code)
+			if tc.expectedBody != "" {
+				suite.Equal(tc.expectedBody, response.Body.String())
+			}
+		})
+	}
+}
+
+func (suite *HandlerSuite) TestGetShortURLAuditLogSuccess() {
+	createdAt := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	suite.mockShortURLManager.EXPECT().GetAuditLog(gomock.Any(), &shorturl.AuditFilter{ShortURLId: "AABBCC", Limit: 10, Offset: 0}).
+		Return([]*shorturl.AuditEntry{
+			{
+				Id:         1,
+				Operation:  shorturl.OperationCreate,
+				ShortURLId: "AABBCC",
+				Actor:      "jane@example.com",
+				Payload:    []byte(`{"long_url":"https://example.com","tags":[]}`),
+				CreatedAt:  createdAt,
+			},
+			{
+				Id:         2,
+				Operation:  shorturl.OperationDelete,
+				ShortURLId: "AABBCC",
+				CreatedAt:  createdAt,
+			},
+		}, nil)
+
+	request := withURLParams(httptest.NewRequest(http.MethodGet, "/private/v1/short-urls/AABBCC/audit?limit=10", nil),
+		map[string]string{"shortURLId": "AABBCC"})
+	response := httptest.NewRecorder()
+	suite.handler.GetShortURLAuditLog(response, request)
+
+	suite.Equal(http.StatusOK, response.Code)
+	suite.JSONEq(`{"entries": [
+		{
+			"id": 1,
+			"operation": "create",
+			"short_url_id": "AABBCC",
+			"actor": "jane@example.com",
+			"payload": {"long_url": "https://example.com", "tags": []},
+			"created_at": "2025-06-01T12:00:00Z"
+		},
+		{
+			"id": 2,
+			"operation": "delete",
+			"short_url_id": "AABBCC",
+			"created_at": "2025-06-01T12:00:00Z"
+		}
+	]}`, response.Body.String())
+}
+
+func (suite *HandlerSuite) TestGetShortURLAuditLogFailInvalidOffset() {
+	request := withURLParams(httptest.NewRequest(http.MethodGet, "/private/v1/short-urls/AABBCC/audit?offset=-1", nil),
+		map[string]string{"shortURLId": "AABBCC"})
+	response := httptest.NewRecorder()
+	suite.handler.GetShortURLAuditLog(response, request)
+
+	suite.Equal(http.StatusBadRequest, response.Code)
+}
+
+func (suite *HandlerSuite) TestListUserShortURLsSuccess() {
+	createdAt := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	suite.mockShortURLManager.EXPECT().ListShortURLsByOwner(gomock.Any(), "user-1", 10, 5).
+		Return([]*shorturl.ShortURL{
+			{Id: "AABBCC", LongURL: "https://example.com", CreatedBy: "user-1", CreatedAt: createdAt, UpdatedAt: createdAt},
+		}, nil)
+
+	request := withURLParams(httptest.NewRequest(http.MethodGet, "/private/v1/users/user-1/short-urls?limit=10&offset=5", nil),
+		map[string]string{"userId": "user-1"})
+	response := httptest.NewRecorder()
+	suite.handler.ListUserShortURLs(response, request)
+
+	suite.Equal(http.StatusOK, response.Code)
+	suite.JSONEq(`{"short_urls": [{
+		"id": "AABBCC",
+		"short_url": "http://localhost:8080/public/v1/short-urls/AABBCC",
+		"long_url": "https://example.com",
+		"tags": [],
+		"created_by": "user-1",
+		"created_at": "2025-06-01T12:00:00Z",
+		"updated_at": "2025-06-01T12:00:00Z"
+	}]}`, response.Body.String())
+}
+
+func (suite *HandlerSuite) TestListUserShortURLsForbidden() {
+	suite.mockShortURLManager.EXPECT().ListShortURLsByOwner(gomock.Any(), "user-1", 100, 0).
+		Return(nil, shorturl.ErrNotOwner)
+
+	request := withURLParams(httptest.NewRequest(http.MethodGet, "/private/v1/users/user-1/short-urls", nil),
+		map[string]string{"userId": "user-1"})
+	response := httptest.NewRecorder()
+	suite.handler.ListUserShortURLs(response, request)
+
+	suite.Equal(http.StatusForbidden, response.Code)
+}
+
+func (suite *HandlerSuite) TestDeleteUserShortURLsSuccess() {
+	suite.mockShortURLManager.EXPECT().DeleteShortURLsByOwner(gomock.Any(), "user-1").
+		DoAndReturn(func(ctx context.Context, _ string) error {
+			suite.Equal("jane@example.com", shorturl.ActorFromContext(ctx))
+
+			return nil
+		})
+
+	request := withURLParams(httptest.NewRequest(http.MethodDelete, "/private/v1/users/user-1/short-urls", nil),
+		map[string]string{"userId": "user-1"})
+	request.Header.Set(handlers.ActorHeader, "jane@example.com")
+	response := httptest.NewRecorder()
+	suite.handler.DeleteUserShortURLs(response, request)
+
+	suite.Equal(http.StatusNoContent, response.Code)
+}
+
+func (suite *HandlerSuite) TestDeleteUserShortURLsForbidden() {
+	suite.mockShortURLManager.EXPECT().DeleteShortURLsByOwner(gomock.Any(), "user-1").Return(shorturl.ErrNotOwner)
+
+	request := withURLParams(httptest.NewRequest(http.MethodDelete, "/private/v1/users/user-1/short-urls", nil),
+		map[string]string{"userId": "user-1"})
+	response := httptest.NewRecorder()
+	suite.handler.DeleteUserShortURLs(response, request)
+
+	suite.Equal(http.StatusForbidden, response.Code)
+}
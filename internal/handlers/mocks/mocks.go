@@ -0,0 +1,1800 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./handler.go
+//
+// Generated by this command:
+//
+//	mockgen -typed -package=mocks -source=./handler.go -destination=./mocks/mocks.go
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	metrics "github.com/AvalosM/short-url-service/pkg/metrics"
+	shorturl "github.com/AvalosM/short-url-service/pkg/shorturl"
+	webhook "github.com/AvalosM/short-url-service/pkg/webhook"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockShortURLManager is a mock of ShortURLManager interface.
+type MockShortURLManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockShortURLManagerMockRecorder
+	isgomock struct{}
+}
+
+// MockShortURLManagerMockRecorder is the mock recorder for MockShortURLManager.
+type MockShortURLManagerMockRecorder struct {
+	mock *MockShortURLManager
+}
+
+// NewMockShortURLManager creates a new mock instance.
+func NewMockShortURLManager(ctrl *gomock.Controller) *MockShortURLManager {
+	mock := &MockShortURLManager{ctrl: ctrl}
+	mock.recorder = &MockShortURLManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockShortURLManager) EXPECT() *MockShortURLManagerMockRecorder {
+	return m.recorder
+}
+
+// CheckPassword mocks base method.
+func (m *MockShortURLManager) CheckPassword(ctx context.Context, shortURLId, password string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckPassword", ctx, shortURLId, password)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CheckPassword indicates an expected call of CheckPassword.
+func (mr *MockShortURLManagerMockRecorder) CheckPassword(ctx, shortURLId, password any) *MockShortURLManagerCheckPasswordCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckPassword", reflect.TypeOf((*MockShortURLManager)(nil).CheckPassword), ctx, shortURLId, password)
+	return &MockShortURLManagerCheckPasswordCall{Call: call}
+}
+
+// MockShortURLManagerCheckPasswordCall wrap *gomock.Call
+type MockShortURLManagerCheckPasswordCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockShortURLManagerCheckPasswordCall) Return(arg0 error) *MockShortURLManagerCheckPasswordCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockShortURLManagerCheckPasswordCall) Do(f func(context.Context, string, string) error) *MockShortURLManagerCheckPasswordCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockShortURLManagerCheckPasswordCall) DoAndReturn(f func(context.Context, string, string) error) *MockShortURLManagerCheckPasswordCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// CheckShortURL mocks base method.
+func (m *MockShortURLManager) CheckShortURL(ctx context.Context, shortURLId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckShortURL", ctx, shortURLId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CheckShortURL indicates an expected call of CheckShortURL.
+func (mr *MockShortURLManagerMockRecorder) CheckShortURL(ctx, shortURLId any) *MockShortURLManagerCheckShortURLCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckShortURL", reflect.TypeOf((*MockShortURLManager)(nil).CheckShortURL), ctx, shortURLId)
+	return &MockShortURLManagerCheckShortURLCall{Call: call}
+}
+
+// MockShortURLManagerCheckShortURLCall wrap *gomock.Call
+type MockShortURLManagerCheckShortURLCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockShortURLManagerCheckShortURLCall) Return(arg0 error) *MockShortURLManagerCheckShortURLCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockShortURLManagerCheckShortURLCall) Do(f func(context.Context, string) error) *MockShortURLManagerCheckShortURLCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockShortURLManagerCheckShortURLCall) DoAndReturn(f func(context.Context, string) error) *MockShortURLManagerCheckShortURLCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// CreateAlias mocks base method.
+func (m *MockShortURLManager) CreateAlias(ctx context.Context, shortURLId, aliasId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAlias", ctx, shortURLId, aliasId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateAlias indicates an expected call of CreateAlias.
+func (mr *MockShortURLManagerMockRecorder) CreateAlias(ctx, shortURLId, aliasId any) *MockShortURLManagerCreateAliasCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAlias", reflect.TypeOf((*MockShortURLManager)(nil).CreateAlias), ctx, shortURLId, aliasId)
+	return &MockShortURLManagerCreateAliasCall{Call: call}
+}
+
+// MockShortURLManagerCreateAliasCall wrap *gomock.Call
+type MockShortURLManagerCreateAliasCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockShortURLManagerCreateAliasCall) Return(arg0 error) *MockShortURLManagerCreateAliasCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockShortURLManagerCreateAliasCall) Do(f func(context.Context, string, string) error) *MockShortURLManagerCreateAliasCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockShortURLManagerCreateAliasCall) DoAndReturn(f func(context.Context, string, string) error) *MockShortURLManagerCreateAliasCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// CreateShortURL mocks base method.
+func (m *MockShortURLManager) CreateShortURL(ctx context.Context, longURL string, options *shorturl.CreateOptions) (*shorturl.ShortURL, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateShortURL", ctx, longURL, options)
+	ret0, _ := ret[0].(*shorturl.ShortURL)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateShortURL indicates an expected call of CreateShortURL.
+func (mr *MockShortURLManagerMockRecorder) CreateShortURL(ctx, longURL, options any) *MockShortURLManagerCreateShortURLCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateShortURL", reflect.TypeOf((*MockShortURLManager)(nil).CreateShortURL), ctx, longURL, options)
+	return &MockShortURLManagerCreateShortURLCall{Call: call}
+}
+
+// MockShortURLManagerCreateShortURLCall wrap *gomock.Call
+type MockShortURLManagerCreateShortURLCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockShortURLManagerCreateShortURLCall) Return(arg0 *shorturl.ShortURL, arg1 error) *MockShortURLManagerCreateShortURLCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockShortURLManagerCreateShortURLCall) Do(f func(context.Context, string, *shorturl.CreateOptions) (*shorturl.ShortURL, error)) *MockShortURLManagerCreateShortURLCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockShortURLManagerCreateShortURLCall) DoAndReturn(f func(context.Context, string, *shorturl.CreateOptions) (*shorturl.ShortURL, error)) *MockShortURLManagerCreateShortURLCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// CreateShortURLBulk mocks base method.
+func (m *MockShortURLManager) CreateShortURLBulk(ctx context.Context, entries []*shorturl.BulkEntry) []*shorturl.BulkResult {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateShortURLBulk", ctx, entries)
+	ret0, _ := ret[0].([]*shorturl.BulkResult)
+	return ret0
+}
+
+// CreateShortURLBulk indicates an expected call of CreateShortURLBulk.
+func (mr *MockShortURLManagerMockRecorder) CreateShortURLBulk(ctx, entries any) *MockShortURLManagerCreateShortURLBulkCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateShortURLBulk", reflect.TypeOf((*MockShortURLManager)(nil).CreateShortURLBulk), ctx, entries)
+	return &MockShortURLManagerCreateShortURLBulkCall{Call: call}
+}
+
+// MockShortURLManagerCreateShortURLBulkCall wrap *gomock.Call
+type MockShortURLManagerCreateShortURLBulkCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockShortURLManagerCreateShortURLBulkCall) Return(arg0 []*shorturl.BulkResult) *MockShortURLManagerCreateShortURLBulkCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockShortURLManagerCreateShortURLBulkCall) Do(f func(context.Context, []*shorturl.BulkEntry) []*shorturl.BulkResult) *MockShortURLManagerCreateShortURLBulkCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockShortURLManagerCreateShortURLBulkCall) DoAndReturn(f func(context.Context, []*shorturl.BulkEntry) []*shorturl.BulkResult) *MockShortURLManagerCreateShortURLBulkCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// DeleteAlias mocks base method.
+func (m *MockShortURLManager) DeleteAlias(ctx context.Context, shortURLId, aliasId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAlias", ctx, shortURLId, aliasId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAlias indicates an expected call of DeleteAlias.
+func (mr *MockShortURLManagerMockRecorder) DeleteAlias(ctx, shortURLId, aliasId any) *MockShortURLManagerDeleteAliasCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAlias", reflect.TypeOf((*MockShortURLManager)(nil).DeleteAlias), ctx, shortURLId, aliasId)
+	return &MockShortURLManagerDeleteAliasCall{Call: call}
+}
+
+// MockShortURLManagerDeleteAliasCall wrap *gomock.Call
+type MockShortURLManagerDeleteAliasCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockShortURLManagerDeleteAliasCall) Return(arg0 error) *MockShortURLManagerDeleteAliasCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockShortURLManagerDeleteAliasCall) Do(f func(context.Context, string, string) error) *MockShortURLManagerDeleteAliasCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockShortURLManagerDeleteAliasCall) DoAndReturn(f func(context.Context, string, string) error) *MockShortURLManagerDeleteAliasCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// DeleteShortURL mocks base method.
+func (m *MockShortURLManager) DeleteShortURL(ctx context.Context, shortURLId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteShortURL", ctx, shortURLId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteShortURL indicates an expected call of DeleteShortURL.
+func (mr *MockShortURLManagerMockRecorder) DeleteShortURL(ctx, shortURLId any) *MockShortURLManagerDeleteShortURLCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteShortURL", reflect.TypeOf((*MockShortURLManager)(nil).DeleteShortURL), ctx, shortURLId)
+	return &MockShortURLManagerDeleteShortURLCall{Call: call}
+}
+
+// MockShortURLManagerDeleteShortURLCall wrap *gomock.Call
+type MockShortURLManagerDeleteShortURLCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockShortURLManagerDeleteShortURLCall) Return(arg0 error) *MockShortURLManagerDeleteShortURLCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockShortURLManagerDeleteShortURLCall) Do(f func(context.Context, string) error) *MockShortURLManagerDeleteShortURLCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockShortURLManagerDeleteShortURLCall) DoAndReturn(f func(context.Context, string) error) *MockShortURLManagerDeleteShortURLCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// DeleteShortURLsByOwner mocks base method.
+func (m *MockShortURLManager) DeleteShortURLsByOwner(ctx context.Context, userID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteShortURLsByOwner", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteShortURLsByOwner indicates an expected call of DeleteShortURLsByOwner.
+func (mr *MockShortURLManagerMockRecorder) DeleteShortURLsByOwner(ctx, userID any) *MockShortURLManagerDeleteShortURLsByOwnerCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteShortURLsByOwner", reflect.TypeOf((*MockShortURLManager)(nil).DeleteShortURLsByOwner), ctx, userID)
+	return &MockShortURLManagerDeleteShortURLsByOwnerCall{Call: call}
+}
+
+// MockShortURLManagerDeleteShortURLsByOwnerCall wrap *gomock.Call
+type MockShortURLManagerDeleteShortURLsByOwnerCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockShortURLManagerDeleteShortURLsByOwnerCall) Return(arg0 error) *MockShortURLManagerDeleteShortURLsByOwnerCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockShortURLManagerDeleteShortURLsByOwnerCall) Do(f func(context.Context, string) error) *MockShortURLManagerDeleteShortURLsByOwnerCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockShortURLManagerDeleteShortURLsByOwnerCall) DoAndReturn(f func(context.Context, string) error) *MockShortURLManagerDeleteShortURLsByOwnerCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// GetAuditLog mocks base method.
+func (m *MockShortURLManager) GetAuditLog(ctx context.Context, filter *shorturl.AuditFilter) ([]*shorturl.AuditEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuditLog", ctx, filter)
+	ret0, _ := ret[0].([]*shorturl.AuditEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuditLog indicates an expected call of GetAuditLog.
+func (mr *MockShortURLManagerMockRecorder) GetAuditLog(ctx, filter any) *MockShortURLManagerGetAuditLogCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuditLog", reflect.TypeOf((*MockShortURLManager)(nil).GetAuditLog), ctx, filter)
+	return &MockShortURLManagerGetAuditLogCall{Call: call}
+}
+
+// MockShortURLManagerGetAuditLogCall wrap *gomock.Call
+type MockShortURLManagerGetAuditLogCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockShortURLManagerGetAuditLogCall) Return(arg0 []*shorturl.AuditEntry, arg1 error) *MockShortURLManagerGetAuditLogCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockShortURLManagerGetAuditLogCall) Do(f func(context.Context, *shorturl.AuditFilter) ([]*shorturl.AuditEntry, error)) *MockShortURLManagerGetAuditLogCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockShortURLManagerGetAuditLogCall) DoAndReturn(f func(context.Context, *shorturl.AuditFilter) ([]*shorturl.AuditEntry, error)) *MockShortURLManagerGetAuditLogCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// GetDashboardStats mocks base method.
+func (m *MockShortURLManager) GetDashboardStats(ctx context.Context, from, to time.Time) (*shorturl.DashboardStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDashboardStats", ctx, from, to)
+	ret0, _ := ret[0].(*shorturl.DashboardStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDashboardStats indicates an expected call of GetDashboardStats.
+func (mr *MockShortURLManagerMockRecorder) GetDashboardStats(ctx, from, to any) *MockShortURLManagerGetDashboardStatsCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDashboardStats", reflect.TypeOf((*MockShortURLManager)(nil).GetDashboardStats), ctx, from, to)
+	return &MockShortURLManagerGetDashboardStatsCall{Call: call}
+}
+
+// MockShortURLManagerGetDashboardStatsCall wrap *gomock.Call
+type MockShortURLManagerGetDashboardStatsCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockShortURLManagerGetDashboardStatsCall) Return(arg0 *shorturl.DashboardStats, arg1 error) *MockShortURLManagerGetDashboardStatsCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockShortURLManagerGetDashboardStatsCall) Do(f func(context.Context, time.Time, time.Time) (*shorturl.DashboardStats, error)) *MockShortURLManagerGetDashboardStatsCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockShortURLManagerGetDashboardStatsCall) DoAndReturn(f func(context.Context, time.Time, time.Time) (*shorturl.DashboardStats, error)) *MockShortURLManagerGetDashboardStatsCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// GetLongURL mocks base method.
+func (m *MockShortURLManager) GetLongURL(ctx context.Context, shortURLId string) (*shorturl.ShortURLResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLongURL", ctx, shortURLId)
+	ret0, _ := ret[0].(*shorturl.ShortURLResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLongURL indicates an expected call of GetLongURL.
+func (mr *MockShortURLManagerMockRecorder) GetLongURL(ctx, shortURLId any) *MockShortURLManagerGetLongURLCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLongURL", reflect.TypeOf((*MockShortURLManager)(nil).GetLongURL), ctx, shortURLId)
+	return &MockShortURLManagerGetLongURLCall{Call: call}
+}
+
+// MockShortURLManagerGetLongURLCall wrap *gomock.Call
+type MockShortURLManagerGetLongURLCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockShortURLManagerGetLongURLCall) Return(arg0 *shorturl.ShortURLResult, arg1 error) *MockShortURLManagerGetLongURLCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockShortURLManagerGetLongURLCall) Do(f func(context.Context, string) (*shorturl.ShortURLResult, error)) *MockShortURLManagerGetLongURLCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockShortURLManagerGetLongURLCall) DoAndReturn(f func(context.Context, string) (*shorturl.ShortURLResult, error)) *MockShortURLManagerGetLongURLCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// GetLongURLBulk mocks base method.
+func (m *MockShortURLManager) GetLongURLBulk(ctx context.Context, ids []string) (map[string]string, []string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLongURLBulk", ctx, ids)
+	ret0, _ := ret[0].(map[string]string)
+	ret1, _ := ret[1].([]string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetLongURLBulk indicates an expected call of GetLongURLBulk.
+func (mr *MockShortURLManagerMockRecorder) GetLongURLBulk(ctx, ids any) *MockShortURLManagerGetLongURLBulkCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLongURLBulk", reflect.TypeOf((*MockShortURLManager)(nil).GetLongURLBulk), ctx, ids)
+	return &MockShortURLManagerGetLongURLBulkCall{Call: call}
+}
+
+// MockShortURLManagerGetLongURLBulkCall wrap *gomock.Call
+type MockShortURLManagerGetLongURLBulkCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockShortURLManagerGetLongURLBulkCall) Return(arg0 map[string]string, arg1 []string, arg2 error) *MockShortURLManagerGetLongURLBulkCall {
+	c.Call = c.Call.Return(arg0, arg1, arg2)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockShortURLManagerGetLongURLBulkCall) Do(f func(context.Context, []string) (map[string]string, []string, error)) *MockShortURLManagerGetLongURLBulkCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockShortURLManagerGetLongURLBulkCall) DoAndReturn(f func(context.Context, []string) (map[string]string, []string, error)) *MockShortURLManagerGetLongURLBulkCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// GetOrCreateShortURL mocks base method.
+func (m *MockShortURLManager) GetOrCreateShortURL(ctx context.Context, longURL string) (string, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrCreateShortURL", ctx, longURL)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetOrCreateShortURL indicates an expected call of GetOrCreateShortURL.
+func (mr *MockShortURLManagerMockRecorder) GetOrCreateShortURL(ctx, longURL any) *MockShortURLManagerGetOrCreateShortURLCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrCreateShortURL", reflect.TypeOf((*MockShortURLManager)(nil).GetOrCreateShortURL), ctx, longURL)
+	return &MockShortURLManagerGetOrCreateShortURLCall{Call: call}
+}
+
+// MockShortURLManagerGetOrCreateShortURLCall wrap *gomock.Call
+type MockShortURLManagerGetOrCreateShortURLCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockShortURLManagerGetOrCreateShortURLCall) Return(arg0 string, arg1 bool, arg2 error) *MockShortURLManagerGetOrCreateShortURLCall {
+	c.Call = c.Call.Return(arg0, arg1, arg2)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockShortURLManagerGetOrCreateShortURLCall) Do(f func(context.Context, string) (string, bool, error)) *MockShortURLManagerGetOrCreateShortURLCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockShortURLManagerGetOrCreateShortURLCall) DoAndReturn(f func(context.Context, string) (string, bool, error)) *MockShortURLManagerGetOrCreateShortURLCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// GetShortURL mocks base method.
+func (m *MockShortURLManager) GetShortURL(ctx context.Context, shortURLId string) (*shorturl.ShortURL, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetShortURL", ctx, shortURLId)
+	ret0, _ := ret[0].(*shorturl.ShortURL)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetShortURL indicates an expected call of GetShortURL.
+func (mr *MockShortURLManagerMockRecorder) GetShortURL(ctx, shortURLId any) *MockShortURLManagerGetShortURLCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetShortURL", reflect.TypeOf((*MockShortURLManager)(nil).GetShortURL), ctx, shortURLId)
+	return &MockShortURLManagerGetShortURLCall{Call: call}
+}
+
+// MockShortURLManagerGetShortURLCall wrap *gomock.Call
+type MockShortURLManagerGetShortURLCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockShortURLManagerGetShortURLCall) Return(arg0 *shorturl.ShortURL, arg1 error) *MockShortURLManagerGetShortURLCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockShortURLManagerGetShortURLCall) Do(f func(context.Context, string) (*shorturl.ShortURL, error)) *MockShortURLManagerGetShortURLCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockShortURLManagerGetShortURLCall) DoAndReturn(f func(context.Context, string) (*shorturl.ShortURL, error)) *MockShortURLManagerGetShortURLCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// ListShortURLs mocks base method.
+func (m *MockShortURLManager) ListShortURLs(ctx context.Context, filter *shorturl.ListFilter) ([]*shorturl.ShortURL, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListShortURLs", ctx, filter)
+	ret0, _ := ret[0].([]*shorturl.ShortURL)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListShortURLs indicates an expected call of ListShortURLs.
+func (mr *MockShortURLManagerMockRecorder) ListShortURLs(ctx, filter any) *MockShortURLManagerListShortURLsCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListShortURLs", reflect.TypeOf((*MockShortURLManager)(nil).ListShortURLs), ctx, filter)
+	return &MockShortURLManagerListShortURLsCall{Call: call}
+}
+
+// MockShortURLManagerListShortURLsCall wrap *gomock.Call
+type MockShortURLManagerListShortURLsCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockShortURLManagerListShortURLsCall) Return(arg0 []*shorturl.ShortURL, arg1 error) *MockShortURLManagerListShortURLsCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockShortURLManagerListShortURLsCall) Do(f func(context.Context, *shorturl.ListFilter) ([]*shorturl.ShortURL, error)) *MockShortURLManagerListShortURLsCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockShortURLManagerListShortURLsCall) DoAndReturn(f func(context.Context, *shorturl.ListFilter) ([]*shorturl.ShortURL, error)) *MockShortURLManagerListShortURLsCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// ListShortURLsByOwner mocks base method.
+func (m *MockShortURLManager) ListShortURLsByOwner(ctx context.Context, userID string, limit, offset int) ([]*shorturl.ShortURL, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListShortURLsByOwner", ctx, userID, limit, offset)
+	ret0, _ := ret[0].([]*shorturl.ShortURL)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListShortURLsByOwner indicates an expected call of ListShortURLsByOwner.
+func (mr *MockShortURLManagerMockRecorder) ListShortURLsByOwner(ctx, userID, limit, offset any) *MockShortURLManagerListShortURLsByOwnerCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListShortURLsByOwner", reflect.TypeOf((*MockShortURLManager)(nil).ListShortURLsByOwner), ctx, userID, limit, offset)
+	return &MockShortURLManagerListShortURLsByOwnerCall{Call: call}
+}
+
+// MockShortURLManagerListShortURLsByOwnerCall wrap *gomock.Call
+type MockShortURLManagerListShortURLsByOwnerCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockShortURLManagerListShortURLsByOwnerCall) Return(arg0 []*shorturl.ShortURL, arg1 error) *MockShortURLManagerListShortURLsByOwnerCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockShortURLManagerListShortURLsByOwnerCall) Do(f func(context.Context, string, int, int) ([]*shorturl.ShortURL, error)) *MockShortURLManagerListShortURLsByOwnerCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockShortURLManagerListShortURLsByOwnerCall) DoAndReturn(f func(context.Context, string, int, int) ([]*shorturl.ShortURL, error)) *MockShortURLManagerListShortURLsByOwnerCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// StreamShortURLs mocks base method.
+func (m *MockShortURLManager) StreamShortURLs(ctx context.Context, filter *shorturl.ListFilter) (<-chan *shorturl.ShortURL, <-chan error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamShortURLs", ctx, filter)
+	ret0, _ := ret[0].(<-chan *shorturl.ShortURL)
+	ret1, _ := ret[1].(<-chan error)
+	return ret0, ret1
+}
+
+// StreamShortURLs indicates an expected call of StreamShortURLs.
+func (mr *MockShortURLManagerMockRecorder) StreamShortURLs(ctx, filter any) *MockShortURLManagerStreamShortURLsCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamShortURLs", reflect.TypeOf((*MockShortURLManager)(nil).StreamShortURLs), ctx, filter)
+	return &MockShortURLManagerStreamShortURLsCall{Call: call}
+}
+
+// MockShortURLManagerStreamShortURLsCall wrap *gomock.Call
+type MockShortURLManagerStreamShortURLsCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockShortURLManagerStreamShortURLsCall) Return(arg0 <-chan *shorturl.ShortURL, arg1 <-chan error) *MockShortURLManagerStreamShortURLsCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockShortURLManagerStreamShortURLsCall) Do(f func(context.Context, *shorturl.ListFilter) (<-chan *shorturl.ShortURL, <-chan error)) *MockShortURLManagerStreamShortURLsCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockShortURLManagerStreamShortURLsCall) DoAndReturn(f func(context.Context, *shorturl.ListFilter) (<-chan *shorturl.ShortURL, <-chan error)) *MockShortURLManagerStreamShortURLsCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// UnlockLongURL mocks base method.
+func (m *MockShortURLManager) UnlockLongURL(ctx context.Context, shortURLId string) (*shorturl.ShortURLResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnlockLongURL", ctx, shortURLId)
+	ret0, _ := ret[0].(*shorturl.ShortURLResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnlockLongURL indicates an expected call of UnlockLongURL.
+func (mr *MockShortURLManagerMockRecorder) UnlockLongURL(ctx, shortURLId any) *MockShortURLManagerUnlockLongURLCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnlockLongURL", reflect.TypeOf((*MockShortURLManager)(nil).UnlockLongURL), ctx, shortURLId)
+	return &MockShortURLManagerUnlockLongURLCall{Call: call}
+}
+
+// MockShortURLManagerUnlockLongURLCall wrap *gomock.Call
+type MockShortURLManagerUnlockLongURLCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockShortURLManagerUnlockLongURLCall) Return(arg0 *shorturl.ShortURLResult, arg1 error) *MockShortURLManagerUnlockLongURLCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockShortURLManagerUnlockLongURLCall) Do(f func(context.Context, string) (*shorturl.ShortURLResult, error)) *MockShortURLManagerUnlockLongURLCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockShortURLManagerUnlockLongURLCall) DoAndReturn(f func(context.Context, string) (*shorturl.ShortURLResult, error)) *MockShortURLManagerUnlockLongURLCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// UpdateShortURLStatus mocks base method.
+func (m *MockShortURLManager) UpdateShortURLStatus(ctx context.Context, shortURLId string, status shorturl.Status) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateShortURLStatus", ctx, shortURLId, status)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateShortURLStatus indicates an expected call of UpdateShortURLStatus.
+func (mr *MockShortURLManagerMockRecorder) UpdateShortURLStatus(ctx, shortURLId, status any) *MockShortURLManagerUpdateShortURLStatusCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateShortURLStatus", reflect.TypeOf((*MockShortURLManager)(nil).UpdateShortURLStatus), ctx, shortURLId, status)
+	return &MockShortURLManagerUpdateShortURLStatusCall{Call: call}
+}
+
+// MockShortURLManagerUpdateShortURLStatusCall wrap *gomock.Call
+type MockShortURLManagerUpdateShortURLStatusCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockShortURLManagerUpdateShortURLStatusCall) Return(arg0 error) *MockShortURLManagerUpdateShortURLStatusCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockShortURLManagerUpdateShortURLStatusCall) Do(f func(context.Context, string, shorturl.Status) error) *MockShortURLManagerUpdateShortURLStatusCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockShortURLManagerUpdateShortURLStatusCall) DoAndReturn(f func(context.Context, string, shorturl.Status) error) *MockShortURLManagerUpdateShortURLStatusCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// MockMetricsManager is a mock of MetricsManager interface.
+type MockMetricsManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockMetricsManagerMockRecorder
+	isgomock struct{}
+}
+
+// MockMetricsManagerMockRecorder is the mock recorder for MockMetricsManager.
+type MockMetricsManagerMockRecorder struct {
+	mock *MockMetricsManager
+}
+
+// NewMockMetricsManager creates a new mock instance.
+func NewMockMetricsManager(ctrl *gomock.Controller) *MockMetricsManager {
+	mock := &MockMetricsManager{ctrl: ctrl}
+	mock.recorder = &MockMetricsManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMetricsManager) EXPECT() *MockMetricsManagerMockRecorder {
+	return m.recorder
+}
+
+// Drain mocks base method.
+func (m *MockMetricsManager) Drain(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Drain", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Drain indicates an expected call of Drain.
+func (mr *MockMetricsManagerMockRecorder) Drain(ctx any) *MockMetricsManagerDrainCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Drain", reflect.TypeOf((*MockMetricsManager)(nil).Drain), ctx)
+	return &MockMetricsManagerDrainCall{Call: call}
+}
+
+// MockMetricsManagerDrainCall wrap *gomock.Call
+type MockMetricsManagerDrainCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockMetricsManagerDrainCall) Return(arg0 error) *MockMetricsManagerDrainCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockMetricsManagerDrainCall) Do(f func(context.Context) error) *MockMetricsManagerDrainCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockMetricsManagerDrainCall) DoAndReturn(f func(context.Context) error) *MockMetricsManagerDrainCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// GetShortURLLatency mocks base method.
+func (m *MockMetricsManager) GetShortURLLatency(ctx context.Context, id string, from, to time.Time) ([]*metrics.Latency, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetShortURLLatency", ctx, id, from, to)
+	ret0, _ := ret[0].([]*metrics.Latency)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetShortURLLatency indicates an expected call of GetShortURLLatency.
+func (mr *MockMetricsManagerMockRecorder) GetShortURLLatency(ctx, id, from, to any) *MockMetricsManagerGetShortURLLatencyCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetShortURLLatency", reflect.TypeOf((*MockMetricsManager)(nil).GetShortURLLatency), ctx, id, from, to)
+	return &MockMetricsManagerGetShortURLLatencyCall{Call: call}
+}
+
+// MockMetricsManagerGetShortURLLatencyCall wrap *gomock.Call
+type MockMetricsManagerGetShortURLLatencyCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockMetricsManagerGetShortURLLatencyCall) Return(arg0 []*metrics.Latency, arg1 error) *MockMetricsManagerGetShortURLLatencyCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockMetricsManagerGetShortURLLatencyCall) Do(f func(context.Context, string, time.Time, time.Time) ([]*metrics.Latency, error)) *MockMetricsManagerGetShortURLLatencyCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockMetricsManagerGetShortURLLatencyCall) DoAndReturn(f func(context.Context, string, time.Time, time.Time) ([]*metrics.Latency, error)) *MockMetricsManagerGetShortURLLatencyCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// GetShortURLMetrics mocks base method.
+func (m *MockMetricsManager) GetShortURLMetrics(ctx context.Context, id string, from, to time.Time) (*metrics.Metrics, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetShortURLMetrics", ctx, id, from, to)
+	ret0, _ := ret[0].(*metrics.Metrics)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetShortURLMetrics indicates an expected call of GetShortURLMetrics.
+func (mr *MockMetricsManagerMockRecorder) GetShortURLMetrics(ctx, id, from, to any) *MockMetricsManagerGetShortURLMetricsCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetShortURLMetrics", reflect.TypeOf((*MockMetricsManager)(nil).GetShortURLMetrics), ctx, id, from, to)
+	return &MockMetricsManagerGetShortURLMetricsCall{Call: call}
+}
+
+// MockMetricsManagerGetShortURLMetricsCall wrap *gomock.Call
+type MockMetricsManagerGetShortURLMetricsCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockMetricsManagerGetShortURLMetricsCall) Return(arg0 *metrics.Metrics, arg1 error) *MockMetricsManagerGetShortURLMetricsCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockMetricsManagerGetShortURLMetricsCall) Do(f func(context.Context, string, time.Time, time.Time) (*metrics.Metrics, error)) *MockMetricsManagerGetShortURLMetricsCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockMetricsManagerGetShortURLMetricsCall) DoAndReturn(f func(context.Context, string, time.Time, time.Time) (*metrics.Metrics, error)) *MockMetricsManagerGetShortURLMetricsCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// GetShortURLMetricsBulk mocks base method.
+func (m *MockMetricsManager) GetShortURLMetricsBulk(ctx context.Context, ids []string, from, to time.Time) (map[string]*metrics.Metrics, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetShortURLMetricsBulk", ctx, ids, from, to)
+	ret0, _ := ret[0].(map[string]*metrics.Metrics)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetShortURLMetricsBulk indicates an expected call of GetShortURLMetricsBulk.
+func (mr *MockMetricsManagerMockRecorder) GetShortURLMetricsBulk(ctx, ids, from, to any) *MockMetricsManagerGetShortURLMetricsBulkCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetShortURLMetricsBulk", reflect.TypeOf((*MockMetricsManager)(nil).GetShortURLMetricsBulk), ctx, ids, from, to)
+	return &MockMetricsManagerGetShortURLMetricsBulkCall{Call: call}
+}
+
+// MockMetricsManagerGetShortURLMetricsBulkCall wrap *gomock.Call
+type MockMetricsManagerGetShortURLMetricsBulkCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockMetricsManagerGetShortURLMetricsBulkCall) Return(arg0 map[string]*metrics.Metrics, arg1 error) *MockMetricsManagerGetShortURLMetricsBulkCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockMetricsManagerGetShortURLMetricsBulkCall) Do(f func(context.Context, []string, time.Time, time.Time) (map[string]*metrics.Metrics, error)) *MockMetricsManagerGetShortURLMetricsBulkCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockMetricsManagerGetShortURLMetricsBulkCall) DoAndReturn(f func(context.Context, []string, time.Time, time.Time) (map[string]*metrics.Metrics, error)) *MockMetricsManagerGetShortURLMetricsBulkCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// RecordShortURLRequestAsync mocks base method.
+func (m *MockMetricsManager) RecordShortURLRequestAsync(tenantID, id, ip, userAgent string, latency time.Duration) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RecordShortURLRequestAsync", tenantID, id, ip, userAgent, latency)
+}
+
+// RecordShortURLRequestAsync indicates an expected call of RecordShortURLRequestAsync.
+func (mr *MockMetricsManagerMockRecorder) RecordShortURLRequestAsync(tenantID, id, ip, userAgent, latency any) *MockMetricsManagerRecordShortURLRequestAsyncCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordShortURLRequestAsync", reflect.TypeOf((*MockMetricsManager)(nil).RecordShortURLRequestAsync), tenantID, id, ip, userAgent, latency)
+	return &MockMetricsManagerRecordShortURLRequestAsyncCall{Call: call}
+}
+
+// MockMetricsManagerRecordShortURLRequestAsyncCall wrap *gomock.Call
+type MockMetricsManagerRecordShortURLRequestAsyncCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockMetricsManagerRecordShortURLRequestAsyncCall) Return() *MockMetricsManagerRecordShortURLRequestAsyncCall {
+	c.Call = c.Call.Return()
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockMetricsManagerRecordShortURLRequestAsyncCall) Do(f func(string, string, string, string, time.Duration)) *MockMetricsManagerRecordShortURLRequestAsyncCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockMetricsManagerRecordShortURLRequestAsyncCall) DoAndReturn(f func(string, string, string, string, time.Duration)) *MockMetricsManagerRecordShortURLRequestAsyncCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// Snapshot mocks base method.
+func (m *MockMetricsManager) Snapshot(ctx context.Context) (map[string]metrics.CollectorSnapshot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Snapshot", ctx)
+	ret0, _ := ret[0].(map[string]metrics.CollectorSnapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Snapshot indicates an expected call of Snapshot.
+func (mr *MockMetricsManagerMockRecorder) Snapshot(ctx any) *MockMetricsManagerSnapshotCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Snapshot", reflect.TypeOf((*MockMetricsManager)(nil).Snapshot), ctx)
+	return &MockMetricsManagerSnapshotCall{Call: call}
+}
+
+// MockMetricsManagerSnapshotCall wrap *gomock.Call
+type MockMetricsManagerSnapshotCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockMetricsManagerSnapshotCall) Return(arg0 map[string]metrics.CollectorSnapshot, arg1 error) *MockMetricsManagerSnapshotCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockMetricsManagerSnapshotCall) Do(f func(context.Context) (map[string]metrics.CollectorSnapshot, error)) *MockMetricsManagerSnapshotCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockMetricsManagerSnapshotCall) DoAndReturn(f func(context.Context) (map[string]metrics.CollectorSnapshot, error)) *MockMetricsManagerSnapshotCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// StreamShortURLMetrics mocks base method.
+func (m *MockMetricsManager) StreamShortURLMetrics(ctx context.Context, id string, from, to time.Time, fn func(*metrics.Interval) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamShortURLMetrics", ctx, id, from, to, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StreamShortURLMetrics indicates an expected call of StreamShortURLMetrics.
+func (mr *MockMetricsManagerMockRecorder) StreamShortURLMetrics(ctx, id, from, to, fn any) *MockMetricsManagerStreamShortURLMetricsCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamShortURLMetrics", reflect.TypeOf((*MockMetricsManager)(nil).StreamShortURLMetrics), ctx, id, from, to, fn)
+	return &MockMetricsManagerStreamShortURLMetricsCall{Call: call}
+}
+
+// MockMetricsManagerStreamShortURLMetricsCall wrap *gomock.Call
+type MockMetricsManagerStreamShortURLMetricsCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockMetricsManagerStreamShortURLMetricsCall) Return(arg0 error) *MockMetricsManagerStreamShortURLMetricsCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockMetricsManagerStreamShortURLMetricsCall) Do(f func(context.Context, string, time.Time, time.Time, func(*metrics.Interval) error) error) *MockMetricsManagerStreamShortURLMetricsCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockMetricsManagerStreamShortURLMetricsCall) DoAndReturn(f func(context.Context, string, time.Time, time.Time, func(*metrics.Interval) error) error) *MockMetricsManagerStreamShortURLMetricsCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// Subscribe mocks base method.
+func (m *MockMetricsManager) Subscribe(ctx context.Context, id string) (<-chan metrics.Event, func()) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Subscribe", ctx, id)
+	ret0, _ := ret[0].(<-chan metrics.Event)
+	ret1, _ := ret[1].(func())
+	return ret0, ret1
+}
+
+// Subscribe indicates an expected call of Subscribe.
+func (mr *MockMetricsManagerMockRecorder) Subscribe(ctx, id any) *MockMetricsManagerSubscribeCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockMetricsManager)(nil).Subscribe), ctx, id)
+	return &MockMetricsManagerSubscribeCall{Call: call}
+}
+
+// MockMetricsManagerSubscribeCall wrap *gomock.Call
+type MockMetricsManagerSubscribeCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockMetricsManagerSubscribeCall) Return(arg0 <-chan metrics.Event, arg1 func()) *MockMetricsManagerSubscribeCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockMetricsManagerSubscribeCall) Do(f func(context.Context, string) (<-chan metrics.Event, func())) *MockMetricsManagerSubscribeCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockMetricsManagerSubscribeCall) DoAndReturn(f func(context.Context, string) (<-chan metrics.Event, func())) *MockMetricsManagerSubscribeCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// MockWebhookManager is a mock of WebhookManager interface.
+type MockWebhookManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhookManagerMockRecorder
+	isgomock struct{}
+}
+
+// MockWebhookManagerMockRecorder is the mock recorder for MockWebhookManager.
+type MockWebhookManagerMockRecorder struct {
+	mock *MockWebhookManager
+}
+
+// NewMockWebhookManager creates a new mock instance.
+func NewMockWebhookManager(ctrl *gomock.Controller) *MockWebhookManager {
+	mock := &MockWebhookManager{ctrl: ctrl}
+	mock.recorder = &MockWebhookManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebhookManager) EXPECT() *MockWebhookManagerMockRecorder {
+	return m.recorder
+}
+
+// DeleteWebhook mocks base method.
+func (m *MockWebhookManager) DeleteWebhook(ctx context.Context, shortURLId string, webhookId int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhook", ctx, shortURLId, webhookId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhook indicates an expected call of DeleteWebhook.
+func (mr *MockWebhookManagerMockRecorder) DeleteWebhook(ctx, shortURLId, webhookId any) *MockWebhookManagerDeleteWebhookCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhook", reflect.TypeOf((*MockWebhookManager)(nil).DeleteWebhook), ctx, shortURLId, webhookId)
+	return &MockWebhookManagerDeleteWebhookCall{Call: call}
+}
+
+// MockWebhookManagerDeleteWebhookCall wrap *gomock.Call
+type MockWebhookManagerDeleteWebhookCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockWebhookManagerDeleteWebhookCall) Return(arg0 error) *MockWebhookManagerDeleteWebhookCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockWebhookManagerDeleteWebhookCall) Do(f func(context.Context, string, int64) error) *MockWebhookManagerDeleteWebhookCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockWebhookManagerDeleteWebhookCall) DoAndReturn(f func(context.Context, string, int64) error) *MockWebhookManagerDeleteWebhookCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// NotifyClickAsync mocks base method.
+func (m *MockWebhookManager) NotifyClickAsync(tenantID, shortURLId string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "NotifyClickAsync", tenantID, shortURLId)
+}
+
+// NotifyClickAsync indicates an expected call of NotifyClickAsync.
+func (mr *MockWebhookManagerMockRecorder) NotifyClickAsync(tenantID, shortURLId any) *MockWebhookManagerNotifyClickAsyncCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NotifyClickAsync", reflect.TypeOf((*MockWebhookManager)(nil).NotifyClickAsync), tenantID, shortURLId)
+	return &MockWebhookManagerNotifyClickAsyncCall{Call: call}
+}
+
+// MockWebhookManagerNotifyClickAsyncCall wrap *gomock.Call
+type MockWebhookManagerNotifyClickAsyncCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockWebhookManagerNotifyClickAsyncCall) Return() *MockWebhookManagerNotifyClickAsyncCall {
+	c.Call = c.Call.Return()
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockWebhookManagerNotifyClickAsyncCall) Do(f func(string, string)) *MockWebhookManagerNotifyClickAsyncCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockWebhookManagerNotifyClickAsyncCall) DoAndReturn(f func(string, string)) *MockWebhookManagerNotifyClickAsyncCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// RegisterWebhook mocks base method.
+func (m *MockWebhookManager) RegisterWebhook(ctx context.Context, shortURLId, url, secret string) (*webhook.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterWebhook", ctx, shortURLId, url, secret)
+	ret0, _ := ret[0].(*webhook.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RegisterWebhook indicates an expected call of RegisterWebhook.
+func (mr *MockWebhookManagerMockRecorder) RegisterWebhook(ctx, shortURLId, url, secret any) *MockWebhookManagerRegisterWebhookCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterWebhook", reflect.TypeOf((*MockWebhookManager)(nil).RegisterWebhook), ctx, shortURLId, url, secret)
+	return &MockWebhookManagerRegisterWebhookCall{Call: call}
+}
+
+// MockWebhookManagerRegisterWebhookCall wrap *gomock.Call
+type MockWebhookManagerRegisterWebhookCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockWebhookManagerRegisterWebhookCall) Return(arg0 *webhook.Webhook, arg1 error) *MockWebhookManagerRegisterWebhookCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockWebhookManagerRegisterWebhookCall) Do(f func(context.Context, string, string, string) (*webhook.Webhook, error)) *MockWebhookManagerRegisterWebhookCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockWebhookManagerRegisterWebhookCall) DoAndReturn(f func(context.Context, string, string, string) (*webhook.Webhook, error)) *MockWebhookManagerRegisterWebhookCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// MockTokenSigner is a mock of TokenSigner interface.
+type MockTokenSigner struct {
+	ctrl     *gomock.Controller
+	recorder *MockTokenSignerMockRecorder
+	isgomock struct{}
+}
+
+// MockTokenSignerMockRecorder is the mock recorder for MockTokenSigner.
+type MockTokenSignerMockRecorder struct {
+	mock *MockTokenSigner
+}
+
+// NewMockTokenSigner creates a new mock instance.
+func NewMockTokenSigner(ctrl *gomock.Controller) *MockTokenSigner {
+	mock := &MockTokenSigner{ctrl: ctrl}
+	mock.recorder = &MockTokenSignerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTokenSigner) EXPECT() *MockTokenSignerMockRecorder {
+	return m.recorder
+}
+
+// Generate mocks base method.
+func (m *MockTokenSigner) Generate(subject string) (string, time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Generate", subject)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(time.Time)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Generate indicates an expected call of Generate.
+func (mr *MockTokenSignerMockRecorder) Generate(subject any) *MockTokenSignerGenerateCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Generate", reflect.TypeOf((*MockTokenSigner)(nil).Generate), subject)
+	return &MockTokenSignerGenerateCall{Call: call}
+}
+
+// MockTokenSignerGenerateCall wrap *gomock.Call
+type MockTokenSignerGenerateCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockTokenSignerGenerateCall) Return(arg0 string, arg1 time.Time, arg2 error) *MockTokenSignerGenerateCall {
+	c.Call = c.Call.Return(arg0, arg1, arg2)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockTokenSignerGenerateCall) Do(f func(string) (string, time.Time, error)) *MockTokenSignerGenerateCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockTokenSignerGenerateCall) DoAndReturn(f func(string) (string, time.Time, error)) *MockTokenSignerGenerateCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// Validate mocks base method.
+func (m *MockTokenSigner) Validate(token, subject string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Validate", token, subject)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Validate indicates an expected call of Validate.
+func (mr *MockTokenSignerMockRecorder) Validate(token, subject any) *MockTokenSignerValidateCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Validate", reflect.TypeOf((*MockTokenSigner)(nil).Validate), token, subject)
+	return &MockTokenSignerValidateCall{Call: call}
+}
+
+// MockTokenSignerValidateCall wrap *gomock.Call
+type MockTokenSignerValidateCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockTokenSignerValidateCall) Return(arg0 error) *MockTokenSignerValidateCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockTokenSignerValidateCall) Do(f func(string, string) error) *MockTokenSignerValidateCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockTokenSignerValidateCall) DoAndReturn(f func(string, string) error) *MockTokenSignerValidateCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// MockConfigReloader is a mock of ConfigReloader interface.
+type MockConfigReloader struct {
+	ctrl     *gomock.Controller
+	recorder *MockConfigReloaderMockRecorder
+	isgomock struct{}
+}
+
+// MockConfigReloaderMockRecorder is the mock recorder for MockConfigReloader.
+type MockConfigReloaderMockRecorder struct {
+	mock *MockConfigReloader
+}
+
+// NewMockConfigReloader creates a new mock instance.
+func NewMockConfigReloader(ctrl *gomock.Controller) *MockConfigReloader {
+	mock := &MockConfigReloader{ctrl: ctrl}
+	mock.recorder = &MockConfigReloaderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockConfigReloader) EXPECT() *MockConfigReloaderMockRecorder {
+	return m.recorder
+}
+
+// Reload mocks base method.
+func (m *MockConfigReloader) Reload() ([]string, []string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reload")
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].([]string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Reload indicates an expected call of Reload.
+func (mr *MockConfigReloaderMockRecorder) Reload() *MockConfigReloaderReloadCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reload", reflect.TypeOf((*MockConfigReloader)(nil).Reload))
+	return &MockConfigReloaderReloadCall{Call: call}
+}
+
+// MockConfigReloaderReloadCall wrap *gomock.Call
+type MockConfigReloaderReloadCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockConfigReloaderReloadCall) Return(arg0, arg1 []string, arg2 error) *MockConfigReloaderReloadCall {
+	c.Call = c.Call.Return(arg0, arg1, arg2)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockConfigReloaderReloadCall) Do(f func() ([]string, []string, error)) *MockConfigReloaderReloadCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockConfigReloaderReloadCall) DoAndReturn(f func() ([]string, []string, error)) *MockConfigReloaderReloadCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// MockShortURLStats is a mock of ShortURLStats interface.
+type MockShortURLStats struct {
+	ctrl     *gomock.Controller
+	recorder *MockShortURLStatsMockRecorder
+	isgomock struct{}
+}
+
+// MockShortURLStatsMockRecorder is the mock recorder for MockShortURLStats.
+type MockShortURLStatsMockRecorder struct {
+	mock *MockShortURLStats
+}
+
+// NewMockShortURLStats creates a new mock instance.
+func NewMockShortURLStats(ctrl *gomock.Controller) *MockShortURLStats {
+	mock := &MockShortURLStats{ctrl: ctrl}
+	mock.recorder = &MockShortURLStatsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockShortURLStats) EXPECT() *MockShortURLStatsMockRecorder {
+	return m.recorder
+}
+
+// ResetStats mocks base method.
+func (m *MockShortURLStats) ResetStats() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ResetStats")
+}
+
+// ResetStats indicates an expected call of ResetStats.
+func (mr *MockShortURLStatsMockRecorder) ResetStats() *MockShortURLStatsResetStatsCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetStats", reflect.TypeOf((*MockShortURLStats)(nil).ResetStats))
+	return &MockShortURLStatsResetStatsCall{Call: call}
+}
+
+// MockShortURLStatsResetStatsCall wrap *gomock.Call
+type MockShortURLStatsResetStatsCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockShortURLStatsResetStatsCall) Return() *MockShortURLStatsResetStatsCall {
+	c.Call = c.Call.Return()
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockShortURLStatsResetStatsCall) Do(f func()) *MockShortURLStatsResetStatsCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockShortURLStatsResetStatsCall) DoAndReturn(f func()) *MockShortURLStatsResetStatsCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// Stats mocks base method.
+func (m *MockShortURLStats) Stats() shorturl.ManagerStats {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stats")
+	ret0, _ := ret[0].(shorturl.ManagerStats)
+	return ret0
+}
+
+// Stats indicates an expected call of Stats.
+func (mr *MockShortURLStatsMockRecorder) Stats() *MockShortURLStatsStatsCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stats", reflect.TypeOf((*MockShortURLStats)(nil).Stats))
+	return &MockShortURLStatsStatsCall{Call: call}
+}
+
+// MockShortURLStatsStatsCall wrap *gomock.Call
+type MockShortURLStatsStatsCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockShortURLStatsStatsCall) Return(arg0 shorturl.ManagerStats) *MockShortURLStatsStatsCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockShortURLStatsStatsCall) Do(f func() shorturl.ManagerStats) *MockShortURLStatsStatsCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockShortURLStatsStatsCall) DoAndReturn(f func() shorturl.ManagerStats) *MockShortURLStatsStatsCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// MockCacheWarmer is a mock of CacheWarmer interface.
+type MockCacheWarmer struct {
+	ctrl     *gomock.Controller
+	recorder *MockCacheWarmerMockRecorder
+	isgomock struct{}
+}
+
+// MockCacheWarmerMockRecorder is the mock recorder for MockCacheWarmer.
+type MockCacheWarmerMockRecorder struct {
+	mock *MockCacheWarmer
+}
+
+// NewMockCacheWarmer creates a new mock instance.
+func NewMockCacheWarmer(ctrl *gomock.Controller) *MockCacheWarmer {
+	mock := &MockCacheWarmer{ctrl: ctrl}
+	mock.recorder = &MockCacheWarmerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCacheWarmer) EXPECT() *MockCacheWarmerMockRecorder {
+	return m.recorder
+}
+
+// WarmCache mocks base method.
+func (m *MockCacheWarmer) WarmCache(ctx context.Context, ids []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WarmCache", ctx, ids)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WarmCache indicates an expected call of WarmCache.
+func (mr *MockCacheWarmerMockRecorder) WarmCache(ctx, ids any) *MockCacheWarmerWarmCacheCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WarmCache", reflect.TypeOf((*MockCacheWarmer)(nil).WarmCache), ctx, ids)
+	return &MockCacheWarmerWarmCacheCall{Call: call}
+}
+
+// MockCacheWarmerWarmCacheCall wrap *gomock.Call
+type MockCacheWarmerWarmCacheCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockCacheWarmerWarmCacheCall) Return(arg0 error) *MockCacheWarmerWarmCacheCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockCacheWarmerWarmCacheCall) Do(f func(context.Context, []string) error) *MockCacheWarmerWarmCacheCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockCacheWarmerWarmCacheCall) DoAndReturn(f func(context.Context, []string) error) *MockCacheWarmerWarmCacheCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// WarmCacheMostVisited mocks base method.
+func (m *MockCacheWarmer) WarmCacheMostVisited(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WarmCacheMostVisited", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WarmCacheMostVisited indicates an expected call of WarmCacheMostVisited.
+func (mr *MockCacheWarmerMockRecorder) WarmCacheMostVisited(ctx any) *MockCacheWarmerWarmCacheMostVisitedCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WarmCacheMostVisited", reflect.TypeOf((*MockCacheWarmer)(nil).WarmCacheMostVisited), ctx)
+	return &MockCacheWarmerWarmCacheMostVisitedCall{Call: call}
+}
+
+// MockCacheWarmerWarmCacheMostVisitedCall wrap *gomock.Call
+type MockCacheWarmerWarmCacheMostVisitedCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockCacheWarmerWarmCacheMostVisitedCall) Return(arg0 error) *MockCacheWarmerWarmCacheMostVisitedCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockCacheWarmerWarmCacheMostVisitedCall) Do(f func(context.Context) error) *MockCacheWarmerWarmCacheMostVisitedCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockCacheWarmerWarmCacheMostVisitedCall) DoAndReturn(f func(context.Context) error) *MockCacheWarmerWarmCacheMostVisitedCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// MockLogger is a mock of Logger interface.
+type MockLogger struct {
+	ctrl     *gomock.Controller
+	recorder *MockLoggerMockRecorder
+	isgomock struct{}
+}
+
+// MockLoggerMockRecorder is the mock recorder for MockLogger.
+type MockLoggerMockRecorder struct {
+	mock *MockLogger
+}
+
+// NewMockLogger creates a new mock instance.
+func NewMockLogger(ctrl *gomock.Controller) *MockLogger {
+	mock := &MockLogger{ctrl: ctrl}
+	mock.recorder = &MockLoggerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLogger) EXPECT() *MockLoggerMockRecorder {
+	return m.recorder
+}
+
+// Debug mocks base method.
+func (m *MockLogger) Debug(msg string, args ...any) {
+	m.ctrl.T.Helper()
+	varargs := []any{msg}
+	for _, a := range args {
+		varargs = append(varargs, a)
+	}
+	m.ctrl.Call(m, "Debug", varargs...)
+}
+
+// Debug indicates an expected call of Debug.
+func (mr *MockLoggerMockRecorder) Debug(msg any, args ...any) *MockLoggerDebugCall {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{msg}, args...)
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Debug", reflect.TypeOf((*MockLogger)(nil).Debug), varargs...)
+	return &MockLoggerDebugCall{Call: call}
+}
+
+// MockLoggerDebugCall wrap *gomock.Call
+type MockLoggerDebugCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockLoggerDebugCall) Return() *MockLoggerDebugCall {
+	c.Call = c.Call.Return()
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockLoggerDebugCall) Do(f func(string, ...any)) *MockLoggerDebugCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockLoggerDebugCall) DoAndReturn(f func(string, ...any)) *MockLoggerDebugCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// Error mocks base method.
+func (m *MockLogger) Error(msg string, args ...any) {
+	m.ctrl.T.Helper()
+	varargs := []any{msg}
+	for _, a := range args {
+		varargs = append(varargs, a)
+	}
+	m.ctrl.Call(m, "Error", varargs...)
+}
+
+// Error indicates an expected call of Error.
+func (mr *MockLoggerMockRecorder) Error(msg any, args ...any) *MockLoggerErrorCall {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{msg}, args...)
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Error", reflect.TypeOf((*MockLogger)(nil).Error), varargs...)
+	return &MockLoggerErrorCall{Call: call}
+}
+
+// MockLoggerErrorCall wrap *gomock.Call
+type MockLoggerErrorCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockLoggerErrorCall) Return() *MockLoggerErrorCall {
+	c.Call = c.Call.Return()
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockLoggerErrorCall) Do(f func(string, ...any)) *MockLoggerErrorCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockLoggerErrorCall) DoAndReturn(f func(string, ...any)) *MockLoggerErrorCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// Info mocks base method.
+func (m *MockLogger) Info(msg string, args ...any) {
+	m.ctrl.T.Helper()
+	varargs := []any{msg}
+	for _, a := range args {
+		varargs = append(varargs, a)
+	}
+	m.ctrl.Call(m, "Info", varargs...)
+}
+
+// Info indicates an expected call of Info.
+func (mr *MockLoggerMockRecorder) Info(msg any, args ...any) *MockLoggerInfoCall {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{msg}, args...)
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Info", reflect.TypeOf((*MockLogger)(nil).Info), varargs...)
+	return &MockLoggerInfoCall{Call: call}
+}
+
+// MockLoggerInfoCall wrap *gomock.Call
+type MockLoggerInfoCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockLoggerInfoCall) Return() *MockLoggerInfoCall {
+	c.Call = c.Call.Return()
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockLoggerInfoCall) Do(f func(string, ...any)) *MockLoggerInfoCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockLoggerInfoCall) DoAndReturn(f func(string, ...any)) *MockLoggerInfoCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// Warn mocks base method.
+func (m *MockLogger) Warn(msg string, args ...any) {
+	m.ctrl.T.Helper()
+	varargs := []any{msg}
+	for _, a := range args {
+		varargs = append(varargs, a)
+	}
+	m.ctrl.Call(m, "Warn", varargs...)
+}
+
+// Warn indicates an expected call of Warn.
+func (mr *MockLoggerMockRecorder) Warn(msg any, args ...any) *MockLoggerWarnCall {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{msg}, args...)
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Warn", reflect.TypeOf((*MockLogger)(nil).Warn), varargs...)
+	return &MockLoggerWarnCall{Call: call}
+}
+
+// MockLoggerWarnCall wrap *gomock.Call
+type MockLoggerWarnCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockLoggerWarnCall) Return() *MockLoggerWarnCall {
+	c.Call = c.Call.Return()
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockLoggerWarnCall) Do(f func(string, ...any)) *MockLoggerWarnCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockLoggerWarnCall) DoAndReturn(f func(string, ...any)) *MockLoggerWarnCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
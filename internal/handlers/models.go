@@ -1,16 +1,308 @@
 package handlers
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/AvalosM/short-url-service/pkg/metrics"
+	"github.com/AvalosM/short-url-service/pkg/shorturl"
+	"github.com/AvalosM/short-url-service/pkg/webhook"
 )
 
+const (
+	// ErrorCodeInvalidURL is the error code of requests with an invalid long URL
+	ErrorCodeInvalidURL = "INVALID_URL"
+	// ErrorCodeURLTooLong is the error code of requests with a long URL over the configured length
+	ErrorCodeURLTooLong = "URL_TOO_LONG"
+	// ErrorCodeURLDenied is the error code of requests with a long URL of a denied host
+	ErrorCodeURLDenied = "URL_DENIED"
+)
+
+// ErrorResponse ...
+type ErrorResponse struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
 // ShortURLRequest ...
 type ShortURLRequest struct {
+	LongURL string   `json:"long_url"`
+	Tags    []string `json:"tags,omitempty"`
+	// Description is a human readable note of at most 512 characters about the short URL
+	Description string `json:"description,omitempty"`
+	MaxClicks   int    `json:"max_clicks,omitempty"`
+	Password    string `json:"password,omitempty"`
+	// RedirectCode is one of 301, 302 or 307, the service default is used when it is not set
+	RedirectCode int `json:"redirect_code,omitempty"`
+	// ForwardQueryParams appends the query parameters of the short URL request to the long URL on redirect
+	ForwardQueryParams bool `json:"forward_query_params,omitempty"`
+	// ExpiresAt must be in the future, the short URL never expires when it is not set
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	// CacheTTLSeconds is how long the redirect is cached, 1 second to 1 year, the service default is used when it is
+	// not set or 0
+	CacheTTLSeconds *int `json:"cache_ttl_seconds,omitempty"`
+	// ShowInterstitial shows a page with the long URL that redirects after a 5 seconds countdown
+	ShowInterstitial bool           `json:"show_interstitial,omitempty"`
+	Webhook          *WebhookConfig `json:"webhook,omitempty"`
+}
+
+// GetOrCreateShortURLRequest ...
+type GetOrCreateShortURLRequest struct {
 	LongURL string `json:"long_url"`
 }
 
+// GetOrCreateShortURLResponse ...
+type GetOrCreateShortURLResponse struct {
+	Id       string `json:"id"`
+	ShortURL string `json:"short_url"`
+	// Created is false when the long URL was already shortened
+	Created bool `json:"created"`
+}
+
+// ImportShortURLsResponse ...
+type ImportShortURLsResponse struct {
+	Created int                  `json:"created"`
+	Failed  int                  `json:"failed"`
+	Rows    []*ImportRowResponse `json:"rows"`
+}
+
+// ImportRowResponse ...
+type ImportRowResponse struct {
+	// Row is the line of the CSV row, the header being line 1
+	Row      int    `json:"row"`
+	LongURL  string `json:"long_url"`
+	Id       string `json:"id,omitempty"`
+	ShortURL string `json:"short_url,omitempty"`
+	// Error tells why the row was not imported, the short URL may still have been created when only its custom slug failed
+	Error string `json:"error,omitempty"`
+}
+
+// WebhookConfig ...
+type WebhookConfig struct {
+	URL    string `json:"url"`
+	Secret string `json:"secret"`
+}
+
+// ShortURLResponse ...
+type ShortURLResponse struct {
+	Id          string           `json:"id"`
+	ShortURL    string           `json:"short_url"`
+	Tags        []string         `json:"tags"`
+	Description string           `json:"description,omitempty"`
+	CreatedBy   string           `json:"created_by,omitempty"`
+	CreatedAt   time.Time        `json:"created_at"`
+	UpdatedAt   time.Time        `json:"updated_at"`
+	Webhook     *WebhookResponse `json:"webhook,omitempty"`
+}
+
+// NewShortURLResponse creates a new ShortURLResponse from the given short URL
+func NewShortURLResponse(shortURL *shorturl.ShortURL, baseURL string) *ShortURLResponse {
+	return &ShortURLResponse{
+		Id:          shortURL.Id,
+		ShortURL:    baseURL + shortURL.Id,
+		Tags:        nonNilTags(shortURL.Tags),
+		Description: shortURL.Description,
+		CreatedBy:   shortURL.CreatedBy,
+		CreatedAt:   shortURL.CreatedAt,
+		UpdatedAt:   shortURL.UpdatedAt,
+	}
+}
+
+// WebhookResponse ...
+type WebhookResponse struct {
+	Id         int64     `json:"id"`
+	ShortURLId string    `json:"short_url_id"`
+	URL        string    `json:"url"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// NewWebhookResponse creates a new WebhookResponse from the given webhook, the secret is never returned
+func NewWebhookResponse(webhook *webhook.Webhook) *WebhookResponse {
+	return &WebhookResponse{
+		Id:         webhook.Id,
+		ShortURLId: webhook.ShortURLId,
+		URL:        webhook.URL,
+		CreatedAt:  webhook.CreatedAt,
+	}
+}
+
+// AliasRequest ...
+type AliasRequest struct {
+	AliasId string `json:"alias_id"`
+}
+
+// AliasResponse ...
+type AliasResponse struct {
+	AliasId    string `json:"alias_id"`
+	ShortURLId string `json:"short_url_id"`
+	ShortURL   string `json:"short_url"`
+}
+
+// NewAliasResponse creates a new AliasResponse for an alias of the short URL with the given id
+func NewAliasResponse(aliasId string, shortURLId string, baseURL string) *AliasResponse {
+	return &AliasResponse{
+		AliasId:    aliasId,
+		ShortURLId: shortURLId,
+		ShortURL:   baseURL + aliasId,
+	}
+}
+
+// ShortURLListResponse ...
+type ShortURLListResponse struct {
+	ShortURLs []*ShortURLListItem `json:"short_urls"`
+}
+
+// ShortURLListItem ...
+type ShortURLListItem struct {
+	Id          string    `json:"id"`
+	ShortURL    string    `json:"short_url"`
+	LongURL     string    `json:"long_url"`
+	Tags        []string  `json:"tags"`
+	Description string    `json:"description,omitempty"`
+	CreatedBy   string    `json:"created_by,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// NewShortURLListResponse creates a new ShortURLListResponse from the given short URLs
+func NewShortURLListResponse(shortURLs []*shorturl.ShortURL, baseURL string) *ShortURLListResponse {
+	items := make([]*ShortURLListItem, 0, len(shortURLs))
+	for _, shortURL := range shortURLs {
+		items = append(items, NewShortURLListItem(shortURL, baseURL))
+	}
+
+	return &ShortURLListResponse{
+		ShortURLs: items,
+	}
+}
+
+// NewShortURLListItem creates a new ShortURLListItem from the given short URL
+func NewShortURLListItem(shortURL *shorturl.ShortURL, baseURL string) *ShortURLListItem {
+	return &ShortURLListItem{
+		Id:          shortURL.Id,
+		ShortURL:    baseURL + shortURL.Id,
+		LongURL:     shortURL.LongURL,
+		Tags:        nonNilTags(shortURL.Tags),
+		Description: shortURL.Description,
+		CreatedBy:   shortURL.CreatedBy,
+		CreatedAt:   shortURL.CreatedAt,
+		UpdatedAt:   shortURL.UpdatedAt,
+	}
+}
+
+// ResolveShortURLsResponse ...
+type ResolveShortURLsResponse struct {
+	// LongURLs are the long URLs of the short URLs found by id
+	LongURLs map[string]string `json:"long_urls"`
+	NotFound []string          `json:"not_found"`
+}
+
+// ClickEvent is a click of a short URL sent by the click stream
+type ClickEvent struct {
+	Id        string    `json:"id"`
+	IP        string    `json:"ip"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// ShortURLPreviewResponse ...
+type ShortURLPreviewResponse struct {
+	Id          string    `json:"id"`
+	LongURL     string    `json:"long_url,omitempty"`
+	Protected   bool      `json:"protected,omitempty"`
+	Tags        []string  `json:"tags"`
+	Description string    `json:"description,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// NewShortURLPreviewResponse creates a new ShortURLPreviewResponse from the given short URL, the long URL of a
+// password protected short URL is not included
+func NewShortURLPreviewResponse(shortURL *shorturl.ShortURL) *ShortURLPreviewResponse {
+	response := &ShortURLPreviewResponse{
+		Id:          shortURL.Id,
+		Protected:   shortURL.Protected(),
+		Tags:        nonNilTags(shortURL.Tags),
+		Description: shortURL.Description,
+		CreatedAt:   shortURL.CreatedAt,
+		UpdatedAt:   shortURL.UpdatedAt,
+	}
+	if !response.Protected {
+		response.LongURL = shortURL.LongURL
+	}
+
+	return response
+}
+
+// ShortURLDetailsResponse ...
+type ShortURLDetailsResponse struct {
+	Id                 string          `json:"id"`
+	ShortURL           string          `json:"short_url"`
+	LongURL            string          `json:"long_url"`
+	Tags               []string        `json:"tags"`
+	Description        string          `json:"description,omitempty"`
+	Status             shorturl.Status `json:"status"`
+	MaxClicks          int             `json:"max_clicks"`
+	ClickCount         int             `json:"click_count"`
+	Protected          bool            `json:"protected"`
+	RedirectCode       int             `json:"redirect_code,omitempty"`
+	ForwardQueryParams bool            `json:"forward_query_params"`
+	ExpiresAt          *time.Time      `json:"expires_at,omitempty"`
+	CacheTTLSeconds    int             `json:"cache_ttl_seconds,omitempty"`
+	ShowInterstitial   bool            `json:"show_interstitial"`
+	CreatedBy          string          `json:"created_by,omitempty"`
+	CreatedAt          time.Time       `json:"created_at"`
+	UpdatedAt          time.Time       `json:"updated_at"`
+}
+
+// NewShortURLDetailsResponse creates a new ShortURLDetailsResponse from the given short URL, the password hash is
+// never returned
+func NewShortURLDetailsResponse(shortURL *shorturl.ShortURL, baseURL string) *ShortURLDetailsResponse {
+	return &ShortURLDetailsResponse{
+		Id:                 shortURL.Id,
+		ShortURL:           baseURL + shortURL.Id,
+		LongURL:            shortURL.LongURL,
+		Tags:               nonNilTags(shortURL.Tags),
+		Description:        shortURL.Description,
+		Status:             shortURL.Status,
+		MaxClicks:          shortURL.MaxClicks,
+		ClickCount:         shortURL.ClickCount,
+		Protected:          shortURL.Protected(),
+		RedirectCode:       shortURL.RedirectCode,
+		ForwardQueryParams: shortURL.ForwardQueryParams,
+		ExpiresAt:          shortURL.ExpiresAt,
+		CacheTTLSeconds:    shortURL.CacheTTLSeconds,
+		ShowInterstitial:   shortURL.ShowInterstitial,
+		CreatedBy:          shortURL.CreatedBy,
+		CreatedAt:          shortURL.CreatedAt,
+		UpdatedAt:          shortURL.UpdatedAt,
+	}
+}
+
+// ProtectedShortURLResponse ...
+type ProtectedShortURLResponse struct {
+	Protected bool `json:"protected"`
+}
+
+// UnlockShortURLRequest ...
+type UnlockShortURLRequest struct {
+	Password string `json:"password"`
+}
+
+// UnlockShortURLResponse ...
+type UnlockShortURLResponse struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// nonNilTags makes sure tags are serialized as an empty list instead of null
+func nonNilTags(tags []string) []string {
+	if tags == nil {
+		return []string{}
+	}
+
+	return tags
+}
+
 // ShortURLMetricsRequest ...
 type ShortURLMetricsRequest struct {
 	From time.Time `json:"from"`
@@ -19,14 +311,270 @@ type ShortURLMetricsRequest struct {
 
 // ShortURLMetricsResponse ...
 type ShortURLMetricsResponse struct {
-	Visits       int64 `json:"visits"`
-	UniqueVisits int64 `json:"unique_visits"`
+	ShortURLId   string `json:"short_url_id"`
+	Visits       int64  `json:"visits"`
+	UniqueVisits int64  `json:"unique_visits"`
+	BotVisits    int64  `json:"bot_visits"`
+	// TopCountries are the countries with the most visits, most visited first
+	TopCountries []*CountryStatResponse `json:"top_countries"`
+	From         time.Time              `json:"from"`
+	To           time.Time              `json:"to"`
+	// CreatedAt is the creation time of the short URL, it is left out of bulk responses
+	CreatedAt time.Time `json:"created_at,omitzero"`
+}
+
+// CountryStatResponse ...
+type CountryStatResponse struct {
+	Country string `json:"country"`
+	Visits  int64  `json:"visits"`
 }
 
 // NewShortURLMetricsResponse creates a new ShortURLMetricsResponse from the given metrics
-func NewShortURLMetricsResponse(metrics *metrics.Metrics) *ShortURLMetricsResponse {
+func NewShortURLMetricsResponse(metrics *metrics.Metrics, shortURL *shorturl.ShortURL) *ShortURLMetricsResponse {
+	response := newMetricsResponse(metrics)
+	response.CreatedAt = shortURL.CreatedAt
+
+	return response
+}
+
+// MetricsBulkRequest ...
+type MetricsBulkRequest struct {
+	// Ids are the short URLs to get metrics for, at most 100
+	Ids  []string  `json:"ids"`
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
+
+// MetricsBulkResponse ...
+type MetricsBulkResponse struct {
+	// Metrics are the metrics of each short URL asked for by id, short URLs without visits get zero metrics
+	Metrics map[string]*ShortURLMetricsResponse `json:"metrics"`
+}
+
+// NewMetricsBulkResponse creates a new MetricsBulkResponse from the given metrics by short URL id
+func NewMetricsBulkResponse(bulk map[string]*metrics.Metrics) *MetricsBulkResponse {
+	response := make(map[string]*ShortURLMetricsResponse, len(bulk))
+	for id, metricsResult := range bulk {
+		response[id] = newMetricsResponse(metricsResult)
+	}
+
+	return &MetricsBulkResponse{Metrics: response}
+}
+
+func newMetricsResponse(metrics *metrics.Metrics) *ShortURLMetricsResponse {
+	topCountries := make([]*CountryStatResponse, 0, len(metrics.TopCountries))
+	for _, stat := range metrics.TopCountries {
+		topCountries = append(topCountries, &CountryStatResponse{Country: stat.Country, Visits: stat.Visits})
+	}
+
 	return &ShortURLMetricsResponse{
+		ShortURLId:   metrics.ShortURLId,
 		Visits:       metrics.Visits,
 		UniqueVisits: metrics.UniqueVisits,
+		BotVisits:    metrics.BotVisits,
+		TopCountries: topCountries,
+		From:         metrics.From,
+		To:           metrics.To,
 	}
 }
+
+// ShortURLLatencyResponse ...
+type ShortURLLatencyResponse struct {
+	ShortURLId string                     `json:"short_url_id"`
+	From       time.Time                  `json:"from"`
+	To         time.Time                  `json:"to"`
+	Intervals  []*LatencyIntervalResponse `json:"intervals"`
+}
+
+// LatencyIntervalResponse ...
+type LatencyIntervalResponse struct {
+	Timestamp time.Time `json:"timestamp"`
+	Requests  int64     `json:"requests"`
+	P50Ns     int64     `json:"p50_ns"`
+	P95Ns     int64     `json:"p95_ns"`
+	P99Ns     int64     `json:"p99_ns"`
+}
+
+// NewShortURLLatencyResponse creates a new ShortURLLatencyResponse from the given latencies
+func NewShortURLLatencyResponse(shortURLId string, from, to time.Time, latencies []*metrics.Latency) *ShortURLLatencyResponse {
+	intervals := make([]*LatencyIntervalResponse, 0, len(latencies))
+	for _, latency := range latencies {
+		intervals = append(intervals, &LatencyIntervalResponse{
+			Timestamp: latency.Timestamp,
+			Requests:  latency.Requests,
+			P50Ns:     latency.P50Ns,
+			P95Ns:     latency.P95Ns,
+			P99Ns:     latency.P99Ns,
+		})
+	}
+
+	return &ShortURLLatencyResponse{
+		ShortURLId: shortURLId,
+		From:       from,
+		To:         to,
+		Intervals:  intervals,
+	}
+}
+
+// MetricsSnapshotResponse ...
+type MetricsSnapshotResponse struct {
+	ShortURLs map[string]*CollectorSnapshotResponse `json:"short_urls"`
+}
+
+// CollectorSnapshotResponse ...
+type CollectorSnapshotResponse struct {
+	ShortURLId   string `json:"short_url_id"`
+	Visits       int64  `json:"visits"`
+	UniqueVisits int64  `json:"unique_visits"`
+	BotVisits    int64  `json:"bot_visits"`
+}
+
+// NewMetricsSnapshotResponse creates a new MetricsSnapshotResponse from the given collector snapshots
+func NewMetricsSnapshotResponse(snapshot map[string]metrics.CollectorSnapshot) *MetricsSnapshotResponse {
+	shortURLs := make(map[string]*CollectorSnapshotResponse, len(snapshot))
+	for id, collector := range snapshot {
+		shortURLs[id] = &CollectorSnapshotResponse{
+			ShortURLId:   collector.ShortURLId,
+			Visits:       collector.Visits,
+			UniqueVisits: collector.UniqueVisits,
+			BotVisits:    collector.BotVisits,
+		}
+	}
+
+	return &MetricsSnapshotResponse{
+		ShortURLs: shortURLs,
+	}
+}
+
+// AuditLogResponse ...
+type AuditLogResponse struct {
+	Entries []*AuditLogEntry `json:"entries"`
+}
+
+// AuditLogEntry ...
+type AuditLogEntry struct {
+	Id         int64           `json:"id"`
+	Operation  string          `json:"operation"`
+	ShortURLId string          `json:"short_url_id"`
+	Actor      string          `json:"actor,omitempty"`
+	Payload    json.RawMessage `json:"payload,omitempty" swaggertype:"object"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+// NewAuditLogResponse creates a new AuditLogResponse from the given audit entries
+func NewAuditLogResponse(entries []*shorturl.AuditEntry) *AuditLogResponse {
+	items := make([]*AuditLogEntry, 0, len(entries))
+	for _, entry := range entries {
+		items = append(items, &AuditLogEntry{
+			Id:         entry.Id,
+			Operation:  entry.Operation,
+			ShortURLId: entry.ShortURLId,
+			Actor:      entry.Actor,
+			Payload:    entry.Payload,
+			CreatedAt:  entry.CreatedAt,
+		})
+	}
+
+	return &AuditLogResponse{
+		Entries: items,
+	}
+}
+
+// ConfigReloadResponse ...
+type ConfigReloadResponse struct {
+	// Reloaded are the configuration sections applied by the reload
+	Reloaded []string `json:"reloaded"`
+	// Ignored are the changed configuration sections that only take effect on restart
+	Ignored []string `json:"ignored"`
+}
+
+// NewConfigReloadResponse creates a new ConfigReloadResponse from the given reloaded and ignored sections
+func NewConfigReloadResponse(reloaded []string, ignored []string) *ConfigReloadResponse {
+	if reloaded == nil {
+		reloaded = []string{}
+	}
+	if ignored == nil {
+		ignored = []string{}
+	}
+
+	return &ConfigReloadResponse{
+		Reloaded: reloaded,
+		Ignored:  ignored,
+	}
+}
+
+// ManagerStatsResponse ...
+type ManagerStatsResponse struct {
+	CollisionCount uint64 `json:"collision_count"`
+	CreateCount    uint64 `json:"create_count"`
+	// CollisionRate is collision_count divided by create_count, 0 until a short URL is created
+	CollisionRate float64 `json:"collision_rate"`
+	HitCount      uint64  `json:"hit_count"`
+	MissCount     uint64  `json:"miss_count"`
+}
+
+// NewManagerStatsResponse creates a new ManagerStatsResponse from the given short URL manager stats
+func NewManagerStatsResponse(stats shorturl.ManagerStats) *ManagerStatsResponse {
+	return &ManagerStatsResponse{
+		CollisionCount: stats.CollisionCount,
+		CreateCount:    stats.CreateCount,
+		CollisionRate:  stats.CollisionRate,
+		HitCount:       stats.HitCount,
+		MissCount:      stats.MissCount,
+	}
+}
+
+// WarmCacheRequest ...
+type WarmCacheRequest struct {
+	// Ids are the short URLs to cache, at most 10000
+	Ids []string `json:"ids"`
+}
+
+// DashboardResponse ...
+type DashboardResponse struct {
+	From             time.Time `json:"from"`
+	To               time.Time `json:"to"`
+	ShortURLsCreated int64     `json:"short_urls_created"`
+	Visits           int64     `json:"visits"`
+	// UniqueVisits sums the unique visitors of every metrics interval
+	UniqueVisits      int64                     `json:"unique_visits"`
+	TopByVisits       []*DashboardShortURLStats `json:"top_by_visits"`
+	TopByUniqueVisits []*DashboardShortURLStats `json:"top_by_unique_visits"`
+}
+
+// DashboardShortURLStats ...
+type DashboardShortURLStats struct {
+	Id           string `json:"id"`
+	ShortURL     string `json:"short_url"`
+	LongURL      string `json:"long_url"`
+	Visits       int64  `json:"visits"`
+	UniqueVisits int64  `json:"unique_visits"`
+}
+
+// NewDashboardResponse creates a new DashboardResponse from the given dashboard stats
+func NewDashboardResponse(stats *shorturl.DashboardStats, baseURL string) *DashboardResponse {
+	return &DashboardResponse{
+		From:              stats.From,
+		To:                stats.To,
+		ShortURLsCreated:  stats.ShortURLsCreated,
+		Visits:            stats.Visits,
+		UniqueVisits:      stats.UniqueVisits,
+		TopByVisits:       newDashboardShortURLStats(stats.TopByVisits, baseURL),
+		TopByUniqueVisits: newDashboardShortURLStats(stats.TopByUniqueVisits, baseURL),
+	}
+}
+
+func newDashboardShortURLStats(top []*shorturl.ShortURLStats, baseURL string) []*DashboardShortURLStats {
+	response := make([]*DashboardShortURLStats, 0, len(top))
+	for _, stats := range top {
+		response = append(response, &DashboardShortURLStats{
+			Id:           stats.ShortURLId,
+			ShortURL:     baseURL + stats.ShortURLId,
+			LongURL:      stats.LongURL,
+			Visits:       stats.Visits,
+			UniqueVisits: stats.UniqueVisits,
+		})
+	}
+
+	return response
+}
@@ -0,0 +1,20 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// validateTimeRange checks the time range of a request, it must start before it ends and span at most
+// MaxMetricsRangeInDays
+func (c *Config) validateTimeRange(from, to time.Time) error {
+	if !from.Before(to) {
+		return errors.New("from must be before to")
+	}
+	if to.Sub(from) > time.Duration(c.MaxMetricsRangeInDays)*24*time.Hour {
+		return fmt.Errorf("time range cannot exceed %d days", c.MaxMetricsRangeInDays)
+	}
+
+	return nil
+}
@@ -0,0 +1,106 @@
+package middleware
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+
+	"github.com/AvalosM/short-url-service/pkg/logging"
+)
+
+const (
+	// AccessLogFormatJSON logs every request as a structured entry
+	AccessLogFormatJSON = "json"
+	// AccessLogFormatCombined logs every request as a line of the Apache Combined Log Format
+	AccessLogFormatCombined = "combined"
+
+	accessLogMessage   = "http request"
+	combinedTimeLayout = "02/Jan/2006:15:04:05 -0700"
+)
+
+// AccessLog logs every request with its status code, latency, request ID and remote IP in the given format,
+// AccessLogFormatJSON or AccessLogFormatCombined. The request ID is set by chi's RequestID middleware.
+func AccessLog(logger Logger, format string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+			next.ServeHTTP(recorder, r)
+
+			// Read after serving, RealIP further down the chain rewrites the remote address of the request
+			remoteIP := r.RemoteAddr
+			if host, _, err := net.SplitHostPort(remoteIP); err == nil {
+				remoteIP = host
+			}
+
+			if format == AccessLogFormatCombined {
+				logger.Info(combinedLogLine(r, remoteIP, start, recorder))
+
+				return
+			}
+
+			logger.Info(accessLogMessage,
+				logging.MethodKey, r.Method,
+				logging.PathKey, r.URL.Path,
+				logging.StatusKey, recorder.status,
+				logging.LatencyMSKey, float64(time.Since(start).Microseconds())/1000,
+				logging.RequestIdKey, chimiddleware.GetReqID(r.Context()),
+				logging.RemoteIPKey, remoteIP,
+			)
+		})
+	}
+}
+
+// combinedLogLine formats a request as %h %l %u %t "%r" %>s %b "%{Referer}i" "%{User-agent}i"
+func combinedLogLine(r *http.Request, remoteIP string, start time.Time, recorder *statusRecorder) string {
+	size := "-"
+	if recorder.bytes > 0 {
+		size = strconv.Itoa(recorder.bytes)
+	}
+
+	return fmt.Sprintf("%s - - [%s] %q %d %s %q %q",
+		remoteIP,
+		start.Format(combinedTimeLayout),
+		r.Method+" "+r.RequestURI+" "+r.Proto,
+		recorder.status,
+		size,
+		r.Referer(),
+		r.UserAgent(),
+	)
+}
+
+// statusRecorder captures the status code and body size of a response
+type statusRecorder struct {
+	http.ResponseWriter
+	status      int
+	bytes       int
+	wroteHeader bool
+}
+
+func (w *statusRecorder) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.status = status
+		w.wroteHeader = true
+	}
+
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusRecorder) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += n
+
+	return n, err
+}
+
+// Unwrap lets http.ResponseController reach the underlying response writer
+func (w *statusRecorder) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
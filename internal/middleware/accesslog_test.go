@@ -0,0 +1,100 @@
+package middleware_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/AvalosM/short-url-service/internal/middleware"
+)
+
+type AccessLogSuite struct {
+	suite.Suite
+	output *bytes.Buffer
+	logger *slog.Logger
+}
+
+func (suite *AccessLogSuite) SetupTest() {
+	suite.output = &bytes.Buffer{}
+	suite.logger = slog.New(slog.NewJSONHandler(suite.output, nil))
+}
+
+func TestAccessLogSuite(t *testing.T) {
+	suite.Run(t, new(AccessLogSuite))
+}
+
+func (suite *AccessLogSuite) serve(format string, handler http.HandlerFunc, request *http.Request) {
+	chain := chimiddleware.RequestID(middleware.AccessLog(suite.logger, format)(handler))
+	chain.ServeHTTP(httptest.NewRecorder(), request)
+}
+
+func (suite *AccessLogSuite) TestAccessLogJSON() {
+	request := httptest.NewRequest(http.MethodGet, "/public/v1/short-urls/AABBCC?utm_source=newsletter", nil)
+	request.RemoteAddr = "203.0.113.7:54321"
+	request.Header.Set(chimiddleware.RequestIDHeader, "request-42")
+
+	suite.serve(middleware.AccessLogFormatJSON, func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "https://example.com", http.StatusFound)
+	}, request)
+
+	var entry map[string]interface{}
+	suite.Require().NoError(json.Unmarshal(suite.output.Bytes(), &entry))
+	suite.Equal("INFO", entry["level"])
+	suite.Equal("http request", entry["msg"])
+	suite.Equal(http.MethodGet, entry["method"])
+	suite.Equal("/public/v1/short-urls/AABBCC", entry["path"])
+	suite.Equal(float64(http.StatusFound), entry["status"])
+	suite.Equal("request-42", entry["requestId"])
+	suite.Equal("203.0.113.7", entry["remoteIP"])
+	suite.Contains(entry, "latencyMS")
+	suite.GreaterOrEqual(entry["latencyMS"], float64(0))
+}
+
+func (suite *AccessLogSuite) TestAccessLogJSONDefaultStatus() {
+	suite.serve(middleware.AccessLogFormatJSON, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	var entry map[string]interface{}
+	suite.Require().NoError(json.Unmarshal(suite.output.Bytes(), &entry))
+	suite.Equal(float64(http.StatusOK), entry["status"])
+	suite.NotEmpty(entry["requestId"])
+}
+
+func (suite *AccessLogSuite) TestAccessLogCombined() {
+	request := httptest.NewRequest(http.MethodPost, "/private/v1/short-urls/?dry_run=true", nil)
+	request.RemoteAddr = "203.0.113.7:54321"
+	request.Header.Set("Referer", "https://example.com/")
+	request.Header.Set("User-Agent", "curl/8.5.0")
+
+	suite.serve(middleware.AccessLogFormatCombined, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id":"AABBCC"}`))
+	}, request)
+
+	var entry map[string]interface{}
+	suite.Require().NoError(json.Unmarshal(suite.output.Bytes(), &entry))
+	suite.Regexp(regexp.MustCompile(
+		`^203\.0\.113\.7 - - \[\d{2}/\w{3}/\d{4}:\d{2}:\d{2}:\d{2} [+-]\d{4}\] `+
+			`"POST /private/v1/short-urls/\?dry_run=true HTTP/1\.1" 201 15 "https://example\.com/" "curl/8\.5\.0"$`),
+		entry["msg"])
+}
+
+func (suite *AccessLogSuite) TestAccessLogCombinedEmptyBody() {
+	request := httptest.NewRequest(http.MethodDelete, "/private/v1/short-urls/AABBCC", nil)
+
+	suite.serve(middleware.AccessLogFormatCombined, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}, request)
+
+	var entry map[string]interface{}
+	suite.Require().NoError(json.Unmarshal(suite.output.Bytes(), &entry))
+	suite.Regexp(`" 204 - "" ""$`, entry["msg"])
+}
@@ -0,0 +1,129 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/AvalosM/short-url-service/pkg/tenant"
+	"github.com/AvalosM/short-url-service/pkg/user"
+)
+
+const (
+	bearerPrefix  = "Bearer "
+	tenantIdClaim = "tenant_id"
+)
+
+// TenantClaims are the claims of the JWTs accepted by the Auth middleware, the sub claim is the id of the user the
+// token is issued to
+type TenantClaims struct {
+	TenantId string `json:"tenant_id"`
+	// Role is the role of the user, user.RoleAdmin users manage the short URLs of every user
+	Role string `json:"role,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// TokenAuthenticator validates the bearer tokens accepted by the Auth middleware, the gRPC server validates its
+// tokens with it too
+type TokenAuthenticator struct {
+	parser *jwt.Parser
+	secret []byte
+}
+
+// NewTokenAuthenticator creates a TokenAuthenticator of HS256 tokens signed with secret
+func NewTokenAuthenticator(secret []byte) *TokenAuthenticator {
+	return &TokenAuthenticator{
+		parser: jwt.NewParser(jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()})),
+		secret: secret,
+	}
+}
+
+// Authenticate validates the bearer token of the given Authorization value and returns ctx scoped to the tenant of
+// its tenant_id claim, on behalf of the user of its sub claim with the role of its role claim
+func (a *TokenAuthenticator) Authenticate(ctx context.Context, authorization string) (context.Context, error) {
+	if !strings.HasPrefix(authorization, bearerPrefix) {
+		return nil, errors.New("missing bearer token")
+	}
+
+	claims := &TenantClaims{}
+	keyFunc := func(*jwt.Token) (interface{}, error) {
+		return a.secret, nil
+	}
+	if _, err := a.parser.ParseWithClaims(strings.TrimPrefix(authorization, bearerPrefix), claims, keyFunc); err != nil {
+		return nil, errors.New("invalid token")
+	}
+	if claims.TenantId == "" {
+		return nil, errors.New("token is missing the " + tenantIdClaim + " claim")
+	}
+	if len(claims.TenantId) > tenant.MaxIDLength {
+		return nil, errors.New("invalid " + tenantIdClaim + " claim")
+	}
+	if len(claims.Subject) > user.MaxIDLength {
+		return nil, errors.New("invalid sub claim")
+	}
+
+	ctx = tenant.WithID(ctx, claims.TenantId)
+	if claims.Subject != "" {
+		ctx = user.WithID(ctx, claims.Subject)
+	}
+	if claims.Role != "" {
+		ctx = user.WithRole(ctx, claims.Role)
+	}
+
+	return ctx, nil
+}
+
+// Auth authenticates requests with an HS256 signed JWT sent as a bearer token and scopes them to the tenant
+// of its tenant_id claim. Requests without a valid token carrying a tenant are rejected with a 401. Requests are made
+// on behalf of the user of the sub claim with the role of the role claim, tokens without them are still accepted.
+func Auth(secret []byte) func(http.Handler) http.Handler {
+	authenticator := NewTokenAuthenticator(secret)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, err := authenticator.Authenticate(r.Context(), r.Header.Get("Authorization"))
+			if err != nil {
+				writeJSONError(w, http.StatusUnauthorized, err.Error())
+
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// RequireAdmin rejects with a 403 the requests not made by a user with user.RoleAdmin, it goes after Auth which sets
+// the role of the request
+func RequireAdmin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !user.IsAdmin(r.Context()) {
+			writeJSONError(w, http.StatusForbidden, "admin role required")
+
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// TenantFromURLParam scopes requests to the tenant given by the named chi URL parameter, it is used by the public
+// routes of non-default tenants which are not authenticated
+func TenantFromURLParam(param string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			tenantID := chi.URLParam(r, param)
+			if tenantID == "" || len(tenantID) > tenant.MaxIDLength {
+				writeJSONError(w, http.StatusBadRequest, "invalid tenant id")
+
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(tenant.WithID(r.Context(), tenantID)))
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/AvalosM/short-url-service/internal/middleware"
+	"github.com/AvalosM/short-url-service/pkg/tenant"
+	"github.com/AvalosM/short-url-service/pkg/user"
+)
+
+type AuthSuite struct {
+	suite.Suite
+	secret []byte
+}
+
+func (suite *AuthSuite) SetupTest() {
+	suite.secret = []byte("0123456789abcdef0123456789abcdef")
+}
+
+func TestAuthSuite(t *testing.T) {
+	suite.Run(t, new(AuthSuite))
+}
+
+func (suite *AuthSuite) sign(method jwt.SigningMethod, key interface{}, claims jwt.Claims) string {
+	token, err := jwt.NewWithClaims(method, claims).SignedString(key)
+	suite.Require().NoError(err)
+
+	return token
+}
+
+func (suite *AuthSuite) serve(authorization string) (*httptest.ResponseRecorder, string) {
+	var tenantID string
+	handler := middleware.Auth(suite.secret)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tenantID = tenant.IDFromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	request := httptest.NewRequest(http.MethodGet, "/private/v1/short-urls", nil)
+	if authorization != "" {
+		request.Header.Set("Authorization", authorization)
+	}
+
+	response := httptest.NewRecorder()
+	handler.ServeHTTP(response, request)
+
+	return response, tenantID
+}
+
+func (suite *AuthSuite) TestAuthSuccess() {
+	token := suite.sign(jwt.SigningMethodHS256, suite.secret, &middleware.TenantClaims{
+		TenantId:         "acme",
+		RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour))},
+	})
+
+	response, tenantID := suite.serve("Bearer " + token)
+
+	suite.Equal(http.StatusOK, response.Code)
+	suite.Equal("acme", tenantID)
+}
+
+func (suite *AuthSuite) TestAuthSuccessUser() {
+	token := suite.sign(jwt.SigningMethodHS256, suite.secret, &middleware.TenantClaims{
+		TenantId:         "acme",
+		Role:             user.RoleAdmin,
+		RegisteredClaims: jwt.RegisteredClaims{Subject: "user-1", ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour))},
+	})
+
+	var userID string
+	var admin bool
+	handler := middleware.Auth(suite.secret)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userID = user.IDFromContext(r.Context())
+		admin = user.IsAdmin(r.Context())
+	}))
+
+	request := httptest.NewRequest(http.MethodGet, "/private/v1/users/user-2/short-urls", nil)
+	request.Header.Set("Authorization", "Bearer "+token)
+	handler.ServeHTTP(httptest.NewRecorder(), request)
+
+	suite.Equal("user-1", userID)
+	suite.True(admin)
+}
+
+func (suite *AuthSuite) TestAuthFailMissingToken() {
+	response, _ := suite.serve("")
+
+	suite.Equal(http.StatusUnauthorized, response.Code)
+	suite.Equal("application/json", response.Header().Get("Content-Type"))
+}
+
+func (suite *AuthSuite) TestAuthFailInvalidToken() {
+	testCases := []struct {
+		name  string
+		token string
+	}{
+		{
+			name:  "wrong secret",
+			token: suite.sign(jwt.SigningMethodHS256, []byte("another secret"), &middleware.TenantClaims{TenantId: "acme"}),
+		},
+		{
+			name:  "wrong signing method",
+			token: suite.sign(jwt.SigningMethodHS512, suite.secret, &middleware.TenantClaims{TenantId: "acme"}),
+		},
+		{
+			name: "expired",
+			token: suite.sign(jwt.SigningMethodHS256, suite.secret, &middleware.TenantClaims{
+				TenantId:         "acme",
+				RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Hour))},
+			}),
+		},
+		{
+			name:  "missing tenant",
+			token: suite.sign(jwt.SigningMethodHS256, suite.secret, &middleware.TenantClaims{}),
+		},
+		{
+			name:  "malformed",
+			token: "not-a-jwt",
+		},
+	}
+
+	for _, testCase := range testCases {
+		suite.Run(testCase.name, func() {
+			response, tenantID := suite.serve("Bearer " + testCase.token)
+
+			suite.Equal(http.StatusUnauthorized, response.Code)
+			suite.Empty(tenantID)
+		})
+	}
+}
+
+func (suite *AuthSuite) TestRequireAdmin() {
+	handler := middleware.RequireAdmin(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	for name, testCase := range map[string]struct {
+		role string
+		code int
+	}{
+		"admin":   {role: user.RoleAdmin, code: http.StatusOK},
+		"user":    {role: "user", code: http.StatusForbidden},
+		"no role": {code: http.StatusForbidden},
+	} {
+		suite.Run(name, func() {
+			request := httptest.NewRequest(http.MethodGet, "/private/v1/admin/stats", nil)
+			if testCase.role != "" {
+				request = request.WithContext(user.WithRole(request.Context(), testCase.role))
+			}
+
+			response := httptest.NewRecorder()
+			handler.ServeHTTP(response, request)
+
+			suite.Equal(testCase.code, response.Code)
+		})
+	}
+}
+
+func (suite *AuthSuite) TestTenantFromURLParam() {
+	var tenantID string
+	r := chi.NewRouter()
+	r.With(middleware.TenantFromURLParam("tenantId")).Get("/tenants/{tenantId}/short-urls/{shortURLId}", func(w http.ResponseWriter, r *http.Request) {
+		tenantID = tenant.IDFromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	})
+
+	response := httptest.NewRecorder()
+	r.ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/tenants/acme/short-urls/AABBCC", nil))
+
+	suite.Equal(http.StatusOK, response.Code)
+	suite.Equal("acme", tenantID)
+}
@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+)
+
+// limitedBody is a request body limited by MaxBodySize, it keeps the original body so the limit can be replaced
+type limitedBody struct {
+	io.ReadCloser
+	original io.ReadCloser
+}
+
+// MaxBodySize limits the request body to maxBytes, reads past the limit fail with *http.MaxBytesError. A MaxBodySize
+// further down the chain replaces the limit of an earlier one, so single routes can accept larger bodies.
+func MaxBodySize(maxBytes int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body := r.Body
+			if limited, ok := body.(*limitedBody); ok {
+				body = limited.original
+			}
+			r.Body = &limitedBody{ReadCloser: http.MaxBytesReader(w, body, maxBytes), original: body}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
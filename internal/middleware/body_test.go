@@ -0,0 +1,52 @@
+package middleware_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/AvalosM/short-url-service/internal/middleware"
+)
+
+type MaxBodySizeSuite struct {
+	suite.Suite
+}
+
+func TestMaxBodySizeSuite(t *testing.T) {
+	suite.Run(t, new(MaxBodySizeSuite))
+}
+
+// serve reads a body of the given size through the middlewares, it returns the read error
+func (suite *MaxBodySizeSuite) serve(size int, middlewares ...func(http.Handler) http.Handler) error {
+	var readErr error
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, readErr = io.ReadAll(r.Body)
+	})
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+
+	request := httptest.NewRequest(http.MethodPost, "/private/v1/short-urls", strings.NewReader(strings.Repeat("a", size)))
+	handler.ServeHTTP(httptest.NewRecorder(), request)
+
+	return readErr
+}
+
+func (suite *MaxBodySizeSuite) TestMaxBodySize() {
+	suite.NoError(suite.serve(10, middleware.MaxBodySize(10)))
+
+	var maxBytesErr *http.MaxBytesError
+	suite.True(errors.As(suite.serve(11, middleware.MaxBodySize(10)), &maxBytesErr))
+}
+
+func (suite *MaxBodySizeSuite) TestMaxBodySizeReplacesEarlierLimit() {
+	suite.NoError(suite.serve(100, middleware.MaxBodySize(10), middleware.MaxBodySize(100)))
+
+	var maxBytesErr *http.MaxBytesError
+	suite.True(errors.As(suite.serve(11, middleware.MaxBodySize(100), middleware.MaxBodySize(10)), &maxBytesErr))
+}
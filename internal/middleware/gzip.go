@@ -0,0 +1,162 @@
+package middleware
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"strings"
+)
+
+// Gzip compresses responses of at least minSizeBytes for clients that accept gzip encoding, event streams are not
+// compressed
+func Gzip(minSizeBytes int) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Accept-Encoding")
+
+			if !acceptsGzip(r) {
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			gzipWriter := &gzipResponseWriter{
+				ResponseWriter: w,
+				minSizeBytes:   minSizeBytes,
+				status:         http.StatusOK,
+			}
+			defer gzipWriter.finish()
+
+			next.ServeHTTP(gzipWriter, r)
+		})
+	}
+}
+
+func acceptsGzip(r *http.Request) bool {
+	for _, encoding := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		name, _, _ := strings.Cut(strings.TrimSpace(encoding), ";")
+		if strings.EqualFold(name, "gzip") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// gzipResponseWriter buffers the response until it reaches minSizeBytes, from then on the response is compressed
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	minSizeBytes int
+	status       int
+	buffer       bytes.Buffer
+	gzip         *gzip.Writer
+	passthrough  bool
+}
+
+// WriteHeader holds the status back until the response is known to be compressed or not, event streams are never
+// compressed so their status is written at once
+func (w *gzipResponseWriter) WriteHeader(status int) {
+	w.status = status
+	if w.gzip == nil && !w.passthrough && isEventStream(w.Header()) {
+		_ = w.passThrough()
+	}
+}
+
+func (w *gzipResponseWriter) Write(b []byte) (int, error) {
+	if w.gzip != nil {
+		return w.gzip.Write(b)
+	}
+	if !w.passthrough && isEventStream(w.Header()) {
+		if err := w.passThrough(); err != nil {
+			return 0, err
+		}
+	}
+	if w.passthrough {
+		return w.ResponseWriter.Write(b)
+	}
+
+	n, _ := w.buffer.Write(b)
+	if w.buffer.Len() < w.minSizeBytes {
+		return n, nil
+	}
+
+	header := w.Header()
+	if header.Get("Content-Encoding") != "" {
+		// Already encoded by the handler
+		if err := w.passThrough(); err != nil {
+			return 0, err
+		}
+
+		return n, nil
+	}
+
+	header.Set("Content-Encoding", "gzip")
+	header.Del("Content-Length")
+	w.ResponseWriter.WriteHeader(w.status)
+
+	w.gzip = gzip.NewWriter(w.ResponseWriter)
+	if _, err := w.gzip.Write(w.buffer.Bytes()); err != nil {
+		return 0, err
+	}
+	w.buffer.Reset()
+
+	return n, nil
+}
+
+// finish flushes the compressed stream or, for responses below minSizeBytes, writes the buffered body as is
+func (w *gzipResponseWriter) finish() {
+	if w.gzip != nil {
+		_ = w.gzip.Close()
+
+		return
+	}
+	if w.passthrough {
+		return
+	}
+
+	w.ResponseWriter.WriteHeader(w.status)
+	if w.buffer.Len() > 0 {
+		_, _ = w.ResponseWriter.Write(w.buffer.Bytes())
+	}
+}
+
+// FlushError sends what was written so far to the client, a response below minSizeBytes is sent uncompressed so
+// streamed responses are not held back until they reach it
+func (w *gzipResponseWriter) FlushError() error {
+	switch {
+	case w.gzip != nil:
+		if err := w.gzip.Flush(); err != nil {
+			return err
+		}
+	case !w.passthrough:
+		if err := w.passThrough(); err != nil {
+			return err
+		}
+	}
+
+	return http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Unwrap returns the wrapped response writer, so http.ResponseController reaches it to set write deadlines
+func (w *gzipResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// passThrough writes the status and the buffered body uncompressed, the rest of the response is written as is
+func (w *gzipResponseWriter) passThrough() error {
+	w.passthrough = true
+	w.ResponseWriter.WriteHeader(w.status)
+	if w.buffer.Len() == 0 {
+		return nil
+	}
+
+	_, err := w.ResponseWriter.Write(w.buffer.Bytes())
+	w.buffer.Reset()
+
+	return err
+}
+
+// isEventStream reports whether the response is a stream of Server-Sent Events
+func isEventStream(header http.Header) bool {
+	return strings.HasPrefix(header.Get("Content-Type"), "text/event-stream")
+}
@@ -0,0 +1,154 @@
+package middleware_test
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/AvalosM/short-url-service/internal/middleware"
+)
+
+type GzipSuite struct {
+	suite.Suite
+	body []byte
+}
+
+func (suite *GzipSuite) SetupTest() {
+	metrics := make([]map[string]int64, 0, 500)
+	for i := range 500 {
+		metrics = append(metrics, map[string]int64{"visits": int64(i), "unique_visits": int64(i / 2)})
+	}
+
+	body, err := json.Marshal(metrics)
+	suite.Require().NoError(err)
+
+	suite.body = body
+}
+
+func TestGzipSuite(t *testing.T) {
+	suite.Run(t, new(GzipSuite))
+}
+
+func (suite *GzipSuite) serve(minSizeBytes int, acceptEncoding string) *httptest.ResponseRecorder {
+	handler := middleware.Gzip(minSizeBytes)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(suite.body)
+	}))
+
+	request := httptest.NewRequest(http.MethodGet, "/private/v1/short-urls/AABBCC/metrics", nil)
+	if acceptEncoding != "" {
+		request.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+
+	response := httptest.NewRecorder()
+	handler.ServeHTTP(response, request)
+
+	return response
+}
+
+func (suite *GzipSuite) TestGzipCompressesLargeResponse() {
+	response := suite.serve(1024, "deflate, gzip;q=1.0")
+
+	suite.Equal(http.StatusOK, response.Code)
+	suite.Equal("gzip", response.Header().Get("Content-Encoding"))
+	suite.Equal("Accept-Encoding", response.Header().Get("Vary"))
+	suite.Less(response.Body.Len(), len(suite.body))
+
+	reader, err := gzip.NewReader(response.Body)
+	suite.Require().NoError(err)
+
+	body, err := io.ReadAll(reader)
+	suite.Require().NoError(err)
+	suite.Equal(suite.body, body)
+}
+
+func (suite *GzipSuite) TestGzipSkipsSmallResponse() {
+	response := suite.serve(len(suite.body)+1, "gzip")
+
+	suite.Equal(http.StatusOK, response.Code)
+	suite.Empty(response.Header().Get("Content-Encoding"))
+	suite.Equal(suite.body, response.Body.Bytes())
+}
+
+func (suite *GzipSuite) TestGzipSkipsWithoutAcceptEncoding() {
+	response := suite.serve(1024, "")
+
+	suite.Equal(http.StatusOK, response.Code)
+	suite.Empty(response.Header().Get("Content-Encoding"))
+	suite.Equal("Accept-Encoding", response.Header().Get("Vary"))
+	suite.Equal(suite.body, response.Body.Bytes())
+}
+
+func (suite *GzipSuite) TestGzipSkipsEncodedResponse() {
+	handler := middleware.Gzip(1024)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "br")
+		_, _ = w.Write(suite.body)
+	}))
+
+	request := httptest.NewRequest(http.MethodGet, "/private/metrics", nil)
+	request.Header.Set("Accept-Encoding", "gzip, br")
+
+	response := httptest.NewRecorder()
+	handler.ServeHTTP(response, request)
+
+	suite.Equal("br", response.Header().Get("Content-Encoding"))
+	suite.Equal(suite.body, response.Body.Bytes())
+}
+
+func (suite *GzipSuite) TestGzipFlushSendsSmallResponseUncompressed() {
+	handler := middleware.Gzip(1024)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("event: heartbeat\n\n"))
+		suite.NoError(http.NewResponseController(w).Flush())
+		_, _ = w.Write([]byte("event: heartbeat\n\n"))
+	}))
+
+	request := httptest.NewRequest(http.MethodGet, "/private/v1/short-urls/AABBCC/stream", nil)
+	request.Header.Set("Accept-Encoding", "gzip")
+
+	response := httptest.NewRecorder()
+	handler.ServeHTTP(response, request)
+
+	suite.True(response.Flushed)
+	suite.Empty(response.Header().Get("Content-Encoding"))
+	suite.Equal("event: heartbeat\n\nevent: heartbeat\n\n", response.Body.String())
+}
+
+func (suite *GzipSuite) TestGzipSkipsEventStream() {
+	handler := middleware.Gzip(0)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(suite.body)
+	}))
+
+	request := httptest.NewRequest(http.MethodGet, "/private/v1/short-urls/AABBCC/stream", nil)
+	request.Header.Set("Accept-Encoding", "gzip")
+
+	response := httptest.NewRecorder()
+	handler.ServeHTTP(response, request)
+
+	suite.Empty(response.Header().Get("Content-Encoding"))
+	suite.Equal(suite.body, response.Body.Bytes())
+}
+
+func (suite *GzipSuite) TestGzipUnwrap() {
+	var unwrapped http.ResponseWriter
+	handler := middleware.Gzip(0)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		unwrapped = w.(interface{ Unwrap() http.ResponseWriter }).Unwrap()
+	}))
+
+	request := httptest.NewRequest(http.MethodGet, "/private/v1/short-urls/AABBCC/stream", nil)
+	request.Header.Set("Accept-Encoding", "gzip")
+
+	response := httptest.NewRecorder()
+	handler.ServeHTTP(response, request)
+
+	suite.Same(response, unwrapped)
+}
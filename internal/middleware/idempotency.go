@@ -0,0 +1,159 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/AvalosM/short-url-service/pkg/tenant"
+)
+
+const (
+	IdempotencyKeyHeader = "Idempotency-Key"
+
+	idempotencyNamespace = "idempotency:%s:%s:%s:%s"
+	// idempotencyInFlightNamespace reserves an idempotency key while its first request runs
+	idempotencyInFlightNamespace = "idempotency_in_flight:%s:%s:%s:%s"
+	idempotencyStatusField       = "status"
+	idempotencyBodyField         = "body"
+	idempotencyTypeField         = "content_type"
+	idempotencyRequestHashField  = "request_hash"
+)
+
+// Idempotency replays the stored response for requests carrying an already seen Idempotency-Key header.
+// Only successful responses are stored so that failed requests can be retried. A key reused with another request
+// body is answered with a 422. The key is reserved for at most inFlightTTL while its first request runs, duplicates
+// sent meanwhile are answered with a 409 instead of running again.
+func Idempotency(cache Cache, ttl time.Duration, inFlightTTL time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			idempotencyKey := r.Header.Get(IdempotencyKeyHeader)
+			if idempotencyKey == "" {
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				var maxBytesErr *http.MaxBytesError
+				if errors.As(err, &maxBytesErr) {
+					writeJSONError(w, http.StatusRequestEntityTooLarge, "request body too large")
+
+					return
+				}
+
+				writeJSONError(w, http.StatusBadRequest, "failed to read request body")
+
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			requestHash := sha256.Sum256(body)
+			hexRequestHash := hex.EncodeToString(requestHash[:])
+
+			ctx := r.Context()
+			// Keys are only unique within a tenant, the same key sent by two tenants must not replay each other's response
+			tenantID := tenant.IDFromContext(ctx)
+			key := fmt.Sprintf(idempotencyNamespace, tenantID, r.Method, r.URL.Path, idempotencyKey)
+			inFlightKey := fmt.Sprintf(idempotencyInFlightNamespace, tenantID, r.Method, r.URL.Path, idempotencyKey)
+
+			if replayStoredResponse(w, r, cache, key, hexRequestHash) {
+				return
+			}
+
+			// A cache failure only disables the reservation, like it disables the replay
+			reserved, err := cache.SetNX(ctx, inFlightKey, hexRequestHash, inFlightTTL)
+			if err == nil && !reserved {
+				writeJSONError(w, http.StatusConflict, "a request with this idempotency key is in progress")
+
+				return
+			}
+			if reserved {
+				defer func() { _ = cache.Delete(context.WithoutCancel(ctx), inFlightKey) }()
+
+				// The first request may have been stored between the lookup and the reservation
+				if replayStoredResponse(w, r, cache, key, hexRequestHash) {
+					return
+				}
+			}
+
+			recorder := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(recorder, r)
+
+			if recorder.status < http.StatusOK || recorder.status >= http.StatusMultipleChoices {
+				return
+			}
+
+			// The response was already written, a failure to store it only disables the replay
+			_ = cache.SetHash(ctx, key, map[string]string{
+				idempotencyStatusField:      strconv.Itoa(recorder.status),
+				idempotencyBodyField:        recorder.body.String(),
+				idempotencyTypeField:        recorder.Header().Get("Content-Type"),
+				idempotencyRequestHashField: hexRequestHash,
+			}, ttl)
+		})
+	}
+}
+
+// replayStoredResponse writes the response stored under key if any, or a 422 when it was stored for another request
+// body. It reports whether a response was written.
+func replayStoredResponse(w http.ResponseWriter, r *http.Request, cache Cache, key string, requestHash string) bool {
+	fields, found, err := cache.GetHash(r.Context(), key)
+	if err != nil || !found {
+		return false
+	}
+
+	if fields[idempotencyRequestHashField] != requestHash {
+		writeJSONError(w, http.StatusUnprocessableEntity, "idempotency key already used with another request body")
+
+		return true
+	}
+
+	return replayResponse(w, fields)
+}
+
+func replayResponse(w http.ResponseWriter, fields map[string]string) bool {
+	status, err := strconv.Atoi(fields[idempotencyStatusField])
+	if err != nil {
+		return false
+	}
+
+	if contentType := fields[idempotencyTypeField]; contentType != "" {
+		w.Header().Set("Content-Type", contentType)
+	}
+	w.WriteHeader(status)
+	_, _ = w.Write([]byte(fields[idempotencyBodyField]))
+
+	return true
+}
+
+// responseRecorder captures the status and body written to the underlying http.ResponseWriter
+type responseRecorder struct {
+	http.ResponseWriter
+	status      int
+	body        bytes.Buffer
+	wroteHeader bool
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.wroteHeader = true
+	r.body.Write(b)
+
+	return r.ResponseWriter.Write(b)
+}
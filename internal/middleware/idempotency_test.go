@@ -0,0 +1,234 @@
+package middleware_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+
+	"github.com/AvalosM/short-url-service/internal/middleware"
+	"github.com/AvalosM/short-url-service/internal/middleware/mocks"
+)
+
+//go:generate mockgen -typed -package=mocks  -source=./middleware.go -destination=./mocks/mocks.go
+
+type IdempotencySuite struct {
+	suite.Suite
+	mockCtrl    *gomock.Controller
+	mockCache   *mocks.MockCache
+	ttl         time.Duration
+	inFlightTTL time.Duration
+}
+
+func (suite *IdempotencySuite) SetupTest() {
+	suite.mockCtrl = gomock.NewController(suite.T())
+	suite.mockCache = mocks.NewMockCache(suite.mockCtrl)
+	suite.ttl = time.Hour
+	suite.inFlightTTL = time.Minute
+}
+
+func (suite *IdempotencySuite) TearDownTest() {
+	suite.mockCtrl.Finish()
+}
+
+func TestIdempotencySuite(t *testing.T) {
+	suite.Run(t, new(IdempotencySuite))
+}
+
+// useMemoryCache makes the mock cache keep hashes and values in memory
+func (suite *IdempotencySuite) useMemoryCache() {
+	var mu sync.Mutex
+	hashes := map[string]map[string]string{}
+	values := map[string]string{}
+
+	suite.mockCache.EXPECT().GetHash(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, key string) (map[string]string, bool, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			fields, found := hashes[key]
+
+			return fields, found, nil
+		}).AnyTimes()
+	suite.mockCache.EXPECT().SetHash(gomock.Any(), gomock.Any(), gomock.Any(), suite.ttl).
+		DoAndReturn(func(_ context.Context, key string, fields map[string]string, _ time.Duration) error {
+			mu.Lock()
+			defer mu.Unlock()
+			hashes[key] = fields
+
+			return nil
+		}).AnyTimes()
+	suite.mockCache.EXPECT().SetNX(gomock.Any(), gomock.Any(), gomock.Any(), suite.inFlightTTL).
+		DoAndReturn(func(_ context.Context, key string, value string, _ time.Duration) (bool, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			if _, found := values[key]; found {
+				return false, nil
+			}
+			values[key] = value
+
+			return true, nil
+		}).AnyTimes()
+	suite.mockCache.EXPECT().Delete(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, key string) error {
+			mu.Lock()
+			defer mu.Unlock()
+			delete(values, key)
+
+			return nil
+		}).AnyTimes()
+}
+
+func newIdempotentRequest(body string) *http.Request {
+	request := httptest.NewRequest(http.MethodPost, "/private/v1/short-urls/", strings.NewReader(body))
+	request.Header.Set(middleware.IdempotencyKeyHeader, "some-key")
+
+	return request
+}
+
+func (suite *IdempotencySuite) TestIdempotencySameKeyReplaysResponse() {
+	suite.useMemoryCache()
+
+	writes := 0
+	handler := middleware.Idempotency(suite.mockCache, suite.ttl, suite.inFlightTTL)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writes++
+		body, _ := io.ReadAll(r.Body)
+		suite.Equal(`{"long_url":"https://example.com"}`, string(body))
+
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte("AABBCC"))
+	}))
+
+	responses := make([]*httptest.ResponseRecorder, 2)
+	for i := range responses {
+		responses[i] = httptest.NewRecorder()
+		handler.ServeHTTP(responses[i], newIdempotentRequest(`{"long_url":"https://example.com"}`))
+	}
+
+	suite.Equal(1, writes)
+	suite.Equal(http.StatusCreated, responses[0].Code)
+	suite.Equal(responses[0].Code, responses[1].Code)
+	suite.Equal("AABBCC", responses[0].Body.String())
+	suite.Equal(responses[0].Body.String(), responses[1].Body.String())
+}
+
+func (suite *IdempotencySuite) TestIdempotencyFailOtherBody() {
+	suite.useMemoryCache()
+
+	writes := 0
+	handler := middleware.Idempotency(suite.mockCache, suite.ttl, suite.inFlightTTL)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writes++
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), newIdempotentRequest(`{"long_url":"https://example.com"}`))
+
+	response := httptest.NewRecorder()
+	handler.ServeHTTP(response, newIdempotentRequest(`{"long_url":"https://example.org"}`))
+
+	suite.Equal(1, writes)
+	suite.Equal(http.StatusUnprocessableEntity, response.Code)
+}
+
+func (suite *IdempotencySuite) TestIdempotencyFailInFlight() {
+	suite.useMemoryCache()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var writes atomic.Int32
+	handler := middleware.Idempotency(suite.mockCache, suite.ttl, suite.inFlightTTL)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writes.Add(1)
+		close(started)
+		<-release
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	first := httptest.NewRecorder()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		handler.ServeHTTP(first, newIdempotentRequest(`{"long_url":"https://example.com"}`))
+	}()
+	<-started
+
+	// A duplicate sent while the first request runs does not run again
+	duplicate := httptest.NewRecorder()
+	handler.ServeHTTP(duplicate, newIdempotentRequest(`{"long_url":"https://example.com"}`))
+	suite.Equal(http.StatusConflict, duplicate.Code)
+
+	close(release)
+	<-done
+	suite.Equal(http.StatusCreated, first.Code)
+
+	// Once the first request is done its response is replayed
+	replayed := httptest.NewRecorder()
+	handler.ServeHTTP(replayed, newIdempotentRequest(`{"long_url":"https://example.com"}`))
+	suite.Equal(http.StatusCreated, replayed.Code)
+	suite.Equal(int32(1), writes.Load())
+}
+
+func (suite *IdempotencySuite) TestIdempotencyNoKeyPassesThrough() {
+	writes := 0
+	handler := middleware.Idempotency(suite.mockCache, suite.ttl, suite.inFlightTTL)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writes++
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	for range 2 {
+		request := httptest.NewRequest(http.MethodPost, "/private/v1/short-urls/", nil)
+		handler.ServeHTTP(httptest.NewRecorder(), request)
+	}
+
+	suite.Equal(2, writes)
+}
+
+func (suite *IdempotencySuite) TestIdempotencyErrorResponseNotStored() {
+	suite.useMemoryCache()
+
+	writes := 0
+	handler := middleware.Idempotency(suite.mockCache, suite.ttl, suite.inFlightTTL)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writes++
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+
+	for range 2 {
+		handler.ServeHTTP(httptest.NewRecorder(), newIdempotentRequest(""))
+	}
+
+	suite.Equal(2, writes)
+}
+
+func (suite *IdempotencySuite) TestIdempotencyCacheUnavailable() {
+	suite.mockCache.EXPECT().GetHash(gomock.Any(), gomock.Any()).Return(nil, false, errors.New("cache unavailable"))
+	suite.mockCache.EXPECT().SetNX(gomock.Any(), gomock.Any(), gomock.Any(), suite.inFlightTTL).Return(false, errors.New("cache unavailable"))
+	suite.mockCache.EXPECT().SetHash(gomock.Any(), gomock.Any(), gomock.Any(), suite.ttl).Return(errors.New("cache unavailable"))
+
+	handler := middleware.Idempotency(suite.mockCache, suite.ttl, suite.inFlightTTL)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	response := httptest.NewRecorder()
+	handler.ServeHTTP(response, newIdempotentRequest(`{"long_url":"https://example.com"}`))
+
+	suite.Equal(http.StatusCreated, response.Code)
+}
+
+func (suite *IdempotencySuite) TestIdempotencyFailBodyTooLarge() {
+	handler := middleware.MaxBodySize(8)(middleware.Idempotency(suite.mockCache, suite.ttl, suite.inFlightTTL)(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			suite.Fail("handler called")
+		})))
+
+	response := httptest.NewRecorder()
+	handler.ServeHTTP(response, newIdempotentRequest(`{"long_url":"https://example.com"}`))
+
+	suite.Equal(http.StatusRequestEntityTooLarge, response.Code)
+}
@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"net/http"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+
+	"github.com/AvalosM/short-url-service/pkg/logging"
+)
+
+// LogContext adds the request ID set by chi's RequestID middleware to the request-scoped log attributes, so records
+// logged with logging.FromContext while serving the request carry it
+func LogContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		if requestId := chimiddleware.GetReqID(ctx); requestId != "" {
+			ctx = logging.NewContext(ctx, logging.RequestIdKey, requestId)
+		}
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
@@ -0,0 +1,58 @@
+package middleware_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/AvalosM/short-url-service/internal/middleware"
+	"github.com/AvalosM/short-url-service/pkg/logging"
+	"github.com/AvalosM/short-url-service/pkg/tenant"
+)
+
+type LogContextSuite struct {
+	suite.Suite
+	output *bytes.Buffer
+	logger *slog.Logger
+}
+
+func (suite *LogContextSuite) SetupTest() {
+	suite.output = &bytes.Buffer{}
+	suite.logger = slog.New(slog.NewJSONHandler(suite.output, nil))
+}
+
+func TestLogContextSuite(t *testing.T) {
+	suite.Run(t, new(LogContextSuite))
+}
+
+func (suite *LogContextSuite) TestLogContextRequestId() {
+	handler := chimiddleware.RequestID(middleware.LogContext(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := tenant.WithID(r.Context(), "acme")
+		logging.FromContext(ctx, suite.logger).Error("failed to get short URL", logging.ShortURLIdKey, "AABBCC")
+	})))
+
+	request := httptest.NewRequest(http.MethodGet, "/public/v1/short-urls/AABBCC", nil)
+	request.Header.Set(chimiddleware.RequestIDHeader, "request-1")
+	handler.ServeHTTP(httptest.NewRecorder(), request)
+
+	var record map[string]any
+	suite.Require().NoError(json.Unmarshal(suite.output.Bytes(), &record))
+	suite.Equal("failed to get short URL", record["msg"])
+	suite.Equal("AABBCC", record[logging.ShortURLIdKey])
+	suite.Equal("request-1", record[logging.RequestIdKey])
+	suite.Equal("acme", record[logging.TenantIdKey])
+}
+
+func (suite *LogContextSuite) TestLogContextWithoutRequestId() {
+	handler := middleware.LogContext(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		suite.Same(suite.logger, logging.FromContext(r.Context(), suite.logger))
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/public/v1/short-urls/AABBCC", nil))
+}
@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"context"
+	"time"
+)
+
+// Cache middleware cache
+type Cache interface {
+	GetHash(ctx context.Context, key string) (map[string]string, bool, error)
+	SetHash(ctx context.Context, key string, fields map[string]string, duration time.Duration) error
+	SetNX(ctx context.Context, key string, value string, duration time.Duration) (bool, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// Logger ...
+type Logger interface {
+	Error(msg string, args ...interface{})
+	Info(msg string, args ...interface{})
+	Debug(msg string, args ...interface{})
+	Warn(msg string, args ...interface{})
+}
@@ -0,0 +1,385 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./middleware.go
+//
+// Generated by this command:
+//
+//	mockgen -typed -package=mocks -source=./middleware.go -destination=./mocks/mocks.go
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockCache is a mock of Cache interface.
+type MockCache struct {
+	ctrl     *gomock.Controller
+	recorder *MockCacheMockRecorder
+	isgomock struct{}
+}
+
+// MockCacheMockRecorder is the mock recorder for MockCache.
+type MockCacheMockRecorder struct {
+	mock *MockCache
+}
+
+// NewMockCache creates a new mock instance.
+func NewMockCache(ctrl *gomock.Controller) *MockCache {
+	mock := &MockCache{ctrl: ctrl}
+	mock.recorder = &MockCacheMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCache) EXPECT() *MockCacheMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockCache) Delete(ctx context.Context, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockCacheMockRecorder) Delete(ctx, key any) *MockCacheDeleteCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockCache)(nil).Delete), ctx, key)
+	return &MockCacheDeleteCall{Call: call}
+}
+
+// MockCacheDeleteCall wrap *gomock.Call
+type MockCacheDeleteCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockCacheDeleteCall) Return(arg0 error) *MockCacheDeleteCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockCacheDeleteCall) Do(f func(context.Context, string) error) *MockCacheDeleteCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockCacheDeleteCall) DoAndReturn(f func(context.Context, string) error) *MockCacheDeleteCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// GetHash mocks base method.
+func (m *MockCache) GetHash(ctx context.Context, key string) (map[string]string, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHash", ctx, key)
+	ret0, _ := ret[0].(map[string]string)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetHash indicates an expected call of GetHash.
+func (mr *MockCacheMockRecorder) GetHash(ctx, key any) *MockCacheGetHashCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHash", reflect.TypeOf((*MockCache)(nil).GetHash), ctx, key)
+	return &MockCacheGetHashCall{Call: call}
+}
+
+// MockCacheGetHashCall wrap *gomock.Call
+type MockCacheGetHashCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockCacheGetHashCall) Return(arg0 map[string]string, arg1 bool, arg2 error) *MockCacheGetHashCall {
+	c.Call = c.Call.Return(arg0, arg1, arg2)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockCacheGetHashCall) Do(f func(context.Context, string) (map[string]string, bool, error)) *MockCacheGetHashCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockCacheGetHashCall) DoAndReturn(f func(context.Context, string) (map[string]string, bool, error)) *MockCacheGetHashCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// SetHash mocks base method.
+func (m *MockCache) SetHash(ctx context.Context, key string, fields map[string]string, duration time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetHash", ctx, key, fields, duration)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetHash indicates an expected call of SetHash.
+func (mr *MockCacheMockRecorder) SetHash(ctx, key, fields, duration any) *MockCacheSetHashCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetHash", reflect.TypeOf((*MockCache)(nil).SetHash), ctx, key, fields, duration)
+	return &MockCacheSetHashCall{Call: call}
+}
+
+// MockCacheSetHashCall wrap *gomock.Call
+type MockCacheSetHashCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockCacheSetHashCall) Return(arg0 error) *MockCacheSetHashCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockCacheSetHashCall) Do(f func(context.Context, string, map[string]string, time.Duration) error) *MockCacheSetHashCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockCacheSetHashCall) DoAndReturn(f func(context.Context, string, map[string]string, time.Duration) error) *MockCacheSetHashCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// SetNX mocks base method.
+func (m *MockCache) SetNX(ctx context.Context, key, value string, duration time.Duration) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetNX", ctx, key, value, duration)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetNX indicates an expected call of SetNX.
+func (mr *MockCacheMockRecorder) SetNX(ctx, key, value, duration any) *MockCacheSetNXCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetNX", reflect.TypeOf((*MockCache)(nil).SetNX), ctx, key, value, duration)
+	return &MockCacheSetNXCall{Call: call}
+}
+
+// MockCacheSetNXCall wrap *gomock.Call
+type MockCacheSetNXCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockCacheSetNXCall) Return(arg0 bool, arg1 error) *MockCacheSetNXCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockCacheSetNXCall) Do(f func(context.Context, string, string, time.Duration) (bool, error)) *MockCacheSetNXCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockCacheSetNXCall) DoAndReturn(f func(context.Context, string, string, time.Duration) (bool, error)) *MockCacheSetNXCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// MockLogger is a mock of Logger interface.
+type MockLogger struct {
+	ctrl     *gomock.Controller
+	recorder *MockLoggerMockRecorder
+	isgomock struct{}
+}
+
+// MockLoggerMockRecorder is the mock recorder for MockLogger.
+type MockLoggerMockRecorder struct {
+	mock *MockLogger
+}
+
+// NewMockLogger creates a new mock instance.
+func NewMockLogger(ctrl *gomock.Controller) *MockLogger {
+	mock := &MockLogger{ctrl: ctrl}
+	mock.recorder = &MockLoggerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLogger) EXPECT() *MockLoggerMockRecorder {
+	return m.recorder
+}
+
+// Debug mocks base method.
+func (m *MockLogger) Debug(msg string, args ...any) {
+	m.ctrl.T.Helper()
+	varargs := []any{msg}
+	for _, a := range args {
+		varargs = append(varargs, a)
+	}
+	m.ctrl.Call(m, "Debug", varargs...)
+}
+
+// Debug indicates an expected call of Debug.
+func (mr *MockLoggerMockRecorder) Debug(msg any, args ...any) *MockLoggerDebugCall {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{msg}, args...)
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Debug", reflect.TypeOf((*MockLogger)(nil).Debug), varargs...)
+	return &MockLoggerDebugCall{Call: call}
+}
+
+// MockLoggerDebugCall wrap *gomock.Call
+type MockLoggerDebugCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockLoggerDebugCall) Return() *MockLoggerDebugCall {
+	c.Call = c.Call.Return()
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockLoggerDebugCall) Do(f func(string, ...any)) *MockLoggerDebugCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockLoggerDebugCall) DoAndReturn(f func(string, ...any)) *MockLoggerDebugCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// Error mocks base method.
+func (m *MockLogger) Error(msg string, args ...any) {
+	m.ctrl.T.Helper()
+	varargs := []any{msg}
+	for _, a := range args {
+		varargs = append(varargs, a)
+	}
+	m.ctrl.Call(m, "Error", varargs...)
+}
+
+// Error indicates an expected call of Error.
+func (mr *MockLoggerMockRecorder) Error(msg any, args ...any) *MockLoggerErrorCall {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{msg}, args...)
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Error", reflect.TypeOf((*MockLogger)(nil).Error), varargs...)
+	return &MockLoggerErrorCall{Call: call}
+}
+
+// MockLoggerErrorCall wrap *gomock.Call
+type MockLoggerErrorCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockLoggerErrorCall) Return() *MockLoggerErrorCall {
+	c.Call = c.Call.Return()
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockLoggerErrorCall) Do(f func(string, ...any)) *MockLoggerErrorCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockLoggerErrorCall) DoAndReturn(f func(string, ...any)) *MockLoggerErrorCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// Info mocks base method.
+func (m *MockLogger) Info(msg string, args ...any) {
+	m.ctrl.T.Helper()
+	varargs := []any{msg}
+	for _, a := range args {
+		varargs = append(varargs, a)
+	}
+	m.ctrl.Call(m, "Info", varargs...)
+}
+
+// Info indicates an expected call of Info.
+func (mr *MockLoggerMockRecorder) Info(msg any, args ...any) *MockLoggerInfoCall {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{msg}, args...)
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Info", reflect.TypeOf((*MockLogger)(nil).Info), varargs...)
+	return &MockLoggerInfoCall{Call: call}
+}
+
+// MockLoggerInfoCall wrap *gomock.Call
+type MockLoggerInfoCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockLoggerInfoCall) Return() *MockLoggerInfoCall {
+	c.Call = c.Call.Return()
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockLoggerInfoCall) Do(f func(string, ...any)) *MockLoggerInfoCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockLoggerInfoCall) DoAndReturn(f func(string, ...any)) *MockLoggerInfoCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// Warn mocks base method.
+func (m *MockLogger) Warn(msg string, args ...any) {
+	m.ctrl.T.Helper()
+	varargs := []any{msg}
+	for _, a := range args {
+		varargs = append(varargs, a)
+	}
+	m.ctrl.Call(m, "Warn", varargs...)
+}
+
+// Warn indicates an expected call of Warn.
+func (mr *MockLoggerMockRecorder) Warn(msg any, args ...any) *MockLoggerWarnCall {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{msg}, args...)
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Warn", reflect.TypeOf((*MockLogger)(nil).Warn), varargs...)
+	return &MockLoggerWarnCall{Call: call}
+}
+
+// MockLoggerWarnCall wrap *gomock.Call
+type MockLoggerWarnCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockLoggerWarnCall) Return() *MockLoggerWarnCall {
+	c.Call = c.Call.Return()
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockLoggerWarnCall) Do(f func(string, ...any)) *MockLoggerWarnCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockLoggerWarnCall) DoAndReturn(f func(string, ...any)) *MockLoggerWarnCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"net/http"
+	"runtime/debug"
+
+	"github.com/AvalosM/short-url-service/pkg/logging"
+)
+
+// Recovery recovers from panics in the wrapped handler, logs them and responds with a 500
+func Recovery(logger Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				rec := recover()
+				if rec == nil {
+					return
+				}
+				if rec == http.ErrAbortHandler {
+					// Sentinel used to abort a response, let net/http handle it
+					panic(rec)
+				}
+
+				logger.Error("recovered from panic", logging.PanicKey, rec, logging.StackKey, string(debug.Stack()))
+
+				writeJSONError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+			}()
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
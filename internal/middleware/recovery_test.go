@@ -0,0 +1,64 @@
+package middleware_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+
+	"github.com/AvalosM/short-url-service/internal/middleware"
+	"github.com/AvalosM/short-url-service/internal/middleware/mocks"
+	"github.com/AvalosM/short-url-service/pkg/logging"
+)
+
+type RecoverySuite struct {
+	suite.Suite
+	mockCtrl   *gomock.Controller
+	mockLogger *mocks.MockLogger
+}
+
+func (suite *RecoverySuite) SetupTest() {
+	suite.mockCtrl = gomock.NewController(suite.T())
+	suite.mockLogger = mocks.NewMockLogger(suite.mockCtrl)
+}
+
+func (suite *RecoverySuite) TearDownTest() {
+	suite.mockCtrl.Finish()
+}
+
+func TestRecoverySuite(t *testing.T) {
+	suite.Run(t, new(RecoverySuite))
+}
+
+func (suite *RecoverySuite) TestRecoveryPanic() {
+	suite.mockLogger.EXPECT().Error("recovered from panic", logging.PanicKey, "some panic", logging.StackKey, gomock.Any())
+
+	handler := middleware.Recovery(suite.mockLogger)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("some panic")
+	}))
+
+	response := httptest.NewRecorder()
+	suite.NotPanics(func() {
+		handler.ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+
+	var body middleware.ErrorResponse
+	suite.Require().NoError(json.NewDecoder(response.Body).Decode(&body))
+	suite.Equal(http.StatusInternalServerError, response.Code)
+	suite.Equal("application/json", response.Header().Get("Content-Type"))
+	suite.Equal(http.StatusText(http.StatusInternalServerError), body.Error)
+}
+
+func (suite *RecoverySuite) TestRecoveryNoPanic() {
+	handler := middleware.Recovery(suite.mockLogger)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	response := httptest.NewRecorder()
+	handler.ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	suite.Equal(http.StatusOK, response.Code)
+}
@@ -0,0 +1,26 @@
+package middleware
+
+import "net/http"
+
+const (
+	strictTransportSecurity = "max-age=63072000; includeSubDomains"
+	referrerPolicy          = "strict-origin-when-cross-origin"
+)
+
+// SecurityHeaders sets security related headers on every response.
+// HSTS is only set when hstsEnabled is true, it must not be sent over plain HTTP.
+func SecurityHeaders(hstsEnabled bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header := w.Header()
+			if hstsEnabled {
+				header.Set("Strict-Transport-Security", strictTransportSecurity)
+			}
+			header.Set("X-Content-Type-Options", "nosniff")
+			header.Set("X-Frame-Options", "DENY")
+			header.Set("Referrer-Policy", referrerPolicy)
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
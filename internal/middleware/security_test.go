@@ -0,0 +1,49 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/AvalosM/short-url-service/internal/middleware"
+)
+
+type SecurityHeadersSuite struct {
+	suite.Suite
+}
+
+func TestSecurityHeadersSuite(t *testing.T) {
+	suite.Run(t, new(SecurityHeadersSuite))
+}
+
+func (suite *SecurityHeadersSuite) serve(hstsEnabled bool) *httptest.ResponseRecorder {
+	handler := middleware.SecurityHeaders(hstsEnabled)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "https://example.com", http.StatusFound)
+	}))
+
+	response := httptest.NewRecorder()
+	handler.ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/public/v1/short-urls/AABBCC", nil))
+
+	return response
+}
+
+func (suite *SecurityHeadersSuite) TestSecurityHeadersTLSEnabled() {
+	response := suite.serve(true)
+
+	suite.Equal(http.StatusFound, response.Code)
+	suite.Equal("max-age=63072000; includeSubDomains", response.Header().Get("Strict-Transport-Security"))
+	suite.Equal("nosniff", response.Header().Get("X-Content-Type-Options"))
+	suite.Equal("DENY", response.Header().Get("X-Frame-Options"))
+	suite.Equal("strict-origin-when-cross-origin", response.Header().Get("Referrer-Policy"))
+}
+
+func (suite *SecurityHeadersSuite) TestSecurityHeadersTLSDisabled() {
+	response := suite.serve(false)
+
+	suite.Empty(response.Header().Get("Strict-Transport-Security"))
+	suite.Equal("nosniff", response.Header().Get("X-Content-Type-Options"))
+	suite.Equal("DENY", response.Header().Get("X-Frame-Options"))
+	suite.Equal("strict-origin-when-cross-origin", response.Header().Get("Referrer-Policy"))
+}
@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// unmatchedRoute labels the sizes of the requests not matching any route
+const unmatchedRoute = "unmatched"
+
+// BodySizeBuckets are the upper bounds in bytes of the buckets of the request and response body size histograms,
+// from 64B to 1MiB
+var BodySizeBuckets = prometheus.ExponentialBuckets(64, 4, 8)
+
+// countingBody is a request body counting the bytes read from it
+type countingBody struct {
+	io.ReadCloser
+	bytes int
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.bytes += n
+
+	return n, err
+}
+
+// RequestSizeTracker records the bytes of the request bodies read by the handlers in the request_body_bytes
+// histogram of reg, and the bytes of the response bodies written in the response_body_bytes histogram. Both are
+// labeled with the route pattern of the request. It must go before MaxBodySize so limits set on single routes
+// replace the limit of the router.
+func RequestSizeTracker(reg prometheus.Registerer) func(http.Handler) http.Handler {
+	requestBytes := bodySizeHistogram(reg, "request_body_bytes", "Size of the HTTP request bodies read in bytes by handler")
+	responseBytes := bodySizeHistogram(reg, "response_body_bytes", "Size of the HTTP response bodies written in bytes by handler")
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body := &countingBody{ReadCloser: r.Body}
+			r.Body = body
+			recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+			next.ServeHTTP(recorder, r)
+
+			// The route pattern is only complete once every router down the chain has matched the request
+			handler := unmatchedRoute
+			if routeContext := chi.RouteContext(r.Context()); routeContext != nil && routeContext.RoutePattern() != "" {
+				handler = routeContext.RoutePattern()
+			}
+
+			requestBytes.WithLabelValues(handler).Observe(float64(body.bytes))
+			responseBytes.WithLabelValues(handler).Observe(float64(recorder.bytes))
+		})
+	}
+}
+
+// bodySizeHistogram registers a body size histogram with reg, or returns the one already registered, so the
+// tracker can be used on several routers
+func bodySizeHistogram(reg prometheus.Registerer, name string, help string) *prometheus.HistogramVec {
+	histogram := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    name,
+		Help:    help,
+		Buckets: BodySizeBuckets,
+	}, []string{"handler"})
+
+	if err := reg.Register(histogram); err != nil {
+		var alreadyRegistered prometheus.AlreadyRegisteredError
+		if errors.As(err, &alreadyRegistered) {
+			return alreadyRegistered.ExistingCollector.(*prometheus.HistogramVec)
+		}
+
+		panic(err)
+	}
+
+	return histogram
+}
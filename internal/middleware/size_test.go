@@ -0,0 +1,86 @@
+package middleware_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/AvalosM/short-url-service/internal/middleware"
+)
+
+type RequestSizeTrackerSuite struct {
+	suite.Suite
+	registry *prometheus.Registry
+	router   chi.Router
+}
+
+func (suite *RequestSizeTrackerSuite) SetupTest() {
+	suite.registry = prometheus.NewRegistry()
+	suite.router = chi.NewRouter()
+	suite.router.Use(middleware.RequestSizeTracker(suite.registry))
+	suite.router.Post("/short-urls/{shortURLId}", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		_, _ = io.WriteString(w, "created")
+	})
+}
+
+func TestRequestSizeTrackerSuite(t *testing.T) {
+	suite.Run(t, new(RequestSizeTrackerSuite))
+}
+
+// histogram returns the histogram of the family with the given name gathered from the registry
+func (suite *RequestSizeTrackerSuite) histogram(name string) *dto.Metric {
+	families, err := suite.registry.Gather()
+	suite.Require().NoError(err)
+
+	for _, family := range families {
+		if family.GetName() == name {
+			suite.Require().Len(family.GetMetric(), 1)
+
+			return family.GetMetric()[0]
+		}
+	}
+	suite.FailNow("histogram not found", name)
+
+	return nil
+}
+
+func (suite *RequestSizeTrackerSuite) TestRequestSizeTracker() {
+	request := httptest.NewRequest(http.MethodPost, "/short-urls/AABBCC", strings.NewReader(strings.Repeat("a", 100)))
+	suite.router.ServeHTTP(httptest.NewRecorder(), request)
+
+	requestBytes := suite.histogram("request_body_bytes")
+	suite.Equal("handler", requestBytes.GetLabel()[0].GetName())
+	suite.Equal("/short-urls/{shortURLId}", requestBytes.GetLabel()[0].GetValue())
+	suite.Equal(uint64(1), requestBytes.GetHistogram().GetSampleCount())
+	suite.InDelta(100, requestBytes.GetHistogram().GetSampleSum(), 0)
+	// 100 bytes fall in the second bucket, up to 256 bytes
+	suite.Equal(uint64(0), requestBytes.GetHistogram().GetBucket()[0].GetCumulativeCount())
+	suite.Equal(uint64(1), requestBytes.GetHistogram().GetBucket()[1].GetCumulativeCount())
+
+	responseBytes := suite.histogram("response_body_bytes")
+	suite.InDelta(len("created"), responseBytes.GetHistogram().GetSampleSum(), 0)
+}
+
+func (suite *RequestSizeTrackerSuite) TestRequestSizeTrackerUnmatchedRoute() {
+	request := httptest.NewRequest(http.MethodPost, "/unknown", strings.NewReader("body"))
+	suite.router.ServeHTTP(httptest.NewRecorder(), request)
+
+	requestBytes := suite.histogram("request_body_bytes")
+	suite.Equal("unmatched", requestBytes.GetLabel()[0].GetValue())
+	// The body of unmatched requests is never read
+	suite.InDelta(0, requestBytes.GetHistogram().GetSampleSum(), 0)
+}
+
+func (suite *RequestSizeTrackerSuite) TestRequestSizeTrackerRegisteredTwice() {
+	suite.NotPanics(func() {
+		middleware.RequestSizeTracker(suite.registry)
+	})
+}
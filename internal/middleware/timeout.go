@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+const TimeoutMessage = "request timed out"
+
+// Timeout bounds the handler execution to d, the request context gets the same deadline.
+// Handlers that exceed it get a 503 response with TimeoutMessage as body.
+func Timeout(d time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		withDeadline := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), d)
+			defer cancel()
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+
+		return http.TimeoutHandler(withDeadline, d, TimeoutMessage)
+	}
+}
@@ -0,0 +1,53 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/AvalosM/short-url-service/internal/middleware"
+)
+
+type TimeoutSuite struct {
+	suite.Suite
+}
+
+func TestTimeoutSuite(t *testing.T) {
+	suite.Run(t, new(TimeoutSuite))
+}
+
+func (suite *TimeoutSuite) TestTimeoutExceeded() {
+	handler := middleware.Timeout(10 * time.Millisecond)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	response := httptest.NewRecorder()
+	handler.ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	suite.Equal(http.StatusServiceUnavailable, response.Code)
+	suite.Equal(middleware.TimeoutMessage, response.Body.String())
+}
+
+func (suite *TimeoutSuite) TestTimeoutNotExceeded() {
+	handler := middleware.Timeout(time.Second)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, hasDeadline := r.Context().Deadline()
+		suite.True(hasDeadline)
+
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte("AABBCC"))
+	}))
+
+	response := httptest.NewRecorder()
+	handler.ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	suite.Equal(http.StatusCreated, response.Code)
+	suite.Equal("AABBCC", response.Body.String())
+}
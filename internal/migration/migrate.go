@@ -0,0 +1,67 @@
+package migration
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/golang-migrate/migrate/v4"
+	pgx "github.com/golang-migrate/migrate/v4/database/pgx/v5"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+)
+
+// Migrator applies the SQL migrations found in a directory to a database
+type Migrator struct {
+	migrate *migrate.Migrate
+}
+
+// NewMigrator creates a new Migrator, it opens its own connection to the database
+func NewMigrator(driver string, dataSourceName string, migrationsPath string) (*Migrator, error) {
+	db, err := sql.Open(driver, dataSourceName)
+	if err != nil {
+		return nil, fmt.Errorf("opening migrations database connection: %w", err)
+	}
+
+	databaseDriver, err := pgx.WithInstance(db, &pgx.Config{})
+	if err != nil {
+		_ = db.Close()
+
+		return nil, fmt.Errorf("creating migrations database driver: %w", err)
+	}
+
+	m, err := migrate.NewWithDatabaseInstance("file://"+migrationsPath, "pgx5", databaseDriver)
+	if err != nil {
+		_ = databaseDriver.Close()
+
+		return nil, fmt.Errorf("creating migrator: %w", err)
+	}
+
+	return &Migrator{
+		migrate: m,
+	}, nil
+}
+
+// Up applies all pending migrations
+func (m *Migrator) Up() error {
+	if err := m.migrate.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("applying migrations: %w", err)
+	}
+
+	return nil
+}
+
+// Down rolls back all applied migrations
+func (m *Migrator) Down() error {
+	if err := m.migrate.Down(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("rolling back migrations: %w", err)
+	}
+
+	return nil
+}
+
+// Close closes the migrator database connection
+func (m *Migrator) Close() error {
+	sourceErr, databaseErr := m.migrate.Close()
+
+	return errors.Join(sourceErr, databaseErr)
+}
@@ -1,18 +1,120 @@
 package router
 
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/AvalosM/short-url-service/internal/middleware"
+)
+
+const minAuthSecretLength = 32
+
 // Config holds the configuration for the router
 type Config struct {
-	SwaggerEnabled bool `json:"swagger_enabled"`
+	SwaggerEnabled             bool  `json:"swagger_enabled"`
+	IdempotencyKeyTTLInSeconds int   `json:"idempotency_key_ttl_in_seconds"`
+	RedirectTimeoutInMS        int   `json:"redirect_timeout_in_ms"`
+	CreateTimeoutInMS          int   `json:"create_timeout_in_ms"`
+	MetricsTimeoutInMS         int   `json:"metrics_timeout_in_ms"`
+	MaxRequestBodyBytes        int64 `json:"max_request_body_bytes"`
+	GzipMinSizeBytes           int   `json:"gzip_min_size_bytes"`
+
+	// MaxImportFileSizeBytes limits the body of CSV imports instead of MaxRequestBodyBytes
+	MaxImportFileSizeBytes int64 `json:"max_import_file_size_bytes"`
+
+	// PProfEnabled mounts the net/http/pprof handlers at /private/v1/admin/debug/pprof/, behind the authentication of
+	// the admin routes and restricted to admins. It requires AuthEnabled.
+	PProfEnabled bool `json:"pprof_enabled"`
+
+	// AuthEnabled requires private requests to carry a JWT signed with AuthSecret, they are scoped to
+	// the tenant of its tenant_id claim. Without it every private request belongs to the default tenant and the admin
+	// routes, which require the admin role claim, are forbidden.
+	AuthEnabled bool   `json:"auth_enabled"`
+	AuthSecret  string `json:"auth_secret"`
+
+	// AccessLog logs every request of both routers, requests are not logged when it is nil
+	AccessLog *AccessLogConfig `json:"access_log"`
+
+	// FaviconPath is the file served at /favicon.ico, which answers 204 No Content when it is empty
+	FaviconPath string `json:"favicon_path"`
+	// RobotsTxt is the body served at /robots.txt
+	RobotsTxt string `json:"robots_txt"`
+
+	// HSTSEnabled is derived from the HTTP server TLS configuration
+	HSTSEnabled bool `json:"-"`
+
+	// PublicMiddlewares are applied to every request of the public router after its own middlewares
+	PublicMiddlewares []func(http.Handler) http.Handler `json:"-"`
+	// PrivateMiddlewares are applied to every request of the private router after its own middlewares
+	PrivateMiddlewares []func(http.Handler) http.Handler `json:"-"`
+}
+
+// AccessLogConfig holds the configuration of the access log
+type AccessLogConfig struct {
+	// Format is middleware.AccessLogFormatJSON or middleware.AccessLogFormatCombined
+	Format string `json:"format"`
+}
+
+// Validate checks if the access log configuration is valid
+func (c *AccessLogConfig) Validate() error {
+	switch c.Format {
+	case middleware.AccessLogFormatJSON, middleware.AccessLogFormatCombined:
+		return nil
+	default:
+		return fmt.Errorf("access log format must be %q or %q", middleware.AccessLogFormatJSON, middleware.AccessLogFormatCombined)
+	}
 }
 
 // DefaultConfig returns the default configuration for the router
 func DefaultConfig() *Config {
 	return &Config{
-		SwaggerEnabled: true, // Default to true for Swagger UI
+		SwaggerEnabled:             true,         // Default to true for Swagger UI
+		IdempotencyKeyTTLInSeconds: 24 * 60 * 60, // 24 hours
+		RedirectTimeoutInMS:        500,
+		CreateTimeoutInMS:          2000,
+		MetricsTimeoutInMS:         5000,
+		MaxRequestBodyBytes:        4096,
+		MaxImportFileSizeBytes:     1 << 20, // 1 MB
+		GzipMinSizeBytes:           1024,
+		RobotsTxt:                  "User-agent: *\nDisallow: /private/\n",
 	}
 }
 
 // Validate checks if the configuration is valid
 func (c *Config) Validate() error {
+	if c.IdempotencyKeyTTLInSeconds <= 0 {
+		return errors.New("idempotency key ttl must be greater than 0")
+	}
+	if c.RedirectTimeoutInMS <= 0 {
+		return errors.New("redirect timeout must be greater than 0")
+	}
+	if c.CreateTimeoutInMS <= 0 {
+		return errors.New("create timeout must be greater than 0")
+	}
+	if c.MetricsTimeoutInMS <= 0 {
+		return errors.New("metrics timeout must be greater than 0")
+	}
+	if c.MaxRequestBodyBytes <= 0 {
+		return errors.New("max request body bytes must be greater than 0")
+	}
+	if c.MaxImportFileSizeBytes <= 0 {
+		return errors.New("max import file size bytes must be greater than 0")
+	}
+	if c.GzipMinSizeBytes < 0 {
+		return errors.New("gzip min size bytes cannot be negative")
+	}
+	if c.AuthEnabled && len(c.AuthSecret) < minAuthSecretLength {
+		return fmt.Errorf("auth secret must be at least %d characters long", minAuthSecretLength)
+	}
+	if c.PProfEnabled && !c.AuthEnabled {
+		return errors.New("pprof requires auth to be enabled")
+	}
+	if c.AccessLog != nil {
+		if err := c.AccessLog.Validate(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
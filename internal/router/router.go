@@ -1,54 +1,197 @@
 package router
 
 import (
+	"io"
 	"net/http"
+	"time"
 
 	"github.com/go-chi/chi/v5"
-	"github.com/go-chi/chi/v5/middleware"
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	httpSwagger "github.com/swaggo/http-swagger"
 
 	"github.com/AvalosM/short-url-service/internal/handlers"
+	"github.com/AvalosM/short-url-service/internal/middleware"
+	"github.com/AvalosM/short-url-service/pkg/telemetry"
 )
 
-func NewRouter(config *Config, shortURLHandler *handlers.ShortURLHandler) http.Handler {
+func NewRouter(config *Config, shortURLHandler *handlers.ShortURLHandler, adminHandler *handlers.AdminHandler, cache middleware.Cache,
+	logger middleware.Logger) http.Handler {
 	r := chi.NewRouter()
+	// Outermost so panics of the middlewares of both routers and of the routes outside them are recovered too
+	r.Use(middleware.Recovery(logger))
 
 	// Mount the routers
-	r.Mount("/public", createPublicRouter(shortURLHandler))
-	r.Mount("/private", createPrivateRouter(shortURLHandler))
+	r.Mount("/public", createPublicRouter(config, shortURLHandler, logger))
+	r.Mount("/private", createPrivateRouter(config, shortURLHandler, adminHandler, cache, logger))
 
 	if config.SwaggerEnabled {
 		r.Get("/swagger/*", httpSwagger.Handler(httpSwagger.InstanceName("swagger")))
 	}
 
+	// Browsers and crawlers ask for these on their own, answering them keeps them out of the 404s
+	r.Get("/favicon.ico", favicon(config.FaviconPath))
+	r.Get("/robots.txt", robotsTxt(config.RobotsTxt))
+
 	return r
 }
 
-func createPublicRouter(shortURLHandler *handlers.ShortURLHandler) chi.Router {
+// favicon serves the file at path, or no content when path is empty
+func favicon(path string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if path == "" {
+			w.WriteHeader(http.StatusNoContent)
+
+			return
+		}
+
+		http.ServeFile(w, r, path)
+	}
+}
+
+// robotsTxt serves body as the robots.txt of the service
+func robotsTxt(body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		_, _ = io.WriteString(w, body)
+	}
+}
+
+func createPublicRouter(config *Config, shortURLHandler *handlers.ShortURLHandler, logger middleware.Logger) chi.Router {
 	r := chi.NewRouter()
 	// TODO: set public middlewares (CORS, Rate Limiting, etc.)
-	r.Use(middleware.RealIP)
+	useRequestId(r)
+	useAccessLog(r, config, logger)
+	r.Use(middleware.Recovery(logger))
+	r.Use(middleware.SecurityHeaders(config.HSTSEnabled))
+	r.Use(middleware.RequestSizeTracker(prometheus.DefaultRegisterer))
+	r.Use(middleware.MaxBodySize(config.MaxRequestBodyBytes))
+	r.Use(chimiddleware.RealIP)
+	r.Use(config.PublicMiddlewares...)
+
+	redirectTimeout := time.Duration(config.RedirectTimeoutInMS) * time.Millisecond
+
+	publicRoutes := func(r chi.Router) {
+		r.With(middleware.Timeout(redirectTimeout)).Get("/{shortURLId}", instrumented("redirect_to_long_url", shortURLHandler.RedirectToLongURL))
+		r.With(middleware.Timeout(redirectTimeout)).Head("/{shortURLId}", instrumented("check_short_url", shortURLHandler.CheckShortURL))
+		r.With(middleware.Timeout(redirectTimeout)).Get("/{shortURLId}/preview", instrumented("preview_short_url", shortURLHandler.PreviewShortURL))
+		r.With(middleware.Timeout(redirectTimeout)).Post("/{shortURLId}/unlock", instrumented("unlock_short_url", shortURLHandler.UnlockShortURL))
+	}
 
 	r.Route("/v1", func(r chi.Router) {
-		r.Route("/short-urls", func(r chi.Router) {
-			r.Get("/{shortURLId}", shortURLHandler.RedirectToLongURL)
-		})
+		r.Route("/short-urls", publicRoutes)
+		r.With(middleware.TenantFromURLParam("tenantId")).Route("/tenants/{tenantId}/short-urls", publicRoutes)
 	})
 
 	return r
 }
 
-func createPrivateRouter(shortURLHandler *handlers.ShortURLHandler) chi.Router {
+func createPrivateRouter(config *Config, shortURLHandler *handlers.ShortURLHandler, adminHandler *handlers.AdminHandler, cache middleware.Cache,
+	logger middleware.Logger) chi.Router {
 	r := chi.NewRouter()
-	// TODO: set private middlewares (Auth)
+	useRequestId(r)
+	useAccessLog(r, config, logger)
+	r.Use(middleware.Recovery(logger))
+	r.Use(middleware.SecurityHeaders(config.HSTSEnabled))
+	r.Use(middleware.RequestSizeTracker(prometheus.DefaultRegisterer))
+	r.Use(middleware.MaxBodySize(config.MaxRequestBodyBytes))
+	r.Use(middleware.Gzip(config.GzipMinSizeBytes))
+	r.Use(config.PrivateMiddlewares...)
+
+	r.Handle("/metrics", promhttp.Handler())
+
+	idempotencyKeyTTL := time.Duration(config.IdempotencyKeyTTLInSeconds) * time.Second
+	createTimeout := time.Duration(config.CreateTimeoutInMS) * time.Millisecond
+	metricsTimeout := time.Duration(config.MetricsTimeoutInMS) * time.Millisecond
 
 	r.Route("/v1", func(r chi.Router) {
+		if config.AuthEnabled {
+			r.Use(middleware.Auth([]byte(config.AuthSecret)))
+		}
+
 		r.Route("/short-urls", func(r chi.Router) {
-			r.Post("/", shortURLHandler.CreateShortURL)
-			r.Delete("/{shortURLId}", shortURLHandler.DeleteShortURL)
-			r.Get("/{shortURLId}/metrics", shortURLHandler.GetShortURLMetrics)
+			// A create runs for at most the create timeout, its idempotency key is reserved as long
+			r.With(middleware.Timeout(createTimeout), middleware.Idempotency(cache, idempotencyKeyTTL, createTimeout)).Post("/", instrumented("create_short_url", shortURLHandler.CreateShortURL))
+			// Getting or creating is idempotent by itself, it needs no idempotency key
+			r.With(middleware.Timeout(createTimeout)).Put("/", instrumented("get_or_create_short_url", shortURLHandler.GetOrCreateShortURL))
+			// Imports create a short URL per row, they are not bound by the create timeout
+			r.With(middleware.MaxBodySize(config.MaxImportFileSizeBytes)).Post("/import", instrumented("import_short_urls", shortURLHandler.ImportShortURLs))
+			r.With(middleware.Timeout(metricsTimeout)).Get("/", instrumented("list_short_urls", shortURLHandler.ListShortURLs))
+			r.With(middleware.Timeout(metricsTimeout)).Get("/resolve", instrumented("resolve_short_urls", shortURLHandler.ResolveShortURLs))
+			// Exports are streamed, the timeout middleware would buffer the whole response
+			r.Get("/export", instrumented("export_short_urls", shortURLHandler.ExportShortURLs))
+			r.With(middleware.Timeout(metricsTimeout)).Get("/{shortURLId}", instrumented("get_short_url", shortURLHandler.GetShortURL))
+			r.Delete("/{shortURLId}", instrumented("delete_short_url", shortURLHandler.DeleteShortURL))
+			r.Post("/{shortURLId}/pause", instrumented("pause_short_url", shortURLHandler.PauseShortURL))
+			r.Post("/{shortURLId}/resume", instrumented("resume_short_url", shortURLHandler.ResumeShortURL))
+			r.Post("/{shortURLId}/archive", instrumented("archive_short_url", shortURLHandler.ArchiveShortURL))
+			r.With(middleware.Timeout(metricsTimeout)).Get("/{shortURLId}/metrics", instrumented("get_short_url_metrics", shortURLHandler.GetShortURLMetrics))
+			r.With(middleware.Timeout(metricsTimeout)).Get("/{shortURLId}/latency", instrumented("get_short_url_latency", shortURLHandler.GetShortURLLatency))
+			// Streamed like the short URLs export
+			r.Get("/{shortURLId}/metrics/export", instrumented("export_short_url_metrics", shortURLHandler.ExportShortURLMetrics))
+			r.Get("/{shortURLId}/stream", instrumented("stream_short_url_clicks", shortURLHandler.StreamShortURLClicks))
+			r.With(middleware.Timeout(metricsTimeout)).Get("/{shortURLId}/audit", instrumented("get_short_url_audit_log", shortURLHandler.GetShortURLAuditLog))
+			r.Post("/{shortURLId}/webhooks", instrumented("register_webhook", shortURLHandler.RegisterWebhook))
+			r.Delete("/{shortURLId}/webhooks/{webhookId}", instrumented("delete_webhook", shortURLHandler.DeleteWebhook))
+			r.Post("/{shortURLId}/aliases", instrumented("create_alias", shortURLHandler.CreateAlias))
+			r.Delete("/{shortURLId}/aliases/{aliasId}", instrumented("delete_alias", shortURLHandler.DeleteAlias))
+		})
+
+		r.Route("/users/{userId}/short-urls", func(r chi.Router) {
+			r.With(middleware.Timeout(metricsTimeout)).Get("/", instrumented("list_user_short_urls", shortURLHandler.ListUserShortURLs))
+			r.Delete("/", instrumented("delete_user_short_urls", shortURLHandler.DeleteUserShortURLs))
+		})
+
+		r.Route("/metrics", func(r chi.Router) {
+			r.With(middleware.Timeout(metricsTimeout)).Get("/snapshot", instrumented("get_metrics_snapshot", shortURLHandler.GetMetricsSnapshot))
+			r.With(middleware.Timeout(metricsTimeout)).Post("/drain", instrumented("drain_metrics", shortURLHandler.DrainMetrics))
+			r.With(middleware.Timeout(metricsTimeout)).Post("/bulk", instrumented("get_metrics_bulk", shortURLHandler.GetMetricsBulk))
+		})
+
+		r.With(middleware.Timeout(metricsTimeout)).Get("/analytics/dashboard", instrumented("get_dashboard", shortURLHandler.GetDashboard))
+
+		// The admin routes operate the whole process rather than a tenant, they are restricted to admins
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.RequireAdmin)
+
+			r.Post("/admin/config/reload", instrumented("reload_config", adminHandler.ReloadConfig))
+			r.Get("/admin/stats", instrumented("get_stats", adminHandler.GetStats))
+			r.Get("/admin/shorturl/stats", instrumented("get_shorturl_stats", adminHandler.GetStats))
+			r.Post("/admin/stats/reset", instrumented("reset_stats", adminHandler.ResetStats))
+			r.Post("/admin/cache/warm", instrumented("warm_cache", adminHandler.WarmCache))
+			r.Post("/admin/cache/warm/all", instrumented("warm_cache_most_visited", adminHandler.WarmCacheMostVisited))
+
+			// pprof exposes the internals of the whole process, it is only mounted with the admin routes and never on
+			// the public router
+			if config.PProfEnabled {
+				r.Mount("/admin/debug", chimiddleware.Profiler())
+			}
 		})
 	})
 
 	return r
 }
+
+// useRequestId sets the request ID of the requests of r, records logged while serving them carry it
+func useRequestId(r chi.Router) {
+	r.Use(chimiddleware.RequestID)
+	r.Use(middleware.LogContext)
+}
+
+// useAccessLog logs the requests of r when the access log is configured. It goes before the Recovery of the router so
+// requests whose handlers panic are logged with the 500 it writes, the Recovery of NewRouter recovers the panics of
+// the middlewares before it.
+func useAccessLog(r chi.Router, config *Config, logger middleware.Logger) {
+	if config.AccessLog == nil {
+		return
+	}
+
+	r.Use(middleware.AccessLog(logger, config.AccessLog.Format))
+}
+
+// instrumented records the duration of the requests served by h under the given handler name, they are exposed on
+// the metrics endpoint of the private router
+func instrumented(name string, h http.HandlerFunc) http.HandlerFunc {
+	return telemetry.InstrumentedHandler(name, h, prometheus.DefaultRegisterer)
+}
@@ -0,0 +1,335 @@
+package router_test
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+
+	"github.com/AvalosM/short-url-service/internal/handlers"
+	handlermocks "github.com/AvalosM/short-url-service/internal/handlers/mocks"
+	"github.com/AvalosM/short-url-service/internal/middleware"
+	middlewaremocks "github.com/AvalosM/short-url-service/internal/middleware/mocks"
+	"github.com/AvalosM/short-url-service/internal/router"
+	"github.com/AvalosM/short-url-service/pkg/shorturl"
+	"github.com/AvalosM/short-url-service/pkg/user"
+)
+
+type RouterSuite struct {
+	suite.Suite
+	mockCtrl           *gomock.Controller
+	shortURLHandler    *handlers.ShortURLHandler
+	adminHandler       *handlers.AdminHandler
+	mockConfigReloader *handlermocks.MockConfigReloader
+	mockShortURLStats  *handlermocks.MockShortURLStats
+	mockCacheWarmer    *handlermocks.MockCacheWarmer
+	mockCache          *middlewaremocks.MockCache
+	mockLogger         *middlewaremocks.MockLogger
+}
+
+func (suite *RouterSuite) SetupTest() {
+	suite.mockCtrl = gomock.NewController(suite.T())
+	suite.mockCache = middlewaremocks.NewMockCache(suite.mockCtrl)
+	suite.mockLogger = middlewaremocks.NewMockLogger(suite.mockCtrl)
+
+	shortURLHandler, err := handlers.NewShortURLHandler(handlers.DefaultConfig(),
+		handlermocks.NewMockShortURLManager(suite.mockCtrl),
+		handlermocks.NewMockMetricsManager(suite.mockCtrl),
+		handlermocks.NewMockWebhookManager(suite.mockCtrl),
+		handlermocks.NewMockTokenSigner(suite.mockCtrl),
+		handlermocks.NewMockLogger(suite.mockCtrl),
+	)
+	suite.Require().NoError(err)
+
+	suite.shortURLHandler = shortURLHandler
+
+	suite.mockConfigReloader = handlermocks.NewMockConfigReloader(suite.mockCtrl)
+	suite.mockShortURLStats = handlermocks.NewMockShortURLStats(suite.mockCtrl)
+	suite.mockCacheWarmer = handlermocks.NewMockCacheWarmer(suite.mockCtrl)
+	adminHandler, err := handlers.NewAdminHandler(suite.mockConfigReloader, suite.mockShortURLStats, suite.mockCacheWarmer,
+		handlermocks.NewMockLogger(suite.mockCtrl))
+	suite.Require().NoError(err)
+
+	suite.adminHandler = adminHandler
+}
+
+func (suite *RouterSuite) TearDownTest() {
+	suite.mockCtrl.Finish()
+}
+
+func TestRouterSuite(t *testing.T) {
+	suite.Run(t, new(RouterSuite))
+}
+
+func (suite *RouterSuite) serve(config *router.Config, path string) *httptest.ResponseRecorder {
+	r := router.NewRouter(config, suite.shortURLHandler, suite.adminHandler, suite.mockCache, suite.mockLogger)
+
+	response := httptest.NewRecorder()
+	r.ServeHTTP(response, httptest.NewRequest(http.MethodGet, path, nil))
+
+	return response
+}
+
+// serveAs serves a request of path authenticated with a token of a user of the acme tenant with the given role
+func (suite *RouterSuite) serveAs(config *router.Config, method, path string, role string) *httptest.ResponseRecorder {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, &middleware.TenantClaims{
+		TenantId:         "acme",
+		Role:             role,
+		RegisteredClaims: jwt.RegisteredClaims{Subject: "user-1", ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour))},
+	}).SignedString([]byte(config.AuthSecret))
+	suite.Require().NoError(err)
+
+	r := router.NewRouter(config, suite.shortURLHandler, suite.adminHandler, suite.mockCache, suite.mockLogger)
+
+	request := httptest.NewRequest(method, path, nil)
+	request.Header.Set("Authorization", "Bearer "+token)
+
+	response := httptest.NewRecorder()
+	r.ServeHTTP(response, request)
+
+	return response
+}
+
+// authConfig returns the default configuration with authentication enabled
+func authConfig() *router.Config {
+	config := router.DefaultConfig()
+	config.AuthEnabled = true
+	config.AuthSecret = strings.Repeat("s", 32)
+
+	return config
+}
+
+func (suite *RouterSuite) TestPProfEnabled() {
+	config := authConfig()
+	config.PProfEnabled = true
+
+	suite.Equal(http.StatusOK, suite.serveAs(config, http.MethodGet, "/private/v1/admin/debug/pprof/", user.RoleAdmin).Code)
+	suite.Equal(http.StatusOK, suite.serveAs(config, http.MethodGet, "/private/v1/admin/debug/pprof/cmdline", user.RoleAdmin).Code)
+}
+
+func (suite *RouterSuite) TestPProfDisabled() {
+	config := router.DefaultConfig()
+
+	suite.Equal(http.StatusNotFound, suite.serve(config, "/private/v1/admin/debug/pprof/").Code)
+	suite.Equal(http.StatusNotFound, suite.serve(config, "/private/v1/admin/debug/pprof/cmdline").Code)
+}
+
+func (suite *RouterSuite) TestPProfRequiresAuth() {
+	config := authConfig()
+	config.PProfEnabled = true
+
+	suite.Equal(http.StatusUnauthorized, suite.serve(config, "/private/v1/admin/debug/pprof/").Code)
+	suite.Equal(http.StatusUnauthorized, suite.serve(config, "/private/v1/admin/debug/pprof/cmdline").Code)
+	suite.Equal(http.StatusNotFound, suite.serve(config, "/private/debug/pprof/").Code)
+
+	// Users of a tenant are not allowed to profile the process
+	suite.Equal(http.StatusForbidden, suite.serveAs(config, http.MethodGet, "/private/v1/admin/debug/pprof/", "").Code)
+	suite.Equal(http.StatusForbidden, suite.serveAs(config, http.MethodGet, "/private/v1/admin/debug/pprof/cmdline", "user").Code)
+}
+
+func (suite *RouterSuite) TestPProfNeverPublic() {
+	config := router.DefaultConfig()
+	config.PProfEnabled = true
+
+	suite.Equal(http.StatusNotFound, suite.serve(config, "/public/debug/pprof/").Code)
+	suite.Equal(http.StatusNotFound, suite.serve(config, "/debug/pprof/").Code)
+}
+
+func (suite *RouterSuite) TestReloadConfigRequiresAdmin() {
+	config := authConfig()
+
+	suite.Equal(http.StatusForbidden, suite.serveAs(config, http.MethodPost, "/private/v1/admin/config/reload", "").Code)
+	suite.Equal(http.StatusForbidden, suite.serveAs(config, http.MethodPost, "/private/v1/admin/config/reload", "user").Code)
+
+	suite.mockConfigReloader.EXPECT().Reload().Return([]string{"logger"}, nil, nil)
+	suite.Equal(http.StatusOK, suite.serveAs(config, http.MethodPost, "/private/v1/admin/config/reload", user.RoleAdmin).Code)
+}
+
+func (suite *RouterSuite) TestStatsRequireAdmin() {
+	config := authConfig()
+
+	suite.Equal(http.StatusForbidden, suite.serveAs(config, http.MethodGet, "/private/v1/admin/stats", "user").Code)
+	suite.Equal(http.StatusForbidden, suite.serveAs(config, http.MethodPost, "/private/v1/admin/stats/reset", "user").Code)
+
+	suite.mockShortURLStats.EXPECT().Stats().Return(shorturl.ManagerStats{})
+	suite.mockShortURLStats.EXPECT().ResetStats()
+	suite.Equal(http.StatusOK, suite.serveAs(config, http.MethodGet, "/private/v1/admin/stats", user.RoleAdmin).Code)
+	suite.Equal(http.StatusNoContent, suite.serveAs(config, http.MethodPost, "/private/v1/admin/stats/reset", user.RoleAdmin).Code)
+}
+
+func (suite *RouterSuite) TestShortURLStats() {
+	config := authConfig()
+
+	suite.Equal(http.StatusForbidden, suite.serveAs(config, http.MethodGet, "/private/v1/admin/shorturl/stats", "user").Code)
+
+	suite.mockShortURLStats.EXPECT().Stats().Return(shorturl.ManagerStats{CollisionCount: 1, CreateCount: 4, CollisionRate: 0.25})
+	response := suite.serveAs(config, http.MethodGet, "/private/v1/admin/shorturl/stats", user.RoleAdmin)
+	suite.Equal(http.StatusOK, response.Code)
+	suite.JSONEq(`{"collision_count": 1, "create_count": 4, "collision_rate": 0.25, "hit_count": 0, "miss_count": 0}`, response.Body.String())
+
+	metrics := suite.serve(config, "/private/metrics").Body.String()
+	suite.Contains(metrics, `http_request_duration_seconds_count{handler="get_shorturl_stats",method="GET",status="200"}`)
+}
+
+func (suite *RouterSuite) TestWarmCacheRequiresAdmin() {
+	config := authConfig()
+
+	suite.Equal(http.StatusForbidden, suite.serveAs(config, http.MethodPost, "/private/v1/admin/cache/warm", "user").Code)
+	suite.Equal(http.StatusForbidden, suite.serveAs(config, http.MethodPost, "/private/v1/admin/cache/warm/all", "user").Code)
+
+	suite.mockCacheWarmer.EXPECT().WarmCacheMostVisited(gomock.Any()).Return(nil)
+	suite.Equal(http.StatusNoContent, suite.serveAs(config, http.MethodPost, "/private/v1/admin/cache/warm/all", user.RoleAdmin).Code)
+}
+
+func (suite *RouterSuite) TestAccessLogEnabled() {
+	config := router.DefaultConfig()
+	config.AccessLog = &router.AccessLogConfig{Format: middleware.AccessLogFormatJSON}
+
+	suite.mockLogger.EXPECT().Info("http request", gomock.Any()).Times(2)
+
+	suite.Equal(http.StatusNotFound, suite.serve(config, "/public/v1/unknown").Code)
+	suite.Equal(http.StatusNotFound, suite.serve(config, "/private/v1/unknown").Code)
+}
+
+func (suite *RouterSuite) TestRecoveryOutermost() {
+	config := router.DefaultConfig()
+	config.AccessLog = &router.AccessLogConfig{Format: middleware.AccessLogFormatJSON}
+
+	// A panic of a middleware running before the Recovery of the routers is still recovered, the access log panics
+	// once the response is written so it is kept
+	suite.mockLogger.EXPECT().Info("http request", gomock.Any()).Do(func(string, ...any) {
+		panic("access log panic")
+	}).Times(2)
+	suite.mockLogger.EXPECT().Error("recovered from panic", gomock.Any()).Times(2)
+
+	suite.NotPanics(func() {
+		suite.Equal(http.StatusNotFound, suite.serve(config, "/public/v1/unknown").Code)
+		suite.Equal(http.StatusNotFound, suite.serve(config, "/private/v1/unknown").Code)
+	})
+}
+
+func (suite *RouterSuite) TestConfigValidateAccessLog() {
+	config := router.DefaultConfig()
+	suite.NoError(config.Validate())
+
+	config.AccessLog = &router.AccessLogConfig{Format: middleware.AccessLogFormatCombined}
+	suite.NoError(config.Validate())
+
+	config.AccessLog.Format = "common"
+	suite.Error(config.Validate())
+}
+
+func (suite *RouterSuite) TestConfigValidatePProf() {
+	config := router.DefaultConfig()
+	config.PProfEnabled = true
+	suite.Error(config.Validate())
+
+	config.AuthEnabled = true
+	config.AuthSecret = strings.Repeat("s", 32)
+	suite.NoError(config.Validate())
+}
+
+func (suite *RouterSuite) TestFavicon() {
+	config := router.DefaultConfig()
+	response := suite.serve(config, "/favicon.ico")
+	suite.Equal(http.StatusNoContent, response.Code)
+	suite.Empty(response.Body.String())
+
+	config.FaviconPath = filepath.Join(suite.T().TempDir(), "favicon.ico")
+	suite.Require().NoError(os.WriteFile(config.FaviconPath, []byte("icon"), 0o600))
+
+	response = suite.serve(config, "/favicon.ico")
+	suite.Equal(http.StatusOK, response.Code)
+	suite.Equal("icon", response.Body.String())
+}
+
+func (suite *RouterSuite) TestRobotsTxt() {
+	config := router.DefaultConfig()
+	config.AuthEnabled = true
+	config.AuthSecret = strings.Repeat("s", 32)
+
+	response := suite.serve(config, "/robots.txt")
+	suite.Equal(http.StatusOK, response.Code)
+	suite.Equal("text/plain; charset=utf-8", response.Header().Get("Content-Type"))
+	suite.Equal("User-agent: *\nDisallow: /private/\n", response.Body.String())
+
+	config.RobotsTxt = "User-agent: *\nDisallow: /\n"
+	suite.Equal("User-agent: *\nDisallow: /\n", suite.serve(config, "/robots.txt").Body.String())
+}
+
+// importRequest returns a CSV import request whose file is a header with no long_url column padded to size bytes
+func importRequest(size int) *http.Request {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	file, _ := writer.CreateFormFile("file", "urls.csv")
+	_, _ = file.Write([]byte(strings.Repeat("a", size) + "\n"))
+	_ = writer.Close()
+
+	request := httptest.NewRequest(http.MethodPost, "/private/v1/short-urls/import", body)
+	request.Header.Set("Content-Type", writer.FormDataContentType())
+
+	return request
+}
+
+func (suite *RouterSuite) TestImportMaxFileSize() {
+	config := router.DefaultConfig()
+	r := router.NewRouter(config, suite.shortURLHandler, suite.adminHandler, suite.mockCache, suite.mockLogger)
+
+	// Imports are limited by MaxImportFileSizeBytes, files larger than MaxRequestBodyBytes are read
+	response := httptest.NewRecorder()
+	r.ServeHTTP(response, importRequest(int(config.MaxRequestBodyBytes)*2))
+	suite.Equal(http.StatusBadRequest, response.Code)
+
+	response = httptest.NewRecorder()
+	r.ServeHTTP(response, importRequest(int(config.MaxImportFileSizeBytes)))
+	suite.Equal(http.StatusRequestEntityTooLarge, response.Code)
+}
+
+func (suite *RouterSuite) TestRequestDurationMetrics() {
+	config := router.DefaultConfig()
+	r := router.NewRouter(config, suite.shortURLHandler, suite.adminHandler, suite.mockCache, suite.mockLogger)
+
+	r.ServeHTTP(httptest.NewRecorder(), importRequest(16))
+
+	response := suite.serve(config, "/private/metrics")
+	suite.Equal(http.StatusOK, response.Code)
+	suite.Contains(response.Body.String(), `http_request_duration_seconds_count{handler="import_short_urls",method="POST",status="400"}`)
+	suite.Contains(response.Body.String(), `request_body_bytes_count{handler="/private/v1/short-urls/import"}`)
+	suite.Contains(response.Body.String(), `response_body_bytes_count{handler="/private/v1/short-urls/import"}`)
+}
+
+func (suite *RouterSuite) TestCustomMiddlewares() {
+	teapot := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusTeapot)
+		})
+	}
+	header := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Custom", "private")
+			next.ServeHTTP(w, r)
+		})
+	}
+
+	config := router.DefaultConfig()
+	config.PublicMiddlewares = []func(http.Handler) http.Handler{teapot}
+	config.PrivateMiddlewares = []func(http.Handler) http.Handler{header}
+
+	suite.Equal(http.StatusTeapot, suite.serve(config, "/public/v1/short-urls/AABBCC").Code)
+
+	response := suite.serve(config, "/private/v1/unknown")
+	suite.Equal(http.StatusNotFound, response.Code)
+	suite.Equal("private", response.Header().Get("X-Custom"))
+
+	// Routes outside the routers are left alone
+	suite.Equal(http.StatusNoContent, suite.serve(config, "/favicon.ico").Code)
+}
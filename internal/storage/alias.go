@@ -0,0 +1,74 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/AvalosM/short-url-service/pkg/shorturl"
+)
+
+// CreateAlias creates an alias id of a short URL of a tenant in the database, shorturl.ErrAliasExists is returned if
+// the alias id is already taken
+func (p *Storage) CreateAlias(ctx context.Context, tenantID string, aliasID string, canonicalID string) error {
+	defer observeDuration("create_alias")()
+
+	result, err := p.db.ExecContext(ctx,
+		"INSERT INTO short_url_aliases (tenant_id, alias_id, canonical_id) VALUES ($1, $2, $3) ON CONFLICT (tenant_id, alias_id) DO NOTHING",
+		tenantID, aliasID, canonicalID,
+	)
+	if err != nil {
+		return fmt.Errorf("executing create alias query: %w", err)
+	}
+
+	created, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("getting created aliases: %w", err)
+	}
+	if created == 0 {
+		return fmt.Errorf("alias %s: %w", aliasID, shorturl.ErrAliasExists)
+	}
+
+	return nil
+}
+
+// DeleteAlias deletes an alias id of a short URL of a tenant from the database, it reports whether the alias existed
+func (p *Storage) DeleteAlias(ctx context.Context, tenantID string, canonicalID string, aliasID string) (bool, error) {
+	defer observeDuration("delete_alias")()
+
+	result, err := p.db.ExecContext(ctx,
+		"DELETE FROM short_url_aliases WHERE tenant_id = $1 AND alias_id = $2 AND canonical_id = $3", tenantID, aliasID, canonicalID)
+	if err != nil {
+		return false, fmt.Errorf("executing delete alias query: %w", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("getting deleted aliases: %w", err)
+	}
+
+	return deleted > 0, nil
+}
+
+// GetLongURLByAlias retrieves the id and long URL of the short URL an alias id of a tenant points to, aliases of
+// deleted short URLs are not found
+func (p *Storage) GetLongURLByAlias(ctx context.Context, tenantID string, aliasID string) (string, string, bool, error) {
+	defer observeDuration("get_long_url_by_alias")()
+
+	query := `SELECT s.id, s.long_url FROM short_url_aliases a
+			  JOIN short_urls s ON s.tenant_id = a.tenant_id AND s.id = a.canonical_id
+			  WHERE a.tenant_id = $1 AND a.alias_id = $2 AND s.deleted_at IS NULL`
+
+	var canonicalID, longURL string
+	err := p.readDB.QueryRowContext(ctx, query, tenantID, aliasID).Scan(&canonicalID, &longURL)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", "", false, nil
+		}
+
+		return "", "", false, fmt.Errorf("executing get long URL by alias query: %w", err)
+	}
+
+	return canonicalID, longURL, true, nil
+}
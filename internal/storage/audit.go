@@ -0,0 +1,89 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/AvalosM/short-url-service/pkg/shorturl"
+)
+
+// execer is implemented by both *sql.DB and *sql.Tx
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// WriteAuditLog appends an entry to the short URL audit log of a tenant
+func (p *Storage) WriteAuditLog(ctx context.Context, tenantID string, entry shorturl.AuditEntry) error {
+	defer observeDuration("write_audit_log")()
+
+	return writeAuditLog(ctx, p.db, tenantID, entry)
+}
+
+// writeAuditLog appends an entry to the short URL audit log of a tenant using db, which can be a transaction
+func writeAuditLog(ctx context.Context, db execer, tenantID string, entry shorturl.AuditEntry) error {
+	actor := sql.NullString{String: entry.Actor, Valid: entry.Actor != ""}
+	var payload any
+	if len(entry.Payload) > 0 {
+		payload = string(entry.Payload)
+	}
+
+	_, err := db.ExecContext(ctx,
+		"INSERT INTO short_url_audit_log (tenant_id, operation, short_url_id, actor, payload) VALUES ($1, $2, $3, $4, $5)",
+		tenantID, entry.Operation, entry.ShortURLId, actor, payload,
+	)
+	if err != nil {
+		return fmt.Errorf("executing write audit log query: %w", err)
+	}
+
+	return nil
+}
+
+// GetAuditLog retrieves the audit log entries of a short URL, oldest first
+func (p *Storage) GetAuditLog(ctx context.Context, tenantID string, filter *shorturl.AuditFilter) ([]*shorturl.AuditEntry, error) {
+	defer observeDuration("get_audit_log")()
+
+	queryBuilder := p.builder.
+		Select("id", "operation", "short_url_id", "actor", "payload", "created_at").
+		From("short_url_audit_log").
+		Where("tenant_id = ?", tenantID).
+		Where("short_url_id = ?", filter.ShortURLId).
+		OrderBy("created_at", "id")
+
+	if filter.Limit > 0 {
+		queryBuilder = queryBuilder.Limit(uint64(filter.Limit))
+	}
+	if filter.Offset > 0 {
+		queryBuilder = queryBuilder.Offset(uint64(filter.Offset))
+	}
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("building get audit log query: %w", err)
+	}
+
+	rows, err := p.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("executing get audit log query: %w", err)
+	}
+	defer rows.Close()
+
+	entries := make([]*shorturl.AuditEntry, 0)
+	for rows.Next() {
+		entry := &shorturl.AuditEntry{}
+		var actor sql.NullString
+		var payload []byte
+		if err := rows.Scan(&entry.Id, &entry.Operation, &entry.ShortURLId, &actor, &payload, &entry.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scanning audit entry: %w", err)
+		}
+		entry.Actor = actor.String
+		entry.Payload = payload
+
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating audit entries: %w", err)
+	}
+
+	return entries, nil
+}
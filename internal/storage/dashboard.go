@@ -0,0 +1,140 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/AvalosM/short-url-service/pkg/shorturl"
+)
+
+// dashboardTopLimit is the number of short URLs in each top list of the dashboard
+const dashboardTopLimit = 5
+
+// dashboardStatsQuery computes every dashboard figure of a tenant within a time range in a single round trip. The
+// visits of every short URL are summed once and shared by the totals and both top lists. Rolled up rows are matched
+// by the start of their period.
+const dashboardStatsQuery = `WITH created AS (
+				SELECT COUNT(*) AS short_urls FROM short_urls
+				WHERE tenant_id = $1 AND created_at BETWEEN $2 AND $3 AND deleted_at IS NULL
+			), visits AS (
+				SELECT short_url_id, SUM(visit_count) AS visits, SUM(unique_visit_count) AS unique_visits
+				FROM (
+				    SELECT short_url_id, visit_count, unique_visit_count FROM short_url_metrics
+				    WHERE tenant_id = $1 AND timestamp BETWEEN $2 AND $3 AND deleted_at IS NULL
+				    UNION ALL
+				    SELECT short_url_id, visit_count, unique_visit_count FROM short_url_metrics_rollup
+				    WHERE tenant_id = $1 AND timestamp BETWEEN $2 AND $3 AND deleted_at IS NULL
+				) metrics
+				GROUP BY short_url_id
+			), top_visits AS (
+				SELECT short_url_id, visits, unique_visits FROM visits
+				ORDER BY visits DESC, short_url_id
+				LIMIT $4
+			), top_unique_visits AS (
+				SELECT short_url_id, visits, unique_visits FROM visits
+				ORDER BY unique_visits DESC, short_url_id
+				LIMIT $4
+			)
+			SELECT created.short_urls,
+				(SELECT COALESCE(SUM(visits), 0)::bigint FROM visits),
+				(SELECT COALESCE(SUM(unique_visits), 0)::bigint FROM visits),
+				(SELECT COALESCE(json_agg(json_build_object('short_url_id', t.short_url_id, 'long_url', s.long_url,
+				     'visits', t.visits, 'unique_visits', t.unique_visits) ORDER BY t.visits DESC, t.short_url_id), '[]')
+				 FROM top_visits t JOIN short_urls s ON s.tenant_id = $1 AND s.id = t.short_url_id),
+				(SELECT COALESCE(json_agg(json_build_object('short_url_id', t.short_url_id, 'long_url', s.long_url,
+				     'visits', t.visits, 'unique_visits', t.unique_visits) ORDER BY t.unique_visits DESC, t.short_url_id), '[]')
+				 FROM top_unique_visits t JOIN short_urls s ON s.tenant_id = $1 AND s.id = t.short_url_id)
+			FROM created`
+
+// GetDashboardStats retrieves the short URLs created and the redirects of a tenant within a given time range, with
+// the short URLs with the most visits and unique visits
+func (p *Storage) GetDashboardStats(ctx context.Context, tenantID string, from, to time.Time) (*shorturl.DashboardStats, error) {
+	defer observeDuration("get_dashboard_stats")()
+
+	stats := &shorturl.DashboardStats{From: from, To: to}
+	var topByVisits, topByUniqueVisits []byte
+	err := p.readDB.QueryRowContext(ctx, dashboardStatsQuery, tenantID, from, to, dashboardTopLimit).
+		Scan(&stats.ShortURLsCreated, &stats.Visits, &stats.UniqueVisits, &topByVisits, &topByUniqueVisits)
+	if err != nil {
+		return nil, fmt.Errorf("executing get dashboard stats query: %w", err)
+	}
+
+	if stats.TopByVisits, err = decodeTopShortURLs(topByVisits); err != nil {
+		return nil, fmt.Errorf("decoding top short URLs by visits: %w", err)
+	}
+	if stats.TopByUniqueVisits, err = decodeTopShortURLs(topByUniqueVisits); err != nil {
+		return nil, fmt.Errorf("decoding top short URLs by unique visits: %w", err)
+	}
+
+	return stats, nil
+}
+
+// mostVisitedShortURLsQuery sums the visits of every short URL of a tenant since a time, rolled up rows are matched
+// by the start of their period like in the dashboard query
+const mostVisitedShortURLsQuery = `SELECT short_url_id FROM (
+				SELECT short_url_id, visit_count FROM short_url_metrics
+				WHERE tenant_id = $1 AND timestamp >= $2 AND deleted_at IS NULL
+				UNION ALL
+				SELECT short_url_id, visit_count FROM short_url_metrics_rollup
+				WHERE tenant_id = $1 AND timestamp >= $2 AND deleted_at IS NULL
+			) metrics
+			GROUP BY short_url_id
+			ORDER BY SUM(visit_count) DESC, short_url_id
+			LIMIT $3`
+
+// GetMostVisitedShortURLIds retrieves the ids of the short URLs of a tenant with the most visits since a given time,
+// most visited first
+func (p *Storage) GetMostVisitedShortURLIds(ctx context.Context, tenantID string, since time.Time, limit int) ([]string, error) {
+	defer observeDuration("get_most_visited_short_url_ids")()
+
+	rows, err := p.readDB.QueryContext(ctx, mostVisitedShortURLsQuery, tenantID, since, limit)
+	if err != nil {
+		return nil, fmt.Errorf("executing get most visited short URL ids query: %w", err)
+	}
+	defer rows.Close()
+
+	ids := make([]string, 0, limit)
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scanning short URL id: %w", err)
+		}
+
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating short URL ids: %w", err)
+	}
+
+	return ids, nil
+}
+
+// topShortURL is a short URL of a top list of the dashboard query
+type topShortURL struct {
+	ShortURLId   string `json:"short_url_id"`
+	LongURL      string `json:"long_url"`
+	Visits       int64  `json:"visits"`
+	UniqueVisits int64  `json:"unique_visits"`
+}
+
+// decodeTopShortURLs decodes a top list of the dashboard query
+func decodeTopShortURLs(encoded []byte) ([]*shorturl.ShortURLStats, error) {
+	var rows []topShortURL
+	if err := json.Unmarshal(encoded, &rows); err != nil {
+		return nil, err
+	}
+
+	top := make([]*shorturl.ShortURLStats, 0, len(rows))
+	for _, row := range rows {
+		top = append(top, &shorturl.ShortURLStats{
+			ShortURLId:   row.ShortURLId,
+			LongURL:      row.LongURL,
+			Visits:       row.Visits,
+			UniqueVisits: row.UniqueVisits,
+		})
+	}
+
+	return top, nil
+}
@@ -0,0 +1,4 @@
+package storage
+
+// DashboardStatsQuery exposes the dashboard query to the storage tests, which explain it
+const DashboardStatsQuery = dashboardStatsQuery
@@ -0,0 +1,72 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/AvalosM/short-url-service/pkg/metrics"
+)
+
+// CreateLatencyMetrics inserts the redirect latency percentiles of multiple short URLs into the database
+func (p *Storage) CreateLatencyMetrics(ctx context.Context, latencies map[metrics.CollectorKey]*metrics.Latency) error {
+	defer observeDuration("create_latency_metrics")()
+
+	if len(latencies) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+
+	queryBuilder := p.builder.
+		Insert("short_url_latency").
+		Columns("tenant_id", "short_url_id", "request_count", "p50_ns", "p95_ns", "p99_ns", "timestamp")
+
+	for key, latency := range latencies {
+		queryBuilder = queryBuilder.Values(key.TenantId, key.ShortURLId, latency.Requests, latency.P50Ns, latency.P95Ns,
+			latency.P99Ns, now)
+	}
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return fmt.Errorf("building create latency metrics query: %w", err)
+	}
+
+	_, err = p.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("executing create latency metrics query: %w", err)
+	}
+
+	return nil
+}
+
+// GetLatencyMetrics retrieves the redirect latency percentiles of a specific short URL ID of a tenant within a given
+// time range, oldest first
+func (p *Storage) GetLatencyMetrics(ctx context.Context, tenantID string, shortURLId string, from, to time.Time) ([]*metrics.Latency, error) {
+	defer observeDuration("get_latency_metrics")()
+
+	query := `SELECT timestamp, request_count, p50_ns, p95_ns, p99_ns FROM short_url_latency
+			  WHERE tenant_id = $1 AND short_url_id = $2 AND timestamp BETWEEN $3 AND $4
+			  ORDER BY timestamp`
+
+	rows, err := p.readDB.QueryContext(ctx, query, tenantID, shortURLId, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("executing get latency metrics query: %w", err)
+	}
+	defer rows.Close()
+
+	latencies := []*metrics.Latency{}
+	for rows.Next() {
+		latency := &metrics.Latency{}
+		if err := rows.Scan(&latency.Timestamp, &latency.Requests, &latency.P50Ns, &latency.P95Ns, &latency.P99Ns); err != nil {
+			return nil, fmt.Errorf("scanning latency metrics: %w", err)
+		}
+
+		latencies = append(latencies, latency)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating latency metrics: %w", err)
+	}
+
+	return latencies, nil
+}
@@ -3,27 +3,82 @@ package storage
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
+	"slices"
 	"time"
 
+	"github.com/jackc/pgx/v5/pgconn"
+
 	"github.com/AvalosM/short-url-service/pkg/metrics"
 )
 
-// CreateMetrics inserts multiple metric collectors into the database
-func (p *Storage) CreateMetrics(ctx context.Context, collectors map[string]*metrics.Collector) error {
+const (
+	// topCountriesLimit is the number of countries with the most visits included in metrics
+	topCountriesLimit = 10
+	// foreignKeyViolationCode is the SQLSTATE of Postgres foreign key violations
+	foreignKeyViolationCode = "23503"
+)
+
+// CreateMetrics inserts multiple metric collectors into the database in a transaction. The metrics reference their
+// short URL through the short_url_metrics_short_url_id_fkey foreign key on (tenant_id, short_url_id), so when a short
+// URL of the collectors does not exist no metrics are created and an error wrapping metrics.ErrMissingShortURL is
+// returned, unless MetricsBulkInsertFallback is set: then the metrics of the existing short URLs are created and a
+// *metrics.SkippedMetricsError lists the collectors skipped.
+func (p *Storage) CreateMetrics(ctx context.Context, collectors map[metrics.CollectorKey]*metrics.Collector) error {
+	defer observeDuration("create_metrics")()
+
 	if len(collectors) == 0 {
 		return nil
 	}
 
 	now := time.Now()
 
+	rows := make(map[metrics.CollectorKey][]any, len(collectors))
+	for key, collector := range collectors {
+		countryBreakdown := []byte("{}")
+		if len(collector.CountryBreakdown) > 0 {
+			var err error
+			if countryBreakdown, err = json.Marshal(collector.CountryBreakdown); err != nil {
+				return fmt.Errorf("marshaling country breakdown: %w", err)
+			}
+		}
+
+		rows[key] = []any{collector.TenantId, collector.ShortURLId, collector.Visits, collector.UniqueVisits(),
+			collector.BotVisits, string(countryBreakdown), now}
+	}
+
+	err := p.insertMetrics(ctx, slices.Collect(maps.Values(rows)))
+	if err == nil || !isForeignKeyViolation(err) {
+		return err
+	}
+	if !p.config.MetricsBulkInsertFallback {
+		return fmt.Errorf("%w: %w", metrics.ErrMissingShortURL, err)
+	}
+
+	var skipped []metrics.CollectorKey
+	for key, row := range rows {
+		if err := p.insertMetrics(ctx, [][]any{row}); err != nil {
+			if !isForeignKeyViolation(err) {
+				return err
+			}
+
+			skipped = append(skipped, key)
+		}
+	}
+
+	return &metrics.SkippedMetricsError{Keys: skipped}
+}
+
+// insertMetrics inserts the rows into short_url_metrics in a transaction
+func (p *Storage) insertMetrics(ctx context.Context, rows [][]any) error {
 	queryBuilder := p.builder.
 		Insert("short_url_metrics").
-		Columns("short_url_id", "visit_count", "unique_visit_count", "timestamp")
-
-	for _, collector := range collectors {
-		queryBuilder = queryBuilder.Values(collector.ShortURLId, collector.Visits, collector.UniqueVisits(), now)
+		Columns("tenant_id", "short_url_id", "visit_count", "unique_visit_count", "bot_visit_count", "country_breakdown", "timestamp")
+	for _, row := range rows {
+		queryBuilder = queryBuilder.Values(row...)
 	}
 
 	query, args, err := queryBuilder.ToSql()
@@ -31,23 +86,65 @@ func (p *Storage) CreateMetrics(ctx context.Context, collectors map[string]*metr
 		return fmt.Errorf("building create metrics query: %w", err)
 	}
 
-	_, err = p.db.ExecContext(ctx, query, args...)
+	tx, err := p.db.BeginTx(ctx, nil)
 	if err != nil {
+		return fmt.Errorf("beginning create metrics transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
 		return fmt.Errorf("executing create metrics query: %w", err)
 	}
 
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("committing create metrics transaction: %w", err)
+	}
+
 	return nil
 }
 
-// GetMetrics retrieves the metrics for a specific short URL ID within a given time range
-func (p *Storage) GetMetrics(ctx context.Context, shortURLId string, from, to time.Time) (*metrics.Metrics, bool, error) {
-	query := `SELECT SUM(visit_count), SUM(unique_visit_count) 
-			  FROM short_url_metrics
-			  WHERE short_url_id = $1 AND timestamp BETWEEN $2 AND $3
+// isForeignKeyViolation reports whether err is a Postgres foreign key violation
+func isForeignKeyViolation(err error) bool {
+	var pgErr *pgconn.PgError
+
+	return errors.As(err, &pgErr) && pgErr.Code == foreignKeyViolationCode
+}
+
+// GetMetrics retrieves the metrics for a specific short URL ID of a tenant within a given time range
+func (p *Storage) GetMetrics(ctx context.Context, tenantID string, shortURLId string, from, to time.Time) (*metrics.Metrics, bool, error) {
+	defer observeDuration("get_metrics")()
+
+	return p.getMetrics(ctx, tenantID, shortURLId, from, to, false)
+}
+
+// GetDeletedShortURLMetrics retrieves the soft deleted metrics for a specific short URL ID of a tenant within a given time range
+func (p *Storage) GetDeletedShortURLMetrics(ctx context.Context, tenantID string, shortURLId string, from, to time.Time) (*metrics.Metrics, bool, error) {
+	defer observeDuration("get_deleted_short_url_metrics")()
+
+	return p.getMetrics(ctx, tenantID, shortURLId, from, to, true)
+}
+
+func (p *Storage) getMetrics(ctx context.Context, tenantID string, shortURLId string, from, to time.Time, deleted bool) (*metrics.Metrics, bool, error) {
+	deletedFilter := "deleted_at IS NULL"
+	if deleted {
+		deletedFilter = "deleted_at IS NOT NULL"
+	}
+
+	// Rolled up rows are matched by the start of their period
+	query := `SELECT SUM(visit_count), SUM(unique_visit_count), SUM(bot_visit_count)
+			  FROM (
+			      SELECT short_url_id, visit_count, unique_visit_count, bot_visit_count FROM short_url_metrics
+			      WHERE tenant_id = $1 AND short_url_id = $2 AND timestamp BETWEEN $3 AND $4 AND ` + deletedFilter + `
+			      UNION ALL
+			      SELECT short_url_id, visit_count, unique_visit_count, bot_visit_count FROM short_url_metrics_rollup
+			      WHERE tenant_id = $1 AND short_url_id = $2 AND timestamp BETWEEN $3 AND $4 AND ` + deletedFilter + `
+			  ) metrics
 			  GROUP BY short_url_id`
 
-	var visits, uniqueVisits int64
-	if err := p.db.QueryRowContext(ctx, query, shortURLId, from, to).Scan(&visits, &uniqueVisits); err != nil {
+	var visits, uniqueVisits, botVisits int64
+	if err := p.readDB.QueryRowContext(ctx, query, tenantID, shortURLId, from, to).Scan(&visits, &uniqueVisits, &botVisits); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, false, nil
 		}
@@ -55,11 +152,221 @@ func (p *Storage) GetMetrics(ctx context.Context, shortURLId string, from, to ti
 		return nil, false, fmt.Errorf("executing get metrics query: %w", err)
 	}
 
+	topCountries, err := p.getTopCountries(ctx, tenantID, shortURLId, from, to, deletedFilter)
+	if err != nil {
+		return nil, false, err
+	}
+
 	return &metrics.Metrics{
 		ShortURLId:   shortURLId,
 		Visits:       visits,
 		UniqueVisits: uniqueVisits,
+		BotVisits:    botVisits,
+		TopCountries: topCountries,
 		From:         from,
 		To:           to,
 	}, true, nil
 }
+
+// GetMetricsBulk retrieves the metrics of several short URLs of a tenant within a given time range in a single
+// query, by short URL id. Short URLs without metrics within the range are left out.
+func (p *Storage) GetMetricsBulk(ctx context.Context, tenantID string, shortURLIds []string, from, to time.Time) (map[string]*metrics.Metrics, error) {
+	defer observeDuration("get_metrics_bulk")()
+
+	// Rolled up rows are matched by the start of their period
+	query := `SELECT short_url_id, SUM(visit_count), SUM(unique_visit_count), SUM(bot_visit_count)
+			  FROM (
+			      SELECT short_url_id, visit_count, unique_visit_count, bot_visit_count FROM short_url_metrics
+			      WHERE tenant_id = $1 AND short_url_id = ANY($2) AND timestamp BETWEEN $3 AND $4 AND deleted_at IS NULL
+			      UNION ALL
+			      SELECT short_url_id, visit_count, unique_visit_count, bot_visit_count FROM short_url_metrics_rollup
+			      WHERE tenant_id = $1 AND short_url_id = ANY($2) AND timestamp BETWEEN $3 AND $4 AND deleted_at IS NULL
+			  ) metrics
+			  GROUP BY short_url_id`
+
+	rows, err := p.readDB.QueryContext(ctx, query, tenantID, shortURLIds, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("executing get metrics bulk query: %w", err)
+	}
+	defer rows.Close()
+
+	bulk := make(map[string]*metrics.Metrics, len(shortURLIds))
+	for rows.Next() {
+		shortURLMetrics := &metrics.Metrics{From: from, To: to}
+		if err := rows.Scan(&shortURLMetrics.ShortURLId, &shortURLMetrics.Visits, &shortURLMetrics.UniqueVisits, &shortURLMetrics.BotVisits); err != nil {
+			return nil, fmt.Errorf("scanning metrics: %w", err)
+		}
+
+		bulk[shortURLMetrics.ShortURLId] = shortURLMetrics
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating metrics: %w", err)
+	}
+
+	if err := p.getTopCountriesBulk(ctx, tenantID, shortURLIds, from, to, bulk); err != nil {
+		return nil, err
+	}
+
+	return bulk, nil
+}
+
+// getTopCountriesBulk sets the countries with the most visits to each short URL of bulk within a given time range,
+// ties are ordered by country code like in getTopCountries
+func (p *Storage) getTopCountriesBulk(ctx context.Context, tenantID string, shortURLIds []string, from, to time.Time, bulk map[string]*metrics.Metrics) error {
+	query := `SELECT short_url_id, country, visits FROM (
+			      SELECT metrics.short_url_id, country.key AS country, SUM(country.value::bigint) AS visits,
+			          ROW_NUMBER() OVER (PARTITION BY metrics.short_url_id ORDER BY SUM(country.value::bigint) DESC, country.key) AS rank
+			      FROM (
+			          SELECT short_url_id, country_breakdown FROM short_url_metrics
+			          WHERE tenant_id = $1 AND short_url_id = ANY($2) AND timestamp BETWEEN $3 AND $4 AND deleted_at IS NULL
+			          UNION ALL
+			          SELECT short_url_id, country_breakdown FROM short_url_metrics_rollup
+			          WHERE tenant_id = $1 AND short_url_id = ANY($2) AND timestamp BETWEEN $3 AND $4 AND deleted_at IS NULL
+			      ) metrics
+			      CROSS JOIN LATERAL jsonb_each_text(metrics.country_breakdown) country
+			      GROUP BY metrics.short_url_id, country.key
+			  ) countries
+			  WHERE rank <= $5
+			  ORDER BY short_url_id, rank`
+
+	rows, err := p.readDB.QueryContext(ctx, query, tenantID, shortURLIds, from, to, topCountriesLimit)
+	if err != nil {
+		return fmt.Errorf("executing get top countries bulk query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var shortURLId string
+		var stat metrics.CountryStat
+		if err := rows.Scan(&shortURLId, &stat.Country, &stat.Visits); err != nil {
+			return fmt.Errorf("scanning top country: %w", err)
+		}
+
+		if shortURLMetrics, ok := bulk[shortURLId]; ok {
+			shortURLMetrics.TopCountries = append(shortURLMetrics.TopCountries, stat)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterating top countries: %w", err)
+	}
+
+	return nil
+}
+
+// getTopCountries retrieves the countries with the most visits to a short URL of a tenant within a given time range,
+// ties are ordered by country code
+func (p *Storage) getTopCountries(ctx context.Context, tenantID string, shortURLId string, from, to time.Time, deletedFilter string) ([]metrics.CountryStat, error) {
+	query := `SELECT country.key, SUM(country.value::bigint) AS visits
+			  FROM (
+			      SELECT country_breakdown FROM short_url_metrics
+			      WHERE tenant_id = $1 AND short_url_id = $2 AND timestamp BETWEEN $3 AND $4 AND ` + deletedFilter + `
+			      UNION ALL
+			      SELECT country_breakdown FROM short_url_metrics_rollup
+			      WHERE tenant_id = $1 AND short_url_id = $2 AND timestamp BETWEEN $3 AND $4 AND ` + deletedFilter + `
+			  ) metrics
+			  CROSS JOIN LATERAL jsonb_each_text(metrics.country_breakdown) country
+			  GROUP BY country.key
+			  ORDER BY visits DESC, country.key
+			  LIMIT $5`
+
+	rows, err := p.readDB.QueryContext(ctx, query, tenantID, shortURLId, from, to, topCountriesLimit)
+	if err != nil {
+		return nil, fmt.Errorf("executing get top countries query: %w", err)
+	}
+	defer rows.Close()
+
+	var topCountries []metrics.CountryStat
+	for rows.Next() {
+		var stat metrics.CountryStat
+		if err := rows.Scan(&stat.Country, &stat.Visits); err != nil {
+			return nil, fmt.Errorf("scanning top country: %w", err)
+		}
+
+		topCountries = append(topCountries, stat)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating top countries: %w", err)
+	}
+
+	return topCountries, nil
+}
+
+// StreamMetrics calls fn with the metric intervals for a specific short URL ID of a tenant within a given time range,
+// oldest first. Rows are read from the cursor as fn consumes them, iteration stops at the first error fn returns.
+func (p *Storage) StreamMetrics(ctx context.Context, tenantID string, shortURLId string, from, to time.Time, fn func(*metrics.Interval) error) error {
+	defer observeDuration("stream_metrics")()
+
+	query := `SELECT timestamp, visit_count, unique_visit_count, bot_visit_count FROM short_url_metrics
+			  WHERE tenant_id = $1 AND short_url_id = $2 AND timestamp BETWEEN $3 AND $4 AND deleted_at IS NULL
+			  UNION ALL
+			  SELECT timestamp, visit_count, unique_visit_count, bot_visit_count FROM short_url_metrics_rollup
+			  WHERE tenant_id = $1 AND short_url_id = $2 AND timestamp BETWEEN $3 AND $4 AND deleted_at IS NULL
+			  ORDER BY timestamp`
+
+	rows, err := p.readDB.QueryContext(ctx, query, tenantID, shortURLId, from, to)
+	if err != nil {
+		return fmt.Errorf("executing stream metrics query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		interval := &metrics.Interval{}
+		if err := rows.Scan(&interval.Timestamp, &interval.Visits, &interval.UniqueVisits, &interval.BotVisits); err != nil {
+			return fmt.Errorf("scanning metrics interval: %w", err)
+		}
+
+		if err := fn(interval); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterating metrics intervals: %w", err)
+	}
+
+	return nil
+}
+
+// RollupMetrics replaces the metric rows of every tenant in periods of the given granularity that ended before the
+// given time with one summed row per short URL and period, which are kept in short_url_metrics_rollup. Granularity is
+// any date_trunc field, like hour, day or month. Rows of soft deleted short URLs are summed apart so they stay deleted.
+// Country breakdowns are merged by summing the visits of each country.
+func (p *Storage) RollupMetrics(ctx context.Context, granularity string, before time.Time) error {
+	defer observeDuration("rollup_metrics")()
+
+	query := `WITH rolled_up AS (
+			      DELETE FROM short_url_metrics
+			      WHERE timestamp < date_trunc($1, $2::timestamp)
+			      RETURNING tenant_id, short_url_id, visit_count, unique_visit_count, bot_visit_count, country_breakdown,
+			          date_trunc($1, timestamp) AS period, deleted_at
+			  ), totals AS (
+			      SELECT tenant_id, short_url_id, period, deleted_at, SUM(visit_count) AS visit_count,
+			          SUM(unique_visit_count) AS unique_visit_count, SUM(bot_visit_count) AS bot_visit_count
+			      FROM rolled_up
+			      GROUP BY tenant_id, short_url_id, period, deleted_at
+			  ), countries AS (
+			      SELECT tenant_id, short_url_id, period, deleted_at, jsonb_object_agg(country, visits) AS country_breakdown
+			      FROM (
+			          SELECT tenant_id, short_url_id, period, deleted_at, country.key AS country,
+			              SUM(country.value::bigint) AS visits
+			          FROM rolled_up
+			          CROSS JOIN LATERAL jsonb_each_text(rolled_up.country_breakdown) country
+			          GROUP BY tenant_id, short_url_id, period, deleted_at, country.key
+			      ) country_visits
+			      GROUP BY tenant_id, short_url_id, period, deleted_at
+			  )
+			  INSERT INTO short_url_metrics_rollup
+			      (tenant_id, short_url_id, granularity, visit_count, unique_visit_count, bot_visit_count, country_breakdown,
+			      timestamp, deleted_at)
+			  SELECT t.tenant_id, t.short_url_id, $1, t.visit_count, t.unique_visit_count, t.bot_visit_count,
+			      COALESCE(c.country_breakdown, '{}'), t.period, t.deleted_at
+			  FROM totals t
+			  LEFT JOIN countries c ON c.tenant_id = t.tenant_id AND c.short_url_id = t.short_url_id AND c.period = t.period
+			      AND c.deleted_at IS NOT DISTINCT FROM t.deleted_at`
+
+	_, err := p.db.ExecContext(ctx, query, granularity, before)
+	if err != nil {
+		return fmt.Errorf("executing rollup metrics query: %w", err)
+	}
+
+	return nil
+}
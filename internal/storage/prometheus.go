@@ -0,0 +1,23 @@
+package storage
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var operationDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Name: "storage_operation_duration_seconds",
+	Help: "Duration of the storage operations in seconds",
+}, []string{"operation"})
+
+// observeDuration starts timing a storage operation, the returned function records its duration and is meant to be
+// deferred
+func observeDuration(operation string) func() {
+	start := time.Now()
+
+	return func() {
+		operationDuration.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+	}
+}
@@ -3,34 +3,654 @@ package storage
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+
+	"github.com/AvalosM/short-url-service/pkg/shorturl"
 )
 
-// CreateShortURL creates a new short URL entry in the database
-func (p *Storage) CreateShortURL(ctx context.Context, id string, longURL string) error {
-	_, err := p.db.ExecContext(ctx, "INSERT INTO short_urls (id, long_url) VALUES ($1, $2)", id, longURL)
+const shortURLColumns = "tenant_id, id, long_url, tags, description, max_clicks, click_count, password_hash, redirect_code, forward_query_params, expires_at, cache_ttl_seconds, interstitial, created_by, status, created_at, updated_at"
+
+// CreateShortURL creates a new short URL entry in the database, see TryCreateShortURL. shorturl.ErrShortURLExists is
+// returned if a short URL with the same id already exists.
+func (p *Storage) CreateShortURL(ctx context.Context, tenantID string, shortURL *shorturl.ShortURL) (*shorturl.ShortURL, error) {
+	defer observeDuration("create_short_url")()
+
+	created, exists, err := p.TryCreateShortURL(ctx, tenantID, shortURL)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, fmt.Errorf("short URL %s: %w", shortURL.Id, shorturl.ErrShortURLExists)
+	}
+
+	return created, nil
+}
+
+// TryCreateShortURL creates a new short URL entry in the database, a soft deleted entry with the same id is restored
+// with the new values and keeps its creation time, the aliases of the deleted short URL are removed.
+// It reports whether a short URL with the same id already exists instead of creating it, the conflict is detected by
+// the insert itself so concurrent creations of the same id cannot both succeed. The creation is recorded in the
+// audit log within the same transaction.
+func (p *Storage) TryCreateShortURL(ctx context.Context, tenantID string, shortURL *shorturl.ShortURL) (*shorturl.ShortURL, bool, error) {
+	defer observeDuration("try_create_short_url")()
+
+	tags := shortURL.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+
+	maxClicks := sql.NullInt64{Int64: int64(shortURL.MaxClicks), Valid: shortURL.MaxClicks > 0}
+	passwordHash := sql.NullString{String: shortURL.PasswordHash, Valid: shortURL.PasswordHash != ""}
+	redirectCode := sql.NullInt64{Int64: int64(shortURL.RedirectCode), Valid: shortURL.RedirectCode != 0}
+	cacheTTLSeconds := sql.NullInt64{Int64: int64(shortURL.CacheTTLSeconds), Valid: shortURL.CacheTTLSeconds != 0}
+	createdBy := sql.NullString{String: shortURL.CreatedBy, Valid: shortURL.CreatedBy != ""}
+	var expiresAt sql.NullTime
+	if shortURL.ExpiresAt != nil {
+		expiresAt = sql.NullTime{Time: *shortURL.ExpiresAt, Valid: true}
+	}
+
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, false, fmt.Errorf("beginning create short URL transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	query := `INSERT INTO short_urls (tenant_id, id, long_url, tags, description, max_clicks, password_hash, redirect_code, forward_query_params, expires_at,
+			      cache_ttl_seconds, interstitial, created_by)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+			  ON CONFLICT (tenant_id, id) DO UPDATE
+			  SET long_url = EXCLUDED.long_url, tags = EXCLUDED.tags, description = EXCLUDED.description, max_clicks = EXCLUDED.max_clicks, click_count = 0,
+			      password_hash = EXCLUDED.password_hash, redirect_code = EXCLUDED.redirect_code,
+			      forward_query_params = EXCLUDED.forward_query_params, expires_at = EXCLUDED.expires_at,
+			      cache_ttl_seconds = EXCLUDED.cache_ttl_seconds, interstitial = EXCLUDED.interstitial, created_by = EXCLUDED.created_by,
+			      status = DEFAULT, updated_at = now(), deleted_at = NULL
+			  WHERE short_urls.deleted_at IS NOT NULL
+			  RETURNING ` + shortURLColumns
+
+	created, err := p.scanShortURL(tx.QueryRowContext(ctx, query, tenantID, shortURL.Id, shortURL.LongURL, tags, shortURL.Description, maxClicks, passwordHash, redirectCode,
+		shortURL.ForwardQueryParams, expiresAt, cacheTTLSeconds, shortURL.ShowInterstitial, createdBy))
+	if err != nil {
+		// The conflict update only applies to soft deleted entries, no row is returned for a live one
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, true, nil
+		}
+
+		return nil, false, err
+	}
+
+	// Aliases of a deleted short URL must not point to the new one reusing its id
+	_, err = tx.ExecContext(ctx, "DELETE FROM short_url_aliases WHERE tenant_id = $1 AND canonical_id = $2", tenantID, created.Id)
+	if err != nil {
+		return nil, false, fmt.Errorf("deleting aliases of restored short URL: %w", err)
+	}
+
+	payload, err := json.Marshal(createAuditPayload{
+		LongURL:            created.LongURL,
+		Tags:               tags,
+		Description:        created.Description,
+		MaxClicks:          created.MaxClicks,
+		Protected:          created.Protected(),
+		RedirectCode:       created.RedirectCode,
+		ForwardQueryParams: created.ForwardQueryParams,
+		ExpiresAt:          created.ExpiresAt,
+		CacheTTLSeconds:    created.CacheTTLSeconds,
+		ShowInterstitial:   created.ShowInterstitial,
+		CreatedBy:          created.CreatedBy,
+	})
+	if err != nil {
+		return nil, false, fmt.Errorf("marshalling audit payload: %w", err)
+	}
+
+	err = writeAuditLog(ctx, tx, tenantID, shorturl.AuditEntry{
+		Operation:  shorturl.OperationCreate,
+		ShortURLId: created.Id,
+		Actor:      shorturl.ActorFromContext(ctx),
+		Payload:    payload,
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, false, fmt.Errorf("committing create short URL transaction: %w", err)
+	}
+
+	return created, false, nil
+}
+
+// createAuditPayload is the audit log payload of a short URL creation, the password hash is left out on purpose
+type createAuditPayload struct {
+	LongURL            string     `json:"long_url"`
+	Tags               []string   `json:"tags"`
+	Description        string     `json:"description,omitempty"`
+	MaxClicks          int        `json:"max_clicks,omitempty"`
+	Protected          bool       `json:"protected,omitempty"`
+	RedirectCode       int        `json:"redirect_code,omitempty"`
+	ForwardQueryParams bool       `json:"forward_query_params,omitempty"`
+	ExpiresAt          *time.Time `json:"expires_at,omitempty"`
+	CacheTTLSeconds    int        `json:"cache_ttl_seconds,omitempty"`
+	ShowInterstitial   bool       `json:"show_interstitial,omitempty"`
+	CreatedBy          string     `json:"created_by,omitempty"`
+}
+
+// DeleteShortURL soft deletes a short URL entry and its metrics from the database by its id.
+// The deletion is recorded in the audit log within the same transaction.
+func (p *Storage) DeleteShortURL(ctx context.Context, tenantID string, id string) error {
+	defer observeDuration("delete_short_url")()
+
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("beginning delete short URL transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	result, err := tx.ExecContext(ctx,
+		"UPDATE short_urls SET deleted_at = now(), updated_at = now() WHERE tenant_id = $1 AND id = $2 AND deleted_at IS NULL", tenantID, id)
+	if err != nil {
+		return fmt.Errorf("soft deleting short URL: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx,
+		"UPDATE short_url_metrics SET deleted_at = now() WHERE tenant_id = $1 AND short_url_id = $2 AND deleted_at IS NULL", tenantID, id)
+	if err != nil {
+		return fmt.Errorf("soft deleting short URL metrics: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx,
+		"UPDATE short_url_metrics_rollup SET deleted_at = now() WHERE tenant_id = $1 AND short_url_id = $2 AND deleted_at IS NULL", tenantID, id)
+	if err != nil {
+		return fmt.Errorf("soft deleting short URL metrics rollup: %w", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("getting deleted short URLs: %w", err)
+	}
+	if deleted > 0 {
+		err = writeAuditLog(ctx, tx, tenantID, shorturl.AuditEntry{
+			Operation:  shorturl.OperationDelete,
+			ShortURLId: id,
+			Actor:      shorturl.ActorFromContext(ctx),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// DeleteShortURLsByOwner soft deletes the short URLs created by the user with the given id and their metrics, and
+// returns the ids of the deleted short URLs. The user id is erased from the deleted entries, each deletion is recorded
+// in the audit log within the same transaction.
+func (p *Storage) DeleteShortURLsByOwner(ctx context.Context, tenantID string, userID string) ([]string, error) {
+	defer observeDuration("delete_short_urls_by_owner")()
+
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("beginning delete short URLs by owner transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	rows, err := tx.QueryContext(ctx,
+		`UPDATE short_urls SET deleted_at = now(), updated_at = now(), created_by = NULL
+		 WHERE tenant_id = $1 AND created_by = $2 AND deleted_at IS NULL
+		 RETURNING id`, tenantID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("soft deleting short URLs by owner: %w", err)
+	}
+
+	ids := make([]string, 0)
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+
+			return nil, fmt.Errorf("scanning deleted short URL id: %w", err)
+		}
+
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating deleted short URL ids: %w", err)
+	}
+	if len(ids) == 0 {
+		return ids, nil
+	}
+
+	_, err = tx.ExecContext(ctx,
+		"UPDATE short_url_metrics SET deleted_at = now() WHERE tenant_id = $1 AND short_url_id = ANY($2) AND deleted_at IS NULL", tenantID, ids)
+	if err != nil {
+		return nil, fmt.Errorf("soft deleting short URL metrics: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx,
+		"UPDATE short_url_metrics_rollup SET deleted_at = now() WHERE tenant_id = $1 AND short_url_id = ANY($2) AND deleted_at IS NULL", tenantID, ids)
+	if err != nil {
+		return nil, fmt.Errorf("soft deleting short URL metrics rollup: %w", err)
+	}
+
+	for _, id := range ids {
+		err = writeAuditLog(ctx, tx, tenantID, shorturl.AuditEntry{
+			Operation:  shorturl.OperationDelete,
+			ShortURLId: id,
+			Actor:      shorturl.ActorFromContext(ctx),
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("committing delete short URLs by owner transaction: %w", err)
+	}
+
+	return ids, nil
+}
+
+// UpdateShortURLStatus moves a short URL from status from to status to, it reports whether the short URL was updated,
+// which it is not if it does not exist or its status is no longer from. The change is recorded in the audit log within
+// the same transaction.
+func (p *Storage) UpdateShortURLStatus(ctx context.Context, tenantID string, id string, from shorturl.Status, to shorturl.Status) (bool, error) {
+	defer observeDuration("update_short_url_status")()
+
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("beginning update short URL status transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	result, err := tx.ExecContext(ctx,
+		"UPDATE short_urls SET status = $1, updated_at = now() WHERE tenant_id = $2 AND id = $3 AND status = $4 AND deleted_at IS NULL",
+		to, tenantID, id, from)
+	if err != nil {
+		return false, fmt.Errorf("updating short URL status: %w", err)
+	}
+
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("getting updated short URLs: %w", err)
+	}
+	if updated == 0 {
+		return false, nil
+	}
+
+	payload, err := json.Marshal(statusAuditPayload{From: from, To: to})
+	if err != nil {
+		return false, fmt.Errorf("marshalling audit payload: %w", err)
+	}
+
+	err = writeAuditLog(ctx, tx, tenantID, shorturl.AuditEntry{
+		Operation:  shorturl.OperationStatus,
+		ShortURLId: id,
+		Actor:      shorturl.ActorFromContext(ctx),
+		Payload:    payload,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("committing update short URL status transaction: %w", err)
+	}
+
+	return true, nil
+}
+
+// statusAuditPayload is the audit log payload of a short URL status change
+type statusAuditPayload struct {
+	From shorturl.Status `json:"from"`
+	To   shorturl.Status `json:"to"`
+}
+
+// DeleteExpiredShortURLs soft deletes the short URLs of all tenants whose expiration time has passed, along with their
+// metrics, and returns the ids of the deleted short URLs by tenant id. Each deletion is recorded in the audit log
+// within the same transaction.
+func (p *Storage) DeleteExpiredShortURLs(ctx context.Context) (map[string][]string, error) {
+	defer observeDuration("delete_expired_short_urls")()
+
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("beginning delete expired short URLs transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	rows, err := tx.QueryContext(ctx,
+		`UPDATE short_urls SET deleted_at = now(), updated_at = now()
+		 WHERE expires_at < now() AND deleted_at IS NULL
+		 RETURNING tenant_id, id`)
+	if err != nil {
+		return nil, fmt.Errorf("soft deleting expired short URLs: %w", err)
+	}
+
+	deleted := make(map[string][]string)
+	for rows.Next() {
+		var tenantID, id string
+		if err := rows.Scan(&tenantID, &id); err != nil {
+			rows.Close()
+
+			return nil, fmt.Errorf("scanning deleted expired short URL: %w", err)
+		}
+
+		deleted[tenantID] = append(deleted[tenantID], id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating deleted expired short URLs: %w", err)
+	}
+
+	for tenantID, ids := range deleted {
+		_, err = tx.ExecContext(ctx,
+			"UPDATE short_url_metrics SET deleted_at = now() WHERE tenant_id = $1 AND short_url_id = ANY($2) AND deleted_at IS NULL", tenantID, ids)
+		if err != nil {
+			return nil, fmt.Errorf("soft deleting expired short URL metrics: %w", err)
+		}
+
+		_, err = tx.ExecContext(ctx,
+			"UPDATE short_url_metrics_rollup SET deleted_at = now() WHERE tenant_id = $1 AND short_url_id = ANY($2) AND deleted_at IS NULL", tenantID, ids)
+		if err != nil {
+			return nil, fmt.Errorf("soft deleting expired short URL metrics rollup: %w", err)
+		}
+
+		for _, id := range ids {
+			err = writeAuditLog(ctx, tx, tenantID, shorturl.AuditEntry{
+				Operation:  shorturl.OperationDelete,
+				ShortURLId: id,
+				Actor:      shorturl.ActorFromContext(ctx),
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("committing delete expired short URLs transaction: %w", err)
+	}
+
+	return deleted, nil
+}
+
+// GetLongURL retrieves the short URL, including its long URL, for a given short URL id to follow its redirect.
+// A click is counted for short URLs with a click limit, shorturl.ErrClickLimitExceeded is returned once it is reached.
+// Only active short URLs redirect, shorturl.ErrShortURLPaused and shorturl.ErrShortURLArchived are returned otherwise,
+// and shorturl.ErrShortURLExpired once the expiration time of the short URL has passed.
+func (p *Storage) GetLongURL(ctx context.Context, tenantID string, id string) (*shorturl.ShortURL, bool, error) {
+	defer observeDuration("get_long_url")()
+
+	// Redirects are read from the replica, clicks are counted on the primary
+	shortURL, found, err := p.getShortURL(ctx, p.readDB, tenantID, id)
+	if err != nil || !found {
+		return shortURL, found, err
+	}
+	switch shortURL.Status {
+	case shorturl.StatusPaused:
+		return nil, false, shorturl.ErrShortURLPaused
+	case shorturl.StatusArchived:
+		return nil, false, shorturl.ErrShortURLArchived
+	}
+	if shortURL.ExpiresAt != nil && !shortURL.ExpiresAt.After(time.Now()) {
+		return nil, false, shorturl.ErrShortURLExpired
+	}
+	if shortURL.MaxClicks == 0 {
+		return shortURL, true, nil
+	}
+
+	// Counting and checking the limit in a single statement keeps concurrent redirects from exceeding it
+	query := `UPDATE short_urls SET click_count = click_count + 1, updated_at = now()
+			  WHERE tenant_id = $1 AND id = $2 AND deleted_at IS NULL AND status = 'active' AND click_count < max_clicks
+			    AND (expires_at IS NULL OR expires_at > now())
+			  RETURNING ` + shortURLColumns
 
-	return err
+	shortURL, err = p.scanShortURL(p.db.QueryRowContext(ctx, query, tenantID, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, false, shorturl.ErrClickLimitExceeded
+		}
+
+		return nil, false, fmt.Errorf("counting short URL click: %w", err)
+	}
+
+	return shortURL, true, nil
+}
+
+// GetShortURLs retrieves the short URLs with the given ids in a single query without counting clicks, ids that are
+// not short URLs are left out
+func (p *Storage) GetShortURLs(ctx context.Context, tenantID string, ids []string) ([]*shorturl.ShortURL, error) {
+	defer observeDuration("get_short_urls")()
+
+	query := "SELECT " + shortURLColumns + " FROM short_urls WHERE tenant_id = $1 AND id = ANY($2) AND deleted_at IS NULL"
+
+	rows, err := p.readDB.QueryContext(ctx, query, tenantID, ids)
+	if err != nil {
+		return nil, fmt.Errorf("executing get short URLs query: %w", err)
+	}
+	defer rows.Close()
+
+	shortURLs := make([]*shorturl.ShortURL, 0, len(ids))
+	for rows.Next() {
+		shortURL, err := p.scanShortURL(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning short URL: %w", err)
+		}
+
+		shortURLs = append(shortURLs, shortURL)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating short URLs: %w", err)
+	}
+
+	return shortURLs, nil
 }
 
-// DeleteShortURL deletes a short URL entry from the database by its id
-func (p *Storage) DeleteShortURL(ctx context.Context, id string) error {
-	_, err := p.db.ExecContext(ctx, "DELETE FROM short_urls WHERE id = $1", id)
+// GetShortURL retrieves the short URL for a given short URL id without counting a click
+func (p *Storage) GetShortURL(ctx context.Context, tenantID string, id string) (*shorturl.ShortURL, bool, error) {
+	defer observeDuration("get_short_url")()
 
-	return err
+	// Short URL creation checks for existing ids with it, so it must not lag behind writes
+	return p.getShortURL(ctx, p.db, tenantID, id)
 }
 
-// GetLongURL retrieves the long URL associated with a given short URL id
-func (p *Storage) GetLongURL(ctx context.Context, id string) (string, bool, error) {
-	var longURL string
-	err := p.db.QueryRowContext(ctx, "SELECT long_url FROM short_urls WHERE id = $1", id).Scan(&longURL)
+func (p *Storage) getShortURL(ctx context.Context, db *sql.DB, tenantID string, id string) (*shorturl.ShortURL, bool, error) {
+	query := "SELECT " + shortURLColumns + " FROM short_urls WHERE tenant_id = $1 AND id = $2 AND deleted_at IS NULL"
+
+	shortURL, err := p.scanShortURL(db.QueryRowContext(ctx, query, tenantID, id))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return "", false, nil
+			return nil, false, nil
+		}
+
+		return nil, false, err
+	}
+
+	return shortURL, true, nil
+}
+
+// ListShortURLs retrieves the short URLs matching the given filter, newest first
+func (p *Storage) ListShortURLs(ctx context.Context, tenantID string, filter *shorturl.ListFilter) ([]*shorturl.ShortURL, error) {
+	defer observeDuration("list_short_urls")()
+
+	query, args, err := p.listShortURLsQuery(tenantID, filter).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("building list short URLs query: %w", err)
+	}
+
+	rows, err := p.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("executing list short URLs query: %w", err)
+	}
+	defer rows.Close()
+
+	shortURLs := make([]*shorturl.ShortURL, 0)
+	for rows.Next() {
+		shortURL, err := p.scanShortURL(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning short URL: %w", err)
+		}
+
+		shortURLs = append(shortURLs, shortURL)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating short URLs: %w", err)
+	}
+
+	return shortURLs, nil
+}
+
+// StreamShortURLs sends the short URLs matching the given filter, newest first, on the returned short URL channel.
+// Rows are read from the cursor as they are received, so the table is never loaded into memory. Both channels are
+// closed once the rows are read, the error channel first receiving the error that stopped the stream if any. Callers
+// stopping early must cancel ctx.
+func (p *Storage) StreamShortURLs(ctx context.Context, tenantID string, filter *shorturl.ListFilter) (<-chan *shorturl.ShortURL, <-chan error) {
+	shortURLs := make(chan *shorturl.ShortURL)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(errs)
+		defer close(shortURLs)
+
+		if err := p.streamShortURLs(ctx, tenantID, filter, shortURLs); err != nil {
+			errs <- err
+		}
+	}()
+
+	return shortURLs, errs
+}
+
+func (p *Storage) streamShortURLs(ctx context.Context, tenantID string, filter *shorturl.ListFilter, shortURLs chan<- *shorturl.ShortURL) error {
+	defer observeDuration("stream_short_urls")()
+
+	query, args, err := p.listShortURLsQuery(tenantID, filter).ToSql()
+	if err != nil {
+		return fmt.Errorf("building stream short URLs query: %w", err)
+	}
+
+	rows, err := p.readDB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("executing stream short URLs query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		shortURL, err := p.scanShortURL(rows)
+		if err != nil {
+			return fmt.Errorf("scanning short URL: %w", err)
+		}
+
+		select {
+		case shortURLs <- shortURL:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterating short URLs: %w", err)
+	}
+
+	return nil
+}
+
+// ListShortURLsByOwner retrieves the short URLs created by the user with the given id, newest first
+func (p *Storage) ListShortURLsByOwner(ctx context.Context, tenantID string, userID string, limit, offset int) ([]*shorturl.ShortURL, error) {
+	defer observeDuration("list_short_urls_by_owner")()
+
+	query, args, err := p.listShortURLsQuery(tenantID, &shorturl.ListFilter{Limit: limit, Offset: offset}).
+		Where("created_by = ?", userID).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("building list short URLs by owner query: %w", err)
+	}
+
+	rows, err := p.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("executing list short URLs by owner query: %w", err)
+	}
+	defer rows.Close()
+
+	shortURLs := make([]*shorturl.ShortURL, 0)
+	for rows.Next() {
+		shortURL, err := p.scanShortURL(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning short URL: %w", err)
 		}
 
-		return "", false, err
+		shortURLs = append(shortURLs, shortURL)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating short URLs: %w", err)
+	}
+
+	return shortURLs, nil
+}
+
+func (p *Storage) listShortURLsQuery(tenantID string, filter *shorturl.ListFilter) squirrel.SelectBuilder {
+	queryBuilder := p.builder.
+		Select(shortURLColumns).
+		From("short_urls").
+		Where("tenant_id = ?", tenantID).
+		Where("deleted_at IS NULL").
+		OrderBy("created_at DESC", "id")
+
+	if filter.Tag != "" {
+		queryBuilder = queryBuilder.Where("? = ANY(tags)", filter.Tag)
+	}
+	for _, tag := range filter.Tags {
+		queryBuilder = queryBuilder.Where("? = ANY(tags)", tag)
+	}
+	if filter.Limit > 0 {
+		queryBuilder = queryBuilder.Limit(uint64(filter.Limit))
+	}
+	if filter.Offset > 0 {
+		queryBuilder = queryBuilder.Offset(uint64(filter.Offset))
+	}
+
+	return queryBuilder
+}
+
+// scanner is implemented by both *sql.Row and *sql.Rows
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+// scanShortURL scans a row selected with shortURLColumns
+func (p *Storage) scanShortURL(row scanner) (*shorturl.ShortURL, error) {
+	shortURL := &shorturl.ShortURL{}
+	var maxClicks sql.NullInt64
+	var passwordHash sql.NullString
+	var redirectCode sql.NullInt64
+	var expiresAt sql.NullTime
+	var cacheTTLSeconds sql.NullInt64
+	var createdBy sql.NullString
+	err := row.Scan(&shortURL.TenantId, &shortURL.Id, &shortURL.LongURL, p.typeMap.SQLScanner(&shortURL.Tags), &shortURL.Description, &maxClicks, &shortURL.ClickCount,
+		&passwordHash, &redirectCode, &shortURL.ForwardQueryParams, &expiresAt, &cacheTTLSeconds, &shortURL.ShowInterstitial, &createdBy, &shortURL.Status,
+		&shortURL.CreatedAt, &shortURL.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	shortURL.MaxClicks = int(maxClicks.Int64)
+	shortURL.PasswordHash = passwordHash.String
+	shortURL.RedirectCode = int(redirectCode.Int64)
+	shortURL.CacheTTLSeconds = int(cacheTTLSeconds.Int64)
+	shortURL.CreatedBy = createdBy.String
+	if expiresAt.Valid {
+		shortURL.ExpiresAt = &expiresAt.Time
 	}
 
-	return longURL, true, nil
+	return shortURL, nil
 }
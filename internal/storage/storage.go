@@ -2,15 +2,23 @@ package storage
 
 import (
 	"database/sql"
+	"errors"
 
 	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5/pgtype"
 	_ "github.com/jackc/pgx/v5/stdlib"
+
+	"github.com/AvalosM/short-url-service/internal/migration"
 )
 
 // Storage contains resources to interact with a database.
 type Storage struct {
-	db      *sql.DB
+	config *Config
+	db     *sql.DB
+	// readDB serves the reads that can lag behind writes, it is db itself when no read replica is configured
+	readDB  *sql.DB
 	builder squirrel.StatementBuilderType
+	typeMap *pgtype.Map
 }
 
 // NewStorage creates a new Storage
@@ -24,13 +32,64 @@ func NewStorage(config *Config) (*Storage, error) {
 		return nil, err
 	}
 
+	readDB := db
+	if config.ReadDataSourceName != "" {
+		readDB, err = sql.Open(config.Driver, config.ReadDataSourceName)
+		if err != nil {
+			return nil, errors.Join(err, db.Close())
+		}
+
+		if err := readDB.Ping(); err != nil {
+			return nil, errors.Join(err, readDB.Close(), db.Close())
+		}
+	}
+
 	return &Storage{
+		config:  config,
 		db:      db,
+		readDB:  readDB,
 		builder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+		typeMap: pgtype.NewMap(),
 	}, nil
 }
 
-// Healthy checks if the database connection is healthy.
+// Healthy checks if the database connections are healthy.
 func (p *Storage) Healthy() bool {
+	if p.readDB != p.db && p.readDB.Ping() != nil {
+		return false
+	}
+
 	return p.db.Ping() == nil
 }
+
+// Close closes the database connections, closing them again does nothing
+func (p *Storage) Close() error {
+	if p.readDB != p.db {
+		return errors.Join(p.readDB.Close(), p.db.Close())
+	}
+
+	return p.db.Close()
+}
+
+// Migrate applies all pending database migrations
+func (p *Storage) Migrate() error {
+	return p.withMigrator(func(m *migration.Migrator) error {
+		return m.Up()
+	})
+}
+
+// MigrateDown rolls back all applied database migrations
+func (p *Storage) MigrateDown() error {
+	return p.withMigrator(func(m *migration.Migrator) error {
+		return m.Down()
+	})
+}
+
+func (p *Storage) withMigrator(fn func(m *migration.Migrator) error) error {
+	migrator, err := migration.NewMigrator(p.config.Driver, p.config.DataSourceName, p.config.MigrationsPath)
+	if err != nil {
+		return err
+	}
+
+	return errors.Join(fn(migrator), migrator.Close())
+}
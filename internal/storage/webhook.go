@@ -0,0 +1,79 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/AvalosM/short-url-service/pkg/webhook"
+)
+
+// CreateWebhook creates a new webhook entry for a short URL of a tenant in the database. Registering the URL of an
+// existing webhook of the short URL again returns that webhook with its secret replaced instead of a duplicate.
+func (p *Storage) CreateWebhook(ctx context.Context, tenantID string, w *webhook.Webhook) (*webhook.Webhook, error) {
+	defer observeDuration("create_webhook")()
+
+	created := &webhook.Webhook{
+		ShortURLId: w.ShortURLId,
+		URL:        w.URL,
+		Secret:     w.Secret,
+	}
+
+	err := p.db.QueryRowContext(ctx,
+		`INSERT INTO short_url_webhooks (tenant_id, short_url_id, url, secret) VALUES ($1, $2, $3, $4)
+		 ON CONFLICT (tenant_id, short_url_id, url) DO UPDATE SET secret = EXCLUDED.secret
+		 RETURNING id, created_at`,
+		tenantID, w.ShortURLId, w.URL, w.Secret,
+	).Scan(&created.Id, &created.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("executing create webhook query: %w", err)
+	}
+
+	return created, nil
+}
+
+// DeleteWebhook deletes a webhook entry of a short URL of a tenant from the database, it reports whether the webhook existed
+func (p *Storage) DeleteWebhook(ctx context.Context, tenantID string, shortURLId string, id int64) (bool, error) {
+	defer observeDuration("delete_webhook")()
+
+	result, err := p.db.ExecContext(ctx,
+		"DELETE FROM short_url_webhooks WHERE id = $1 AND tenant_id = $2 AND short_url_id = $3", id, tenantID, shortURLId)
+	if err != nil {
+		return false, fmt.Errorf("executing delete webhook query: %w", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("getting deleted webhooks: %w", err)
+	}
+
+	return deleted > 0, nil
+}
+
+// GetWebhooks retrieves the webhooks of a short URL of a tenant
+func (p *Storage) GetWebhooks(ctx context.Context, tenantID string, shortURLId string) ([]*webhook.Webhook, error) {
+	defer observeDuration("get_webhooks")()
+
+	rows, err := p.db.QueryContext(ctx,
+		"SELECT id, short_url_id, url, secret, created_at FROM short_url_webhooks WHERE tenant_id = $1 AND short_url_id = $2 ORDER BY id",
+		tenantID, shortURLId,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("executing get webhooks query: %w", err)
+	}
+	defer rows.Close()
+
+	webhooks := make([]*webhook.Webhook, 0)
+	for rows.Next() {
+		w := &webhook.Webhook{}
+		if err := rows.Scan(&w.Id, &w.ShortURLId, &w.URL, &w.Secret, &w.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scanning webhook: %w", err)
+		}
+
+		webhooks = append(webhooks, w)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating webhooks: %w", err)
+	}
+
+	return webhooks, nil
+}
@@ -0,0 +1,23 @@
+package templates
+
+import (
+	"embed"
+	"html/template"
+)
+
+// InterstitialCountdownSeconds is how long the interstitial page is shown before redirecting
+const InterstitialCountdownSeconds = 5
+
+//go:embed *.html
+var files embed.FS
+
+// Interstitial renders the page shown before redirecting to the long URL of a short URL, with InterstitialData
+var Interstitial = template.Must(template.ParseFS(files, "interstitial.html"))
+
+// InterstitialData is the data of the Interstitial template
+type InterstitialData struct {
+	LongURL          string
+	CountdownSeconds int
+	// Clicks is the number of times the short URL was followed, it is not shown when 0
+	Clicks int64
+}
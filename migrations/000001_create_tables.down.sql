@@ -0,0 +1,2 @@
+drop table if exists short_url_metrics;
+drop table if exists short_urls;
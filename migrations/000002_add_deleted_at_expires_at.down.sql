@@ -0,0 +1,2 @@
+alter table short_urls drop column if exists expires_at;
+alter table short_urls drop column if exists deleted_at;
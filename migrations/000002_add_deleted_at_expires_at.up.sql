@@ -0,0 +1,2 @@
+alter table short_urls add column if not exists deleted_at timestamp;
+alter table short_urls add column if not exists expires_at timestamp;
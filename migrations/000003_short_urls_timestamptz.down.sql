@@ -0,0 +1,2 @@
+alter table short_urls alter column updated_at type timestamp using updated_at at time zone 'UTC';
+alter table short_urls alter column created_at type timestamp using created_at at time zone 'UTC';
@@ -0,0 +1 @@
+alter table short_url_metrics add column if not exists deleted_at timestamptz;
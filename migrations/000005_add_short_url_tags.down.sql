@@ -0,0 +1,3 @@
+drop index if exists idx_short_urls_tags;
+
+alter table short_urls drop column if exists tags;
@@ -0,0 +1,3 @@
+alter table short_urls add column if not exists tags text[] default '{}' not null;
+
+create index if not exists idx_short_urls_tags on short_urls using gin (tags);
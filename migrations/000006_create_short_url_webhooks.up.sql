@@ -0,0 +1,10 @@
+create table if not exists short_url_webhooks (
+    id bigserial primary key,
+    short_url_id varchar(6) not null references short_urls(id) on delete cascade,
+    url text not null,
+    secret text not null,
+
+    created_at timestamptz default now() not null
+);
+
+create index if not exists idx_short_url_webhooks_short_url_id on short_url_webhooks(short_url_id);
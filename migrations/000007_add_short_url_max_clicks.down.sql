@@ -0,0 +1,2 @@
+alter table short_urls drop column if exists click_count;
+alter table short_urls drop column if exists max_clicks;
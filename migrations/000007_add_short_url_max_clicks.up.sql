@@ -0,0 +1,2 @@
+alter table short_urls add column if not exists max_clicks integer;
+alter table short_urls add column if not exists click_count integer default 0 not null;
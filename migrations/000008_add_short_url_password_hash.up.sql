@@ -0,0 +1 @@
+alter table short_urls add column if not exists password_hash text;
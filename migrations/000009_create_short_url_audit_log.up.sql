@@ -0,0 +1,11 @@
+create table if not exists short_url_audit_log (
+    id bigserial primary key,
+    operation varchar(16) not null,
+    short_url_id varchar(6) not null,
+    actor varchar(255),
+    payload jsonb,
+
+    created_at timestamptz default now() not null
+);
+
+create index if not exists idx_short_url_audit_log_short_url_id on short_url_audit_log(short_url_id, created_at);
@@ -0,0 +1,21 @@
+-- fails if two tenants share a short URL id, they must be removed first
+drop index if exists idx_short_url_audit_log_tenant_id_short_url_id;
+create index if not exists idx_short_url_audit_log_short_url_id on short_url_audit_log(short_url_id, created_at);
+drop index if exists idx_short_url_webhooks_tenant_id_short_url_id;
+create index if not exists idx_short_url_webhooks_short_url_id on short_url_webhooks(short_url_id);
+drop index if exists idx_short_url_metrics_tenant_id_short_url_id_timestamp;
+create index if not exists idx_short_url_statistics_short_url_id_timestamp on short_url_metrics using btree (short_url_id, timestamp);
+
+alter table short_url_webhooks drop constraint if exists short_url_webhooks_short_url_id_fkey;
+alter table short_url_metrics drop constraint if exists short_url_metrics_short_url_id_fkey;
+alter table short_urls drop constraint if exists short_urls_pkey;
+alter table short_urls add constraint short_urls_pkey primary key (id);
+alter table short_url_metrics add constraint short_url_metrics_short_url_id_fkey
+    foreign key (short_url_id) references short_urls(id) on delete cascade;
+alter table short_url_webhooks add constraint short_url_webhooks_short_url_id_fkey
+    foreign key (short_url_id) references short_urls(id) on delete cascade;
+
+alter table short_url_audit_log drop column if exists tenant_id;
+alter table short_url_webhooks drop column if exists tenant_id;
+alter table short_url_metrics drop column if exists tenant_id;
+alter table short_urls drop column if exists tenant_id;
@@ -0,0 +1,21 @@
+alter table short_urls add column if not exists tenant_id varchar(64) default '' not null;
+alter table short_url_metrics add column if not exists tenant_id varchar(64) default '' not null;
+alter table short_url_webhooks add column if not exists tenant_id varchar(64) default '' not null;
+alter table short_url_audit_log add column if not exists tenant_id varchar(64) default '' not null;
+
+-- short URL ids are only unique within a tenant
+alter table short_url_metrics drop constraint if exists short_url_metrics_short_url_id_fkey;
+alter table short_url_webhooks drop constraint if exists short_url_webhooks_short_url_id_fkey;
+alter table short_urls drop constraint if exists short_urls_pkey;
+alter table short_urls add constraint short_urls_pkey primary key (tenant_id, id);
+alter table short_url_metrics add constraint short_url_metrics_short_url_id_fkey
+    foreign key (tenant_id, short_url_id) references short_urls(tenant_id, id) on delete cascade;
+alter table short_url_webhooks add constraint short_url_webhooks_short_url_id_fkey
+    foreign key (tenant_id, short_url_id) references short_urls(tenant_id, id) on delete cascade;
+
+drop index if exists idx_short_url_statistics_short_url_id_timestamp;
+create index if not exists idx_short_url_metrics_tenant_id_short_url_id_timestamp on short_url_metrics using btree (tenant_id, short_url_id, timestamp);
+drop index if exists idx_short_url_webhooks_short_url_id;
+create index if not exists idx_short_url_webhooks_tenant_id_short_url_id on short_url_webhooks(tenant_id, short_url_id);
+drop index if exists idx_short_url_audit_log_short_url_id;
+create index if not exists idx_short_url_audit_log_tenant_id_short_url_id on short_url_audit_log(tenant_id, short_url_id, created_at);
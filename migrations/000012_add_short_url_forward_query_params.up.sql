@@ -0,0 +1 @@
+alter table short_urls add column if not exists forward_query_params boolean default false not null;
@@ -0,0 +1 @@
+alter table short_urls alter column expires_at type timestamp using expires_at at time zone 'UTC';
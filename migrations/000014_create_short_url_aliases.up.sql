@@ -0,0 +1,12 @@
+create table if not exists short_url_aliases (
+    tenant_id varchar(64) default '' not null,
+    alias_id varchar(6) not null,
+    canonical_id varchar(6) not null,
+
+    created_at timestamptz default now() not null,
+
+    primary key (tenant_id, alias_id),
+    foreign key (tenant_id, canonical_id) references short_urls(tenant_id, id) on delete cascade
+);
+
+create index if not exists idx_short_url_aliases_tenant_id_canonical_id on short_url_aliases(tenant_id, canonical_id);
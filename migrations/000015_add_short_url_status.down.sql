@@ -0,0 +1,2 @@
+alter table short_urls drop constraint if exists short_urls_status_check;
+alter table short_urls drop column if exists status;
@@ -0,0 +1,2 @@
+alter table short_urls add column if not exists status varchar(16) default 'active' not null;
+alter table short_urls add constraint short_urls_status_check check (status in ('active', 'paused', 'archived'));
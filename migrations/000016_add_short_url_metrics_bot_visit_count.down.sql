@@ -0,0 +1 @@
+alter table short_url_metrics drop column if exists bot_visit_count;
@@ -0,0 +1 @@
+alter table short_url_metrics add column if not exists bot_visit_count bigint default 0 not null;
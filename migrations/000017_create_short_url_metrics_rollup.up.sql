@@ -0,0 +1,14 @@
+create table if not exists short_url_metrics_rollup (
+    tenant_id varchar(64) default '' not null,
+    short_url_id varchar(6) not null,
+    granularity varchar(16) not null,
+    visit_count bigint not null,
+    unique_visit_count bigint not null,
+    bot_visit_count bigint default 0 not null,
+    timestamp timestamp not null,
+    deleted_at timestamptz,
+
+    foreign key (tenant_id, short_url_id) references short_urls(tenant_id, id) on delete cascade
+);
+
+create index if not exists idx_short_url_metrics_rollup_tenant_id_short_url_id_timestamp on short_url_metrics_rollup using btree (tenant_id, short_url_id, timestamp);
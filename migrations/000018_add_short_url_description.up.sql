@@ -0,0 +1 @@
+alter table short_urls add column if not exists description varchar(512) default '' not null;
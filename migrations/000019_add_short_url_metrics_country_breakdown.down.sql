@@ -0,0 +1,2 @@
+alter table short_url_metrics_rollup drop column if exists country_breakdown;
+alter table short_url_metrics drop column if exists country_breakdown;
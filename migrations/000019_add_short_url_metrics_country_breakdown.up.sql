@@ -0,0 +1,2 @@
+alter table short_url_metrics add column if not exists country_breakdown jsonb default '{}' not null;
+alter table short_url_metrics_rollup add column if not exists country_breakdown jsonb default '{}' not null;
@@ -0,0 +1 @@
+drop table if exists short_url_latency;
@@ -0,0 +1,13 @@
+create table if not exists short_url_latency (
+    tenant_id varchar(64) default '' not null,
+    short_url_id varchar(6) not null,
+    request_count bigint not null,
+    p50_ns bigint not null,
+    p95_ns bigint not null,
+    p99_ns bigint not null,
+    timestamp timestamp not null,
+
+    foreign key (tenant_id, short_url_id) references short_urls(tenant_id, id) on delete cascade
+);
+
+create index if not exists idx_short_url_latency_tenant_id_short_url_id_timestamp on short_url_latency using btree (tenant_id, short_url_id, timestamp);
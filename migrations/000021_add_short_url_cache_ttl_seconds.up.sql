@@ -0,0 +1 @@
+alter table short_urls add column if not exists cache_ttl_seconds integer;
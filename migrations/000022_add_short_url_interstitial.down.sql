@@ -0,0 +1 @@
+alter table short_urls drop column if exists interstitial;
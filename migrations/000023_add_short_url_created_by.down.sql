@@ -0,0 +1,3 @@
+drop index if exists idx_short_urls_tenant_id_created_by;
+
+alter table short_urls drop column if exists created_by;
@@ -0,0 +1,3 @@
+alter table short_urls add column if not exists created_by varchar(128);
+
+create index if not exists idx_short_urls_tenant_id_created_by on short_urls using btree (tenant_id, created_by);
@@ -0,0 +1 @@
+alter table short_urls drop constraint if exists short_urls_long_url_length_check;
@@ -0,0 +1 @@
+alter table short_urls add constraint short_urls_long_url_length_check check (length(long_url) <= 2048);
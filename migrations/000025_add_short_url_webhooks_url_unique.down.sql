@@ -0,0 +1 @@
+drop index if exists idx_short_url_webhooks_tenant_id_short_url_id_url;
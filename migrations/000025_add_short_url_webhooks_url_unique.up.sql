@@ -0,0 +1,6 @@
+-- keeps the first of the webhooks registered more than once with the same URL
+delete from short_url_webhooks duplicate using short_url_webhooks original
+where duplicate.tenant_id = original.tenant_id and duplicate.short_url_id = original.short_url_id
+  and duplicate.url = original.url and duplicate.id > original.id;
+
+create unique index if not exists idx_short_url_webhooks_tenant_id_short_url_id_url on short_url_webhooks(tenant_id, short_url_id, url);
@@ -1 +0,0 @@
-drop table if exists short_urls;
\ No newline at end of file
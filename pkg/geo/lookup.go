@@ -0,0 +1,59 @@
+package geo
+
+import (
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/oschwald/maxminddb-golang"
+)
+
+// ErrInvalidIP is returned when the IP to look up cannot be parsed
+var ErrInvalidIP = errors.New("invalid IP")
+
+// record is the part of a GeoLite2 Country or City record the lookup reads
+type record struct {
+	Country struct {
+		ISOCode string `maxminddb:"iso_code"`
+	} `maxminddb:"country"`
+}
+
+// Lookup resolves the country of IPs with a local MaxMind GeoLite2 Country or City database
+type Lookup struct {
+	reader *maxminddb.Reader
+}
+
+// Open opens the MaxMind database at path, the returned lookup must be closed when it is no longer used
+func Open(path string) (*Lookup, error) {
+	reader, err := maxminddb.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening geo database: %w", err)
+	}
+
+	return &Lookup{reader: reader}, nil
+}
+
+// Country returns the ISO 3166-1 alpha-2 code of the country of an IP, with or without a port. An empty code is
+// returned for IPs that are not in the database.
+func (l *Lookup) Country(ip string) (string, error) {
+	if host, _, err := net.SplitHostPort(ip); err == nil {
+		ip = host
+	}
+
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return "", fmt.Errorf("%w: %q", ErrInvalidIP, ip)
+	}
+
+	var result record
+	if err := l.reader.Lookup(parsed, &result); err != nil {
+		return "", fmt.Errorf("looking up IP: %w", err)
+	}
+
+	return result.Country.ISOCode, nil
+}
+
+// Close closes the database
+func (l *Lookup) Close() error {
+	return l.reader.Close()
+}
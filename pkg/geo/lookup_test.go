@@ -0,0 +1,87 @@
+package geo_test
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/maxmind/mmdbwriter"
+	"github.com/maxmind/mmdbwriter/mmdbtype"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/AvalosM/short-url-service/pkg/geo"
+)
+
+type LookupSuite struct {
+	suite.Suite
+	lookup *geo.Lookup
+}
+
+func (suite *LookupSuite) SetupTest() {
+	tree, err := mmdbwriter.New(mmdbwriter.Options{DatabaseType: "GeoLite2-Country", RecordSize: 24})
+	suite.Require().NoError(err)
+
+	networks := map[string]string{
+		"81.2.69.0/24":   "GB",
+		"89.160.20.0/24": "SE",
+		"2001:218::/32":  "JP",
+	}
+	for network, isoCode := range networks {
+		_, ipNet, err := net.ParseCIDR(network)
+		suite.Require().NoError(err)
+
+		err = tree.Insert(ipNet, mmdbtype.Map{
+			"country": mmdbtype.Map{"iso_code": mmdbtype.String(isoCode)},
+		})
+		suite.Require().NoError(err)
+	}
+
+	path := filepath.Join(suite.T().TempDir(), "GeoLite2-Country.mmdb")
+	file, err := os.Create(path)
+	suite.Require().NoError(err)
+	_, err = tree.WriteTo(file)
+	suite.Require().NoError(err)
+	suite.Require().NoError(file.Close())
+
+	lookup, err := geo.Open(path)
+	suite.Require().NoError(err)
+
+	suite.lookup = lookup
+}
+
+func (suite *LookupSuite) TearDownTest() {
+	suite.NoError(suite.lookup.Close())
+}
+
+func TestLookupSuite(t *testing.T) {
+	suite.Run(t, new(LookupSuite))
+}
+
+func (suite *LookupSuite) TestCountrySuccess() {
+	testCases := map[string]string{
+		"81.2.69.160":           "GB",
+		"89.160.20.112:54321":   "SE",
+		"2001:218:85a3::1":      "JP",
+		"[2001:218:85a3::1]:80": "JP",
+		"1.1.1.1":               "",
+	}
+
+	for ip, expectedCountry := range testCases {
+		suite.Run(ip, func() {
+			country, err := suite.lookup.Country(ip)
+			suite.Require().NoError(err)
+			suite.Equal(expectedCountry, country)
+		})
+	}
+}
+
+func (suite *LookupSuite) TestCountryFailInvalidIP() {
+	_, err := suite.lookup.Country("not an ip")
+	suite.ErrorIs(err, geo.ErrInvalidIP)
+}
+
+func (suite *LookupSuite) TestOpenFailMissingDatabase() {
+	_, err := geo.Open(filepath.Join(suite.T().TempDir(), "missing.mmdb"))
+	suite.Error(err)
+}
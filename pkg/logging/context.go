@@ -0,0 +1,62 @@
+package logging
+
+import (
+	"context"
+	"slices"
+
+	"github.com/AvalosM/short-url-service/pkg/tenant"
+)
+
+// Logger is the logger FromContext adds request-scoped attributes to, *slog.Logger implements it
+type Logger interface {
+	Error(msg string, args ...interface{})
+	Info(msg string, args ...interface{})
+	Debug(msg string, args ...interface{})
+	Warn(msg string, args ...interface{})
+}
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying request-scoped log attributes as key value pairs, added to the ones ctx
+// already carries
+func NewContext(ctx context.Context, args ...any) context.Context {
+	contextArgs, _ := ctx.Value(contextKey{}).([]any)
+
+	return context.WithValue(ctx, contextKey{}, append(slices.Clip(contextArgs), args...))
+}
+
+// FromContext returns a logger adding the request-scoped attributes of ctx and the tenant ctx is scoped to, if any,
+// to the records of logger. Loggers of contexts without any are returned as is.
+func FromContext(ctx context.Context, logger Logger) Logger {
+	args, _ := ctx.Value(contextKey{}).([]any)
+	if tenantID := tenant.IDFromContext(ctx); tenantID != tenant.Default {
+		args = append(slices.Clip(args), TenantIdKey, tenantID)
+	}
+	if len(args) == 0 {
+		return logger
+	}
+
+	return &contextLogger{logger: logger, args: args}
+}
+
+// contextLogger adds request-scoped attributes after the attributes of every record
+type contextLogger struct {
+	logger Logger
+	args   []any
+}
+
+func (l *contextLogger) Error(msg string, args ...interface{}) {
+	l.logger.Error(msg, append(slices.Clip(args), l.args...)...)
+}
+
+func (l *contextLogger) Info(msg string, args ...interface{}) {
+	l.logger.Info(msg, append(slices.Clip(args), l.args...)...)
+}
+
+func (l *contextLogger) Debug(msg string, args ...interface{}) {
+	l.logger.Debug(msg, append(slices.Clip(args), l.args...)...)
+}
+
+func (l *contextLogger) Warn(msg string, args ...interface{}) {
+	l.logger.Warn(msg, append(slices.Clip(args), l.args...)...)
+}
@@ -0,0 +1,51 @@
+package logging_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/AvalosM/short-url-service/pkg/logging"
+	"github.com/AvalosM/short-url-service/pkg/tenant"
+)
+
+type ContextSuite struct {
+	suite.Suite
+	logs   *bytes.Buffer
+	logger *slog.Logger
+}
+
+func (suite *ContextSuite) SetupTest() {
+	suite.logs = &bytes.Buffer{}
+	suite.logger = slog.New(slog.NewTextHandler(suite.logs, nil))
+}
+
+func TestContextSuite(t *testing.T) {
+	suite.Run(t, new(ContextSuite))
+}
+
+func (suite *ContextSuite) TestFromContext() {
+	ctx := logging.NewContext(context.Background(), logging.RequestIdKey, "request-1")
+	ctx = logging.NewContext(tenant.WithID(ctx, "acme"), logging.SectionKey, "redirect")
+
+	logging.FromContext(ctx, suite.logger).Info("short URL found", logging.ShortURLIdKey, "AABBCC")
+
+	suite.Contains(suite.logs.String(), "msg=\"short URL found\" shortURLId=AABBCC requestId=request-1 section=redirect tenantId=acme")
+}
+
+func (suite *ContextSuite) TestFromContextKeepsParentAttributes() {
+	parent := logging.NewContext(context.Background(), logging.RequestIdKey, "request-1")
+	_ = logging.NewContext(parent, logging.SectionKey, "redirect")
+
+	logging.FromContext(parent, suite.logger).Info("short URL found")
+
+	suite.Contains(suite.logs.String(), "requestId=request-1")
+	suite.NotContains(suite.logs.String(), "section")
+}
+
+func (suite *ContextSuite) TestFromContextWithoutAttributes() {
+	suite.Same(suite.logger, logging.FromContext(context.Background(), suite.logger))
+}
@@ -0,0 +1,133 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"maps"
+	"sync/atomic"
+)
+
+// PackageLevels are the minimum levels of the records of each package, they can be changed while in use
+type PackageLevels struct {
+	levels atomic.Pointer[map[string]slog.Level]
+}
+
+// NewPackageLevels creates package levels with the given minimum level of each package
+func NewPackageLevels(levels map[string]slog.Level) *PackageLevels {
+	packageLevels := &PackageLevels{}
+	packageLevels.Set(levels)
+
+	return packageLevels
+}
+
+// Set replaces the minimum levels of every package, packages left out go back to the default level
+func (l *PackageLevels) Set(levels map[string]slog.Level) {
+	levels = maps.Clone(levels)
+	l.levels.Store(&levels)
+}
+
+// Level returns the minimum level of the package and whether it has one
+func (l *PackageLevels) Level(pkg string) (slog.Level, bool) {
+	level, ok := (*l.levels.Load())[pkg]
+
+	return level, ok
+}
+
+// min returns the lowest level of any package, or level if it is lower
+func (l *PackageLevels) min(level slog.Level) slog.Level {
+	for _, packageLevel := range *l.levels.Load() {
+		level = min(level, packageLevel)
+	}
+
+	return level
+}
+
+// PackageLevelHandler drops the records below the level of the package they are logged from, named by their
+// PackageKey attribute, and passes the others to the next handler. Records of packages without a level of their own
+// are filtered by the default level. The next handler must enable every level the packages can log at.
+type PackageLevelHandler struct {
+	next          slog.Handler
+	defaultLevel  slog.Leveler
+	packageLevels *PackageLevels
+	// pkg is the package of the attributes added with WithAttrs, empty if none was
+	pkg string
+}
+
+// NewPackageLevelHandler creates a handler filtering the records passed to next by package
+func NewPackageLevelHandler(next slog.Handler, defaultLevel slog.Leveler, packageLevels *PackageLevels) *PackageLevelHandler {
+	return &PackageLevelHandler{
+		next:          next,
+		defaultLevel:  defaultLevel,
+		packageLevels: packageLevels,
+	}
+}
+
+// Enabled reports whether records of the level can be logged, records naming their package themselves are checked
+// again once their attributes are known
+func (h *PackageLevelHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	minLevel := h.level(h.pkg)
+	if h.pkg == "" {
+		minLevel = h.packageLevels.min(minLevel)
+	}
+
+	return level >= minLevel && h.next.Enabled(ctx, level)
+}
+
+// Handle passes the record to the next handler unless it is below the level of its package
+func (h *PackageLevelHandler) Handle(ctx context.Context, record slog.Record) error {
+	pkg := h.pkg
+	if pkg == "" {
+		record.Attrs(func(attr slog.Attr) bool {
+			if attr.Key == PackageKey {
+				pkg = attr.Value.String()
+
+				return false
+			}
+
+			return true
+		})
+	}
+
+	if record.Level < h.level(pkg) {
+		return nil
+	}
+
+	return h.next.Handle(ctx, record)
+}
+
+// WithAttrs returns a handler with the attributes added, a PackageKey attribute sets the package of its records
+func (h *PackageLevelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	handler := *h
+	handler.next = h.next.WithAttrs(attrs)
+	for _, attr := range attrs {
+		if attr.Key == PackageKey {
+			handler.pkg = attr.Value.String()
+		}
+	}
+
+	return &handler
+}
+
+// WithGroup returns a handler with the group added, the package of its records is kept
+func (h *PackageLevelHandler) WithGroup(name string) slog.Handler {
+	handler := *h
+	handler.next = h.next.WithGroup(name)
+
+	return &handler
+}
+
+func (h *PackageLevelHandler) level(pkg string) slog.Level {
+	if pkg != "" {
+		if level, ok := h.packageLevels.Level(pkg); ok {
+			return level
+		}
+	}
+
+	return h.defaultLevel.Level()
+}
+
+// NewPackageLogger returns a logger of parent that names pkg as the package of its records, so a
+// PackageLevelHandler filters them by the level of pkg
+func NewPackageLogger(parent *slog.Logger, pkg string) *slog.Logger {
+	return parent.With(PackageKey, pkg)
+}
@@ -0,0 +1,86 @@
+package logging_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/AvalosM/short-url-service/pkg/logging"
+)
+
+type PackageLevelHandlerSuite struct {
+	suite.Suite
+	logs          *bytes.Buffer
+	level         *slog.LevelVar
+	packageLevels *logging.PackageLevels
+	logger        *slog.Logger
+}
+
+func (suite *PackageLevelHandlerSuite) SetupTest() {
+	suite.logs = &bytes.Buffer{}
+	suite.level = &slog.LevelVar{}
+	suite.packageLevels = logging.NewPackageLevels(map[string]slog.Level{
+		"metrics":  slog.LevelWarn,
+		"handlers": slog.LevelDebug,
+	})
+
+	next := slog.NewTextHandler(suite.logs, &slog.HandlerOptions{Level: slog.LevelDebug})
+	suite.logger = slog.New(logging.NewPackageLevelHandler(next, suite.level, suite.packageLevels))
+}
+
+func TestPackageLevelHandlerSuite(t *testing.T) {
+	suite.Run(t, new(PackageLevelHandlerSuite))
+}
+
+func (suite *PackageLevelHandlerSuite) TestPackageLevels() {
+	metricsLogger := logging.NewPackageLogger(suite.logger, "metrics")
+	metricsLogger.Info("metrics info")
+	metricsLogger.Warn("metrics warn")
+
+	handlersLogger := logging.NewPackageLogger(suite.logger, "handlers")
+	handlersLogger.Debug("handlers debug")
+
+	suite.NotContains(suite.logs.String(), "metrics info")
+	suite.Contains(suite.logs.String(), "metrics warn")
+	suite.Contains(suite.logs.String(), "pkg=metrics")
+	suite.Contains(suite.logs.String(), "handlers debug")
+}
+
+func (suite *PackageLevelHandlerSuite) TestDefaultLevel() {
+	suite.logger.Debug("default debug")
+	suite.logger.Info("default info")
+	logging.NewPackageLogger(suite.logger, "shorturl").Debug("shorturl debug")
+
+	suite.NotContains(suite.logs.String(), "default debug")
+	suite.Contains(suite.logs.String(), "default info")
+	suite.NotContains(suite.logs.String(), "shorturl debug")
+
+	suite.level.Set(slog.LevelDebug)
+	logging.NewPackageLogger(suite.logger, "shorturl").Debug("shorturl debug")
+	suite.Contains(suite.logs.String(), "shorturl debug")
+}
+
+func (suite *PackageLevelHandlerSuite) TestPackageAttributeOfRecord() {
+	suite.logger.Info("metrics info", logging.PackageKey, "metrics")
+	suite.logger.Debug("handlers debug", logging.PackageKey, "handlers")
+
+	suite.NotContains(suite.logs.String(), "metrics info")
+	suite.Contains(suite.logs.String(), "handlers debug")
+}
+
+func (suite *PackageLevelHandlerSuite) TestSetPackageLevels() {
+	metricsLogger := logging.NewPackageLogger(suite.logger, "metrics").WithGroup("request")
+	suite.False(metricsLogger.Enabled(context.Background(), slog.LevelInfo))
+
+	suite.packageLevels.Set(map[string]slog.Level{"metrics": slog.LevelInfo})
+	metricsLogger.Info("metrics info")
+	suite.Contains(suite.logs.String(), "metrics info")
+
+	// Packages left out go back to the default level
+	suite.packageLevels.Set(nil)
+	logging.NewPackageLogger(suite.logger, "handlers").Debug("handlers debug")
+	suite.NotContains(suite.logs.String(), "handlers debug")
+}
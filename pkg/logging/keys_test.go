@@ -0,0 +1,115 @@
+package logging_test
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"log/slog"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/AvalosM/short-url-service/pkg/logging"
+)
+
+// moduleRoot is the root of the module, relative to this package
+const moduleRoot = "../.."
+
+// logMethods are the slog.Logger methods taking a message followed by key-value pairs
+var logMethods = map[string]bool{"Debug": true, "Info": true, "Warn": true, "Error": true}
+
+type LogKeysSuite struct {
+	suite.Suite
+}
+
+func TestLogKeysSuite(t *testing.T) {
+	suite.Run(t, new(LogKeysSuite))
+}
+
+func (suite *LogKeysSuite) TestAttr() {
+	attr := logging.Attr(logging.TenantIdKey, "acme")
+	suite.Equal("tenantId", attr.Key)
+	suite.Equal(slog.StringValue("acme"), attr.Value)
+}
+
+// TestLogCallsUseKeyConstants checks that the log calls of the module never use raw strings as keys
+func (suite *LogKeysSuite) TestLogCallsUseKeyConstants() {
+	fset := token.NewFileSet()
+	var violations []string
+
+	err := filepath.WalkDir(moduleRoot, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if strings.HasPrefix(entry.Name(), ".") && path != moduleRoot {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+		if !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+
+		file, err := parser.ParseFile(fset, path, nil, 0)
+		if err != nil {
+			return err
+		}
+		for _, call := range rawKeyLogCalls(file) {
+			violations = append(violations, fset.Position(call.Pos()).String())
+		}
+
+		return nil
+	})
+	suite.Require().NoError(err)
+	suite.Empty(violations, "log calls must use the key constants of pkg/logging")
+}
+
+// rawKeyLogCalls returns the log calls of file with a string literal as a key. Functions of imported packages other
+// than log/slog, like http.Error, are not log calls.
+func rawKeyLogCalls(file *ast.File) []*ast.CallExpr {
+	packages := make(map[string]string, len(file.Imports))
+	for _, spec := range file.Imports {
+		path, _ := strconv.Unquote(spec.Path.Value)
+		name := path[strings.LastIndex(path, "/")+1:]
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		packages[name] = path
+	}
+
+	var calls []*ast.CallExpr
+	ast.Inspect(file, func(node ast.Node) bool {
+		call, ok := node.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		selector, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || !logMethods[selector.Sel.Name] {
+			return true
+		}
+		if ident, ok := selector.X.(*ast.Ident); ok {
+			if path, imported := packages[ident.Name]; imported && path != "log/slog" {
+				return true
+			}
+		}
+
+		// The message is followed by alternating keys and values
+		for i := 1; i < len(call.Args); i += 2 {
+			if literal, ok := call.Args[i].(*ast.BasicLit); ok && literal.Kind == token.STRING {
+				calls = append(calls, call)
+
+				break
+			}
+		}
+
+		return true
+	})
+
+	return calls
+}
@@ -1,7 +1,44 @@
 package logging
 
+import "log/slog"
+
+// Keys of the attributes of log records, they are used instead of raw strings so the same field is always logged
+// under the same key
 const (
-	ErrorKey      = "error"
-	ShortURLIdKey = "shortURLId"
-	LongURLKey    = "longURL"
+	ErrorKey           = "error"
+	ShortURLIdKey      = "shortURLId"
+	ShortURLIdsKey     = "shortURLIds"
+	LongURLKey         = "longURL"
+	DroppedRequestsKey = "droppedRequests"
+	PanicKey           = "panic"
+	StackKey           = "stack"
+	WebhookIdKey       = "webhookId"
+	DeletedKey         = "deleted"
+	BaseURLKey         = "baseURL"
+	AliasIdKey         = "aliasId"
+	StatusKey          = "status"
+	MethodKey          = "method"
+	PathKey            = "path"
+	LatencyMSKey       = "latencyMS"
+	RequestIdKey       = "requestId"
+	RemoteIPKey        = "remoteIP"
+	IntervalKey        = "interval"
+	SectionKey         = "section"
+	PackageKey         = "pkg"
+	TenantIdKey        = "tenantId"
+	WarmedKey          = "warmed"
+	CollisionRateKey   = "collisionRate"
+	UserIdKey          = "userId"
+	RedirectCodeKey    = "redirectCode"
+	TagsKey            = "tags"
+	LatencyKey         = "latency"
+	OperationKey       = "operation"
+	PortKey            = "port"
+	TLSKey             = "tls"
+	HTTP2Key           = "http2"
 )
+
+// Attr returns an attribute of a log record with one of the keys above
+func Attr(key string, value any) slog.Attr {
+	return slog.Any(key, value)
+}
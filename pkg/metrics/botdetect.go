@@ -0,0 +1,82 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// DefaultBotUASubstrings are the User-Agent substrings of common crawlers, used when no bot UA file is configured
+var DefaultBotUASubstrings = []string{
+	"bot",
+	"crawler",
+	"spider",
+	"slurp",
+	"facebookexternalhit",
+	"embedly",
+	"quora link preview",
+	"whatsapp",
+	"headlesschrome",
+	"python-requests",
+	"go-http-client",
+}
+
+// UserAgentBotDetector identifies bots by substrings of their User-Agent, matched case-insensitively
+type UserAgentBotDetector struct {
+	substrings []string
+}
+
+// NewUserAgentBotDetector creates a bot detector matching the given User-Agent substrings
+func NewUserAgentBotDetector(substrings []string) *UserAgentBotDetector {
+	lowered := make([]string, 0, len(substrings))
+	for _, substring := range substrings {
+		if substring = strings.ToLower(strings.TrimSpace(substring)); substring != "" {
+			lowered = append(lowered, substring)
+		}
+	}
+
+	return &UserAgentBotDetector{substrings: lowered}
+}
+
+// NewBotDetector creates a bot detector matching the User-Agent substrings of botUAFile, one per line with blank lines
+// and lines starting with # ignored. DefaultBotUASubstrings are matched when botUAFile is empty.
+func NewBotDetector(botUAFile string) (*UserAgentBotDetector, error) {
+	if botUAFile == "" {
+		return NewUserAgentBotDetector(DefaultBotUASubstrings), nil
+	}
+
+	file, err := os.Open(botUAFile)
+	if err != nil {
+		return nil, fmt.Errorf("opening bot UA file: %w", err)
+	}
+	defer file.Close()
+
+	var substrings []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		substrings = append(substrings, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading bot UA file: %w", err)
+	}
+
+	return NewUserAgentBotDetector(substrings), nil
+}
+
+// IsBot reports whether the User-Agent contains any of the bot substrings
+func (d *UserAgentBotDetector) IsBot(userAgent string) bool {
+	userAgent = strings.ToLower(userAgent)
+	for _, substring := range d.substrings {
+		if strings.Contains(userAgent, substring) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,62 @@
+package metrics_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/AvalosM/short-url-service/pkg/metrics"
+	"github.com/stretchr/testify/suite"
+)
+
+type BotDetectorSuite struct {
+	suite.Suite
+}
+
+func TestBotDetectorSuite(t *testing.T) {
+	suite.Run(t, new(BotDetectorSuite))
+}
+
+func (suite *BotDetectorSuite) TestIsBotDefaultSubstrings() {
+	detector, err := metrics.NewBotDetector("")
+	suite.Require().NoError(err)
+
+	crawlers := []string{
+		"Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)",
+		"Mozilla/5.0 (compatible; bingbot/2.0; +http://www.bing.com/bingbot.htm)",
+		"facebookexternalhit/1.1 (+http://www.facebook.com/externalhit_uatext.php)",
+		"Mozilla/5.0 (compatible; Yahoo! Slurp; http://help.yahoo.com/help/us/ysearch/slurp)",
+		"Mozilla/5.0 (compatible; Baiduspider/2.0; +http://www.baidu.com/search/spider.html)",
+		"python-requests/2.31.0",
+	}
+	for _, userAgent := range crawlers {
+		suite.True(detector.IsBot(userAgent), userAgent)
+	}
+
+	browsers := []string{
+		"Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0.0.0 Safari/537.36",
+		"Mozilla/5.0 (iPhone; CPU iPhone OS 17_5 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.5 Mobile/15E148 Safari/604.1",
+		"",
+	}
+	for _, userAgent := range browsers {
+		suite.False(detector.IsBot(userAgent), userAgent)
+	}
+}
+
+func (suite *BotDetectorSuite) TestIsBotFileSubstrings() {
+	botUAFile := filepath.Join(suite.T().TempDir(), "bots.txt")
+	suite.Require().NoError(os.WriteFile(botUAFile, []byte("# internal monitors\n\nUptimeChecker\n  LinkAudit  \n"), 0o600))
+
+	detector, err := metrics.NewBotDetector(botUAFile)
+	suite.Require().NoError(err)
+
+	suite.True(detector.IsBot("uptimechecker/1.0"))
+	suite.True(detector.IsBot("Mozilla/5.0 (compatible; LinkAudit/3.2)"))
+	suite.False(detector.IsBot("Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)"))
+	suite.False(detector.IsBot("# internal monitors"))
+}
+
+func (suite *BotDetectorSuite) TestNewBotDetectorFailMissingFile() {
+	_, err := metrics.NewBotDetector(filepath.Join(suite.T().TempDir(), "missing.txt"))
+	suite.Error(err)
+}
@@ -0,0 +1,74 @@
+package metrics
+
+import (
+	"sync"
+	"time"
+)
+
+// SafeCollector guards the Collector of a short URL so it can be read while requests are being recorded to it
+type SafeCollector struct {
+	mu        sync.RWMutex
+	collector *Collector
+}
+
+// NewSafeCollector creates an empty collector of a short URL of a tenant
+func NewSafeCollector(tenantID string, shortURLId string) *SafeCollector {
+	return &SafeCollector{
+		collector: &Collector{
+			TenantId:   tenantID,
+			ShortURLId: shortURLId,
+			Visitors:   make(map[string]time.Time),
+		},
+	}
+}
+
+// Record counts a request as a visit of its visitor, or as a bot visit for requests of bots
+func (c *SafeCollector) Record(request Request) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if request.IsBot {
+		c.collector.BotVisits++
+
+		return
+	}
+
+	c.collector.Visits++
+	c.collector.Visitors[request.VisitorId] = request.Timestamp
+
+	if request.Country != "" {
+		if c.collector.CountryBreakdown == nil {
+			c.collector.CountryBreakdown = make(map[string]int64)
+		}
+		c.collector.CountryBreakdown[request.Country]++
+	}
+}
+
+// Snapshot returns a copy of the metrics collected
+func (c *SafeCollector) Snapshot() CollectorSnapshot {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return CollectorSnapshot{
+		ShortURLId:   c.collector.ShortURLId,
+		Visits:       c.collector.Visits,
+		UniqueVisits: c.collector.UniqueVisits(),
+		BotVisits:    c.collector.BotVisits,
+	}
+}
+
+// Collector returns the guarded collector, it must only be used once no more requests are recorded to c
+func (c *SafeCollector) Collector() *Collector {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.collector
+}
+
+// Reset zeroes the metrics collected
+func (c *SafeCollector) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.collector.Reset()
+}
@@ -1,20 +1,89 @@
 package metrics
 
-import "errors"
+import (
+	"errors"
+	"slices"
+)
+
+// Metrics storage drivers, metrics are stored along short URLs by default
+const (
+	DriverPostgres = "postgres"
+	DriverInfluxDB = "influxdb"
+)
+
+// RollupGranularities are the periods metric rows can be rolled up by
+var RollupGranularities = []string{"hour", "day", "week", "month"}
 
 // Config holds the configuration for the metrics manager
 type Config struct {
-	MetricsIntervalInMS      int `json:"metrics_interval_in_ms"`
-	RequestChannelSize       int `json:"record_channel_size"`
-	RecordRequestTimeoutInMS int `json:"record_request_timeout_in_ms"`
+	MetricsIntervalInMS int `json:"metrics_interval_in_ms"`
+	// MinMetricsIntervalInMS is the shortest interval metrics are flushed at, the interval is halved down to it while
+	// the request channel is more than 80% full and restored once it is less than 20% full
+	MinMetricsIntervalInMS     int `json:"min_metrics_interval_in_ms"`
+	RequestChannelSize         int `json:"record_channel_size"`
+	RecordRequestTimeoutInMS   int `json:"record_request_timeout_in_ms"`
+	MaxFlushContextTimeoutInMS int `json:"max_flush_context_timeout_in_ms"`
+	// MetricsIntervalJitterInMS delays the first flush by a random duration up to this value, so that
+	// instances started together do not flush to storage at the same time
+	MetricsIntervalJitterInMS int `json:"metrics_interval_jitter_in_ms"`
+	// AnonymizeIPs truncates visitor IPs before they are collected, see AnonymizeIP
+	AnonymizeIPs bool `json:"anonymize_ips"`
+	// VisitWindowInSeconds counts one visit per visitor IP and short URL within this window, repeated requests made
+	// before it passes since the visitor was last seen are not counted. 0 counts every request.
+	VisitWindowInSeconds int `json:"visit_window_in_seconds"`
+	// BotUAFile lists the User-Agent substrings identifying bots, see NewBotDetector. Empty uses DefaultBotUASubstrings.
+	BotUAFile string `json:"bot_ua_file"`
+	// RollupIntervalInHours is how often metric rows of past periods are rolled up into one row per short URL and period
+	RollupIntervalInHours int `json:"rollup_interval_in_hours"`
+	// RollupGranularity is the period metric rows are rolled up by, one of RollupGranularities
+	RollupGranularity string `json:"rollup_granularity"`
+	// GeoDBPath is the MaxMind GeoLite2 Country or City database visitor countries are looked up in, countries are not
+	// collected when it is empty
+	GeoDBPath string `json:"geo_db_path"`
+	// Driver is the storage metrics are written to, DriverPostgres or DriverInfluxDB
+	Driver string `json:"driver"`
+	// InfluxDB is the InfluxDB metrics are written to when Driver is DriverInfluxDB
+	InfluxDB *InfluxDBConfig `json:"influxdb"`
+}
+
+// InfluxDBConfig holds the connection to the InfluxDB bucket metrics are written to
+type InfluxDBConfig struct {
+	URL    string `json:"url"`
+	Token  string `json:"token"`
+	Org    string `json:"org"`
+	Bucket string `json:"bucket"`
+}
+
+// Validate checks if the InfluxDB configuration is valid
+func (c *InfluxDBConfig) Validate() error {
+	if c.URL == "" {
+		return errors.New("InfluxDB URL cannot be empty")
+	}
+	if c.Org == "" {
+		return errors.New("InfluxDB org cannot be empty")
+	}
+	if c.Bucket == "" {
+		return errors.New("InfluxDB bucket cannot be empty")
+	}
+	return nil
 }
 
 // DefaultConfig returns the default configuration for the metrics manager
 func DefaultConfig() *Config {
 	return &Config{
-		MetricsIntervalInMS:      1000,
-		RequestChannelSize:       1000,
-		RecordRequestTimeoutInMS: 100,
+		MetricsIntervalInMS:        1000,
+		MinMetricsIntervalInMS:     50,
+		RequestChannelSize:         1000,
+		RecordRequestTimeoutInMS:   100,
+		MaxFlushContextTimeoutInMS: 5000,
+		MetricsIntervalJitterInMS:  0,
+		AnonymizeIPs:               false,
+		VisitWindowInSeconds:       0,
+		BotUAFile:                  "",
+		RollupIntervalInHours:      24,
+		RollupGranularity:          "day",
+		GeoDBPath:                  "",
+		Driver:                     DriverPostgres,
 	}
 }
 
@@ -23,11 +92,41 @@ func (c *Config) Validate() error {
 	if c.MetricsIntervalInMS <= 0 {
 		return errors.New("MetricsIntervalInMS must be greater than 0")
 	}
+	if c.MinMetricsIntervalInMS <= 0 || c.MinMetricsIntervalInMS > c.MetricsIntervalInMS {
+		return errors.New("MinMetricsIntervalInMS must be greater than 0 and at most MetricsIntervalInMS")
+	}
 	if c.RequestChannelSize <= 0 {
 		return errors.New("RequestChannelSize must be greater than 0")
 	}
 	if c.RecordRequestTimeoutInMS <= 0 {
 		return errors.New("RecordRequestTimeoutInMS must be greater than 0")
 	}
+	if c.MaxFlushContextTimeoutInMS <= 0 {
+		return errors.New("MaxFlushContextTimeoutInMS must be greater than 0")
+	}
+	if c.MetricsIntervalJitterInMS < 0 {
+		return errors.New("MetricsIntervalJitterInMS must be greater than or equal to 0")
+	}
+	if c.VisitWindowInSeconds < 0 {
+		return errors.New("VisitWindowInSeconds must be greater than or equal to 0")
+	}
+	if c.RollupIntervalInHours <= 0 {
+		return errors.New("RollupIntervalInHours must be greater than 0")
+	}
+	if !slices.Contains(RollupGranularities, c.RollupGranularity) {
+		return errors.New("RollupGranularity must be one of hour, day, week or month")
+	}
+	switch c.Driver {
+	case DriverPostgres:
+	case DriverInfluxDB:
+		if c.InfluxDB == nil {
+			return errors.New("InfluxDB must be set when Driver is influxdb")
+		}
+		if err := c.InfluxDB.Validate(); err != nil {
+			return err
+		}
+	default:
+		return errors.New("Driver must be postgres or influxdb")
+	}
 	return nil
 }
@@ -0,0 +1,24 @@
+package metrics
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrManagerStopped = errors.New("metrics manager is stopped")
+	// ErrRecordRequestTimeout is reported when a request is dropped because the request channel stayed full
+	ErrRecordRequestTimeout = errors.New("timeout while recording short URL request")
+	// ErrMissingShortURL is returned by Storage.CreateMetrics when a short URL of the collectors does not exist
+	ErrMissingShortURL = errors.New("short URL of the metrics does not exist")
+)
+
+// SkippedMetricsError is returned by Storage.CreateMetrics when the metrics of some short URLs were not created
+// because the short URLs do not exist, the metrics of the others were created
+type SkippedMetricsError struct {
+	Keys []CollectorKey
+}
+
+func (e *SkippedMetricsError) Error() string {
+	return fmt.Sprintf("skipped the metrics of %d short URLs that do not exist", len(e.Keys))
+}
@@ -0,0 +1,55 @@
+package metrics
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/AvalosM/short-url-service/pkg/tenant"
+)
+
+// eventBufferSize is the number of click events kept for a subscriber that is not keeping up, further events are
+// dropped until it catches up
+const eventBufferSize = 64
+
+// Event is a click of a short URL, as it is collected
+type Event struct {
+	ShortURLId string
+	// IP is the visitor IP, anonymized when the manager anonymizes IPs
+	IP        string
+	Timestamp time.Time
+}
+
+// subscribers are the click event channels subscribed to the short URLs, keyed by channel
+type subscribers struct {
+	channels sync.Map
+}
+
+// publish sends the event to the subscribers of its short URL without waiting for them, subscribers with a full
+// buffer miss it
+func (s *subscribers) publish(key CollectorKey, event Event) {
+	s.channels.Range(func(channel, subscribed any) bool {
+		if subscribed.(CollectorKey) != key {
+			return true
+		}
+
+		select {
+		case channel.(chan Event) <- event:
+		default:
+		}
+
+		return true
+	})
+}
+
+// Subscribe returns the click events of the short URL of the tenant of ctx collected from now on, until the returned
+// unsubscribe function is called. The channel is not closed when unsubscribing, a subscriber that falls behind misses
+// events.
+func (m *Manager) Subscribe(ctx context.Context, id string) (<-chan Event, func()) {
+	events := make(chan Event, eventBufferSize)
+	m.subscribers.channels.Store(events, CollectorKey{TenantId: tenant.IDFromContext(ctx), ShortURLId: id})
+
+	return events, func() {
+		m.subscribers.channels.Delete(events)
+	}
+}
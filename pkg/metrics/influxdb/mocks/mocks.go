@@ -0,0 +1,149 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./storage.go
+//
+// Generated by this command:
+//
+//	mockgen -typed -package=mocks -source=./storage.go -destination=./mocks/mocks.go
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	api "github.com/influxdata/influxdb-client-go/v2/api"
+	write "github.com/influxdata/influxdb-client-go/v2/api/write"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockWriteAPI is a mock of WriteAPI interface.
+type MockWriteAPI struct {
+	ctrl     *gomock.Controller
+	recorder *MockWriteAPIMockRecorder
+	isgomock struct{}
+}
+
+// MockWriteAPIMockRecorder is the mock recorder for MockWriteAPI.
+type MockWriteAPIMockRecorder struct {
+	mock *MockWriteAPI
+}
+
+// NewMockWriteAPI creates a new mock instance.
+func NewMockWriteAPI(ctrl *gomock.Controller) *MockWriteAPI {
+	mock := &MockWriteAPI{ctrl: ctrl}
+	mock.recorder = &MockWriteAPIMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWriteAPI) EXPECT() *MockWriteAPIMockRecorder {
+	return m.recorder
+}
+
+// WritePoint mocks base method.
+func (m *MockWriteAPI) WritePoint(ctx context.Context, point ...*write.Point) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx}
+	for _, a := range point {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "WritePoint", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WritePoint indicates an expected call of WritePoint.
+func (mr *MockWriteAPIMockRecorder) WritePoint(ctx any, point ...any) *MockWriteAPIWritePointCall {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx}, point...)
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WritePoint", reflect.TypeOf((*MockWriteAPI)(nil).WritePoint), varargs...)
+	return &MockWriteAPIWritePointCall{Call: call}
+}
+
+// MockWriteAPIWritePointCall wrap *gomock.Call
+type MockWriteAPIWritePointCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockWriteAPIWritePointCall) Return(arg0 error) *MockWriteAPIWritePointCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockWriteAPIWritePointCall) Do(f func(context.Context, ...*write.Point) error) *MockWriteAPIWritePointCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockWriteAPIWritePointCall) DoAndReturn(f func(context.Context, ...*write.Point) error) *MockWriteAPIWritePointCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// MockQueryAPI is a mock of QueryAPI interface.
+type MockQueryAPI struct {
+	ctrl     *gomock.Controller
+	recorder *MockQueryAPIMockRecorder
+	isgomock struct{}
+}
+
+// MockQueryAPIMockRecorder is the mock recorder for MockQueryAPI.
+type MockQueryAPIMockRecorder struct {
+	mock *MockQueryAPI
+}
+
+// NewMockQueryAPI creates a new mock instance.
+func NewMockQueryAPI(ctrl *gomock.Controller) *MockQueryAPI {
+	mock := &MockQueryAPI{ctrl: ctrl}
+	mock.recorder = &MockQueryAPIMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockQueryAPI) EXPECT() *MockQueryAPIMockRecorder {
+	return m.recorder
+}
+
+// QueryWithParams mocks base method.
+func (m *MockQueryAPI) QueryWithParams(ctx context.Context, query string, params any) (*api.QueryTableResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueryWithParams", ctx, query, params)
+	ret0, _ := ret[0].(*api.QueryTableResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryWithParams indicates an expected call of QueryWithParams.
+func (mr *MockQueryAPIMockRecorder) QueryWithParams(ctx, query, params any) *MockQueryAPIQueryWithParamsCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryWithParams", reflect.TypeOf((*MockQueryAPI)(nil).QueryWithParams), ctx, query, params)
+	return &MockQueryAPIQueryWithParamsCall{Call: call}
+}
+
+// MockQueryAPIQueryWithParamsCall wrap *gomock.Call
+type MockQueryAPIQueryWithParamsCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockQueryAPIQueryWithParamsCall) Return(arg0 *api.QueryTableResult, arg1 error) *MockQueryAPIQueryWithParamsCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockQueryAPIQueryWithParamsCall) Do(f func(context.Context, string, any) (*api.QueryTableResult, error)) *MockQueryAPIQueryWithParamsCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockQueryAPIQueryWithParamsCall) DoAndReturn(f func(context.Context, string, any) (*api.QueryTableResult, error)) *MockQueryAPIQueryWithParamsCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
@@ -0,0 +1,347 @@
+package influxdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	influxdb2 "github.com/influxdata/influxdb-client-go/v2"
+	"github.com/influxdata/influxdb-client-go/v2/api"
+	"github.com/influxdata/influxdb-client-go/v2/api/write"
+
+	"github.com/AvalosM/short-url-service/pkg/metrics"
+)
+
+//go:generate mockgen -typed -package=mocks -source=./storage.go -destination=./mocks/mocks.go
+
+// Measurement is the InfluxDB measurement metrics are written to, points are tagged by tenant and short URL id
+const Measurement = "short_url_metrics"
+
+// LatencyMeasurement is the InfluxDB measurement redirect latency percentiles are written to, tagged like Measurement
+const LatencyMeasurement = "short_url_latency"
+
+// WriteAPI writes points to the configured bucket, it is satisfied by api.WriteAPIBlocking
+type WriteAPI interface {
+	WritePoint(ctx context.Context, point ...*write.Point) error
+}
+
+// QueryAPI runs Flux queries in the configured org, it is satisfied by api.QueryAPI
+type QueryAPI interface {
+	QueryWithParams(ctx context.Context, query string, params interface{}) (*api.QueryTableResult, error)
+}
+
+// Storage stores metrics in an InfluxDB bucket, it implements metrics.Storage. Country breakdowns are not stored, so
+// metrics have no top countries.
+type Storage struct {
+	config   *metrics.InfluxDBConfig
+	writeAPI WriteAPI
+	queryAPI QueryAPI
+}
+
+// NewStorage creates a new InfluxDB metrics storage
+func NewStorage(config *metrics.InfluxDBConfig, writeAPI WriteAPI, queryAPI QueryAPI) (*Storage, error) {
+	if config == nil {
+		return nil, errors.New("config cannot be nil")
+	}
+	if writeAPI == nil {
+		return nil, errors.New("write API cannot be nil")
+	}
+	if queryAPI == nil {
+		return nil, errors.New("query API cannot be nil")
+	}
+
+	return &Storage{
+		config:   config,
+		writeAPI: writeAPI,
+		queryAPI: queryAPI,
+	}, nil
+}
+
+// NewClientStorage creates a new InfluxDB metrics storage connected to the configured InfluxDB, the returned function
+// closes the connection
+func NewClientStorage(config *metrics.InfluxDBConfig) (*Storage, func(), error) {
+	if config == nil {
+		return nil, nil, errors.New("config cannot be nil")
+	}
+
+	client := influxdb2.NewClient(config.URL, config.Token)
+	storage, err := NewStorage(config, client.WriteAPIBlocking(config.Org, config.Bucket), client.QueryAPI(config.Org))
+	if err != nil {
+		client.Close()
+
+		return nil, nil, err
+	}
+
+	return storage, client.Close, nil
+}
+
+// CreateMetrics writes multiple metric collectors to InfluxDB in a single batch
+func (s *Storage) CreateMetrics(ctx context.Context, collectors map[metrics.CollectorKey]*metrics.Collector) error {
+	if len(collectors) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	points := make([]*write.Point, 0, len(collectors))
+	for _, collector := range collectors {
+		points = append(points, write.NewPoint(
+			Measurement,
+			map[string]string{
+				"tenant_id":    collector.TenantId,
+				"short_url_id": collector.ShortURLId,
+			},
+			map[string]interface{}{
+				"visit_count":        collector.Visits,
+				"unique_visit_count": collector.UniqueVisits(),
+				"bot_visit_count":    collector.BotVisits,
+			},
+			now,
+		))
+	}
+
+	if err := s.writeAPI.WritePoint(ctx, points...); err != nil {
+		return fmt.Errorf("writing metrics points: %w", err)
+	}
+
+	return nil
+}
+
+// queryParams are the parameters of the metrics queries, the range stop of Flux is exclusive so it is a nanosecond
+// after the end of the requested time range
+type queryParams struct {
+	Bucket      string    `json:"bucket"`
+	Measurement string    `json:"measurement"`
+	TenantID    string    `json:"tenantID"`
+	ShortURLId  string    `json:"shortURLId"`
+	Start       time.Time `json:"start"`
+	Stop        time.Time `json:"stop"`
+}
+
+func (s *Storage) queryParams(tenantID string, shortURLId string, from, to time.Time) queryParams {
+	return queryParams{
+		Bucket:      s.config.Bucket,
+		Measurement: Measurement,
+		TenantID:    tenantID,
+		ShortURLId:  shortURLId,
+		Start:       from,
+		Stop:        to.Add(time.Nanosecond),
+	}
+}
+
+const getMetricsQuery = `from(bucket: params.bucket)
+	|> range(start: time(v: params.start), stop: time(v: params.stop))
+	|> filter(fn: (r) => r._measurement == params.measurement and r.tenant_id == params.tenantID and r.short_url_id == params.shortURLId)
+	|> group(columns: ["_field"])
+	|> sum()`
+
+// GetMetrics retrieves the metrics for a specific short URL ID of a tenant within a given time range
+func (s *Storage) GetMetrics(ctx context.Context, tenantID string, shortURLId string, from, to time.Time) (*metrics.Metrics, bool, error) {
+	result, err := s.queryAPI.QueryWithParams(ctx, getMetricsQuery, s.queryParams(tenantID, shortURLId, from, to))
+	if err != nil {
+		return nil, false, fmt.Errorf("executing get metrics query: %w", err)
+	}
+	defer result.Close()
+
+	found := false
+	metricsResult := &metrics.Metrics{
+		ShortURLId: shortURLId,
+		From:       from,
+		To:         to,
+	}
+	for result.Next() {
+		found = true
+
+		value, ok := result.Record().Value().(int64)
+		if !ok {
+			return nil, false, fmt.Errorf("unexpected %s value %v", result.Record().Field(), result.Record().Value())
+		}
+
+		switch result.Record().Field() {
+		case "visit_count":
+			metricsResult.Visits = value
+		case "unique_visit_count":
+			metricsResult.UniqueVisits = value
+		case "bot_visit_count":
+			metricsResult.BotVisits = value
+		}
+	}
+	if err := result.Err(); err != nil {
+		return nil, false, fmt.Errorf("reading get metrics query result: %w", err)
+	}
+	if !found {
+		return nil, false, nil
+	}
+
+	return metricsResult, true, nil
+}
+
+// bulkQueryParams are the parameters of the metrics queries of several short URLs
+type bulkQueryParams struct {
+	Bucket      string    `json:"bucket"`
+	Measurement string    `json:"measurement"`
+	TenantID    string    `json:"tenantID"`
+	ShortURLIds []string  `json:"shortURLIds"`
+	Start       time.Time `json:"start"`
+	Stop        time.Time `json:"stop"`
+}
+
+const getMetricsBulkQuery = `from(bucket: params.bucket)
+	|> range(start: time(v: params.start), stop: time(v: params.stop))
+	|> filter(fn: (r) => r._measurement == params.measurement and r.tenant_id == params.tenantID and contains(value: r.short_url_id, set: params.shortURLIds))
+	|> group(columns: ["short_url_id", "_field"])
+	|> sum()`
+
+// GetMetricsBulk retrieves the metrics of several short URL IDs of a tenant within a given time range, keyed by short
+// URL ID. Short URLs without metrics are left out.
+func (s *Storage) GetMetricsBulk(ctx context.Context, tenantID string, shortURLIds []string, from, to time.Time) (map[string]*metrics.Metrics, error) {
+	metricsByShortURLId := make(map[string]*metrics.Metrics, len(shortURLIds))
+	if len(shortURLIds) == 0 {
+		return metricsByShortURLId, nil
+	}
+
+	result, err := s.queryAPI.QueryWithParams(ctx, getMetricsBulkQuery, bulkQueryParams{
+		Bucket:      s.config.Bucket,
+		Measurement: Measurement,
+		TenantID:    tenantID,
+		ShortURLIds: shortURLIds,
+		Start:       from,
+		Stop:        to.Add(time.Nanosecond),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("executing get metrics bulk query: %w", err)
+	}
+	defer result.Close()
+
+	for result.Next() {
+		record := result.Record()
+		shortURLId, _ := record.ValueByKey("short_url_id").(string)
+		value, ok := record.Value().(int64)
+		if !ok {
+			return nil, fmt.Errorf("unexpected %s value %v", record.Field(), record.Value())
+		}
+
+		metricsResult, ok := metricsByShortURLId[shortURLId]
+		if !ok {
+			metricsResult = &metrics.Metrics{ShortURLId: shortURLId, From: from, To: to}
+			metricsByShortURLId[shortURLId] = metricsResult
+		}
+
+		switch record.Field() {
+		case "visit_count":
+			metricsResult.Visits = value
+		case "unique_visit_count":
+			metricsResult.UniqueVisits = value
+		case "bot_visit_count":
+			metricsResult.BotVisits = value
+		}
+	}
+	if err := result.Err(); err != nil {
+		return nil, fmt.Errorf("reading get metrics bulk query result: %w", err)
+	}
+
+	return metricsByShortURLId, nil
+}
+
+const streamMetricsQuery = `from(bucket: params.bucket)
+	|> range(start: time(v: params.start), stop: time(v: params.stop))
+	|> filter(fn: (r) => r._measurement == params.measurement and r.tenant_id == params.tenantID and r.short_url_id == params.shortURLId)
+	|> pivot(rowKey: ["_time"], columnKey: ["_field"], valueColumn: "_value")
+	|> group()
+	|> sort(columns: ["_time"])`
+
+// StreamMetrics calls fn with the metric intervals for a specific short URL ID of a tenant within a given time range,
+// oldest first. Records are parsed from the query response as fn consumes them, iteration stops at the first error fn
+// returns.
+func (s *Storage) StreamMetrics(ctx context.Context, tenantID string, shortURLId string, from, to time.Time, fn func(*metrics.Interval) error) error {
+	result, err := s.queryAPI.QueryWithParams(ctx, streamMetricsQuery, s.queryParams(tenantID, shortURLId, from, to))
+	if err != nil {
+		return fmt.Errorf("executing stream metrics query: %w", err)
+	}
+	defer result.Close()
+
+	for result.Next() {
+		record := result.Record()
+		interval := &metrics.Interval{Timestamp: record.Time()}
+		interval.Visits, _ = record.ValueByKey("visit_count").(int64)
+		interval.UniqueVisits, _ = record.ValueByKey("unique_visit_count").(int64)
+		interval.BotVisits, _ = record.ValueByKey("bot_visit_count").(int64)
+
+		if err := fn(interval); err != nil {
+			return err
+		}
+	}
+	if err := result.Err(); err != nil {
+		return fmt.Errorf("reading stream metrics query result: %w", err)
+	}
+
+	return nil
+}
+
+// RollupMetrics does nothing, InfluxDB downsamples and expires points through the tasks and retention policy of the
+// bucket instead
+func (s *Storage) RollupMetrics(_ context.Context, _ string, _ time.Time) error {
+	return nil
+}
+
+// CreateLatencyMetrics writes the redirect latency percentiles of multiple short URLs to InfluxDB in a single batch
+func (s *Storage) CreateLatencyMetrics(ctx context.Context, latencies map[metrics.CollectorKey]*metrics.Latency) error {
+	if len(latencies) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	points := make([]*write.Point, 0, len(latencies))
+	for key, latency := range latencies {
+		points = append(points, write.NewPoint(
+			LatencyMeasurement,
+			map[string]string{
+				"tenant_id":    key.TenantId,
+				"short_url_id": key.ShortURLId,
+			},
+			map[string]interface{}{
+				"request_count": latency.Requests,
+				"p50_ns":        latency.P50Ns,
+				"p95_ns":        latency.P95Ns,
+				"p99_ns":        latency.P99Ns,
+			},
+			now,
+		))
+	}
+
+	if err := s.writeAPI.WritePoint(ctx, points...); err != nil {
+		return fmt.Errorf("writing latency points: %w", err)
+	}
+
+	return nil
+}
+
+// GetLatencyMetrics retrieves the redirect latency percentiles of a specific short URL ID of a tenant within a given
+// time range, oldest first
+func (s *Storage) GetLatencyMetrics(ctx context.Context, tenantID string, shortURLId string, from, to time.Time) ([]*metrics.Latency, error) {
+	params := s.queryParams(tenantID, shortURLId, from, to)
+	params.Measurement = LatencyMeasurement
+
+	result, err := s.queryAPI.QueryWithParams(ctx, streamMetricsQuery, params)
+	if err != nil {
+		return nil, fmt.Errorf("executing get latency metrics query: %w", err)
+	}
+	defer result.Close()
+
+	latencies := []*metrics.Latency{}
+	for result.Next() {
+		record := result.Record()
+		latency := &metrics.Latency{Timestamp: record.Time()}
+		latency.Requests, _ = record.ValueByKey("request_count").(int64)
+		latency.P50Ns, _ = record.ValueByKey("p50_ns").(int64)
+		latency.P95Ns, _ = record.ValueByKey("p95_ns").(int64)
+		latency.P99Ns, _ = record.ValueByKey("p99_ns").(int64)
+
+		latencies = append(latencies, latency)
+	}
+	if err := result.Err(); err != nil {
+		return nil, fmt.Errorf("reading get latency metrics query result: %w", err)
+	}
+
+	return latencies, nil
+}
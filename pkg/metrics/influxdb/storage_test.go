@@ -0,0 +1,310 @@
+package influxdb_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb-client-go/v2/api"
+	"github.com/influxdata/influxdb-client-go/v2/api/write"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+
+	"github.com/AvalosM/short-url-service/pkg/metrics"
+	"github.com/AvalosM/short-url-service/pkg/metrics/influxdb"
+	"github.com/AvalosM/short-url-service/pkg/metrics/influxdb/mocks"
+	"github.com/AvalosM/short-url-service/pkg/tenant"
+)
+
+type StorageSuite struct {
+	suite.Suite
+	mockCtrl     *gomock.Controller
+	mockWriteAPI *mocks.MockWriteAPI
+	mockQueryAPI *mocks.MockQueryAPI
+	config       *metrics.InfluxDBConfig
+	storage      *influxdb.Storage
+}
+
+func (suite *StorageSuite) SetupTest() {
+	suite.mockCtrl = gomock.NewController(suite.T())
+	suite.mockWriteAPI = mocks.NewMockWriteAPI(suite.mockCtrl)
+	suite.mockQueryAPI = mocks.NewMockQueryAPI(suite.mockCtrl)
+
+	suite.config = &metrics.InfluxDBConfig{
+		URL:    "http://localhost:8086",
+		Token:  "token",
+		Org:    "org",
+		Bucket: "metrics",
+	}
+
+	storage, err := influxdb.NewStorage(suite.config, suite.mockWriteAPI, suite.mockQueryAPI)
+	suite.Require().NoError(err)
+
+	suite.storage = storage
+}
+
+func (suite *StorageSuite) TearDownTest() {
+	suite.mockCtrl.Finish()
+}
+
+func TestStorageSuite(t *testing.T) {
+	suite.Run(t, new(StorageSuite))
+}
+
+// queryResult returns a query result reading the given annotated CSV, as returned by the InfluxDB query API
+func queryResult(annotatedCSV string) *api.QueryTableResult {
+	return api.NewQueryTableResult(io.NopCloser(strings.NewReader(annotatedCSV)))
+}
+
+// marshal encodes query params the way the InfluxDB query API sends them
+func (suite *StorageSuite) marshal(params interface{}) string {
+	encoded, err := json.Marshal(params)
+	suite.Require().NoError(err)
+
+	return string(encoded)
+}
+
+func (suite *StorageSuite) TestNewStorageFailNilArguments() {
+	_, err := influxdb.NewStorage(nil, suite.mockWriteAPI, suite.mockQueryAPI)
+	suite.Error(err)
+
+	_, err = influxdb.NewStorage(suite.config, nil, suite.mockQueryAPI)
+	suite.Error(err)
+
+	_, err = influxdb.NewStorage(suite.config, suite.mockWriteAPI, nil)
+	suite.Error(err)
+}
+
+func (suite *StorageSuite) TestCreateMetricsSuccess() {
+	collectors := map[metrics.CollectorKey]*metrics.Collector{
+		{TenantId: "acme", ShortURLId: "AABBCC"}: {
+			TenantId:   "acme",
+			ShortURLId: "AABBCC",
+			Visits:     3,
+			BotVisits:  1,
+			Visitors: map[string]time.Time{
+				"127.0.0.1": {},
+				"127.0.0.2": {},
+			},
+		},
+	}
+
+	suite.mockWriteAPI.EXPECT().WritePoint(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, points ...*write.Point) error {
+			suite.Require().Len(points, 1)
+
+			line := write.PointToLineProtocol(points[0], time.Second)
+			suite.True(strings.HasPrefix(line,
+				"short_url_metrics,short_url_id=AABBCC,tenant_id=acme bot_visit_count=1i,unique_visit_count=2i,visit_count=3i "), line)
+
+			return nil
+		})
+
+	err := suite.storage.CreateMetrics(context.Background(), collectors)
+	suite.NoError(err)
+}
+
+func (suite *StorageSuite) TestCreateMetricsSuccessNoCollectors() {
+	err := suite.storage.CreateMetrics(context.Background(), map[metrics.CollectorKey]*metrics.Collector{})
+	suite.NoError(err)
+}
+
+func (suite *StorageSuite) TestCreateMetricsFailWriteError() {
+	writeErr := errors.New("write error")
+	suite.mockWriteAPI.EXPECT().WritePoint(gomock.Any(), gomock.Any()).Return(writeErr)
+
+	err := suite.storage.CreateMetrics(context.Background(), map[metrics.CollectorKey]*metrics.Collector{
+		{ShortURLId: "AABBCC"}: {ShortURLId: "AABBCC", Visits: 1, Visitors: map[string]time.Time{"127.0.0.1": {}}},
+	})
+	suite.ErrorIs(err, writeErr)
+}
+
+func (suite *StorageSuite) TestGetMetricsSuccess() {
+	from := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)
+
+	suite.mockQueryAPI.EXPECT().QueryWithParams(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, query string, params interface{}) (*api.QueryTableResult, error) {
+			suite.Contains(query, "sum()")
+			suite.Equal(`{"bucket":"metrics","measurement":"short_url_metrics","tenantID":"acme","shortURLId":"AABBCC",`+
+				`"start":"2025-06-01T00:00:00Z","stop":"2025-06-02T00:00:00.000000001Z"}`, suite.marshal(params))
+
+			return queryResult(`#datatype,string,long,string,long
+#group,false,false,true,false
+#default,_result,,,
+,result,table,_field,_value
+,,0,bot_visit_count,3
+,,1,unique_visit_count,7
+,,2,visit_count,42
+
+`), nil
+		})
+
+	metricsResult, found, err := suite.storage.GetMetrics(context.Background(), "acme", "AABBCC", from, to)
+	suite.Require().NoError(err)
+	suite.True(found)
+	suite.Equal(&metrics.Metrics{
+		ShortURLId:   "AABBCC",
+		Visits:       42,
+		UniqueVisits: 7,
+		BotVisits:    3,
+		From:         from,
+		To:           to,
+	}, metricsResult)
+}
+
+func (suite *StorageSuite) TestGetMetricsSuccessNotFound() {
+	suite.mockQueryAPI.EXPECT().QueryWithParams(gomock.Any(), gomock.Any(), gomock.Any()).Return(queryResult(""), nil)
+
+	_, found, err := suite.storage.GetMetrics(context.Background(), tenant.Default, "AABBCC", time.Now(), time.Now())
+	suite.Require().NoError(err)
+	suite.False(found)
+}
+
+func (suite *StorageSuite) TestGetMetricsFailQueryError() {
+	queryErr := errors.New("query error")
+	suite.mockQueryAPI.EXPECT().QueryWithParams(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, queryErr)
+
+	_, _, err := suite.storage.GetMetrics(context.Background(), tenant.Default, "AABBCC", time.Now(), time.Now())
+	suite.ErrorIs(err, queryErr)
+}
+
+func (suite *StorageSuite) TestGetMetricsBulkSuccess() {
+	from := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)
+
+	suite.mockQueryAPI.EXPECT().QueryWithParams(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, query string, params interface{}) (*api.QueryTableResult, error) {
+			suite.Contains(query, "contains(value: r.short_url_id")
+			suite.Equal(`{"bucket":"metrics","measurement":"short_url_metrics","tenantID":"acme","shortURLIds":["AABBCC","DDEEFF","GGHHII"],`+
+				`"start":"2025-06-01T00:00:00Z","stop":"2025-06-02T00:00:00.000000001Z"}`, suite.marshal(params))
+
+			return queryResult(`#datatype,string,long,string,string,long
+#group,false,false,true,true,false
+#default,_result,,,,
+,result,table,short_url_id,_field,_value
+,,0,AABBCC,bot_visit_count,3
+,,1,AABBCC,unique_visit_count,7
+,,2,AABBCC,visit_count,42
+,,3,DDEEFF,visit_count,5
+
+`), nil
+		})
+
+	metricsResult, err := suite.storage.GetMetricsBulk(context.Background(), "acme", []string{"AABBCC", "DDEEFF", "GGHHII"}, from, to)
+	suite.Require().NoError(err)
+	suite.Equal(map[string]*metrics.Metrics{
+		"AABBCC": {ShortURLId: "AABBCC", Visits: 42, UniqueVisits: 7, BotVisits: 3, From: from, To: to},
+		"DDEEFF": {ShortURLId: "DDEEFF", Visits: 5, From: from, To: to},
+	}, metricsResult)
+}
+
+func (suite *StorageSuite) TestGetMetricsBulkSuccessNoIds() {
+	metricsResult, err := suite.storage.GetMetricsBulk(context.Background(), tenant.Default, nil, time.Now(), time.Now())
+	suite.Require().NoError(err)
+	suite.Empty(metricsResult)
+}
+
+func (suite *StorageSuite) TestGetMetricsBulkFailQueryError() {
+	queryErr := errors.New("query error")
+	suite.mockQueryAPI.EXPECT().QueryWithParams(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, queryErr)
+
+	_, err := suite.storage.GetMetricsBulk(context.Background(), tenant.Default, []string{"AABBCC"}, time.Now(), time.Now())
+	suite.ErrorIs(err, queryErr)
+}
+
+func (suite *StorageSuite) TestStreamMetricsSuccess() {
+	suite.mockQueryAPI.EXPECT().QueryWithParams(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, query string, _ interface{}) (*api.QueryTableResult, error) {
+			suite.Contains(query, "pivot(")
+
+			return queryResult(`#datatype,string,long,dateTime:RFC3339,long,long,long
+#group,false,false,false,false,false,false
+#default,_result,,,,,
+,result,table,_time,bot_visit_count,unique_visit_count,visit_count
+,,0,2025-06-01T10:00:00Z,1,2,3
+,,0,2025-06-01T10:00:01Z,0,1,1
+
+`), nil
+		})
+
+	var intervals []*metrics.Interval
+	err := suite.storage.StreamMetrics(context.Background(), tenant.Default, "AABBCC", time.Now(), time.Now(),
+		func(interval *metrics.Interval) error {
+			intervals = append(intervals, interval)
+
+			return nil
+		})
+	suite.Require().NoError(err)
+	suite.Equal([]*metrics.Interval{
+		{Timestamp: time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC), Visits: 3, UniqueVisits: 2, BotVisits: 1},
+		{Timestamp: time.Date(2025, 6, 1, 10, 0, 1, 0, time.UTC), Visits: 1, UniqueVisits: 1},
+	}, intervals)
+}
+
+func (suite *StorageSuite) TestStreamMetricsFailCallbackError() {
+	suite.mockQueryAPI.EXPECT().QueryWithParams(gomock.Any(), gomock.Any(), gomock.Any()).Return(queryResult(`#datatype,string,long,dateTime:RFC3339,long,long,long
+#group,false,false,false,false,false,false
+#default,_result,,,,,
+,result,table,_time,bot_visit_count,unique_visit_count,visit_count
+,,0,2025-06-01T10:00:00Z,1,2,3
+
+`), nil)
+
+	callbackErr := errors.New("callback error")
+	err := suite.storage.StreamMetrics(context.Background(), tenant.Default, "AABBCC", time.Now(), time.Now(),
+		func(*metrics.Interval) error {
+			return callbackErr
+		})
+	suite.Equal(callbackErr, err)
+}
+
+func (suite *StorageSuite) TestRollupMetricsSuccess() {
+	err := suite.storage.RollupMetrics(context.Background(), "day", time.Now())
+	suite.NoError(err)
+}
+
+func (suite *StorageSuite) TestCreateLatencyMetricsSuccess() {
+	latencies := map[metrics.CollectorKey]*metrics.Latency{
+		{TenantId: "acme", ShortURLId: "AABBCC"}: {Requests: 3, P50Ns: 1000, P95Ns: 2000, P99Ns: 3000},
+	}
+
+	suite.mockWriteAPI.EXPECT().WritePoint(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, points ...*write.Point) error {
+			suite.Require().Len(points, 1)
+
+			line := write.PointToLineProtocol(points[0], time.Second)
+			suite.True(strings.HasPrefix(line,
+				"short_url_latency,short_url_id=AABBCC,tenant_id=acme p50_ns=1000i,p95_ns=2000i,p99_ns=3000i,request_count=3i "), line)
+
+			return nil
+		})
+
+	err := suite.storage.CreateLatencyMetrics(context.Background(), latencies)
+	suite.NoError(err)
+}
+
+func (suite *StorageSuite) TestGetLatencyMetricsSuccess() {
+	suite.mockQueryAPI.EXPECT().QueryWithParams(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, params interface{}) (*api.QueryTableResult, error) {
+			suite.Contains(suite.marshal(params), `"measurement":"short_url_latency"`)
+
+			return queryResult(`#datatype,string,long,dateTime:RFC3339,long,long,long,long
+#group,false,false,false,false,false,false,false
+#default,_result,,,,,,
+,result,table,_time,p50_ns,p95_ns,p99_ns,request_count
+,,0,2025-06-01T10:00:00Z,1000,2000,3000,3
+
+`), nil
+		})
+
+	latencies, err := suite.storage.GetLatencyMetrics(context.Background(), tenant.Default, "AABBCC", time.Now(), time.Now())
+	suite.Require().NoError(err)
+	suite.Equal([]*metrics.Latency{
+		{Timestamp: time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC), Requests: 3, P50Ns: 1000, P95Ns: 2000, P99Ns: 3000},
+	}, latencies)
+}
@@ -0,0 +1,40 @@
+package metrics
+
+import (
+	"net"
+	"net/netip"
+)
+
+const (
+	// anonymizedIPv4Bits keeps the first 3 octets of an IPv4 address
+	anonymizedIPv4Bits = 24
+	// anonymizedIPv6Bits keeps the first 48 bits of an IPv6 address, dropping the last 80
+	anonymizedIPv6Bits = 48
+)
+
+// AnonymizeIP truncates the last octet of an IPv4 address and the last 80 bits of an IPv6 address.
+// ip may include a port, as in http.Request.RemoteAddr, which is removed. An empty string is returned
+// for invalid input so the raw value is never stored.
+func AnonymizeIP(ip string) string {
+	if host, _, err := net.SplitHostPort(ip); err == nil {
+		ip = host
+	}
+
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return ""
+	}
+	addr = addr.WithZone("").Unmap()
+
+	bits := anonymizedIPv6Bits
+	if addr.Is4() {
+		bits = anonymizedIPv4Bits
+	}
+
+	prefix, err := addr.Prefix(bits)
+	if err != nil {
+		return ""
+	}
+
+	return prefix.Addr().String()
+}
@@ -0,0 +1,41 @@
+package metrics_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/AvalosM/short-url-service/pkg/metrics"
+)
+
+type IPAnonSuite struct {
+	suite.Suite
+}
+
+func TestIPAnonSuite(t *testing.T) {
+	suite.Run(t, new(IPAnonSuite))
+}
+
+func (suite *IPAnonSuite) TestAnonymizeIP() {
+	testCases := []struct {
+		name     string
+		ip       string
+		expected string
+	}{
+		{name: "ipv4", ip: "192.168.1.42", expected: "192.168.1.0"},
+		{name: "ipv4 with port", ip: "192.168.1.42:54321", expected: "192.168.1.0"},
+		{name: "ipv4 mapped ipv6", ip: "::ffff:192.168.1.42", expected: "192.168.1.0"},
+		{name: "ipv6", ip: "2001:db8:85a3:1234:5678:8a2e:370:7334", expected: "2001:db8:85a3::"},
+		{name: "ipv6 with port", ip: "[2001:db8:85a3:1234:5678:8a2e:370:7334]:443", expected: "2001:db8:85a3::"},
+		{name: "ipv6 with zone", ip: "fe80::1234:5678%eth0", expected: "fe80::"},
+		{name: "invalid", ip: "not-an-ip", expected: ""},
+		{name: "invalid with port", ip: "not-an-ip:80", expected: ""},
+		{name: "empty", ip: "", expected: ""},
+	}
+
+	for _, testCase := range testCases {
+		suite.Run(testCase.name, func() {
+			suite.Equal(testCase.expected, metrics.AnonymizeIP(testCase.ip))
+		})
+	}
+}
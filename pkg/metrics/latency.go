@@ -0,0 +1,39 @@
+package metrics
+
+import (
+	"math"
+	"slices"
+	"time"
+)
+
+// Latency are the redirect latency percentiles of the requests to a short URL flushed at Timestamp
+type Latency struct {
+	Timestamp time.Time
+	Requests  int64
+	P50Ns     int64
+	P95Ns     int64
+	P99Ns     int64
+}
+
+// NewLatency computes the latency percentiles of the given request latencies in nanoseconds, it sorts samples
+func NewLatency(samples []int64) *Latency {
+	slices.Sort(samples)
+
+	return &Latency{
+		Requests: int64(len(samples)),
+		P50Ns:    percentile(samples, 50),
+		P95Ns:    percentile(samples, 95),
+		P99Ns:    percentile(samples, 99),
+	}
+}
+
+// percentile returns the nearest-rank percentile p of sorted samples, 0 when there are none
+func percentile(sorted []int64, p float64) int64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+
+	return sorted[max(rank, 1)-1]
+}
@@ -0,0 +1,50 @@
+package metrics_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/AvalosM/short-url-service/pkg/metrics"
+)
+
+type LatencySuite struct {
+	suite.Suite
+}
+
+func TestLatencySuite(t *testing.T) {
+	suite.Run(t, new(LatencySuite))
+}
+
+func (suite *LatencySuite) TestNewLatency() {
+	testCases := []struct {
+		name     string
+		samples  []int64
+		expected *metrics.Latency
+	}{
+		{name: "no samples", samples: nil, expected: &metrics.Latency{}},
+		{name: "single sample", samples: []int64{7}, expected: &metrics.Latency{Requests: 1, P50Ns: 7, P95Ns: 7, P99Ns: 7}},
+		{name: "unsorted samples", samples: []int64{40, 10, 30, 20}, expected: &metrics.Latency{Requests: 4, P50Ns: 20, P95Ns: 40, P99Ns: 40}},
+		{
+			name:     "hundred samples",
+			samples:  hundredSamples(),
+			expected: &metrics.Latency{Requests: 100, P50Ns: 50, P95Ns: 95, P99Ns: 99},
+		},
+	}
+
+	for _, testCase := range testCases {
+		suite.Run(testCase.name, func() {
+			suite.Equal(testCase.expected, metrics.NewLatency(testCase.samples))
+		})
+	}
+}
+
+// hundredSamples returns the latencies 100 down to 1
+func hundredSamples() []int64 {
+	samples := make([]int64, 0, 100)
+	for i := int64(100); i > 0; i-- {
+		samples = append(samples, i)
+	}
+
+	return samples
+}
@@ -4,15 +4,38 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math/rand"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/AvalosM/short-url-service/pkg/logging"
+	"github.com/AvalosM/short-url-service/pkg/tenant"
 )
 
-// Storage short url persistent storage
+// errorChannelSize is the number of errors Errors holds until they are read, later errors are dropped
+const errorChannelSize = 100
+
+// Storage short url persistent storage, collectors carry the tenant of their short URL
 type Storage interface {
-	CreateMetrics(ctx context.Context, metrics map[string]*Collector) error
-	GetMetrics(ctx context.Context, shortURLId string, from, to time.Time) (*Metrics, bool, error)
+	CreateMetrics(ctx context.Context, metrics map[CollectorKey]*Collector) error
+	GetMetrics(ctx context.Context, tenantID string, shortURLId string, from, to time.Time) (*Metrics, bool, error)
+	GetMetricsBulk(ctx context.Context, tenantID string, shortURLIds []string, from, to time.Time) (map[string]*Metrics, error)
+	StreamMetrics(ctx context.Context, tenantID string, shortURLId string, from, to time.Time, fn func(*Interval) error) error
+	RollupMetrics(ctx context.Context, granularity string, before time.Time) error
+	CreateLatencyMetrics(ctx context.Context, latencies map[CollectorKey]*Latency) error
+	GetLatencyMetrics(ctx context.Context, tenantID string, shortURLId string, from, to time.Time) ([]*Latency, error)
+}
+
+// BotDetector identifies the requests of bots by their User-Agent
+type BotDetector interface {
+	IsBot(userAgent string) bool
+}
+
+// GeoLookup resolves the country code of a visitor IP, with or without a port. An empty code is returned for IPs of
+// unknown countries.
+type GeoLookup interface {
+	Country(ip string) (string, error)
 }
 
 // Logger ...
@@ -27,48 +50,88 @@ type Logger interface {
 type Manager struct {
 	config      *Config
 	storage     Storage
-	collectors  map[string]*Collector
-	requestChan chan Request
-	stopChan    chan struct{}
-	logger      Logger
+	botDetector BotDetector
+	geoLookup   GeoLookup
+	// collectorsMu guards collectors, which are only written by the consumer goroutine but read by Snapshot
+	collectorsMu sync.RWMutex
+	collectors   map[CollectorKey]*SafeCollector
+	latencies    map[CollectorKey][]int64
+	lastSeen     map[CollectorKey]map[string]time.Time
+	requestChan  chan Request
+	drainChan    chan chan error
+	stopChan     chan struct{}
+	errChan      chan error
+	subscribers  subscribers
+	dropCount    atomic.Uint64
+	// interval is the current flush interval in nanoseconds, see adaptInterval
+	interval atomic.Int64
+	logger   Logger
 }
 
-// NewManager creates a new metrics manager
-func NewManager(config *Config, storage Storage, logger Logger) (*Manager, error) {
+// NewManager creates a new metrics manager, geoLookup can be nil to not collect visitor countries
+func NewManager(config *Config, storage Storage, botDetector BotDetector, geoLookup GeoLookup, logger Logger) (*Manager, error) {
 	if config == nil {
 		return nil, errors.New("config cannot be nil")
 	}
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
 	if storage == nil {
 		return nil, errors.New("storage cannot be nil")
 	}
+	if botDetector == nil {
+		return nil, errors.New("bot detector cannot be nil")
+	}
 	if logger == nil {
 		return nil, errors.New("logger cannot be nil")
 	}
 
-	return &Manager{
+	manager := &Manager{
 		config:      config,
 		storage:     storage,
-		collectors:  make(map[string]*Collector),
+		botDetector: botDetector,
+		geoLookup:   geoLookup,
+		collectors:  make(map[CollectorKey]*SafeCollector),
+		latencies:   make(map[CollectorKey][]int64),
+		lastSeen:    make(map[CollectorKey]map[string]time.Time),
 		requestChan: make(chan Request, config.RequestChannelSize),
+		drainChan:   make(chan chan error),
 		stopChan:    make(chan struct{}),
+		errChan:     make(chan error, errorChannelSize),
 		logger:      logger,
-	}, nil
+	}
+	manager.interval.Store(int64(manager.baseInterval()))
+
+	return manager, nil
 }
 
 // Start starts the metrics manager request consumer
 func (m *Manager) Start() func() {
 	go func() {
-		ticker := time.NewTicker(time.Duration(m.config.MetricsIntervalInMS) * time.Millisecond)
+		interval := m.baseInterval()
+		m.interval.Store(int64(interval))
+		ticker := time.NewTicker(interval + m.jitter())
 		defer ticker.Stop()
 
+		jittered := m.config.MetricsIntervalJitterInMS > 0
 		for {
 			select {
 			case <-ticker.C:
 				m.logger.Debug("flushing metrics")
 				m.flushMetrics()
+
+				// Only the first flush is delayed by the jitter, the phase it sets is kept afterwards
+				if next := m.adaptInterval(interval); next != interval || jittered {
+					ticker.Reset(next)
+					interval = next
+					jittered = false
+				}
 			case request := <-m.requestChan:
 				m.logger.Debug("processing request")
 				m.processRequest(request)
+			case response := <-m.drainChan:
+				m.logger.Debug("draining metrics")
+				response <- m.flushMetrics()
 			case <-m.stopChan:
 				m.logger.Debug("flushing metrics")
 				m.flushMetrics()
@@ -85,32 +148,278 @@ func (m *Manager) Start() func() {
 	}
 }
 
-func (m *Manager) flushMetrics() {
-	err := m.storage.CreateMetrics(context.Background(), m.collectors)
+// StartRollup rolls up the metrics of all tenants in storage every interval, until ctx is done or the returned stop
+// function is called. Only periods of the configured granularity that ended before each rollup are rolled up, so
+// metrics still being collected are kept as they are. The stop function waits for a running rollup to finish.
+func (m *Manager) StartRollup(ctx context.Context, interval time.Duration) func() {
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				m.rollupMetrics(ctx)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	m.log(ctx).Info("metrics rollup started")
+
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+func (m *Manager) rollupMetrics(ctx context.Context) {
+	if err := m.storage.RollupMetrics(ctx, m.config.RollupGranularity, time.Now()); err != nil {
+		m.log(ctx).Error("failed to roll up metrics in storage", logging.ErrorKey, err)
+
+		return
+	}
+
+	m.log(ctx).Info("rolled up metrics")
+}
+
+// baseInterval returns the configured flush interval
+func (m *Manager) baseInterval() time.Duration {
+	return time.Duration(m.config.MetricsIntervalInMS) * time.Millisecond
+}
+
+// adaptInterval returns the interval of the next flush given the current one. Under bursts of requests the channel
+// fills faster than it is drained between flushes, so the interval is halved down to the minimum while it is more
+// than 80% full, and restored to the configured one once it is less than 20% full.
+func (m *Manager) adaptInterval(current time.Duration) time.Duration {
+	queued, size := len(m.requestChan), cap(m.requestChan)
+
+	next := current
+	switch {
+	case queued*5 > size*4:
+		next = max(current/2, time.Duration(m.config.MinMetricsIntervalInMS)*time.Millisecond)
+	case queued*5 < size:
+		next = m.baseInterval()
+	}
+
+	if next != current {
+		m.logger.Debug("adapting metrics flush interval", logging.IntervalKey, next.String())
+		m.interval.Store(int64(next))
+	}
+
+	return next
+}
+
+// FlushInterval returns the interval metrics are currently flushed at, shorter than the configured one under bursts
+// of requests
+func (m *Manager) FlushInterval() time.Duration {
+	return time.Duration(m.interval.Load())
+}
+
+// jitter returns a random duration between 0 and the configured jitter
+func (m *Manager) jitter() time.Duration {
+	if m.config.MetricsIntervalJitterInMS <= 0 {
+		return 0
+	}
+
+	return time.Duration(rand.Intn(m.config.MetricsIntervalJitterInMS+1)) * time.Millisecond
+}
+
+func (m *Manager) flushMetrics() error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(m.config.MaxFlushContextTimeoutInMS)*time.Millisecond)
+	defer cancel()
+
+	// New requests are collected apart while the flushed collectors are written
+	m.collectorsMu.Lock()
+	flushed := m.collectors
+	m.collectors = make(map[CollectorKey]*SafeCollector)
+	m.collectorsMu.Unlock()
+
+	collectors := make(map[CollectorKey]*Collector, len(flushed))
+	for key, collector := range flushed {
+		collectors[key] = collector.Collector()
+	}
+
+	err := m.storage.CreateMetrics(ctx, collectors)
+	var skipped *SkippedMetricsError
+	if errors.As(err, &skipped) {
+		shortURLIds := make([]string, 0, len(skipped.Keys))
+		for _, key := range skipped.Keys {
+			shortURLIds = append(shortURLIds, key.ShortURLId)
+		}
+		m.logger.Warn("skipped metrics of short URLs that do not exist", logging.ShortURLIdsKey, shortURLIds)
+		err = nil
+	}
 	if err != nil {
 		m.logger.Error("creating metrics in storage", logging.ErrorKey, err)
+		err = fmt.Errorf("creating metrics in storage: %w", err)
+	}
+
+	if latencyErr := m.flushLatencies(ctx); latencyErr != nil {
+		err = errors.Join(err, latencyErr)
+	}
+
+	resetCollectors(flushed)
+	clear(m.latencies)
+	m.pruneLastSeen(time.Now())
+
+	if dropped := m.dropCount.Swap(0); dropped > 0 {
+		m.logger.Warn("dropped short URL requests since last flush", logging.DroppedRequestsKey, dropped)
+	}
+
+	return err
+}
+
+// resetCollectors resets the flushed collectors and removes them, so the visitors they hold are released even if a
+// collector is still referenced
+func resetCollectors(collectors map[CollectorKey]*SafeCollector) {
+	for _, collector := range collectors {
+		collector.Reset()
+	}
+	clear(collectors)
+}
+
+// flushLatencies creates the latency percentiles of the requests collected since the last flush in storage
+func (m *Manager) flushLatencies(ctx context.Context) error {
+	if len(m.latencies) == 0 {
+		return nil
+	}
+
+	latencies := make(map[CollectorKey]*Latency, len(m.latencies))
+	for key, samples := range m.latencies {
+		latencies[key] = NewLatency(samples)
+	}
+
+	if err := m.storage.CreateLatencyMetrics(ctx, latencies); err != nil {
+		m.log(ctx).Error("creating latency metrics in storage", logging.ErrorKey, err)
+
+		return fmt.Errorf("creating latency metrics in storage: %w", err)
 	}
 
-	clear(m.collectors)
+	return nil
 }
 
 func (m *Manager) processRequest(request Request) {
 	m.logger.Debug("processing request")
 
-	collector, found := m.collectors[request.ShortURLId]
+	key := CollectorKey{TenantId: request.TenantId, ShortURLId: request.ShortURLId}
+
+	// Every redirect is served, so the latency of repeated visits and bots is collected too
+	if request.LatencyNs > 0 {
+		m.latencies[key] = append(m.latencies[key], request.LatencyNs)
+	}
+	if !request.IsBot && !m.countVisit(key, request) {
+		return
+	}
+
+	// Only the consumer goroutine writes collectors, so it can read them without holding the lock
+	collector, found := m.collectors[key]
+	if !found {
+		collector = NewSafeCollector(request.TenantId, request.ShortURLId)
+
+		m.collectorsMu.Lock()
+		m.collectors[key] = collector
+		m.collectorsMu.Unlock()
+	}
+
+	collector.Record(request)
+
+	m.subscribers.publish(key, Event{
+		ShortURLId: request.ShortURLId,
+		IP:         request.VisitorId,
+		Timestamp:  request.Timestamp,
+	})
+}
+
+// countVisit reports whether a request counts as a visit, requests of a visitor seen within the visit window do not.
+// Visitors are seen on every request, so a visitor polling more often than the window is only counted once. The
+// last time visitors were seen is kept across flushes, it must only be called from the consumer goroutine.
+func (m *Manager) countVisit(key CollectorKey, request Request) bool {
+	if m.config.VisitWindowInSeconds <= 0 {
+		return true
+	}
+
+	visitors, found := m.lastSeen[key]
 	if !found {
-		collector = &Collector{
-			ShortURLId: request.ShortURLId,
-			Visits:     1,
-			Visitors:   map[string]struct{}{request.VisitorId: {}},
+		visitors = make(map[string]time.Time)
+		m.lastSeen[key] = visitors
+	}
+
+	lastSeen, seen := visitors[request.VisitorId]
+	visitors[request.VisitorId] = request.Timestamp
+
+	return !seen || request.Timestamp.Sub(lastSeen) > m.visitWindow()
+}
+
+// pruneLastSeen forgets the visitors last seen before the visit window, their next request is counted as a visit anyway
+func (m *Manager) pruneLastSeen(now time.Time) {
+	for key, visitors := range m.lastSeen {
+		for visitorId, lastSeen := range visitors {
+			if now.Sub(lastSeen) > m.visitWindow() {
+				delete(visitors, visitorId)
+			}
 		}
-		m.collectors[request.ShortURLId] = collector
+		if len(visitors) == 0 {
+			delete(m.lastSeen, key)
+		}
+	}
+}
 
-		return
+func (m *Manager) visitWindow() time.Duration {
+	return time.Duration(m.config.VisitWindowInSeconds) * time.Second
+}
+
+// Snapshot returns a copy of the metrics collected since the last flush for the short URLs of the tenant of ctx,
+// keyed by short URL id. Requests still waiting for the consumer goroutine are not in it.
+func (m *Manager) Snapshot(ctx context.Context) (map[string]CollectorSnapshot, error) {
+	select {
+	case <-m.stopChan:
+		return nil, ErrManagerStopped
+	default:
 	}
 
-	collector.Visits++
-	collector.Visitors[request.VisitorId] = struct{}{}
+	tenantID := tenant.IDFromContext(ctx)
+
+	m.collectorsMu.RLock()
+	defer m.collectorsMu.RUnlock()
+
+	snapshot := make(map[string]CollectorSnapshot)
+	for key, collector := range m.collectors {
+		if key.TenantId != tenantID {
+			continue
+		}
+
+		snapshot[key.ShortURLId] = collector.Snapshot()
+	}
+
+	return snapshot, nil
+}
+
+// Drain flushes the metrics collected since the last flush to storage and waits for the flush to complete
+func (m *Manager) Drain(ctx context.Context) error {
+	// The response channel is buffered so the consumer never waits for the caller to read it
+	response := make(chan error, 1)
+
+	select {
+	case m.drainChan <- response:
+	case <-ctx.Done():
+		return fmt.Errorf("requesting metrics drain: %w", ctx.Err())
+	case <-m.stopChan:
+		return ErrManagerStopped
+	}
+
+	select {
+	case err := <-response:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("waiting for metrics drain: %w", ctx.Err())
+	}
 }
 
 // Stop stops the metrics manager and flushes any remaining metrics
@@ -119,30 +428,92 @@ func (m *Manager) Stop() {
 	close(m.stopChan)
 }
 
-// RecordShortURLRequestAsync records a short URL request asynchronously
-func (m *Manager) RecordShortURLRequestAsync(id string, ip string) {
-	go m.RecordShortURLRequest(id, ip)
+// log returns the logger of the manager adding the request-scoped attributes of ctx to its records
+func (m *Manager) log(ctx context.Context) Logger {
+	return logging.FromContext(ctx, m.logger)
+}
+
+// RecordShortURLRequestAsync records a short URL request of a tenant asynchronously
+func (m *Manager) RecordShortURLRequestAsync(tenantID string, id string, ip string, userAgent string, latency time.Duration) {
+	go m.RecordShortURLRequest(tenantID, id, ip, userAgent, latency)
 }
 
-// RecordShortURLRequest records a short URL request
-func (m *Manager) RecordShortURLRequest(id string, ip string) {
+// RecordShortURLRequest records a short URL request of a tenant and the latency its redirect was served with,
+// requests of bots are counted apart from visits
+func (m *Manager) RecordShortURLRequest(tenantID string, id string, ip string, userAgent string, latency time.Duration) {
+	// The country is looked up before anonymizing, a truncated IP may belong to another network
+	country := m.country(ip)
+	if m.config.AnonymizeIPs {
+		ip = AnonymizeIP(ip)
+	}
+
 	select {
 	case m.requestChan <- Request{
+		TenantId:   tenantID,
 		ShortURLId: id,
 		VisitorId:  ip,
+		Timestamp:  time.Now(),
+		IsBot:      m.botDetector.IsBot(userAgent),
+		Country:    country,
+		LatencyNs:  latency.Nanoseconds(),
 	}:
 	case <-time.After(time.Millisecond * time.Duration(m.config.RecordRequestTimeoutInMS)):
-		m.logger.Warn("timeout while recording short URL request")
+		m.recordDrop()
+		m.reportError(fmt.Errorf("failed to record request of short URL %s: %w", id, ErrRecordRequestTimeout))
+		m.logger.Warn("timeout while recording short URL request", logging.TenantIdKey, tenantID, logging.ShortURLIdKey, id,
+			logging.LatencyKey, latency)
 	case <-m.stopChan:
-		m.logger.Warn("metrics manager is stopping, cannot record request")
+		m.recordDrop()
+		m.reportError(fmt.Errorf("failed to record request of short URL %s: %w", id, ErrManagerStopped))
+		m.logger.Warn("metrics manager is stopping, cannot record request", logging.TenantIdKey, tenantID, logging.ShortURLIdKey, id)
 	}
 }
 
-// GetShortURLMetrics retrieves metrics for a short URL within a specified time range
+// reportError sends err to the channel returned by Errors, it is dropped when the channel is full
+func (m *Manager) reportError(err error) {
+	select {
+	case m.errChan <- err:
+	default:
+	}
+}
+
+// Errors returns the channel the errors of dropped requests are sent to, they wrap ErrRecordRequestTimeout or
+// ErrManagerStopped. It holds up to 100 errors not read yet, errors reported while it is full are dropped.
+func (m *Manager) Errors() <-chan error {
+	return m.errChan
+}
+
+// country returns the country code of a visitor IP, empty when there is no geo lookup or the country is unknown
+func (m *Manager) country(ip string) string {
+	if m.geoLookup == nil {
+		return ""
+	}
+
+	country, err := m.geoLookup.Country(ip)
+	if err != nil {
+		m.logger.Debug("failed to look up visitor country", logging.ErrorKey, err)
+
+		return ""
+	}
+
+	return country
+}
+
+func (m *Manager) recordDrop() {
+	m.dropCount.Add(1)
+	droppedRequestsCounter.Inc()
+}
+
+// DroppedRequests returns the number of requests dropped since the last flush
+func (m *Manager) DroppedRequests() uint64 {
+	return m.dropCount.Load()
+}
+
+// GetShortURLMetrics retrieves metrics for a short URL of the tenant of ctx within a specified time range
 func (m *Manager) GetShortURLMetrics(ctx context.Context, id string, from, to time.Time) (*Metrics, error) {
-	metrics, found, err := m.storage.GetMetrics(ctx, id, from, to)
+	metrics, found, err := m.storage.GetMetrics(ctx, tenant.IDFromContext(ctx), id, from, to)
 	if err != nil {
-		m.logger.Error("failed to get metrics from storage", logging.ShortURLIdKey, id, logging.ErrorKey, err)
+		m.log(ctx).Error("failed to get metrics from storage", logging.ShortURLIdKey, id, logging.ErrorKey, err)
 
 		return nil, fmt.Errorf("getting metrics from storage: %w", err)
 	}
@@ -158,3 +529,57 @@ func (m *Manager) GetShortURLMetrics(ctx context.Context, id string, from, to ti
 
 	return metrics, nil
 }
+
+// GetShortURLMetricsBulk retrieves the metrics of several short URLs of the tenant of ctx within a specified time
+// range with a single storage call, by short URL id. Like GetShortURLMetrics, short URLs without metrics get zero ones.
+func (m *Manager) GetShortURLMetricsBulk(ctx context.Context, ids []string, from, to time.Time) (map[string]*Metrics, error) {
+	bulk, err := m.storage.GetMetricsBulk(ctx, tenant.IDFromContext(ctx), ids, from, to)
+	if err != nil {
+		m.log(ctx).Error("failed to get metrics from storage", logging.ShortURLIdsKey, ids, logging.ErrorKey, err)
+
+		return nil, fmt.Errorf("getting metrics from storage: %w", err)
+	}
+
+	for _, id := range ids {
+		if _, found := bulk[id]; !found {
+			bulk[id] = &Metrics{ShortURLId: id, From: from, To: to}
+		}
+	}
+
+	return bulk, nil
+}
+
+// StreamShortURLMetrics calls fn with the metric intervals of a short URL of the tenant of ctx within a specified time
+// range, oldest first, without loading them all into memory. Errors returned by fn are returned as they are.
+func (m *Manager) StreamShortURLMetrics(ctx context.Context, id string, from, to time.Time, fn func(*Interval) error) error {
+	var fnErr error
+	err := m.storage.StreamMetrics(ctx, tenant.IDFromContext(ctx), id, from, to, func(interval *Interval) error {
+		fnErr = fn(interval)
+
+		return fnErr
+	})
+	if err != nil {
+		if fnErr != nil {
+			return fnErr
+		}
+
+		m.log(ctx).Error("failed to stream metrics from storage", logging.ShortURLIdKey, id, logging.ErrorKey, err)
+
+		return fmt.Errorf("streaming metrics from storage: %w", err)
+	}
+
+	return nil
+}
+
+// GetShortURLLatency retrieves the redirect latency percentiles of a short URL of the tenant of ctx within a specified
+// time range, one per flush, oldest first
+func (m *Manager) GetShortURLLatency(ctx context.Context, id string, from, to time.Time) ([]*Latency, error) {
+	latencies, err := m.storage.GetLatencyMetrics(ctx, tenant.IDFromContext(ctx), id, from, to)
+	if err != nil {
+		m.log(ctx).Error("failed to get latency metrics from storage", logging.ShortURLIdKey, id, logging.ErrorKey, err)
+
+		return nil, fmt.Errorf("getting latency metrics from storage: %w", err)
+	}
+
+	return latencies, nil
+}
@@ -3,16 +3,22 @@ package metrics_test
 import (
 	"context"
 	"errors"
+	"fmt"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/AvalosM/short-url-service/pkg/logging"
 	"github.com/AvalosM/short-url-service/pkg/metrics"
 	"github.com/AvalosM/short-url-service/pkg/metrics/mocks"
+	"github.com/AvalosM/short-url-service/pkg/tenant"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 	"go.uber.org/mock/gomock"
 )
 
+const browserUserAgent = "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0.0.0 Safari/537.36"
+
 //go:generate mockgen -typed -package=mocks  -source=./manager.go -destination=./mocks/mocks.go
 
 type ManagerSuite struct {
@@ -20,6 +26,7 @@ type ManagerSuite struct {
 	mockCtrl    *gomock.Controller
 	mockStorage *mocks.MockStorage
 	mockLogger  *mocks.MockLogger
+	botDetector *metrics.UserAgentBotDetector
 	config      *metrics.Config
 	manager     *metrics.Manager
 }
@@ -35,7 +42,11 @@ func (suite *ManagerSuite) SetupTest() {
 
 	suite.config = metrics.DefaultConfig()
 
-	manager, err := metrics.NewManager(suite.config, suite.mockStorage, suite.mockLogger)
+	botDetector, err := metrics.NewBotDetector(suite.config.BotUAFile)
+	suite.Require().NoError(err)
+	suite.botDetector = botDetector
+
+	manager, err := metrics.NewManager(suite.config, suite.mockStorage, suite.botDetector, nil, suite.mockLogger)
 	suite.Require().NoError(err)
 
 	suite.manager = manager
@@ -49,25 +60,51 @@ func TestManagerSuite(t *testing.T) {
 	suite.Run(t, new(ManagerSuite))
 }
 
+func (suite *ManagerSuite) TestNewManagerFailZeroConfig() {
+	_, err := metrics.NewManager(&metrics.Config{}, suite.mockStorage, suite.botDetector, nil, suite.mockLogger)
+	suite.ErrorContains(err, "MetricsIntervalInMS")
+}
+
+// withoutVisitTimes copies collectors clearing the times their visitors were counted at, so they can be compared
+func withoutVisitTimes(collectors map[metrics.CollectorKey]*metrics.Collector) map[metrics.CollectorKey]*metrics.Collector {
+	copied := make(map[metrics.CollectorKey]*metrics.Collector, len(collectors))
+	for key, collector := range collectors {
+		visitors := make(map[string]time.Time, len(collector.Visitors))
+		for visitorId := range collector.Visitors {
+			visitors[visitorId] = time.Time{}
+		}
+
+		copied[key] = &metrics.Collector{
+			TenantId:   collector.TenantId,
+			ShortURLId: collector.ShortURLId,
+			Visits:     collector.Visits,
+			BotVisits:  collector.BotVisits,
+			Visitors:   visitors,
+		}
+	}
+
+	return copied
+}
+
 func (suite *ManagerSuite) TestRecordShortURLRequestAsyncSuccess() {
 	shortURLId0 := "AABBCC"
 	shortURLId1 := "DDEEFF"
 	host0 := "127.0.0.1"
 	host1 := "127.0.0.2"
 
-	expectedCollectors := map[string]*metrics.Collector{
-		shortURLId0: {
+	expectedCollectors := map[metrics.CollectorKey]*metrics.Collector{
+		{ShortURLId: shortURLId0}: {
 			ShortURLId: shortURLId0,
 			Visits:     3,
-			Visitors: map[string]struct{}{
+			Visitors: map[string]time.Time{
 				host0: {},
 				host1: {},
 			},
 		},
-		shortURLId1: {
+		{ShortURLId: shortURLId1}: {
 			ShortURLId: shortURLId1,
 			Visits:     1,
-			Visitors: map[string]struct{}{
+			Visitors: map[string]time.Time{
 				host1: {},
 			},
 		},
@@ -77,19 +114,19 @@ func (suite *ManagerSuite) TestRecordShortURLRequestAsyncSuccess() {
 
 	stopManager := suite.manager.Start()
 
-	suite.mockStorage.EXPECT().CreateMetrics(context.Background(), gomock.Any()).
-		DoAndReturn(func(_ context.Context, collectors map[string]*metrics.Collector) error {
-			suite.EqualValues(expectedCollectors, collectors)
+	suite.mockStorage.EXPECT().CreateMetrics(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, collectors map[metrics.CollectorKey]*metrics.Collector) error {
+			suite.EqualValues(expectedCollectors, withoutVisitTimes(collectors))
 
 			close(done)
 			return nil
 		})
-	suite.mockStorage.EXPECT().CreateMetrics(context.Background(), gomock.Any()).AnyTimes()
+	suite.mockStorage.EXPECT().CreateMetrics(gomock.Any(), gomock.Any()).AnyTimes()
 
-	suite.manager.RecordShortURLRequestAsync(shortURLId0, host0)
-	suite.manager.RecordShortURLRequestAsync(shortURLId0, host1)
-	suite.manager.RecordShortURLRequestAsync(shortURLId0, host0)
-	suite.manager.RecordShortURLRequestAsync(shortURLId1, host1)
+	suite.manager.RecordShortURLRequest(tenant.Default, shortURLId0, host0, browserUserAgent, 0)
+	suite.manager.RecordShortURLRequest(tenant.Default, shortURLId0, host1, browserUserAgent, 0)
+	suite.manager.RecordShortURLRequest(tenant.Default, shortURLId0, host0, browserUserAgent, 0)
+	suite.manager.RecordShortURLRequest(tenant.Default, shortURLId1, host1, browserUserAgent, 0)
 
 	// Wait for metrics to be sent or timeout
 	select {
@@ -102,18 +139,67 @@ func (suite *ManagerSuite) TestRecordShortURLRequestAsyncSuccess() {
 	stopManager()
 }
 
+func (suite *ManagerSuite) TestRecordShortURLRequestAsyncSuccessSeparatesTenants() {
+	shortURLId := "AABBCC"
+	tenantID := "acme"
+	host := "127.0.0.1"
+
+	expectedCollectors := map[metrics.CollectorKey]*metrics.Collector{
+		{ShortURLId: shortURLId}: {
+			ShortURLId: shortURLId,
+			Visits:     1,
+			Visitors: map[string]time.Time{
+				host: {},
+			},
+		},
+		{TenantId: tenantID, ShortURLId: shortURLId}: {
+			TenantId:   tenantID,
+			ShortURLId: shortURLId,
+			Visits:     2,
+			Visitors: map[string]time.Time{
+				host: {},
+			},
+		},
+	}
+
+	done := make(chan struct{})
+
+	suite.mockStorage.EXPECT().CreateMetrics(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, collectors map[metrics.CollectorKey]*metrics.Collector) error {
+			suite.EqualValues(expectedCollectors, withoutVisitTimes(collectors))
+
+			close(done)
+			return nil
+		})
+	suite.mockStorage.EXPECT().CreateMetrics(gomock.Any(), gomock.Any()).AnyTimes()
+
+	suite.manager.RecordShortURLRequest(tenant.Default, shortURLId, host, browserUserAgent, 0)
+	suite.manager.RecordShortURLRequest(tenantID, shortURLId, host, browserUserAgent, 0)
+	suite.manager.RecordShortURLRequest(tenantID, shortURLId, host, browserUserAgent, 0)
+
+	stopManager := suite.manager.Start()
+
+	select {
+	case <-done:
+	case <-time.After(time.Duration(suite.config.MetricsIntervalInMS*2) * time.Millisecond):
+		suite.T().Fatal("Timeout waiting for metrics to be processed")
+	}
+
+	stopManager()
+}
+
 func (suite *ManagerSuite) TestRecordShortURLRequestAsyncSuccessNoCollectors() {
 	done := make(chan struct{})
 
 	stopManager := suite.manager.Start()
 
-	suite.mockStorage.EXPECT().CreateMetrics(context.Background(), map[string]*metrics.Collector{}).
-		DoAndReturn(func(_ context.Context, _ map[string]*metrics.Collector) error {
+	suite.mockStorage.EXPECT().CreateMetrics(gomock.Any(), map[metrics.CollectorKey]*metrics.Collector{}).
+		DoAndReturn(func(_ context.Context, _ map[metrics.CollectorKey]*metrics.Collector) error {
 			close(done)
 
 			return nil
 		})
-	suite.mockStorage.EXPECT().CreateMetrics(context.Background(), gomock.Any()).AnyTimes()
+	suite.mockStorage.EXPECT().CreateMetrics(gomock.Any(), gomock.Any()).AnyTimes()
 
 	// Wait for metrics to be sent or timeout
 	select {
@@ -126,25 +212,109 @@ func (suite *ManagerSuite) TestRecordShortURLRequestAsyncSuccessNoCollectors() {
 	stopManager()
 }
 
+func (suite *ManagerSuite) TestStartFirstFlushWithinJitter() {
+	const epsilonInMS = 50
+
+	config := metrics.DefaultConfig()
+	config.MetricsIntervalInMS = 200
+	config.MetricsIntervalJitterInMS = 100
+
+	flushTimes := make(chan time.Time, 2)
+	firstFlush := func(_ context.Context, _ map[metrics.CollectorKey]*metrics.Collector) error {
+		flushTimes <- time.Now()
+
+		return nil
+	}
+
+	for range 2 {
+		storage := mocks.NewMockStorage(suite.mockCtrl)
+		storage.EXPECT().CreateMetrics(gomock.Any(), gomock.Any()).DoAndReturn(firstFlush)
+		storage.EXPECT().CreateMetrics(gomock.Any(), gomock.Any()).AnyTimes()
+
+		manager, err := metrics.NewManager(config, storage, suite.botDetector, nil, suite.mockLogger)
+		suite.Require().NoError(err)
+
+		stopManager := manager.Start()
+		defer stopManager()
+	}
+
+	timeout := time.Duration(config.MetricsIntervalInMS+config.MetricsIntervalJitterInMS+epsilonInMS) * time.Millisecond
+	var firsts [2]time.Time
+	for i := range firsts {
+		select {
+		case firsts[i] = <-flushTimes:
+		case <-time.After(timeout):
+			suite.T().Fatal("Timeout waiting for metrics to be flushed")
+		}
+	}
+
+	maxSeparation := time.Duration(config.MetricsIntervalJitterInMS+epsilonInMS) * time.Millisecond
+	suite.LessOrEqual(firsts[1].Sub(firsts[0]), maxSeparation)
+}
+
+func (suite *ManagerSuite) TestStartAdaptsIntervalToBursts() {
+	config := metrics.DefaultConfig()
+	config.MetricsIntervalInMS = 200
+	config.MinMetricsIntervalInMS = 50
+	config.RequestChannelSize = 10
+
+	flushing := make(chan struct{})
+	release := make(chan struct{})
+	storage := mocks.NewMockStorage(suite.mockCtrl)
+	gomock.InOrder(
+		// The manager is kept busy flushing while the burst fills the request channel
+		storage.EXPECT().CreateMetrics(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(context.Context, map[metrics.CollectorKey]*metrics.Collector) error {
+				close(flushing)
+				<-release
+
+				return nil
+			}),
+		storage.EXPECT().CreateMetrics(gomock.Any(), gomock.Any()).AnyTimes(),
+	)
+
+	manager, err := metrics.NewManager(config, storage, suite.botDetector, nil, suite.mockLogger)
+	suite.Require().NoError(err)
+	suite.Equal(200*time.Millisecond, manager.FlushInterval())
+
+	stopManager := manager.Start()
+	defer stopManager()
+
+	<-flushing
+	for range 9 {
+		manager.RecordShortURLRequest(tenant.Default, "AABBCC", "127.0.0.1", browserUserAgent, 0)
+	}
+	close(release)
+
+	suite.Eventually(func() bool {
+		return manager.FlushInterval() == 100*time.Millisecond
+	}, time.Second, 5*time.Millisecond)
+
+	// The burst is drained before the next flush, so the configured interval is restored
+	suite.Eventually(func() bool {
+		return manager.FlushInterval() == 200*time.Millisecond
+	}, time.Second, 5*time.Millisecond)
+}
+
 func (suite *ManagerSuite) TestRecordShortURLRequestAsyncFailStorageError() {
 	shortURLId0 := "AABBCC"
 	shortURLId1 := "DDEEFF"
 	host0 := "127.0.0.1"
 	host1 := "127.0.0.2"
 
-	expectedCollectors := map[string]*metrics.Collector{
-		shortURLId0: {
+	expectedCollectors := map[metrics.CollectorKey]*metrics.Collector{
+		{ShortURLId: shortURLId0}: {
 			ShortURLId: shortURLId0,
 			Visits:     3,
-			Visitors: map[string]struct{}{
+			Visitors: map[string]time.Time{
 				host0: {},
 				host1: {},
 			},
 		},
-		shortURLId1: {
+		{ShortURLId: shortURLId1}: {
 			ShortURLId: shortURLId1,
 			Visits:     1,
-			Visitors: map[string]struct{}{
+			Visitors: map[string]time.Time{
 				host1: {},
 			},
 		},
@@ -155,20 +325,169 @@ func (suite *ManagerSuite) TestRecordShortURLRequestAsyncFailStorageError() {
 
 	stopManager := suite.manager.Start()
 
-	suite.mockStorage.EXPECT().CreateMetrics(context.Background(), gomock.Any()).
-		DoAndReturn(func(_ context.Context, collectors map[string]*metrics.Collector) error {
-			suite.EqualValues(expectedCollectors, collectors)
+	suite.mockStorage.EXPECT().CreateMetrics(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, collectors map[metrics.CollectorKey]*metrics.Collector) error {
+			suite.EqualValues(expectedCollectors, withoutVisitTimes(collectors))
 
 			close(done)
 			return expectedError
 		})
 	suite.mockLogger.EXPECT().Error("creating metrics in storage", logging.ErrorKey, expectedError)
-	suite.mockStorage.EXPECT().CreateMetrics(context.Background(), gomock.Any()).AnyTimes()
+	suite.mockStorage.EXPECT().CreateMetrics(gomock.Any(), gomock.Any()).AnyTimes()
+
+	suite.manager.RecordShortURLRequest(tenant.Default, shortURLId0, host0, browserUserAgent, 0)
+	suite.manager.RecordShortURLRequest(tenant.Default, shortURLId0, host1, browserUserAgent, 0)
+	suite.manager.RecordShortURLRequest(tenant.Default, shortURLId0, host0, browserUserAgent, 0)
+	suite.manager.RecordShortURLRequest(tenant.Default, shortURLId1, host1, browserUserAgent, 0)
+
+	select {
+	case <-done:
+	case <-time.After(time.Duration(suite.config.MetricsIntervalInMS*2) * time.Millisecond):
+		suite.Fail("Timeout waiting for metrics to be processed")
+	}
+
+	stopManager()
+}
+
+func (suite *ManagerSuite) TestRecordShortURLRequestDropsOnFullChannel() {
+	suite.config.RequestChannelSize = 2
+	suite.config.RecordRequestTimeoutInMS = 1
+	overflow := 3
+
+	manager, err := metrics.NewManager(suite.config, suite.mockStorage, suite.botDetector, nil, suite.mockLogger)
+	suite.Require().NoError(err)
+
+	for range suite.config.RequestChannelSize + overflow {
+		manager.RecordShortURLRequest(tenant.Default, "AABBCC", "127.0.0.1", browserUserAgent, 0)
+	}
+
+	suite.Equal(uint64(overflow), manager.DroppedRequests())
+}
+
+func (suite *ManagerSuite) TestRecordShortURLRequestReportsErrorOnFullChannel() {
+	suite.config.RequestChannelSize = 1
+	suite.config.RecordRequestTimeoutInMS = 1
+
+	manager, err := metrics.NewManager(suite.config, suite.mockStorage, suite.botDetector, nil, suite.mockLogger)
+	suite.Require().NoError(err)
+
+	manager.RecordShortURLRequest(tenant.Default, "AABBCC", "127.0.0.1", browserUserAgent, 0)
+	suite.Empty(manager.Errors())
+
+	manager.RecordShortURLRequest(tenant.Default, "AABBCC", "127.0.0.1", browserUserAgent, 0)
+	suite.Require().Len(manager.Errors(), 1)
+	suite.ErrorIs(<-manager.Errors(), metrics.ErrRecordRequestTimeout)
+}
+
+// newStoppedManager returns a stopped manager whose request channel is full, so every request recorded is dropped
+func (suite *ManagerSuite) newStoppedManager() *metrics.Manager {
+	suite.config.RequestChannelSize = 1
+	suite.config.RecordRequestTimeoutInMS = 60000
+
+	manager, err := metrics.NewManager(suite.config, suite.mockStorage, suite.botDetector, nil, suite.mockLogger)
+	suite.Require().NoError(err)
+
+	manager.RecordShortURLRequest(tenant.Default, "AABBCC", "127.0.0.1", browserUserAgent, 0)
+	manager.Stop()
+
+	return manager
+}
+
+func (suite *ManagerSuite) TestRecordShortURLRequestReportsErrorOnStop() {
+	manager := suite.newStoppedManager()
 
-	suite.manager.RecordShortURLRequestAsync(shortURLId0, host0)
-	suite.manager.RecordShortURLRequestAsync(shortURLId0, host1)
-	suite.manager.RecordShortURLRequestAsync(shortURLId0, host0)
-	suite.manager.RecordShortURLRequestAsync(shortURLId1, host1)
+	manager.RecordShortURLRequest(tenant.Default, "AABBCC", "127.0.0.1", browserUserAgent, 0)
+	suite.Require().Len(manager.Errors(), 1)
+	suite.ErrorIs(<-manager.Errors(), metrics.ErrManagerStopped)
+}
+
+func (suite *ManagerSuite) TestRecordShortURLRequestDropsErrorsOnFullErrorChannel() {
+	manager := suite.newStoppedManager()
+
+	for range 150 {
+		manager.RecordShortURLRequest(tenant.Default, "AABBCC", "127.0.0.1", browserUserAgent, 0)
+	}
+
+	suite.Len(manager.Errors(), 100)
+	suite.Equal(uint64(150), manager.DroppedRequests())
+}
+
+func (suite *ManagerSuite) TestRecordShortURLRequestAsyncSuccessAnonymizeIPs() {
+	suite.config.AnonymizeIPs = true
+	shortURLId := "AABBCC"
+
+	expectedCollectors := map[metrics.CollectorKey]*metrics.Collector{
+		{ShortURLId: shortURLId}: {
+			ShortURLId: shortURLId,
+			Visits:     2,
+			Visitors: map[string]time.Time{
+				"192.168.1.0": {},
+			},
+		},
+	}
+
+	done := make(chan struct{})
+
+	stopManager := suite.manager.Start()
+
+	suite.mockStorage.EXPECT().CreateMetrics(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, collectors map[metrics.CollectorKey]*metrics.Collector) error {
+			suite.EqualValues(expectedCollectors, withoutVisitTimes(collectors))
+
+			close(done)
+			return nil
+		})
+	suite.mockStorage.EXPECT().CreateMetrics(gomock.Any(), gomock.Any()).AnyTimes()
+
+	suite.manager.RecordShortURLRequest(tenant.Default, shortURLId, "192.168.1.42:54321", browserUserAgent, 0)
+	suite.manager.RecordShortURLRequest(tenant.Default, shortURLId, "192.168.1.43:54322", browserUserAgent, 0)
+
+	select {
+	case <-done:
+	case <-time.After(time.Duration(suite.config.MetricsIntervalInMS*2) * time.Millisecond):
+		suite.Fail("Timeout waiting for metrics to be processed")
+	}
+
+	stopManager()
+}
+
+func (suite *ManagerSuite) TestRecordShortURLRequestSuccessCountryBreakdown() {
+	suite.config.AnonymizeIPs = true
+	shortURLId := "AABBCC"
+
+	mockGeoLookup := mocks.NewMockGeoLookup(suite.mockCtrl)
+	mockGeoLookup.EXPECT().Country("192.168.1.42:54321").Return("AR", nil)
+	mockGeoLookup.EXPECT().Country("192.168.2.42:54321").Return("AR", nil)
+	mockGeoLookup.EXPECT().Country("192.168.3.42:54321").Return("", nil)
+	mockGeoLookup.EXPECT().Country("192.168.4.42:54321").Return("", errors.New("lookup error"))
+	mockGeoLookup.EXPECT().Country("66.249.66.1:54321").Return("US", nil)
+
+	manager, err := metrics.NewManager(suite.config, suite.mockStorage, suite.botDetector, mockGeoLookup, suite.mockLogger)
+	suite.Require().NoError(err)
+
+	done := make(chan struct{})
+
+	stopManager := manager.Start()
+
+	suite.mockStorage.EXPECT().CreateMetrics(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, collectors map[metrics.CollectorKey]*metrics.Collector) error {
+			collector := collectors[metrics.CollectorKey{ShortURLId: shortURLId}]
+			suite.Require().NotNil(collector)
+			suite.Equal(int64(4), collector.Visits)
+			suite.Equal(int64(1), collector.BotVisits)
+			suite.Equal(map[string]int64{"AR": 2}, collector.CountryBreakdown)
+
+			close(done)
+			return nil
+		})
+	suite.mockStorage.EXPECT().CreateMetrics(gomock.Any(), gomock.Any()).AnyTimes()
+
+	googlebot := "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)"
+	manager.RecordShortURLRequest(tenant.Default, shortURLId, "192.168.1.42:54321", browserUserAgent, 0)
+	manager.RecordShortURLRequest(tenant.Default, shortURLId, "192.168.2.42:54321", browserUserAgent, 0)
+	manager.RecordShortURLRequest(tenant.Default, shortURLId, "192.168.3.42:54321", browserUserAgent, 0)
+	manager.RecordShortURLRequest(tenant.Default, shortURLId, "192.168.4.42:54321", browserUserAgent, 0)
+	manager.RecordShortURLRequest(tenant.Default, shortURLId, "66.249.66.1:54321", googlebot, 0)
 
 	select {
 	case <-done:
@@ -179,6 +498,65 @@ func (suite *ManagerSuite) TestRecordShortURLRequestAsyncFailStorageError() {
 	stopManager()
 }
 
+func (suite *ManagerSuite) TestRecordShortURLRequestSuccessLatency() {
+	googlebot := "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)"
+	done := make(chan struct{})
+
+	stopManager := suite.manager.Start()
+
+	suite.mockStorage.EXPECT().CreateMetrics(gomock.Any(), gomock.Any()).AnyTimes()
+	suite.mockStorage.EXPECT().CreateLatencyMetrics(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, latencies map[metrics.CollectorKey]*metrics.Latency) error {
+			// Repeated visits and bots are served redirects too, so their latency counts
+			suite.Equal(map[metrics.CollectorKey]*metrics.Latency{
+				{ShortURLId: "AABBCC"}: {Requests: 3, P50Ns: 2000, P95Ns: 3000, P99Ns: 3000},
+			}, latencies)
+
+			close(done)
+			return nil
+		})
+	suite.mockStorage.EXPECT().CreateLatencyMetrics(gomock.Any(), gomock.Any()).AnyTimes()
+
+	suite.manager.RecordShortURLRequest(tenant.Default, "AABBCC", "127.0.0.1", browserUserAgent, 3*time.Microsecond)
+	suite.manager.RecordShortURLRequest(tenant.Default, "AABBCC", "127.0.0.1", browserUserAgent, time.Microsecond)
+	suite.manager.RecordShortURLRequest(tenant.Default, "AABBCC", "66.249.66.1", googlebot, 2*time.Microsecond)
+
+	select {
+	case <-done:
+	case <-time.After(time.Duration(suite.config.MetricsIntervalInMS*2) * time.Millisecond):
+		suite.Fail("Timeout waiting for latency metrics to be processed")
+	}
+
+	stopManager()
+}
+
+func (suite *ManagerSuite) TestGetShortURLLatencySuccess() {
+	ctx := context.Background()
+	from := time.Now().AddDate(0, 0, -1)
+	to := time.Now()
+
+	expectedLatencies := []*metrics.Latency{{Timestamp: from, Requests: 3, P50Ns: 1000, P95Ns: 2000, P99Ns: 3000}}
+
+	suite.mockStorage.EXPECT().GetLatencyMetrics(ctx, tenant.Default, "AABBCC", from, to).Return(expectedLatencies, nil)
+
+	latencies, err := suite.manager.GetShortURLLatency(ctx, "AABBCC", from, to)
+	suite.Require().NoError(err)
+	suite.Equal(expectedLatencies, latencies)
+}
+
+func (suite *ManagerSuite) TestGetShortURLLatencyFailStorageError() {
+	ctx := context.Background()
+	from := time.Now().AddDate(0, 0, -1)
+	to := time.Now()
+
+	suite.mockStorage.EXPECT().GetLatencyMetrics(ctx, tenant.Default, "AABBCC", from, to).Return(nil, errors.New("storage error"))
+	suite.mockLogger.EXPECT().Error("failed to get latency metrics from storage", gomock.Any())
+
+	latencies, err := suite.manager.GetShortURLLatency(ctx, "AABBCC", from, to)
+	suite.Error(err)
+	suite.Nil(latencies)
+}
+
 func (suite *ManagerSuite) TestGetShortURLMetricsSuccess() {
 	ctx := context.Background()
 	shortURLId := "AABBCC"
@@ -193,7 +571,7 @@ func (suite *ManagerSuite) TestGetShortURLMetricsSuccess() {
 		To:           to,
 	}
 
-	suite.mockStorage.EXPECT().GetMetrics(ctx, shortURLId, from, to).Return(expectedMetrics, true, nil)
+	suite.mockStorage.EXPECT().GetMetrics(ctx, tenant.Default, shortURLId, from, to).Return(expectedMetrics, true, nil)
 
 	metricsResult, err := suite.manager.GetShortURLMetrics(ctx, shortURLId, from, to)
 	suite.Require().NoError(err)
@@ -214,9 +592,550 @@ func (suite *ManagerSuite) TestGetShortURLMetricsSuccessMetricsNotFound() {
 		To:           to,
 	}
 
-	suite.mockStorage.EXPECT().GetMetrics(ctx, shortURLId, from, to).Return(nil, false, nil)
+	suite.mockStorage.EXPECT().GetMetrics(ctx, tenant.Default, shortURLId, from, to).Return(nil, false, nil)
 
 	metricsResult, err := suite.manager.GetShortURLMetrics(ctx, shortURLId, from, to)
 	suite.Require().NoError(err)
 	suite.Equal(expectedMetrics, metricsResult)
 }
+
+func (suite *ManagerSuite) TestGetShortURLMetricsBulkSuccess() {
+	ctx := context.Background()
+	from := time.Now().AddDate(0, 0, -1)
+	to := time.Now()
+
+	storedMetrics := &metrics.Metrics{ShortURLId: "AABBCC", Visits: 42, UniqueVisits: 7, From: from, To: to}
+	suite.mockStorage.EXPECT().GetMetricsBulk(ctx, tenant.Default, []string{"AABBCC", "DDEEFF"}, from, to).
+		Return(map[string]*metrics.Metrics{"AABBCC": storedMetrics}, nil)
+
+	metricsResult, err := suite.manager.GetShortURLMetricsBulk(ctx, []string{"AABBCC", "DDEEFF"}, from, to)
+	suite.Require().NoError(err)
+	suite.Equal(map[string]*metrics.Metrics{
+		"AABBCC": storedMetrics,
+		"DDEEFF": {ShortURLId: "DDEEFF", From: from, To: to},
+	}, metricsResult)
+}
+
+func (suite *ManagerSuite) TestGetShortURLMetricsBulkFailStorageError() {
+	ctx := context.Background()
+	storageErr := errors.New("storage error")
+	suite.mockStorage.EXPECT().GetMetricsBulk(ctx, tenant.Default, []string{"AABBCC"}, gomock.Any(), gomock.Any()).
+		Return(nil, storageErr)
+	suite.mockLogger.EXPECT().Error("failed to get metrics from storage", gomock.Any())
+
+	_, err := suite.manager.GetShortURLMetricsBulk(ctx, []string{"AABBCC"}, time.Now(), time.Now())
+	suite.ErrorIs(err, storageErr)
+}
+
+func (suite *ManagerSuite) TestStreamShortURLMetricsSuccess() {
+	ctx := context.Background()
+	shortURLId := "AABBCC"
+	from := time.Now().AddDate(0, 0, -1)
+	to := time.Now()
+
+	intervals := []*metrics.Interval{
+		{Timestamp: from, Visits: 3, UniqueVisits: 2},
+		{Timestamp: to, Visits: 1, UniqueVisits: 1},
+	}
+
+	suite.mockStorage.EXPECT().StreamMetrics(ctx, tenant.Default, shortURLId, from, to, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, _ string, _, _ time.Time, fn func(*metrics.Interval) error) error {
+			for _, interval := range intervals {
+				if err := fn(interval); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+
+	var streamed []*metrics.Interval
+	err := suite.manager.StreamShortURLMetrics(ctx, shortURLId, from, to, func(interval *metrics.Interval) error {
+		streamed = append(streamed, interval)
+
+		return nil
+	})
+	suite.Require().NoError(err)
+	suite.Equal(intervals, streamed)
+}
+
+func (suite *ManagerSuite) TestStreamShortURLMetricsFailCallbackError() {
+	ctx := context.Background()
+	callbackErr := errors.New("write error")
+
+	suite.mockStorage.EXPECT().StreamMetrics(ctx, tenant.Default, "AABBCC", gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, _ string, _, _ time.Time, fn func(*metrics.Interval) error) error {
+			return fmt.Errorf("streaming: %w", fn(&metrics.Interval{}))
+		})
+
+	err := suite.manager.StreamShortURLMetrics(ctx, "AABBCC", time.Now(), time.Now(), func(*metrics.Interval) error {
+		return callbackErr
+	})
+	suite.Equal(callbackErr, err)
+}
+
+func (suite *ManagerSuite) TestStreamShortURLMetricsFailStorageError() {
+	ctx := context.Background()
+	storageErr := errors.New("storage error")
+
+	suite.mockStorage.EXPECT().StreamMetrics(ctx, tenant.Default, "AABBCC", gomock.Any(), gomock.Any(), gomock.Any()).Return(storageErr)
+	suite.mockLogger.EXPECT().Error(gomock.Any(), gomock.Any())
+
+	err := suite.manager.StreamShortURLMetrics(ctx, "AABBCC", time.Now(), time.Now(), func(*metrics.Interval) error {
+		return nil
+	})
+	suite.ErrorIs(err, storageErr)
+}
+
+func (suite *ManagerSuite) TestSnapshotSuccess() {
+	suite.mockStorage.EXPECT().CreateMetrics(gomock.Any(), gomock.Any()).AnyTimes()
+
+	stopManager := suite.manager.Start()
+	defer stopManager()
+
+	suite.manager.RecordShortURLRequest(tenant.Default, "AABBCC", "127.0.0.1", browserUserAgent, 0)
+	suite.manager.RecordShortURLRequest(tenant.Default, "AABBCC", "127.0.0.2", browserUserAgent, 0)
+	suite.manager.RecordShortURLRequest(tenant.Default, "AABBCC", "127.0.0.1", browserUserAgent, 0)
+	suite.manager.RecordShortURLRequest(tenant.Default, "DDEEFF", "127.0.0.1", browserUserAgent, 0)
+	suite.manager.RecordShortURLRequest("acme", "AABBCC", "127.0.0.3", browserUserAgent, 0)
+
+	expectedSnapshot := map[string]metrics.CollectorSnapshot{
+		"AABBCC": {ShortURLId: "AABBCC", Visits: 3, UniqueVisits: 2},
+		"DDEEFF": {ShortURLId: "DDEEFF", Visits: 1, UniqueVisits: 1},
+	}
+
+	// Recorded requests are buffered, so they may still be pending when the first snapshot is taken
+	suite.Eventually(func() bool {
+		snapshot, err := suite.manager.Snapshot(context.Background())
+
+		return err == nil && assert.ObjectsAreEqual(expectedSnapshot, snapshot)
+	}, time.Second, 10*time.Millisecond)
+
+	snapshot, err := suite.manager.Snapshot(tenant.WithID(context.Background(), "acme"))
+	suite.Require().NoError(err)
+	suite.Equal(map[string]metrics.CollectorSnapshot{
+		"AABBCC": {ShortURLId: "AABBCC", Visits: 1, UniqueVisits: 1},
+	}, snapshot)
+}
+
+func (suite *ManagerSuite) TestSnapshotSuccessConcurrentRecords() {
+	suite.mockStorage.EXPECT().CreateMetrics(gomock.Any(), gomock.Any()).AnyTimes()
+
+	stopManager := suite.manager.Start()
+	defer stopManager()
+
+	const requests = 100
+
+	var wg sync.WaitGroup
+	for i := range requests {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			suite.manager.RecordShortURLRequest(tenant.Default, "AABBCC", fmt.Sprintf("10.0.0.%d", i), browserUserAgent, 0)
+		}()
+	}
+
+	// Snapshots are taken while the requests are recorded
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for range requests {
+			_, err := suite.manager.Snapshot(context.Background())
+			suite.NoError(err)
+		}
+	}()
+
+	wg.Wait()
+
+	suite.waitForSnapshot(map[string]metrics.CollectorSnapshot{
+		"AABBCC": {ShortURLId: "AABBCC", Visits: requests, UniqueVisits: requests},
+	})
+}
+
+func (suite *ManagerSuite) TestSubscribeSuccess() {
+	suite.mockStorage.EXPECT().CreateMetrics(gomock.Any(), gomock.Any()).AnyTimes()
+
+	stopManager := suite.manager.Start()
+	defer stopManager()
+
+	events, unsubscribe := suite.manager.Subscribe(context.Background(), "AABBCC")
+	defer unsubscribe()
+
+	suite.manager.RecordShortURLRequest("acme", "AABBCC", "127.0.0.2", browserUserAgent, 0)
+	suite.manager.RecordShortURLRequest(tenant.Default, "DDEEFF", "127.0.0.3", browserUserAgent, 0)
+	suite.manager.RecordShortURLRequest(tenant.Default, "AABBCC", "127.0.0.1", browserUserAgent, 0)
+
+	select {
+	case event := <-events:
+		suite.Equal("AABBCC", event.ShortURLId)
+		suite.Equal("127.0.0.1", event.IP)
+		suite.False(event.Timestamp.IsZero())
+	case <-time.After(time.Second):
+		suite.Fail("click event not delivered")
+	}
+	suite.Empty(events)
+}
+
+func (suite *ManagerSuite) TestSubscribeSuccessUnsubscribed() {
+	suite.mockStorage.EXPECT().CreateMetrics(gomock.Any(), gomock.Any()).AnyTimes()
+
+	stopManager := suite.manager.Start()
+	defer stopManager()
+
+	events, unsubscribe := suite.manager.Subscribe(context.Background(), "AABBCC")
+	unsubscribe()
+
+	suite.manager.RecordShortURLRequest(tenant.Default, "AABBCC", "127.0.0.1", browserUserAgent, 0)
+	suite.waitForSnapshot(map[string]metrics.CollectorSnapshot{
+		"AABBCC": {ShortURLId: "AABBCC", Visits: 1, UniqueVisits: 1},
+	})
+
+	suite.Empty(events)
+}
+
+// waitForSnapshot waits until the snapshot of the default tenant is the expected one, recorded requests are buffered
+// so they may still be pending. Requests are processed in order, so the last recorded one must change the snapshot.
+func (suite *ManagerSuite) waitForSnapshot(expectedSnapshot map[string]metrics.CollectorSnapshot) {
+	suite.Eventually(func() bool {
+		snapshot, err := suite.manager.Snapshot(context.Background())
+
+		return err == nil && assert.ObjectsAreEqual(expectedSnapshot, snapshot)
+	}, 2*time.Second, 10*time.Millisecond)
+}
+
+func (suite *ManagerSuite) TestVisitWindowCountsOneVisitPerVisitor() {
+	suite.config.MetricsIntervalInMS = int(time.Hour.Milliseconds())
+	suite.config.VisitWindowInSeconds = 60
+	suite.mockStorage.EXPECT().CreateMetrics(gomock.Any(), gomock.Any()).AnyTimes()
+
+	stopManager := suite.manager.Start()
+	defer stopManager()
+
+	suite.manager.RecordShortURLRequest(tenant.Default, "AABBCC", "127.0.0.1", browserUserAgent, 0)
+	suite.manager.RecordShortURLRequest(tenant.Default, "AABBCC", "127.0.0.1", browserUserAgent, 0)
+	suite.manager.RecordShortURLRequest(tenant.Default, "DDEEFF", "127.0.0.1", browserUserAgent, 0)
+	suite.manager.RecordShortURLRequest(tenant.Default, "AABBCC", "127.0.0.1", browserUserAgent, 0)
+	suite.manager.RecordShortURLRequest(tenant.Default, "AABBCC", "127.0.0.2", browserUserAgent, 0)
+
+	suite.waitForSnapshot(map[string]metrics.CollectorSnapshot{
+		"AABBCC": {ShortURLId: "AABBCC", Visits: 2, UniqueVisits: 2},
+		"DDEEFF": {ShortURLId: "DDEEFF", Visits: 1, UniqueVisits: 1},
+	})
+}
+
+func (suite *ManagerSuite) TestVisitWindowSpansFlushes() {
+	suite.config.MetricsIntervalInMS = int(time.Hour.Milliseconds())
+	suite.config.VisitWindowInSeconds = 60
+	suite.mockStorage.EXPECT().CreateMetrics(gomock.Any(), gomock.Any()).AnyTimes()
+
+	stopManager := suite.manager.Start()
+	defer stopManager()
+
+	suite.manager.RecordShortURLRequest(tenant.Default, "AABBCC", "127.0.0.1", browserUserAgent, 0)
+	suite.waitForSnapshot(map[string]metrics.CollectorSnapshot{
+		"AABBCC": {ShortURLId: "AABBCC", Visits: 1, UniqueVisits: 1},
+	})
+	suite.Require().NoError(suite.manager.Drain(context.Background()))
+
+	suite.manager.RecordShortURLRequest(tenant.Default, "AABBCC", "127.0.0.1", browserUserAgent, 0)
+	suite.manager.RecordShortURLRequest(tenant.Default, "AABBCC", "127.0.0.2", browserUserAgent, 0)
+
+	suite.waitForSnapshot(map[string]metrics.CollectorSnapshot{
+		"AABBCC": {ShortURLId: "AABBCC", Visits: 1, UniqueVisits: 1},
+	})
+}
+
+func (suite *ManagerSuite) TestVisitWindowCountsVisitorAgainAfterWindow() {
+	suite.config.MetricsIntervalInMS = int(time.Hour.Milliseconds())
+	suite.config.VisitWindowInSeconds = 1
+	suite.mockStorage.EXPECT().CreateMetrics(gomock.Any(), gomock.Any()).AnyTimes()
+
+	stopManager := suite.manager.Start()
+	defer stopManager()
+
+	suite.manager.RecordShortURLRequest(tenant.Default, "AABBCC", "127.0.0.1", browserUserAgent, 0)
+	suite.waitForSnapshot(map[string]metrics.CollectorSnapshot{
+		"AABBCC": {ShortURLId: "AABBCC", Visits: 1, UniqueVisits: 1},
+	})
+
+	time.Sleep(1100 * time.Millisecond)
+
+	suite.manager.RecordShortURLRequest(tenant.Default, "AABBCC", "127.0.0.1", browserUserAgent, 0)
+	suite.manager.RecordShortURLRequest(tenant.Default, "AABBCC", "127.0.0.2", browserUserAgent, 0)
+
+	suite.waitForSnapshot(map[string]metrics.CollectorSnapshot{
+		"AABBCC": {ShortURLId: "AABBCC", Visits: 3, UniqueVisits: 2},
+	})
+}
+
+func (suite *ManagerSuite) TestConfigValidateMinMetricsInterval() {
+	config := metrics.DefaultConfig()
+	suite.NoError(config.Validate())
+
+	config.MinMetricsIntervalInMS = 0
+	suite.Error(config.Validate())
+
+	config.MinMetricsIntervalInMS = config.MetricsIntervalInMS + 1
+	suite.Error(config.Validate())
+}
+
+func (suite *ManagerSuite) TestConfigValidateVisitWindow() {
+	config := metrics.DefaultConfig()
+	suite.NoError(config.Validate())
+
+	config.VisitWindowInSeconds = -1
+	suite.Error(config.Validate())
+}
+
+func (suite *ManagerSuite) TestRecordShortURLRequestCountsBotsApart() {
+	suite.config.MetricsIntervalInMS = int(time.Hour.Milliseconds())
+	suite.config.VisitWindowInSeconds = 60
+	suite.mockStorage.EXPECT().CreateMetrics(gomock.Any(), gomock.Any()).AnyTimes()
+
+	stopManager := suite.manager.Start()
+	defer stopManager()
+
+	googlebot := "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)"
+	suite.manager.RecordShortURLRequest(tenant.Default, "AABBCC", "127.0.0.1", browserUserAgent, 0)
+	suite.manager.RecordShortURLRequest(tenant.Default, "AABBCC", "66.249.66.1", googlebot, 0)
+	suite.manager.RecordShortURLRequest(tenant.Default, "AABBCC", "66.249.66.1", googlebot, 0)
+	suite.manager.RecordShortURLRequest(tenant.Default, "DDEEFF", "66.249.66.1", googlebot, 0)
+
+	suite.waitForSnapshot(map[string]metrics.CollectorSnapshot{
+		"AABBCC": {ShortURLId: "AABBCC", Visits: 1, UniqueVisits: 1, BotVisits: 2},
+		"DDEEFF": {ShortURLId: "DDEEFF", BotVisits: 1},
+	})
+}
+
+func (suite *ManagerSuite) TestStartRollupSuccess() {
+	rolledUp := make(chan struct{}, 1)
+	suite.mockStorage.EXPECT().RollupMetrics(gomock.Any(), "day", gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, before time.Time) error {
+			suite.WithinDuration(time.Now(), before, time.Second)
+
+			select {
+			case rolledUp <- struct{}{}:
+			default:
+			}
+
+			return nil
+		}).MinTimes(1)
+
+	stopRollup := suite.manager.StartRollup(context.Background(), 10*time.Millisecond)
+
+	select {
+	case <-rolledUp:
+	case <-time.After(100 * time.Millisecond):
+		suite.Fail("Waiting for metrics rollup timed out")
+	}
+
+	stopRollup()
+}
+
+func (suite *ManagerSuite) TestStartRollupFailStorageError() {
+	failed := make(chan struct{}, 1)
+	suite.mockStorage.EXPECT().RollupMetrics(gomock.Any(), "day", gomock.Any()).Return(errors.New("storage error")).MinTimes(1)
+	suite.mockLogger.EXPECT().Error(gomock.Any(), gomock.Any()).Do(func(string, ...interface{}) {
+		select {
+		case failed <- struct{}{}:
+		default:
+		}
+	}).MinTimes(1)
+
+	stopRollup := suite.manager.StartRollup(context.Background(), 10*time.Millisecond)
+
+	select {
+	case <-failed:
+	case <-time.After(100 * time.Millisecond):
+		suite.Fail("Waiting for metrics rollup error timed out")
+	}
+
+	stopRollup()
+}
+
+func (suite *ManagerSuite) TestConfigValidateRollup() {
+	config := metrics.DefaultConfig()
+	suite.NoError(config.Validate())
+
+	config.RollupIntervalInHours = 0
+	suite.Error(config.Validate())
+
+	config = metrics.DefaultConfig()
+	config.RollupGranularity = "minute"
+	suite.Error(config.Validate())
+}
+
+func (suite *ManagerSuite) TestConfigValidateDriver() {
+	config := metrics.DefaultConfig()
+	suite.Equal(metrics.DriverPostgres, config.Driver)
+
+	config.Driver = "mysql"
+	suite.Error(config.Validate())
+
+	config.Driver = metrics.DriverInfluxDB
+	suite.Error(config.Validate())
+
+	config.InfluxDB = &metrics.InfluxDBConfig{URL: "http://localhost:8086", Org: "org"}
+	suite.Error(config.Validate())
+
+	config.InfluxDB.Bucket = "metrics"
+	suite.NoError(config.Validate())
+}
+
+func (suite *ManagerSuite) TestSnapshotSuccessNoCollectors() {
+	suite.mockStorage.EXPECT().CreateMetrics(gomock.Any(), gomock.Any()).AnyTimes()
+
+	stopManager := suite.manager.Start()
+	defer stopManager()
+
+	snapshot, err := suite.manager.Snapshot(context.Background())
+	suite.Require().NoError(err)
+	suite.Empty(snapshot)
+}
+
+func (suite *ManagerSuite) TestSnapshotFailManagerStopped() {
+	suite.mockStorage.EXPECT().CreateMetrics(gomock.Any(), gomock.Any()).AnyTimes()
+
+	suite.manager.Start()
+	suite.manager.Stop()
+
+	_, err := suite.manager.Snapshot(context.Background())
+	suite.ErrorIs(err, metrics.ErrManagerStopped)
+}
+
+func (suite *ManagerSuite) TestDrainSuccess() {
+	// Only the drain may flush before the manager is stopped
+	suite.config.MetricsIntervalInMS = 60000
+
+	stopManager := suite.manager.Start()
+	defer stopManager()
+
+	suite.manager.RecordShortURLRequest(tenant.Default, "AABBCC", "127.0.0.1", browserUserAgent, 0)
+	suite.manager.RecordShortURLRequest(tenant.Default, "AABBCC", "127.0.0.2", browserUserAgent, 0)
+	suite.manager.RecordShortURLRequest(tenant.Default, "DDEEFF", "127.0.0.1", browserUserAgent, 0)
+
+	suite.Eventually(func() bool {
+		snapshot, err := suite.manager.Snapshot(context.Background())
+
+		return err == nil && len(snapshot) == 2 && snapshot["AABBCC"].Visits == 2
+	}, time.Second, 10*time.Millisecond)
+
+	expectedCollectors := map[metrics.CollectorKey]*metrics.Collector{
+		{ShortURLId: "AABBCC"}: {
+			ShortURLId: "AABBCC",
+			Visits:     2,
+			Visitors:   map[string]time.Time{"127.0.0.1": {}, "127.0.0.2": {}},
+		},
+		{ShortURLId: "DDEEFF"}: {
+			ShortURLId: "DDEEFF",
+			Visits:     1,
+			Visitors:   map[string]time.Time{"127.0.0.1": {}},
+		},
+	}
+
+	gomock.InOrder(
+		suite.mockStorage.EXPECT().CreateMetrics(gomock.Any(), gomock.Cond(func(collectors map[metrics.CollectorKey]*metrics.Collector) bool {
+			return assert.ObjectsAreEqual(expectedCollectors, withoutVisitTimes(collectors))
+		})).Return(nil),
+		suite.mockStorage.EXPECT().CreateMetrics(gomock.Any(), map[metrics.CollectorKey]*metrics.Collector{}).AnyTimes(),
+	)
+
+	err := suite.manager.Drain(context.Background())
+	suite.Require().NoError(err)
+
+	snapshot, err := suite.manager.Snapshot(context.Background())
+	suite.Require().NoError(err)
+	suite.Empty(snapshot)
+}
+
+func (suite *ManagerSuite) TestDrainSuccessResetsFlushedCollectors() {
+	suite.config.MetricsIntervalInMS = 60000
+
+	stopManager := suite.manager.Start()
+	defer stopManager()
+
+	suite.manager.RecordShortURLRequest(tenant.Default, "AABBCC", "127.0.0.1", browserUserAgent, 0)
+	suite.Eventually(func() bool {
+		snapshot, err := suite.manager.Snapshot(context.Background())
+
+		return err == nil && len(snapshot) == 1
+	}, time.Second, 10*time.Millisecond)
+
+	var flushed *metrics.Collector
+	gomock.InOrder(
+		suite.mockStorage.EXPECT().CreateMetrics(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, collectors map[metrics.CollectorKey]*metrics.Collector) error {
+				flushed = collectors[metrics.CollectorKey{ShortURLId: "AABBCC"}]
+
+				return nil
+			}),
+		suite.mockStorage.EXPECT().CreateMetrics(gomock.Any(), gomock.Any()).AnyTimes(),
+	)
+
+	suite.Require().NoError(suite.manager.Drain(context.Background()))
+	suite.Require().NotNil(flushed)
+	suite.Zero(flushed.Visits)
+	suite.Empty(flushed.Visitors)
+}
+
+func (suite *ManagerSuite) TestDrainFailStorageError() {
+	suite.config.MetricsIntervalInMS = 60000
+	suite.mockLogger.EXPECT().Error(gomock.Any(), gomock.Any()).AnyTimes()
+
+	stopManager := suite.manager.Start()
+	defer stopManager()
+
+	gomock.InOrder(
+		suite.mockStorage.EXPECT().CreateMetrics(gomock.Any(), gomock.Any()).Return(errors.New("storage error")),
+		suite.mockStorage.EXPECT().CreateMetrics(gomock.Any(), gomock.Any()).AnyTimes(),
+	)
+
+	err := suite.manager.Drain(context.Background())
+	suite.Error(err)
+}
+
+func (suite *ManagerSuite) TestDrainSuccessSkippedMetrics() {
+	suite.config.MetricsIntervalInMS = 60000
+
+	stopManager := suite.manager.Start()
+	defer stopManager()
+
+	// Metrics of short URLs deleted while they were collected are skipped, the flush still succeeds
+	gomock.InOrder(
+		suite.mockStorage.EXPECT().CreateMetrics(gomock.Any(), gomock.Any()).
+			Return(&metrics.SkippedMetricsError{Keys: []metrics.CollectorKey{{ShortURLId: "AABBCC"}}}),
+		suite.mockStorage.EXPECT().CreateMetrics(gomock.Any(), gomock.Any()).AnyTimes(),
+	)
+
+	err := suite.manager.Drain(context.Background())
+	suite.NoError(err)
+}
+
+func (suite *ManagerSuite) TestDrainFailManagerStopped() {
+	suite.mockStorage.EXPECT().CreateMetrics(gomock.Any(), gomock.Any()).AnyTimes()
+
+	suite.manager.Start()
+	suite.manager.Stop()
+
+	err := suite.manager.Drain(context.Background())
+	suite.ErrorIs(err, metrics.ErrManagerStopped)
+}
+
+func (suite *ManagerSuite) TestCollectorReset() {
+	collector := &metrics.Collector{
+		ShortURLId:       "AABBCC",
+		Visits:           3,
+		BotVisits:        1,
+		Visitors:         map[string]time.Time{"127.0.0.1": time.Now(), "127.0.0.2": time.Now()},
+		CountryBreakdown: map[string]int64{"AR": 3},
+	}
+
+	collector.Reset()
+
+	suite.Equal("AABBCC", collector.ShortURLId)
+	suite.Zero(collector.Visits)
+	suite.Zero(collector.BotVisits)
+	suite.Empty(collector.Visitors)
+	suite.NotNil(collector.Visitors)
+	suite.Nil(collector.CountryBreakdown)
+}
@@ -42,8 +42,46 @@ func (m *MockStorage) EXPECT() *MockStorageMockRecorder {
 	return m.recorder
 }
 
+// CreateLatencyMetrics mocks base method.
+func (m *MockStorage) CreateLatencyMetrics(ctx context.Context, latencies map[metrics.CollectorKey]*metrics.Latency) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateLatencyMetrics", ctx, latencies)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateLatencyMetrics indicates an expected call of CreateLatencyMetrics.
+func (mr *MockStorageMockRecorder) CreateLatencyMetrics(ctx, latencies any) *MockStorageCreateLatencyMetricsCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLatencyMetrics", reflect.TypeOf((*MockStorage)(nil).CreateLatencyMetrics), ctx, latencies)
+	return &MockStorageCreateLatencyMetricsCall{Call: call}
+}
+
+// MockStorageCreateLatencyMetricsCall wrap *gomock.Call
+type MockStorageCreateLatencyMetricsCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockStorageCreateLatencyMetricsCall) Return(arg0 error) *MockStorageCreateLatencyMetricsCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockStorageCreateLatencyMetricsCall) Do(f func(context.Context, map[metrics.CollectorKey]*metrics.Latency) error) *MockStorageCreateLatencyMetricsCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockStorageCreateLatencyMetricsCall) DoAndReturn(f func(context.Context, map[metrics.CollectorKey]*metrics.Latency) error) *MockStorageCreateLatencyMetricsCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // CreateMetrics mocks base method.
-func (m *MockStorage) CreateMetrics(ctx context.Context, arg1 map[string]*metrics.Collector) error {
+func (m *MockStorage) CreateMetrics(ctx context.Context, arg1 map[metrics.CollectorKey]*metrics.Collector) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "CreateMetrics", ctx, arg1)
 	ret0, _ := ret[0].(error)
@@ -69,21 +107,60 @@ func (c *MockStorageCreateMetricsCall) Return(arg0 error) *MockStorageCreateMetr
 }
 
 // Do rewrite *gomock.Call.Do
-func (c *MockStorageCreateMetricsCall) Do(f func(context.Context, map[string]*metrics.Collector) error) *MockStorageCreateMetricsCall {
+func (c *MockStorageCreateMetricsCall) Do(f func(context.Context, map[metrics.CollectorKey]*metrics.Collector) error) *MockStorageCreateMetricsCall {
 	c.Call = c.Call.Do(f)
 	return c
 }
 
 // DoAndReturn rewrite *gomock.Call.DoAndReturn
-func (c *MockStorageCreateMetricsCall) DoAndReturn(f func(context.Context, map[string]*metrics.Collector) error) *MockStorageCreateMetricsCall {
+func (c *MockStorageCreateMetricsCall) DoAndReturn(f func(context.Context, map[metrics.CollectorKey]*metrics.Collector) error) *MockStorageCreateMetricsCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// GetLatencyMetrics mocks base method.
+func (m *MockStorage) GetLatencyMetrics(ctx context.Context, tenantID, shortURLId string, from, to time.Time) ([]*metrics.Latency, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLatencyMetrics", ctx, tenantID, shortURLId, from, to)
+	ret0, _ := ret[0].([]*metrics.Latency)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLatencyMetrics indicates an expected call of GetLatencyMetrics.
+func (mr *MockStorageMockRecorder) GetLatencyMetrics(ctx, tenantID, shortURLId, from, to any) *MockStorageGetLatencyMetricsCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatencyMetrics", reflect.TypeOf((*MockStorage)(nil).GetLatencyMetrics), ctx, tenantID, shortURLId, from, to)
+	return &MockStorageGetLatencyMetricsCall{Call: call}
+}
+
+// MockStorageGetLatencyMetricsCall wrap *gomock.Call
+type MockStorageGetLatencyMetricsCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockStorageGetLatencyMetricsCall) Return(arg0 []*metrics.Latency, arg1 error) *MockStorageGetLatencyMetricsCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockStorageGetLatencyMetricsCall) Do(f func(context.Context, string, string, time.Time, time.Time) ([]*metrics.Latency, error)) *MockStorageGetLatencyMetricsCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockStorageGetLatencyMetricsCall) DoAndReturn(f func(context.Context, string, string, time.Time, time.Time) ([]*metrics.Latency, error)) *MockStorageGetLatencyMetricsCall {
 	c.Call = c.Call.DoAndReturn(f)
 	return c
 }
 
 // GetMetrics mocks base method.
-func (m *MockStorage) GetMetrics(ctx context.Context, shortURLId string, from, to time.Time) (*metrics.Metrics, bool, error) {
+func (m *MockStorage) GetMetrics(ctx context.Context, tenantID, shortURLId string, from, to time.Time) (*metrics.Metrics, bool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetMetrics", ctx, shortURLId, from, to)
+	ret := m.ctrl.Call(m, "GetMetrics", ctx, tenantID, shortURLId, from, to)
 	ret0, _ := ret[0].(*metrics.Metrics)
 	ret1, _ := ret[1].(bool)
 	ret2, _ := ret[2].(error)
@@ -91,9 +168,9 @@ func (m *MockStorage) GetMetrics(ctx context.Context, shortURLId string, from, t
 }
 
 // GetMetrics indicates an expected call of GetMetrics.
-func (mr *MockStorageMockRecorder) GetMetrics(ctx, shortURLId, from, to any) *MockStorageGetMetricsCall {
+func (mr *MockStorageMockRecorder) GetMetrics(ctx, tenantID, shortURLId, from, to any) *MockStorageGetMetricsCall {
 	mr.mock.ctrl.T.Helper()
-	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMetrics", reflect.TypeOf((*MockStorage)(nil).GetMetrics), ctx, shortURLId, from, to)
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMetrics", reflect.TypeOf((*MockStorage)(nil).GetMetrics), ctx, tenantID, shortURLId, from, to)
 	return &MockStorageGetMetricsCall{Call: call}
 }
 
@@ -109,13 +186,253 @@ func (c *MockStorageGetMetricsCall) Return(arg0 *metrics.Metrics, arg1 bool, arg
 }
 
 // Do rewrite *gomock.Call.Do
-func (c *MockStorageGetMetricsCall) Do(f func(context.Context, string, time.Time, time.Time) (*metrics.Metrics, bool, error)) *MockStorageGetMetricsCall {
+func (c *MockStorageGetMetricsCall) Do(f func(context.Context, string, string, time.Time, time.Time) (*metrics.Metrics, bool, error)) *MockStorageGetMetricsCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockStorageGetMetricsCall) DoAndReturn(f func(context.Context, string, string, time.Time, time.Time) (*metrics.Metrics, bool, error)) *MockStorageGetMetricsCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// GetMetricsBulk mocks base method.
+func (m *MockStorage) GetMetricsBulk(ctx context.Context, tenantID string, shortURLIds []string, from, to time.Time) (map[string]*metrics.Metrics, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMetricsBulk", ctx, tenantID, shortURLIds, from, to)
+	ret0, _ := ret[0].(map[string]*metrics.Metrics)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMetricsBulk indicates an expected call of GetMetricsBulk.
+func (mr *MockStorageMockRecorder) GetMetricsBulk(ctx, tenantID, shortURLIds, from, to any) *MockStorageGetMetricsBulkCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMetricsBulk", reflect.TypeOf((*MockStorage)(nil).GetMetricsBulk), ctx, tenantID, shortURLIds, from, to)
+	return &MockStorageGetMetricsBulkCall{Call: call}
+}
+
+// MockStorageGetMetricsBulkCall wrap *gomock.Call
+type MockStorageGetMetricsBulkCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockStorageGetMetricsBulkCall) Return(arg0 map[string]*metrics.Metrics, arg1 error) *MockStorageGetMetricsBulkCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockStorageGetMetricsBulkCall) Do(f func(context.Context, string, []string, time.Time, time.Time) (map[string]*metrics.Metrics, error)) *MockStorageGetMetricsBulkCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockStorageGetMetricsBulkCall) DoAndReturn(f func(context.Context, string, []string, time.Time, time.Time) (map[string]*metrics.Metrics, error)) *MockStorageGetMetricsBulkCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// RollupMetrics mocks base method.
+func (m *MockStorage) RollupMetrics(ctx context.Context, granularity string, before time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RollupMetrics", ctx, granularity, before)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RollupMetrics indicates an expected call of RollupMetrics.
+func (mr *MockStorageMockRecorder) RollupMetrics(ctx, granularity, before any) *MockStorageRollupMetricsCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RollupMetrics", reflect.TypeOf((*MockStorage)(nil).RollupMetrics), ctx, granularity, before)
+	return &MockStorageRollupMetricsCall{Call: call}
+}
+
+// MockStorageRollupMetricsCall wrap *gomock.Call
+type MockStorageRollupMetricsCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockStorageRollupMetricsCall) Return(arg0 error) *MockStorageRollupMetricsCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockStorageRollupMetricsCall) Do(f func(context.Context, string, time.Time) error) *MockStorageRollupMetricsCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockStorageRollupMetricsCall) DoAndReturn(f func(context.Context, string, time.Time) error) *MockStorageRollupMetricsCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// StreamMetrics mocks base method.
+func (m *MockStorage) StreamMetrics(ctx context.Context, tenantID, shortURLId string, from, to time.Time, fn func(*metrics.Interval) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamMetrics", ctx, tenantID, shortURLId, from, to, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StreamMetrics indicates an expected call of StreamMetrics.
+func (mr *MockStorageMockRecorder) StreamMetrics(ctx, tenantID, shortURLId, from, to, fn any) *MockStorageStreamMetricsCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamMetrics", reflect.TypeOf((*MockStorage)(nil).StreamMetrics), ctx, tenantID, shortURLId, from, to, fn)
+	return &MockStorageStreamMetricsCall{Call: call}
+}
+
+// MockStorageStreamMetricsCall wrap *gomock.Call
+type MockStorageStreamMetricsCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockStorageStreamMetricsCall) Return(arg0 error) *MockStorageStreamMetricsCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockStorageStreamMetricsCall) Do(f func(context.Context, string, string, time.Time, time.Time, func(*metrics.Interval) error) error) *MockStorageStreamMetricsCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockStorageStreamMetricsCall) DoAndReturn(f func(context.Context, string, string, time.Time, time.Time, func(*metrics.Interval) error) error) *MockStorageStreamMetricsCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// MockBotDetector is a mock of BotDetector interface.
+type MockBotDetector struct {
+	ctrl     *gomock.Controller
+	recorder *MockBotDetectorMockRecorder
+	isgomock struct{}
+}
+
+// MockBotDetectorMockRecorder is the mock recorder for MockBotDetector.
+type MockBotDetectorMockRecorder struct {
+	mock *MockBotDetector
+}
+
+// NewMockBotDetector creates a new mock instance.
+func NewMockBotDetector(ctrl *gomock.Controller) *MockBotDetector {
+	mock := &MockBotDetector{ctrl: ctrl}
+	mock.recorder = &MockBotDetectorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBotDetector) EXPECT() *MockBotDetectorMockRecorder {
+	return m.recorder
+}
+
+// IsBot mocks base method.
+func (m *MockBotDetector) IsBot(userAgent string) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsBot", userAgent)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsBot indicates an expected call of IsBot.
+func (mr *MockBotDetectorMockRecorder) IsBot(userAgent any) *MockBotDetectorIsBotCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsBot", reflect.TypeOf((*MockBotDetector)(nil).IsBot), userAgent)
+	return &MockBotDetectorIsBotCall{Call: call}
+}
+
+// MockBotDetectorIsBotCall wrap *gomock.Call
+type MockBotDetectorIsBotCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockBotDetectorIsBotCall) Return(arg0 bool) *MockBotDetectorIsBotCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockBotDetectorIsBotCall) Do(f func(string) bool) *MockBotDetectorIsBotCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockBotDetectorIsBotCall) DoAndReturn(f func(string) bool) *MockBotDetectorIsBotCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// MockGeoLookup is a mock of GeoLookup interface.
+type MockGeoLookup struct {
+	ctrl     *gomock.Controller
+	recorder *MockGeoLookupMockRecorder
+	isgomock struct{}
+}
+
+// MockGeoLookupMockRecorder is the mock recorder for MockGeoLookup.
+type MockGeoLookupMockRecorder struct {
+	mock *MockGeoLookup
+}
+
+// NewMockGeoLookup creates a new mock instance.
+func NewMockGeoLookup(ctrl *gomock.Controller) *MockGeoLookup {
+	mock := &MockGeoLookup{ctrl: ctrl}
+	mock.recorder = &MockGeoLookupMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGeoLookup) EXPECT() *MockGeoLookupMockRecorder {
+	return m.recorder
+}
+
+// Country mocks base method.
+func (m *MockGeoLookup) Country(ip string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Country", ip)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Country indicates an expected call of Country.
+func (mr *MockGeoLookupMockRecorder) Country(ip any) *MockGeoLookupCountryCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Country", reflect.TypeOf((*MockGeoLookup)(nil).Country), ip)
+	return &MockGeoLookupCountryCall{Call: call}
+}
+
+// MockGeoLookupCountryCall wrap *gomock.Call
+type MockGeoLookupCountryCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockGeoLookupCountryCall) Return(arg0 string, arg1 error) *MockGeoLookupCountryCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockGeoLookupCountryCall) Do(f func(string) (string, error)) *MockGeoLookupCountryCall {
 	c.Call = c.Call.Do(f)
 	return c
 }
 
 // DoAndReturn rewrite *gomock.Call.DoAndReturn
-func (c *MockStorageGetMetricsCall) DoAndReturn(f func(context.Context, string, time.Time, time.Time) (*metrics.Metrics, bool, error)) *MockStorageGetMetricsCall {
+func (c *MockGeoLookupCountryCall) DoAndReturn(f func(string) (string, error)) *MockGeoLookupCountryCall {
 	c.Call = c.Call.DoAndReturn(f)
 	return c
 }
@@ -7,15 +7,45 @@ type Metrics struct {
 	ShortURLId   string
 	Visits       int64
 	UniqueVisits int64
+	BotVisits    int64
+	// TopCountries are the countries with the most visits, most visited first
+	TopCountries []CountryStat
 	From         time.Time
 	To           time.Time
 }
 
+// CountryStat is the number of visits from a country, identified by its ISO 3166-1 alpha-2 code
+type CountryStat struct {
+	Country string
+	Visits  int64
+}
+
+// Interval are the metrics for a short URL flushed at Timestamp, or rolled up into the period starting at Timestamp
+type Interval struct {
+	Timestamp    time.Time
+	Visits       int64
+	UniqueVisits int64
+	BotVisits    int64
+}
+
+// CollectorKey identifies the collector of a short URL, short URL ids are only unique within a tenant
+type CollectorKey struct {
+	TenantId   string
+	ShortURLId string
+}
+
 // Collector is used to collect metrics for a short URL before flushing them to the database
 type Collector struct {
+	TenantId   string
 	ShortURLId string
 	Visits     int64
-	Visitors   map[string]struct{}
+	// BotVisits are the requests of bots, they are not counted as visits
+	BotVisits int64
+	// Visitors are the visitor IPs counted since the last flush and the time they were last counted at
+	Visitors map[string]time.Time
+	// CountryBreakdown are the visits by country code, only visits from a known country are counted. It is nil until
+	// the first of them.
+	CountryBreakdown map[string]int64
 }
 
 // UniqueVisits returns the number of unique visitors for the short URL
@@ -23,8 +53,32 @@ func (m *Collector) UniqueVisits() int64 {
 	return int64(len(m.Visitors))
 }
 
+// Reset zeroes the metrics collected, the visitors and country breakdown maps are replaced so the memory they hold can
+// be released
+func (m *Collector) Reset() {
+	m.Visits = 0
+	m.BotVisits = 0
+	m.Visitors = make(map[string]time.Time)
+	m.CountryBreakdown = nil
+}
+
+// CollectorSnapshot is a copy of the metrics collected for a short URL since the last flush
+type CollectorSnapshot struct {
+	ShortURLId   string
+	Visits       int64
+	UniqueVisits int64
+	BotVisits    int64
+}
+
 // Request represents a request to collect metrics for a short URL
 type Request struct {
+	TenantId   string
 	ShortURLId string
 	VisitorId  string
+	Timestamp  time.Time
+	IsBot      bool
+	// Country is the country code of the visitor IP, empty when it is unknown
+	Country string
+	// LatencyNs is the time it took to resolve the short URL of the request, from it reaching the redirect handler
+	LatencyNs int64
 }
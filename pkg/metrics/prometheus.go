@@ -0,0 +1,11 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var droppedRequestsCounter = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "short_url_metrics_dropped_total",
+	Help: "Total number of short URL requests dropped by the metrics manager",
+})
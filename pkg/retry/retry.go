@@ -0,0 +1,94 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"time"
+)
+
+// permanentError wraps an error that should not be retried
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+// Permanent marks an error as non-retriable, Retry returns the wrapped error immediately
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &permanentError{err: err}
+}
+
+// Retry calls fn until it succeeds, returns a non-retriable error, maxAttempts is reached or ctx is done.
+// The wait between attempts grows exponentially from backoff and includes random jitter.
+func Retry(ctx context.Context, maxAttempts int, backoff time.Duration, fn func() error) error {
+	if maxAttempts <= 0 {
+		return errors.New("max attempts must be greater than 0")
+	}
+
+	var err error
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			if err != nil {
+				return err
+			}
+
+			return ctxErr
+		}
+
+		err = fn()
+		if err == nil {
+			return nil
+		}
+
+		var permanent *permanentError
+		if errors.As(err, &permanent) {
+			return permanent.err
+		}
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return err
+		}
+
+		if attempt == maxAttempts-1 {
+			break
+		}
+
+		timer := time.NewTimer(delay(backoff, attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+
+			return err
+		case <-timer.C:
+		}
+	}
+
+	return err
+}
+
+// delay returns the exponential backoff for the given attempt with up to 50% jitter
+func delay(backoff time.Duration, attempt int) time.Duration {
+	if backoff <= 0 {
+		return 0
+	}
+
+	d := backoff << attempt
+	if d <= 0 {
+		// overflow
+		d = backoff
+	}
+
+	half := d / 2
+
+	return half + rand.N(half+1)
+}
@@ -0,0 +1,100 @@
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/AvalosM/short-url-service/pkg/retry"
+)
+
+type RetrySuite struct {
+	suite.Suite
+}
+
+func TestRetrySuite(t *testing.T) {
+	suite.Run(t, new(RetrySuite))
+}
+
+func (suite *RetrySuite) TestRetrySuccessAfterTransientErrors() {
+	attempts := 0
+	err := retry.Retry(context.Background(), 3, time.Millisecond, func() error {
+		attempts++
+		if attempts < 3 {
+			return errors.New("transient error")
+		}
+
+		return nil
+	})
+	suite.Require().NoError(err)
+	suite.Equal(3, attempts)
+}
+
+func (suite *RetrySuite) TestRetryFailMaxAttempts() {
+	expectedError := errors.New("transient error")
+
+	attempts := 0
+	err := retry.Retry(context.Background(), 3, time.Millisecond, func() error {
+		attempts++
+
+		return expectedError
+	})
+	suite.Require().ErrorIs(err, expectedError)
+	suite.Equal(3, attempts)
+}
+
+func (suite *RetrySuite) TestRetryStopsOnPermanentError() {
+	expectedError := errors.New("permanent error")
+
+	attempts := 0
+	err := retry.Retry(context.Background(), 3, time.Millisecond, func() error {
+		attempts++
+
+		return retry.Permanent(expectedError)
+	})
+	suite.Require().Equal(expectedError, err)
+	suite.Equal(1, attempts)
+}
+
+func (suite *RetrySuite) TestRetryStopsOnContextCancellation() {
+	ctx, cancel := context.WithCancel(context.Background())
+	expectedError := errors.New("transient error")
+
+	attempts := 0
+	err := retry.Retry(ctx, 5, time.Hour, func() error {
+		attempts++
+		cancel()
+
+		return expectedError
+	})
+	suite.Require().ErrorIs(err, expectedError)
+	suite.Equal(1, attempts)
+}
+
+func (suite *RetrySuite) TestRetryStopsOnContextError() {
+	attempts := 0
+	err := retry.Retry(context.Background(), 5, time.Millisecond, func() error {
+		attempts++
+
+		return context.DeadlineExceeded
+	})
+	suite.Require().ErrorIs(err, context.DeadlineExceeded)
+	suite.Equal(1, attempts)
+}
+
+func (suite *RetrySuite) TestRetryFailContextAlreadyDone() {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	attempts := 0
+	err := retry.Retry(ctx, 3, time.Millisecond, func() error {
+		attempts++
+
+		return nil
+	})
+	suite.Require().ErrorIs(err, context.Canceled)
+	suite.Zero(attempts)
+}
@@ -0,0 +1,45 @@
+package shorturl
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// Audit log operations
+const (
+	OperationCreate = "create"
+	OperationDelete = "delete"
+	OperationStatus = "status"
+)
+
+// AuditEntry is an append-only record of an operation on a short URL
+type AuditEntry struct {
+	Id         int64
+	Operation  string
+	ShortURLId string
+	Actor      string
+	Payload    json.RawMessage
+	CreatedAt  time.Time
+}
+
+// AuditFilter paginates the audit log of a short URL
+type AuditFilter struct {
+	ShortURLId string
+	Limit      int
+	Offset     int
+}
+
+type actorKey struct{}
+
+// WithActor returns a copy of ctx carrying the actor recorded in the audit log of the operations made with it
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext returns the actor carried by ctx, empty if there is none
+func ActorFromContext(ctx context.Context) string {
+	actor, _ := ctx.Value(actorKey{}).(string)
+
+	return actor
+}
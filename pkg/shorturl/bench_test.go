@@ -0,0 +1,132 @@
+package shorturl_test
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"testing"
+
+	"github.com/AvalosM/short-url-service/pkg/shorturl"
+)
+
+// Baselines on an Intel Xeon, regressions well above them are worth a look:
+//
+//	BenchmarkGenerateIdWithOffset              41 ns/op      8 B/op    1 allocs/op
+//	BenchmarkGenerateIdWithOffset_Parallel     47 ns/op      8 B/op    1 allocs/op
+//	BenchmarkCreateShortURL_NoCollision      1741 ns/op    599 B/op    7 allocs/op
+//	BenchmarkCreateShortURL_MaxCollisions    4260 ns/op   6080 B/op   65 allocs/op
+//
+// The single allocation of GenerateIdWithOffset is the id itself, the strings.Builder is grown to the id length up
+// front so encoding the hash never reallocates it.
+
+// memoryStorage keeps short URLs in memory, only the methods creating short URLs are implemented
+type memoryStorage struct {
+	shorturl.Storage
+	mu        sync.Mutex
+	shortURLs map[string]*shorturl.ShortURL
+	// taken makes every id look taken by the short URL of another long URL
+	taken bool
+}
+
+func newMemoryStorage() *memoryStorage {
+	return &memoryStorage{shortURLs: make(map[string]*shorturl.ShortURL)}
+}
+
+func (s *memoryStorage) TryCreateShortURL(_ context.Context, tenantID string, shortURL *shorturl.ShortURL) (*shorturl.ShortURL, bool, error) {
+	if s.taken {
+		return nil, true, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := tenantID + "/" + shortURL.Id
+	if _, ok := s.shortURLs[key]; ok {
+		return nil, true, nil
+	}
+	s.shortURLs[key] = shortURL
+
+	return shortURL, false, nil
+}
+
+func (s *memoryStorage) GetShortURL(_ context.Context, tenantID string, shortURLId string) (*shorturl.ShortURL, bool, error) {
+	if s.taken {
+		return &shorturl.ShortURL{Id: shortURLId, LongURL: "https://example.org"}, true, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	shortURL, ok := s.shortURLs[tenantID+"/"+shortURLId]
+
+	return shortURL, ok, nil
+}
+
+func newBenchmarkManager(b *testing.B, storage shorturl.Storage) *shorturl.Manager {
+	manager, err := shorturl.NewManager(shorturl.DefaultConfig(), storage, struct{ shorturl.Cache }{}, slog.New(slog.DiscardHandler))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	return manager
+}
+
+func BenchmarkGenerateIdWithOffset(b *testing.B) {
+	manager := newBenchmarkManager(b, newMemoryStorage())
+	b.ReportAllocs()
+
+	offset := uint(0)
+	for b.Loop() {
+		if _, err := manager.GenerateIdWithOffset("https://example.com", offset); err != nil {
+			b.Fatal(err)
+		}
+		offset++
+	}
+}
+
+func BenchmarkGenerateIdWithOffset_Parallel(b *testing.B) {
+	manager := newBenchmarkManager(b, newMemoryStorage())
+	b.ReportAllocs()
+
+	b.RunParallel(func(pb *testing.PB) {
+		offset := uint(0)
+		for pb.Next() {
+			if _, err := manager.GenerateIdWithOffset("https://example.com", offset); err != nil {
+				b.Error(err)
+
+				return
+			}
+			offset++
+		}
+	})
+}
+
+func BenchmarkCreateShortURL_NoCollision(b *testing.B) {
+	manager := newBenchmarkManager(b, newMemoryStorage())
+	ctx := context.Background()
+	b.ReportAllocs()
+
+	i := 0
+	for b.Loop() {
+		if _, err := manager.CreateShortURL(ctx, fmt.Sprintf("https://example.com/%d", i), nil); err != nil {
+			b.Fatal(err)
+		}
+		i++
+	}
+}
+
+func BenchmarkCreateShortURL_MaxCollisions(b *testing.B) {
+	storage := newMemoryStorage()
+	storage.taken = true
+	manager := newBenchmarkManager(b, storage)
+	ctx := context.Background()
+	b.ReportAllocs()
+
+	for b.Loop() {
+		// Every id generated is taken, so creating fails once MaxShortURLIdRetries ids were tried
+		if _, err := manager.CreateShortURL(ctx, "https://example.com", nil); err == nil {
+			b.Fatal("short URL created with every id taken")
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package shorturl
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+)
+
+// Collision strategies of short URL ids, they choose the hash an id is generated from after offset collisions
+const (
+	// CollisionStrategyQuadratic probes h(k) + i/2 + i^2/2
+	CollisionStrategyQuadratic = "quadratic"
+	// CollisionStrategyLinear probes h(k) + i
+	CollisionStrategyLinear = "linear"
+	// CollisionStrategyDoubleHash probes h(k) + i*h2(k), with h2 the FNV-1 hash of h(k)
+	CollisionStrategyDoubleHash = "double_hash"
+)
+
+// collisionFns are the collision functions of each collision strategy
+var collisionFns = map[string]func(hash uint64, offset uint) uint64{
+	CollisionStrategyQuadratic:  quadraticProbe,
+	CollisionStrategyLinear:     linearProbe,
+	CollisionStrategyDoubleHash: doubleHashProbe,
+}
+
+// https://en.wikipedia.org/wiki/Quadratic_probing
+// m = 2^64
+// h(k,i) = h(k) + i/2 + i^2/2
+func quadraticProbe(hash uint64, offset uint) uint64 {
+	return hash + uint64((offset+(offset*offset))/2)
+}
+
+// https://en.wikipedia.org/wiki/Linear_probing
+// h(k,i) = h(k) + i
+func linearProbe(hash uint64, offset uint) uint64 {
+	return hash + uint64(offset)
+}
+
+// https://en.wikipedia.org/wiki/Double_hashing
+// h(k,i) = h(k) + i*h2(k), h2 is made odd so the probes of a key visit every value modulo 2^64
+func doubleHashProbe(hash uint64, offset uint) uint64 {
+	if offset == 0 {
+		return hash
+	}
+
+	var key [8]byte
+	binary.BigEndian.PutUint64(key[:], hash)
+
+	h2 := fnv.New64()
+	_, _ = h2.Write(key[:])
+
+	return hash + uint64(offset)*(h2.Sum64()|1)
+}
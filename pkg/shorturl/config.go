@@ -1,18 +1,80 @@
 package shorturl
 
-import "fmt"
+import (
+	"fmt"
+	"math"
+	"net/http"
+)
+
+const (
+	minShortURLIdLength = 4
+	maxShortURLIdLength = 12
+	// maxLongURLColumnLength is the longest long URL the short_urls table takes
+	maxLongURLColumnLength = 2048
+)
 
 // Config holds the configuration for the short URL manager
 type Config struct {
-	MaxShortURLIdRetries      int `json:"max_short_url_id_retries"`
-	ShortURLCacheTTLInSeconds int `json:"short_url_cache_ttl_in_seconds"`
+	MaxShortURLIdRetries      int `json:"max_short_url_id_retries" jsonschema:"minimum=1,description=Number of ids tried for a long URL before creating its short URL fails"`
+	ShortURLCacheTTLInSeconds int `json:"short_url_cache_ttl_in_seconds" jsonschema:"minimum=1,description=How long long URLs are cached"`
+	MaxStorageRetries         int `json:"max_storage_retries" jsonschema:"minimum=0,description=Number of times storage reads and idempotent writes are retried on transient errors"`
+	StorageRetryBackoffInMS   int `json:"storage_retry_backoff_in_ms" jsonschema:"minimum=1,description=Backoff between storage retries"`
+	CacheWriteTimeoutInMS     int `json:"cache_write_timeout_in_ms" jsonschema:"minimum=1,description=Timeout of the cache writes made after serving a redirect"`
+	// DefaultRedirectCode is the HTTP status of the redirect of short URLs without their own redirect code
+	DefaultRedirectCode int `json:"default_redirect_code" jsonschema:"enum=301,enum=302,enum=307,description=HTTP status of the redirect of short URLs without their own redirect code"`
+	// ExpiryCleanupIntervalInSeconds is how often expired short URLs are deleted from storage
+	ExpiryCleanupIntervalInSeconds int `json:"expiry_cleanup_interval_in_seconds" jsonschema:"minimum=1,description=How often expired short URLs are deleted from storage"`
+	// AllowHTTP accepts http:// long URLs in addition to https:// ones
+	AllowHTTP bool `json:"allow_http" jsonschema:"description=Accept http:// long URLs in addition to https:// ones"`
+	// DashboardCacheTTLInSeconds is how long dashboard stats are cached
+	DashboardCacheTTLInSeconds int `json:"dashboard_cache_ttl_in_seconds" jsonschema:"minimum=1,description=How long dashboard stats are cached"`
+	// ClickRateLimitRPS is the number of clicks per second each short URL can take, 0 disables the limit
+	ClickRateLimitRPS int `json:"click_rate_limit_rps" jsonschema:"minimum=0,description=Clicks per second each short URL can take. 0 disables the limit"`
+	// ClickRateLimitBurst is the number of clicks each short URL can take at once above ClickRateLimitRPS
+	ClickRateLimitBurst int `json:"click_rate_limit_burst" jsonschema:"minimum=0,description=Clicks each short URL can take at once above click_rate_limit_rps"`
+	// CollisionStrategy is how new ids are probed when the id of a long URL is taken: quadratic, linear or double_hash
+	CollisionStrategy string `json:"collision_strategy" jsonschema:"enum=quadratic,enum=linear,enum=double_hash,description=How new ids are probed when the id of a long URL is taken"`
+	// ExpectedUniqueURLs is the number of short URLs the service is expected to hold, the id space must fit it
+	// SafetyFactor times over
+	ExpectedUniqueURLs int64 `json:"expected_unique_urls" jsonschema:"minimum=0,description=Number of short URLs the service is expected to hold"`
+	// SafetyFactor is how many times the id space must be larger than ExpectedUniqueURLs, collisions get more
+	// frequent as the id space fills up
+	SafetyFactor float64 `json:"safety_factor" jsonschema:"minimum=1,description=How many times the id space must be larger than expected_unique_urls"`
+	// WarmCacheBatchSize is the number of short URLs read from storage and cached at a time when warming the cache
+	WarmCacheBatchSize int `json:"warm_cache_batch_size" jsonschema:"minimum=1,description=Number of short URLs read and cached at a time when warming the cache"`
+	// WarmCacheTopN is the number of most visited short URLs cached when warming the cache with them
+	WarmCacheTopN int `json:"warm_cache_top_n" jsonschema:"minimum=1,description=Number of most visited short URLs cached when warming the cache with them"`
+	// CollisionRateAlertThreshold is the rate of id collisions per short URL created above which a warning is logged,
+	// 0 disables the warning
+	CollisionRateAlertThreshold float64 `json:"collision_rate_alert_threshold" jsonschema:"minimum=0,description=Rate of id collisions per short URL created above which a warning is logged. 0 disables the warning"`
+	// MaxLongURLLength is the length in bytes of the longest long URL accepted once canonicalized, it cannot exceed
+	// the 2048 bytes the short_urls table takes
+	MaxLongURLLength int `json:"max_long_url_length" jsonschema:"minimum=1,maximum=2048,description=Length in bytes of the longest long URL accepted"`
+	// DeniedHosts are the hosts whose long URLs are not shortened, their subdomains are denied too
+	DeniedHosts []string `json:"denied_hosts,omitempty" jsonschema:"description=Hosts whose long URLs are not shortened. Their subdomains are denied too"`
 }
 
 // DefaultConfig configuration
 func DefaultConfig() *Config {
 	return &Config{
-		MaxShortURLIdRetries:      10,
-		ShortURLCacheTTLInSeconds: 60 * 60, // 1 hour
+		MaxShortURLIdRetries:           10,
+		ShortURLCacheTTLInSeconds:      60 * 60, // 1 hour
+		MaxStorageRetries:              2,
+		StorageRetryBackoffInMS:        50,
+		CacheWriteTimeoutInMS:          500,
+		DefaultRedirectCode:            http.StatusFound,
+		ExpiryCleanupIntervalInSeconds: 5 * 60, // 5 minutes
+		AllowHTTP:                      false,
+		DashboardCacheTTLInSeconds:     60,
+		ClickRateLimitRPS:              0,
+		ClickRateLimitBurst:            0,
+		CollisionStrategy:              CollisionStrategyQuadratic,
+		ExpectedUniqueURLs:             100_000_000,
+		SafetyFactor:                   10,
+		WarmCacheBatchSize:             500,
+		WarmCacheTopN:                  1000,
+		CollisionRateAlertThreshold:    0.1,
+		MaxLongURLLength:               2048,
 	}
 }
 
@@ -24,5 +86,83 @@ func (c *Config) Validate() error {
 	if c.ShortURLCacheTTLInSeconds <= 0 {
 		return fmt.Errorf("ShortURLCacheTTLInSeconds must be greater than 0")
 	}
+	if c.MaxStorageRetries < 0 {
+		return fmt.Errorf("MaxStorageRetries must be greater than or equal to 0")
+	}
+	if c.StorageRetryBackoffInMS <= 0 {
+		return fmt.Errorf("StorageRetryBackoffInMS must be greater than 0")
+	}
+	if c.CacheWriteTimeoutInMS <= 0 {
+		return fmt.Errorf("CacheWriteTimeoutInMS must be greater than 0")
+	}
+	if !ValidRedirectCode(c.DefaultRedirectCode) {
+		return fmt.Errorf("DefaultRedirectCode must be 301, 302 or 307")
+	}
+	if c.ExpiryCleanupIntervalInSeconds <= 0 {
+		return fmt.Errorf("ExpiryCleanupIntervalInSeconds must be greater than 0")
+	}
+	if c.DashboardCacheTTLInSeconds <= 0 {
+		return fmt.Errorf("DashboardCacheTTLInSeconds must be greater than 0")
+	}
+	if c.ClickRateLimitRPS < 0 {
+		return fmt.Errorf("ClickRateLimitRPS must be greater than or equal to 0")
+	}
+	if c.ClickRateLimitRPS > 0 && c.ClickRateLimitBurst <= 0 {
+		return fmt.Errorf("ClickRateLimitBurst must be greater than 0 when ClickRateLimitRPS is set")
+	}
+	if _, ok := collisionFns[c.CollisionStrategy]; !ok {
+		return fmt.Errorf("CollisionStrategy must be quadratic, linear or double_hash")
+	}
+	if c.ExpectedUniqueURLs < 0 {
+		return fmt.Errorf("ExpectedUniqueURLs must be greater than or equal to 0")
+	}
+	if c.SafetyFactor < 1 {
+		return fmt.Errorf("SafetyFactor must be greater than or equal to 1")
+	}
+	if err := validateIdSpace(shortURLIdLength, len(charset), c.SafetyFactor, c.ExpectedUniqueURLs); err != nil {
+		return err
+	}
+	if c.WarmCacheBatchSize <= 0 {
+		return fmt.Errorf("WarmCacheBatchSize must be greater than 0")
+	}
+	if c.WarmCacheTopN <= 0 {
+		return fmt.Errorf("WarmCacheTopN must be greater than 0")
+	}
+	if c.CollisionRateAlertThreshold < 0 {
+		return fmt.Errorf("CollisionRateAlertThreshold must be greater than or equal to 0")
+	}
+	if c.MaxLongURLLength <= 0 || c.MaxLongURLLength > maxLongURLColumnLength {
+		return fmt.Errorf("MaxLongURLLength must be between 1 and %d", maxLongURLColumnLength)
+	}
+	return nil
+}
+
+// validateIdSpace checks that ids of the given length are between 4 and 12 characters long, and that ids of that
+// length in the given base can hold expectedUniqueURLs safetyFactor times over
+func validateIdSpace(length, base int, safetyFactor float64, expectedUniqueURLs int64) error {
+	if length < minShortURLIdLength || length > maxShortURLIdLength {
+		return fmt.Errorf("short URL id length must be between %d and %d, got %d", minShortURLIdLength, maxShortURLIdLength, length)
+	}
+
+	maxUniqueIds := math.Pow(float64(base), float64(length))
+	if maxUniqueIds <= float64(expectedUniqueURLs)*safetyFactor {
+		return fmt.Errorf("with length=%d and base=%d, max unique IDs is %s which is less than expectedUniqueURLs=%s times safetyFactor=%g",
+			length, base, formatCount(maxUniqueIds), formatCount(float64(expectedUniqueURLs)), safetyFactor)
+	}
+
 	return nil
 }
+
+// formatCount formats n with a K, M, B or T suffix, 11316496 is formatted as 11M
+func formatCount(n float64) string {
+	for _, unit := range []struct {
+		suffix string
+		value  float64
+	}{{"T", 1e12}, {"B", 1e9}, {"M", 1e6}, {"K", 1e3}} {
+		if n >= unit.value {
+			return fmt.Sprintf("%d%s", int64(n/unit.value), unit.suffix)
+		}
+	}
+
+	return fmt.Sprintf("%d", int64(n))
+}
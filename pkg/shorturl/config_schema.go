@@ -0,0 +1,15 @@
+package shorturl
+
+import (
+	"encoding/json"
+
+	"github.com/invopop/jsonschema"
+)
+
+// JSONSchema returns the JSON Schema of the configuration of the short URL manager, generated from the jsonschema
+// tags of Config. Every field is required and no others are allowed.
+func (c *Config) JSONSchema() ([]byte, error) {
+	reflector := &jsonschema.Reflector{DoNotReference: true}
+
+	return json.MarshalIndent(reflector.Reflect(c), "", "  ")
+}
@@ -0,0 +1,46 @@
+package shorturl_test
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+
+	"github.com/AvalosM/short-url-service/pkg/shorturl"
+)
+
+// validateConfig validates the JSON encoding of config against schema
+func (suite *ManagerSuite) validateConfig(schema []byte, config any) error {
+	schemaDoc, err := jsonschema.UnmarshalJSON(bytes.NewReader(schema))
+	suite.Require().NoError(err)
+
+	compiler := jsonschema.NewCompiler()
+	suite.Require().NoError(compiler.AddResource("config.json", schemaDoc))
+	compiled, err := compiler.Compile("config.json")
+	suite.Require().NoError(err)
+
+	encoded, err := json.Marshal(config)
+	suite.Require().NoError(err)
+	instance, err := jsonschema.UnmarshalJSON(bytes.NewReader(encoded))
+	suite.Require().NoError(err)
+
+	return compiled.Validate(instance)
+}
+
+func (suite *ManagerSuite) TestConfigJSONSchema() {
+	schema, err := shorturl.DefaultConfig().JSONSchema()
+	suite.Require().NoError(err)
+	suite.True(json.Valid(schema))
+
+	suite.NoError(suite.validateConfig(schema, shorturl.DefaultConfig()))
+
+	invalid := shorturl.DefaultConfig()
+	invalid.MaxShortURLIdRetries = 0
+	suite.Error(suite.validateConfig(schema, invalid))
+
+	invalid = shorturl.DefaultConfig()
+	invalid.CollisionStrategy = "cuckoo"
+	suite.Error(suite.validateConfig(schema, invalid))
+
+	suite.Error(suite.validateConfig(schema, map[string]any{"max_short_url_id_retries": 10}))
+}
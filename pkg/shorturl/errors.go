@@ -1,9 +1,41 @@
 package shorturl
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrShortURLNotFound        = errors.New("short URL not found")
+	ErrShortURLExists          = errors.New("short URL already exists")
+	ErrInvalidLongURL          = errors.New("invalid long URL")
+	ErrInvalidTags             = errors.New("invalid tags")
+	ErrInvalidDescription      = errors.New("invalid description")
+	ErrInvalidMaxClicks        = errors.New("invalid max clicks")
+	ErrClickLimitExceeded      = errors.New("short URL click limit exceeded")
+	ErrClickRateLimitExceeded  = errors.New("short URL click rate limit exceeded")
+	ErrInvalidPassword         = errors.New("invalid password")
+	ErrPasswordRequired        = errors.New("short URL is password protected")
+	ErrNotProtected            = errors.New("short URL is not password protected")
+	ErrInvalidRedirectCode     = errors.New("invalid redirect code")
+	ErrInvalidCacheTTL         = errors.New("invalid cache TTL")
+	ErrInvalidExpiresAt        = errors.New("invalid expiration time")
+	ErrInvalidAliasId          = errors.New("invalid alias id")
+	ErrAliasExists             = errors.New("alias already exists")
+	ErrAliasNotFound           = errors.New("alias not found")
+	ErrShortURLPaused          = errors.New("short URL is paused")
+	ErrShortURLArchived        = errors.New("short URL is archived")
+	ErrShortURLExpired         = errors.New("short URL is expired")
+	ErrInvalidStatusTransition = errors.New("invalid status transition")
+	ErrNotOwner                = errors.New("not the owner of the short URLs")
+	// ErrURLDenied is returned for long URLs of a host the service does not shorten
+	ErrURLDenied = errors.New("long URL host is denied")
+)
 
+// Errors detailing why a long URL or an alias id is invalid, they match ErrInvalidLongURL and ErrInvalidAliasId with
+// errors.Is
 var (
-	ErrShortURLNotFound = errors.New("short URL not found")
-	ErrShortURLExists   = errors.New("short URL already exists")
-	ErrInvalidLongURL   = errors.New("invalid long URL")
+	ErrURLTooLong       = fmt.Errorf("%w: too long", ErrInvalidLongURL)
+	ErrSlugTooLong      = fmt.Errorf("%w: too long", ErrInvalidAliasId)
+	ErrSlugInvalidChars = fmt.Errorf("%w: only letters and digits are allowed", ErrInvalidAliasId)
 )
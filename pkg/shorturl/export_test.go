@@ -0,0 +1,4 @@
+package shorturl
+
+// ValidateIdSpace exposes validateIdSpace to the tests of the package
+var ValidateIdSpace = validateIdSpace
@@ -2,32 +2,82 @@ package shorturl
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"hash/fnv"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
 	"strings"
+	"sync"
 	"time"
+	"unicode/utf8"
+
+	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/net/idna"
 
 	"github.com/AvalosM/short-url-service/pkg/logging"
+	"github.com/AvalosM/short-url-service/pkg/retry"
+	"github.com/AvalosM/short-url-service/pkg/tenant"
+	"github.com/AvalosM/short-url-service/pkg/user"
 )
 
 const (
 	charset          = "0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
 	base             = uint64(len(charset))
 	shortURLIdLength = 6
+	maxTags          = 20
+	// maxDescriptionLength is in characters, like the length of the description column
+	maxDescriptionLength = 512
+	// bcrypt ignores anything past the first 72 bytes of a password
+	maxPasswordBytes = 72
+	// staleAfter is how long after its last update a short URL redirect stops being cached
+	staleAfter = 365 * 24 * time.Hour
+	// maxCacheTTLSeconds is the longest cache TTL of a short URL, one year
+	maxCacheTTLSeconds = 365 * 24 * 60 * 60
+	// bulkCacheWorkers is the number of concurrent cache writes of GetLongURLBulk
+	bulkCacheWorkers = 8
+	// expiryCleanupActor is the actor recorded in the audit log for the deletions of expired short URLs
+	expiryCleanupActor = "expiry-cleanup"
+)
+
+var (
+	tagPattern     = regexp.MustCompile(`^[a-zA-Z0-9:_-]{1,64}$`)
+	aliasIdPattern = regexp.MustCompile(fmt.Sprintf(`^[a-zA-Z0-9]{%d}$`, shortURLIdLength))
+	// alphanumericPattern also matches the empty string, alias ids of the wrong length are caught by aliasIdPattern
+	alphanumericPattern = regexp.MustCompile(`^[a-zA-Z0-9]*$`)
 )
 
-// Storage short url persistent storage
+// Storage short url persistent storage, short URL ids are unique within a tenant
 type Storage interface {
-	CreateShortURL(ctx context.Context, id string, longURL string) error
-	DeleteShortURL(ctx context.Context, id string) error
-	GetLongURL(ctx context.Context, id string) (string, bool, error)
+	TryCreateShortURL(ctx context.Context, tenantID string, shortURL *ShortURL) (*ShortURL, bool, error)
+	DeleteShortURL(ctx context.Context, tenantID string, id string) error
+	GetLongURL(ctx context.Context, tenantID string, id string) (*ShortURL, bool, error)
+	GetShortURL(ctx context.Context, tenantID string, id string) (*ShortURL, bool, error)
+	GetShortURLs(ctx context.Context, tenantID string, ids []string) ([]*ShortURL, error)
+	ListShortURLs(ctx context.Context, tenantID string, filter *ListFilter) ([]*ShortURL, error)
+	ListShortURLsByOwner(ctx context.Context, tenantID string, userID string, limit, offset int) ([]*ShortURL, error)
+	DeleteShortURLsByOwner(ctx context.Context, tenantID string, userID string) ([]string, error)
+	StreamShortURLs(ctx context.Context, tenantID string, filter *ListFilter) (<-chan *ShortURL, <-chan error)
+	GetDashboardStats(ctx context.Context, tenantID string, from, to time.Time) (*DashboardStats, error)
+	GetMostVisitedShortURLIds(ctx context.Context, tenantID string, since time.Time, limit int) ([]string, error)
+	GetAuditLog(ctx context.Context, tenantID string, filter *AuditFilter) ([]*AuditEntry, error)
+	DeleteExpiredShortURLs(ctx context.Context) (map[string][]string, error)
+	UpdateShortURLStatus(ctx context.Context, tenantID string, id string, from Status, to Status) (bool, error)
+	CreateAlias(ctx context.Context, tenantID string, aliasID string, canonicalID string) error
+	DeleteAlias(ctx context.Context, tenantID string, canonicalID string, aliasID string) (bool, error)
+	GetLongURLByAlias(ctx context.Context, tenantID string, aliasID string) (string, string, bool, error)
 }
 
-// Cache short url cache
+// Cache short url cache, deleting a key that does not exist is not an error
 type Cache interface {
 	Get(ctx context.Context, key string) (string, bool, error)
+	GetMulti(ctx context.Context, keys []string) (map[string]string, error)
 	Set(ctx context.Context, key string, value string, duration time.Duration) error
+	SetMulti(ctx context.Context, entries map[string]string, duration time.Duration) error
 	Delete(ctx context.Context, key string) error
 }
 
@@ -45,6 +95,11 @@ type Manager struct {
 	storage Storage
 	cache   Cache
 	logger  Logger
+	// clickLimiters is nil when click rate limiting is disabled
+	clickLimiters *clickLimiters
+	// collisionFn returns the hash the id of a long URL is generated from after offset collisions
+	collisionFn func(hash uint64, offset uint) uint64
+	counters    managerCounters
 }
 
 // NewManager creates a new short URL manager
@@ -52,6 +107,9 @@ func NewManager(config *Config, storage Storage, cache Cache, logger Logger) (*M
 	if config == nil {
 		return nil, errors.New("config cannot be nil")
 	}
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
 	if storage == nil {
 		return nil, errors.New("storage cannot be nil")
 	}
@@ -59,99 +117,952 @@ func NewManager(config *Config, storage Storage, cache Cache, logger Logger) (*M
 		return nil, errors.New("cache cannot be nil")
 	}
 
-	return &Manager{
-		config:  config,
-		storage: storage,
-		cache:   cache,
-		logger:  logger,
-	}, nil
+	manager := &Manager{
+		config:      config,
+		storage:     storage,
+		cache:       cache,
+		logger:      logger,
+		collisionFn: collisionFns[config.CollisionStrategy],
+	}
+	if config.ClickRateLimitRPS > 0 {
+		manager.clickLimiters = newClickLimiters(config.ClickRateLimitRPS, config.ClickRateLimitBurst)
+	}
+
+	return manager, nil
 }
 
-// GetLongURL retrieves the long URL for the given short URL id
-func (m *Manager) GetLongURL(ctx context.Context, shortURLId string) (string, error) {
-	longURL, found, err := m.cache.Get(ctx, shortURLId)
+// GetLongURL retrieves the long URL to redirect to for the given short URL id and the redirect status, a click is
+// counted for short URLs with a click limit. ErrPasswordRequired is returned for password protected short URLs.
+// Ids that are not short URLs are looked up as aliases, which redirect like the short URL they point to.
+// ErrClickRateLimitExceeded is returned when the id takes more clicks than the configured click rate limit.
+func (m *Manager) GetLongURL(ctx context.Context, shortURLId string) (*ShortURLResult, error) {
+	return m.getLongURL(ctx, shortURLId, false, true)
+}
+
+// UnlockLongURL retrieves the long URL to redirect to for the given short URL id and the redirect status even if it
+// is password protected, callers must have verified the password first
+func (m *Manager) UnlockLongURL(ctx context.Context, shortURLId string) (*ShortURLResult, error) {
+	return m.getLongURL(ctx, shortURLId, true, false)
+}
+
+func (m *Manager) getLongURL(ctx context.Context, shortURLId string, unlocked bool, followAlias bool) (*ShortURLResult, error) {
+	tenantID := tenant.IDFromContext(ctx)
+	key := cacheKey(tenantID, shortURLId)
+
+	if m.clickLimiters != nil && !m.clickLimiters.allow(key, time.Now()) {
+		m.log(ctx).Debug("short URL click rate limit exceeded", logging.ShortURLIdKey, shortURLId)
+
+		return nil, ErrClickRateLimitExceeded
+	}
+
+	cached, found, err := m.cache.Get(ctx, key)
 	if err != nil {
-		m.logger.Error("failed to get long URL from cache", logging.ShortURLIdKey, shortURLId, logging.ErrorKey, err)
+		m.log(ctx).Error("failed to get long URL from cache", logging.ShortURLIdKey, shortURLId, logging.ErrorKey, err)
 	}
 	if found {
-		return longURL, nil
+		if result, ok := m.decodeCacheValue(cached); ok {
+			m.counters.hits.Add(1)
+			if result.ExpiresAt != nil && !result.ExpiresAt.After(time.Now()) {
+				m.log(ctx).Debug("short URL is expired", logging.ShortURLIdKey, shortURLId)
+
+				return nil, ErrShortURLExpired
+			}
+			result.ShortURLId = shortURLId
+
+			return result, nil
+		}
+
+		m.log(ctx).Warn("invalid long URL cache entry", logging.ShortURLIdKey, shortURLId)
 	}
+	m.counters.misses.Add(1)
 
-	longURL, found, err = m.storage.GetLongURL(ctx, shortURLId)
+	// Getting the long URL counts a click, it is not retried so a click committed before a failure is not counted twice
+	var shortURL *ShortURL
+	shortURL, found, err = m.storage.GetLongURL(ctx, tenantID, shortURLId)
 	if err != nil {
-		m.logger.Error("failed to get long URL from storage", logging.ShortURLIdKey, shortURLId, logging.ErrorKey, err)
+		switch {
+		case errors.Is(err, ErrClickLimitExceeded):
+			m.log(ctx).Debug("short URL click limit exceeded", logging.ShortURLIdKey, shortURLId)
+
+			return nil, ErrClickLimitExceeded
+		case errors.Is(err, ErrShortURLPaused):
+			m.log(ctx).Debug("short URL is paused", logging.ShortURLIdKey, shortURLId)
+
+			return nil, ErrShortURLPaused
+		case errors.Is(err, ErrShortURLArchived):
+			m.log(ctx).Debug("short URL is archived", logging.ShortURLIdKey, shortURLId)
+
+			return nil, ErrShortURLArchived
+		case errors.Is(err, ErrShortURLExpired):
+			m.log(ctx).Debug("short URL is expired", logging.ShortURLIdKey, shortURLId)
+
+			return nil, ErrShortURLExpired
+		}
+
+		m.log(ctx).Error("failed to get long URL from storage", logging.ShortURLIdKey, shortURLId, logging.ErrorKey, err)
 
-		return "", fmt.Errorf("failed to get long URL from storage: %w", err)
+		return nil, fmt.Errorf("failed to get long URL from storage: %w", err)
 	}
 	if !found {
-		m.logger.Debug("short URL not found", logging.ShortURLIdKey, shortURLId)
+		if followAlias {
+			return m.getLongURLByAlias(ctx, shortURLId, unlocked)
+		}
+
+		m.log(ctx).Debug("short URL not found", logging.ShortURLIdKey, shortURLId)
+
+		return nil, ErrShortURLNotFound
+	}
+
+	if shortURL.Protected() && !unlocked {
+		return nil, ErrPasswordRequired
+	}
 
-		return "", ErrShortURLNotFound
+	result := &ShortURLResult{
+		ShortURLId:         shortURL.Id,
+		LongURL:            shortURL.LongURL,
+		RedirectCode:       m.redirectCode(shortURL.RedirectCode),
+		ForwardQueryParams: shortURL.ForwardQueryParams,
+		ExpiresAt:          shortURL.ExpiresAt,
+		ShowInterstitial:   shortURL.ShowInterstitial,
 	}
 
+	// Clicks of short URLs with a limit must always reach storage to be counted, and protected short URLs must
+	// never be served from the cache without a password
+	if shortURL.MaxClicks > 0 || shortURL.Protected() {
+		return result, nil
+	}
+
+	ttl := m.cacheTTL(shortURL)
+	if ttl <= 0 {
+		return result, nil
+	}
+
+	// The cache write outlives the request, it is bounded by its own timeout instead
+	cacheCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), time.Duration(m.config.CacheWriteTimeoutInMS)*time.Millisecond)
 	go func(ctx context.Context) {
-		if err := m.cache.Set(ctx, shortURLId, longURL, time.Duration(m.config.ShortURLCacheTTLInSeconds)*time.Second); err != nil {
-			m.logger.Error("failed to set long URL in cache", logging.ShortURLIdKey, shortURLId, logging.ErrorKey, err)
+		defer cancel()
+
+		value, err := encodeCacheValue(shortURL)
+		if err != nil {
+			m.log(ctx).Error("failed to encode long URL cache entry", logging.ShortURLIdKey, shortURLId, logging.ErrorKey, err)
+
+			return
+		}
+
+		if err := m.cache.Set(ctx, key, value, ttl); err != nil {
+			m.log(ctx).Error("failed to set long URL in cache", logging.ShortURLIdKey, shortURLId, logging.ErrorKey, err)
 		}
-	}(context.WithoutCancel(ctx))
+	}(cacheCtx)
 
-	return longURL, nil
+	return result, nil
 }
 
-// CreateShortURL creates a short URL id for the given long URL
-func (m *Manager) CreateShortURL(ctx context.Context, longURL string) (string, error) {
-	err := validateLongURL(longURL)
-	if err != nil {
-		m.logger.Info("invalid long URL", logging.LongURLKey, longURL, logging.ErrorKey, err)
+// GetLongURLBulk resolves the long URLs of many short URLs, it returns the long URL of every short URL found and the
+// ids of the others in the order given. The cache is read in a single call and the misses are read from storage in a
+// single call, which are then cached. No click is counted, so the short URLs found can redirect or not, but inactive,
+// expired and password protected short URLs are not found. Aliases are not followed.
+func (m *Manager) GetLongURLBulk(ctx context.Context, ids []string) (map[string]string, []string, error) {
+	tenantID := tenant.IDFromContext(ctx)
+	unique := make(map[string]bool, len(ids))
+	ids = slices.DeleteFunc(slices.Clone(ids), func(id string) bool {
+		duplicate := unique[id]
+		unique[id] = true
+
+		return duplicate
+	})
 
-		return "", ErrInvalidLongURL
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = cacheKey(tenantID, id)
 	}
 
-	id, err := m.GenerateShortURLId(ctx, longURL)
+	// The short URLs are looked up in storage when the cache fails
+	values, err := m.cache.GetMulti(ctx, keys)
 	if err != nil {
-		if errors.Is(err, ErrShortURLExists) {
-			return id, nil
+		m.log(ctx).Error("failed to get long URLs from cache", logging.ErrorKey, err)
+	}
+
+	cached := make([]*ShortURLResult, len(ids))
+	for i, key := range keys {
+		value, found := values[key]
+		if !found {
+			continue
+		}
+
+		result, ok := m.decodeCacheValue(value)
+		if !ok {
+			m.log(ctx).Warn("invalid long URL cache entry", logging.ShortURLIdKey, ids[i])
+
+			continue
 		}
+		cached[i] = result
+	}
+
+	longURLs := make(map[string]string, len(ids))
+	var misses []string
+	for i, id := range ids {
+		switch {
+		case cached[i] == nil:
+			misses = append(misses, id)
+		case cached[i].ExpiresAt == nil || cached[i].ExpiresAt.After(time.Now()):
+			longURLs[id] = cached[i].LongURL
+		}
+	}
+
+	if len(misses) > 0 {
+		var shortURLs []*ShortURL
+		err := m.retryStorage(ctx, func() error {
+			var err error
+			shortURLs, err = m.storage.GetShortURLs(ctx, tenantID, misses)
+
+			return err
+		})
+		if err != nil {
+			m.log(ctx).Error("failed to get short URLs from storage", logging.ErrorKey, err)
+
+			return nil, nil, fmt.Errorf("failed to get short URLs from storage: %w", err)
+		}
+
+		var backfill []*ShortURL
+		for _, shortURL := range shortURLs {
+			if shortURL.Status != StatusActive || shortURL.Protected() || (shortURL.ExpiresAt != nil && !shortURL.ExpiresAt.After(time.Now())) {
+				continue
+			}
+
+			longURLs[shortURL.Id] = shortURL.LongURL
+			// Like single redirects, short URLs with a click limit are never cached
+			if shortURL.MaxClicks == 0 && m.cacheTTL(shortURL) > 0 {
+				backfill = append(backfill, shortURL)
+			}
+		}
+
+		m.forEachConcurrently(len(backfill), func(i int) {
+			shortURL := backfill[i]
+			value, err := encodeCacheValue(shortURL)
+			if err != nil {
+				m.log(ctx).Error("failed to encode long URL cache entry", logging.ShortURLIdKey, shortURL.Id, logging.ErrorKey, err)
+
+				return
+			}
+
+			if err := m.cache.Set(ctx, cacheKey(tenantID, shortURL.Id), value, m.cacheTTL(shortURL)); err != nil {
+				m.log(ctx).Error("failed to set long URL in cache", logging.ShortURLIdKey, shortURL.Id, logging.ErrorKey, err)
+			}
+		})
+	}
+
+	notFound := make([]string, 0)
+	for _, id := range ids {
+		if _, ok := longURLs[id]; !ok {
+			notFound = append(notFound, id)
+		}
+	}
+
+	return longURLs, notFound, nil
+}
+
+// forEachConcurrently calls fn with every index up to n, at most bulkCacheWorkers at a time, and waits for them
+func (m *Manager) forEachConcurrently(n int, fn func(i int)) {
+	var wg sync.WaitGroup
+	workers := make(chan struct{}, bulkCacheWorkers)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		workers <- struct{}{}
+		go func() {
+			defer func() {
+				<-workers
+				wg.Done()
+			}()
+
+			fn(i)
+		}()
+	}
+	wg.Wait()
+}
+
+// cacheTTL returns how long the redirect of a short URL is cached, its own TTL or else the configured one, shortened
+// so it does not outlive the expiration of the short URL nor a year after its last update. Short URLs that have not
+// been updated for a year are not cached.
+func (m *Manager) cacheTTL(shortURL *ShortURL) time.Duration {
+	ttl := time.Duration(m.config.ShortURLCacheTTLInSeconds) * time.Second
+	if shortURL.CacheTTLSeconds > 0 {
+		ttl = time.Duration(shortURL.CacheTTLSeconds) * time.Second
+	}
+	if shortURL.ExpiresAt != nil {
+		ttl = min(ttl, time.Until(*shortURL.ExpiresAt))
+	}
+	if shortURL.UpdatedAt.IsZero() {
+		return ttl
+	}
+
+	return min(ttl, time.Until(shortURL.UpdatedAt.Add(staleAfter)))
+}
+
+// getLongURLByAlias retrieves the long URL to redirect to for the short URL the given alias id points to. Aliases
+// are not cached themselves, the redirect of the short URL they point to is.
+func (m *Manager) getLongURLByAlias(ctx context.Context, aliasId string, unlocked bool) (*ShortURLResult, error) {
+	var canonicalId string
+	var found bool
+	err := m.retryStorage(ctx, func() error {
+		var err error
+		canonicalId, _, found, err = m.storage.GetLongURLByAlias(ctx, tenant.IDFromContext(ctx), aliasId)
+
+		return err
+	})
+	if err != nil {
+		m.log(ctx).Error("failed to get alias from storage", logging.ShortURLIdKey, aliasId, logging.ErrorKey, err)
+
+		return nil, fmt.Errorf("failed to get alias from storage: %w", err)
+	}
+	if !found {
+		m.log(ctx).Debug("short URL not found", logging.ShortURLIdKey, aliasId)
+
+		return nil, ErrShortURLNotFound
+	}
+
+	// Aliases point to short URLs only, so they are never followed twice
+	return m.getLongURL(ctx, canonicalId, unlocked, false)
+}
+
+// redirectCode returns the redirect status of a short URL with the given redirect code, falling back to the
+// configured default when it has none
+func (m *Manager) redirectCode(redirectCode int) int {
+	if redirectCode == 0 {
+		return m.config.DefaultRedirectCode
+	}
+
+	return redirectCode
+}
+
+// cacheEntry is the cached redirect of a short URL with redirect options
+type cacheEntry struct {
+	LongURL            string     `json:"long_url"`
+	RedirectCode       int        `json:"redirect_code,omitempty"`
+	ForwardQueryParams bool       `json:"forward_query_params,omitempty"`
+	ExpiresAt          *time.Time `json:"expires_at,omitempty"`
+	ShowInterstitial   bool       `json:"show_interstitial,omitempty"`
+}
+
+// encodeCacheValue encodes the cached redirect of a short URL. Short URLs without redirect options are cached as
+// the bare long URL so the default redirect code is resolved on read, the others as a JSON cacheEntry. Long URLs
+// always start with an http or https scheme so the two forms cannot be mistaken for each other.
+func encodeCacheValue(shortURL *ShortURL) (string, error) {
+	if shortURL.RedirectCode == 0 && !shortURL.ForwardQueryParams && shortURL.ExpiresAt == nil && !shortURL.ShowInterstitial {
+		return shortURL.LongURL, nil
+	}
 
+	value, err := json.Marshal(cacheEntry{
+		LongURL:            shortURL.LongURL,
+		RedirectCode:       shortURL.RedirectCode,
+		ForwardQueryParams: shortURL.ForwardQueryParams,
+		ExpiresAt:          shortURL.ExpiresAt,
+		ShowInterstitial:   shortURL.ShowInterstitial,
+	})
+	if err != nil {
 		return "", err
 	}
 
-	if err := m.storage.CreateShortURL(ctx, id, longURL); err != nil {
-		m.logger.Error("failed to create short URL in storage", logging.ShortURLIdKey, id, logging.LongURLKey, longURL, logging.ErrorKey, err)
+	return string(value), nil
+}
+
+// decodeCacheValue decodes a value encoded by encodeCacheValue, it reports false if the value is malformed
+func (m *Manager) decodeCacheValue(value string) (*ShortURLResult, bool) {
+	if !strings.HasPrefix(value, "{") {
+		return &ShortURLResult{LongURL: value, RedirectCode: m.config.DefaultRedirectCode}, true
+	}
+
+	var entry cacheEntry
+	if err := json.Unmarshal([]byte(value), &entry); err != nil {
+		return nil, false
+	}
+	if entry.RedirectCode != 0 && !ValidRedirectCode(entry.RedirectCode) {
+		return nil, false
+	}
+
+	return &ShortURLResult{
+		LongURL:            entry.LongURL,
+		RedirectCode:       m.redirectCode(entry.RedirectCode),
+		ForwardQueryParams: entry.ForwardQueryParams,
+		ExpiresAt:          entry.ExpiresAt,
+		ShowInterstitial:   entry.ShowInterstitial,
+	}, true
+}
+
+// GetShortURL retrieves the short URL with the given id from storage, no click is counted
+func (m *Manager) GetShortURL(ctx context.Context, shortURLId string) (*ShortURL, error) {
+	var shortURL *ShortURL
+	var found bool
+	err := m.retryStorage(ctx, func() error {
+		var err error
+		shortURL, found, err = m.storage.GetShortURL(ctx, tenant.IDFromContext(ctx), shortURLId)
+
+		return err
+	})
+	if err != nil {
+		m.log(ctx).Error("failed to get long URL from storage", logging.ShortURLIdKey, shortURLId, logging.ErrorKey, err)
+
+		return nil, fmt.Errorf("failed to get long URL from storage: %w", err)
+	}
+	if !found {
+		m.log(ctx).Debug("short URL not found", logging.ShortURLIdKey, shortURLId)
+
+		return nil, ErrShortURLNotFound
+	}
+
+	return shortURL, nil
+}
+
+// CheckShortURL reports whether the short URL or alias with the given id would redirect, without counting a click.
+// ErrShortURLNotFound, ErrShortURLExpired, ErrClickLimitExceeded, ErrShortURLPaused and ErrShortURLArchived are
+// returned for short URLs that would not. Password protected short URLs are reported as available.
+func (m *Manager) CheckShortURL(ctx context.Context, shortURLId string) error {
+	shortURL, err := m.GetShortURL(ctx, shortURLId)
+	if errors.Is(err, ErrShortURLNotFound) {
+		var canonicalId string
+		var found bool
+		err = m.retryStorage(ctx, func() error {
+			var err error
+			canonicalId, _, found, err = m.storage.GetLongURLByAlias(ctx, tenant.IDFromContext(ctx), shortURLId)
+
+			return err
+		})
+		if err != nil {
+			m.log(ctx).Error("failed to get alias from storage", logging.ShortURLIdKey, shortURLId, logging.ErrorKey, err)
+
+			return fmt.Errorf("failed to get alias from storage: %w", err)
+		}
+		if !found {
+			return ErrShortURLNotFound
+		}
+
+		shortURL, err = m.GetShortURL(ctx, canonicalId)
+	}
+	if err != nil {
+		return err
+	}
+
+	return shortURL.CheckAvailable(time.Now())
+}
+
+// CheckPassword verifies the password of the short URL with the given id, ErrInvalidPassword is returned if it
+// does not match
+func (m *Manager) CheckPassword(ctx context.Context, shortURLId string, password string) error {
+	shortURL, err := m.GetShortURL(ctx, shortURLId)
+	if err != nil {
+		return err
+	}
+	if !shortURL.Protected() {
+		return ErrNotProtected
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(shortURL.PasswordHash), []byte(password)); err != nil {
+		m.log(ctx).Debug("invalid short URL password", logging.ShortURLIdKey, shortURLId)
+
+		return ErrInvalidPassword
+	}
+
+	return nil
+}
+
+// CreateShortURL creates a short URL for the given long URL, an existing short URL is returned if the long URL was already shortened.
+// options can be nil.
+func (m *Manager) CreateShortURL(ctx context.Context, longURL string, options *CreateOptions) (*ShortURL, error) {
+	shortURL, _, err := m.createShortURL(ctx, longURL, options)
+
+	return shortURL, err
+}
+
+// CreateShortURLBulk creates a short URL for each entry, see CreateShortURL, and returns their results in the same
+// order. An entry failing does not stop the others from being created.
+func (m *Manager) CreateShortURLBulk(ctx context.Context, entries []*BulkEntry) []*BulkResult {
+	results := make([]*BulkResult, 0, len(entries))
+	for _, entry := range entries {
+		shortURL, err := m.CreateShortURL(ctx, entry.LongURL, entry.Options)
+		if err == nil && entry.AliasId != "" {
+			err = m.CreateAlias(ctx, shortURL.Id, entry.AliasId)
+		}
+
+		results = append(results, &BulkResult{ShortURL: shortURL, Err: err})
+	}
+
+	return results
+}
 
-		return "", fmt.Errorf("failed to create short URL in storage: %w", err)
+// GetOrCreateShortURL returns the id of the short URL of the given long URL, creating it if the long URL was not
+// shortened yet. It reports whether the short URL was created.
+func (m *Manager) GetOrCreateShortURL(ctx context.Context, longURL string) (string, bool, error) {
+	shortURL, created, err := m.createShortURL(ctx, longURL, nil)
+	if err != nil {
+		return "", false, err
 	}
 
-	return id, nil
+	return shortURL.Id, created, nil
 }
 
-func validateLongURL(longURL string) error {
+// createShortURL creates a short URL for the given long URL or returns the existing one, it reports whether the short
+// URL was created
+func (m *Manager) createShortURL(ctx context.Context, longURL string, options *CreateOptions) (*ShortURL, bool, error) {
+	longURL, err := m.canonicalLongURL(longURL)
+	if err != nil {
+		m.log(ctx).Info("invalid long URL", logging.LongURLKey, longURL, logging.ErrorKey, err)
+
+		switch {
+		case errors.Is(err, ErrURLTooLong):
+			return nil, false, ErrURLTooLong
+		case errors.Is(err, ErrURLDenied):
+			return nil, false, ErrURLDenied
+		default:
+			return nil, false, ErrInvalidLongURL
+		}
+	}
+
+	if options == nil {
+		options = &CreateOptions{}
+	}
+
+	if err := validateTags(options.Tags); err != nil {
+		m.log(ctx).Info("invalid tags", logging.LongURLKey, longURL, logging.TagsKey, options.Tags, logging.ErrorKey, err)
+
+		return nil, false, fmt.Errorf("%w: %w", ErrInvalidTags, err)
+	}
+
+	if utf8.RuneCountInString(options.Description) > maxDescriptionLength {
+		m.log(ctx).Info("invalid description", logging.LongURLKey, longURL)
+
+		return nil, false, fmt.Errorf("%w: description must be at most %d characters long", ErrInvalidDescription, maxDescriptionLength)
+	}
+
+	if options.MaxClicks < 0 {
+		m.log(ctx).Info("invalid max clicks", logging.LongURLKey, longURL)
+
+		return nil, false, fmt.Errorf("%w: max clicks cannot be negative", ErrInvalidMaxClicks)
+	}
+
+	if options.RedirectCode != 0 && !ValidRedirectCode(options.RedirectCode) {
+		m.log(ctx).Info("invalid redirect code", logging.LongURLKey, longURL, logging.RedirectCodeKey, options.RedirectCode)
+
+		return nil, false, fmt.Errorf("%w: redirect code must be 301, 302 or 307", ErrInvalidRedirectCode)
+	}
+
+	if options.ExpiresAt != nil && !options.ExpiresAt.After(time.Now()) {
+		m.log(ctx).Info("invalid expiration time", logging.LongURLKey, longURL)
+
+		return nil, false, fmt.Errorf("%w: expiration time must be in the future", ErrInvalidExpiresAt)
+	}
+
+	if options.CacheTTLSeconds < 0 || options.CacheTTLSeconds > maxCacheTTLSeconds {
+		m.log(ctx).Info("invalid cache TTL", logging.LongURLKey, longURL)
+
+		return nil, false, fmt.Errorf("%w: cache TTL must be 1 to %d seconds", ErrInvalidCacheTTL, maxCacheTTLSeconds)
+	}
+
+	var passwordHash string
+	if options.Password != "" {
+		if len(options.Password) > maxPasswordBytes {
+			m.log(ctx).Info("invalid password", logging.LongURLKey, longURL)
+
+			return nil, false, fmt.Errorf("%w: password must be at most %d bytes long", ErrInvalidPassword, maxPasswordBytes)
+		}
+
+		hash, err := bcrypt.GenerateFromPassword([]byte(options.Password), bcrypt.DefaultCost)
+		if err != nil {
+			m.log(ctx).Error("failed to hash password", logging.LongURLKey, longURL, logging.ErrorKey, err)
+
+			return nil, false, fmt.Errorf("failed to hash password: %w", err)
+		}
+		passwordHash = string(hash)
+	}
+
+	tenantID := tenant.IDFromContext(ctx)
+	for offset := 0; offset < m.config.MaxShortURLIdRetries; offset++ {
+		id, err := m.GenerateIdWithOffset(longURL, uint(offset))
+		if err != nil {
+			m.log(ctx).Error("failed to generate short URL ID with offset", logging.LongURLKey, longURL, logging.ErrorKey, err)
+
+			return nil, false, fmt.Errorf("failed to generate short URL ID with offset: %w", err)
+		}
+
+		// Creating first and checking the existing short URL only on conflict leaves no window for a concurrent
+		// creation of the same id between the check and the insert. It is not retried, a short URL committed before a
+		// failure would be found by the retry as an existing one
+		shortURL, exists, err := m.storage.TryCreateShortURL(ctx, tenantID, &ShortURL{
+			Id:                 id,
+			LongURL:            longURL,
+			Tags:               options.Tags,
+			Description:        options.Description,
+			MaxClicks:          options.MaxClicks,
+			PasswordHash:       passwordHash,
+			RedirectCode:       options.RedirectCode,
+			ForwardQueryParams: options.ForwardQueryParams,
+			ExpiresAt:          options.ExpiresAt,
+			CacheTTLSeconds:    options.CacheTTLSeconds,
+			ShowInterstitial:   options.ShowInterstitial,
+			CreatedBy:          user.IDFromContext(ctx),
+		})
+		if err != nil {
+			m.log(ctx).Error("failed to create short URL in storage", logging.ShortURLIdKey, id, logging.LongURLKey, longURL, logging.ErrorKey, err)
+
+			return nil, false, fmt.Errorf("failed to create short URL in storage: %w", err)
+		}
+		if !exists {
+			m.counters.creates.Add(1)
+
+			return shortURL, true, nil
+		}
+
+		var stored *ShortURL
+		var found bool
+		err = m.retryStorage(ctx, func() error {
+			stored, found, err = m.storage.GetShortURL(ctx, tenantID, id)
+
+			return err
+		})
+		if err != nil {
+			m.log(ctx).Error("error checking existing short URL", logging.ShortURLIdKey, id, logging.ErrorKey, err)
+
+			return nil, false, fmt.Errorf("error checking existing short URL: %w", err)
+		}
+		// A short URL of the long URL with other options is not reused, it would not be protected, limited or
+		// expiring as asked for
+		if found && stored.LongURL == longURL && stored.matchesOptions(options) {
+			return stored, false, nil
+		}
+
+		m.recordCollision(ctx)
+		m.log(ctx).Debug("collision detected for short URL", logging.ShortURLIdKey, id, logging.LongURLKey, longURL)
+	}
+
+	m.log(ctx).Error("failed to generate unique short URL", logging.LongURLKey, longURL)
+
+	return nil, false, fmt.Errorf("failed to generate unique short URL")
+}
+
+// matchesOptions reports whether the short URL was created with the given options, the password is checked against
+// its hash
+func (s *ShortURL) matchesOptions(options *CreateOptions) bool {
+	if s.Description != options.Description || s.MaxClicks != options.MaxClicks ||
+		s.RedirectCode != options.RedirectCode || s.ForwardQueryParams != options.ForwardQueryParams ||
+		s.CacheTTLSeconds != options.CacheTTLSeconds || s.ShowInterstitial != options.ShowInterstitial {
+		return false
+	}
+
+	if !slices.Equal(sortedTags(s.Tags), sortedTags(options.Tags)) {
+		return false
+	}
+
+	// Stored times only keep microseconds
+	if (s.ExpiresAt == nil) != (options.ExpiresAt == nil) ||
+		s.ExpiresAt != nil && !s.ExpiresAt.Truncate(time.Microsecond).Equal(options.ExpiresAt.Truncate(time.Microsecond)) {
+		return false
+	}
+
+	if options.Password == "" || !s.Protected() {
+		return options.Password == "" && !s.Protected()
+	}
+
+	return bcrypt.CompareHashAndPassword([]byte(s.PasswordHash), []byte(options.Password)) == nil
+}
+
+// sortedTags returns a sorted copy of the tags, nil if there are none
+func sortedTags(tags []string) []string {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	return slices.Sorted(slices.Values(tags))
+}
+
+// canonicalLongURL validates the long URL and returns its canonical form, internationalized domain names are
+// IDNA encoded and non-ASCII characters elsewhere are percent-encoded. The length limit applies to the canonical form,
+// which is the one stored.
+func (m *Manager) canonicalLongURL(longURL string) (string, error) {
 	if longURL == "" {
-		return errors.New("long URL cannot be empty")
+		return "", errors.New("long URL cannot be empty")
+	}
+
+	parsed, err := url.Parse(longURL)
+	if err != nil {
+		return "", fmt.Errorf("malformed long URL: %w", err)
+	}
+	switch {
+	case parsed.Scheme == "https":
+	case parsed.Scheme == "http" && m.config.AllowHTTP:
+	case m.config.AllowHTTP:
+		return "", errors.New("long URLs must start with http:// or https://")
+	default:
+		return "", errors.New("long URLs must start with https://")
+	}
+	if parsed.Host == "" {
+		return "", errors.New("long URL must have a host")
+	}
+
+	if hostname := parsed.Hostname(); !isASCII(hostname) {
+		asciiHostname, err := idna.Lookup.ToASCII(hostname)
+		if err != nil {
+			return "", fmt.Errorf("invalid internationalized domain name: %w", err)
+		}
+		if port := parsed.Port(); port != "" {
+			asciiHostname = net.JoinHostPort(asciiHostname, port)
+		}
+		parsed.Host = asciiHostname
+	}
+	if m.deniedHost(parsed.Hostname()) {
+		return "", fmt.Errorf("%w: %s", ErrURLDenied, parsed.Hostname())
+	}
+	// url.URL.String escapes the path and fragment but keeps the raw query as is
+	parsed.RawQuery = escapeNonASCII(parsed.RawQuery)
+
+	canonical := parsed.String()
+	if len(canonical) > m.config.MaxLongURLLength {
+		return "", fmt.Errorf("%w: long URL must be at most %d bytes long", ErrURLTooLong, m.config.MaxLongURLLength)
+	}
+
+	return canonical, nil
+}
+
+// deniedHost reports whether the IDNA encoded hostname is one of the denied hosts or a subdomain of one, denied
+// internationalized domain names are encoded before comparing them
+func (m *Manager) deniedHost(hostname string) bool {
+	hostname = strings.ToLower(hostname)
+	for _, denied := range m.config.DeniedHosts {
+		if asciiDenied, err := idna.Lookup.ToASCII(denied); err == nil {
+			denied = asciiDenied
+		}
+		denied = strings.ToLower(denied)
+		if hostname == denied || strings.HasSuffix(hostname, "."+denied) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+
+	return true
+}
+
+func escapeNonASCII(s string) string {
+	if isASCII(s) {
+		return s
+	}
+
+	var escaped strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] < utf8.RuneSelf {
+			escaped.WriteByte(s[i])
+			continue
+		}
+		fmt.Fprintf(&escaped, "%%%02X", s[i])
+	}
+
+	return escaped.String()
+}
+
+// ValidRedirectCode reports whether code is an HTTP status short URLs can redirect with
+func ValidRedirectCode(code int) bool {
+	switch code {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusTemporaryRedirect:
+		return true
+	default:
+		return false
+	}
+}
+
+func validateTags(tags []string) error {
+	if len(tags) > maxTags {
+		return fmt.Errorf("at most %d tags are allowed", maxTags)
 	}
-	if !strings.HasPrefix(longURL, "https://") {
-		return errors.New("long URLs must start with https://")
+	for _, tag := range tags {
+		if !tagPattern.MatchString(tag) {
+			return fmt.Errorf("tag %q must be 1 to 64 alphanumeric, ':', '-' or '_' characters", tag)
+		}
 	}
 
 	return nil
 }
 
+// ListShortURLs lists the short URLs matching the given filter
+func (m *Manager) ListShortURLs(ctx context.Context, filter *ListFilter) ([]*ShortURL, error) {
+	var shortURLs []*ShortURL
+	err := m.retryStorage(ctx, func() error {
+		var err error
+		shortURLs, err = m.storage.ListShortURLs(ctx, tenant.IDFromContext(ctx), filter)
+
+		return err
+	})
+	if err != nil {
+		m.log(ctx).Error("failed to list short URLs from storage", logging.ErrorKey, err)
+
+		return nil, fmt.Errorf("failed to list short URLs from storage: %w", err)
+	}
+
+	return shortURLs, nil
+}
+
+// ListShortURLsByOwner lists the short URLs the user with the given id created in the tenant of ctx, newest first.
+// ErrNotOwner is returned unless ctx is made on behalf of that user or of an admin.
+func (m *Manager) ListShortURLsByOwner(ctx context.Context, userID string, limit, offset int) ([]*ShortURL, error) {
+	if !user.CanAccess(ctx, userID) {
+		return nil, ErrNotOwner
+	}
+
+	var shortURLs []*ShortURL
+	err := m.retryStorage(ctx, func() error {
+		var err error
+		shortURLs, err = m.storage.ListShortURLsByOwner(ctx, tenant.IDFromContext(ctx), userID, limit, offset)
+
+		return err
+	})
+	if err != nil {
+		m.log(ctx).Error("failed to list short URLs by owner from storage", logging.ErrorKey, err)
+
+		return nil, fmt.Errorf("failed to list short URLs by owner from storage: %w", err)
+	}
+
+	return shortURLs, nil
+}
+
+// DeleteShortURLsByOwner deletes every short URL the user with the given id created in the tenant of ctx, for
+// deletions of user accounts. ErrNotOwner is returned unless ctx is made on behalf of that user or of an admin.
+func (m *Manager) DeleteShortURLsByOwner(ctx context.Context, userID string) error {
+	if !user.CanAccess(ctx, userID) {
+		return ErrNotOwner
+	}
+
+	tenantID := tenant.IDFromContext(ctx)
+
+	ids, err := m.storage.DeleteShortURLsByOwner(ctx, tenantID, userID)
+	if err != nil {
+		m.log(ctx).Error("failed to delete short URLs by owner from storage", logging.UserIdKey, userID, logging.ErrorKey, err)
+
+		return fmt.Errorf("failed to delete short URLs by owner from storage: %w", err)
+	}
+
+	m.log(ctx).Info("deleted short URLs by owner", logging.UserIdKey, userID, logging.DeletedKey, len(ids))
+
+	// Every cached redirect is removed even if some fail, the short URLs are already deleted
+	var cacheErr error
+	for _, id := range ids {
+		cacheErr = errors.Join(cacheErr, m.deleteCachedRedirect(ctx, tenantID, id))
+	}
+
+	return cacheErr
+}
+
+// StreamShortURLs sends the short URLs matching the given filter, newest first, on the returned short URL channel
+// without loading them all into memory. Both channels are closed once every short URL is sent, the error channel first
+// receiving the error that stopped the stream if any. Callers stopping early must cancel ctx.
+func (m *Manager) StreamShortURLs(ctx context.Context, filter *ListFilter) (<-chan *ShortURL, <-chan error) {
+	shortURLs, storageErrs := m.storage.StreamShortURLs(ctx, tenant.IDFromContext(ctx), filter)
+
+	errs := make(chan error, 1)
+	go func() {
+		defer close(errs)
+
+		// A stream stopped by its caller cancelling ctx is not a storage failure
+		if err := <-storageErrs; err != nil && ctx.Err() == nil {
+			m.log(ctx).Error("failed to stream short URLs from storage", logging.ErrorKey, err)
+			errs <- fmt.Errorf("streaming short URLs from storage: %w", err)
+		}
+	}()
+
+	return shortURLs, errs
+}
+
+// GetDashboardStats retrieves the dashboard stats of the tenant of ctx within a time range, they are cached for
+// DashboardCacheTTLInSeconds so they may lag behind the latest short URLs and redirects
+func (m *Manager) GetDashboardStats(ctx context.Context, from, to time.Time) (*DashboardStats, error) {
+	tenantID := tenant.IDFromContext(ctx)
+	key := dashboardCacheKey(tenantID, from, to)
+
+	cached, found, err := m.cache.Get(ctx, key)
+	if err != nil {
+		m.log(ctx).Error("failed to get dashboard stats from cache", logging.ErrorKey, err)
+	}
+	if found {
+		var stats DashboardStats
+		if err := json.Unmarshal([]byte(cached), &stats); err == nil {
+			return &stats, nil
+		}
+
+		m.log(ctx).Warn("invalid dashboard stats cache entry")
+	}
+
+	var stats *DashboardStats
+	err = m.retryStorage(ctx, func() error {
+		var err error
+		stats, err = m.storage.GetDashboardStats(ctx, tenantID, from, to)
+
+		return err
+	})
+	if err != nil {
+		m.log(ctx).Error("failed to get dashboard stats from storage", logging.ErrorKey, err)
+
+		return nil, fmt.Errorf("failed to get dashboard stats from storage: %w", err)
+	}
+
+	value, err := json.Marshal(stats)
+	if err != nil {
+		m.log(ctx).Error("failed to encode dashboard stats cache entry", logging.ErrorKey, err)
+
+		return stats, nil
+	}
+	if err := m.cache.Set(ctx, key, string(value), time.Duration(m.config.DashboardCacheTTLInSeconds)*time.Second); err != nil {
+		m.log(ctx).Error("failed to set dashboard stats in cache", logging.ErrorKey, err)
+	}
+
+	return stats, nil
+}
+
+// GetAuditLog retrieves the audit log entries of a short URL, oldest first
+func (m *Manager) GetAuditLog(ctx context.Context, filter *AuditFilter) ([]*AuditEntry, error) {
+	var entries []*AuditEntry
+	err := m.retryStorage(ctx, func() error {
+		var err error
+		entries, err = m.storage.GetAuditLog(ctx, tenant.IDFromContext(ctx), filter)
+
+		return err
+	})
+	if err != nil {
+		m.log(ctx).Error("failed to get audit log from storage", logging.ShortURLIdKey, filter.ShortURLId, logging.ErrorKey, err)
+
+		return nil, fmt.Errorf("failed to get audit log from storage: %w", err)
+	}
+
+	return entries, nil
+}
+
 // DeleteShortURL deletes the short URL with the given id
 func (m *Manager) DeleteShortURL(ctx context.Context, shortURLId string) error {
 	if shortURLId == "" {
 		return errors.New("short URL ID cannot be empty")
 	}
 
+	tenantID := tenant.IDFromContext(ctx)
+
 	// Remove from storage
-	if err := m.storage.DeleteShortURL(ctx, shortURLId); err != nil {
-		m.logger.Error("failed to delete short URL from storage", logging.ShortURLIdKey, shortURLId, logging.ErrorKey, err)
+	err := m.storage.DeleteShortURL(ctx, tenantID, shortURLId)
+	if err != nil {
+		m.log(ctx).Error("failed to delete short URL from storage", logging.ShortURLIdKey, shortURLId, logging.ErrorKey, err)
 
 		return fmt.Errorf("failed to delete short URL from storage: %w", err)
 	}
 
 	// Remove from cache
-	if err := m.cache.Delete(ctx, shortURLId); err != nil {
-		m.logger.Error("failed to delete short URL from cache", logging.ShortURLIdKey, shortURLId, logging.ErrorKey, err)
+	return m.deleteCachedRedirect(ctx, tenantID, shortURLId)
+}
+
+// deleteCachedRedirect removes the cached redirect of a short URL of a tenant. Short URLs without a cached redirect,
+// never cached or already expired, are not an error, Cache.Delete only fails when the cache cannot be reached.
+func (m *Manager) deleteCachedRedirect(ctx context.Context, tenantID string, shortURLId string) error {
+	if err := m.cache.Delete(ctx, cacheKey(tenantID, shortURLId)); err != nil {
+		m.log(ctx).Error("failed to delete short URL from cache", logging.ShortURLIdKey, shortURLId, logging.ErrorKey, err)
 
 		return fmt.Errorf("failed to delete short URL from cache: %w", err)
 	}
@@ -159,39 +1070,173 @@ func (m *Manager) DeleteShortURL(ctx context.Context, shortURLId string) error {
 	return nil
 }
 
-// GenerateShortURLId generates a unique short URL ID for the given long URL
-func (m *Manager) GenerateShortURLId(ctx context.Context, longURL string) (string, error) {
-	if longURL == "" {
-		return "", errors.New("long URL cannot be empty")
+// UpdateShortURLStatus moves the short URL with the given id to the given status, ErrInvalidStatusTransition is
+// returned if it cannot be moved there from its current status. Active and paused short URLs can be moved to each
+// other and archived, archived short URLs stay archived.
+func (m *Manager) UpdateShortURLStatus(ctx context.Context, shortURLId string, status Status) error {
+	shortURL, err := m.GetShortURL(ctx, shortURLId)
+	if err != nil {
+		return err
 	}
+	if !shortURL.Status.CanTransitionTo(status) {
+		m.log(ctx).Info("invalid short URL status transition", logging.ShortURLIdKey, shortURLId, logging.StatusKey, status)
 
-	for offset := 0; offset < m.config.MaxShortURLIdRetries; offset++ {
-		id, err := m.GenerateIdWithOffset(longURL, uint(offset))
-		if err != nil {
-			m.logger.Error("failed to generate short URL ID with offset", logging.LongURLKey, longURL, logging.ErrorKey, err)
+		return fmt.Errorf("%w: from %s to %s", ErrInvalidStatusTransition, shortURL.Status, status)
+	}
 
-			return "", fmt.Errorf("failed to generate short URL ID with offset: %w", err)
-		}
+	tenantID := tenant.IDFromContext(ctx)
+	updated, err := m.storage.UpdateShortURLStatus(ctx, tenantID, shortURLId, shortURL.Status, status)
+	if err != nil {
+		m.log(ctx).Error("failed to update short URL status in storage", logging.ShortURLIdKey, shortURLId, logging.StatusKey, status,
+			logging.ErrorKey, err)
 
-		storedLongURL, found, err := m.storage.GetLongURL(ctx, id)
-		if err != nil {
-			m.logger.Error("error checking existing short URL", logging.ShortURLIdKey, id, logging.ErrorKey, err)
+		return fmt.Errorf("failed to update short URL status in storage: %w", err)
+	}
+	if !updated {
+		// The short URL was deleted or its status changed since it was read
+		return fmt.Errorf("%w: short URL changed concurrently", ErrInvalidStatusTransition)
+	}
+
+	// Cached redirects must not outlive a pause or archive
+	return m.deleteCachedRedirect(ctx, tenantID, shortURLId)
+}
+
+// CreateAlias creates an alias id that redirects like the short URL with the given id, so links to a retired short
+// URL keep working. ErrShortURLExists is returned if the alias id is a short URL itself and ErrAliasExists if it is
+// already an alias.
+func (m *Manager) CreateAlias(ctx context.Context, shortURLId string, aliasId string) error {
+	switch {
+	case len(aliasId) > shortURLIdLength:
+		return ErrSlugTooLong
+	case !alphanumericPattern.MatchString(aliasId):
+		return ErrSlugInvalidChars
+	case !aliasIdPattern.MatchString(aliasId):
+		return fmt.Errorf("%w: alias id must be %d alphanumeric characters", ErrInvalidAliasId, shortURLIdLength)
+	}
+
+	if _, err := m.GetShortURL(ctx, shortURLId); err != nil {
+		return err
+	}
+
+	_, err := m.GetShortURL(ctx, aliasId)
+	if err == nil {
+		m.log(ctx).Info("alias id is a short URL", logging.ShortURLIdKey, shortURLId, logging.AliasIdKey, aliasId)
 
-			return "", fmt.Errorf("error checking existing short URL: %w", err)
+		return ErrShortURLExists
+	}
+	if !errors.Is(err, ErrShortURLNotFound) {
+		return err
+	}
+
+	err = m.storage.CreateAlias(ctx, tenant.IDFromContext(ctx), aliasId, shortURLId)
+	if err != nil {
+		if errors.Is(err, ErrAliasExists) {
+			m.log(ctx).Info("alias already exists", logging.ShortURLIdKey, shortURLId, logging.AliasIdKey, aliasId)
+
+			return ErrAliasExists
 		}
-		if !found {
-			return id, nil
+
+		m.log(ctx).Error("failed to create alias in storage", logging.ShortURLIdKey, shortURLId, logging.AliasIdKey, aliasId, logging.ErrorKey, err)
+
+		return fmt.Errorf("failed to create alias in storage: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteAlias deletes an alias id of the short URL with the given id, ErrAliasNotFound is returned if the short URL
+// has no such alias
+func (m *Manager) DeleteAlias(ctx context.Context, shortURLId string, aliasId string) error {
+	deleted, err := m.storage.DeleteAlias(ctx, tenant.IDFromContext(ctx), shortURLId, aliasId)
+	if err != nil {
+		m.log(ctx).Error("failed to delete alias from storage", logging.ShortURLIdKey, shortURLId, logging.AliasIdKey, aliasId, logging.ErrorKey, err)
+
+		return fmt.Errorf("failed to delete alias from storage: %w", err)
+	}
+	if !deleted {
+		return ErrAliasNotFound
+	}
+
+	return nil
+}
+
+// StartExpiryCleanup soft deletes the expired short URLs of all tenants from storage and the cache every interval
+// until ctx is done or the returned stop function is called. The stop function waits for a running cleanup to finish.
+func (m *Manager) StartExpiryCleanup(ctx context.Context, interval time.Duration) func() {
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				m.deleteExpiredShortURLs(ctx)
+			case <-ctx.Done():
+				return
+			}
 		}
-		if storedLongURL == longURL {
-			return id, ErrShortURLExists
+	}()
+
+	m.log(ctx).Info("expired short URL cleanup started")
+
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+func (m *Manager) deleteExpiredShortURLs(ctx context.Context) {
+	// Soft deleting returns the deleted ids only once, it is not retried so a failure after the commit does not leave
+	// their cache entries behind. The next cleanup retries a failed one.
+	deleted, err := m.storage.DeleteExpiredShortURLs(WithActor(ctx, expiryCleanupActor))
+	if err != nil {
+		m.log(ctx).Error("failed to delete expired short URLs from storage", logging.ErrorKey, err)
+
+		return
+	}
+
+	// Every cached redirect is removed even if some fail, the short URLs are already deleted
+	count := 0
+	for tenantID, ids := range deleted {
+		for _, id := range ids {
+			_ = m.deleteCachedRedirect(ctx, tenantID, id)
 		}
+		count += len(ids)
+	}
+
+	m.log(ctx).Info("deleted expired short URLs", logging.DeletedKey, count)
+}
 
-		m.logger.Debug("collision detected for short URL", logging.ShortURLIdKey, id, logging.LongURLKey, longURL)
+// log returns the logger of the manager adding the request-scoped attributes of ctx to its records
+func (m *Manager) log(ctx context.Context) Logger {
+	return logging.FromContext(ctx, m.logger)
+}
+
+// cacheKey returns the cache key of a short URL, keys of the default tenant are the bare short URL id so entries
+// cached before tenants were introduced stay valid
+func cacheKey(tenantID string, shortURLId string) string {
+	if tenantID == tenant.Default {
+		return shortURLId
 	}
 
-	m.logger.Error("failed to generate unique short URL", logging.LongURLKey, longURL)
+	return tenantID + "/" + shortURLId
+}
+
+// dashboardCacheKey returns the cache key of the dashboard stats of a tenant within a time range, the colon keeps it
+// apart from the keys of short URLs
+func dashboardCacheKey(tenantID string, from, to time.Time) string {
+	return fmt.Sprintf("dashboard:%s:%d:%d", tenantID, from.Unix(), to.Unix())
+}
 
-	return "", fmt.Errorf("failed to generate unique short URL")
+// retryStorage retries a storage call on transient errors using the configured backoff. Only reads and idempotent
+// writes are retried, a write committed before a failure reached the manager would be applied twice.
+func (m *Manager) retryStorage(ctx context.Context, fn func() error) error {
+	return retry.Retry(ctx, m.config.MaxStorageRetries+1, time.Duration(m.config.StorageRetryBackoffInMS)*time.Millisecond, fn)
 }
 
 // GenerateIdWithOffset creates an id with the given long URL and offset
@@ -202,13 +1247,7 @@ func (m *Manager) GenerateIdWithOffset(longURL string, offset uint) (string, err
 		return "", fmt.Errorf("failed to hash long URL: %w", err)
 	}
 
-	hashValue := h.Sum64()
-
-	// https://en.wikipedia.org/wiki/Quadratic_probing
-	// m = 2^64
-	// h(k,i) = h(k) + i/2 + i^2/2
-	quadraticOffset := (offset + (offset * offset)) / 2
-	hashValue += uint64(quadraticOffset)
+	hashValue := m.collisionFn(h.Sum64(), offset)
 
 	var id strings.Builder
 	id.Grow(shortURLIdLength)
@@ -3,14 +3,20 @@ package shorturl_test
 import (
 	"context"
 	"errors"
+	"fmt"
+	"net/http"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/suite"
 	"go.uber.org/mock/gomock"
+	"golang.org/x/crypto/bcrypt"
 
 	"github.com/AvalosM/short-url-service/pkg/shorturl"
 	"github.com/AvalosM/short-url-service/pkg/shorturl/mocks"
+	"github.com/AvalosM/short-url-service/pkg/tenant"
+	"github.com/AvalosM/short-url-service/pkg/user"
 )
 
 //go:generate mockgen -typed -package=mocks  -source=./manager.go -destination=./mocks/mocks.go
@@ -37,8 +43,18 @@ func (suite *ManagerSuite) SetupTest() {
 	suite.mockLogger.EXPECT().Warn(gomock.Any(), gomock.Any()).AnyTimes()
 
 	suite.config = &shorturl.Config{
-		MaxShortURLIdRetries:      3,
-		ShortURLCacheTTLInSeconds: 60,
+		MaxShortURLIdRetries:           3,
+		ShortURLCacheTTLInSeconds:      60,
+		StorageRetryBackoffInMS:        1,
+		CacheWriteTimeoutInMS:          100,
+		DefaultRedirectCode:            http.StatusFound,
+		ExpiryCleanupIntervalInSeconds: 60,
+		DashboardCacheTTLInSeconds:     60,
+		CollisionStrategy:              shorturl.CollisionStrategyQuadratic,
+		SafetyFactor:                   1,
+		WarmCacheBatchSize:             2,
+		WarmCacheTopN:                  10,
+		MaxLongURLLength:               2048,
 	}
 
 	manager, err := shorturl.NewManager(suite.config, suite.mockStorage, suite.mockCache, suite.mockLogger)
@@ -64,138 +80,1603 @@ func (suite *ManagerSuite) TestGetLongURLSuccess() {
 	done := make(chan struct{})
 
 	suite.mockCache.EXPECT().Get(ctx, id).Return("", false, nil)
-	suite.mockStorage.EXPECT().GetLongURL(ctx, id).Return(expectedLongURL, true, nil)
-	suite.mockCache.EXPECT().Set(context.WithoutCancel(ctx), id, expectedLongURL, time.Second*time.Duration(suite.config.ShortURLCacheTTLInSeconds)).
+	suite.mockStorage.EXPECT().GetLongURL(ctx, tenant.Default, id).Return(&shorturl.ShortURL{Id: id, LongURL: expectedLongURL}, true, nil)
+	suite.mockCache.EXPECT().Set(gomock.Any(), id, expectedLongURL, time.Second*time.Duration(suite.config.ShortURLCacheTTLInSeconds)).
 		DoAndReturn(func(ctx context.Context, s string, s2 string, duration time.Duration) error {
+			_, hasDeadline := ctx.Deadline()
+			suite.True(hasDeadline)
+
+			close(done)
+			return nil
+		})
+
+	result, err := suite.manager.GetLongURL(ctx, id)
+	suite.Require().NoError(err)
+	suite.Equal(&shorturl.ShortURLResult{ShortURLId: id, LongURL: expectedLongURL, RedirectCode: http.StatusFound}, result)
+
+	select {
+	case <-done:
+	case <-time.After(100 * time.Millisecond):
+		suite.Fail("Waiting for cache set timed out")
+	}
+}
+
+func (suite *ManagerSuite) TestGetLongURLSuccessCacheHit() {
+	ctx := context.Background()
+	id := "AABBCC"
+
+	expectedLongURL := "https://example.com"
+
+	suite.mockCache.EXPECT().Get(ctx, id).Return(expectedLongURL, true, nil)
+
+	result, err := suite.manager.GetLongURL(ctx, id)
+	suite.Require().NoError(err)
+	suite.Equal(&shorturl.ShortURLResult{ShortURLId: id, LongURL: expectedLongURL, RedirectCode: http.StatusFound}, result)
+}
+
+func (suite *ManagerSuite) TestGetLongURLSuccessCacheHitTenant() {
+	ctx := tenant.WithID(context.Background(), "acme")
+	id := "AABBCC"
+
+	expectedLongURL := "https://example.com"
+
+	suite.mockCache.EXPECT().Get(ctx, "acme/"+id).Return(expectedLongURL, true, nil)
+
+	result, err := suite.manager.GetLongURL(ctx, id)
+	suite.Require().NoError(err)
+	suite.Equal(&shorturl.ShortURLResult{ShortURLId: id, LongURL: expectedLongURL, RedirectCode: http.StatusFound}, result)
+}
+
+func (suite *ManagerSuite) TestGetLongURLSuccessDefaultRedirectCode() {
+	ctx := context.Background()
+	id := "AABBCC"
+
+	suite.config.DefaultRedirectCode = http.StatusMovedPermanently
+
+	suite.mockCache.EXPECT().Get(ctx, id).Return("https://example.com", true, nil)
+
+	result, err := suite.manager.GetLongURL(ctx, id)
+	suite.Require().NoError(err)
+	suite.Equal(&shorturl.ShortURLResult{ShortURLId: id, LongURL: "https://example.com", RedirectCode: http.StatusMovedPermanently}, result)
+}
+
+func (suite *ManagerSuite) TestGetLongURLSuccessRedirectCode() {
+	ctx := context.Background()
+	id := "AABBCC"
+
+	done := make(chan struct{})
+
+	suite.mockCache.EXPECT().Get(ctx, id).Return("", false, nil)
+	suite.mockStorage.EXPECT().GetLongURL(ctx, tenant.Default, id).
+		Return(&shorturl.ShortURL{Id: id, LongURL: "https://example.com", RedirectCode: http.StatusTemporaryRedirect}, true, nil)
+	suite.mockCache.EXPECT().Set(gomock.Any(), id, `{"long_url":"https://example.com","redirect_code":307}`, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, _ string, _ time.Duration) error {
+			close(done)
+			return nil
+		})
+
+	result, err := suite.manager.GetLongURL(ctx, id)
+	suite.Require().NoError(err)
+	suite.Equal(&shorturl.ShortURLResult{ShortURLId: id, LongURL: "https://example.com", RedirectCode: http.StatusTemporaryRedirect}, result)
+
+	select {
+	case <-done:
+	case <-time.After(100 * time.Millisecond):
+		suite.Fail("Waiting for cache set timed out")
+	}
+}
+
+func (suite *ManagerSuite) TestGetLongURLSuccessCacheHitRedirectCode() {
+	ctx := context.Background()
+	id := "AABBCC"
+
+	suite.mockCache.EXPECT().Get(ctx, id).Return(`{"long_url":"https://example.com","redirect_code":301}`, true, nil)
+
+	result, err := suite.manager.GetLongURL(ctx, id)
+	suite.Require().NoError(err)
+	suite.Equal(&shorturl.ShortURLResult{ShortURLId: id, LongURL: "https://example.com", RedirectCode: http.StatusMovedPermanently}, result)
+}
+
+func (suite *ManagerSuite) TestGetLongURLSuccessCacheHitForwardQueryParams() {
+	ctx := context.Background()
+	id := "AABBCC"
+
+	suite.mockCache.EXPECT().Get(ctx, id).Return(`{"long_url":"https://example.com","forward_query_params":true}`, true, nil)
+
+	result, err := suite.manager.GetLongURL(ctx, id)
+	suite.Require().NoError(err)
+	suite.Equal(&shorturl.ShortURLResult{ShortURLId: id, LongURL: "https://example.com", RedirectCode: http.StatusFound, ForwardQueryParams: true}, result)
+}
+
+func (suite *ManagerSuite) TestGetLongURLSuccessInterstitial() {
+	ctx := context.Background()
+	id := "AABBCC"
+
+	done := make(chan struct{})
+
+	suite.mockCache.EXPECT().Get(ctx, id).Return("", false, nil)
+	suite.mockStorage.EXPECT().GetLongURL(ctx, tenant.Default, id).
+		Return(&shorturl.ShortURL{Id: id, LongURL: "https://example.com", ShowInterstitial: true}, true, nil)
+	suite.mockCache.EXPECT().Set(gomock.Any(), id, `{"long_url":"https://example.com","show_interstitial":true}`, gomock.Any()).
+		DoAndReturn(func(context.Context, string, string, time.Duration) error {
+			close(done)
+			return nil
+		})
+
+	result, err := suite.manager.GetLongURL(ctx, id)
+	suite.Require().NoError(err)
+	suite.True(result.ShowInterstitial)
+
+	select {
+	case <-done:
+	case <-time.After(100 * time.Millisecond):
+		suite.Fail("Waiting for cache set timed out")
+	}
+
+	suite.mockCache.EXPECT().Get(ctx, id).Return(`{"long_url":"https://example.com","show_interstitial":true}`, true, nil)
+
+	result, err = suite.manager.GetLongURL(ctx, id)
+	suite.Require().NoError(err)
+	suite.Equal(&shorturl.ShortURLResult{ShortURLId: id, LongURL: "https://example.com", RedirectCode: http.StatusFound, ShowInterstitial: true}, result)
+}
+
+func (suite *ManagerSuite) TestGetLongURLSuccessCacheHitExpiresAt() {
+	ctx := context.Background()
+	id := "AABBCC"
+	expiresAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	suite.mockCache.EXPECT().Get(ctx, id).Return(`{"long_url":"https://example.com","expires_at":"2030-01-01T00:00:00Z"}`, true, nil)
+
+	result, err := suite.manager.GetLongURL(ctx, id)
+	suite.Require().NoError(err)
+	suite.Equal(&shorturl.ShortURLResult{ShortURLId: id, LongURL: "https://example.com", RedirectCode: http.StatusFound, ExpiresAt: &expiresAt}, result)
+}
+
+func (suite *ManagerSuite) TestGetLongURLFailCacheHitExpired() {
+	ctx := context.Background()
+	id := "AABBCC"
+
+	suite.mockCache.EXPECT().Get(ctx, id).Return(`{"long_url":"https://example.com","expires_at":"2020-01-01T00:00:00Z"}`, true, nil)
+
+	result, err := suite.manager.GetLongURL(ctx, id)
+	suite.Require().ErrorIs(err, shorturl.ErrShortURLExpired)
+	suite.Nil(result)
+}
+
+func (suite *ManagerSuite) TestGetLongURLSuccessInvalidCacheEntry() {
+	ctx := context.Background()
+	id := "AABBCC"
+
+	suite.mockCache.EXPECT().Get(ctx, id).Return(`{"long_url":"https://example.com","redirect_code":200}`, true, nil)
+	suite.mockStorage.EXPECT().GetLongURL(ctx, tenant.Default, id).
+		Return(&shorturl.ShortURL{Id: id, LongURL: "https://example.com", MaxClicks: 5}, true, nil)
+
+	result, err := suite.manager.GetLongURL(ctx, id)
+	suite.Require().NoError(err)
+	suite.Equal(&shorturl.ShortURLResult{ShortURLId: id, LongURL: "https://example.com", RedirectCode: http.StatusFound}, result)
+}
+
+func (suite *ManagerSuite) TestGetLongURLSuccessCacheTTL() {
+	ctx := context.Background()
+	id := "AABBCC"
+	suite.config.ShortURLCacheTTLInSeconds = 60 * 60
+	configuredTTL := time.Hour
+
+	testCases := []struct {
+		name            string
+		updatedAt       time.Time
+		cacheTTLSeconds int
+		minTTL          time.Duration
+		maxTTL          time.Duration
+	}{
+		{name: "recently updated", updatedAt: time.Now().Add(-time.Hour), minTTL: configuredTTL, maxTTL: configuredTTL},
+		{name: "updated almost a year ago", updatedAt: time.Now().AddDate(0, 0, -365).Add(10 * time.Minute),
+			minTTL: 9 * time.Minute, maxTTL: 10 * time.Minute},
+		{name: "custom TTL", updatedAt: time.Now().Add(-time.Hour), cacheTTLSeconds: 24 * 60 * 60, minTTL: 24 * time.Hour, maxTTL: 24 * time.Hour},
+		{name: "custom TTL updated almost a year ago", updatedAt: time.Now().AddDate(0, 0, -365).Add(10 * time.Minute), cacheTTLSeconds: 24 * 60 * 60,
+			minTTL: 9 * time.Minute, maxTTL: 10 * time.Minute},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			done := make(chan struct{})
+
+			suite.mockCache.EXPECT().Get(ctx, id).Return("", false, nil)
+			suite.mockStorage.EXPECT().GetLongURL(ctx, tenant.Default, id).
+				Return(&shorturl.ShortURL{Id: id, LongURL: "https://example.com", UpdatedAt: tc.updatedAt, CacheTTLSeconds: tc.cacheTTLSeconds}, true, nil)
+			suite.mockCache.EXPECT().Set(gomock.Any(), id, "https://example.com", gomock.Any()).
+				DoAndReturn(func(_ context.Context, _ string, _ string, ttl time.Duration) error {
+					suite.GreaterOrEqual(ttl, tc.minTTL)
+					suite.LessOrEqual(ttl, tc.maxTTL)
+
+					close(done)
+					return nil
+				})
+
+			_, err := suite.manager.GetLongURL(ctx, id)
+			suite.Require().NoError(err)
+
+			select {
+			case <-done:
+			case <-time.After(100 * time.Millisecond):
+				suite.Fail("Waiting for cache set timed out")
+			}
+		})
+	}
+}
+
+func (suite *ManagerSuite) TestGetLongURLSuccessCacheTTLBoundedByExpiration() {
+	ctx := context.Background()
+	id := "AABBCC"
+	expiresAt := time.Now().Add(10 * time.Second)
+	done := make(chan struct{})
+
+	suite.mockCache.EXPECT().Get(ctx, id).Return("", false, nil)
+	suite.mockStorage.EXPECT().GetLongURL(ctx, tenant.Default, id).
+		Return(&shorturl.ShortURL{Id: id, LongURL: "https://example.com", ExpiresAt: &expiresAt}, true, nil)
+	suite.mockCache.EXPECT().Set(gomock.Any(), id, gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, _ string, ttl time.Duration) error {
+			suite.Greater(ttl, 9*time.Second)
+			suite.LessOrEqual(ttl, 10*time.Second)
+
 			close(done)
 			return nil
 		})
 
-	result, err := suite.manager.GetLongURL(ctx, id)
-	suite.Require().NoError(err)
-	suite.Equal(expectedLongURL, result)
+	_, err := suite.manager.GetLongURL(ctx, id)
+	suite.Require().NoError(err)
+
+	select {
+	case <-done:
+	case <-time.After(100 * time.Millisecond):
+		suite.Fail("Waiting for cache set timed out")
+	}
+}
+
+func (suite *ManagerSuite) TestGetLongURLSuccessStaleNotCached() {
+	ctx := context.Background()
+	id := "AABBCC"
+
+	expectedLongURL := "https://example.com"
+
+	suite.mockCache.EXPECT().Get(ctx, id).Return("", false, nil)
+	suite.mockStorage.EXPECT().GetLongURL(ctx, tenant.Default, id).
+		Return(&shorturl.ShortURL{Id: id, LongURL: expectedLongURL, UpdatedAt: time.Now().AddDate(-2, 0, 0)}, true, nil)
+
+	result, err := suite.manager.GetLongURL(ctx, id)
+	suite.Require().NoError(err)
+	suite.Equal(&shorturl.ShortURLResult{ShortURLId: id, LongURL: expectedLongURL, RedirectCode: http.StatusFound}, result)
+}
+
+func (suite *ManagerSuite) TestGetLongURLSuccessClickLimitNotCached() {
+	ctx := context.Background()
+	id := "AABBCC"
+
+	expectedLongURL := "https://example.com"
+
+	suite.mockCache.EXPECT().Get(ctx, id).Return("", false, nil)
+	suite.mockStorage.EXPECT().GetLongURL(ctx, tenant.Default, id).
+		Return(&shorturl.ShortURL{Id: id, LongURL: expectedLongURL, MaxClicks: 5, ClickCount: 1}, true, nil)
+
+	result, err := suite.manager.GetLongURL(ctx, id)
+	suite.Require().NoError(err)
+	suite.Equal(&shorturl.ShortURLResult{ShortURLId: id, LongURL: expectedLongURL, RedirectCode: http.StatusFound}, result)
+}
+
+func (suite *ManagerSuite) TestGetLongURLFailClickLimitExceeded() {
+	ctx := context.Background()
+	id := "AABBCC"
+
+	suite.mockCache.EXPECT().Get(ctx, id).Return("", false, nil)
+	suite.mockStorage.EXPECT().GetLongURL(ctx, tenant.Default, id).Return(nil, false, shorturl.ErrClickLimitExceeded).Times(1)
+
+	result, err := suite.manager.GetLongURL(ctx, id)
+	suite.Require().ErrorIs(err, shorturl.ErrClickLimitExceeded)
+	suite.Empty(result)
+}
+
+func (suite *ManagerSuite) TestGetLongURLFailPasswordRequired() {
+	ctx := context.Background()
+	id := "AABBCC"
+
+	suite.mockCache.EXPECT().Get(ctx, id).Return("", false, nil)
+	suite.mockStorage.EXPECT().GetLongURL(ctx, tenant.Default, id).
+		Return(&shorturl.ShortURL{Id: id, LongURL: "https://example.com", PasswordHash: "hash"}, true, nil)
+
+	result, err := suite.manager.GetLongURL(ctx, id)
+	suite.Require().ErrorIs(err, shorturl.ErrPasswordRequired)
+	suite.Empty(result)
+}
+
+func (suite *ManagerSuite) TestGetLongURLFailClickRateLimitExceeded() {
+	ctx := context.Background()
+
+	suite.config.ClickRateLimitRPS = 1
+	suite.config.ClickRateLimitBurst = 1
+	manager, err := shorturl.NewManager(suite.config, suite.mockStorage, suite.mockCache, suite.mockLogger)
+	suite.Require().NoError(err)
+
+	suite.mockCache.EXPECT().Get(ctx, "AABBCC").Return("https://example.com", true, nil).Times(1)
+
+	errs := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			_, err := manager.GetLongURL(ctx, "AABBCC")
+			errs <- err
+		}()
+	}
+
+	var allowed, limited int
+	for i := 0; i < 2; i++ {
+		switch err := <-errs; {
+		case err == nil:
+			allowed++
+		case errors.Is(err, shorturl.ErrClickRateLimitExceeded):
+			limited++
+		default:
+			suite.Failf("unexpected error", "%v", err)
+		}
+	}
+	suite.Equal(1, allowed)
+	suite.Equal(1, limited)
+
+	// Every short URL has its own limit
+	suite.mockCache.EXPECT().Get(ctx, "DDEEFF").Return("https://example.com", true, nil)
+	_, err = manager.GetLongURL(ctx, "DDEEFF")
+	suite.Require().NoError(err)
+}
+
+func (suite *ManagerSuite) TestUnlockLongURLSuccessNotCached() {
+	ctx := context.Background()
+	id := "AABBCC"
+
+	suite.mockCache.EXPECT().Get(ctx, id).Return("", false, nil)
+	suite.mockStorage.EXPECT().GetLongURL(ctx, tenant.Default, id).
+		Return(&shorturl.ShortURL{Id: id, LongURL: "https://example.com", PasswordHash: "hash"}, true, nil)
+
+	result, err := suite.manager.UnlockLongURL(ctx, id)
+	suite.Require().NoError(err)
+	suite.Equal(&shorturl.ShortURLResult{ShortURLId: id, LongURL: "https://example.com", RedirectCode: http.StatusFound}, result)
+}
+
+func (suite *ManagerSuite) TestGetLongURLFailNotFound() {
+	ctx := context.Background()
+	id := "AABBCC"
+
+	suite.mockCache.EXPECT().Get(ctx, id).Return("", false, nil)
+	suite.mockStorage.EXPECT().GetLongURL(ctx, tenant.Default, id).Return(nil, false, nil)
+	suite.mockStorage.EXPECT().GetLongURLByAlias(ctx, tenant.Default, id).Return("", "", false, nil)
+
+	result, err := suite.manager.GetLongURL(ctx, id)
+	suite.Require().ErrorIs(err, shorturl.ErrShortURLNotFound)
+	suite.Zero(result)
+}
+
+func (suite *ManagerSuite) TestGetLongURLSuccessAlias() {
+	ctx := context.Background()
+	aliasId := "OLDID1"
+	id := "AABBCC"
+
+	suite.mockCache.EXPECT().Get(ctx, aliasId).Return("", false, nil)
+	suite.mockStorage.EXPECT().GetLongURL(ctx, tenant.Default, aliasId).Return(nil, false, nil)
+	suite.mockStorage.EXPECT().GetLongURLByAlias(ctx, tenant.Default, aliasId).Return(id, "https://example.com", true, nil)
+	suite.mockCache.EXPECT().Get(ctx, id).Return("https://example.com", true, nil)
+
+	result, err := suite.manager.GetLongURL(ctx, aliasId)
+	suite.Require().NoError(err)
+	suite.Equal(&shorturl.ShortURLResult{ShortURLId: id, LongURL: "https://example.com", RedirectCode: http.StatusFound}, result)
+}
+
+func (suite *ManagerSuite) TestGetLongURLFailAliasOfDeletedShortURL() {
+	ctx := context.Background()
+	aliasId := "OLDID1"
+	id := "AABBCC"
+
+	suite.mockCache.EXPECT().Get(ctx, aliasId).Return("", false, nil)
+	suite.mockStorage.EXPECT().GetLongURL(ctx, tenant.Default, aliasId).Return(nil, false, nil)
+	suite.mockStorage.EXPECT().GetLongURLByAlias(ctx, tenant.Default, aliasId).Return(id, "https://example.com", true, nil)
+	// The short URL the alias points to is deleted in between, the canonical id is not looked up as an alias again
+	suite.mockCache.EXPECT().Get(ctx, id).Return("", false, nil)
+	suite.mockStorage.EXPECT().GetLongURL(ctx, tenant.Default, id).Return(nil, false, nil)
+
+	result, err := suite.manager.GetLongURL(ctx, aliasId)
+	suite.Require().ErrorIs(err, shorturl.ErrShortURLNotFound)
+	suite.Zero(result)
+}
+
+func (suite *ManagerSuite) TestGetLongURLFailInactive() {
+	ctx := context.Background()
+	id := "AABBCC"
+
+	for _, expectedError := range []error{shorturl.ErrShortURLPaused, shorturl.ErrShortURLArchived, shorturl.ErrShortURLExpired} {
+		suite.Run(expectedError.Error(), func() {
+			suite.mockCache.EXPECT().Get(ctx, id).Return("", false, nil)
+			// Inactive and expired short URLs are not retried
+			suite.mockStorage.EXPECT().GetLongURL(ctx, tenant.Default, id).Return(nil, false, expectedError)
+
+			result, err := suite.manager.GetLongURL(ctx, id)
+			suite.Require().ErrorIs(err, expectedError)
+			suite.Nil(result)
+		})
+	}
+}
+
+func (suite *ManagerSuite) TestStatusCanTransitionTo() {
+	testCases := []struct {
+		from     shorturl.Status
+		to       shorturl.Status
+		expected bool
+	}{
+		{from: shorturl.StatusActive, to: shorturl.StatusPaused, expected: true},
+		{from: shorturl.StatusActive, to: shorturl.StatusArchived, expected: true},
+		{from: shorturl.StatusActive, to: shorturl.StatusActive, expected: false},
+		{from: shorturl.StatusPaused, to: shorturl.StatusActive, expected: true},
+		{from: shorturl.StatusPaused, to: shorturl.StatusArchived, expected: true},
+		{from: shorturl.StatusPaused, to: shorturl.StatusPaused, expected: false},
+		{from: shorturl.StatusArchived, to: shorturl.StatusActive, expected: false},
+		{from: shorturl.StatusArchived, to: shorturl.StatusPaused, expected: false},
+		{from: shorturl.StatusArchived, to: shorturl.StatusArchived, expected: false},
+		{from: shorturl.StatusActive, to: "deleted", expected: false},
+	}
+
+	for _, testCase := range testCases {
+		suite.Run(string(testCase.from)+" to "+string(testCase.to), func() {
+			suite.Equal(testCase.expected, testCase.from.CanTransitionTo(testCase.to))
+		})
+	}
+}
+
+func (suite *ManagerSuite) TestUpdateShortURLStatusSuccess() {
+	ctx := context.Background()
+	id := "AABBCC"
+
+	suite.mockStorage.EXPECT().GetShortURL(ctx, tenant.Default, id).Return(&shorturl.ShortURL{Id: id, Status: shorturl.StatusActive}, true, nil)
+	suite.mockStorage.EXPECT().UpdateShortURLStatus(ctx, tenant.Default, id, shorturl.StatusActive, shorturl.StatusPaused).Return(true, nil)
+	suite.mockCache.EXPECT().Delete(ctx, id).Return(nil)
+
+	suite.Require().NoError(suite.manager.UpdateShortURLStatus(ctx, id, shorturl.StatusPaused))
+}
+
+func (suite *ManagerSuite) TestUpdateShortURLStatusFailInvalidTransition() {
+	ctx := context.Background()
+	id := "AABBCC"
+
+	suite.mockStorage.EXPECT().GetShortURL(ctx, tenant.Default, id).Return(&shorturl.ShortURL{Id: id, Status: shorturl.StatusArchived}, true, nil)
+
+	err := suite.manager.UpdateShortURLStatus(ctx, id, shorturl.StatusActive)
+	suite.Require().ErrorIs(err, shorturl.ErrInvalidStatusTransition)
+}
+
+func (suite *ManagerSuite) TestUpdateShortURLStatusFailConcurrentChange() {
+	ctx := context.Background()
+	id := "AABBCC"
+
+	suite.mockStorage.EXPECT().GetShortURL(ctx, tenant.Default, id).Return(&shorturl.ShortURL{Id: id, Status: shorturl.StatusPaused}, true, nil)
+	suite.mockStorage.EXPECT().UpdateShortURLStatus(ctx, tenant.Default, id, shorturl.StatusPaused, shorturl.StatusActive).Return(false, nil)
+
+	err := suite.manager.UpdateShortURLStatus(ctx, id, shorturl.StatusActive)
+	suite.Require().ErrorIs(err, shorturl.ErrInvalidStatusTransition)
+}
+
+func (suite *ManagerSuite) TestUpdateShortURLStatusFailNotFound() {
+	ctx := context.Background()
+	id := "AABBCC"
+
+	suite.mockStorage.EXPECT().GetShortURL(ctx, tenant.Default, id).Return(nil, false, nil)
+
+	err := suite.manager.UpdateShortURLStatus(ctx, id, shorturl.StatusPaused)
+	suite.Require().ErrorIs(err, shorturl.ErrShortURLNotFound)
+}
+
+func (suite *ManagerSuite) TestCreateAliasSuccess() {
+	ctx := context.Background()
+
+	suite.mockStorage.EXPECT().GetShortURL(ctx, tenant.Default, "AABBCC").Return(&shorturl.ShortURL{Id: "AABBCC"}, true, nil)
+	suite.mockStorage.EXPECT().GetShortURL(ctx, tenant.Default, "OLDID1").Return(nil, false, nil)
+	suite.mockStorage.EXPECT().CreateAlias(ctx, tenant.Default, "OLDID1", "AABBCC").Return(nil)
+
+	suite.Require().NoError(suite.manager.CreateAlias(ctx, "AABBCC", "OLDID1"))
+}
+
+func (suite *ManagerSuite) TestCreateAliasFailInvalidAliasId() {
+	ctx := context.Background()
+
+	for _, aliasId := range []string{"", "OLDID", "OLDID12", "OLD-I1"} {
+		suite.Run(aliasId, func() {
+			suite.Require().ErrorIs(suite.manager.CreateAlias(ctx, "AABBCC", aliasId), shorturl.ErrInvalidAliasId)
+		})
+	}
+}
+
+func (suite *ManagerSuite) TestCreateAliasFailSlugErrors() {
+	ctx := context.Background()
+
+	testCases := map[string]struct {
+		aliasId     string
+		expectedErr error
+	}{
+		"too long":      {aliasId: "OLDID12", expectedErr: shorturl.ErrSlugTooLong},
+		"invalid chars": {aliasId: "OLD-I1", expectedErr: shorturl.ErrSlugInvalidChars},
+		"too short":     {aliasId: "OLDID", expectedErr: shorturl.ErrInvalidAliasId},
+	}
+
+	for name, tc := range testCases {
+		suite.Run(name, func() {
+			err := suite.manager.CreateAlias(ctx, "AABBCC", tc.aliasId)
+			suite.Require().ErrorIs(err, tc.expectedErr)
+			suite.ErrorIs(err, shorturl.ErrInvalidAliasId)
+		})
+	}
+
+	// Errors are returned as they are, not wrapped
+	suite.Equal(shorturl.ErrSlugTooLong, suite.manager.CreateAlias(ctx, "AABBCC", "OLDID12"))
+}
+
+func (suite *ManagerSuite) TestCreateAliasFailShortURLNotFound() {
+	ctx := context.Background()
+
+	suite.mockStorage.EXPECT().GetShortURL(ctx, tenant.Default, "AABBCC").Return(nil, false, nil)
+
+	suite.Require().ErrorIs(suite.manager.CreateAlias(ctx, "AABBCC", "OLDID1"), shorturl.ErrShortURLNotFound)
+}
+
+func (suite *ManagerSuite) TestCreateAliasFailAliasIdIsShortURL() {
+	ctx := context.Background()
+
+	suite.mockStorage.EXPECT().GetShortURL(ctx, tenant.Default, "AABBCC").Return(&shorturl.ShortURL{Id: "AABBCC"}, true, nil)
+	suite.mockStorage.EXPECT().GetShortURL(ctx, tenant.Default, "OLDID1").Return(&shorturl.ShortURL{Id: "OLDID1"}, true, nil)
+
+	suite.Require().ErrorIs(suite.manager.CreateAlias(ctx, "AABBCC", "OLDID1"), shorturl.ErrShortURLExists)
+}
+
+func (suite *ManagerSuite) TestCreateAliasFailAliasExists() {
+	ctx := context.Background()
+
+	suite.mockStorage.EXPECT().GetShortURL(ctx, tenant.Default, "AABBCC").Return(&shorturl.ShortURL{Id: "AABBCC"}, true, nil)
+	suite.mockStorage.EXPECT().GetShortURL(ctx, tenant.Default, "OLDID1").Return(nil, false, nil)
+	suite.mockStorage.EXPECT().CreateAlias(ctx, tenant.Default, "OLDID1", "AABBCC").Return(fmt.Errorf("alias OLDID1: %w", shorturl.ErrAliasExists))
+
+	suite.Require().ErrorIs(suite.manager.CreateAlias(ctx, "AABBCC", "OLDID1"), shorturl.ErrAliasExists)
+}
+
+func (suite *ManagerSuite) TestDeleteAliasSuccess() {
+	ctx := context.Background()
+
+	suite.mockStorage.EXPECT().DeleteAlias(ctx, tenant.Default, "AABBCC", "OLDID1").Return(true, nil)
+
+	suite.Require().NoError(suite.manager.DeleteAlias(ctx, "AABBCC", "OLDID1"))
+}
+
+func (suite *ManagerSuite) TestDeleteAliasFailNotFound() {
+	ctx := context.Background()
+
+	suite.mockStorage.EXPECT().DeleteAlias(ctx, tenant.Default, "AABBCC", "OLDID1").Return(false, nil)
+
+	suite.Require().ErrorIs(suite.manager.DeleteAlias(ctx, "AABBCC", "OLDID1"), shorturl.ErrAliasNotFound)
+}
+
+func (suite *ManagerSuite) TestGetLongURLFailStorageGetLongURLError() {
+	ctx := context.Background()
+	id := "AABBCC"
+
+	expectedError := errors.New("some storage error")
+
+	suite.mockCache.EXPECT().Get(ctx, id).Return("", false, nil)
+	suite.mockStorage.EXPECT().GetLongURL(ctx, tenant.Default, id).Return(nil, false, expectedError)
+
+	result, err := suite.manager.GetLongURL(ctx, id)
+	suite.Require().ErrorIs(err, expectedError)
+	suite.Zero(result)
+}
+
+func (suite *ManagerSuite) TestGetLongURLFailStorageNotRetried() {
+	ctx := context.Background()
+	id := "AABBCC"
+
+	// Getting the long URL counts a click, retrying it could count the click twice
+	expectedError := errors.New("some transient error")
+	suite.config.MaxStorageRetries = 2
+
+	suite.mockCache.EXPECT().Get(ctx, id).Return("", false, nil)
+	suite.mockStorage.EXPECT().GetLongURL(ctx, tenant.Default, id).Return(nil, false, expectedError)
+
+	result, err := suite.manager.GetLongURL(ctx, id)
+	suite.Require().ErrorIs(err, expectedError)
+	suite.Zero(result)
+}
+
+func (suite *ManagerSuite) TestGetShortURLSuccessStorageRetry() {
+	ctx := context.Background()
+	id := "AABBCC"
+
+	expectedShortURL := &shorturl.ShortURL{Id: id, LongURL: "https://example.com"}
+	suite.config.MaxStorageRetries = 2
+
+	gomock.InOrder(
+		suite.mockStorage.EXPECT().GetShortURL(ctx, tenant.Default, id).Return(nil, false, errors.New("some transient error")),
+		suite.mockStorage.EXPECT().GetShortURL(ctx, tenant.Default, id).Return(expectedShortURL, true, nil),
+	)
+
+	result, err := suite.manager.GetShortURL(ctx, id)
+	suite.Require().NoError(err)
+	suite.Equal(expectedShortURL, result)
+}
+
+func (suite *ManagerSuite) TestGetShortURLFailStorageRetriesExhausted() {
+	ctx := context.Background()
+	id := "AABBCC"
+
+	expectedError := errors.New("some transient error")
+	suite.config.MaxStorageRetries = 2
+
+	suite.mockStorage.EXPECT().GetShortURL(ctx, tenant.Default, id).Return(nil, false, expectedError).Times(suite.config.MaxStorageRetries + 1)
+
+	result, err := suite.manager.GetShortURL(ctx, id)
+	suite.Require().ErrorIs(err, expectedError)
+	suite.Nil(result)
+}
+
+func (suite *ManagerSuite) TestGetShortURLFailStorageRetryContextCancelled() {
+	ctx, cancel := context.WithCancel(context.Background())
+	id := "AABBCC"
+
+	expectedError := errors.New("some transient error")
+	suite.config.MaxStorageRetries = 2
+
+	suite.mockStorage.EXPECT().GetShortURL(ctx, tenant.Default, id).
+		DoAndReturn(func(_ context.Context, _ string, _ string) (*shorturl.ShortURL, bool, error) {
+			cancel()
+
+			return nil, false, expectedError
+		})
+
+	result, err := suite.manager.GetShortURL(ctx, id)
+	suite.Require().ErrorIs(err, expectedError)
+	suite.Nil(result)
+}
+
+func (suite *ManagerSuite) TestGetLongURLFailContextCancelled() {
+	ctx, cancel := context.WithCancel(context.Background())
+	id := "AABBCC"
+
+	suite.config.MaxStorageRetries = 2
+
+	suite.mockCache.EXPECT().Get(ctx, id).Return("", false, nil)
+	suite.mockStorage.EXPECT().GetLongURL(ctx, tenant.Default, id).
+		DoAndReturn(func(ctx context.Context, _ string, _ string) (*shorturl.ShortURL, bool, error) {
+			<-ctx.Done()
+
+			return nil, false, ctx.Err()
+		})
+
+	time.AfterFunc(10*time.Millisecond, cancel)
+
+	result, err := suite.manager.GetLongURL(ctx, id)
+	suite.Require().ErrorIs(err, context.Canceled)
+	suite.Zero(result)
+}
+
+func (suite *ManagerSuite) TestGetLongURLBulkSuccessPartialCacheHit() {
+	ctx := context.Background()
+	ttl := time.Second * time.Duration(suite.config.ShortURLCacheTTLInSeconds)
+
+	suite.mockCache.EXPECT().GetMulti(ctx, []string{"AAAAAA", "BBBBBB", "CCCCCC", "DDDDDD", "EEEEEE"}).Return(map[string]string{
+		"AAAAAA": "https://a.example.com",
+		"CCCCCC": `{"long_url":"https://c.example.com","expires_at":"2020-01-01T00:00:00Z"}`,
+		"DDDDDD": "{invalid",
+	}, nil)
+	suite.mockStorage.EXPECT().GetShortURLs(ctx, tenant.Default, []string{"BBBBBB", "DDDDDD", "EEEEEE"}).Return([]*shorturl.ShortURL{
+		{Id: "BBBBBB", LongURL: "https://b.example.com", Status: shorturl.StatusActive},
+		{Id: "DDDDDD", LongURL: "https://d.example.com", Status: shorturl.StatusActive},
+	}, nil)
+	suite.mockCache.EXPECT().Set(ctx, "BBBBBB", "https://b.example.com", ttl).Return(nil)
+	suite.mockCache.EXPECT().Set(ctx, "DDDDDD", "https://d.example.com", ttl).Return(nil)
+
+	longURLs, notFound, err := suite.manager.GetLongURLBulk(ctx, []string{"AAAAAA", "BBBBBB", "CCCCCC", "AAAAAA", "DDDDDD", "EEEEEE"})
+	suite.Require().NoError(err)
+	suite.Equal(map[string]string{
+		"AAAAAA": "https://a.example.com",
+		"BBBBBB": "https://b.example.com",
+		"DDDDDD": "https://d.example.com",
+	}, longURLs)
+	suite.Equal([]string{"CCCCCC", "EEEEEE"}, notFound)
+}
+
+func (suite *ManagerSuite) TestGetLongURLBulkSuccessAllCached() {
+	ctx := context.Background()
+
+	suite.mockCache.EXPECT().GetMulti(ctx, []string{"AAAAAA", "BBBBBB"}).Return(map[string]string{
+		"AAAAAA": "https://a.example.com",
+		"BBBBBB": "https://b.example.com",
+	}, nil)
+
+	longURLs, notFound, err := suite.manager.GetLongURLBulk(ctx, []string{"AAAAAA", "BBBBBB"})
+	suite.Require().NoError(err)
+	suite.Equal(map[string]string{"AAAAAA": "https://a.example.com", "BBBBBB": "https://b.example.com"}, longURLs)
+	suite.Empty(notFound)
+}
+
+func (suite *ManagerSuite) TestGetLongURLBulkSuccessCacheError() {
+	ctx := context.Background()
+	ttl := time.Second * time.Duration(suite.config.ShortURLCacheTTLInSeconds)
+
+	suite.mockCache.EXPECT().GetMulti(ctx, []string{"AAAAAA", "BBBBBB"}).Return(nil, errors.New("cache error"))
+	suite.mockStorage.EXPECT().GetShortURLs(ctx, tenant.Default, []string{"AAAAAA", "BBBBBB"}).Return([]*shorturl.ShortURL{
+		{Id: "AAAAAA", LongURL: "https://a.example.com", Status: shorturl.StatusActive},
+	}, nil)
+	suite.mockCache.EXPECT().Set(ctx, "AAAAAA", "https://a.example.com", ttl).Return(nil)
+
+	longURLs, notFound, err := suite.manager.GetLongURLBulk(ctx, []string{"AAAAAA", "BBBBBB"})
+	suite.Require().NoError(err)
+	suite.Equal(map[string]string{"AAAAAA": "https://a.example.com"}, longURLs)
+	suite.Equal([]string{"BBBBBB"}, notFound)
+}
+
+func (suite *ManagerSuite) TestGetLongURLBulkSuccessNotRedirecting() {
+	ctx := tenant.WithID(context.Background(), "acme")
+	past := time.Now().Add(-time.Minute)
+
+	suite.mockCache.EXPECT().GetMulti(ctx, []string{"acme/AAAAAA", "acme/BBBBBB", "acme/CCCCCC", "acme/DDDDDD"}).Return(map[string]string{}, nil)
+	suite.mockStorage.EXPECT().GetShortURLs(ctx, "acme", []string{"AAAAAA", "BBBBBB", "CCCCCC", "DDDDDD"}).Return([]*shorturl.ShortURL{
+		{Id: "AAAAAA", LongURL: "https://a.example.com", Status: shorturl.StatusPaused},
+		{Id: "BBBBBB", LongURL: "https://b.example.com", Status: shorturl.StatusActive, PasswordHash: "hash"},
+		{Id: "CCCCCC", LongURL: "https://c.example.com", Status: shorturl.StatusActive, ExpiresAt: &past},
+		{Id: "DDDDDD", LongURL: "https://d.example.com", Status: shorturl.StatusActive, MaxClicks: 10},
+	}, nil)
+
+	longURLs, notFound, err := suite.manager.GetLongURLBulk(ctx, []string{"AAAAAA", "BBBBBB", "CCCCCC", "DDDDDD"})
+	suite.Require().NoError(err)
+	suite.Equal(map[string]string{"DDDDDD": "https://d.example.com"}, longURLs)
+	suite.Equal([]string{"AAAAAA", "BBBBBB", "CCCCCC"}, notFound)
+}
+
+func (suite *ManagerSuite) TestGetLongURLBulkFailStorageError() {
+	ctx := context.Background()
+
+	suite.mockCache.EXPECT().GetMulti(ctx, []string{"AAAAAA"}).Return(map[string]string{}, nil)
+	suite.mockStorage.EXPECT().GetShortURLs(ctx, tenant.Default, []string{"AAAAAA"}).Return(nil, errors.New("storage error"))
+
+	longURLs, notFound, err := suite.manager.GetLongURLBulk(ctx, []string{"AAAAAA"})
+	suite.Require().Error(err)
+	suite.Nil(longURLs)
+	suite.Nil(notFound)
+}
+
+func (suite *ManagerSuite) TestGetShortURLSuccess() {
+	ctx := context.Background()
+	id := "AABBCC"
+
+	expectedShortURL := &shorturl.ShortURL{
+		Id:        id,
+		LongURL:   "https://example.com",
+		CreatedAt: time.Now(),
+	}
+
+	suite.mockStorage.EXPECT().GetShortURL(ctx, tenant.Default, id).Return(expectedShortURL, true, nil)
+
+	result, err := suite.manager.GetShortURL(ctx, id)
+	suite.Require().NoError(err)
+	suite.Equal(expectedShortURL, result)
+}
+
+func (suite *ManagerSuite) TestGetShortURLFailNotFound() {
+	ctx := context.Background()
+	id := "AABBCC"
+
+	suite.mockStorage.EXPECT().GetShortURL(ctx, tenant.Default, id).Return(nil, false, nil)
+
+	result, err := suite.manager.GetShortURL(ctx, id)
+	suite.Require().ErrorIs(err, shorturl.ErrShortURLNotFound)
+	suite.Nil(result)
+}
+
+func (suite *ManagerSuite) TestCreateShortURLSuccess() {
+	ctx := context.Background()
+	longURL := "https://example.com"
+
+	expectedId, err := suite.manager.GenerateIdWithOffset(longURL, 0)
+	suite.Require().NoError(err)
+
+	suite.mockStorage.EXPECT().TryCreateShortURL(ctx, tenant.Default, &shorturl.ShortURL{Id: expectedId, LongURL: longURL}).Return(&shorturl.ShortURL{Id: expectedId, LongURL: longURL}, false, nil)
+
+	shortURL, err := suite.manager.CreateShortURL(ctx, longURL, nil)
+	suite.Require().NoError(err)
+	suite.Equal(expectedId, shortURL.Id)
+}
+
+func (suite *ManagerSuite) TestCreateShortURLSuccessCreatedBy() {
+	ctx := user.WithID(context.Background(), "user-1")
+	longURL := "https://example.com"
+
+	expectedId, err := suite.manager.GenerateIdWithOffset(longURL, 0)
+	suite.Require().NoError(err)
+
+	suite.mockStorage.EXPECT().TryCreateShortURL(ctx, tenant.Default, &shorturl.ShortURL{Id: expectedId, LongURL: longURL, CreatedBy: "user-1"}).
+		Return(&shorturl.ShortURL{Id: expectedId, LongURL: longURL, CreatedBy: "user-1"}, false, nil)
+
+	shortURL, err := suite.manager.CreateShortURL(ctx, longURL, nil)
+	suite.Require().NoError(err)
+	suite.Equal("user-1", shortURL.CreatedBy)
+}
+
+func (suite *ManagerSuite) TestCreateShortURLSuccessTenant() {
+	ctx := tenant.WithID(context.Background(), "acme")
+	longURL := "https://example.com"
+
+	expectedId, err := suite.manager.GenerateIdWithOffset(longURL, 0)
+	suite.Require().NoError(err)
+
+	// The same id taken in another tenant does not collide, only the tenant of ctx is checked
+	suite.mockStorage.EXPECT().TryCreateShortURL(ctx, "acme", &shorturl.ShortURL{Id: expectedId, LongURL: longURL}).
+		Return(&shorturl.ShortURL{TenantId: "acme", Id: expectedId, LongURL: longURL}, false, nil)
+
+	shortURL, err := suite.manager.CreateShortURL(ctx, longURL, nil)
+	suite.Require().NoError(err)
+	suite.Equal(expectedId, shortURL.Id)
+	suite.Equal("acme", shortURL.TenantId)
+}
+
+func (suite *ManagerSuite) TestCreateShortURLSuccessAlreadyExists() {
+	ctx := context.Background()
+	longURL := "https://example.com"
+
+	expectedId, err := suite.manager.GenerateIdWithOffset(longURL, 0)
+	suite.Require().NoError(err)
+
+	suite.mockStorage.EXPECT().TryCreateShortURL(ctx, tenant.Default, &shorturl.ShortURL{Id: expectedId, LongURL: longURL}).Return(nil, true, nil)
+	suite.mockStorage.EXPECT().GetShortURL(ctx, tenant.Default, expectedId).Return(&shorturl.ShortURL{Id: expectedId, LongURL: longURL}, true, nil)
+
+	shortURL, err := suite.manager.CreateShortURL(ctx, longURL, nil)
+	suite.Require().NoError(err)
+	suite.Equal(expectedId, shortURL.Id)
+}
+
+func (suite *ManagerSuite) TestCreateShortURLSuccessHashCollision() {
+	ctx := context.Background()
+	longURL := "https://example.com"
+	someOtherLongURL := "https://another-example.com"
+
+	expectedId0, err := suite.manager.GenerateIdWithOffset(longURL, 0)
+	suite.Require().NoError(err)
+
+	expectedId1, err := suite.manager.GenerateIdWithOffset(longURL, 1)
+	suite.Require().NoError(err)
+
+	suite.mockStorage.EXPECT().TryCreateShortURL(ctx, tenant.Default, &shorturl.ShortURL{Id: expectedId0, LongURL: longURL}).Return(nil, true, nil)
+	suite.mockStorage.EXPECT().GetShortURL(ctx, tenant.Default, expectedId0).Return(&shorturl.ShortURL{Id: expectedId0, LongURL: someOtherLongURL}, true, nil)
+	suite.mockStorage.EXPECT().TryCreateShortURL(ctx, tenant.Default, &shorturl.ShortURL{Id: expectedId1, LongURL: longURL}).Return(&shorturl.ShortURL{Id: expectedId1, LongURL: longURL}, false, nil)
+
+	shortURL, err := suite.manager.CreateShortURL(ctx, longURL, nil)
+	suite.Require().NoError(err)
+	suite.Equal(expectedId1, shortURL.Id)
+}
+
+func (suite *ManagerSuite) TestGetOrCreateShortURLSuccess() {
+	ctx := context.Background()
+	longURL := "https://example.com"
+
+	expectedId, err := suite.manager.GenerateIdWithOffset(longURL, 0)
+	suite.Require().NoError(err)
+
+	gomock.InOrder(
+		suite.mockStorage.EXPECT().TryCreateShortURL(ctx, tenant.Default, &shorturl.ShortURL{Id: expectedId, LongURL: longURL}).
+			Return(&shorturl.ShortURL{Id: expectedId, LongURL: longURL}, false, nil),
+		suite.mockStorage.EXPECT().TryCreateShortURL(ctx, tenant.Default, &shorturl.ShortURL{Id: expectedId, LongURL: longURL}).
+			Return(nil, true, nil),
+		suite.mockStorage.EXPECT().GetShortURL(ctx, tenant.Default, expectedId).
+			Return(&shorturl.ShortURL{Id: expectedId, LongURL: longURL}, true, nil),
+	)
+
+	id, created, err := suite.manager.GetOrCreateShortURL(ctx, longURL)
+	suite.Require().NoError(err)
+	suite.Equal(expectedId, id)
+	suite.True(created)
+
+	id, created, err = suite.manager.GetOrCreateShortURL(ctx, longURL)
+	suite.Require().NoError(err)
+	suite.Equal(expectedId, id)
+	suite.False(created)
+}
+
+func (suite *ManagerSuite) TestGetOrCreateShortURLFailInvalidURL() {
+	id, created, err := suite.manager.GetOrCreateShortURL(context.Background(), "not a URL")
+	suite.ErrorIs(err, shorturl.ErrInvalidLongURL)
+	suite.Empty(id)
+	suite.False(created)
+}
+
+func (suite *ManagerSuite) TestCreateShortURLBulk() {
+	ctx := context.Background()
+	longURL := "https://example.com"
+
+	expectedId, err := suite.manager.GenerateIdWithOffset(longURL, 0)
+	suite.Require().NoError(err)
+
+	suite.mockStorage.EXPECT().TryCreateShortURL(ctx, tenant.Default, &shorturl.ShortURL{Id: expectedId, LongURL: longURL, Tags: []string{"campaign"}}).
+		Return(&shorturl.ShortURL{Id: expectedId, LongURL: longURL, Tags: []string{"campaign"}}, false, nil)
+
+	// The invalid entry fails on its own, the entries after it are still created
+	results := suite.manager.CreateShortURLBulk(ctx, []*shorturl.BulkEntry{
+		{LongURL: "not a URL"},
+		{LongURL: longURL, Options: &shorturl.CreateOptions{Tags: []string{"campaign"}}},
+	})
+	suite.Require().Len(results, 2)
+	suite.ErrorIs(results[0].Err, shorturl.ErrInvalidLongURL)
+	suite.Nil(results[0].ShortURL)
+	suite.Require().NoError(results[1].Err)
+	suite.Equal(expectedId, results[1].ShortURL.Id)
+}
+
+func (suite *ManagerSuite) TestCreateShortURLFailInvalidURL() {
+	ctx := context.Background()
+	testCases := []struct {
+		name       string
+		invalidURL string
+	}{
+		{
+			name:       "invalid URL prefix",
+			invalidURL: "htp://example.com",
+		},
+		{
+			name:       "empty URL",
+			invalidURL: "",
+		},
+		{
+			name:       "http URL",
+			invalidURL: "http://example.com",
+		},
+		{
+			name:       "malformed URL",
+			invalidURL: "https://exa mple.com",
+		},
+		{
+			name:       "missing host",
+			invalidURL: "https:///path",
+		},
+		{
+			name:       "missing scheme separator",
+			invalidURL: "https:example.com",
+		},
+		{
+			name:       "invalid internationalized domain",
+			invalidURL: "https://exa mple.com",
+		},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			shortURL, err := suite.manager.CreateShortURL(ctx, tc.invalidURL, nil)
+			suite.Require().ErrorIs(err, shorturl.ErrInvalidLongURL)
+			suite.Nil(shortURL)
+		})
+
+	}
+}
+
+func (suite *ManagerSuite) TestCreateShortURLMaxLongURLLength() {
+	ctx := context.Background()
+	prefix := "https://example.com/"
+
+	longURL := prefix + strings.Repeat("a", 2048-len(prefix))
+	expectedId, err := suite.manager.GenerateIdWithOffset(longURL, 0)
+	suite.Require().NoError(err)
+
+	expectedShortURL := &shorturl.ShortURL{Id: expectedId, LongURL: longURL}
+	suite.mockStorage.EXPECT().TryCreateShortURL(ctx, tenant.Default, expectedShortURL).Return(expectedShortURL, false, nil)
+
+	shortURL, err := suite.manager.CreateShortURL(ctx, longURL, nil)
+	suite.Require().NoError(err)
+	suite.Equal(expectedShortURL, shortURL)
+
+	shortURL, err = suite.manager.CreateShortURL(ctx, longURL+"a", nil)
+	suite.Require().ErrorIs(err, shorturl.ErrInvalidLongURL)
+	suite.Equal(shorturl.ErrURLTooLong, err)
+	suite.Nil(shortURL)
+
+	// Percent-encoding makes the canonical form of the long URL longer than the one given
+	shortURL, err = suite.manager.CreateShortURL(ctx, prefix+strings.Repeat("ü", 400), nil)
+	suite.Require().ErrorIs(err, shorturl.ErrInvalidLongURL)
+	suite.Nil(shortURL)
+}
+
+func (suite *ManagerSuite) TestCreateShortURLFailDeniedHost() {
+	ctx := context.Background()
+	suite.config.DeniedHosts = []string{"evil.example", "MÜNCHEN.de"}
+
+	for _, longURL := range []string{
+		"https://evil.example/path",
+		"https://EVIL.example",
+		"https://sub.evil.example:8443/",
+		"https://xn--mnchen-3ya.de/",
+	} {
+		suite.Run(longURL, func() {
+			shortURL, err := suite.manager.CreateShortURL(ctx, longURL, nil)
+			suite.Require().Equal(shorturl.ErrURLDenied, err)
+			suite.Nil(shortURL)
+		})
+	}
+
+	_, _, err := suite.manager.GetOrCreateShortURL(ctx, "https://evil.example")
+	suite.Equal(shorturl.ErrURLDenied, err)
+
+	// Hosts merely ending like a denied host are not its subdomains
+	longURL := "https://notevil.example"
+	expectedId, err := suite.manager.GenerateIdWithOffset(longURL, 0)
+	suite.Require().NoError(err)
+
+	expectedShortURL := &shorturl.ShortURL{Id: expectedId, LongURL: longURL}
+	suite.mockStorage.EXPECT().TryCreateShortURL(ctx, tenant.Default, expectedShortURL).Return(expectedShortURL, false, nil)
+
+	shortURL, err := suite.manager.CreateShortURL(ctx, longURL, nil)
+	suite.Require().NoError(err)
+	suite.Equal(expectedShortURL, shortURL)
+}
+
+func (suite *ManagerSuite) TestCreateShortURLInternationalizedURL() {
+	ctx := context.Background()
+	testCases := []struct {
+		name         string
+		longURL      string
+		canonicalURL string
+	}{
+		{
+			name:         "unicode domain and path",
+			longURL:      "https://münchen.de/bücher",
+			canonicalURL: "https://xn--mnchen-3ya.de/b%C3%BCcher",
+		},
+		{
+			name:         "punycode domain",
+			longURL:      "https://xn--mnchen-3ya.de/b%C3%BCcher",
+			canonicalURL: "https://xn--mnchen-3ya.de/b%C3%BCcher",
+		},
+		{
+			name:         "arabic path and query",
+			longURL:      "https://example.com/مرحبا?q=عربي",
+			canonicalURL: "https://example.com/%D9%85%D8%B1%D8%AD%D8%A8%D8%A7?q=%D8%B9%D8%B1%D8%A8%D9%8A",
+		},
+		{
+			name:         "emoji domain with port",
+			longURL:      "https://😀.ws:8443/",
+			canonicalURL: "https://xn--e28h.ws:8443/",
+		},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			expectedId, err := suite.manager.GenerateIdWithOffset(tc.canonicalURL, 0)
+			suite.Require().NoError(err)
+
+			expectedShortURL := &shorturl.ShortURL{Id: expectedId, LongURL: tc.canonicalURL}
+
+			suite.mockStorage.EXPECT().TryCreateShortURL(ctx, tenant.Default, expectedShortURL).Return(expectedShortURL, false, nil)
+
+			shortURL, err := suite.manager.CreateShortURL(ctx, tc.longURL, nil)
+			suite.Require().NoError(err)
+			suite.Equal(tc.canonicalURL, shortURL.LongURL)
+		})
+	}
+}
+
+func (suite *ManagerSuite) TestCreateShortURLAllowHTTP() {
+	ctx := context.Background()
+	longURL := "http://example.com"
+	suite.config.AllowHTTP = true
+
+	expectedId, err := suite.manager.GenerateIdWithOffset(longURL, 0)
+	suite.Require().NoError(err)
+
+	expectedShortURL := &shorturl.ShortURL{Id: expectedId, LongURL: longURL}
+
+	suite.mockStorage.EXPECT().TryCreateShortURL(ctx, tenant.Default, expectedShortURL).Return(expectedShortURL, false, nil)
+
+	shortURL, err := suite.manager.CreateShortURL(ctx, longURL, nil)
+	suite.Require().NoError(err)
+	suite.Equal(expectedShortURL, shortURL)
+}
+
+func (suite *ManagerSuite) TestCreateShortURLAllowHTTPFailInvalidURL() {
+	ctx := context.Background()
+	suite.config.AllowHTTP = true
+
+	for _, invalidURL := range []string{"ftp://example.com", "http://", "http://exa mple.com"} {
+		suite.Run(invalidURL, func() {
+			shortURL, err := suite.manager.CreateShortURL(ctx, invalidURL, nil)
+			suite.Require().ErrorIs(err, shorturl.ErrInvalidLongURL)
+			suite.Nil(shortURL)
+		})
+	}
+}
+
+func (suite *ManagerSuite) TestCreateShortURLSuccessWithTags() {
+	ctx := context.Background()
+	longURL := "https://example.com"
+	tags := []string{"campaign:summer2025", "team:marketing", "some_tag-1"}
+
+	expectedId, err := suite.manager.GenerateIdWithOffset(longURL, 0)
+	suite.Require().NoError(err)
+
+	expectedShortURL := &shorturl.ShortURL{Id: expectedId, LongURL: longURL, Tags: tags}
+
+	suite.mockStorage.EXPECT().TryCreateShortURL(ctx, tenant.Default, expectedShortURL).Return(expectedShortURL, false, nil)
+
+	shortURL, err := suite.manager.CreateShortURL(ctx, longURL, &shorturl.CreateOptions{Tags: tags})
+	suite.Require().NoError(err)
+	suite.Equal(tags, shortURL.Tags)
+}
+
+func (suite *ManagerSuite) TestCreateShortURLFailInvalidTags() {
+	ctx := context.Background()
+	tooManyTags := make([]string, 21)
+	for i := range tooManyTags {
+		tooManyTags[i] = "tag"
+	}
+
+	testCases := []struct {
+		name string
+		tags []string
+	}{
+		{
+			name: "invalid characters",
+			tags: []string{"campaign summer"},
+		},
+		{
+			name: "empty tag",
+			tags: []string{""},
+		},
+		{
+			name: "tag too long",
+			tags: []string{strings.Repeat("a", 65)},
+		},
+		{
+			name: "too many tags",
+			tags: tooManyTags,
+		},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			shortURL, err := suite.manager.CreateShortURL(ctx, "https://example.com", &shorturl.CreateOptions{Tags: tc.tags})
+			suite.Require().ErrorIs(err, shorturl.ErrInvalidTags)
+			suite.Nil(shortURL)
+		})
+	}
+}
+
+func (suite *ManagerSuite) TestCreateShortURLSuccessMaxClicks() {
+	ctx := context.Background()
+	longURL := "https://example.com"
+
+	expectedId, err := suite.manager.GenerateIdWithOffset(longURL, 0)
+	suite.Require().NoError(err)
+
+	suite.mockStorage.EXPECT().TryCreateShortURL(ctx, tenant.Default, &shorturl.ShortURL{Id: expectedId, LongURL: longURL, MaxClicks: 10}).
+		Return(&shorturl.ShortURL{Id: expectedId, LongURL: longURL, MaxClicks: 10}, false, nil)
+
+	shortURL, err := suite.manager.CreateShortURL(ctx, longURL, &shorturl.CreateOptions{MaxClicks: 10})
+	suite.Require().NoError(err)
+	suite.Equal(10, shortURL.MaxClicks)
+}
+
+func (suite *ManagerSuite) TestCreateShortURLSuccessDescription() {
+	ctx := context.Background()
+	longURL := "https://example.com"
+	// The limit is in characters, not bytes
+	description := strings.Repeat("ü", 512)
+
+	expectedId, err := suite.manager.GenerateIdWithOffset(longURL, 0)
+	suite.Require().NoError(err)
+
+	suite.mockStorage.EXPECT().TryCreateShortURL(ctx, tenant.Default, &shorturl.ShortURL{Id: expectedId, LongURL: longURL, Description: description}).
+		Return(&shorturl.ShortURL{Id: expectedId, LongURL: longURL, Description: description}, false, nil)
+
+	shortURL, err := suite.manager.CreateShortURL(ctx, longURL, &shorturl.CreateOptions{Description: description})
+	suite.Require().NoError(err)
+	suite.Equal(description, shortURL.Description)
+}
+
+func (suite *ManagerSuite) TestCreateShortURLFailDescriptionTooLong() {
+	shortURL, err := suite.manager.CreateShortURL(context.Background(), "https://example.com",
+		&shorturl.CreateOptions{Description: strings.Repeat("a", 513)})
+	suite.Require().ErrorIs(err, shorturl.ErrInvalidDescription)
+	suite.Nil(shortURL)
+}
+
+func (suite *ManagerSuite) TestCreateShortURLFailInvalidMaxClicks() {
+	shortURL, err := suite.manager.CreateShortURL(context.Background(), "https://example.com", &shorturl.CreateOptions{MaxClicks: -1})
+	suite.Require().ErrorIs(err, shorturl.ErrInvalidMaxClicks)
+	suite.Nil(shortURL)
+}
+
+func (suite *ManagerSuite) TestCreateShortURLFailInvalidRedirectCode() {
+	shortURL, err := suite.manager.CreateShortURL(context.Background(), "https://example.com", &shorturl.CreateOptions{RedirectCode: http.StatusOK})
+	suite.Require().ErrorIs(err, shorturl.ErrInvalidRedirectCode)
+	suite.Nil(shortURL)
+}
+
+func (suite *ManagerSuite) TestCreateShortURLFailExpiresAtInThePast() {
+	expiresAt := time.Now().Add(-time.Minute)
+
+	shortURL, err := suite.manager.CreateShortURL(context.Background(), "https://example.com", &shorturl.CreateOptions{ExpiresAt: &expiresAt})
+	suite.Require().ErrorIs(err, shorturl.ErrInvalidExpiresAt)
+	suite.Nil(shortURL)
+}
+
+func (suite *ManagerSuite) TestCreateShortURLSuccessWithCacheTTL() {
+	ctx := context.Background()
+	longURL := "https://example.com"
+
+	expectedId, err := suite.manager.GenerateIdWithOffset(longURL, 0)
+	suite.Require().NoError(err)
+
+	suite.mockStorage.EXPECT().TryCreateShortURL(ctx, tenant.Default, &shorturl.ShortURL{Id: expectedId, LongURL: longURL, CacheTTLSeconds: 300}).
+		Return(&shorturl.ShortURL{Id: expectedId, LongURL: longURL, CacheTTLSeconds: 300}, false, nil)
+
+	shortURL, err := suite.manager.CreateShortURL(ctx, longURL, &shorturl.CreateOptions{CacheTTLSeconds: 300})
+	suite.Require().NoError(err)
+	suite.Equal(300, shortURL.CacheTTLSeconds)
+}
+
+func (suite *ManagerSuite) TestCreateShortURLFailInvalidCacheTTL() {
+	for _, cacheTTLSeconds := range []int{-1, 365*24*60*60 + 1} {
+		shortURL, err := suite.manager.CreateShortURL(context.Background(), "https://example.com", &shorturl.CreateOptions{CacheTTLSeconds: cacheTTLSeconds})
+		suite.Require().ErrorIs(err, shorturl.ErrInvalidCacheTTL)
+		suite.Nil(shortURL)
+	}
+}
+
+func (suite *ManagerSuite) TestStartExpiryCleanupSuccess() {
+	deleted := make(chan struct{}, 1)
+	suite.mockStorage.EXPECT().DeleteExpiredShortURLs(gomock.Any()).
+		DoAndReturn(func(_ context.Context) (map[string][]string, error) {
+			select {
+			case deleted <- struct{}{}:
+			default:
+			}
+
+			return map[string][]string{tenant.Default: {"AABBCC"}}, nil
+		}).MinTimes(1)
+	suite.mockCache.EXPECT().Delete(gomock.Any(), "AABBCC").Return(nil).MinTimes(1)
+
+	stopCleanup := suite.manager.StartExpiryCleanup(context.Background(), 10*time.Millisecond)
 
 	select {
-	case <-done:
+	case <-deleted:
 	case <-time.After(100 * time.Millisecond):
-		suite.Fail("Waiting for cache set timed out")
+		suite.Fail("Waiting for expired short URLs cleanup timed out")
 	}
+
+	stopCleanup()
 }
 
-func (suite *ManagerSuite) TestGetLongURLSuccessCacheHit() {
-	ctx := context.Background()
-	id := "AABBCC"
+func (suite *ManagerSuite) TestStartExpiryCleanupDeletesCachedRedirects() {
+	deleted := make(chan struct{}, 1)
+	suite.mockStorage.EXPECT().DeleteExpiredShortURLs(gomock.Any()).
+		DoAndReturn(func(ctx context.Context) (map[string][]string, error) {
+			suite.Equal("expiry-cleanup", shorturl.ActorFromContext(ctx))
 
-	expectedLongURL := "https://example.com"
+			return map[string][]string{tenant.Default: {"AABBCC"}, "acme": {"DDEEFF"}}, nil
+		}).Times(1)
+	suite.mockStorage.EXPECT().DeleteExpiredShortURLs(gomock.Any()).Return(map[string][]string{}, nil).AnyTimes()
+	// A failed cache delete does not stop the other entries from being removed
+	suite.mockCache.EXPECT().Delete(gomock.Any(), "AABBCC").Return(errors.New("cache error"))
+	suite.mockCache.EXPECT().Delete(gomock.Any(), "acme/DDEEFF").DoAndReturn(func(_ context.Context, _ string) error {
+		deleted <- struct{}{}
 
-	suite.mockCache.EXPECT().Get(ctx, id).Return(expectedLongURL, true, nil)
+		return nil
+	})
 
-	result, err := suite.manager.GetLongURL(ctx, id)
-	suite.Require().NoError(err)
-	suite.Equal(expectedLongURL, result)
+	stopCleanup := suite.manager.StartExpiryCleanup(context.Background(), 10*time.Millisecond)
+
+	select {
+	case <-deleted:
+	case <-time.After(100 * time.Millisecond):
+		suite.Fail("Waiting for expired short URLs cache entries to be deleted timed out")
+	}
+
+	stopCleanup()
 }
 
-func (suite *ManagerSuite) TestGetLongURLFailNotFound() {
-	ctx := context.Background()
-	id := "AABBCC"
+func (suite *ManagerSuite) TestStartExpiryCleanupStopsWhenContextIsDone() {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
 
-	suite.mockCache.EXPECT().Get(ctx, id).Return("", false, nil)
-	suite.mockStorage.EXPECT().GetLongURL(ctx, id).Return("", false, nil)
+	stopCleanup := suite.manager.StartExpiryCleanup(ctx, time.Hour)
 
-	result, err := suite.manager.GetLongURL(ctx, id)
-	suite.Require().ErrorIs(err, shorturl.ErrShortURLNotFound)
-	suite.Zero(result)
+	// The stop function waits for the cleanup goroutine, which must have returned on its own
+	stopped := make(chan struct{})
+	go func() {
+		stopCleanup()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(100 * time.Millisecond):
+		suite.Fail("Waiting for expired short URLs cleanup to stop timed out")
+	}
 }
 
-func (suite *ManagerSuite) TestGetLongURLFailStorageGetLongURLError() {
-	ctx := context.Background()
-	id := "AABBCC"
+func (suite *ManagerSuite) TestConfigValidateExpiryCleanupInterval() {
+	config := shorturl.DefaultConfig()
+	config.ExpiryCleanupIntervalInSeconds = 0
+	suite.Error(config.Validate())
+}
 
-	expectedError := errors.New("some storage error")
+func (suite *ManagerSuite) TestConfigValidateClickRateLimit() {
+	config := shorturl.DefaultConfig()
+	config.ClickRateLimitRPS = -1
+	suite.Error(config.Validate())
 
-	suite.mockCache.EXPECT().Get(ctx, id).Return("", false, nil)
-	suite.mockStorage.EXPECT().GetLongURL(ctx, id).Return("", false, expectedError)
+	config.ClickRateLimitRPS = 10
+	suite.Error(config.Validate())
 
-	result, err := suite.manager.GetLongURL(ctx, id)
-	suite.Require().ErrorIs(err, expectedError)
-	suite.Zero(result)
+	config.ClickRateLimitBurst = 20
+	suite.NoError(config.Validate())
 }
 
-func (suite *ManagerSuite) TestCreateShortURLSuccess() {
-	ctx := context.Background()
-	longURL := "https://example.com"
+func (suite *ManagerSuite) TestConfigValidateMaxLongURLLength() {
+	config := shorturl.DefaultConfig()
+	config.MaxLongURLLength = 0
+	suite.Error(config.Validate())
 
-	expectedId, err := suite.manager.GenerateIdWithOffset(longURL, 0)
-	suite.Require().NoError(err)
+	config.MaxLongURLLength = 2049
+	suite.Error(config.Validate())
 
-	suite.mockStorage.EXPECT().GetLongURL(ctx, expectedId).Return("", false, nil)
-	suite.mockStorage.EXPECT().CreateShortURL(ctx, expectedId, longURL).Return(nil)
+	config.MaxLongURLLength = 2048
+	suite.NoError(config.Validate())
+}
 
-	shortURLId, err := suite.manager.CreateShortURL(ctx, longURL)
-	suite.Require().NoError(err)
-	suite.Equal(expectedId, shortURLId)
+func (suite *ManagerSuite) TestConfigValidateIdSpace() {
+	config := shorturl.DefaultConfig()
+	suite.NoError(config.Validate())
+
+	// 62^6 ids
+	config.SafetyFactor = 1
+	config.ExpectedUniqueURLs = 56_800_235_583
+	suite.NoError(config.Validate())
+
+	config.ExpectedUniqueURLs = 56_800_235_584
+	suite.EqualError(config.Validate(),
+		"with length=6 and base=62, max unique IDs is 56B which is less than expectedUniqueURLs=56B times safetyFactor=1")
+
+	config.ExpectedUniqueURLs = 1_000_000
+	config.SafetyFactor = 0.5
+	suite.Error(config.Validate())
+
+	config.SafetyFactor = 1
+	config.ExpectedUniqueURLs = -1
+	suite.Error(config.Validate())
 }
 
-func (suite *ManagerSuite) TestCreateShortURLSuccessAlreadyExists() {
+func (suite *ManagerSuite) TestValidateIdSpace() {
+	testCases := map[string]struct {
+		length             int
+		safetyFactor       float64
+		expectedUniqueURLs int64
+		err                string
+	}{
+		"min length": {length: 4, safetyFactor: 1, expectedUniqueURLs: 11_316_495},
+		"max length": {length: 12, safetyFactor: 1, expectedUniqueURLs: 1_000_000_000},
+		"too short":  {length: 3, safetyFactor: 1, err: "short URL id length must be between 4 and 12, got 3"},
+		"too long":   {length: 13, safetyFactor: 1, err: "short URL id length must be between 4 and 12, got 13"},
+		"full id space": {length: 4, safetyFactor: 1, expectedUniqueURLs: 11_316_496,
+			err: "with length=4 and base=58, max unique IDs is 11M which is less than expectedUniqueURLs=11M times safetyFactor=1"},
+		"below safety factor": {length: 4, safetyFactor: 5, expectedUniqueURLs: 10_000_000,
+			err: "with length=4 and base=58, max unique IDs is 11M which is less than expectedUniqueURLs=10M times safetyFactor=5"},
+		"no expected short URLs": {length: 4, safetyFactor: 10},
+	}
+
+	for name, testCase := range testCases {
+		suite.Run(name, func() {
+			err := shorturl.ValidateIdSpace(testCase.length, 58, testCase.safetyFactor, testCase.expectedUniqueURLs)
+			if testCase.err == "" {
+				suite.NoError(err)
+
+				return
+			}
+
+			suite.EqualError(err, testCase.err)
+		})
+	}
+}
+
+func (suite *ManagerSuite) TestConfigValidateRedirectCode() {
+	config := shorturl.DefaultConfig()
+	suite.NoError(config.Validate())
+
+	for _, code := range []int{http.StatusMovedPermanently, http.StatusFound, http.StatusTemporaryRedirect} {
+		config.DefaultRedirectCode = code
+		suite.NoError(config.Validate())
+	}
+
+	for _, code := range []int{0, http.StatusOK, http.StatusSeeOther, http.StatusPermanentRedirect} {
+		config.DefaultRedirectCode = code
+		suite.Error(config.Validate())
+	}
+}
+
+func (suite *ManagerSuite) TestCreateShortURLSuccessPasswordHashed() {
 	ctx := context.Background()
 	longURL := "https://example.com"
 
 	expectedId, err := suite.manager.GenerateIdWithOffset(longURL, 0)
 	suite.Require().NoError(err)
 
-	suite.mockStorage.EXPECT().GetLongURL(ctx, expectedId).Return(longURL, true, nil)
+	suite.mockStorage.EXPECT().TryCreateShortURL(ctx, tenant.Default, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, _ string, shortURL *shorturl.ShortURL) (*shorturl.ShortURL, bool, error) {
+			suite.Equal(expectedId, shortURL.Id)
+			suite.NotEqual("hunter2", shortURL.PasswordHash)
+			suite.NoError(bcrypt.CompareHashAndPassword([]byte(shortURL.PasswordHash), []byte("hunter2")))
 
-	shortURLId, err := suite.manager.CreateShortURL(ctx, longURL)
+			return shortURL, false, nil
+		})
+
+	shortURL, err := suite.manager.CreateShortURL(ctx, longURL, &shorturl.CreateOptions{Password: "hunter2"})
 	suite.Require().NoError(err)
-	suite.Equal(expectedId, shortURLId)
+	suite.True(shortURL.Protected())
 }
 
-func (suite *ManagerSuite) TestCreateShortURLSuccessHashCollision() {
+func (suite *ManagerSuite) TestCreateShortURLAlreadyExistsWithOtherOptions() {
 	ctx := context.Background()
 	longURL := "https://example.com"
-	someOtherLongURL := "https://another-example.com"
+	expiresAt := time.Now().Add(time.Hour)
+
+	testCases := []struct {
+		name    string
+		options *shorturl.CreateOptions
+		check   func(*shorturl.ShortURL)
+	}{
+		{
+			name:    "password",
+			options: &shorturl.CreateOptions{Password: "hunter2"},
+			check: func(shortURL *shorturl.ShortURL) {
+				suite.True(shortURL.Protected())
+			},
+		},
+		{
+			name:    "max clicks",
+			options: &shorturl.CreateOptions{MaxClicks: 5},
+			check: func(shortURL *shorturl.ShortURL) {
+				suite.Equal(5, shortURL.MaxClicks)
+			},
+		},
+		{
+			name:    "expires at",
+			options: &shorturl.CreateOptions{ExpiresAt: &expiresAt},
+			check: func(shortURL *shorturl.ShortURL) {
+				suite.Equal(&expiresAt, shortURL.ExpiresAt)
+			},
+		},
+	}
 
 	expectedId0, err := suite.manager.GenerateIdWithOffset(longURL, 0)
 	suite.Require().NoError(err)
-
 	expectedId1, err := suite.manager.GenerateIdWithOffset(longURL, 1)
 	suite.Require().NoError(err)
 
-	suite.mockStorage.EXPECT().GetLongURL(ctx, expectedId0).Return(someOtherLongURL, true, nil)
-	suite.mockStorage.EXPECT().GetLongURL(ctx, expectedId1).Return("", false, nil)
-	suite.mockStorage.EXPECT().CreateShortURL(ctx, expectedId1, longURL).Return(nil)
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			// The existing short URL of the long URL has no options, a new one is created for the options asked for
+			gomock.InOrder(
+				suite.mockStorage.EXPECT().TryCreateShortURL(ctx, tenant.Default, gomock.Any()).Return(nil, true, nil),
+				suite.mockStorage.EXPECT().GetShortURL(ctx, tenant.Default, expectedId0).
+					Return(&shorturl.ShortURL{Id: expectedId0, LongURL: longURL}, true, nil),
+				suite.mockStorage.EXPECT().TryCreateShortURL(ctx, tenant.Default, gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ string, shortURL *shorturl.ShortURL) (*shorturl.ShortURL, bool, error) {
+						suite.Equal(expectedId1, shortURL.Id)
+
+						return shortURL, false, nil
+					}),
+			)
+
+			shortURL, err := suite.manager.CreateShortURL(ctx, longURL, tc.options)
+			suite.Require().NoError(err)
+			suite.Equal(expectedId1, shortURL.Id)
+			tc.check(shortURL)
+		})
+	}
+}
+
+func (suite *ManagerSuite) TestCreateShortURLAlreadyExistsWithSamePassword() {
+	ctx := context.Background()
+	longURL := "https://example.com"
+
+	expectedId, err := suite.manager.GenerateIdWithOffset(longURL, 0)
+	suite.Require().NoError(err)
+	hash, err := bcrypt.GenerateFromPassword([]byte("hunter2"), bcrypt.MinCost)
+	suite.Require().NoError(err)
+
+	suite.mockStorage.EXPECT().TryCreateShortURL(ctx, tenant.Default, gomock.Any()).Return(nil, true, nil)
+	suite.mockStorage.EXPECT().GetShortURL(ctx, tenant.Default, expectedId).
+		Return(&shorturl.ShortURL{Id: expectedId, LongURL: longURL, PasswordHash: string(hash)}, true, nil)
 
-	shortURLId, err := suite.manager.CreateShortURL(ctx, longURL)
+	shortURL, err := suite.manager.CreateShortURL(ctx, longURL, &shorturl.CreateOptions{Password: "hunter2"})
 	suite.Require().NoError(err)
-	suite.Equal(expectedId1, shortURLId)
+	suite.Equal(expectedId, shortURL.Id)
+	suite.True(shortURL.Protected())
 }
 
-func (suite *ManagerSuite) TestCreateShortURLFailInvalidURL() {
+func (suite *ManagerSuite) TestCheckShortURL() {
 	ctx := context.Background()
+	id := "AABBCC"
+	past := time.Now().Add(-time.Minute)
+	future := time.Now().Add(time.Hour)
+
 	testCases := []struct {
-		name       string
-		invalidURL string
+		name        string
+		shortURL    *shorturl.ShortURL
+		expectedErr error
 	}{
-		{
-			name:       "invalid URL prefix",
-			invalidURL: "htp://example.com",
-		},
-		{
-			name:       "empty URL",
-			invalidURL: "",
-		},
+		{name: "active", shortURL: &shorturl.ShortURL{Id: id, Status: shorturl.StatusActive}},
+		{name: "protected", shortURL: &shorturl.ShortURL{Id: id, Status: shorturl.StatusActive, PasswordHash: "$2a$10$hash"}},
+		{name: "not expired", shortURL: &shorturl.ShortURL{Id: id, Status: shorturl.StatusActive, ExpiresAt: &future}},
+		{name: "clicks left", shortURL: &shorturl.ShortURL{Id: id, Status: shorturl.StatusActive, MaxClicks: 5, ClickCount: 4}},
+		{name: "expired", shortURL: &shorturl.ShortURL{Id: id, Status: shorturl.StatusActive, ExpiresAt: &past},
+			expectedErr: shorturl.ErrShortURLExpired},
+		{name: "click limit exceeded", shortURL: &shorturl.ShortURL{Id: id, Status: shorturl.StatusActive, MaxClicks: 5, ClickCount: 5},
+			expectedErr: shorturl.ErrClickLimitExceeded},
+		{name: "paused", shortURL: &shorturl.ShortURL{Id: id, Status: shorturl.StatusPaused}, expectedErr: shorturl.ErrShortURLPaused},
+		{name: "archived", shortURL: &shorturl.ShortURL{Id: id, Status: shorturl.StatusArchived}, expectedErr: shorturl.ErrShortURLArchived},
 	}
 
 	for _, tc := range testCases {
 		suite.Run(tc.name, func() {
-			shortURLId, err := suite.manager.CreateShortURL(ctx, tc.invalidURL)
-			suite.Require().ErrorIs(err, shorturl.ErrInvalidLongURL)
-			suite.Zero(shortURLId)
+			// Clicks are never counted, the storage GetLongURL is not called
+			suite.mockStorage.EXPECT().GetShortURL(ctx, tenant.Default, id).Return(tc.shortURL, true, nil)
+
+			err := suite.manager.CheckShortURL(ctx, id)
+			if tc.expectedErr != nil {
+				suite.ErrorIs(err, tc.expectedErr)
+			} else {
+				suite.NoError(err)
+			}
 		})
-
 	}
 }
 
+func (suite *ManagerSuite) TestCheckShortURLAlias() {
+	ctx := context.Background()
+
+	suite.mockStorage.EXPECT().GetShortURL(ctx, tenant.Default, "DDEEFF").Return(nil, false, nil)
+	suite.mockStorage.EXPECT().GetLongURLByAlias(ctx, tenant.Default, "DDEEFF").Return("AABBCC", "https://example.com", true, nil)
+	suite.mockStorage.EXPECT().GetShortURL(ctx, tenant.Default, "AABBCC").
+		Return(&shorturl.ShortURL{Id: "AABBCC", Status: shorturl.StatusArchived}, true, nil)
+
+	suite.ErrorIs(suite.manager.CheckShortURL(ctx, "DDEEFF"), shorturl.ErrShortURLArchived)
+}
+
+func (suite *ManagerSuite) TestCheckShortURLFailNotFound() {
+	ctx := context.Background()
+
+	suite.mockStorage.EXPECT().GetShortURL(ctx, tenant.Default, "AABBCC").Return(nil, false, nil)
+	suite.mockStorage.EXPECT().GetLongURLByAlias(ctx, tenant.Default, "AABBCC").Return("", "", false, nil)
+
+	suite.ErrorIs(suite.manager.CheckShortURL(ctx, "AABBCC"), shorturl.ErrShortURLNotFound)
+}
+
+func (suite *ManagerSuite) TestCheckPassword() {
+	ctx := context.Background()
+	id := "AABBCC"
+
+	hash, err := bcrypt.GenerateFromPassword([]byte("hunter2"), bcrypt.MinCost)
+	suite.Require().NoError(err)
+
+	suite.mockStorage.EXPECT().GetShortURL(ctx, tenant.Default, id).
+		Return(&shorturl.ShortURL{Id: id, LongURL: "https://example.com", PasswordHash: string(hash)}, true, nil).Times(2)
+
+	suite.NoError(suite.manager.CheckPassword(ctx, id, "hunter2"))
+	suite.ErrorIs(suite.manager.CheckPassword(ctx, id, "wrong"), shorturl.ErrInvalidPassword)
+}
+
+func (suite *ManagerSuite) TestCheckPasswordFailNotProtected() {
+	ctx := context.Background()
+	id := "AABBCC"
+
+	suite.mockStorage.EXPECT().GetShortURL(ctx, tenant.Default, id).Return(&shorturl.ShortURL{Id: id, LongURL: "https://example.com"}, true, nil)
+
+	suite.ErrorIs(suite.manager.CheckPassword(ctx, id, "hunter2"), shorturl.ErrNotProtected)
+}
+
 func (suite *ManagerSuite) TestCreateShortURlFailMaxHashCollisions() {
 	ctx := context.Background()
 	longURL := "https://example.com"
@@ -205,15 +1686,16 @@ func (suite *ManagerSuite) TestCreateShortURlFailMaxHashCollisions() {
 		expectedId, err := suite.manager.GenerateIdWithOffset(longURL, uint(i))
 		suite.Require().NoError(err)
 
-		suite.mockStorage.EXPECT().GetLongURL(ctx, expectedId).Return(someOtherLongURL, true, nil)
+		suite.mockStorage.EXPECT().TryCreateShortURL(ctx, tenant.Default, &shorturl.ShortURL{Id: expectedId, LongURL: longURL}).Return(nil, true, nil)
+		suite.mockStorage.EXPECT().GetShortURL(ctx, tenant.Default, expectedId).Return(&shorturl.ShortURL{Id: expectedId, LongURL: someOtherLongURL}, true, nil)
 	}
 
-	shortURLId, err := suite.manager.CreateShortURL(ctx, longURL)
+	shortURL, err := suite.manager.CreateShortURL(ctx, longURL, nil)
 	suite.Require().ErrorContains(err, "failed to generate unique short URL")
-	suite.Zero(shortURLId)
+	suite.Nil(shortURL)
 }
 
-func (suite *ManagerSuite) TestCreateShortURLFailStorageGetLongURLError() {
+func (suite *ManagerSuite) TestCreateShortURLFailStorageGetShortURLError() {
 	ctx := context.Background()
 	longURL := "https://example.com"
 
@@ -221,34 +1703,65 @@ func (suite *ManagerSuite) TestCreateShortURLFailStorageGetLongURLError() {
 	expectedId, err := suite.manager.GenerateIdWithOffset(longURL, 0)
 	suite.Require().NoError(err)
 
-	suite.mockStorage.EXPECT().GetLongURL(ctx, expectedId).Return("", false, expectedError)
+	suite.mockStorage.EXPECT().TryCreateShortURL(ctx, tenant.Default, &shorturl.ShortURL{Id: expectedId, LongURL: longURL}).Return(nil, true, nil)
+	suite.mockStorage.EXPECT().GetShortURL(ctx, tenant.Default, expectedId).Return(nil, false, expectedError)
 
-	shortURLId, err := suite.manager.CreateShortURL(ctx, longURL)
+	shortURL, err := suite.manager.CreateShortURL(ctx, longURL, nil)
 	suite.Require().ErrorIs(err, expectedError)
-	suite.Zero(shortURLId)
+	suite.Nil(shortURL)
 }
 
 func (suite *ManagerSuite) TestCreateShortURLFailStorageCreateShortURLError() {
 	ctx := context.Background()
 	longURL := "https://example.com"
 
+	// Creating is not retried, a short URL committed before the error would be found as an existing one
 	expectedError := errors.New("some storage error")
+	suite.config.MaxStorageRetries = 2
 	expectedId, err := suite.manager.GenerateIdWithOffset(longURL, 0)
 	suite.Require().NoError(err)
 
-	suite.mockStorage.EXPECT().GetLongURL(ctx, expectedId).Return("", false, nil)
-	suite.mockStorage.EXPECT().CreateShortURL(ctx, expectedId, longURL).Return(expectedError)
+	suite.mockStorage.EXPECT().TryCreateShortURL(ctx, tenant.Default, &shorturl.ShortURL{Id: expectedId, LongURL: longURL}).Return(nil, false, expectedError)
 
-	shortURLId, err := suite.manager.CreateShortURL(ctx, longURL)
+	shortURL, err := suite.manager.CreateShortURL(ctx, longURL, nil)
 	suite.Require().ErrorIs(err, expectedError)
-	suite.Zero(shortURLId)
+	suite.Nil(shortURL)
+}
+
+func (suite *ManagerSuite) TestGetAuditLogSuccess() {
+	ctx := context.Background()
+	filter := &shorturl.AuditFilter{ShortURLId: "AABBCC", Limit: 10}
+	expectedEntries := []*shorturl.AuditEntry{{Id: 1, Operation: shorturl.OperationCreate, ShortURLId: "AABBCC"}}
+
+	suite.mockStorage.EXPECT().GetAuditLog(ctx, tenant.Default, filter).Return(expectedEntries, nil)
+
+	entries, err := suite.manager.GetAuditLog(ctx, filter)
+	suite.Require().NoError(err)
+	suite.Equal(expectedEntries, entries)
+}
+
+func (suite *ManagerSuite) TestActorContext() {
+	suite.Empty(shorturl.ActorFromContext(context.Background()))
+	suite.Equal("jane@example.com", shorturl.ActorFromContext(shorturl.WithActor(context.Background(), "jane@example.com")))
 }
 
 func (suite *ManagerSuite) TestDeleteShortURLSuccess() {
 	ctx := context.Background()
 	id := "AABBCC"
 
-	suite.mockStorage.EXPECT().DeleteShortURL(ctx, id).Return(nil)
+	suite.mockStorage.EXPECT().DeleteShortURL(ctx, tenant.Default, id).Return(nil)
+	suite.mockCache.EXPECT().Delete(ctx, id).Return(nil)
+
+	err := suite.manager.DeleteShortURL(ctx, id)
+	suite.Require().NoError(err)
+}
+
+func (suite *ManagerSuite) TestDeleteShortURLSuccessCacheMiss() {
+	ctx := context.Background()
+	id := "AABBCC"
+
+	// The redirect was never cached or its entry already expired, the cache deletes nothing
+	suite.mockStorage.EXPECT().DeleteShortURL(ctx, tenant.Default, id).Return(nil)
 	suite.mockCache.EXPECT().Delete(ctx, id).Return(nil)
 
 	err := suite.manager.DeleteShortURL(ctx, id)
@@ -260,7 +1773,7 @@ func (suite *ManagerSuite) TestDeleteShortURLFailStorageDeleteShortURLError() {
 	id := "AABBCC"
 
 	expectedError := errors.New("some storage error")
-	suite.mockStorage.EXPECT().DeleteShortURL(ctx, id).Return(expectedError)
+	suite.mockStorage.EXPECT().DeleteShortURL(ctx, tenant.Default, id).Return(expectedError)
 
 	err := suite.manager.DeleteShortURL(ctx, id)
 	suite.Require().ErrorIs(err, expectedError)
@@ -271,9 +1784,327 @@ func (suite *ManagerSuite) TestDeleteShortURLFailCacheDeleteError() {
 	id := "AABBCC"
 
 	expectedError := errors.New("some cache error")
-	suite.mockStorage.EXPECT().DeleteShortURL(ctx, id).Return(nil)
+	suite.mockStorage.EXPECT().DeleteShortURL(ctx, tenant.Default, id).Return(nil)
 	suite.mockCache.EXPECT().Delete(ctx, id).Return(expectedError)
 
 	err := suite.manager.DeleteShortURL(ctx, id)
 	suite.Require().ErrorIs(err, expectedError)
 }
+
+func (suite *ManagerSuite) TestListShortURLsByOwnerSuccess() {
+	shortURLs := []*shorturl.ShortURL{{Id: "AABBCC", CreatedBy: "user-1"}}
+
+	testCases := map[string]context.Context{
+		"owner": user.WithID(context.Background(), "user-1"),
+		"admin": user.WithRole(user.WithID(context.Background(), "user-2"), user.RoleAdmin),
+	}
+
+	for name, ctx := range testCases {
+		suite.Run(name, func() {
+			suite.mockStorage.EXPECT().ListShortURLsByOwner(ctx, tenant.Default, "user-1", 10, 20).Return(shortURLs, nil)
+
+			listed, err := suite.manager.ListShortURLsByOwner(ctx, "user-1", 10, 20)
+			suite.Require().NoError(err)
+			suite.Equal(shortURLs, listed)
+		})
+	}
+}
+
+func (suite *ManagerSuite) TestListShortURLsByOwnerFailNotOwner() {
+	testCases := map[string]context.Context{
+		"anonymous":  context.Background(),
+		"other user": user.WithID(context.Background(), "user-2"),
+		"other role": user.WithRole(user.WithID(context.Background(), "user-2"), "viewer"),
+	}
+
+	for name, ctx := range testCases {
+		suite.Run(name, func() {
+			_, err := suite.manager.ListShortURLsByOwner(ctx, "user-1", 10, 0)
+			suite.ErrorIs(err, shorturl.ErrNotOwner)
+		})
+	}
+}
+
+func (suite *ManagerSuite) TestDeleteShortURLsByOwnerSuccess() {
+	ctx := user.WithID(tenant.WithID(context.Background(), "acme"), "user-1")
+
+	suite.mockStorage.EXPECT().DeleteShortURLsByOwner(ctx, "acme", "user-1").Return([]string{"AABBCC", "DDEEFF"}, nil)
+	suite.mockCache.EXPECT().Delete(ctx, "acme/AABBCC").Return(nil)
+	suite.mockCache.EXPECT().Delete(ctx, "acme/DDEEFF").Return(nil)
+
+	suite.Require().NoError(suite.manager.DeleteShortURLsByOwner(ctx, "user-1"))
+}
+
+func (suite *ManagerSuite) TestDeleteShortURLsByOwnerFailCacheDeleteError() {
+	ctx := user.WithID(context.Background(), "user-1")
+
+	// Every cached redirect is deleted even after a failure
+	expectedError := errors.New("some cache error")
+	suite.mockStorage.EXPECT().DeleteShortURLsByOwner(ctx, tenant.Default, "user-1").Return([]string{"AABBCC", "DDEEFF"}, nil)
+	suite.mockCache.EXPECT().Delete(ctx, "AABBCC").Return(expectedError)
+	suite.mockCache.EXPECT().Delete(ctx, "DDEEFF").Return(nil)
+
+	suite.ErrorIs(suite.manager.DeleteShortURLsByOwner(ctx, "user-1"), expectedError)
+}
+
+func (suite *ManagerSuite) TestDeleteShortURLsByOwnerFailNotOwner() {
+	ctx := user.WithID(context.Background(), "user-2")
+
+	suite.ErrorIs(suite.manager.DeleteShortURLsByOwner(ctx, "user-1"), shorturl.ErrNotOwner)
+}
+
+// streamShortURLs returns a storage StreamShortURLs mock implementation sending the given short URLs followed by err
+func streamShortURLs(err error, shortURLs ...*shorturl.ShortURL) func(context.Context, string, *shorturl.ListFilter) (<-chan *shorturl.ShortURL, <-chan error) {
+	return func(context.Context, string, *shorturl.ListFilter) (<-chan *shorturl.ShortURL, <-chan error) {
+		streamed := make(chan *shorturl.ShortURL, len(shortURLs))
+		errs := make(chan error, 1)
+		for _, shortURL := range shortURLs {
+			streamed <- shortURL
+		}
+		if err != nil {
+			errs <- err
+		}
+		close(streamed)
+		close(errs)
+
+		return streamed, errs
+	}
+}
+
+func (suite *ManagerSuite) TestStreamShortURLs() {
+	ctx := context.Background()
+	filter := &shorturl.ListFilter{Tags: []string{"campaign"}}
+	shortURLs := []*shorturl.ShortURL{{Id: "AABBCC"}, {Id: "DDEEFF"}}
+
+	suite.mockStorage.EXPECT().StreamShortURLs(ctx, tenant.Default, filter).DoAndReturn(streamShortURLs(nil, shortURLs...))
+	streamed, errs := suite.manager.StreamShortURLs(ctx, filter)
+	var received []*shorturl.ShortURL
+	for shortURL := range streamed {
+		received = append(received, shortURL)
+	}
+	suite.Require().NoError(<-errs)
+	suite.Equal(shortURLs, received)
+
+	storageErr := errors.New("storage error")
+	suite.mockStorage.EXPECT().StreamShortURLs(ctx, tenant.Default, filter).DoAndReturn(streamShortURLs(storageErr, shortURLs[0]))
+	streamed, errs = suite.manager.StreamShortURLs(ctx, filter)
+	for range streamed {
+	}
+	suite.ErrorIs(<-errs, storageErr)
+
+	// A stream cancelled by its caller is not reported as failed
+	cancelledCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	suite.mockStorage.EXPECT().StreamShortURLs(cancelledCtx, tenant.Default, filter).DoAndReturn(streamShortURLs(context.Canceled))
+	streamed, errs = suite.manager.StreamShortURLs(cancelledCtx, filter)
+	for range streamed {
+	}
+	suite.NoError(<-errs)
+}
+
+func (suite *ManagerSuite) TestGetDashboardStats() {
+	ctx := context.Background()
+	from := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 7)
+	stats := &shorturl.DashboardStats{
+		From:             from,
+		To:               to,
+		ShortURLsCreated: 3,
+		Visits:           10,
+		UniqueVisits:     4,
+		TopByVisits:      []*shorturl.ShortURLStats{{ShortURLId: "AABBCC", LongURL: "https://example.com", Visits: 10, UniqueVisits: 4}},
+	}
+	key := fmt.Sprintf("dashboard:%s:%d:%d", tenant.Default, from.Unix(), to.Unix())
+
+	var cached string
+	suite.mockCache.EXPECT().Get(ctx, key).Return("", false, nil)
+	suite.mockStorage.EXPECT().GetDashboardStats(ctx, tenant.Default, from, to).Return(stats, nil)
+	suite.mockCache.EXPECT().Set(ctx, key, gomock.Any(), time.Minute).
+		DoAndReturn(func(_ context.Context, _ string, value string, _ time.Duration) error {
+			cached = value
+
+			return nil
+		})
+
+	result, err := suite.manager.GetDashboardStats(ctx, from, to)
+	suite.Require().NoError(err)
+	suite.Equal(stats, result)
+
+	// Cached stats are not read from storage again
+	suite.mockCache.EXPECT().Get(ctx, key).Return(cached, true, nil)
+
+	result, err = suite.manager.GetDashboardStats(ctx, from, to)
+	suite.Require().NoError(err)
+	suite.Equal(stats, result)
+}
+
+func (suite *ManagerSuite) TestGetDashboardStatsFailStorageError() {
+	ctx := context.Background()
+	storageErr := errors.New("storage error")
+
+	suite.mockCache.EXPECT().Get(ctx, gomock.Any()).Return("", false, nil)
+	suite.mockStorage.EXPECT().GetDashboardStats(ctx, tenant.Default, gomock.Any(), gomock.Any()).Return(nil, storageErr).AnyTimes()
+
+	_, err := suite.manager.GetDashboardStats(ctx, time.Now().Add(-time.Hour), time.Now())
+	suite.ErrorIs(err, storageErr)
+}
+
+func (suite *ManagerSuite) TestGenerateIdWithOffsetDistinctIds() {
+	for _, strategy := range []string{shorturl.CollisionStrategyQuadratic, shorturl.CollisionStrategyLinear, shorturl.CollisionStrategyDoubleHash} {
+		suite.Run(strategy, func() {
+			config := *suite.config
+			config.CollisionStrategy = strategy
+			manager, err := shorturl.NewManager(&config, suite.mockStorage, suite.mockCache, suite.mockLogger)
+			suite.Require().NoError(err)
+
+			ids := make(map[string]bool)
+			for offset := range uint(1000) {
+				id, err := manager.GenerateIdWithOffset("https://example.com", offset)
+				suite.Require().NoError(err)
+				suite.Len(id, 6)
+				suite.False(ids[id], "offset %d repeats id %s", offset, id)
+				ids[id] = true
+			}
+		})
+	}
+}
+
+func (suite *ManagerSuite) TestNewManagerFailZeroConfig() {
+	_, err := shorturl.NewManager(&shorturl.Config{}, suite.mockStorage, suite.mockCache, suite.mockLogger)
+	suite.ErrorContains(err, "MaxShortURLIdRetries")
+}
+
+func (suite *ManagerSuite) TestNewManagerFailUnknownCollisionStrategy() {
+	config := *suite.config
+	config.CollisionStrategy = "cuckoo"
+
+	_, err := shorturl.NewManager(&config, suite.mockStorage, suite.mockCache, suite.mockLogger)
+	suite.Error(err)
+	suite.Error(config.Validate())
+}
+
+// BenchmarkCollisionStrategies measures the ids probed to create a short URL once 1M short URLs are stored
+func BenchmarkCollisionStrategies(b *testing.B) {
+	const stored = 1_000_000
+
+	for _, strategy := range []string{shorturl.CollisionStrategyQuadratic, shorturl.CollisionStrategyLinear, shorturl.CollisionStrategyDoubleHash} {
+		b.Run(strategy, func(b *testing.B) {
+			config := shorturl.DefaultConfig()
+			config.CollisionStrategy = strategy
+			manager, err := shorturl.NewManager(config, mocks.NewMockStorage(nil), mocks.NewMockCache(nil), nil)
+			if err != nil {
+				b.Fatal(err)
+			}
+
+			ids := make(map[string]bool, stored)
+			create := func(longURL string) int {
+				for offset := uint(0); ; offset++ {
+					id, err := manager.GenerateIdWithOffset(longURL, offset)
+					if err != nil {
+						b.Fatal(err)
+					}
+					if !ids[id] {
+						ids[id] = true
+
+						return int(offset) + 1
+					}
+				}
+			}
+
+			for i := range stored {
+				create(fmt.Sprintf("https://example.com/%d", i))
+			}
+
+			probes := 0
+			i := 0
+			for b.Loop() {
+				probes += create(fmt.Sprintf("https://example.org/%d", i))
+				i++
+			}
+			b.ReportMetric(float64(probes)/float64(b.N), "probes/op")
+		})
+	}
+}
+
+func (suite *ManagerSuite) TestStats() {
+	ctx := context.Background()
+	longURL := "https://example.com"
+
+	expectedId0, err := suite.manager.GenerateIdWithOffset(longURL, 0)
+	suite.Require().NoError(err)
+
+	expectedId1, err := suite.manager.GenerateIdWithOffset(longURL, 1)
+	suite.Require().NoError(err)
+
+	suite.mockStorage.EXPECT().TryCreateShortURL(ctx, tenant.Default, &shorturl.ShortURL{Id: expectedId0, LongURL: longURL}).Return(nil, true, nil)
+	suite.mockStorage.EXPECT().GetShortURL(ctx, tenant.Default, expectedId0).Return(&shorturl.ShortURL{Id: expectedId0, LongURL: "https://another-example.com"}, true, nil)
+	suite.mockStorage.EXPECT().TryCreateShortURL(ctx, tenant.Default, &shorturl.ShortURL{Id: expectedId1, LongURL: longURL}).Return(&shorturl.ShortURL{Id: expectedId1, LongURL: longURL}, false, nil)
+
+	_, err = suite.manager.CreateShortURL(ctx, longURL, nil)
+	suite.Require().NoError(err)
+
+	suite.mockCache.EXPECT().Get(ctx, "AABBCC").Return(longURL, true, nil).Times(2)
+	suite.mockCache.EXPECT().Get(ctx, "DDEEFF").Return("", false, nil)
+	suite.mockStorage.EXPECT().GetLongURL(ctx, tenant.Default, "DDEEFF").Return(nil, false, nil)
+	suite.mockStorage.EXPECT().GetLongURLByAlias(ctx, tenant.Default, "DDEEFF").Return("", "", false, nil)
+
+	for range 2 {
+		_, err = suite.manager.GetLongURL(ctx, "AABBCC")
+		suite.Require().NoError(err)
+	}
+	_, err = suite.manager.GetLongURL(ctx, "DDEEFF")
+	suite.ErrorIs(err, shorturl.ErrShortURLNotFound)
+
+	suite.Equal(shorturl.ManagerStats{CollisionCount: 1, CreateCount: 1, CollisionRate: 1, HitCount: 2, MissCount: 1}, suite.manager.Stats())
+
+	suite.manager.ResetStats()
+	suite.Equal(shorturl.ManagerStats{}, suite.manager.Stats())
+}
+
+func (suite *ManagerSuite) TestStatsCollisionRateAlert() {
+	ctx := context.Background()
+	config := *suite.config
+	config.CollisionRateAlertThreshold = 0.5
+
+	// Only the first collision above the threshold is warned about
+	mockLogger := mocks.NewMockLogger(suite.mockCtrl)
+	mockLogger.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+	mockLogger.EXPECT().Warn("short URL id collision rate above threshold", gomock.Any()).Times(1)
+
+	manager, err := shorturl.NewManager(&config, suite.mockStorage, suite.mockCache, mockLogger)
+	suite.Require().NoError(err)
+
+	firstLongURL := "https://example.com/first"
+	firstId, err := manager.GenerateIdWithOffset(firstLongURL, 0)
+	suite.Require().NoError(err)
+	suite.mockStorage.EXPECT().TryCreateShortURL(ctx, tenant.Default, &shorturl.ShortURL{Id: firstId, LongURL: firstLongURL}).
+		Return(&shorturl.ShortURL{Id: firstId, LongURL: firstLongURL}, false, nil)
+
+	_, err = manager.CreateShortURL(ctx, firstLongURL, nil)
+	suite.Require().NoError(err)
+	suite.Zero(manager.Stats().CollisionRate)
+
+	// The first two ids of the second long URL are taken
+	longURL := "https://example.com/second"
+	for offset := range 3 {
+		id, err := manager.GenerateIdWithOffset(longURL, uint(offset))
+		suite.Require().NoError(err)
+
+		if offset < 2 {
+			suite.mockStorage.EXPECT().TryCreateShortURL(ctx, tenant.Default, &shorturl.ShortURL{Id: id, LongURL: longURL}).Return(nil, true, nil)
+			suite.mockStorage.EXPECT().GetShortURL(ctx, tenant.Default, id).Return(&shorturl.ShortURL{Id: id, LongURL: "https://example.com/other"}, true, nil)
+
+			continue
+		}
+		suite.mockStorage.EXPECT().TryCreateShortURL(ctx, tenant.Default, &shorturl.ShortURL{Id: id, LongURL: longURL}).
+			Return(&shorturl.ShortURL{Id: id, LongURL: longURL}, false, nil)
+	}
+
+	_, err = manager.CreateShortURL(ctx, longURL, nil)
+	suite.Require().NoError(err)
+
+	stats := manager.Stats()
+	suite.Equal(uint64(2), stats.CollisionCount)
+	suite.Equal(uint64(2), stats.CreateCount)
+	suite.InDelta(1, stats.CollisionRate, 0)
+}
@@ -14,6 +14,7 @@ import (
 	reflect "reflect"
 	time "time"
 
+	shorturl "github.com/AvalosM/short-url-service/pkg/shorturl"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -41,56 +42,134 @@ func (m *MockStorage) EXPECT() *MockStorageMockRecorder {
 	return m.recorder
 }
 
-// CreateShortURL mocks base method.
-func (m *MockStorage) CreateShortURL(ctx context.Context, id, longURL string) error {
+// CreateAlias mocks base method.
+func (m *MockStorage) CreateAlias(ctx context.Context, tenantID, aliasID, canonicalID string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateShortURL", ctx, id, longURL)
+	ret := m.ctrl.Call(m, "CreateAlias", ctx, tenantID, aliasID, canonicalID)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// CreateShortURL indicates an expected call of CreateShortURL.
-func (mr *MockStorageMockRecorder) CreateShortURL(ctx, id, longURL any) *MockStorageCreateShortURLCall {
+// CreateAlias indicates an expected call of CreateAlias.
+func (mr *MockStorageMockRecorder) CreateAlias(ctx, tenantID, aliasID, canonicalID any) *MockStorageCreateAliasCall {
 	mr.mock.ctrl.T.Helper()
-	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateShortURL", reflect.TypeOf((*MockStorage)(nil).CreateShortURL), ctx, id, longURL)
-	return &MockStorageCreateShortURLCall{Call: call}
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAlias", reflect.TypeOf((*MockStorage)(nil).CreateAlias), ctx, tenantID, aliasID, canonicalID)
+	return &MockStorageCreateAliasCall{Call: call}
 }
 
-// MockStorageCreateShortURLCall wrap *gomock.Call
-type MockStorageCreateShortURLCall struct {
+// MockStorageCreateAliasCall wrap *gomock.Call
+type MockStorageCreateAliasCall struct {
 	*gomock.Call
 }
 
 // Return rewrite *gomock.Call.Return
-func (c *MockStorageCreateShortURLCall) Return(arg0 error) *MockStorageCreateShortURLCall {
+func (c *MockStorageCreateAliasCall) Return(arg0 error) *MockStorageCreateAliasCall {
 	c.Call = c.Call.Return(arg0)
 	return c
 }
 
 // Do rewrite *gomock.Call.Do
-func (c *MockStorageCreateShortURLCall) Do(f func(context.Context, string, string) error) *MockStorageCreateShortURLCall {
+func (c *MockStorageCreateAliasCall) Do(f func(context.Context, string, string, string) error) *MockStorageCreateAliasCall {
 	c.Call = c.Call.Do(f)
 	return c
 }
 
 // DoAndReturn rewrite *gomock.Call.DoAndReturn
-func (c *MockStorageCreateShortURLCall) DoAndReturn(f func(context.Context, string, string) error) *MockStorageCreateShortURLCall {
+func (c *MockStorageCreateAliasCall) DoAndReturn(f func(context.Context, string, string, string) error) *MockStorageCreateAliasCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// DeleteAlias mocks base method.
+func (m *MockStorage) DeleteAlias(ctx context.Context, tenantID, canonicalID, aliasID string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAlias", ctx, tenantID, canonicalID, aliasID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteAlias indicates an expected call of DeleteAlias.
+func (mr *MockStorageMockRecorder) DeleteAlias(ctx, tenantID, canonicalID, aliasID any) *MockStorageDeleteAliasCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAlias", reflect.TypeOf((*MockStorage)(nil).DeleteAlias), ctx, tenantID, canonicalID, aliasID)
+	return &MockStorageDeleteAliasCall{Call: call}
+}
+
+// MockStorageDeleteAliasCall wrap *gomock.Call
+type MockStorageDeleteAliasCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockStorageDeleteAliasCall) Return(arg0 bool, arg1 error) *MockStorageDeleteAliasCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockStorageDeleteAliasCall) Do(f func(context.Context, string, string, string) (bool, error)) *MockStorageDeleteAliasCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockStorageDeleteAliasCall) DoAndReturn(f func(context.Context, string, string, string) (bool, error)) *MockStorageDeleteAliasCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// DeleteExpiredShortURLs mocks base method.
+func (m *MockStorage) DeleteExpiredShortURLs(ctx context.Context) (map[string][]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredShortURLs", ctx)
+	ret0, _ := ret[0].(map[string][]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpiredShortURLs indicates an expected call of DeleteExpiredShortURLs.
+func (mr *MockStorageMockRecorder) DeleteExpiredShortURLs(ctx any) *MockStorageDeleteExpiredShortURLsCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredShortURLs", reflect.TypeOf((*MockStorage)(nil).DeleteExpiredShortURLs), ctx)
+	return &MockStorageDeleteExpiredShortURLsCall{Call: call}
+}
+
+// MockStorageDeleteExpiredShortURLsCall wrap *gomock.Call
+type MockStorageDeleteExpiredShortURLsCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockStorageDeleteExpiredShortURLsCall) Return(arg0 map[string][]string, arg1 error) *MockStorageDeleteExpiredShortURLsCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockStorageDeleteExpiredShortURLsCall) Do(f func(context.Context) (map[string][]string, error)) *MockStorageDeleteExpiredShortURLsCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockStorageDeleteExpiredShortURLsCall) DoAndReturn(f func(context.Context) (map[string][]string, error)) *MockStorageDeleteExpiredShortURLsCall {
 	c.Call = c.Call.DoAndReturn(f)
 	return c
 }
 
 // DeleteShortURL mocks base method.
-func (m *MockStorage) DeleteShortURL(ctx context.Context, id string) error {
+func (m *MockStorage) DeleteShortURL(ctx context.Context, tenantID, id string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteShortURL", ctx, id)
+	ret := m.ctrl.Call(m, "DeleteShortURL", ctx, tenantID, id)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteShortURL indicates an expected call of DeleteShortURL.
-func (mr *MockStorageMockRecorder) DeleteShortURL(ctx, id any) *MockStorageDeleteShortURLCall {
+func (mr *MockStorageMockRecorder) DeleteShortURL(ctx, tenantID, id any) *MockStorageDeleteShortURLCall {
 	mr.mock.ctrl.T.Helper()
-	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteShortURL", reflect.TypeOf((*MockStorage)(nil).DeleteShortURL), ctx, id)
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteShortURL", reflect.TypeOf((*MockStorage)(nil).DeleteShortURL), ctx, tenantID, id)
 	return &MockStorageDeleteShortURLCall{Call: call}
 }
 
@@ -106,31 +185,148 @@ func (c *MockStorageDeleteShortURLCall) Return(arg0 error) *MockStorageDeleteSho
 }
 
 // Do rewrite *gomock.Call.Do
-func (c *MockStorageDeleteShortURLCall) Do(f func(context.Context, string) error) *MockStorageDeleteShortURLCall {
+func (c *MockStorageDeleteShortURLCall) Do(f func(context.Context, string, string) error) *MockStorageDeleteShortURLCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockStorageDeleteShortURLCall) DoAndReturn(f func(context.Context, string, string) error) *MockStorageDeleteShortURLCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// DeleteShortURLsByOwner mocks base method.
+func (m *MockStorage) DeleteShortURLsByOwner(ctx context.Context, tenantID, userID string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteShortURLsByOwner", ctx, tenantID, userID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteShortURLsByOwner indicates an expected call of DeleteShortURLsByOwner.
+func (mr *MockStorageMockRecorder) DeleteShortURLsByOwner(ctx, tenantID, userID any) *MockStorageDeleteShortURLsByOwnerCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteShortURLsByOwner", reflect.TypeOf((*MockStorage)(nil).DeleteShortURLsByOwner), ctx, tenantID, userID)
+	return &MockStorageDeleteShortURLsByOwnerCall{Call: call}
+}
+
+// MockStorageDeleteShortURLsByOwnerCall wrap *gomock.Call
+type MockStorageDeleteShortURLsByOwnerCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockStorageDeleteShortURLsByOwnerCall) Return(arg0 []string, arg1 error) *MockStorageDeleteShortURLsByOwnerCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockStorageDeleteShortURLsByOwnerCall) Do(f func(context.Context, string, string) ([]string, error)) *MockStorageDeleteShortURLsByOwnerCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockStorageDeleteShortURLsByOwnerCall) DoAndReturn(f func(context.Context, string, string) ([]string, error)) *MockStorageDeleteShortURLsByOwnerCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// GetAuditLog mocks base method.
+func (m *MockStorage) GetAuditLog(ctx context.Context, tenantID string, filter *shorturl.AuditFilter) ([]*shorturl.AuditEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuditLog", ctx, tenantID, filter)
+	ret0, _ := ret[0].([]*shorturl.AuditEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuditLog indicates an expected call of GetAuditLog.
+func (mr *MockStorageMockRecorder) GetAuditLog(ctx, tenantID, filter any) *MockStorageGetAuditLogCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuditLog", reflect.TypeOf((*MockStorage)(nil).GetAuditLog), ctx, tenantID, filter)
+	return &MockStorageGetAuditLogCall{Call: call}
+}
+
+// MockStorageGetAuditLogCall wrap *gomock.Call
+type MockStorageGetAuditLogCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockStorageGetAuditLogCall) Return(arg0 []*shorturl.AuditEntry, arg1 error) *MockStorageGetAuditLogCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockStorageGetAuditLogCall) Do(f func(context.Context, string, *shorturl.AuditFilter) ([]*shorturl.AuditEntry, error)) *MockStorageGetAuditLogCall {
 	c.Call = c.Call.Do(f)
 	return c
 }
 
 // DoAndReturn rewrite *gomock.Call.DoAndReturn
-func (c *MockStorageDeleteShortURLCall) DoAndReturn(f func(context.Context, string) error) *MockStorageDeleteShortURLCall {
+func (c *MockStorageGetAuditLogCall) DoAndReturn(f func(context.Context, string, *shorturl.AuditFilter) ([]*shorturl.AuditEntry, error)) *MockStorageGetAuditLogCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// GetDashboardStats mocks base method.
+func (m *MockStorage) GetDashboardStats(ctx context.Context, tenantID string, from, to time.Time) (*shorturl.DashboardStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDashboardStats", ctx, tenantID, from, to)
+	ret0, _ := ret[0].(*shorturl.DashboardStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDashboardStats indicates an expected call of GetDashboardStats.
+func (mr *MockStorageMockRecorder) GetDashboardStats(ctx, tenantID, from, to any) *MockStorageGetDashboardStatsCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDashboardStats", reflect.TypeOf((*MockStorage)(nil).GetDashboardStats), ctx, tenantID, from, to)
+	return &MockStorageGetDashboardStatsCall{Call: call}
+}
+
+// MockStorageGetDashboardStatsCall wrap *gomock.Call
+type MockStorageGetDashboardStatsCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockStorageGetDashboardStatsCall) Return(arg0 *shorturl.DashboardStats, arg1 error) *MockStorageGetDashboardStatsCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockStorageGetDashboardStatsCall) Do(f func(context.Context, string, time.Time, time.Time) (*shorturl.DashboardStats, error)) *MockStorageGetDashboardStatsCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockStorageGetDashboardStatsCall) DoAndReturn(f func(context.Context, string, time.Time, time.Time) (*shorturl.DashboardStats, error)) *MockStorageGetDashboardStatsCall {
 	c.Call = c.Call.DoAndReturn(f)
 	return c
 }
 
 // GetLongURL mocks base method.
-func (m *MockStorage) GetLongURL(ctx context.Context, id string) (string, bool, error) {
+func (m *MockStorage) GetLongURL(ctx context.Context, tenantID, id string) (*shorturl.ShortURL, bool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetLongURL", ctx, id)
-	ret0, _ := ret[0].(string)
+	ret := m.ctrl.Call(m, "GetLongURL", ctx, tenantID, id)
+	ret0, _ := ret[0].(*shorturl.ShortURL)
 	ret1, _ := ret[1].(bool)
 	ret2, _ := ret[2].(error)
 	return ret0, ret1, ret2
 }
 
 // GetLongURL indicates an expected call of GetLongURL.
-func (mr *MockStorageMockRecorder) GetLongURL(ctx, id any) *MockStorageGetLongURLCall {
+func (mr *MockStorageMockRecorder) GetLongURL(ctx, tenantID, id any) *MockStorageGetLongURLCall {
 	mr.mock.ctrl.T.Helper()
-	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLongURL", reflect.TypeOf((*MockStorage)(nil).GetLongURL), ctx, id)
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLongURL", reflect.TypeOf((*MockStorage)(nil).GetLongURL), ctx, tenantID, id)
 	return &MockStorageGetLongURLCall{Call: call}
 }
 
@@ -140,19 +336,374 @@ type MockStorageGetLongURLCall struct {
 }
 
 // Return rewrite *gomock.Call.Return
-func (c *MockStorageGetLongURLCall) Return(arg0 string, arg1 bool, arg2 error) *MockStorageGetLongURLCall {
+func (c *MockStorageGetLongURLCall) Return(arg0 *shorturl.ShortURL, arg1 bool, arg2 error) *MockStorageGetLongURLCall {
 	c.Call = c.Call.Return(arg0, arg1, arg2)
 	return c
 }
 
 // Do rewrite *gomock.Call.Do
-func (c *MockStorageGetLongURLCall) Do(f func(context.Context, string) (string, bool, error)) *MockStorageGetLongURLCall {
+func (c *MockStorageGetLongURLCall) Do(f func(context.Context, string, string) (*shorturl.ShortURL, bool, error)) *MockStorageGetLongURLCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockStorageGetLongURLCall) DoAndReturn(f func(context.Context, string, string) (*shorturl.ShortURL, bool, error)) *MockStorageGetLongURLCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// GetLongURLByAlias mocks base method.
+func (m *MockStorage) GetLongURLByAlias(ctx context.Context, tenantID, aliasID string) (string, string, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLongURLByAlias", ctx, tenantID, aliasID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(bool)
+	ret3, _ := ret[3].(error)
+	return ret0, ret1, ret2, ret3
+}
+
+// GetLongURLByAlias indicates an expected call of GetLongURLByAlias.
+func (mr *MockStorageMockRecorder) GetLongURLByAlias(ctx, tenantID, aliasID any) *MockStorageGetLongURLByAliasCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLongURLByAlias", reflect.TypeOf((*MockStorage)(nil).GetLongURLByAlias), ctx, tenantID, aliasID)
+	return &MockStorageGetLongURLByAliasCall{Call: call}
+}
+
+// MockStorageGetLongURLByAliasCall wrap *gomock.Call
+type MockStorageGetLongURLByAliasCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockStorageGetLongURLByAliasCall) Return(arg0, arg1 string, arg2 bool, arg3 error) *MockStorageGetLongURLByAliasCall {
+	c.Call = c.Call.Return(arg0, arg1, arg2, arg3)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockStorageGetLongURLByAliasCall) Do(f func(context.Context, string, string) (string, string, bool, error)) *MockStorageGetLongURLByAliasCall {
 	c.Call = c.Call.Do(f)
 	return c
 }
 
 // DoAndReturn rewrite *gomock.Call.DoAndReturn
-func (c *MockStorageGetLongURLCall) DoAndReturn(f func(context.Context, string) (string, bool, error)) *MockStorageGetLongURLCall {
+func (c *MockStorageGetLongURLByAliasCall) DoAndReturn(f func(context.Context, string, string) (string, string, bool, error)) *MockStorageGetLongURLByAliasCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// GetMostVisitedShortURLIds mocks base method.
+func (m *MockStorage) GetMostVisitedShortURLIds(ctx context.Context, tenantID string, since time.Time, limit int) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMostVisitedShortURLIds", ctx, tenantID, since, limit)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMostVisitedShortURLIds indicates an expected call of GetMostVisitedShortURLIds.
+func (mr *MockStorageMockRecorder) GetMostVisitedShortURLIds(ctx, tenantID, since, limit any) *MockStorageGetMostVisitedShortURLIdsCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMostVisitedShortURLIds", reflect.TypeOf((*MockStorage)(nil).GetMostVisitedShortURLIds), ctx, tenantID, since, limit)
+	return &MockStorageGetMostVisitedShortURLIdsCall{Call: call}
+}
+
+// MockStorageGetMostVisitedShortURLIdsCall wrap *gomock.Call
+type MockStorageGetMostVisitedShortURLIdsCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockStorageGetMostVisitedShortURLIdsCall) Return(arg0 []string, arg1 error) *MockStorageGetMostVisitedShortURLIdsCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockStorageGetMostVisitedShortURLIdsCall) Do(f func(context.Context, string, time.Time, int) ([]string, error)) *MockStorageGetMostVisitedShortURLIdsCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockStorageGetMostVisitedShortURLIdsCall) DoAndReturn(f func(context.Context, string, time.Time, int) ([]string, error)) *MockStorageGetMostVisitedShortURLIdsCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// GetShortURL mocks base method.
+func (m *MockStorage) GetShortURL(ctx context.Context, tenantID, id string) (*shorturl.ShortURL, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetShortURL", ctx, tenantID, id)
+	ret0, _ := ret[0].(*shorturl.ShortURL)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetShortURL indicates an expected call of GetShortURL.
+func (mr *MockStorageMockRecorder) GetShortURL(ctx, tenantID, id any) *MockStorageGetShortURLCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetShortURL", reflect.TypeOf((*MockStorage)(nil).GetShortURL), ctx, tenantID, id)
+	return &MockStorageGetShortURLCall{Call: call}
+}
+
+// MockStorageGetShortURLCall wrap *gomock.Call
+type MockStorageGetShortURLCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockStorageGetShortURLCall) Return(arg0 *shorturl.ShortURL, arg1 bool, arg2 error) *MockStorageGetShortURLCall {
+	c.Call = c.Call.Return(arg0, arg1, arg2)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockStorageGetShortURLCall) Do(f func(context.Context, string, string) (*shorturl.ShortURL, bool, error)) *MockStorageGetShortURLCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockStorageGetShortURLCall) DoAndReturn(f func(context.Context, string, string) (*shorturl.ShortURL, bool, error)) *MockStorageGetShortURLCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// GetShortURLs mocks base method.
+func (m *MockStorage) GetShortURLs(ctx context.Context, tenantID string, ids []string) ([]*shorturl.ShortURL, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetShortURLs", ctx, tenantID, ids)
+	ret0, _ := ret[0].([]*shorturl.ShortURL)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetShortURLs indicates an expected call of GetShortURLs.
+func (mr *MockStorageMockRecorder) GetShortURLs(ctx, tenantID, ids any) *MockStorageGetShortURLsCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetShortURLs", reflect.TypeOf((*MockStorage)(nil).GetShortURLs), ctx, tenantID, ids)
+	return &MockStorageGetShortURLsCall{Call: call}
+}
+
+// MockStorageGetShortURLsCall wrap *gomock.Call
+type MockStorageGetShortURLsCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockStorageGetShortURLsCall) Return(arg0 []*shorturl.ShortURL, arg1 error) *MockStorageGetShortURLsCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockStorageGetShortURLsCall) Do(f func(context.Context, string, []string) ([]*shorturl.ShortURL, error)) *MockStorageGetShortURLsCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockStorageGetShortURLsCall) DoAndReturn(f func(context.Context, string, []string) ([]*shorturl.ShortURL, error)) *MockStorageGetShortURLsCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// ListShortURLs mocks base method.
+func (m *MockStorage) ListShortURLs(ctx context.Context, tenantID string, filter *shorturl.ListFilter) ([]*shorturl.ShortURL, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListShortURLs", ctx, tenantID, filter)
+	ret0, _ := ret[0].([]*shorturl.ShortURL)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListShortURLs indicates an expected call of ListShortURLs.
+func (mr *MockStorageMockRecorder) ListShortURLs(ctx, tenantID, filter any) *MockStorageListShortURLsCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListShortURLs", reflect.TypeOf((*MockStorage)(nil).ListShortURLs), ctx, tenantID, filter)
+	return &MockStorageListShortURLsCall{Call: call}
+}
+
+// MockStorageListShortURLsCall wrap *gomock.Call
+type MockStorageListShortURLsCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockStorageListShortURLsCall) Return(arg0 []*shorturl.ShortURL, arg1 error) *MockStorageListShortURLsCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockStorageListShortURLsCall) Do(f func(context.Context, string, *shorturl.ListFilter) ([]*shorturl.ShortURL, error)) *MockStorageListShortURLsCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockStorageListShortURLsCall) DoAndReturn(f func(context.Context, string, *shorturl.ListFilter) ([]*shorturl.ShortURL, error)) *MockStorageListShortURLsCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// ListShortURLsByOwner mocks base method.
+func (m *MockStorage) ListShortURLsByOwner(ctx context.Context, tenantID, userID string, limit, offset int) ([]*shorturl.ShortURL, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListShortURLsByOwner", ctx, tenantID, userID, limit, offset)
+	ret0, _ := ret[0].([]*shorturl.ShortURL)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListShortURLsByOwner indicates an expected call of ListShortURLsByOwner.
+func (mr *MockStorageMockRecorder) ListShortURLsByOwner(ctx, tenantID, userID, limit, offset any) *MockStorageListShortURLsByOwnerCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListShortURLsByOwner", reflect.TypeOf((*MockStorage)(nil).ListShortURLsByOwner), ctx, tenantID, userID, limit, offset)
+	return &MockStorageListShortURLsByOwnerCall{Call: call}
+}
+
+// MockStorageListShortURLsByOwnerCall wrap *gomock.Call
+type MockStorageListShortURLsByOwnerCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockStorageListShortURLsByOwnerCall) Return(arg0 []*shorturl.ShortURL, arg1 error) *MockStorageListShortURLsByOwnerCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockStorageListShortURLsByOwnerCall) Do(f func(context.Context, string, string, int, int) ([]*shorturl.ShortURL, error)) *MockStorageListShortURLsByOwnerCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockStorageListShortURLsByOwnerCall) DoAndReturn(f func(context.Context, string, string, int, int) ([]*shorturl.ShortURL, error)) *MockStorageListShortURLsByOwnerCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// StreamShortURLs mocks base method.
+func (m *MockStorage) StreamShortURLs(ctx context.Context, tenantID string, filter *shorturl.ListFilter) (<-chan *shorturl.ShortURL, <-chan error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamShortURLs", ctx, tenantID, filter)
+	ret0, _ := ret[0].(<-chan *shorturl.ShortURL)
+	ret1, _ := ret[1].(<-chan error)
+	return ret0, ret1
+}
+
+// StreamShortURLs indicates an expected call of StreamShortURLs.
+func (mr *MockStorageMockRecorder) StreamShortURLs(ctx, tenantID, filter any) *MockStorageStreamShortURLsCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamShortURLs", reflect.TypeOf((*MockStorage)(nil).StreamShortURLs), ctx, tenantID, filter)
+	return &MockStorageStreamShortURLsCall{Call: call}
+}
+
+// MockStorageStreamShortURLsCall wrap *gomock.Call
+type MockStorageStreamShortURLsCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockStorageStreamShortURLsCall) Return(arg0 <-chan *shorturl.ShortURL, arg1 <-chan error) *MockStorageStreamShortURLsCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockStorageStreamShortURLsCall) Do(f func(context.Context, string, *shorturl.ListFilter) (<-chan *shorturl.ShortURL, <-chan error)) *MockStorageStreamShortURLsCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockStorageStreamShortURLsCall) DoAndReturn(f func(context.Context, string, *shorturl.ListFilter) (<-chan *shorturl.ShortURL, <-chan error)) *MockStorageStreamShortURLsCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// TryCreateShortURL mocks base method.
+func (m *MockStorage) TryCreateShortURL(ctx context.Context, tenantID string, shortURL *shorturl.ShortURL) (*shorturl.ShortURL, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TryCreateShortURL", ctx, tenantID, shortURL)
+	ret0, _ := ret[0].(*shorturl.ShortURL)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// TryCreateShortURL indicates an expected call of TryCreateShortURL.
+func (mr *MockStorageMockRecorder) TryCreateShortURL(ctx, tenantID, shortURL any) *MockStorageTryCreateShortURLCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TryCreateShortURL", reflect.TypeOf((*MockStorage)(nil).TryCreateShortURL), ctx, tenantID, shortURL)
+	return &MockStorageTryCreateShortURLCall{Call: call}
+}
+
+// MockStorageTryCreateShortURLCall wrap *gomock.Call
+type MockStorageTryCreateShortURLCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockStorageTryCreateShortURLCall) Return(arg0 *shorturl.ShortURL, arg1 bool, arg2 error) *MockStorageTryCreateShortURLCall {
+	c.Call = c.Call.Return(arg0, arg1, arg2)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockStorageTryCreateShortURLCall) Do(f func(context.Context, string, *shorturl.ShortURL) (*shorturl.ShortURL, bool, error)) *MockStorageTryCreateShortURLCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockStorageTryCreateShortURLCall) DoAndReturn(f func(context.Context, string, *shorturl.ShortURL) (*shorturl.ShortURL, bool, error)) *MockStorageTryCreateShortURLCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// UpdateShortURLStatus mocks base method.
+func (m *MockStorage) UpdateShortURLStatus(ctx context.Context, tenantID, id string, from, to shorturl.Status) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateShortURLStatus", ctx, tenantID, id, from, to)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateShortURLStatus indicates an expected call of UpdateShortURLStatus.
+func (mr *MockStorageMockRecorder) UpdateShortURLStatus(ctx, tenantID, id, from, to any) *MockStorageUpdateShortURLStatusCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateShortURLStatus", reflect.TypeOf((*MockStorage)(nil).UpdateShortURLStatus), ctx, tenantID, id, from, to)
+	return &MockStorageUpdateShortURLStatusCall{Call: call}
+}
+
+// MockStorageUpdateShortURLStatusCall wrap *gomock.Call
+type MockStorageUpdateShortURLStatusCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockStorageUpdateShortURLStatusCall) Return(arg0 bool, arg1 error) *MockStorageUpdateShortURLStatusCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockStorageUpdateShortURLStatusCall) Do(f func(context.Context, string, string, shorturl.Status, shorturl.Status) (bool, error)) *MockStorageUpdateShortURLStatusCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockStorageUpdateShortURLStatusCall) DoAndReturn(f func(context.Context, string, string, shorturl.Status, shorturl.Status) (bool, error)) *MockStorageUpdateShortURLStatusCall {
 	c.Call = c.Call.DoAndReturn(f)
 	return c
 }
@@ -259,6 +810,45 @@ func (c *MockCacheGetCall) DoAndReturn(f func(context.Context, string) (string,
 	return c
 }
 
+// GetMulti mocks base method.
+func (m *MockCache) GetMulti(ctx context.Context, keys []string) (map[string]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMulti", ctx, keys)
+	ret0, _ := ret[0].(map[string]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMulti indicates an expected call of GetMulti.
+func (mr *MockCacheMockRecorder) GetMulti(ctx, keys any) *MockCacheGetMultiCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMulti", reflect.TypeOf((*MockCache)(nil).GetMulti), ctx, keys)
+	return &MockCacheGetMultiCall{Call: call}
+}
+
+// MockCacheGetMultiCall wrap *gomock.Call
+type MockCacheGetMultiCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockCacheGetMultiCall) Return(arg0 map[string]string, arg1 error) *MockCacheGetMultiCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockCacheGetMultiCall) Do(f func(context.Context, []string) (map[string]string, error)) *MockCacheGetMultiCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockCacheGetMultiCall) DoAndReturn(f func(context.Context, []string) (map[string]string, error)) *MockCacheGetMultiCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // Set mocks base method.
 func (m *MockCache) Set(ctx context.Context, key, value string, duration time.Duration) error {
 	m.ctrl.T.Helper()
@@ -297,6 +887,44 @@ func (c *MockCacheSetCall) DoAndReturn(f func(context.Context, string, string, t
 	return c
 }
 
+// SetMulti mocks base method.
+func (m *MockCache) SetMulti(ctx context.Context, entries map[string]string, duration time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetMulti", ctx, entries, duration)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetMulti indicates an expected call of SetMulti.
+func (mr *MockCacheMockRecorder) SetMulti(ctx, entries, duration any) *MockCacheSetMultiCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetMulti", reflect.TypeOf((*MockCache)(nil).SetMulti), ctx, entries, duration)
+	return &MockCacheSetMultiCall{Call: call}
+}
+
+// MockCacheSetMultiCall wrap *gomock.Call
+type MockCacheSetMultiCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockCacheSetMultiCall) Return(arg0 error) *MockCacheSetMultiCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockCacheSetMultiCall) Do(f func(context.Context, map[string]string, time.Duration) error) *MockCacheSetMultiCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockCacheSetMultiCall) DoAndReturn(f func(context.Context, map[string]string, time.Duration) error) *MockCacheSetMultiCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // MockLogger is a mock of Logger interface.
 type MockLogger struct {
 	ctrl     *gomock.Controller
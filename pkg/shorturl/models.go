@@ -0,0 +1,158 @@
+package shorturl
+
+import (
+	"slices"
+	"time"
+)
+
+// ShortURL is a short URL id and the long URL it points to
+type ShortURL struct {
+	TenantId string
+	Id       string
+	LongURL  string
+	Tags     []string
+	// Description is a human readable note about the short URL for operators
+	Description string
+	// MaxClicks is the number of redirects allowed before the short URL is deactivated, 0 means no limit
+	MaxClicks  int
+	ClickCount int
+	// PasswordHash is the bcrypt hash of the password protecting the redirect, empty if it is not protected
+	PasswordHash string
+	// RedirectCode is the HTTP status of the redirect, 0 means the manager DefaultRedirectCode
+	RedirectCode int
+	// ForwardQueryParams appends the query parameters of the short URL request to the long URL on redirect
+	ForwardQueryParams bool
+	// ExpiresAt is when the short URL expires, nil if it never does
+	ExpiresAt *time.Time
+	// CacheTTLSeconds is how long the redirect is cached, 0 means the manager ShortURLCacheTTLInSeconds
+	CacheTTLSeconds int
+	// ShowInterstitial shows a page with the long URL that redirects after a countdown instead of redirecting at once
+	ShowInterstitial bool
+	// CreatedBy is the id of the user who created the short URL, empty if it was not created on behalf of a user
+	CreatedBy string
+	// Status is the lifecycle state of the short URL, only active short URLs redirect
+	Status    Status
+	CreatedAt time.Time
+	// UpdatedAt is when the short URL was last changed, cached redirects expire at most a year after it
+	UpdatedAt time.Time
+}
+
+// Protected reports whether a password is required to follow the short URL redirect
+func (s *ShortURL) Protected() bool {
+	return s.PasswordHash != ""
+}
+
+// CheckAvailable reports whether the short URL would redirect at now, ErrShortURLPaused, ErrShortURLArchived,
+// ErrShortURLExpired or ErrClickLimitExceeded is returned if it would not
+func (s *ShortURL) CheckAvailable(now time.Time) error {
+	switch {
+	case s.Status == StatusPaused:
+		return ErrShortURLPaused
+	case s.Status == StatusArchived:
+		return ErrShortURLArchived
+	case s.ExpiresAt != nil && !s.ExpiresAt.After(now):
+		return ErrShortURLExpired
+	case s.MaxClicks > 0 && s.ClickCount >= s.MaxClicks:
+		return ErrClickLimitExceeded
+	}
+
+	return nil
+}
+
+// Status is the lifecycle state of a short URL
+type Status string
+
+const (
+	// StatusActive short URLs redirect, it is the status of new short URLs
+	StatusActive Status = "active"
+	// StatusPaused short URLs are temporarily disabled until they are resumed
+	StatusPaused Status = "paused"
+	// StatusArchived short URLs are permanently disabled
+	StatusArchived Status = "archived"
+)
+
+// statusTransitions are the statuses a short URL can be moved to from each status, archiving is final
+var statusTransitions = map[Status][]Status{
+	StatusActive: {StatusPaused, StatusArchived},
+	StatusPaused: {StatusActive, StatusArchived},
+}
+
+// CanTransitionTo reports whether a short URL with status s can be moved to status to
+func (s Status) CanTransitionTo(to Status) bool {
+	return slices.Contains(statusTransitions[s], to)
+}
+
+// CreateOptions holds the optional attributes of a new short URL
+type CreateOptions struct {
+	Tags []string
+	// Description is at most 512 characters long
+	Description string
+	MaxClicks   int
+	// Password protects the redirect when not empty, only its hash is stored
+	Password string
+	// RedirectCode is one of 301, 302 or 307, 0 uses the manager DefaultRedirectCode
+	RedirectCode       int
+	ForwardQueryParams bool
+	// ExpiresAt must be in the future, nil creates a short URL that never expires
+	ExpiresAt *time.Time
+	// CacheTTLSeconds is 1 second to 1 year, 0 uses the manager ShortURLCacheTTLInSeconds
+	CacheTTLSeconds  int
+	ShowInterstitial bool
+}
+
+// BulkEntry is a long URL to be shortened by CreateShortURLBulk
+type BulkEntry struct {
+	LongURL string
+	// Options can be nil
+	Options *CreateOptions
+	// AliasId is created as an alias of the short URL when not empty
+	AliasId string
+}
+
+// BulkResult is the outcome of shortening a BulkEntry, ShortURL is set even when only creating the alias failed
+type BulkResult struct {
+	ShortURL *ShortURL
+	Err      error
+}
+
+// ShortURLResult is the long URL a short URL redirects to and the HTTP status of the redirect. ShortURLId is the id
+// of the short URL followed, which is the canonical one when it was reached through an alias.
+type ShortURLResult struct {
+	ShortURLId         string
+	LongURL            string
+	RedirectCode       int
+	ForwardQueryParams bool
+	ExpiresAt          *time.Time
+	ShowInterstitial   bool
+}
+
+// ListFilter filters and paginates short URL listings
+type ListFilter struct {
+	Tag    string
+	Limit  int
+	Offset int
+	// Tags only matches short URLs having all of them
+	Tags []string
+}
+
+// DashboardStats summarizes the short URLs of a tenant and their redirects within a time range
+type DashboardStats struct {
+	From time.Time
+	To   time.Time
+	// ShortURLsCreated counts the short URLs created within the range that are not deleted
+	ShortURLsCreated int64
+	Visits           int64
+	// UniqueVisits sums the unique visitors of every metrics interval, a visitor returning in a later interval is
+	// counted again
+	UniqueVisits      int64
+	TopByVisits       []*ShortURLStats
+	TopByUniqueVisits []*ShortURLStats
+}
+
+// ShortURLStats are the visits of a short URL within a time range
+type ShortURLStats struct {
+	ShortURLId   string
+	LongURL      string
+	Visits       int64
+	UniqueVisits int64
+}
@@ -0,0 +1,94 @@
+package shorturl
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/AvalosM/short-url-service/pkg/logging"
+)
+
+// clickLimiterIdleTimeout is how long the click rate limiter of a short URL is kept without being used
+const clickLimiterIdleTimeout = 10 * time.Minute
+
+// clickLimiter is the click rate limiter of a single short URL
+type clickLimiter struct {
+	limiter *rate.Limiter
+	// lastUsed is the unix nano time the limiter was last consulted
+	lastUsed atomic.Int64
+}
+
+// clickLimiters rate limits the clicks of each short URL separately, limiters are created on first use
+type clickLimiters struct {
+	limit    rate.Limit
+	burst    int
+	limiters sync.Map
+}
+
+func newClickLimiters(rps int, burst int) *clickLimiters {
+	return &clickLimiters{limit: rate.Limit(rps), burst: burst}
+}
+
+// allow reports whether a click of the short URL with the given key is within its rate limit
+func (l *clickLimiters) allow(key string, now time.Time) bool {
+	value, ok := l.limiters.Load(key)
+	if !ok {
+		value, _ = l.limiters.LoadOrStore(key, &clickLimiter{limiter: rate.NewLimiter(l.limit, l.burst)})
+	}
+
+	limiter := value.(*clickLimiter)
+	limiter.lastUsed.Store(now.UnixNano())
+
+	return limiter.limiter.AllowN(now, 1)
+}
+
+// evictIdle removes the limiters not used since before idleSince and returns how many were removed
+func (l *clickLimiters) evictIdle(idleSince time.Time) int {
+	evicted := 0
+	l.limiters.Range(func(key, value any) bool {
+		if value.(*clickLimiter).lastUsed.Load() < idleSince.UnixNano() {
+			l.limiters.CompareAndDelete(key, value)
+			evicted++
+		}
+
+		return true
+	})
+
+	return evicted
+}
+
+// StartClickLimiterEviction removes the click rate limiters of short URLs idle for 10 minutes every interval until
+// ctx is done or the returned stop function is called. It does nothing when click rate limiting is disabled.
+func (m *Manager) StartClickLimiterEviction(ctx context.Context, interval time.Duration) func() {
+	if m.clickLimiters == nil {
+		return func() {}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case now := <-ticker.C:
+				evicted := m.clickLimiters.evictIdle(now.Add(-clickLimiterIdleTimeout))
+				m.log(ctx).Debug("evicted idle click rate limiters", logging.DeletedKey, evicted)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return func() {
+		cancel()
+		<-done
+	}
+}
@@ -0,0 +1,82 @@
+package shorturl
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/AvalosM/short-url-service/pkg/logging"
+)
+
+// ManagerStats are the counters of a running manager since it was created or its stats were last reset
+type ManagerStats struct {
+	// CollisionCount is the number of generated ids taken by the short URL of another long URL
+	CollisionCount uint64
+	// CreateCount is the number of short URLs created
+	CreateCount uint64
+	// CollisionRate is CollisionCount divided by CreateCount, 0 until a short URL is created
+	CollisionRate float64
+	// HitCount is the number of long URLs served from the cache
+	HitCount uint64
+	// MissCount is the number of long URLs looked up in storage because they were not cached
+	MissCount uint64
+}
+
+// managerCounters back the stats of a manager, they are updated concurrently by the requests it serves
+type managerCounters struct {
+	collisions atomic.Uint64
+	creates    atomic.Uint64
+	hits       atomic.Uint64
+	misses     atomic.Uint64
+	// collisionAlert is set while the collision rate is above CollisionRateAlertThreshold, so crossing it is only
+	// warned about once
+	collisionAlert atomic.Bool
+}
+
+// Stats returns the counters of the manager
+func (m *Manager) Stats() ManagerStats {
+	collisions, creates := m.counters.collisions.Load(), m.counters.creates.Load()
+
+	return ManagerStats{
+		CollisionCount: collisions,
+		CreateCount:    creates,
+		CollisionRate:  collisionRate(collisions, creates),
+		HitCount:       m.counters.hits.Load(),
+		MissCount:      m.counters.misses.Load(),
+	}
+}
+
+// ResetStats sets every counter of the manager back to zero
+func (m *Manager) ResetStats() {
+	m.counters.collisions.Store(0)
+	m.counters.creates.Store(0)
+	m.counters.hits.Store(0)
+	m.counters.misses.Store(0)
+	m.counters.collisionAlert.Store(false)
+}
+
+// recordCollision counts a collision and warns when it takes the collision rate above CollisionRateAlertThreshold
+func (m *Manager) recordCollision(ctx context.Context) {
+	collisions := m.counters.collisions.Add(1)
+	creates := m.counters.creates.Load()
+	if m.config.CollisionRateAlertThreshold <= 0 || creates == 0 {
+		return
+	}
+
+	rate := collisionRate(collisions, creates)
+	if rate <= m.config.CollisionRateAlertThreshold {
+		m.counters.collisionAlert.Store(false)
+
+		return
+	}
+	if !m.counters.collisionAlert.Swap(true) {
+		m.log(ctx).Warn("short URL id collision rate above threshold", logging.CollisionRateKey, rate)
+	}
+}
+
+func collisionRate(collisions, creates uint64) float64 {
+	if creates == 0 {
+		return 0
+	}
+
+	return float64(collisions) / float64(creates)
+}
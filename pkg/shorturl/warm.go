@@ -0,0 +1,118 @@
+package shorturl
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/AvalosM/short-url-service/pkg/logging"
+	"github.com/AvalosM/short-url-service/pkg/tenant"
+)
+
+// warmCacheVisitsWindow is how far back the visits of short URLs are counted to find the most visited ones
+const warmCacheVisitsWindow = 7 * 24 * time.Hour
+
+// WarmCache caches the redirects of the short URLs of the tenant of ctx with the given ids, so their first clicks
+// are served from the cache. Short URLs are read from storage and cached in batches of WarmCacheBatchSize. Short URLs
+// not found or that would not be cached by a redirect, like inactive or password protected ones, are skipped.
+func (m *Manager) WarmCache(ctx context.Context, ids []string) error {
+	tenantID := tenant.IDFromContext(ctx)
+
+	ids = slices.Clone(ids)
+	slices.Sort(ids)
+	ids = slices.Compact(ids)
+
+	warmed := 0
+	for batch := range slices.Chunk(ids, m.config.WarmCacheBatchSize) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		var shortURLs []*ShortURL
+		err := m.retryStorage(ctx, func() error {
+			var err error
+			shortURLs, err = m.storage.GetShortURLs(ctx, tenantID, batch)
+
+			return err
+		})
+		if err != nil {
+			m.log(ctx).Error("failed to get short URLs from storage", logging.ErrorKey, err)
+
+			return fmt.Errorf("failed to get short URLs from storage: %w", err)
+		}
+
+		n, err := m.cacheShortURLs(ctx, tenantID, shortURLs)
+		if err != nil {
+			m.log(ctx).Error("failed to set long URLs in cache", logging.ErrorKey, err)
+
+			return fmt.Errorf("failed to set long URLs in cache: %w", err)
+		}
+		warmed += n
+	}
+
+	m.log(ctx).Info("warmed short URL cache", logging.WarmedKey, warmed)
+
+	return nil
+}
+
+// WarmCacheMostVisited caches the redirects of the WarmCacheTopN short URLs of the tenant of ctx with the most visits
+// within the last week, like WarmCache
+func (m *Manager) WarmCacheMostVisited(ctx context.Context) error {
+	var ids []string
+	err := m.retryStorage(ctx, func() error {
+		var err error
+		ids, err = m.storage.GetMostVisitedShortURLIds(ctx, tenant.IDFromContext(ctx), time.Now().Add(-warmCacheVisitsWindow),
+			m.config.WarmCacheTopN)
+
+		return err
+	})
+	if err != nil {
+		m.log(ctx).Error("failed to get most visited short URLs from storage", logging.ErrorKey, err)
+
+		return fmt.Errorf("failed to get most visited short URLs from storage: %w", err)
+	}
+
+	return m.WarmCache(ctx, ids)
+}
+
+// cacheShortURLs caches the redirects of the short URLs a redirect would cache, short URLs sharing a cache TTL are
+// set together. It returns the number of short URLs cached.
+func (m *Manager) cacheShortURLs(ctx context.Context, tenantID string, shortURLs []*ShortURL) (int, error) {
+	entries := make(map[time.Duration]map[string]string)
+	cached := 0
+	for _, shortURL := range shortURLs {
+		// Like single redirects, short URLs with a click limit or a password are never cached
+		if shortURL.Status != StatusActive || shortURL.Protected() || shortURL.MaxClicks > 0 ||
+			(shortURL.ExpiresAt != nil && !shortURL.ExpiresAt.After(time.Now())) {
+			continue
+		}
+
+		// TTLs are rounded down to seconds, otherwise short URLs close to going stale never share one
+		ttl := m.cacheTTL(shortURL).Truncate(time.Second)
+		if ttl <= 0 {
+			continue
+		}
+
+		value, err := encodeCacheValue(shortURL)
+		if err != nil {
+			m.log(ctx).Error("failed to encode long URL cache entry", logging.ShortURLIdKey, shortURL.Id, logging.ErrorKey, err)
+
+			continue
+		}
+
+		if entries[ttl] == nil {
+			entries[ttl] = make(map[string]string)
+		}
+		entries[ttl][cacheKey(tenantID, shortURL.Id)] = value
+		cached++
+	}
+
+	for ttl, batch := range entries {
+		if err := m.cache.SetMulti(ctx, batch, ttl); err != nil {
+			return 0, err
+		}
+	}
+
+	return cached, nil
+}
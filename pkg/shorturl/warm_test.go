@@ -0,0 +1,169 @@
+package shorturl_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go.uber.org/mock/gomock"
+
+	"github.com/AvalosM/short-url-service/pkg/shorturl"
+	"github.com/AvalosM/short-url-service/pkg/tenant"
+)
+
+// mapCache is an in-memory cache ignoring TTLs
+type mapCache struct {
+	mu     sync.Mutex
+	values map[string]string
+}
+
+func (c *mapCache) Get(_ context.Context, key string) (string, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	value, found := c.values[key]
+
+	return value, found, nil
+}
+
+func (c *mapCache) GetMulti(_ context.Context, keys []string) (map[string]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	values := make(map[string]string, len(keys))
+	for _, key := range keys {
+		if value, found := c.values[key]; found {
+			values[key] = value
+		}
+	}
+
+	return values, nil
+}
+
+func (c *mapCache) Set(_ context.Context, key string, value string, _ time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.values[key] = value
+
+	return nil
+}
+
+func (c *mapCache) SetMulti(_ context.Context, entries map[string]string, _ time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, value := range entries {
+		c.values[key] = value
+	}
+
+	return nil
+}
+
+func (c *mapCache) Delete(_ context.Context, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.values, key)
+
+	return nil
+}
+
+// hitRate returns the share of the long URLs served from the cache
+func hitRate(stats shorturl.ManagerStats) float64 {
+	return float64(stats.HitCount) / float64(stats.HitCount+stats.MissCount)
+}
+
+func (suite *ManagerSuite) TestWarmCacheImprovesHitRate() {
+	ctx := context.Background()
+	ids := []string{"AAAAAA", "BBBBBB", "CCCCCC", "DDDDDD"}
+
+	manager, err := shorturl.NewManager(suite.config, suite.mockStorage, &mapCache{values: map[string]string{}}, suite.mockLogger)
+	suite.Require().NoError(err)
+
+	// Paused short URLs are looked up in storage on every click
+	suite.mockStorage.EXPECT().GetLongURL(ctx, tenant.Default, "DDDDDD").Return(nil, false, shorturl.ErrShortURLPaused).Times(2)
+	_, err = manager.GetLongURL(ctx, "DDDDDD")
+	suite.Require().ErrorIs(err, shorturl.ErrShortURLPaused)
+	suite.Zero(hitRate(manager.Stats()))
+
+	// Batches of 2 short URLs
+	suite.mockStorage.EXPECT().GetShortURLs(ctx, tenant.Default, []string{"AAAAAA", "BBBBBB"}).Return([]*shorturl.ShortURL{
+		{Id: "AAAAAA", LongURL: "https://a.example.com", Status: shorturl.StatusActive},
+		{Id: "BBBBBB", LongURL: "https://b.example.com", Status: shorturl.StatusActive},
+	}, nil)
+	suite.mockStorage.EXPECT().GetShortURLs(ctx, tenant.Default, []string{"CCCCCC", "DDDDDD"}).Return([]*shorturl.ShortURL{
+		{Id: "CCCCCC", LongURL: "https://c.example.com", Status: shorturl.StatusActive},
+		{Id: "DDDDDD", LongURL: "https://d.example.com", Status: shorturl.StatusPaused},
+	}, nil)
+	suite.Require().NoError(manager.WarmCache(ctx, append(ids, "AAAAAA")))
+
+	for _, id := range ids {
+		_, _ = manager.GetLongURL(ctx, id)
+	}
+
+	stats := manager.Stats()
+	suite.Equal(uint64(3), stats.HitCount)
+	suite.Equal(uint64(2), stats.MissCount)
+	suite.InDelta(0.6, hitRate(stats), 0.001)
+}
+
+func (suite *ManagerSuite) TestWarmCacheSkipsShortURLsNotCached() {
+	ctx := tenant.WithID(context.Background(), "acme")
+	ttl := time.Second * time.Duration(suite.config.ShortURLCacheTTLInSeconds)
+	past := time.Now().Add(-time.Minute)
+
+	suite.mockStorage.EXPECT().GetShortURLs(ctx, "acme", []string{"AAAAAA", "BBBBBB"}).Return([]*shorturl.ShortURL{
+		{Id: "AAAAAA", LongURL: "https://a.example.com", Status: shorturl.StatusActive},
+		{Id: "BBBBBB", LongURL: "https://b.example.com", Status: shorturl.StatusActive, PasswordHash: "hash"},
+	}, nil)
+	suite.mockStorage.EXPECT().GetShortURLs(ctx, "acme", []string{"CCCCCC", "DDDDDD"}).Return([]*shorturl.ShortURL{
+		{Id: "CCCCCC", LongURL: "https://c.example.com", Status: shorturl.StatusActive, ExpiresAt: &past},
+		{Id: "DDDDDD", LongURL: "https://d.example.com", Status: shorturl.StatusActive, MaxClicks: 10},
+	}, nil)
+	suite.mockCache.EXPECT().SetMulti(ctx, map[string]string{"acme/AAAAAA": "https://a.example.com"}, ttl).Return(nil)
+
+	suite.Require().NoError(suite.manager.WarmCache(ctx, []string{"DDDDDD", "CCCCCC", "BBBBBB", "AAAAAA"}))
+}
+
+func (suite *ManagerSuite) TestWarmCacheFailStorageError() {
+	ctx := context.Background()
+
+	suite.mockStorage.EXPECT().GetShortURLs(ctx, tenant.Default, []string{"AAAAAA"}).Return(nil, errors.New("storage error"))
+
+	suite.Error(suite.manager.WarmCache(ctx, []string{"AAAAAA"}))
+}
+
+func (suite *ManagerSuite) TestWarmCacheFailCacheError() {
+	ctx := context.Background()
+
+	suite.mockStorage.EXPECT().GetShortURLs(ctx, tenant.Default, []string{"AAAAAA"}).Return([]*shorturl.ShortURL{
+		{Id: "AAAAAA", LongURL: "https://a.example.com", Status: shorturl.StatusActive},
+	}, nil)
+	suite.mockCache.EXPECT().SetMulti(ctx, gomock.Any(), gomock.Any()).Return(errors.New("cache error"))
+
+	suite.Error(suite.manager.WarmCache(ctx, []string{"AAAAAA"}))
+}
+
+func (suite *ManagerSuite) TestWarmCacheMostVisited() {
+	ctx := context.Background()
+
+	suite.mockStorage.EXPECT().GetMostVisitedShortURLIds(ctx, tenant.Default, gomock.Any(), suite.config.WarmCacheTopN).
+		Return([]string{"AAAAAA"}, nil)
+	suite.mockStorage.EXPECT().GetShortURLs(ctx, tenant.Default, []string{"AAAAAA"}).Return([]*shorturl.ShortURL{
+		{Id: "AAAAAA", LongURL: "https://a.example.com", Status: shorturl.StatusActive},
+	}, nil)
+	suite.mockCache.EXPECT().SetMulti(ctx, map[string]string{"AAAAAA": "https://a.example.com"}, gomock.Any()).Return(nil)
+
+	suite.Require().NoError(suite.manager.WarmCacheMostVisited(ctx))
+}
+
+func (suite *ManagerSuite) TestWarmCacheMostVisitedFailStorageError() {
+	ctx := context.Background()
+
+	suite.mockStorage.EXPECT().GetMostVisitedShortURLIds(ctx, tenant.Default, gomock.Any(), suite.config.WarmCacheTopN).
+		Return(nil, errors.New("storage error"))
+
+	suite.Error(suite.manager.WarmCacheMostVisited(ctx))
+}
@@ -0,0 +1,75 @@
+package telemetry
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// RequestDurationBuckets are the upper bounds in seconds of the buckets of the request duration histogram
+var RequestDurationBuckets = []float64{0.001, 0.005, 0.01, 0.05, 0.1, 0.5, 1.0, 5.0}
+
+// requestDuration registers the request duration histogram with reg, or returns the one already registered
+func requestDuration(reg prometheus.Registerer) *prometheus.HistogramVec {
+	histogram := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "Duration of the HTTP requests in seconds by handler, method and status",
+		Buckets: RequestDurationBuckets,
+	}, []string{"handler", "method", "status"})
+
+	if err := reg.Register(histogram); err != nil {
+		var alreadyRegistered prometheus.AlreadyRegisteredError
+		if errors.As(err, &alreadyRegistered) {
+			return alreadyRegistered.ExistingCollector.(*prometheus.HistogramVec)
+		}
+
+		panic(err)
+	}
+
+	return histogram
+}
+
+// InstrumentedHandler records the duration of the requests served by h in the http_request_duration_seconds
+// histogram of reg, labeled with name, the method of the request and the status of the response
+func InstrumentedHandler(name string, h http.HandlerFunc, reg prometheus.Registerer) http.HandlerFunc {
+	histogram := requestDuration(reg)
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+		h(recorder, r)
+
+		histogram.WithLabelValues(name, r.Method, strconv.Itoa(recorder.status)).Observe(time.Since(start).Seconds())
+	}
+}
+
+// statusRecorder captures the status code of a response
+type statusRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (w *statusRecorder) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.status = status
+		w.wroteHeader = true
+	}
+
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusRecorder) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap lets http.ResponseController reach the underlying response writer
+func (w *statusRecorder) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,120 @@
+package telemetry_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/AvalosM/short-url-service/pkg/telemetry"
+)
+
+type TelemetrySuite struct {
+	suite.Suite
+	registry *prometheus.Registry
+}
+
+func (suite *TelemetrySuite) SetupTest() {
+	suite.registry = prometheus.NewRegistry()
+}
+
+func TestTelemetrySuite(t *testing.T) {
+	suite.Run(t, new(TelemetrySuite))
+}
+
+func redirect(w http.ResponseWriter, r *http.Request) {
+	http.Redirect(w, r, "https://example.com", http.StatusFound)
+}
+
+// requestDurations returns the request duration histograms gathered from registry
+func requestDurations(registry *prometheus.Registry) ([]*dto.Metric, error) {
+	families, err := registry.Gather()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, family := range families {
+		if family.GetName() == "http_request_duration_seconds" {
+			return family.GetMetric(), nil
+		}
+	}
+
+	return nil, nil
+}
+
+// p99Bucket returns the cumulative count of the bucket holding the 99th percentile of the histogram, 0 if none does
+func p99Bucket(histogram *dto.Histogram) uint64 {
+	for _, bucket := range histogram.GetBucket() {
+		if float64(bucket.GetCumulativeCount()) >= 0.99*float64(histogram.GetSampleCount()) {
+			return bucket.GetCumulativeCount()
+		}
+	}
+
+	return 0
+}
+
+func labels(metric *dto.Metric) map[string]string {
+	labels := make(map[string]string)
+	for _, label := range metric.GetLabel() {
+		labels[label.GetName()] = label.GetValue()
+	}
+
+	return labels
+}
+
+func (suite *TelemetrySuite) TestInstrumentedHandler() {
+	handler := telemetry.InstrumentedHandler("redirect_to_long_url", redirect, suite.registry)
+	notFound := telemetry.InstrumentedHandler("check_short_url", http.NotFound, suite.registry)
+
+	for range 1000 {
+		handler(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/public/v1/short-urls/AABBCC", nil))
+	}
+	notFound(httptest.NewRecorder(), httptest.NewRequest(http.MethodHead, "/public/v1/short-urls/AABBCC", nil))
+
+	metrics, err := requestDurations(suite.registry)
+	suite.Require().NoError(err)
+	suite.Require().Len(metrics, 2)
+
+	suite.Equal(map[string]string{"handler": "check_short_url", "method": "HEAD", "status": "404"}, labels(metrics[0]))
+	suite.Equal(uint64(1), metrics[0].GetHistogram().GetSampleCount())
+
+	suite.Equal(map[string]string{"handler": "redirect_to_long_url", "method": "GET", "status": "302"}, labels(metrics[1]))
+	suite.Equal(uint64(1000), metrics[1].GetHistogram().GetSampleCount())
+	suite.Len(metrics[1].GetHistogram().GetBucket(), len(telemetry.RequestDurationBuckets))
+	suite.NotZero(p99Bucket(metrics[1].GetHistogram()))
+}
+
+func (suite *TelemetrySuite) TestInstrumentedHandlerDefaultStatus() {
+	handler := telemetry.InstrumentedHandler("empty", func(http.ResponseWriter, *http.Request) {}, suite.registry)
+	handler(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	metrics, err := requestDurations(suite.registry)
+	suite.Require().NoError(err)
+	suite.Require().Len(metrics, 1)
+	suite.Equal("200", labels(metrics[0])["status"])
+}
+
+// BenchmarkInstrumentedHandler makes 1000 redirect requests per iteration and checks the bucket of their 99th
+// percentile is populated
+func BenchmarkInstrumentedHandler(b *testing.B) {
+	registry := prometheus.NewRegistry()
+	handler := telemetry.InstrumentedHandler("redirect_to_long_url", redirect, registry)
+	request := httptest.NewRequest(http.MethodGet, "/public/v1/short-urls/AABBCC", nil)
+
+	for b.Loop() {
+		for range 1000 {
+			handler(httptest.NewRecorder(), request)
+		}
+	}
+
+	metrics, err := requestDurations(registry)
+	if err != nil {
+		b.Fatal(err)
+	}
+	if len(metrics) != 1 || p99Bucket(metrics[0].GetHistogram()) == 0 {
+		b.Fatal("the p99 bucket of the redirect requests is empty")
+	}
+}
@@ -0,0 +1,26 @@
+package tenant
+
+import "context"
+
+// Default is the tenant of requests that are not scoped to any tenant
+const Default = ""
+
+// MaxIDLength is the maximum length of a tenant id
+const MaxIDLength = 64
+
+type idKey struct{}
+
+// WithID returns a copy of ctx scoped to the tenant with the given id
+func WithID(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, idKey{}, tenantID)
+}
+
+// IDFromContext returns the id of the tenant ctx is scoped to, Default if there is none
+func IDFromContext(ctx context.Context) string {
+	tenantID, ok := ctx.Value(idKey{}).(string)
+	if !ok {
+		return Default
+	}
+
+	return tenantID
+}
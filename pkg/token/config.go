@@ -0,0 +1,40 @@
+package token
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+)
+
+const minSecretLength = 32
+
+// Config holds the configuration for the token signer
+type Config struct {
+	// Secret signs the tokens, it must be shared by every instance of the service
+	Secret       string `json:"secret"`
+	TTLInSeconds int    `json:"ttl_in_seconds"`
+}
+
+// DefaultConfig returns the default configuration for the token signer, its secret is random so tokens are only
+// valid on the instance that issued them
+func DefaultConfig() *Config {
+	secret := make([]byte, minSecretLength)
+	_, _ = rand.Read(secret)
+
+	return &Config{
+		Secret:       hex.EncodeToString(secret),
+		TTLInSeconds: 5 * 60, // 5 minutes
+	}
+}
+
+// Validate checks if the configuration is valid
+func (c *Config) Validate() error {
+	if len(c.Secret) < minSecretLength {
+		return errors.New("Secret must be at least 32 characters long")
+	}
+	if c.TTLInSeconds <= 0 {
+		return errors.New("TTLInSeconds must be greater than 0")
+	}
+
+	return nil
+}
@@ -0,0 +1,87 @@
+package token
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidToken = errors.New("invalid token")
+	ErrExpiredToken = errors.New("expired token")
+)
+
+// Signer issues and validates signed, time-limited tokens bound to a subject
+type Signer struct {
+	config *Config
+	secret []byte
+	now    func() time.Time
+}
+
+// NewSigner creates a new token signer
+func NewSigner(config *Config) (*Signer, error) {
+	if config == nil {
+		return nil, errors.New("config cannot be nil")
+	}
+
+	return &Signer{
+		config: config,
+		secret: []byte(config.Secret),
+		now:    time.Now,
+	}, nil
+}
+
+// Generate issues a token for the subject, it returns the token and the time it expires at
+func (s *Signer) Generate(subject string) (string, time.Time, error) {
+	if subject == "" {
+		return "", time.Time{}, errors.New("subject cannot be empty")
+	}
+
+	expiresAt := s.now().Add(time.Duration(s.config.TTLInSeconds) * time.Second).Truncate(time.Second)
+	payload := base64.RawURLEncoding.EncodeToString([]byte(subject + "|" + strconv.FormatInt(expiresAt.Unix(), 10)))
+
+	return payload + "." + s.sign(payload), expiresAt, nil
+}
+
+// Validate checks that the token was issued by this signer for the subject and has not expired
+func (s *Signer) Validate(token string, subject string) error {
+	payload, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return ErrInvalidToken
+	}
+	if !hmac.Equal([]byte(signature), []byte(s.sign(payload))) {
+		return ErrInvalidToken
+	}
+
+	decoded, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return fmt.Errorf("%w: decoding payload: %w", ErrInvalidToken, err)
+	}
+
+	tokenSubject, expiry, ok := strings.Cut(string(decoded), "|")
+	if !ok || tokenSubject != subject {
+		return ErrInvalidToken
+	}
+
+	expiresAt, err := strconv.ParseInt(expiry, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: parsing expiry: %w", ErrInvalidToken, err)
+	}
+	if !s.now().Before(time.Unix(expiresAt, 0)) {
+		return ErrExpiredToken
+	}
+
+	return nil
+}
+
+func (s *Signer) sign(payload string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(payload))
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
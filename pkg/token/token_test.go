@@ -0,0 +1,68 @@
+package token
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type SignerSuite struct {
+	suite.Suite
+	now    time.Time
+	signer *Signer
+}
+
+func (suite *SignerSuite) SetupTest() {
+	signer, err := NewSigner(&Config{
+		Secret:       "0123456789abcdef0123456789abcdef",
+		TTLInSeconds: 60,
+	})
+	suite.Require().NoError(err)
+
+	suite.now = time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	signer.now = func() time.Time { return suite.now }
+	suite.signer = signer
+}
+
+func TestSignerSuite(t *testing.T) {
+	suite.Run(t, new(SignerSuite))
+}
+
+func (suite *SignerSuite) TestGenerateAndValidate() {
+	token, expiresAt, err := suite.signer.Generate("AABBCC")
+	suite.Require().NoError(err)
+	suite.Equal(suite.now.Add(time.Minute), expiresAt)
+
+	suite.NoError(suite.signer.Validate(token, "AABBCC"))
+}
+
+func (suite *SignerSuite) TestValidateFailOtherSubject() {
+	token, _, err := suite.signer.Generate("AABBCC")
+	suite.Require().NoError(err)
+
+	suite.ErrorIs(suite.signer.Validate(token, "DDEEFF"), ErrInvalidToken)
+}
+
+func (suite *SignerSuite) TestValidateFailExpired() {
+	token, _, err := suite.signer.Generate("AABBCC")
+	suite.Require().NoError(err)
+
+	suite.now = suite.now.Add(time.Minute)
+	suite.ErrorIs(suite.signer.Validate(token, "AABBCC"), ErrExpiredToken)
+}
+
+func (suite *SignerSuite) TestValidateFailTampered() {
+	token, _, err := suite.signer.Generate("AABBCC")
+	suite.Require().NoError(err)
+
+	other, err := NewSigner(&Config{Secret: "fedcba9876543210fedcba9876543210", TTLInSeconds: 60})
+	suite.Require().NoError(err)
+	otherToken, _, err := other.Generate("AABBCC")
+	suite.Require().NoError(err)
+
+	testCases := []string{"", "garbage", token + "x", otherToken}
+	for _, testCase := range testCases {
+		suite.ErrorIs(suite.signer.Validate(testCase, "AABBCC"), ErrInvalidToken)
+	}
+}
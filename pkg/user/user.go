@@ -0,0 +1,48 @@
+package user
+
+import "context"
+
+// MaxIDLength is the maximum length of a user id, the length of the short URL created_by column
+const MaxIDLength = 128
+
+// RoleAdmin is the role of users allowed to manage the short URLs of every user
+const RoleAdmin = "admin"
+
+type idKey struct{}
+
+type roleKey struct{}
+
+// WithID returns a copy of ctx made on behalf of the user with the given id
+func WithID(ctx context.Context, userID string) context.Context {
+	return context.WithValue(ctx, idKey{}, userID)
+}
+
+// IDFromContext returns the id of the user ctx is made on behalf of, empty if there is none
+func IDFromContext(ctx context.Context) string {
+	userID, _ := ctx.Value(idKey{}).(string)
+
+	return userID
+}
+
+// WithRole returns a copy of ctx made by a user with the given role
+func WithRole(ctx context.Context, role string) context.Context {
+	return context.WithValue(ctx, roleKey{}, role)
+}
+
+// IsAdmin reports whether ctx is made by a user with RoleAdmin
+func IsAdmin(ctx context.Context) bool {
+	role, _ := ctx.Value(roleKey{}).(string)
+
+	return role == RoleAdmin
+}
+
+// CanAccess reports whether ctx is made by the user with the given id or by an admin
+func CanAccess(ctx context.Context, userID string) bool {
+	if IsAdmin(ctx) {
+		return true
+	}
+
+	callerID := IDFromContext(ctx)
+
+	return callerID != "" && callerID == userID
+}
@@ -0,0 +1,45 @@
+package webhook
+
+import "errors"
+
+// Config holds the configuration for the webhook manager
+type Config struct {
+	DispatchTimeoutInMS int `json:"dispatch_timeout_in_ms"`
+	MaxRetries          int `json:"max_retries"`
+	RetryBackoffInMS    int `json:"retry_backoff_in_ms"`
+	// NotifyQueueSize is the number of clicks waiting to be notified, clicks are dropped while the queue is full
+	NotifyQueueSize int `json:"notify_queue_size"`
+	// NotifyWorkers is the number of clicks notified at the same time
+	NotifyWorkers int `json:"notify_workers"`
+}
+
+// DefaultConfig returns the default configuration for the webhook manager
+func DefaultConfig() *Config {
+	return &Config{
+		DispatchTimeoutInMS: 5000,
+		MaxRetries:          3,
+		RetryBackoffInMS:    200,
+		NotifyQueueSize:     1000,
+		NotifyWorkers:       8,
+	}
+}
+
+// Validate checks if the configuration is valid
+func (c *Config) Validate() error {
+	if c.DispatchTimeoutInMS <= 0 {
+		return errors.New("DispatchTimeoutInMS must be greater than 0")
+	}
+	if c.MaxRetries < 0 {
+		return errors.New("MaxRetries must be greater than or equal to 0")
+	}
+	if c.RetryBackoffInMS <= 0 {
+		return errors.New("RetryBackoffInMS must be greater than 0")
+	}
+	if c.NotifyQueueSize <= 0 {
+		return errors.New("NotifyQueueSize must be greater than 0")
+	}
+	if c.NotifyWorkers <= 0 {
+		return errors.New("NotifyWorkers must be greater than 0")
+	}
+	return nil
+}
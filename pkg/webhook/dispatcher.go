@@ -0,0 +1,94 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/AvalosM/short-url-service/pkg/retry"
+)
+
+// SignatureHeader holds the HMAC-SHA256 signature of the request body, prefixed with "sha256="
+const SignatureHeader = "X-Signature-256"
+
+// HTTPClient sends webhook requests
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Dispatcher posts signed events to webhooks
+type Dispatcher struct {
+	config *Config
+	client HTTPClient
+}
+
+// NewDispatcher creates a new webhook dispatcher
+func NewDispatcher(config *Config, client HTTPClient) *Dispatcher {
+	return &Dispatcher{
+		config: config,
+		client: client,
+	}
+}
+
+// Dispatch posts the event to the webhook, retrying with exponential backoff on network and server errors
+func (d *Dispatcher) Dispatch(ctx context.Context, webhook *Webhook, event *Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("marshalling webhook event: %w", err)
+	}
+
+	signature := Sign(payload, webhook.Secret)
+	backoff := time.Duration(d.config.RetryBackoffInMS) * time.Millisecond
+
+	return retry.Retry(ctx, d.config.MaxRetries+1, backoff, func() error {
+		return d.post(ctx, webhook.URL, payload, signature)
+	})
+}
+
+func (d *Dispatcher) post(ctx context.Context, url string, payload []byte, signature string) error {
+	attemptCtx, cancel := context.WithTimeout(ctx, time.Duration(d.config.DispatchTimeoutInMS)*time.Millisecond)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(attemptCtx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return retry.Permanent(fmt.Errorf("creating webhook request: %w", err))
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(SignatureHeader, signature)
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		if ctx.Err() == nil && attemptCtx.Err() != nil {
+			// only this attempt timed out, do not wrap the context error so it is retried
+			return fmt.Errorf("sending webhook request: %v", err)
+		}
+
+		return fmt.Errorf("sending webhook request: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	switch {
+	case resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests:
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	case resp.StatusCode >= http.StatusBadRequest:
+		return retry.Permanent(fmt.Errorf("webhook responded with status %d", resp.StatusCode))
+	}
+
+	return nil
+}
+
+// Sign returns the value of the SignatureHeader for the payload signed with secret
+func Sign(payload []byte, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
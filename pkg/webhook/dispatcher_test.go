@@ -0,0 +1,118 @@
+package webhook_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/AvalosM/short-url-service/pkg/webhook"
+)
+
+type DispatcherSuite struct {
+	suite.Suite
+	config     *webhook.Config
+	dispatcher *webhook.Dispatcher
+}
+
+func (suite *DispatcherSuite) SetupTest() {
+	suite.config = &webhook.Config{
+		DispatchTimeoutInMS: 100,
+		MaxRetries:          3,
+		RetryBackoffInMS:    1,
+	}
+	suite.dispatcher = webhook.NewDispatcher(suite.config, http.DefaultClient)
+}
+
+func TestDispatcherSuite(t *testing.T) {
+	suite.Run(t, new(DispatcherSuite))
+}
+
+func (suite *DispatcherSuite) TestDispatchSignsPayload() {
+	event := &webhook.Event{
+		Event:      webhook.EventClicked,
+		ShortURLId: "AABBCC",
+		Timestamp:  time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC),
+	}
+
+	var body []byte
+	var signature string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		signature = r.Header.Get(webhook.SignatureHeader)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	err := suite.dispatcher.Dispatch(context.Background(), &webhook.Webhook{URL: server.URL, Secret: "secret"}, event)
+	suite.Require().NoError(err)
+
+	var received webhook.Event
+	suite.Require().NoError(json.Unmarshal(body, &received))
+	suite.Equal(*event, received)
+	suite.Equal(webhook.Sign(body, "secret"), signature)
+}
+
+func (suite *DispatcherSuite) TestDispatchRetriesServerErrors() {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	err := suite.dispatcher.Dispatch(context.Background(), &webhook.Webhook{URL: server.URL, Secret: "secret"}, &webhook.Event{})
+	suite.NoError(err)
+	suite.Equal(int32(3), calls.Load())
+}
+
+func (suite *DispatcherSuite) TestDispatchRetriesTimeouts() {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			time.Sleep(200 * time.Millisecond)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	err := suite.dispatcher.Dispatch(context.Background(), &webhook.Webhook{URL: server.URL, Secret: "secret"}, &webhook.Event{})
+	suite.NoError(err)
+	suite.Equal(int32(2), calls.Load())
+}
+
+func (suite *DispatcherSuite) TestDispatchFailAfterMaxRetries() {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	err := suite.dispatcher.Dispatch(context.Background(), &webhook.Webhook{URL: server.URL, Secret: "secret"}, &webhook.Event{})
+	suite.Error(err)
+	suite.Equal(int32(suite.config.MaxRetries+1), calls.Load())
+}
+
+func (suite *DispatcherSuite) TestDispatchDoesNotRetryClientErrors() {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	err := suite.dispatcher.Dispatch(context.Background(), &webhook.Webhook{URL: server.URL, Secret: "secret"}, &webhook.Event{})
+	suite.Error(err)
+	suite.Equal(int32(1), calls.Load())
+}
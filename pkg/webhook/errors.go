@@ -0,0 +1,8 @@
+package webhook
+
+import "errors"
+
+var (
+	ErrWebhookNotFound = errors.New("webhook not found")
+	ErrInvalidWebhook  = errors.New("invalid webhook")
+)
@@ -0,0 +1,185 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/AvalosM/short-url-service/pkg/logging"
+	"github.com/AvalosM/short-url-service/pkg/tenant"
+)
+
+// Storage webhook persistent storage
+type Storage interface {
+	CreateWebhook(ctx context.Context, tenantID string, webhook *Webhook) (*Webhook, error)
+	DeleteWebhook(ctx context.Context, tenantID string, shortURLId string, id int64) (bool, error)
+	GetWebhooks(ctx context.Context, tenantID string, shortURLId string) ([]*Webhook, error)
+}
+
+// Logger ...
+type Logger interface {
+	Error(msg string, args ...interface{})
+	Info(msg string, args ...interface{})
+	Debug(msg string, args ...interface{})
+	Warn(msg string, args ...interface{})
+}
+
+// click is a click of a short URL of a tenant waiting to be notified
+type click struct {
+	tenantID   string
+	shortURLId string
+}
+
+// Manager webhook manager
+type Manager struct {
+	config     *Config
+	storage    Storage
+	dispatcher *Dispatcher
+	clicks     chan click
+	logger     Logger
+}
+
+// NewManager creates a new webhook manager
+func NewManager(config *Config, storage Storage, client HTTPClient, logger Logger) (*Manager, error) {
+	if config == nil {
+		return nil, errors.New("config cannot be nil")
+	}
+	if storage == nil {
+		return nil, errors.New("storage cannot be nil")
+	}
+	if client == nil {
+		return nil, errors.New("http client cannot be nil")
+	}
+	if logger == nil {
+		return nil, errors.New("logger cannot be nil")
+	}
+
+	return &Manager{
+		config:     config,
+		storage:    storage,
+		dispatcher: NewDispatcher(config, client),
+		clicks:     make(chan click, config.NotifyQueueSize),
+		logger:     logger,
+	}, nil
+}
+
+// RegisterWebhook registers a webhook notified of the events of the given short URL of the tenant of ctx
+func (m *Manager) RegisterWebhook(ctx context.Context, shortURLId string, webhookURL string, secret string) (*Webhook, error) {
+	if err := Validate(webhookURL, secret); err != nil {
+		m.logger.Info("invalid webhook", logging.ShortURLIdKey, shortURLId, logging.ErrorKey, err)
+
+		return nil, err
+	}
+
+	webhook, err := m.storage.CreateWebhook(ctx, tenant.IDFromContext(ctx), &Webhook{
+		ShortURLId: shortURLId,
+		URL:        webhookURL,
+		Secret:     secret,
+	})
+	if err != nil {
+		m.logger.Error("failed to create webhook in storage", logging.ShortURLIdKey, shortURLId, logging.ErrorKey, err)
+
+		return nil, fmt.Errorf("failed to create webhook in storage: %w", err)
+	}
+
+	return webhook, nil
+}
+
+// Validate checks that webhookURL is an absolute http(s) URL and secret is not empty, the returned
+// error wraps ErrInvalidWebhook
+func Validate(webhookURL string, secret string) error {
+	parsedURL, err := url.Parse(webhookURL)
+	if err != nil {
+		return fmt.Errorf("%w: parsing webhook URL: %w", ErrInvalidWebhook, err)
+	}
+	if parsedURL.Scheme != "https" && parsedURL.Scheme != "http" {
+		return fmt.Errorf("%w: webhook URL must start with http:// or https://", ErrInvalidWebhook)
+	}
+	if parsedURL.Host == "" {
+		return fmt.Errorf("%w: webhook URL must have a host", ErrInvalidWebhook)
+	}
+	if secret == "" {
+		return fmt.Errorf("%w: webhook secret cannot be empty", ErrInvalidWebhook)
+	}
+
+	return nil
+}
+
+// DeleteWebhook deletes the webhook with the given id from the given short URL of the tenant of ctx
+func (m *Manager) DeleteWebhook(ctx context.Context, shortURLId string, webhookId int64) error {
+	found, err := m.storage.DeleteWebhook(ctx, tenant.IDFromContext(ctx), shortURLId, webhookId)
+	if err != nil {
+		m.logger.Error("failed to delete webhook from storage", logging.ShortURLIdKey, shortURLId, logging.WebhookIdKey, webhookId, logging.ErrorKey, err)
+
+		return fmt.Errorf("failed to delete webhook from storage: %w", err)
+	}
+	if !found {
+		return ErrWebhookNotFound
+	}
+
+	return nil
+}
+
+// Start starts the workers notifying the clicks queued by NotifyClickAsync. The returned stop function waits for
+// the notifications being sent, the clicks still queued are dropped.
+func (m *Manager) Start() func() {
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	for range m.config.NotifyWorkers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for {
+				select {
+				case click := <-m.clicks:
+					m.NotifyClick(context.Background(), click.tenantID, click.shortURLId)
+				case <-stop:
+					return
+				}
+			}
+		}()
+	}
+
+	return func() {
+		close(stop)
+		wg.Wait()
+	}
+}
+
+// NotifyClickAsync queues a click of the given short URL of a tenant to be notified to its webhooks by the workers
+// started with Start, the click is dropped when the queue is full
+func (m *Manager) NotifyClickAsync(tenantID string, shortURLId string) {
+	select {
+	case m.clicks <- click{tenantID: tenantID, shortURLId: shortURLId}:
+	default:
+		m.logger.Warn("webhook notification queue is full, dropping click", logging.TenantIdKey, tenantID, logging.ShortURLIdKey, shortURLId)
+	}
+}
+
+// NotifyClick notifies the webhooks of the given short URL of a tenant of a click
+func (m *Manager) NotifyClick(ctx context.Context, tenantID string, shortURLId string) {
+	storageCtx, cancel := context.WithTimeout(ctx, time.Duration(m.config.DispatchTimeoutInMS)*time.Millisecond)
+	webhooks, err := m.storage.GetWebhooks(storageCtx, tenantID, shortURLId)
+	cancel()
+	if err != nil {
+		m.logger.Error("failed to get webhooks from storage", logging.ShortURLIdKey, shortURLId, logging.ErrorKey, err)
+
+		return
+	}
+
+	event := &Event{
+		Event:      EventClicked,
+		ShortURLId: shortURLId,
+		Timestamp:  time.Now().UTC(),
+	}
+
+	for _, webhook := range webhooks {
+		if err := m.dispatcher.Dispatch(ctx, webhook, event); err != nil {
+			m.logger.Warn("failed to dispatch webhook", logging.ShortURLIdKey, shortURLId, logging.WebhookIdKey, webhook.Id, logging.ErrorKey, err)
+		}
+	}
+}
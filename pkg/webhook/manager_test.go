@@ -0,0 +1,146 @@
+package webhook_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+
+	"github.com/AvalosM/short-url-service/pkg/tenant"
+	"github.com/AvalosM/short-url-service/pkg/webhook"
+	"github.com/AvalosM/short-url-service/pkg/webhook/mocks"
+)
+
+//go:generate mockgen -typed -package=mocks  -source=./manager.go -destination=./mocks/mocks.go
+
+type ManagerSuite struct {
+	suite.Suite
+	mockCtrl    *gomock.Controller
+	mockStorage *mocks.MockStorage
+	mockLogger  *mocks.MockLogger
+	manager     *webhook.Manager
+}
+
+func (suite *ManagerSuite) SetupTest() {
+	suite.mockCtrl = gomock.NewController(suite.T())
+	suite.mockStorage = mocks.NewMockStorage(suite.mockCtrl)
+	suite.mockLogger = mocks.NewMockLogger(suite.mockCtrl)
+
+	suite.mockLogger.EXPECT().Error(gomock.Any(), gomock.Any()).AnyTimes()
+	suite.mockLogger.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+	suite.mockLogger.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+	suite.mockLogger.EXPECT().Warn(gomock.Any(), gomock.Any()).AnyTimes()
+
+	config := &webhook.Config{
+		DispatchTimeoutInMS: 100,
+		MaxRetries:          1,
+		RetryBackoffInMS:    1,
+		NotifyQueueSize:     1,
+		NotifyWorkers:       1,
+	}
+
+	manager, err := webhook.NewManager(config, suite.mockStorage, http.DefaultClient, suite.mockLogger)
+	suite.Require().NoError(err)
+
+	suite.manager = manager
+}
+
+func (suite *ManagerSuite) TearDownTest() {
+	suite.mockCtrl.Finish()
+}
+
+func TestManagerSuite(t *testing.T) {
+	suite.Run(t, new(ManagerSuite))
+}
+
+func (suite *ManagerSuite) TestRegisterWebhookSuccess() {
+	expected := &webhook.Webhook{Id: 1, ShortURLId: "AABBCC", URL: "https://hooks.example.com", Secret: "secret"}
+	suite.mockStorage.EXPECT().CreateWebhook(gomock.Any(), tenant.Default, &webhook.Webhook{
+		ShortURLId: "AABBCC",
+		URL:        "https://hooks.example.com",
+		Secret:     "secret",
+	}).Return(expected, nil)
+
+	created, err := suite.manager.RegisterWebhook(context.Background(), "AABBCC", "https://hooks.example.com", "secret")
+	suite.NoError(err)
+	suite.Equal(expected, created)
+}
+
+func (suite *ManagerSuite) TestRegisterWebhookSuccessTenant() {
+	ctx := tenant.WithID(context.Background(), "acme")
+	expected := &webhook.Webhook{Id: 1, ShortURLId: "AABBCC", URL: "https://hooks.example.com", Secret: "secret"}
+	suite.mockStorage.EXPECT().CreateWebhook(ctx, "acme", gomock.Any()).Return(expected, nil)
+
+	created, err := suite.manager.RegisterWebhook(ctx, "AABBCC", "https://hooks.example.com", "secret")
+	suite.NoError(err)
+	suite.Equal(expected, created)
+}
+
+func (suite *ManagerSuite) TestRegisterWebhookFailInvalid() {
+	testCases := []struct {
+		url    string
+		secret string
+	}{
+		{url: "ftp://hooks.example.com", secret: "secret"},
+		{url: "/relative", secret: "secret"},
+		{url: "https://hooks.example.com", secret: ""},
+	}
+
+	for _, testCase := range testCases {
+		_, err := suite.manager.RegisterWebhook(context.Background(), "AABBCC", testCase.url, testCase.secret)
+		suite.ErrorIs(err, webhook.ErrInvalidWebhook)
+	}
+}
+
+func (suite *ManagerSuite) TestDeleteWebhookFailNotFound() {
+	suite.mockStorage.EXPECT().DeleteWebhook(gomock.Any(), tenant.Default, "AABBCC", int64(1)).Return(false, nil)
+
+	err := suite.manager.DeleteWebhook(context.Background(), "AABBCC", 1)
+	suite.ErrorIs(err, webhook.ErrWebhookNotFound)
+}
+
+func (suite *ManagerSuite) TestNotifyClickDispatchesToAllWebhooks() {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	suite.mockStorage.EXPECT().GetWebhooks(gomock.Any(), "acme", "AABBCC").Return([]*webhook.Webhook{
+		{Id: 1, ShortURLId: "AABBCC", URL: server.URL, Secret: "one"},
+		{Id: 2, ShortURLId: "AABBCC", URL: server.URL, Secret: "two"},
+	}, nil)
+
+	suite.manager.NotifyClick(context.Background(), "acme", "AABBCC")
+	suite.Equal(int32(2), calls.Load())
+}
+
+func (suite *ManagerSuite) TestNotifyClickAsync() {
+	done := make(chan struct{})
+	// The queue holds a single click, the clicks queued while it is full are dropped
+	suite.mockStorage.EXPECT().GetWebhooks(gomock.Any(), "acme", "AABBCC").
+		DoAndReturn(func(context.Context, string, string) ([]*webhook.Webhook, error) {
+			close(done)
+
+			return nil, nil
+		})
+
+	suite.manager.NotifyClickAsync("acme", "AABBCC")
+	suite.manager.NotifyClickAsync("acme", "AABBCC")
+	suite.manager.NotifyClickAsync("acme", "AABBCC")
+
+	stop := suite.manager.Start()
+	defer stop()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		suite.Fail("Waiting for click notification timed out")
+	}
+}
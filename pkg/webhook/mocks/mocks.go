@@ -0,0 +1,347 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./manager.go
+//
+// Generated by this command:
+//
+//	mockgen -typed -package=mocks -source=./manager.go -destination=./mocks/mocks.go
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	webhook "github.com/AvalosM/short-url-service/pkg/webhook"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockStorage is a mock of Storage interface.
+type MockStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockStorageMockRecorder
+	isgomock struct{}
+}
+
+// MockStorageMockRecorder is the mock recorder for MockStorage.
+type MockStorageMockRecorder struct {
+	mock *MockStorage
+}
+
+// NewMockStorage creates a new mock instance.
+func NewMockStorage(ctrl *gomock.Controller) *MockStorage {
+	mock := &MockStorage{ctrl: ctrl}
+	mock.recorder = &MockStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStorage) EXPECT() *MockStorageMockRecorder {
+	return m.recorder
+}
+
+// CreateWebhook mocks base method.
+func (m *MockStorage) CreateWebhook(ctx context.Context, tenantID string, arg2 *webhook.Webhook) (*webhook.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhook", ctx, tenantID, arg2)
+	ret0, _ := ret[0].(*webhook.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWebhook indicates an expected call of CreateWebhook.
+func (mr *MockStorageMockRecorder) CreateWebhook(ctx, tenantID, arg2 any) *MockStorageCreateWebhookCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhook", reflect.TypeOf((*MockStorage)(nil).CreateWebhook), ctx, tenantID, arg2)
+	return &MockStorageCreateWebhookCall{Call: call}
+}
+
+// MockStorageCreateWebhookCall wrap *gomock.Call
+type MockStorageCreateWebhookCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockStorageCreateWebhookCall) Return(arg0 *webhook.Webhook, arg1 error) *MockStorageCreateWebhookCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockStorageCreateWebhookCall) Do(f func(context.Context, string, *webhook.Webhook) (*webhook.Webhook, error)) *MockStorageCreateWebhookCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockStorageCreateWebhookCall) DoAndReturn(f func(context.Context, string, *webhook.Webhook) (*webhook.Webhook, error)) *MockStorageCreateWebhookCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// DeleteWebhook mocks base method.
+func (m *MockStorage) DeleteWebhook(ctx context.Context, tenantID, shortURLId string, id int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhook", ctx, tenantID, shortURLId, id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteWebhook indicates an expected call of DeleteWebhook.
+func (mr *MockStorageMockRecorder) DeleteWebhook(ctx, tenantID, shortURLId, id any) *MockStorageDeleteWebhookCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhook", reflect.TypeOf((*MockStorage)(nil).DeleteWebhook), ctx, tenantID, shortURLId, id)
+	return &MockStorageDeleteWebhookCall{Call: call}
+}
+
+// MockStorageDeleteWebhookCall wrap *gomock.Call
+type MockStorageDeleteWebhookCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockStorageDeleteWebhookCall) Return(arg0 bool, arg1 error) *MockStorageDeleteWebhookCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockStorageDeleteWebhookCall) Do(f func(context.Context, string, string, int64) (bool, error)) *MockStorageDeleteWebhookCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockStorageDeleteWebhookCall) DoAndReturn(f func(context.Context, string, string, int64) (bool, error)) *MockStorageDeleteWebhookCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// GetWebhooks mocks base method.
+func (m *MockStorage) GetWebhooks(ctx context.Context, tenantID, shortURLId string) ([]*webhook.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhooks", ctx, tenantID, shortURLId)
+	ret0, _ := ret[0].([]*webhook.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhooks indicates an expected call of GetWebhooks.
+func (mr *MockStorageMockRecorder) GetWebhooks(ctx, tenantID, shortURLId any) *MockStorageGetWebhooksCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhooks", reflect.TypeOf((*MockStorage)(nil).GetWebhooks), ctx, tenantID, shortURLId)
+	return &MockStorageGetWebhooksCall{Call: call}
+}
+
+// MockStorageGetWebhooksCall wrap *gomock.Call
+type MockStorageGetWebhooksCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockStorageGetWebhooksCall) Return(arg0 []*webhook.Webhook, arg1 error) *MockStorageGetWebhooksCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockStorageGetWebhooksCall) Do(f func(context.Context, string, string) ([]*webhook.Webhook, error)) *MockStorageGetWebhooksCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockStorageGetWebhooksCall) DoAndReturn(f func(context.Context, string, string) ([]*webhook.Webhook, error)) *MockStorageGetWebhooksCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// MockLogger is a mock of Logger interface.
+type MockLogger struct {
+	ctrl     *gomock.Controller
+	recorder *MockLoggerMockRecorder
+	isgomock struct{}
+}
+
+// MockLoggerMockRecorder is the mock recorder for MockLogger.
+type MockLoggerMockRecorder struct {
+	mock *MockLogger
+}
+
+// NewMockLogger creates a new mock instance.
+func NewMockLogger(ctrl *gomock.Controller) *MockLogger {
+	mock := &MockLogger{ctrl: ctrl}
+	mock.recorder = &MockLoggerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLogger) EXPECT() *MockLoggerMockRecorder {
+	return m.recorder
+}
+
+// Debug mocks base method.
+func (m *MockLogger) Debug(msg string, args ...any) {
+	m.ctrl.T.Helper()
+	varargs := []any{msg}
+	for _, a := range args {
+		varargs = append(varargs, a)
+	}
+	m.ctrl.Call(m, "Debug", varargs...)
+}
+
+// Debug indicates an expected call of Debug.
+func (mr *MockLoggerMockRecorder) Debug(msg any, args ...any) *MockLoggerDebugCall {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{msg}, args...)
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Debug", reflect.TypeOf((*MockLogger)(nil).Debug), varargs...)
+	return &MockLoggerDebugCall{Call: call}
+}
+
+// MockLoggerDebugCall wrap *gomock.Call
+type MockLoggerDebugCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockLoggerDebugCall) Return() *MockLoggerDebugCall {
+	c.Call = c.Call.Return()
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockLoggerDebugCall) Do(f func(string, ...any)) *MockLoggerDebugCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockLoggerDebugCall) DoAndReturn(f func(string, ...any)) *MockLoggerDebugCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// Error mocks base method.
+func (m *MockLogger) Error(msg string, args ...any) {
+	m.ctrl.T.Helper()
+	varargs := []any{msg}
+	for _, a := range args {
+		varargs = append(varargs, a)
+	}
+	m.ctrl.Call(m, "Error", varargs...)
+}
+
+// Error indicates an expected call of Error.
+func (mr *MockLoggerMockRecorder) Error(msg any, args ...any) *MockLoggerErrorCall {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{msg}, args...)
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Error", reflect.TypeOf((*MockLogger)(nil).Error), varargs...)
+	return &MockLoggerErrorCall{Call: call}
+}
+
+// MockLoggerErrorCall wrap *gomock.Call
+type MockLoggerErrorCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockLoggerErrorCall) Return() *MockLoggerErrorCall {
+	c.Call = c.Call.Return()
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockLoggerErrorCall) Do(f func(string, ...any)) *MockLoggerErrorCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockLoggerErrorCall) DoAndReturn(f func(string, ...any)) *MockLoggerErrorCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// Info mocks base method.
+func (m *MockLogger) Info(msg string, args ...any) {
+	m.ctrl.T.Helper()
+	varargs := []any{msg}
+	for _, a := range args {
+		varargs = append(varargs, a)
+	}
+	m.ctrl.Call(m, "Info", varargs...)
+}
+
+// Info indicates an expected call of Info.
+func (mr *MockLoggerMockRecorder) Info(msg any, args ...any) *MockLoggerInfoCall {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{msg}, args...)
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Info", reflect.TypeOf((*MockLogger)(nil).Info), varargs...)
+	return &MockLoggerInfoCall{Call: call}
+}
+
+// MockLoggerInfoCall wrap *gomock.Call
+type MockLoggerInfoCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockLoggerInfoCall) Return() *MockLoggerInfoCall {
+	c.Call = c.Call.Return()
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockLoggerInfoCall) Do(f func(string, ...any)) *MockLoggerInfoCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockLoggerInfoCall) DoAndReturn(f func(string, ...any)) *MockLoggerInfoCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// Warn mocks base method.
+func (m *MockLogger) Warn(msg string, args ...any) {
+	m.ctrl.T.Helper()
+	varargs := []any{msg}
+	for _, a := range args {
+		varargs = append(varargs, a)
+	}
+	m.ctrl.Call(m, "Warn", varargs...)
+}
+
+// Warn indicates an expected call of Warn.
+func (mr *MockLoggerMockRecorder) Warn(msg any, args ...any) *MockLoggerWarnCall {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{msg}, args...)
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Warn", reflect.TypeOf((*MockLogger)(nil).Warn), varargs...)
+	return &MockLoggerWarnCall{Call: call}
+}
+
+// MockLoggerWarnCall wrap *gomock.Call
+type MockLoggerWarnCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockLoggerWarnCall) Return() *MockLoggerWarnCall {
+	c.Call = c.Call.Return()
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockLoggerWarnCall) Do(f func(string, ...any)) *MockLoggerWarnCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockLoggerWarnCall) DoAndReturn(f func(string, ...any)) *MockLoggerWarnCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
@@ -0,0 +1,22 @@
+package webhook
+
+import "time"
+
+// EventClicked is the event sent when a short URL is followed
+const EventClicked = "short_url.clicked"
+
+// Webhook is a URL notified of the events of a short URL
+type Webhook struct {
+	Id         int64
+	ShortURLId string
+	URL        string
+	Secret     string
+	CreatedAt  time.Time
+}
+
+// Event is the payload posted to a webhook
+type Event struct {
+	Event      string    `json:"event"`
+	ShortURLId string    `json:"short_url_id"`
+	Timestamp  time.Time `json:"timestamp"`
+}
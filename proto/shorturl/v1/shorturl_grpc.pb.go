@@ -0,0 +1,247 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.3
+// source: shorturl/v1/shorturl.proto
+
+package shorturlv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	ShortURLService_CreateShortURL_FullMethodName = "/shorturl.v1.ShortURLService/CreateShortURL"
+	ShortURLService_GetLongURL_FullMethodName     = "/shorturl.v1.ShortURLService/GetLongURL"
+	ShortURLService_DeleteShortURL_FullMethodName = "/shorturl.v1.ShortURLService/DeleteShortURL"
+	ShortURLService_GetMetrics_FullMethodName     = "/shorturl.v1.ShortURLService/GetMetrics"
+)
+
+// ShortURLServiceClient is the client API for ShortURLService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// ShortURLService manages short URLs, it mirrors the private HTTP API
+type ShortURLServiceClient interface {
+	// CreateShortURL creates a short URL for the given long URL
+	CreateShortURL(ctx context.Context, in *CreateShortURLRequest, opts ...grpc.CallOption) (*CreateShortURLResponse, error)
+	// GetLongURL returns the long URL of a short URL, it is not counted as a click
+	GetLongURL(ctx context.Context, in *GetLongURLRequest, opts ...grpc.CallOption) (*GetLongURLResponse, error)
+	// DeleteShortURL deletes a short URL
+	DeleteShortURL(ctx context.Context, in *DeleteShortURLRequest, opts ...grpc.CallOption) (*DeleteShortURLResponse, error)
+	// GetMetrics returns the metrics of a short URL within a time range
+	GetMetrics(ctx context.Context, in *GetMetricsRequest, opts ...grpc.CallOption) (*GetMetricsResponse, error)
+}
+
+type shortURLServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewShortURLServiceClient(cc grpc.ClientConnInterface) ShortURLServiceClient {
+	return &shortURLServiceClient{cc}
+}
+
+func (c *shortURLServiceClient) CreateShortURL(ctx context.Context, in *CreateShortURLRequest, opts ...grpc.CallOption) (*CreateShortURLResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateShortURLResponse)
+	err := c.cc.Invoke(ctx, ShortURLService_CreateShortURL_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortURLServiceClient) GetLongURL(ctx context.Context, in *GetLongURLRequest, opts ...grpc.CallOption) (*GetLongURLResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetLongURLResponse)
+	err := c.cc.Invoke(ctx, ShortURLService_GetLongURL_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortURLServiceClient) DeleteShortURL(ctx context.Context, in *DeleteShortURLRequest, opts ...grpc.CallOption) (*DeleteShortURLResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteShortURLResponse)
+	err := c.cc.Invoke(ctx, ShortURLService_DeleteShortURL_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortURLServiceClient) GetMetrics(ctx context.Context, in *GetMetricsRequest, opts ...grpc.CallOption) (*GetMetricsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetMetricsResponse)
+	err := c.cc.Invoke(ctx, ShortURLService_GetMetrics_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ShortURLServiceServer is the server API for ShortURLService service.
+// All implementations must embed UnimplementedShortURLServiceServer
+// for forward compatibility.
+//
+// ShortURLService manages short URLs, it mirrors the private HTTP API
+type ShortURLServiceServer interface {
+	// CreateShortURL creates a short URL for the given long URL
+	CreateShortURL(context.Context, *CreateShortURLRequest) (*CreateShortURLResponse, error)
+	// GetLongURL returns the long URL of a short URL, it is not counted as a click
+	GetLongURL(context.Context, *GetLongURLRequest) (*GetLongURLResponse, error)
+	// DeleteShortURL deletes a short URL
+	DeleteShortURL(context.Context, *DeleteShortURLRequest) (*DeleteShortURLResponse, error)
+	// GetMetrics returns the metrics of a short URL within a time range
+	GetMetrics(context.Context, *GetMetricsRequest) (*GetMetricsResponse, error)
+	mustEmbedUnimplementedShortURLServiceServer()
+}
+
+// UnimplementedShortURLServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedShortURLServiceServer struct{}
+
+func (UnimplementedShortURLServiceServer) CreateShortURL(context.Context, *CreateShortURLRequest) (*CreateShortURLResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateShortURL not implemented")
+}
+func (UnimplementedShortURLServiceServer) GetLongURL(context.Context, *GetLongURLRequest) (*GetLongURLResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLongURL not implemented")
+}
+func (UnimplementedShortURLServiceServer) DeleteShortURL(context.Context, *DeleteShortURLRequest) (*DeleteShortURLResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteShortURL not implemented")
+}
+func (UnimplementedShortURLServiceServer) GetMetrics(context.Context, *GetMetricsRequest) (*GetMetricsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMetrics not implemented")
+}
+func (UnimplementedShortURLServiceServer) mustEmbedUnimplementedShortURLServiceServer() {}
+func (UnimplementedShortURLServiceServer) testEmbeddedByValue()                         {}
+
+// UnsafeShortURLServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ShortURLServiceServer will
+// result in compilation errors.
+type UnsafeShortURLServiceServer interface {
+	mustEmbedUnimplementedShortURLServiceServer()
+}
+
+func RegisterShortURLServiceServer(s grpc.ServiceRegistrar, srv ShortURLServiceServer) {
+	// If the following call pancis, it indicates UnimplementedShortURLServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&ShortURLService_ServiceDesc, srv)
+}
+
+func _ShortURLService_CreateShortURL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateShortURLRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortURLServiceServer).CreateShortURL(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShortURLService_CreateShortURL_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortURLServiceServer).CreateShortURL(ctx, req.(*CreateShortURLRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShortURLService_GetLongURL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLongURLRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortURLServiceServer).GetLongURL(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShortURLService_GetLongURL_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortURLServiceServer).GetLongURL(ctx, req.(*GetLongURLRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShortURLService_DeleteShortURL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteShortURLRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortURLServiceServer).DeleteShortURL(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShortURLService_DeleteShortURL_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortURLServiceServer).DeleteShortURL(ctx, req.(*DeleteShortURLRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShortURLService_GetMetrics_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMetricsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortURLServiceServer).GetMetrics(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShortURLService_GetMetrics_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortURLServiceServer).GetMetrics(ctx, req.(*GetMetricsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ShortURLService_ServiceDesc is the grpc.ServiceDesc for ShortURLService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ShortURLService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "shorturl.v1.ShortURLService",
+	HandlerType: (*ShortURLServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateShortURL",
+			Handler:    _ShortURLService_CreateShortURL_Handler,
+		},
+		{
+			MethodName: "GetLongURL",
+			Handler:    _ShortURLService_GetLongURL_Handler,
+		},
+		{
+			MethodName: "DeleteShortURL",
+			Handler:    _ShortURLService_DeleteShortURL_Handler,
+		},
+		{
+			MethodName: "GetMetrics",
+			Handler:    _ShortURLService_GetMetrics_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "shorturl/v1/shorturl.proto",
+}